@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/system"
+	"github.com/spf13/cobra"
+)
+
+// newAgentDisableCommand creates `duckrow agent disable`.
+func newAgentDisableCommand() *cobra.Command {
+	disableCmd := &cobra.Command{
+		Use:   "disable <name>",
+		Short: "Silence an agent without uninstalling it",
+		Long: `Disable an installed agent for every system it is installed for, or only
+for the systems given with --systems.
+
+Each system's agent file is renamed with a .disabled suffix, so the system
+no longer loads it. The lock entry is kept, and sync and update leave the
+agent disabled. 'duckrow agent enable' restores it.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAgentToggle(cmd, args[0], true)
+		},
+	}
+	disableCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
+	disableCmd.Flags().String("systems", "", "Comma-separated system names to disable the agent for (default: all)")
+	return disableCmd
+}
+
+// newAgentEnableCommand creates `duckrow agent enable`.
+func newAgentEnableCommand() *cobra.Command {
+	enableCmd := &cobra.Command{
+		Use:   "enable <name>",
+		Short: "Restore an agent silenced with 'agent disable'",
+		Long: `Enable an agent disabled with 'duckrow agent disable' for every system it is
+disabled for, or only for the systems given with --systems.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAgentToggle(cmd, args[0], false)
+		},
+	}
+	enableCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
+	enableCmd.Flags().String("systems", "", "Comma-separated system names to enable the agent for (default: all)")
+	return enableCmd
+}
+
+func runAgentToggle(cmd *cobra.Command, name string, disable bool) error {
+	targetDir, err := resolveTargetDir(cmd)
+	if err != nil {
+		return err
+	}
+
+	// Unlike install, an empty --systems means every system, not the
+	// project's default systems.
+	var systems []system.System
+	if flag, _ := cmd.Flags().GetString("systems"); flag != "" {
+		names := strings.Split(flag, ",")
+		for i := range names {
+			names[i] = strings.TrimSpace(names[i])
+		}
+		if systems, err = system.ByNames(names); err != nil {
+			return err
+		}
+	}
+
+	toggle, verb, state := core.EnableAgent, "Enabled", "enabled"
+	if disable {
+		toggle, verb, state = core.DisableAgent, "Disabled", "disabled"
+	}
+	changed, err := toggle(name, targetDir, systems)
+	if len(changed) > 0 {
+		fmt.Fprintf(os.Stdout, "%s: %s (%s)\n", verb, name, strings.Join(system.DisplayNames(changed), ", "))
+	}
+	if err != nil {
+		return err
+	}
+	if len(changed) == 0 {
+		fmt.Fprintf(os.Stdout, "Agent %q is already %s.\n", name, state)
+		return nil
+	}
+	if disable {
+		fmt.Fprintf(os.Stdout, "The lock entry is kept; run 'duckrow agent enable %s' to restore it.\n", name)
+	}
+	return nil
+}
@@ -1,13 +1,14 @@
 package cmd
 
 import (
+	"bufio"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"sort"
 	"strings"
-	"text/tabwriter"
 
 	"github.com/barysiuk/duckrow/internal/core"
 	"github.com/barysiuk/duckrow/internal/core/asset"
@@ -25,14 +26,22 @@ func registerAssetCommands() {
 	}
 }
 
+// installPatternHelp describes name patterns in the install commands' help.
+const installPatternHelp = `A name pattern such as 'go-*' (or '<registry>/go-*') installs every
+matching registry entry in one transaction: all of them, or none if one
+fails. The matches are listed and installed once you confirm, or with --yes.`
+
 // buildAssetCommand creates a Cobra command tree for one asset kind:
 //
 //	duckrow <kind> install <source-or-name>
 //	duckrow <kind> uninstall <name>
 //	duckrow <kind> list
+//	duckrow <kind> info <name>
 //	duckrow <kind> sync
 //	duckrow <kind> outdated  (file-based kinds only)
 //	duckrow <kind> update    (file-based kinds only)
+//	duckrow mcp edit <name>
+//	duckrow agent disable|enable <name>
 func buildAssetCommand(kind asset.Kind, handler asset.Handler) *cobra.Command {
 	name := string(kind)
 	display := handler.DisplayName()
@@ -53,14 +62,49 @@ func buildAssetCommand(kind asset.Kind, handler asset.Handler) *cobra.Command {
 			return runAssetInstall(cmd, args, kind)
 		},
 	}
+	installCmd.Long = fmt.Sprintf(`Install %s(s) from a source or a configured registry.
+
+`, lower) + installPatternHelp
 	installCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
 	installCmd.Flags().StringP("registry", "r", "", "Limit to a specific registry")
 	addSystemsFlag(installCmd)
 	installCmd.Flags().Bool("no-lock", false, "Skip lock file update")
-	installCmd.Flags().Bool("force", false, "Overwrite existing")
+	installCmd.Flags().Bool("local", false, "Record in the personal .duckrow/local.lock.json instead of the team lock")
+	switch kind {
+	case asset.KindMCP:
+		installCmd.Flags().Bool("force", false, "Overwrite existing MCP entries, or replace a same-named MCP from another registry")
+		installCmd.Flags().Bool("dry-run", false, "Show how each config file would change without writing it")
+	case asset.KindAgent, asset.KindCommand, asset.KindRule:
+		installCmd.Flags().Bool("force", false, fmt.Sprintf("Replace a same-named %s from another source, or %s files duckrow didn't write", lower, lower))
+	default:
+		installCmd.Flags().Bool("force", false, fmt.Sprintf("Replace a same-named %s from another source", lower))
+	}
+	if kind != asset.KindMCP {
+		installCmd.Flags().Bool("reinstall", false, "Copy again even if already installed at the same commit")
+	}
+	installCmd.Flags().String("as", "", "Install under a different name (recorded as an alias in the lock file)")
+	installCmd.Flags().Bool("json", false, "Output what was installed as JSON")
+	installCmd.Flags().BoolP("yes", "y", false, "Install every registry entry matching a name pattern without asking")
 	// Skill-specific flag
 	if kind == asset.KindSkill {
+		installCmd.Flags().Bool("overwrite-modified", false, "Discard local changes to the installed skill without asking")
 		installCmd.Flags().Bool("internal", false, "Include internal skills")
+		installCmd.Flags().Bool("accept-large", false, "Install skills over the size limits without asking")
+		installCmd.Flags().Bool("no-validate", false, "Skip SKILL.md frontmatter validation")
+		installCmd.Flags().Bool("namespace", false, "Install registry skills under <registry>--<name>, whatever the skillNamespaces setting")
+
+		// Skills can be picked interactively when no argument is given.
+		installCmd.Use = "install [source-or-name]"
+		installCmd.Long = `Install skill(s) from a git source or a configured registry.
+
+When run without arguments on a terminal, an interactive picker lists the
+skills available in your registries.
+
+` + installPatternHelp
+		installCmd.Args = cobra.MaximumNArgs(1)
+	}
+	if kind == asset.KindSkill || kind == asset.KindMCP {
+		installCmd.Flags().Bool("global", false, fmt.Sprintf("Install the %s for your user, in every project, instead of into a directory", lower))
 	}
 	parent.AddCommand(installCmd)
 
@@ -76,6 +120,12 @@ func buildAssetCommand(kind asset.Kind, handler asset.Handler) *cobra.Command {
 	uninstallCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
 	uninstallCmd.Flags().Bool("no-lock", false, "Remove without updating the lock file")
 	uninstallCmd.Flags().Bool("all", false, fmt.Sprintf("Remove all installed %ss", lower))
+	if kind == asset.KindSkill || kind == asset.KindMCP {
+		uninstallCmd.Flags().Bool("global", false, fmt.Sprintf("Remove a %s installed with --global", lower))
+	}
+	if kind == asset.KindMCP {
+		uninstallCmd.Flags().Bool("take-ownership", false, "Also remove config entries duckrow didn't write")
+	}
 	parent.AddCommand(uninstallCmd)
 
 	// --- list ---
@@ -89,8 +139,24 @@ func buildAssetCommand(kind asset.Kind, handler asset.Handler) *cobra.Command {
 	}
 	listCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
 	listCmd.Flags().Bool("json", false, "Output as JSON")
+	if kind == asset.KindSkill || kind == asset.KindMCP {
+		listCmd.Flags().Bool("global", false, fmt.Sprintf("List the %ss installed with --global", lower))
+	}
 	parent.AddCommand(listCmd)
 
+	// --- info ---
+	infoCmd := &cobra.Command{
+		Use:   "info <name>",
+		Short: fmt.Sprintf("Show the registry entry of a %s, with its install notes", lower),
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runAssetInfo(cmd, args[0], kind)
+		},
+	}
+	infoCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
+	infoCmd.Flags().StringP("registry", "r", "", "Limit to a specific registry")
+	parent.AddCommand(infoCmd)
+
 	// --- sync ---
 	syncCmd := &cobra.Command{
 		Use:   "sync",
@@ -102,10 +168,27 @@ func buildAssetCommand(kind asset.Kind, handler asset.Handler) *cobra.Command {
 	}
 	syncCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
 	syncCmd.Flags().Bool("dry-run", false, "Show what would be done without making changes")
-	syncCmd.Flags().Bool("force", false, "Overwrite existing entries")
+	syncCmd.Flags().String("tag", "", fmt.Sprintf("Sync only the %ss installed with this registry tag", lower))
+	if kind == asset.KindMCP {
+		syncCmd.Flags().Bool("force", false, "Overwrite existing entries")
+	} else {
+		syncCmd.Flags().Bool("reinstall", false, fmt.Sprintf("Install %ss that are already present again", lower))
+		syncCmd.Flags().Bool("force", false, "Alias for --reinstall")
+		_ = syncCmd.Flags().MarkDeprecated("force", "use --reinstall instead")
+		if kind == asset.KindSkill {
+			syncCmd.Flags().Bool("overwrite-modified", false, "Discard local changes to skills that are installed again without asking")
+		}
+	}
 	addSystemsFlag(syncCmd)
 	parent.AddCommand(syncCmd)
 
+	if kind == asset.KindMCP {
+		parent.AddCommand(newMCPEditCommand())
+	}
+	if kind == asset.KindAgent {
+		parent.AddCommand(newAgentDisableCommand(), newAgentEnableCommand())
+	}
+
 	// --- outdated and update (source-based kinds only) ---
 	if kind != asset.KindMCP {
 		outdatedCmd := &cobra.Command{
@@ -118,6 +201,7 @@ func buildAssetCommand(kind asset.Kind, handler asset.Handler) *cobra.Command {
 		}
 		outdatedCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
 		outdatedCmd.Flags().Bool("json", false, "Output as JSON for scripting")
+		outdatedCmd.Flags().Bool("changelog", false, "Show the commits and CHANGELOG.md entries each update pulls in")
 		parent.AddCommand(outdatedCmd)
 
 		updateCmd := &cobra.Command{
@@ -131,6 +215,14 @@ func buildAssetCommand(kind asset.Kind, handler asset.Handler) *cobra.Command {
 		updateCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
 		updateCmd.Flags().Bool("all", false, fmt.Sprintf("Update all %ss in the lock file", lower))
 		updateCmd.Flags().Bool("dry-run", false, "Show what would be updated without making changes")
+		updateCmd.Flags().Bool("no-changelog", false, "Don't show the commits and CHANGELOG.md entries each update pulls in")
+		updateCmd.Flags().Bool("retry-failed", false, fmt.Sprintf("Update only the %ss the last update --all in this folder failed on", lower))
+		updateCmd.Flags().Bool("json", false, "Print the summary, with each failure and its error class, as JSON")
+		if kind == asset.KindSkill {
+			updateCmd.Flags().StringSlice("paths", nil, "Update only these files or directories of the skill (e.g. docs/,SKILL.md)")
+			updateCmd.Flags().Bool("no-validate", false, "Skip SKILL.md frontmatter validation")
+			updateCmd.Flags().Bool("overwrite-modified", false, "Discard local changes to the updated skills without asking")
+		}
 		addSystemsFlag(updateCmd)
 		parent.AddCommand(updateCmd)
 	}
@@ -150,14 +242,49 @@ func runAssetInstall(cmd *cobra.Command, args []string, kind asset.Kind) error {
 
 	registryFilter, _ := cmd.Flags().GetString("registry")
 	noLock, _ := cmd.Flags().GetBool("no-lock")
+	local, _ := cmd.Flags().GetBool("local")
 	force, _ := cmd.Flags().GetBool("force")
+	reinstall, _ := cmd.Flags().GetBool("reinstall")
+	alias, _ := cmd.Flags().GetString("as")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+
+	if noLock && local {
+		return fmt.Errorf("--local cannot be used with --no-lock")
+	}
+
+	scope, targetDir, lockDir, err := resolveScope(cmd)
+	if err != nil {
+		return err
+	}
 
 	cfg, err := d.config.Load()
 	if err != nil {
 		return fmt.Errorf("loading config: %w", err)
 	}
 
-	arg := args[0]
+	// Registries may be named by alias; match them by repo from here on.
+	if registryFilter != "" {
+		reg, err := findRegistry(cfg.Registries, registryFilter)
+		if err != nil {
+			return err
+		}
+		registryFilter = reg.Repo
+	}
+
+	var arg string
+	if len(args) == 0 {
+		picked, pickErr := pickRegistryAsset(d, cfg, kind, registryFilter, targetDir)
+		if pickErr != nil {
+			return pickErr
+		}
+		if picked == nil {
+			return nil // Cancelled.
+		}
+		arg = picked.Entry.Name
+		registryFilter = picked.RegistryRepo
+	} else {
+		arg = args[0]
+	}
 
 	// Reject local paths explicitly.
 	if strings.HasPrefix(arg, "./") || strings.HasPrefix(arg, "../") ||
@@ -167,9 +294,19 @@ func runAssetInstall(cmd *cobra.Command, args []string, kind asset.Kind) error {
 
 	isURL := strings.HasPrefix(arg, "https://") || strings.HasPrefix(arg, "http://") || strings.HasPrefix(arg, "git@")
 
-	targetDir, err := resolveTargetDir(cmd)
-	if err != nil {
-		return err
+	// "<registry>/<name>" picks the registry by name or alias.
+	if prefix, name, ok := strings.Cut(arg, "/"); ok && !isURL && registryFilter == "" && !strings.Contains(name, "/") {
+		if reg, err := findRegistry(cfg.Registries, prefix); err == nil {
+			arg = name
+			registryFilter = reg.Repo
+		}
+	}
+
+	if !isURL && core.IsAssetPattern(arg) {
+		if jsonOutput {
+			return fmt.Errorf("--json cannot be used with a name pattern")
+		}
+		return installAssetPattern(cmd, d, cfg, kind, arg, registryFilter)
 	}
 
 	targetSystems, err := resolveTargetSystems(cmd)
@@ -179,26 +316,111 @@ func runAssetInstall(cmd *cobra.Command, args []string, kind asset.Kind) error {
 
 	// Resolve additional systems: for skills, add universal systems to any
 	// explicitly requested non-universal systems. MCP defaults to detected.
-	if targetSystems != nil && kind == asset.KindSkill {
+	if targetSystems != nil && kind == asset.KindSkill && scope == system.ScopeProject {
 		// User specified --systems: add universal systems.
 		targetSystems = append(system.Universal(), targetSystems...)
 		targetSystems = deduplicateSystems(targetSystems)
 	}
 
-	orch := core.NewOrchestrator()
+	orch := core.NewOrchestratorInScope(scope)
 
 	switch kind {
 	case asset.KindSkill:
-		return installSkill(cmd, orch, cfg, arg, isURL, registryFilter, targetDir, targetSystems, noLock, force, d)
+		return installSkill(cmd, orch, cfg, arg, isURL, registryFilter, targetDir, lockDir, targetSystems, noLock, local, force, reinstall, alias, d)
 	case asset.KindMCP:
-		return installMCP(orch, cfg, arg, registryFilter, targetDir, targetSystems, noLock, force, d)
-	case asset.KindAgent:
-		return installAgent(orch, cfg, arg, isURL, registryFilter, targetDir, targetSystems, noLock, force, d)
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		if dryRun && jsonOutput {
+			return fmt.Errorf("--json cannot be used with --dry-run")
+		}
+		return installMCP(orch, cfg, arg, registryFilter, targetDir, lockDir, scope, targetSystems, noLock, local, force, dryRun, jsonOutput, alias, d)
+	case asset.KindAgent, asset.KindCommand, asset.KindRule:
+		return installFileAsset(kind, orch, cfg, arg, isURL, registryFilter, targetDir, targetSystems, noLock, local, force, reinstall, jsonOutput, alias, d)
 	default:
 		return fmt.Errorf("install not implemented for kind %q", kind)
 	}
 }
 
+// installAssetPattern installs every registry entry of a kind whose name
+// matches a glob pattern such as go-*, as one transaction: all of them or,
+// if one fails, none.
+func installAssetPattern(cmd *cobra.Command, d *deps, cfg *core.Config, kind asset.Kind, pattern, registryFilter string) error {
+	noLock, _ := cmd.Flags().GetBool("no-lock")
+	local, _ := cmd.Flags().GetBool("local")
+	alias, _ := cmd.Flags().GetString("as")
+	namespaced, _ := cmd.Flags().GetBool("namespace")
+	global, _ := cmd.Flags().GetBool("global")
+	switch {
+	case noLock:
+		return fmt.Errorf("--no-lock cannot be used with a name pattern")
+	case local:
+		return fmt.Errorf("--local cannot be used with a name pattern")
+	case global:
+		return fmt.Errorf("--global cannot be used with a name pattern")
+	case alias != "":
+		return fmt.Errorf("--as cannot be used with a name pattern")
+	case namespaced:
+		return fmt.Errorf("--namespace cannot be used with a name pattern")
+	}
+	if prefix, _, ok := strings.Cut(pattern, "/"); ok {
+		return fmt.Errorf("registry %q not found", prefix)
+	}
+
+	rm := core.NewRegistryManager(d.config.RegistriesDir())
+	matches, err := rm.MatchAssets(cfg.Registries, kind, pattern, registryFilter)
+	if err != nil {
+		return err
+	}
+	return installRegistryBatch(cmd, cfg, rm, fmt.Sprintf("%q matches %d %s(s)", pattern, len(matches), kind), matches)
+}
+
+// installRegistryBatch lists registry entries under heading and installs
+// them as one transaction once the user confirms on a terminal, or with
+// --yes.
+func installRegistryBatch(cmd *cobra.Command, cfg *core.Config, rm *core.RegistryManager, heading string, matches []core.RegistryAssetInfo) error {
+	yes, _ := cmd.Flags().GetBool("yes")
+	targetDir, err := resolveTargetDir(cmd)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "%s:\n", heading)
+	for _, m := range matches {
+		fmt.Fprintf(os.Stdout, "  %-6s %s (%s)\n", m.Kind, m.Entry.Name, m.RegistryName)
+	}
+	if !yes {
+		if !isInteractive() {
+			return fmt.Errorf("not installing %d entries without confirmation; re-run with --yes", len(matches))
+		}
+		fmt.Fprintf(os.Stderr, "Install them into %s? [y/N] ", targetDir)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			return nil
+		}
+	}
+	fmt.Fprintln(os.Stdout)
+
+	targetSystems, err := resolveTargetSystems(cmd)
+	if err != nil {
+		return err
+	}
+	results, err := core.NewOrchestrator().InstallRecommended(matches, core.RecommendedInstallOptions{
+		TargetDir:         targetDir,
+		TargetSystems:     targetSystems,
+		IgnorePatterns:    cfg.Settings.IgnorePatterns,
+		CloneURLOverrides: cfg.Settings.CloneURLOverrides,
+	})
+	if err != nil {
+		var batchErr *core.RecommendedError
+		if errors.As(err, &batchErr) {
+			err = fmt.Errorf("installing %s %q: %w (nothing was installed)", batchErr.Asset.Kind, batchErr.Asset.Entry.Name, batchErr.Err)
+		}
+		return withConflictHint(err)
+	}
+	printRecommendedResults(results, targetDir)
+	return nil
+}
+
 // installSkill handles skill-specific install logic.
 func installSkill(
 	cmd *cobra.Command,
@@ -207,67 +429,146 @@ func installSkill(
 	arg string,
 	isURL bool,
 	registryFilter string,
-	targetDir string,
+	targetDir, lockDir string,
 	targetSystems []system.System,
-	noLock, force bool,
+	noLock, local, force, reinstall bool,
+	alias string,
 	d *deps,
 ) error {
 	internal, _ := cmd.Flags().GetBool("internal")
+	acceptLarge, _ := cmd.Flags().GetBool("accept-large")
+	noValidate, _ := cmd.Flags().GetBool("no-validate")
+	overwriteModified, _ := cmd.Flags().GetBool("overwrite-modified")
+	namespaced, _ := cmd.Flags().GetBool("namespace")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
 
 	var source *core.ParsedSource
 	var registryCommit string
 	var skillFilter string
+	var postInstall string
+	var platforms []string
+	var tags []string
+	var namespace string
+	var entry asset.RegistryEntry
+	var constraint string
+	var versioned bool
 	var err error
 
+	if namespaced && alias != "" {
+		return fmt.Errorf("--namespace cannot be used with --as")
+	}
+	namespaceMode := cfg.Settings.Namespaces()
+	if namespaced {
+		namespaceMode = core.NamespaceAlways
+	}
+
+	rm := core.NewRegistryManager(d.config.RegistriesDir())
 	if isURL {
 		if registryFilter != "" {
 			return fmt.Errorf("--registry cannot be used with a direct URL source")
 		}
+		if namespaced {
+			return fmt.Errorf("--namespace only applies to skills installed from a registry")
+		}
 		source, err = core.ParseSource(arg)
 		if err != nil {
 			return fmt.Errorf("invalid source: %w", err)
 		}
 	} else {
-		rm := core.NewRegistryManager(d.config.RegistriesDir())
+		// "<name>@<constraint>" asks for a version, e.g. go-review@^1.2.
+		arg, constraint, versioned = strings.Cut(arg, "@")
 		skillInfo, findErr := rm.FindSkill(cfg.Registries, arg, registryFilter)
 		if findErr != nil {
 			return findErr
 		}
+		if err := core.CheckPlatform(asset.KindSkill, skillInfo.Skill); err != nil {
+			return err
+		}
 		source, err = core.ParseSource(skillInfo.Skill.Source)
 		if err != nil {
 			return fmt.Errorf("invalid skill source in registry: %w", err)
 		}
 		skillFilter = skillInfo.Skill.Name
 		registryCommit = skillInfo.Skill.Commit
+		postInstall = skillInfo.Skill.PostInstallMessage
+		platforms = skillInfo.Skill.Platforms
+		tags = skillInfo.Skill.Tags
+		namespace = skillInfo.RegistryName
+		entry = skillInfo.Skill
+	}
+
+	// A registry entry's mirror only applies to installs of that entry;
+	// direct URL sources are fetched as given.
+	overrides := cfg.Settings.CloneURLOverrides
+	source.ApplyMirror(overrides, entry.CloneURL)
+
+	// A version constraint resolves to a version tag, or failing that to
+	// the registry entry's own version. Without one, the entry's version is
+	// recorded when its commit is what gets installed.
+	var version string
+	if versioned {
+		c, err := core.ParseVersionConstraint(constraint)
+		if err != nil {
+			return err
+		}
+		version, registryCommit, err = core.ResolveSkillVersion(source, entry, c)
+		if err != nil {
+			return err
+		}
+	} else if entry.Version != "" && registryCommit != "" {
+		if v, err := core.ParseVersion(entry.Version); err == nil {
+			version = v.String()
+		}
 	}
 
-	source.ApplyCloneURLOverride(cfg.Settings.CloneURLOverrides)
+	// Read existing lock for conflict checks and source-change warnings.
+	existingLock, _ := core.ReadLayeredLockFile(lockDir)
 
 	results, err := orch.InstallFromSource(source, asset.KindSkill, core.OrchestratorInstallOptions{
-		TargetDir:       targetDir,
-		TargetSystems:   targetSystems,
-		IncludeInternal: internal,
-		NameFilter:      skillFilter,
-		Commit:          registryCommit,
-		Force:           force,
+		TargetDir:         targetDir,
+		TargetSystems:     targetSystems,
+		IncludeInternal:   internal,
+		NameFilter:        skillFilter,
+		Commit:            registryCommit,
+		Version:           version,
+		VersionConstraint: constraint,
+		Force:             force,
+		Reinstall:         reinstall,
+		IgnorePatterns:    cfg.Settings.IgnorePatterns,
+		CloneURLOverrides: overrides,
+		Mirrors:           rm.Mirrors(cfg.Registries),
+		NoValidate:        noValidate,
+		Limits:            skillSizeLimits(cfg, acceptLarge),
+		ConfirmLarge:      confirmLargeSkill,
+		Alias:             alias,
+		Namespace:         namespace,
+		NamespaceMode:     namespaceMode,
+		Lock:              existingLock,
+		ResolveConflict:   promptAlias,
+		OverwriteModified: overwriteModified,
+		ConfirmOverwrite:  confirmOverwriteModified,
 	})
 	if err != nil {
-		return err
-	}
-
-	// Read existing lock for source-change warnings.
-	var existingLock *core.LockFile
-	if !noLock {
-		existingLock, _ = core.ReadLockFile(targetDir)
+		var largeErr *core.LargeSkillError
+		if errors.As(err, &largeErr) {
+			return fmt.Errorf("%w; re-run with --accept-large to install anyway", err)
+		}
+		var invalidErr *core.InvalidSkillError
+		if errors.As(err, &invalidErr) {
+			return fmt.Errorf("%w\nfix the SKILL.md in the source, or re-run with --no-validate to install anyway", err)
+		}
+		return withConflictHint(err)
 	}
 
+	report := core.InstallReport{Kind: asset.KindSkill, Registry: namespace, PostInstallMessage: postInstall}
 	for _, r := range results {
-		fmt.Fprintf(os.Stdout, "Installed: %s\n", r.Asset.Name)
-		if r.Asset.PreparedPath != "" {
-			fmt.Fprintf(os.Stdout, "  Path: %s\n", r.Asset.PreparedPath)
+		report.Assets = append(report.Assets, core.NewInstallReportAsset(r, targetDir))
+		if r.Unchanged {
+			continue
 		}
-		if len(r.Systems) > 0 {
-			fmt.Fprintf(os.Stdout, "  Systems: %s\n", joinStrings(r.Systems))
+		if len(r.MissingRequirements) > 0 && lockDir == targetDir {
+			report.Warn("skill %q needs %s, not found in %s; it may not work in this project",
+				r.Asset.Name, joinStrings(r.MissingRequirements), targetDir)
 		}
 
 		if !noLock && r.Commit != "" {
@@ -276,31 +577,30 @@ func installSkill(
 				src = core.NormalizeSource(source.Host, source.Owner, source.Repo, "")
 			}
 
-			// Warn if source changed.
+			// Warn if source changed (only reachable with --force).
 			if existingLock != nil {
 				for _, existing := range core.AssetsByKind(existingLock, asset.KindSkill) {
 					if existing.Name == r.Asset.Name && existing.Source != src {
-						fmt.Fprintf(os.Stderr, "Warning: skill %q source changed from %q to %q\n",
-							r.Asset.Name, existing.Source, src)
+						report.Warn("skill %q source changed from %q to %q", r.Asset.Name, existing.Source, src)
 					}
 				}
 			}
 
-			entry := asset.LockedAsset{
-				Kind:   asset.KindSkill,
-				Name:   r.Asset.Name,
-				Source: src,
-				Commit: r.Commit,
-				Ref:    r.Ref,
-			}
-			if lockErr := core.AddOrUpdateAsset(targetDir, entry); lockErr != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to update lock file: %v\n", lockErr)
+			r.Asset.Source = src
+			entry := r.LockEntry(platforms, tags)
+			if lockName, lockErr := writeLockEntry(lockDir, entry, local); lockErr != nil {
+				report.Warn("failed to update lock file: %v", lockErr)
+			} else {
+				report.LockFile = lockName
+				if lockDir == targetDir {
+					attestToReport(&report, d, cfg, targetDir, entry)
+				}
 			}
 		} else if !noLock && r.Commit == "" {
-			fmt.Fprintf(os.Stderr, "Warning: could not determine commit for %q; not pinned in lock file\n", r.Asset.Name)
+			report.Warn("could not determine commit for %q; not pinned in lock file", r.Asset.Name)
 		}
 	}
-	return nil
+	return writeInstallReport(report, jsonOutput)
 }
 
 // installMCP handles MCP-specific install logic.
@@ -309,9 +609,11 @@ func installMCP(
 	cfg *core.Config,
 	name string,
 	registryFilter string,
-	targetDir string,
+	targetDir, lockDir string,
+	scope system.Scope,
 	targetSystems []system.System,
-	noLock, force bool,
+	noLock, local, force, dryRun, jsonOutput bool,
+	alias string,
 	d *deps,
 ) error {
 	rm := core.NewRegistryManager(d.config.RegistriesDir())
@@ -319,87 +621,151 @@ func installMCP(
 	if findErr != nil {
 		return findErr
 	}
+	if err := core.CheckPlatform(asset.KindMCP, mcpInfo.MCP); err != nil {
+		return err
+	}
 
 	// Resolve target systems for MCP.
 	if targetSystems == nil {
-		// Default: all MCP-capable systems detected in the folder.
+		// Default: all MCP-capable systems detected in the folder, or for
+		// --global, installed on this machine.
 		detected := system.DetectInFolder(targetDir)
-		targetSystems = filterMCPCapable(detected)
+		if scope == system.ScopeGlobal {
+			detected = system.Detect()
+		}
+		targetSystems = filterMCPCapable(system.InScope(detected, scope))
 		if len(targetSystems) == 0 {
 			// Fall back to all MCP-capable systems.
-			targetSystems = filterMCPCapable(system.All())
+			targetSystems = filterMCPCapable(system.InScope(system.All(), scope))
 		}
 	} else {
-		targetSystems = filterMCPCapable(targetSystems)
+		targetSystems = filterMCPCapable(system.InScope(targetSystems, scope))
 		if len(targetSystems) == 0 {
 			return fmt.Errorf("none of the specified systems support MCP configurations")
 		}
 	}
 
-	fmt.Fprintf(os.Stdout, "Installing MCP %q from registry %q...\n\n", name, mcpInfo.RegistryName)
+	// Resolve the server key, checking for a same-named MCP from another
+	// registry before any config file is touched.
+	installName := mcpInfo.MCP.Name
+	existingLock, _ := core.ReadLayeredLockFile(lockDir)
+	if alias != "" {
+		if err := core.ValidateAlias(alias); err != nil {
+			return err
+		}
+		installName = alias
+	} else if err := core.CheckInstallName(existingLock, asset.KindMCP, installName); err != nil {
+		return withConflictHint(err)
+	}
+	if !force {
+		origins := []string{mcpInfo.RegistryName, mcpInfo.RegistryRepo}
+		if c := core.FindConflict(existingLock, asset.KindMCP, installName, origins...); c != nil {
+			renamed := promptAlias(*c)
+			if renamed == "" {
+				return withConflictHint(&core.ConflictError{Conflict: *c})
+			}
+			if err := core.ValidateAlias(renamed); err != nil {
+				return err
+			}
+			if c := core.FindConflict(existingLock, asset.KindMCP, renamed, origins...); c != nil {
+				return &core.ConflictError{Conflict: *c}
+			}
+			installName = renamed
+		}
+	}
+	name = installName
+
+	if !jsonOutput {
+		fmt.Fprintf(os.Stdout, "Installing MCP %q from registry %q...\n\n", name, mcpInfo.RegistryName)
+	}
 
 	// Build asset from MCP entry.
 	meta, ok := mcpInfo.MCP.Meta.(asset.MCPMeta)
 	if !ok {
 		return fmt.Errorf("invalid MCP metadata")
 	}
+	if err := core.CheckMCPHost(meta); err != nil {
+		return err
+	}
+	// Reinstalling from the same registry keeps local overrides.
+	var overrides core.MCPOverrides
+	if existing := core.FindLockedAsset(existingLock, asset.KindMCP, name); existing != nil {
+		if registry, _ := existing.Data["registry"].(string); registry == mcpInfo.RegistryName {
+			overrides = core.LockedMCPOverrides(*existing)
+		}
+	}
 	a := asset.Asset{
 		Kind:        asset.KindMCP,
-		Name:        mcpInfo.MCP.Name,
+		Name:        name,
 		Description: mcpInfo.MCP.Description,
-		Meta:        meta,
+		Meta:        overrides.Apply(meta),
 	}
 
-	// Install into each target system.
-	fmt.Fprintln(os.Stdout, "Wrote MCP config to:")
-	for _, sys := range targetSystems {
-		configPath := resolveMCPConfigPathFromSystem(sys, targetDir)
-
-		err := sys.Install(a, targetDir, system.InstallOptions{Force: force})
-		if err != nil {
-			if strings.Contains(err.Error(), "already exists") {
+	if dryRun {
+		for _, sys := range targetSystems {
+			configPath := resolveMCPConfigPathFromSystem(sys, targetDir)
+			err := previewMCPInstall(sys, a, targetDir, force)
+			if errors.Is(err, system.ErrAlreadyExists) {
 				fmt.Fprintf(os.Stdout, "  ! %-24s %q already exists\n", configPath, name)
-				continue
+			} else if err != nil {
+				fmt.Fprintf(os.Stderr, "  x %-24s error: %s\n", configPath, err.Error())
 			}
-			fmt.Fprintf(os.Stderr, "  x %-24s error: %s\n", configPath, err.Error())
-			continue
 		}
+		fmt.Fprintln(os.Stdout, "Dry run: no config files or lock file were written.")
+		return nil
+	}
 
-		fmt.Fprintf(os.Stdout, "  + %-24s (%s)\n", configPath, sys.DisplayName())
+	report := core.InstallReport{
+		Kind:               asset.KindMCP,
+		Registry:           mcpInfo.RegistryName,
+		PostInstallMessage: mcpInfo.MCP.PostInstallMessage,
+	}
+	installed := core.InstallReportAsset{Name: name, RequiredEnv: core.ExtractRequiredEnv(meta.Env)}
+	if name != mcpInfo.MCP.Name {
+		installed.AliasOf = mcpInfo.MCP.Name
 	}
 
-	// Update lock file.
-	if !noLock {
-		requiredEnv := core.ExtractRequiredEnv(meta.Env)
-		data := map[string]any{
-			"registry":   mcpInfo.RegistryName,
-			"configHash": core.ComputeConfigHash(meta),
-		}
-		if len(requiredEnv) > 0 {
-			data["requiredEnv"] = requiredEnv
-		}
-		entry := asset.LockedAsset{
-			Kind: asset.KindMCP,
-			Name: name,
-			Data: data,
-		}
-		if lockErr := core.AddOrUpdateAsset(targetDir, entry); lockErr != nil {
-			fmt.Fprintf(os.Stderr, "Warning: failed to update lock file: %v\n", lockErr)
+	// Install into each target system.
+	for _, sys := range targetSystems {
+		file := core.InstallReportFile{
+			Path:   resolveMCPConfigPathFromSystem(sys, targetDir),
+			System: sys.DisplayName(),
+			Key:    core.MCPConfigKey(sys, name),
+		}
+		if err := sys.Install(a, targetDir, system.InstallOptions{Force: force}); errors.Is(err, system.ErrAlreadyExists) {
+			file.Skipped = fmt.Sprintf("%q already exists", name)
+		} else if err != nil {
+			file.Error = err.Error()
 		} else {
-			fmt.Fprintln(os.Stdout, "\nUpdated duckrow.lock.json")
+			installed.Systems = append(installed.Systems, sys.Name())
 		}
+		installed.Files = append(installed.Files, file)
+	}
+	report.Assets = []core.InstallReportAsset{installed}
 
-		if len(requiredEnv) > 0 {
-			fmt.Fprintln(os.Stdout, "\n! The following environment variables are required:")
-			for _, v := range requiredEnv {
-				fmt.Fprintf(os.Stdout, "  %s  (used by %s)\n", v, name)
+	// Update lock file.
+	if !noLock {
+		info := asset.InstallInfo{
+			Registry:  mcpInfo.RegistryName,
+			Platforms: mcpInfo.MCP.Platforms,
+			Tags:      mcpInfo.MCP.Tags,
+			AliasOf:   installed.AliasOf,
+		}
+		// The hash covers the registry config; overrides are recorded apart.
+		base := a
+		base.Meta = meta
+		handler, _ := asset.Get(asset.KindMCP)
+		entry := core.WithMCPOverrides(handler.BuildLockEntry(base, info), overrides)
+		if lockName, lockErr := writeLockEntry(lockDir, entry, local); lockErr != nil {
+			report.Warn("failed to update lock file: %v", lockErr)
+		} else {
+			report.LockFile = lockName
+			if lockDir == targetDir {
+				attestToReport(&report, d, cfg, targetDir, entry)
 			}
-			fmt.Fprintln(os.Stdout, "\n  Add values to .env.duckrow or ~/.duckrow/.env.duckrow")
 		}
 	}
-
-	fmt.Fprintf(os.Stdout, "\nMCP %q installed successfully.\n", name)
-	return nil
+	return writeInstallReport(report, jsonOutput)
 }
 
 // ---------------------------------------------------------------------------
@@ -418,26 +784,27 @@ func runAssetUninstall(cmd *cobra.Command, args []string, kind asset.Kind) error
 
 	noLock, _ := cmd.Flags().GetBool("no-lock")
 
-	targetDir, err := resolveTargetDir(cmd)
+	scope, targetDir, lockDir, err := resolveScope(cmd)
 	if err != nil {
 		return err
 	}
 
-	orch := core.NewOrchestrator()
+	orch := core.NewOrchestratorInScope(scope)
 
 	switch kind {
 	case asset.KindSkill:
-		return uninstallSkill(orch, targetDir, args, all, noLock)
+		return uninstallSkill(orch, targetDir, lockDir, args, all, noLock)
 	case asset.KindMCP:
-		return uninstallMCP(targetDir, args, all, noLock)
-	case asset.KindAgent:
-		return uninstallAgent(orch, targetDir, args, all, noLock)
+		takeOwnership, _ := cmd.Flags().GetBool("take-ownership")
+		return uninstallMCP(targetDir, lockDir, scope, args, all, noLock, takeOwnership)
+	case asset.KindAgent, asset.KindCommand, asset.KindRule:
+		return uninstallFileAsset(kind, orch, targetDir, args, all, noLock)
 	default:
 		return fmt.Errorf("uninstall not implemented for kind %q", kind)
 	}
 }
 
-func uninstallSkill(orch *core.Orchestrator, targetDir string, args []string, all, noLock bool) error {
+func uninstallSkill(orch *core.Orchestrator, targetDir, lockDir string, args []string, all, noLock bool) error {
 	if all {
 		// Scan to find all installed skills, then remove each.
 		allInstalled, err := orch.ScanFolder(targetDir)
@@ -460,7 +827,7 @@ func uninstallSkill(orch *core.Orchestrator, targetDir string, args []string, al
 
 		if !noLock {
 			for _, s := range skills {
-				if lockErr := core.RemoveAssetEntry(targetDir, asset.KindSkill, s.Name); lockErr != nil {
+				if lockErr := core.RemoveLayeredAssetEntry(lockDir, asset.KindSkill, s.Name); lockErr != nil {
 					fmt.Fprintf(os.Stderr, "Warning: failed to update lock file for %q: %v\n", s.Name, lockErr)
 				}
 			}
@@ -476,20 +843,20 @@ func uninstallSkill(orch *core.Orchestrator, targetDir string, args []string, al
 	fmt.Fprintf(os.Stdout, "Removed: %s\n", name)
 
 	if !noLock {
-		if lockErr := core.RemoveAssetEntry(targetDir, asset.KindSkill, name); lockErr != nil {
+		if lockErr := core.RemoveLayeredAssetEntry(lockDir, asset.KindSkill, name); lockErr != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to update lock file: %v\n", lockErr)
 		}
 	}
 	return nil
 }
 
-func uninstallMCP(targetDir string, args []string, all, noLock bool) error {
-	lf, err := core.ReadLockFile(targetDir)
+func uninstallMCP(targetDir, lockDir string, scope system.Scope, args []string, all, noLock, takeOwnership bool) error {
+	lf, err := core.ReadLayeredLockFile(lockDir)
 	if err != nil {
 		return fmt.Errorf("reading lock file: %w", err)
 	}
 	if lf == nil {
-		return fmt.Errorf("no duckrow.lock.json found in %s", targetDir)
+		return fmt.Errorf("no duckrow.lock.json found in %s", lockDir)
 	}
 
 	if all {
@@ -499,22 +866,34 @@ func uninstallMCP(targetDir string, args []string, all, noLock bool) error {
 			return nil
 		}
 
+		var removed []string
+		var kept int
 		for _, m := range lockedMCPs {
-			if err := removeMCPFromSystems(m.Name, nil, targetDir); err != nil {
-				return err
+			opts := system.RemoveMCPOptions{URLHash: asset.LockedURLHash(m), TakeOwnership: takeOwnership}
+			if err := removeMCPFromSystems(m.Name, nil, targetDir, scope, opts); err != nil {
+				if !errors.Is(err, system.ErrUnmanaged) {
+					return err
+				}
+				fmt.Fprintf(os.Stderr, "Kept: %s (%v)\n", m.Name, err)
+				kept++
+				continue
 			}
+			removed = append(removed, m.Name)
 			fmt.Fprintf(os.Stdout, "Removed: %s\n", m.Name)
 		}
-		fmt.Fprintf(os.Stdout, "\nRemoved %d MCP(s).\n", len(lockedMCPs))
+		fmt.Fprintf(os.Stdout, "\nRemoved %d MCP(s).\n", len(removed))
 
-		// Remove all MCP entries from lock file.
+		// Remove the removed MCPs' entries from the lock file.
 		if !noLock {
-			for _, m := range lockedMCPs {
-				if lockErr := core.RemoveAssetEntry(targetDir, asset.KindMCP, m.Name); lockErr != nil {
+			for _, name := range removed {
+				if lockErr := core.RemoveLayeredAssetEntry(lockDir, asset.KindMCP, name); lockErr != nil {
 					fmt.Fprintf(os.Stderr, "Warning: failed to update lock file: %v\n", lockErr)
 				}
 			}
 		}
+		if kept > 0 {
+			return fmt.Errorf("%d MCP(s) kept; rerun with --take-ownership to remove config entries duckrow didn't write", kept)
+		}
 		return nil
 	}
 
@@ -528,15 +907,19 @@ func uninstallMCP(targetDir string, args []string, all, noLock bool) error {
 
 	fmt.Fprintf(os.Stdout, "Removing MCP %q...\n\n", name)
 
-	if err := removeMCPFromSystems(name, nil, targetDir); err != nil {
+	opts := system.RemoveMCPOptions{URLHash: asset.LockedURLHash(*lockedMCP), TakeOwnership: takeOwnership}
+	if err := removeMCPFromSystems(name, nil, targetDir, scope, opts); err != nil {
+		if errors.Is(err, system.ErrUnmanaged) {
+			return fmt.Errorf("%w; remove it with 'duckrow mcp uninstall %s --take-ownership'", err, name)
+		}
 		return err
 	}
 
 	if !noLock {
-		if lockErr := core.RemoveAssetEntry(targetDir, asset.KindMCP, name); lockErr != nil {
+		if lockErr := core.RemoveLayeredAssetEntry(lockDir, asset.KindMCP, name); lockErr != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to update lock file: %v\n", lockErr)
 		} else {
-			fmt.Fprintln(os.Stdout, "\nUpdated duckrow.lock.json")
+			fmt.Fprintf(os.Stdout, "\nUpdated %s\n", lockFileLabel(lockDir))
 		}
 	}
 
@@ -544,8 +927,11 @@ func uninstallMCP(targetDir string, args []string, all, noLock bool) error {
 	return nil
 }
 
-// removeMCPFromSystems removes an MCP entry from agent config files.
-func removeMCPFromSystems(name string, agentNames []string, targetDir string) error {
+// removeMCPFromSystems removes an MCP entry from agent config files, the
+// ones in targetDir or, in system.ScopeGlobal, the user-level ones. Entries
+// duckrow didn't write are kept, unless opts.TakeOwnership is set, and an
+// error wrapping system.ErrUnmanaged is returned once the rest are removed.
+func removeMCPFromSystems(name string, agentNames []string, targetDir string, scope system.Scope, opts system.RemoveMCPOptions) error {
 	var targetSystems []system.System
 	if len(agentNames) > 0 {
 		var err error
@@ -560,40 +946,32 @@ func removeMCPFromSystems(name string, agentNames []string, targetDir string) er
 	}
 
 	fmt.Fprintln(os.Stdout, "Removed from:")
-	for _, sys := range targetSystems {
+	var unmanaged error
+	for _, sys := range system.InScope(targetSystems, scope) {
 		if !sys.Supports(asset.KindMCP) {
 			continue
 		}
 		configPath := resolveMCPConfigPathFromSystem(sys, targetDir)
-		err := sys.Remove(asset.KindMCP, name, targetDir)
+		var err error
+		if r, ok := sys.(interface {
+			RemoveMCP(string, string, system.RemoveMCPOptions) error
+		}); ok {
+			err = r.RemoveMCP(name, targetDir, opts)
+		} else {
+			err = sys.Remove(asset.KindMCP, name, targetDir)
+		}
+		if errors.Is(err, system.ErrUnmanaged) {
+			fmt.Fprintf(os.Stderr, "  = %-24s kept: not written by duckrow\n", configPath)
+			unmanaged = err
+			continue
+		}
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "  x %-24s error: %s\n", configPath, err.Error())
 			continue
 		}
 		fmt.Fprintf(os.Stdout, "  - %-24s (%s)\n", configPath, sys.DisplayName())
 	}
-	return nil
-}
-
-func lockedRequiredEnv(locked asset.LockedAsset) []string {
-	if locked.Data == nil {
-		return nil
-	}
-	if envs, ok := locked.Data["requiredEnv"]; ok {
-		switch v := envs.(type) {
-		case []string:
-			return v
-		case []interface{}:
-			result := make([]string, 0, len(v))
-			for _, item := range v {
-				if s, ok := item.(string); ok {
-					result = append(result, s)
-				}
-			}
-			return result
-		}
-	}
-	return nil
+	return unmanaged
 }
 
 // ---------------------------------------------------------------------------
@@ -601,13 +979,13 @@ func lockedRequiredEnv(locked asset.LockedAsset) []string {
 // ---------------------------------------------------------------------------
 
 func runAssetList(cmd *cobra.Command, kind asset.Kind) error {
-	targetDir, err := resolveTargetDir(cmd)
+	scope, targetDir, lockDir, err := resolveScope(cmd)
 	if err != nil {
 		return err
 	}
 	jsonOutput, _ := cmd.Flags().GetBool("json")
 
-	orch := core.NewOrchestrator()
+	orch := core.NewOrchestratorInScope(scope)
 	allInstalled, err := orch.ScanFolder(targetDir)
 	if err != nil {
 		return fmt.Errorf("scanning folder: %w", err)
@@ -615,9 +993,11 @@ func runAssetList(cmd *cobra.Command, kind asset.Kind) error {
 
 	items := allInstalled[kind]
 
+	// Team and local lock layers, used to label where each asset came from.
+	lf, _ := core.ReadLayeredLockFile(lockDir)
+
 	if kind == asset.KindMCP {
 		// MCPs are config-only; list from lock file.
-		lf, _ := core.ReadLockFile(targetDir)
 		lockedMCPs := core.AssetsByKind(lf, asset.KindMCP)
 		if len(lockedMCPs) == 0 {
 			if jsonOutput {
@@ -628,7 +1008,15 @@ func runAssetList(cmd *cobra.Command, kind asset.Kind) error {
 			return nil
 		}
 		if jsonOutput {
-			data, err := json.MarshalIndent(lockedMCPs, "", "  ")
+			type mcpInfo struct {
+				asset.LockedAsset
+				Origin core.LockOrigin `json:"origin"`
+			}
+			out := make([]mcpInfo, 0, len(lockedMCPs))
+			for _, m := range lockedMCPs {
+				out = append(out, mcpInfo{LockedAsset: m, Origin: lf.Origin(asset.KindMCP, m.Name)})
+			}
+			data, err := json.MarshalIndent(out, "", "  ")
 			if err != nil {
 				return fmt.Errorf("marshaling JSON: %w", err)
 			}
@@ -636,17 +1024,23 @@ func runAssetList(cmd *cobra.Command, kind asset.Kind) error {
 			return nil
 		}
 		for _, m := range lockedMCPs {
-			fmt.Fprintf(os.Stdout, "%s\n", m.Name)
+			fmt.Fprintf(os.Stdout, "%s%s%s\n", m.Name, originLabel(lf, asset.KindMCP, m.Name), overridesLabel(core.LockedMCPOverrides(m)))
 		}
 		return nil
 	}
 
-	if kind == asset.KindAgent {
-		// Agents are rendered per-system; scan each system to build system lists.
-		return listAgents(targetDir, jsonOutput)
+	if asset.IsSystemFile(kind) {
+		// Agents, commands, and rules are written per-system; scan each
+		// system to build system lists.
+		return listFileAssets(kind, targetDir, lf, jsonOutput)
 	}
 
 	// File-based assets (skills).
+	if scope == system.ScopeProject {
+		if issues, err := orch.ScanLinks(targetDir); err == nil && len(issues) > 0 {
+			fmt.Fprintf(os.Stderr, "Warning: %d broken or stale skill link(s) in system directories; run 'duckrow repair'\n", len(issues))
+		}
+	}
 	if len(items) == 0 {
 		if jsonOutput {
 			fmt.Fprintln(os.Stdout, "[]")
@@ -658,7 +1052,17 @@ func runAssetList(cmd *cobra.Command, kind asset.Kind) error {
 	}
 
 	if jsonOutput {
-		data, err := json.MarshalIndent(items, "", "  ")
+		type installedInfo struct {
+			asset.InstalledAsset
+			Origin core.LockOrigin
+			Size   core.SkillSize
+		}
+		out := make([]installedInfo, 0, len(items))
+		for _, item := range items {
+			size, _ := core.DirSize(item.Path)
+			out = append(out, installedInfo{InstalledAsset: item, Origin: lf.Origin(kind, item.Name), Size: size})
+		}
+		data, err := json.MarshalIndent(out, "", "  ")
 		if err != nil {
 			return fmt.Errorf("marshaling JSON: %w", err)
 		}
@@ -667,7 +1071,13 @@ func runAssetList(cmd *cobra.Command, kind asset.Kind) error {
 	}
 
 	for _, item := range items {
-		fmt.Fprintf(os.Stdout, "%s\n", item.Name)
+		partial := ""
+		if locked := core.FindLockedAsset(lf, kind, item.Name); locked != nil {
+			if p := core.LockedPartial(*locked); p != nil {
+				partial = fmt.Sprintf(" (partial: %s)", p)
+			}
+		}
+		fmt.Fprintf(os.Stdout, "%s%s%s\n", item.Name, originLabel(lf, kind, item.Name), partial)
 		if item.Description != "" {
 			fmt.Fprintf(os.Stdout, "  %s\n", item.Description)
 		}
@@ -675,6 +1085,99 @@ func runAssetList(cmd *cobra.Command, kind asset.Kind) error {
 	return nil
 }
 
+// ---------------------------------------------------------------------------
+// runAssetInfo — shared registry entry details for all asset kinds
+// ---------------------------------------------------------------------------
+
+// runAssetInfo prints a registry entry: where it comes from, whether it is
+// installed in the target folder, and its post-install message. name may
+// also be the alias an asset was installed under.
+func runAssetInfo(cmd *cobra.Command, name string, kind asset.Kind) error {
+	d, err := newDeps()
+	if err != nil {
+		return err
+	}
+	cfg, err := d.config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	targetDir, err := resolveTargetDir(cmd)
+	if err != nil {
+		return err
+	}
+
+	registries := cfg.Registries
+	if registryFilter, _ := cmd.Flags().GetString("registry"); registryFilter != "" {
+		reg, err := findRegistry(cfg.Registries, registryFilter)
+		if err != nil {
+			return err
+		}
+		registries = []core.Registry{*reg}
+	}
+
+	lf, _ := core.ReadLayeredLockFile(targetDir)
+	locked := core.FindLockedAsset(lf, kind, name)
+	lookup := name
+	if locked != nil {
+		lookup = core.LockedUpstreamName(*locked)
+	}
+
+	rm := core.NewRegistryManager(d.config.RegistriesDir())
+	entry, registryName, err := rm.FindAsset(registries, kind, lookup)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "Name: %s\n", entry.Name)
+	if entry.Description != "" {
+		fmt.Fprintf(os.Stdout, "Description: %s\n", entry.Description)
+	}
+	fmt.Fprintf(os.Stdout, "Registry: %s\n", registryName)
+	if entry.Source != "" {
+		fmt.Fprintf(os.Stdout, "Source: %s\n", entry.Source)
+	}
+	if entry.Commit != "" {
+		fmt.Fprintf(os.Stdout, "Pinned commit: %s\n", entry.Commit)
+	}
+	if len(entry.Platforms) > 0 {
+		fmt.Fprintf(os.Stdout, "Platforms: %s\n", strings.Join(entry.Platforms, ", "))
+	}
+	if len(entry.Tags) > 0 {
+		fmt.Fprintf(os.Stdout, "Tags: %s\n", strings.Join(entry.Tags, ", "))
+	}
+	if meta, ok := entry.Meta.(asset.MCPMeta); ok {
+		if meta.URL != "" {
+			fmt.Fprintf(os.Stdout, "URL: %s\n", meta.URL)
+		} else if meta.Command != "" {
+			fmt.Fprintf(os.Stdout, "Command: %s\n", strings.Join(append([]string{meta.Command}, meta.Args...), " "))
+		}
+		if required := core.ExtractRequiredEnv(meta.Env); len(required) > 0 {
+			fmt.Fprintf(os.Stdout, "Required env: %s\n", joinStrings(required))
+		}
+	}
+	switch {
+	case locked == nil:
+		fmt.Fprintln(os.Stdout, "Installed: no")
+	case locked.Commit != "":
+		fmt.Fprintf(os.Stdout, "Installed: yes, as %s at %s%s\n", locked.Name, core.TruncateCommit(locked.Commit), originLabel(lf, kind, locked.Name))
+	default:
+		fmt.Fprintf(os.Stdout, "Installed: yes, as %s%s\n", locked.Name, originLabel(lf, kind, locked.Name))
+	}
+	if kind == asset.KindMCP && locked != nil {
+		if o := core.LockedMCPOverrides(*locked); !o.IsEmpty() {
+			fmt.Fprintln(os.Stdout, "Local overrides (duckrow mcp edit):")
+			if o.Args != nil {
+				fmt.Fprintf(os.Stdout, "  args: %s\n", strings.Join(o.Args, " "))
+			}
+			for _, name := range o.EnvNames() {
+				fmt.Fprintf(os.Stdout, "  env: %s=%s\n", name, o.Env[name])
+			}
+		}
+	}
+	printPostInstallMessage(entry.PostInstallMessage)
+	return nil
+}
+
 // ---------------------------------------------------------------------------
 // runAssetSync — shared per-kind sync handler
 // ---------------------------------------------------------------------------
@@ -684,11 +1187,20 @@ type assetSyncResult struct {
 	installed   int
 	skipped     int
 	errors      int
+	failures    []core.FailedItem   // one per error, for --json and --retry-failed
 	requiredEnv map[string][]string // envVar -> []mcpName (MCP-specific)
 }
 
+// fail counts the named asset as failed with err, classed by
+// core.ErrorClass unless class is given.
+func (r *assetSyncResult) fail(kind asset.Kind, name, class string, err error) {
+	r.errors++
+	r.failures = append(r.failures, core.NewFailedItem(kind, name, class, err))
+}
+
 func runAssetSync(cmd *cobra.Command, kind asset.Kind) error {
-	result, err := runAssetSyncInner(cmd, kind)
+	// On skill and agent sync, --force is the deprecated name of --reinstall.
+	result, err := runAssetSyncInner(cmd, kind, nil, kind != asset.KindMCP)
 	if err != nil {
 		return err
 	}
@@ -701,21 +1213,29 @@ func runAssetSync(cmd *cobra.Command, kind asset.Kind) error {
 		fmt.Fprintf(os.Stdout, "\nMCPs: %d installed, %d skipped, %d errors\n",
 			result.installed, result.skipped, result.errors)
 		printRequiredEnvSummary(result.requiredEnv)
-	case asset.KindAgent:
-		fmt.Fprintf(os.Stdout, "\nAgents: %d installed, %d skipped, %d errors\n",
-			result.installed, result.skipped, result.errors)
+	case asset.KindAgent, asset.KindCommand, asset.KindRule:
+		fmt.Fprintf(os.Stdout, "\n%ss: %d installed, %d skipped, %d errors\n",
+			display, result.installed, result.skipped, result.errors)
 	default:
 		fmt.Fprintf(os.Stdout, "\nSynced: %d installed, %d skipped, %d errors\n",
 			result.installed, result.skipped, result.errors)
 	}
 
+	if targetDir, err := resolveTargetDir(cmd); err == nil {
+		notifyDone(cmd, targetDir, fmt.Sprintf("%d installed, %d skipped, %d errors",
+			result.installed, result.skipped, result.errors), result.errors > 0)
+	}
+
 	if result.errors > 0 {
 		return fmt.Errorf("%d %s(s) failed to sync", result.errors, strings.ToLower(display))
 	}
 	return nil
 }
 
-func runAssetSyncInner(cmd *cobra.Command, kind asset.Kind) (*assetSyncResult, error) {
+// runAssetSyncInner syncs one asset kind. If lf is nil, the layered lock file
+// is read from the target directory. forceReinstalls makes --force also
+// reinstall skills and agents, as --reinstall does.
+func runAssetSyncInner(cmd *cobra.Command, kind asset.Kind, lf *core.LockFile, forceReinstalls bool) (*assetSyncResult, error) {
 	d, err := newDeps()
 	if err != nil {
 		return nil, err
@@ -728,43 +1248,81 @@ func runAssetSyncInner(cmd *cobra.Command, kind asset.Kind) (*assetSyncResult, e
 
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
 	force, _ := cmd.Flags().GetBool("force")
+	reinstall, _ := cmd.Flags().GetBool("reinstall")
+	overwriteModified, _ := cmd.Flags().GetBool("overwrite-modified")
+	if forceReinstalls && kind != asset.KindMCP {
+		reinstall = reinstall || force
+	}
 
 	targetSystems, err := resolveTargetSystems(cmd)
 	if err != nil {
 		return nil, err
 	}
 
-	lf, err := core.ReadLockFile(targetDir)
-	if err != nil {
-		return nil, fmt.Errorf("reading lock file: %w", err)
-	}
 	if lf == nil {
-		return nil, fmt.Errorf("no duckrow.lock.json found in %s", targetDir)
+		lf, err = core.ReadLayeredLockFile(targetDir)
+		if err != nil {
+			return nil, fmt.Errorf("reading lock file: %w", err)
+		}
+		if lf == nil {
+			return nil, fmt.Errorf("no duckrow.lock.json found in %s", targetDir)
+		}
+	}
+
+	tag, _ := cmd.Flags().GetString("tag")
+	if tag != "" {
+		lf = lf.WithTag(tag)
+	}
+	if retry, _ := cmd.Flags().GetBool("retry-failed"); retry {
+		lf = lf.WithFailed(d.config.Failures(targetDir, failuresSync))
+	}
+
+	// Two locked names that map to the same directory or file would
+	// overwrite each other.
+	if kind != asset.KindMCP {
+		if groups := core.FindLockCollisions(lf, kind); len(groups) > 0 {
+			for _, names := range groups {
+				fmt.Fprintf(os.Stderr, "Error: %ss %s would be installed at the same path\n", kind, strings.Join(names, ", "))
+			}
+			return nil, fmt.Errorf("conflicting %s names in lock file; reinstall one of them with --as <name>", kind)
+		}
 	}
 
 	cfg, err := d.config.Load()
 	if err != nil {
 		return nil, fmt.Errorf("loading config: %w", err)
 	}
+	overrides := cfg.Settings.CloneURLOverrides
+	mirrors := core.NewRegistryManager(d.config.RegistriesDir()).Mirrors(cfg.Registries)
 
+	var result *assetSyncResult
 	switch kind {
 	case asset.KindSkill:
-		return syncSkills(lf, cfg, targetDir, targetSystems, dryRun, force)
+		result, err = syncSkills(lf, cfg, overrides, mirrors, targetDir, targetSystems, dryRun, reinstall, overwriteModified)
 	case asset.KindMCP:
-		return syncMCPs(lf, cfg, targetDir, targetSystems, dryRun, force, d)
-	case asset.KindAgent:
-		return syncAgents(lf, cfg, targetDir, targetSystems, dryRun, force)
+		result, err = syncMCPs(lf, cfg, targetDir, targetSystems, dryRun, force, d)
+	case asset.KindAgent, asset.KindCommand, asset.KindRule:
+		result, err = syncFileAssets(kind, lf, cfg, overrides, mirrors, targetDir, targetSystems, dryRun, reinstall)
 	default:
-		return &assetSyncResult{}, nil
+		result = &assetSyncResult{}
+	}
+	if err == nil && !dryRun {
+		// With --tag, only the tagged entries were tried.
+		saveFailures(d, targetDir, failuresSync, func(k asset.Kind, name string) bool {
+			return k == kind && (tag == "" || core.FindLockedAsset(lf, kind, name) != nil)
+		}, result.failures)
 	}
+	return result, err
 }
 
 func syncSkills(
 	lf *core.LockFile,
 	cfg *core.Config,
+	overrides map[string]string,
+	mirrors core.RegistryMirrors,
 	targetDir string,
 	targetSystems []system.System,
-	dryRun, force bool,
+	dryRun, reinstall, overwriteModified bool,
 ) (*assetSyncResult, error) {
 	res := &assetSyncResult{}
 
@@ -776,9 +1334,14 @@ func syncSkills(
 	orch := core.NewOrchestrator()
 
 	for _, skill := range lockedSkills {
+		if skipForPlatform(skill, dryRun) {
+			res.skipped++
+			continue
+		}
+
 		// Check if skill directory already exists.
 		skillDir := filepath.Join(targetDir, ".agents", "skills", skill.Name)
-		if !force {
+		if !reinstall {
 			if _, statErr := os.Stat(skillDir); statErr == nil {
 				res.skipped++
 				if dryRun {
@@ -789,7 +1352,7 @@ func syncSkills(
 		}
 
 		if dryRun {
-			fmt.Fprintf(os.Stdout, "install: %s (commit %s)\n", skill.Name, core.TruncateCommit(skill.Commit))
+			fmt.Fprintf(os.Stdout, "install: %s (commit %s)%s\n", skill.Name, core.TruncateCommit(skill.Commit), platformLabel(skill))
 			res.installed++
 			continue
 		}
@@ -797,7 +1360,7 @@ func syncSkills(
 		host, owner, repo, subPath, parseErr := core.ParseLockSource(skill.Source)
 		if parseErr != nil {
 			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", skill.Name, parseErr)
-			res.errors++
+			res.fail(asset.KindSkill, skill.Name, core.FailureInvalidSource, parseErr)
 			continue
 		}
 
@@ -810,21 +1373,44 @@ func syncSkills(
 			CloneURL: cloneURL,
 			SubPath:  subPath,
 		}
-		psource.ApplyCloneURLOverride(cfg.Settings.CloneURLOverrides)
+		psource.ApplyMirror(overrides, mirrors.Locked(skill))
 
 		_, installErr := orch.InstallFromSource(psource, asset.KindSkill, core.OrchestratorInstallOptions{
-			TargetDir:     targetDir,
-			TargetSystems: targetSystems,
-			NameFilter:    skill.Name,
-			Commit:        skill.Commit,
+			TargetDir:         targetDir,
+			TargetSystems:     targetSystems,
+			NameFilter:        core.LockedUpstreamName(skill),
+			Commit:            skill.Commit,
+			IgnorePatterns:    cfg.Settings.IgnorePatterns,
+			CloneURLOverrides: overrides,
+			Mirrors:           mirrors,
+			NoValidate:        true, // already accepted into the lock
+			LegacyNames:       true,
+			Alias:             lockedAlias(skill),
+			Lock:              lf,
+			Reinstall:         reinstall,
+			OverwriteModified: overwriteModified,
+			ConfirmOverwrite:  confirmOverwriteModified,
 		})
 		if installErr != nil {
-			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", skill.Name, installErr)
-			res.errors++
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", skill.Name, withConflictHint(installErr))
+			res.fail(asset.KindSkill, skill.Name, "", installErr)
 			continue
 		}
 
-		fmt.Fprintf(os.Stdout, "Installed: %s\n", skill.Name)
+		// Re-apply paths that were updated ahead of the locked commit.
+		if p := core.LockedPartial(skill); p != nil {
+			_, _, partialErr := orch.UpdatePaths(psource, skill, p.Commit, p.Paths, core.OrchestratorInstallOptions{
+				TargetDir:      targetDir,
+				IgnorePatterns: cfg.Settings.IgnorePatterns,
+			})
+			if partialErr != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s: updating %s: %v\n", skill.Name, strings.Join(p.Paths, ", "), partialErr)
+				res.fail(asset.KindSkill, skill.Name, "", partialErr)
+				continue
+			}
+		}
+
+		fmt.Fprintf(os.Stdout, "Installed: %s%s\n", skill.Name, originLabel(lf, asset.KindSkill, skill.Name))
 		res.installed++
 	}
 
@@ -851,22 +1437,23 @@ func syncMCPs(
 	rm := core.NewRegistryManager(d.config.RegistriesDir())
 
 	for _, lockedMCP := range lockedMCPs {
-		mcpInfo, findErr := rm.FindMCP(cfg.Registries, lockedMCP.Name, "")
+		if skipForPlatform(lockedMCP, dryRun) {
+			result.skipped++
+			continue
+		}
+
+		// Look the MCP up in the registry it was installed from, so that
+		// same-named MCPs from other registries don't make it ambiguous.
+		lockedRegistry, _ := lockedMCP.Data["registry"].(string)
+		mcpInfo, findErr := rm.FindMCP(cfg.Registries, core.LockedUpstreamName(lockedMCP), lockedRegistry)
 		if findErr != nil {
 			fmt.Fprintf(os.Stderr, "! MCP %q: registry %q not configured\n", lockedMCP.Name, lockedMCP.Data["registry"])
 			fmt.Fprintf(os.Stderr, "  Run: duckrow registry add <url>\n")
-			result.errors++
+			result.fail(asset.KindMCP, lockedMCP.Name, core.FailureNoRegistry, findErr)
 			continue
 		}
 
-		if dryRun {
-			fmt.Fprintf(os.Stdout, "install: %s (from %s)\n", lockedMCP.Name, mcpInfo.RegistryName)
-			result.installed++
-			for _, v := range lockedRequiredEnv(lockedMCP) {
-				result.requiredEnv[v] = append(result.requiredEnv[v], lockedMCP.Name)
-			}
-			continue
-		}
+		overrides := core.LockedMCPOverrides(lockedMCP)
 
 		// Determine systems for this MCP.
 		systems := targetSystems
@@ -883,14 +1470,32 @@ func syncMCPs(
 		// Build asset and install.
 		meta, ok := mcpInfo.MCP.Meta.(asset.MCPMeta)
 		if !ok {
-			result.errors++
+			result.fail(asset.KindMCP, lockedMCP.Name, "", fmt.Errorf("invalid MCP metadata in registry %s", mcpInfo.RegistryName))
+			continue
+		}
+		if err := core.CheckMCPHost(meta); err != nil {
+			result.fail(asset.KindMCP, lockedMCP.Name, "", err)
 			continue
 		}
 		a := asset.Asset{
 			Kind:        asset.KindMCP,
-			Name:        mcpInfo.MCP.Name,
+			Name:        lockedMCP.Name,
 			Description: mcpInfo.MCP.Description,
-			Meta:        meta,
+			Meta:        overrides.Apply(meta),
+		}
+
+		if dryRun {
+			fmt.Fprintf(os.Stdout, "install: %s (from %s)%s%s\n", lockedMCP.Name, mcpInfo.RegistryName, platformLabel(lockedMCP), overridesLabel(overrides))
+			for _, sys := range systems {
+				if sys.Supports(asset.KindMCP) {
+					_ = previewMCPInstall(sys, a, targetDir, force)
+				}
+			}
+			result.installed++
+			for _, v := range core.LockedRequiredEnv(lockedMCP) {
+				result.requiredEnv[v] = append(result.requiredEnv[v], lockedMCP.Name)
+			}
+			continue
 		}
 
 		wrote := false
@@ -907,13 +1512,13 @@ func syncMCPs(
 		}
 
 		if wrote {
-			fmt.Fprintf(os.Stdout, "Installed: %s\n", lockedMCP.Name)
+			fmt.Fprintf(os.Stdout, "Installed: %s%s%s\n", lockedMCP.Name, originLabel(lf, asset.KindMCP, lockedMCP.Name), overridesLabel(overrides))
 			result.installed++
 		} else {
 			result.skipped++
 		}
 
-		for _, v := range lockedRequiredEnv(lockedMCP) {
+		for _, v := range core.LockedRequiredEnv(lockedMCP) {
 			result.requiredEnv[v] = append(result.requiredEnv[v], lockedMCP.Name)
 		}
 	}
@@ -937,8 +1542,9 @@ func runAssetOutdated(cmd *cobra.Command, kind asset.Kind) error {
 	}
 
 	jsonOutput, _ := cmd.Flags().GetBool("json")
+	showChangelog, _ := cmd.Flags().GetBool("changelog")
 
-	lf, err := core.ReadLockFile(targetDir)
+	lf, err := core.ReadLayeredLockFile(targetDir)
 	if err != nil {
 		return fmt.Errorf("reading lock file: %w", err)
 	}
@@ -962,12 +1568,21 @@ func runAssetOutdated(cmd *cobra.Command, kind asset.Kind) error {
 	}
 
 	rm := core.NewRegistryManager(d.config.RegistriesDir())
-	rm.HydrateRegistryCommits(cfg.Registries, cfg.Settings.CloneURLOverrides)
+	hydrateForUpdates(rm, cfg)
 	registryCommits := core.BuildRegistryCommitMap(cfg.Registries, rm)
+	overrides := cfg.Settings.CloneURLOverrides
+	mirrors := rm.Mirrors(cfg.Registries)
 
-	updates, err := core.CheckForUpdates(lf, kind, cfg.Settings.CloneURLOverrides, registryCommits)
-	if err != nil {
-		return fmt.Errorf("checking for updates: %w", err)
+	updates := checkForUpdates(lf, kind, overrides, mirrors, registryCommits)
+
+	if showChangelog {
+		reader := core.NewChangelogReader(overrides, mirrors)
+		defer reader.Close()
+		for i, u := range updates {
+			if u.HasUpdate {
+				updates[i].Changelog = readChangelog(reader, lf, kind, u)
+			}
+		}
 	}
 
 	if jsonOutput {
@@ -980,23 +1595,48 @@ func runAssetOutdated(cmd *cobra.Command, kind asset.Kind) error {
 	}
 
 	header := strings.ToUpper(lower[:1]) + lower[1:]
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	fmt.Fprintf(w, "%s\tInstalled\tAvailable\tSource\n", header)
+	t := newTable(os.Stdout, header, "Installed", "Available", "Source")
 
 	for _, u := range updates {
-		installed := core.TruncateCommit(u.InstalledCommit)
+		installed := versionOrCommit(u.InstalledVersion, u.InstalledCommit)
 		available := "(up to date)"
-		if u.HasUpdate {
-			available = core.TruncateCommit(u.AvailableCommit)
+		if u.Error != "" {
+			available = "(check failed)"
+		} else if u.HasUpdate {
+			available = versionOrCommit(u.AvailableVersion, u.AvailableCommit)
+			if u.UpdateLevel != "" {
+				available += " (" + u.UpdateLevel + ")"
+			}
+		}
+		// A newer version the lock entry's constraint rules out.
+		if u.LatestVersion != "" && u.LatestVersion != u.AvailableVersion {
+			available += "; latest " + u.LatestVersion
 		}
 		source := truncateSource(u.Source)
-		fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", u.Name, installed, available, source)
+		t.row(u.Name, installed, available, source)
 	}
 
-	_ = w.Flush()
+	_ = t.flush()
+
+	for _, u := range updates {
+		if u.Changelog != nil {
+			fmt.Fprintf(os.Stdout, "\n%s %s -> %s\n", u.Name,
+				versionOrCommit(u.InstalledVersion, u.InstalledCommit), versionOrCommit(u.AvailableVersion, u.AvailableCommit))
+			printChangelog(os.Stdout, u.Changelog)
+		}
+	}
 	return nil
 }
 
+// versionOrCommit shows a skill installed by version as its version and
+// anything else as its short commit.
+func versionOrCommit(version, commit string) string {
+	if version != "" {
+		return version
+	}
+	return core.TruncateCommit(commit)
+}
+
 // ---------------------------------------------------------------------------
 // runAssetUpdate — update assets to the available commit
 // ---------------------------------------------------------------------------
@@ -1011,8 +1651,16 @@ func runAssetUpdate(cmd *cobra.Command, args []string, kind asset.Kind) error {
 
 	all, _ := cmd.Flags().GetBool("all")
 	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	noChangelog, _ := cmd.Flags().GetBool("no-changelog")
+	retry, _ := cmd.Flags().GetBool("retry-failed")
+	jsonOutput, _ := cmd.Flags().GetBool("json")
+	noValidate, _ := cmd.Flags().GetBool("no-validate")
+	overwriteModified, _ := cmd.Flags().GetBool("overwrite-modified")
 
-	if len(args) == 0 && !all {
+	if retry && (all || len(args) > 0) {
+		return fmt.Errorf("--retry-failed updates the %ss the last update failed on; don't name one or use --all", lower)
+	}
+	if len(args) == 0 && !all && !retry {
 		article := "a"
 		if strings.HasPrefix(lower, "a") || strings.HasPrefix(lower, "e") ||
 			strings.HasPrefix(lower, "i") || strings.HasPrefix(lower, "o") ||
@@ -1023,24 +1671,28 @@ func runAssetUpdate(cmd *cobra.Command, args []string, kind asset.Kind) error {
 			article, lower, lower, lower, lower)
 	}
 
+	paths, _ := cmd.Flags().GetStringSlice("paths")
+	if len(paths) > 0 {
+		if all || retry {
+			return fmt.Errorf("--paths updates a single %s; name it instead of using --all", lower)
+		}
+		var err error
+		if paths, err = core.CleanPartialPaths(paths); err != nil {
+			return fmt.Errorf("--paths: %w", err)
+		}
+	}
+
 	targetSystems, err := resolveTargetSystems(cmd)
 	if err != nil {
 		return err
 	}
 
-	// Agents are rendered per-system and include non-universal systems
-	// (e.g. Claude Code). Ensure all agent-capable systems are targeted
-	// so updates are written everywhere, not just universal systems.
-	if kind == asset.KindAgent && targetSystems == nil {
-		targetSystems = filterAgentCapable(system.All())
-	}
-
 	targetDir, err := resolveTargetDir(cmd)
 	if err != nil {
 		return err
 	}
 
-	lf, err := core.ReadLockFile(targetDir)
+	lf, err := core.ReadLayeredLockFile(targetDir)
 	if err != nil {
 		return fmt.Errorf("reading lock file: %w", err)
 	}
@@ -1054,14 +1706,35 @@ func runAssetUpdate(cmd *cobra.Command, args []string, kind asset.Kind) error {
 	}
 
 	rm := core.NewRegistryManager(d.config.RegistriesDir())
-	rm.HydrateRegistryCommits(cfg.Registries, cfg.Settings.CloneURLOverrides)
+	hydrateForUpdates(rm, cfg)
 	registryCommits := core.BuildRegistryCommitMap(cfg.Registries, rm)
+	overrides := cfg.Settings.CloneURLOverrides
+	mirrors := rm.Mirrors(cfg.Registries)
+
+	stdout, restore := stdoutForJSON(jsonOutput)
+	defer restore()
 
 	// Determine which assets to check.
 	var assetsToCheck *core.LockFile
-	if all {
+	switch {
+	case all:
 		assetsToCheck = lf
-	} else {
+	case retry:
+		var items []core.FailedItem
+		for _, f := range d.config.Failures(targetDir, failuresUpdate) {
+			if f.Kind == kind {
+				items = append(items, f)
+			}
+		}
+		if len(items) == 0 {
+			if jsonOutput {
+				return writeSummaryJSON(stdout, updateJSON{Failed: []core.FailedItem{}})
+			}
+			fmt.Fprintf(os.Stdout, "Nothing to retry: the last %s update had no failures.\n", lower)
+			return nil
+		}
+		assetsToCheck = lf.WithFailed(items)
+	default:
 		name := args[0]
 		found := core.FindLockedAsset(lf, kind, name)
 		if found == nil {
@@ -1070,15 +1743,33 @@ func runAssetUpdate(cmd *cobra.Command, args []string, kind asset.Kind) error {
 		assetsToCheck = &core.LockFile{Assets: []asset.LockedAsset{*found}}
 	}
 
-	updates, err := core.CheckForUpdates(assetsToCheck, kind, cfg.Settings.CloneURLOverrides, registryCommits)
-	if err != nil {
-		return fmt.Errorf("checking for updates: %w", err)
+	updates := checkForUpdates(assetsToCheck, kind, overrides, mirrors, registryCommits)
+
+	// Show what each update pulls in, read before the asset is replaced.
+	var changelogs *core.ChangelogReader
+	if !noChangelog {
+		changelogs = core.NewChangelogReader(overrides, mirrors)
+		defer changelogs.Close()
 	}
 
 	orch := core.NewOrchestrator()
 	var updated, skipped, errors int
+	failed := []core.FailedItem{}
+	fail := func(name, class string, err error) {
+		errors++
+		failed = append(failed, core.NewFailedItem(kind, name, class, err))
+	}
 
 	for _, u := range updates {
+		if u.Error != "" {
+			fmt.Fprintf(os.Stderr, "Error: %s: checking for updates: %s\n", u.Name, u.Error)
+			class := u.ErrorClass
+			if class == "" {
+				class = core.FailureCheck
+			}
+			fail(u.Name, class, fmt.Errorf("checking for updates: %s", u.Error))
+			continue
+		}
 		if !u.HasUpdate {
 			skipped++
 			if dryRun {
@@ -1087,9 +1778,17 @@ func runAssetUpdate(cmd *cobra.Command, args []string, kind asset.Kind) error {
 			continue
 		}
 
+		changelog := readChangelog(changelogs, lf, kind, u)
+
 		if dryRun {
-			fmt.Fprintf(os.Stdout, "update: %s %s -> %s\n", u.Name,
-				core.TruncateCommit(u.InstalledCommit), core.TruncateCommit(u.AvailableCommit))
+			if len(paths) > 0 {
+				fmt.Fprintf(os.Stdout, "update: %s %s %s -> %s\n", u.Name, strings.Join(paths, ", "),
+					core.TruncateCommit(u.InstalledCommit), core.TruncateCommit(u.AvailableCommit))
+			} else {
+				fmt.Fprintf(os.Stdout, "update: %s %s -> %s\n", u.Name,
+					versionOrCommit(u.InstalledVersion, u.InstalledCommit), versionOrCommit(u.AvailableVersion, u.AvailableCommit))
+			}
+			printChangelog(os.Stdout, changelog)
 			updated++
 			continue
 		}
@@ -1098,14 +1797,14 @@ func runAssetUpdate(cmd *cobra.Command, args []string, kind asset.Kind) error {
 		lockEntry := core.FindLockedAsset(lf, kind, u.Name)
 		if lockEntry == nil {
 			fmt.Fprintf(os.Stderr, "Error: %s: lock entry not found\n", u.Name)
-			errors++
+			fail(u.Name, core.FailureNoLockEntry, fmt.Errorf("lock entry not found"))
 			continue
 		}
 
 		host, owner, repo, subPath, parseErr := core.ParseLockSource(u.Source)
 		if parseErr != nil {
 			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", u.Name, parseErr)
-			errors++
+			fail(u.Name, core.FailureInvalidSource, parseErr)
 			continue
 		}
 
@@ -1119,27 +1818,70 @@ func runAssetUpdate(cmd *cobra.Command, args []string, kind asset.Kind) error {
 			SubPath:  subPath,
 			Ref:      lockEntry.Ref,
 		}
-		psource.ApplyCloneURLOverride(cfg.Settings.CloneURLOverrides)
+		psource.ApplyMirror(overrides, mirrors.Locked(*lockEntry))
 
-		// Remove existing.
-		if err := orch.RemoveAsset(kind, u.Name, targetDir, nil); err != nil {
-			fmt.Fprintf(os.Stderr, "Error: %s: removing: %v\n", u.Name, err)
-			errors++
+		// Refresh only the chosen paths; the rest stays at the locked commit.
+		if len(paths) > 0 {
+			entry, changed, err := orch.UpdatePaths(psource, *lockEntry, u.AvailableCommit, paths, core.OrchestratorInstallOptions{
+				TargetDir:      targetDir,
+				IgnorePatterns: cfg.Settings.IgnorePatterns,
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %s: %v\n", u.Name, err)
+				fail(u.Name, "", err)
+				continue
+			}
+			local := lf.Origin(kind, entry.Name) == core.OriginLocal
+			if _, lockErr := writeLockEntry(targetDir, entry, local); lockErr != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to update lock file: %v\n", lockErr)
+			} else {
+				printAttestation(d, cfg, targetDir, entry)
+			}
+			fmt.Fprintf(os.Stdout, "Updated: %s %s %s -> %s\n", entry.Name, strings.Join(paths, ", "),
+				core.TruncateCommit(u.InstalledCommit), core.TruncateCommit(u.AvailableCommit))
+			for _, f := range changed {
+				fmt.Fprintf(os.Stdout, "  %s\n", f)
+			}
+			fmt.Fprintf(os.Stdout, "Other files stay at %s.\n", core.TruncateCommit(entry.Commit))
+			printChangelog(os.Stdout, changelog)
+			updated++
 			continue
 		}
 
-		// Reinstall at available commit.
-		installOpts := core.OrchestratorInstallOptions{
-			TargetDir:     targetDir,
-			TargetSystems: targetSystems,
-			NameFilter:    u.Name,
-			Commit:        u.AvailableCommit,
+		// Agents, commands, and rules are rendered per system. Without
+		// --systems, render the update again for each system the asset is
+		// installed for, not just the universal ones.
+		systems := targetSystems
+		if systems == nil && asset.IsSystemFile(kind) {
+			systems = installedSystems(targetDir, kind, u.Name)
 		}
 
-		results, installErr := orch.InstallFromSource(psource, kind, installOpts)
+		// Install the available commit over the installed one.
+		installOpts := core.OrchestratorInstallOptions{
+			TargetDir:      targetDir,
+			TargetSystems:  systems,
+			NameFilter:     core.LockedUpstreamName(*lockEntry),
+			Commit:         u.AvailableCommit,
+			IgnorePatterns: cfg.Settings.IgnorePatterns,
+			Alias:          lockedAlias(*lockEntry),
+			Namespace:      core.LockedNamespace(*lockEntry),
+			LegacyNames:    true,
+			NoValidate:     noValidate,
+
+			Version:           u.AvailableVersion,
+			VersionConstraint: core.LockedVersionConstraint(*lockEntry),
+
+			CloneURLOverrides: overrides,
+			Mirrors:           mirrors,
+			OverwriteModified: overwriteModified,
+			ConfirmOverwrite:  confirmOverwriteModified,
+		}
+
+		results, installErr := orch.UpdateAsset(psource, kind, *lockEntry, installOpts)
 		if installErr != nil {
+			installErr = withUpdateHint(installErr)
 			fmt.Fprintf(os.Stderr, "Error: %s: installing: %v\n", u.Name, installErr)
-			errors++
+			fail(u.Name, "", fmt.Errorf("installing: %w", installErr))
 			continue
 		}
 
@@ -1148,28 +1890,70 @@ func runAssetUpdate(cmd *cobra.Command, args []string, kind asset.Kind) error {
 			if src == "" {
 				src = core.NormalizeSource(psource.Host, psource.Owner, psource.Repo, "")
 			}
-			entry := asset.LockedAsset{
-				Kind:   kind,
-				Name:   r.Asset.Name,
-				Source: src,
-				Commit: r.Commit,
-				Ref:    r.Ref,
-			}
-			if lockErr := core.AddOrUpdateAsset(targetDir, entry); lockErr != nil {
+			r.Asset.Source = src
+			entry := r.LockEntry(lockEntry.Platforms, lockEntry.Tags)
+			local := lf.Origin(kind, r.Asset.Name) == core.OriginLocal
+			if _, lockErr := writeLockEntry(targetDir, entry, local); lockErr != nil {
 				fmt.Fprintf(os.Stderr, "Warning: failed to update lock file: %v\n", lockErr)
+			} else {
+				printAttestation(d, cfg, targetDir, entry)
 			}
 			fmt.Fprintf(os.Stdout, "Updated: %s %s -> %s\n", r.Asset.Name,
-				core.TruncateCommit(u.InstalledCommit), core.TruncateCommit(r.Commit))
+				versionOrCommit(u.InstalledVersion, u.InstalledCommit), versionOrCommit(r.Version, r.Commit))
 		}
+		printChangelog(os.Stdout, changelog)
 		updated++
 	}
 
-	fmt.Fprintf(os.Stdout, "\nUpdate: %d updated, %d up-to-date, %d errors\n", updated, skipped, errors)
+	bulk := all || retry
+	if bulk && !dryRun {
+		// Every locked asset of the kind, or every failed one, was tried.
+		saveFailures(d, targetDir, failuresUpdate, func(k asset.Kind, name string) bool {
+			return k == kind
+		}, failed)
+	}
+	if bulk {
+		notifyDone(cmd, targetDir, fmt.Sprintf("%d updated, %d up-to-date, %d errors", updated, skipped, errors), errors > 0)
+	}
 
+	var errUpdate error
 	if errors > 0 {
-		return fmt.Errorf("%d %s(s) failed to update", errors, lower)
+		errUpdate = fmt.Errorf("%d %s(s) failed to update", errors, lower)
 	}
-	return nil
+	if jsonOutput {
+		if err := writeSummaryJSON(stdout, updateJSON{Updated: updated, UpToDate: skipped, Errors: errors, Failed: failed}); err != nil {
+			return err
+		}
+		return errUpdate
+	}
+
+	fmt.Fprintf(os.Stdout, "\nUpdate: %d updated, %d up-to-date, %d errors\n", updated, skipped, errors)
+	if bulk && !dryRun {
+		printRetryHint(failed, fmt.Sprintf("duckrow %s update", lower))
+	}
+	return errUpdate
+}
+
+// withUpdateHint adds the way past a failed SKILL.md validation, or local
+// changes that would be overwritten, to an update error.
+func withUpdateHint(err error) error {
+	var invalidErr *core.InvalidSkillError
+	if errors.As(err, &invalidErr) {
+		return fmt.Errorf("%w\nfix the SKILL.md in the source, or re-run with --no-validate to update anyway", err)
+	}
+	var modifiedErr *core.ModifiedSkillError
+	if errors.As(err, &modifiedErr) {
+		return fmt.Errorf("%w; re-run with --overwrite-modified to discard the changes", err)
+	}
+	return err
+}
+
+// updateJSON is the summary update --json prints.
+type updateJSON struct {
+	Updated  int               `json:"updated"`
+	UpToDate int               `json:"upToDate"`
+	Errors   int               `json:"errors"`
+	Failed   []core.FailedItem `json:"failed"`
 }
 
 // ---------------------------------------------------------------------------
@@ -1196,12 +1980,15 @@ func printRequiredEnvSummary(envMap map[string][]string) {
 }
 
 // ---------------------------------------------------------------------------
-// Agent install / uninstall / list / sync
+// Agent and command install / uninstall / list / sync
 // ---------------------------------------------------------------------------
 
-// installAgent handles agent-specific install logic.
-// Agents can be installed from a direct git URL or by name from a registry.
-func installAgent(
+// installFileAsset handles install logic for agents, commands, and rules,
+// which are written as a single file into each capable system's own
+// directory.
+// They can be installed from a direct git URL or by name from a registry.
+func installFileAsset(
+	kind asset.Kind,
 	orch *core.Orchestrator,
 	cfg *core.Config,
 	arg string,
@@ -1209,15 +1996,25 @@ func installAgent(
 	registryFilter string,
 	targetDir string,
 	targetSystems []system.System,
-	noLock, force bool,
+	noLock, local, force, reinstall, jsonOutput bool,
+	alias string,
 	d *deps,
 ) error {
+	handler, _ := asset.Get(kind)
+	display := handler.DisplayName()
+	lower := strings.ToLower(display)
+
 	var source *core.ParsedSource
 	var registryCommit string
-	var agentFilter string
+	var nameFilter string
 	var registryName string
+	var postInstall string
+	var platforms []string
+	var tags []string
+	var mirror string
 	var err error
 
+	rm := core.NewRegistryManager(d.config.RegistriesDir())
 	if isURL {
 		if registryFilter != "" {
 			return fmt.Errorf("--registry cannot be used with a direct URL source")
@@ -1227,69 +2024,80 @@ func installAgent(
 			return fmt.Errorf("invalid source: %w", err)
 		}
 	} else {
-		rm := core.NewRegistryManager(d.config.RegistriesDir())
-		entry, regName, findErr := rm.FindAsset(cfg.Registries, asset.KindAgent, arg)
+		registries := cfg.Registries
+		if registryFilter != "" {
+			registries = nil
+			for _, r := range cfg.Registries {
+				if r.Matches(registryFilter) {
+					registries = append(registries, r)
+				}
+			}
+		}
+		entry, regName, findErr := rm.FindAsset(registries, kind, arg)
 		if findErr != nil {
 			return findErr
 		}
+		if err := core.CheckPlatform(kind, *entry); err != nil {
+			return err
+		}
 		source, err = core.ParseSource(entry.Source)
 		if err != nil {
-			return fmt.Errorf("invalid agent source in registry: %w", err)
+			return fmt.Errorf("invalid %s source in registry: %w", lower, err)
 		}
-		agentFilter = entry.Name
+		nameFilter = entry.Name
 		registryCommit = entry.Commit
 		registryName = regName
+		postInstall = entry.PostInstallMessage
+		platforms = entry.Platforms
+		tags = entry.Tags
+		mirror = entry.CloneURL
 	}
 
-	source.ApplyCloneURLOverride(cfg.Settings.CloneURLOverrides)
+	source.ApplyMirror(cfg.Settings.CloneURLOverrides, mirror)
 
-	// Resolve target systems for agents.
+	// Resolve target systems.
 	if targetSystems == nil {
-		// Default: all agent-capable systems detected in the folder.
+		// Default: all capable systems detected in the folder.
 		detected := system.DetectInFolder(targetDir)
-		targetSystems = filterAgentCapable(detected)
+		targetSystems = filterCapable(detected, kind)
 		if len(targetSystems) == 0 {
-			// Fall back to all agent-capable systems.
-			targetSystems = filterAgentCapable(system.All())
+			// Fall back to all capable systems.
+			targetSystems = filterCapable(system.All(), kind)
 		}
 	} else {
-		targetSystems = filterAgentCapable(targetSystems)
+		targetSystems = filterCapable(targetSystems, kind)
 		if len(targetSystems) == 0 {
-			return fmt.Errorf("none of the specified systems support agents")
+			return fmt.Errorf("none of the specified systems support %ss", lower)
 		}
 	}
 
-	if registryName != "" {
-		fmt.Fprintf(os.Stdout, "Installing agent %q from registry %q...\n\n", arg, registryName)
+	if registryName != "" && !jsonOutput {
+		fmt.Fprintf(os.Stdout, "Installing %s %q from registry %q...\n\n", lower, arg, registryName)
 	}
 
-	results, err := orch.InstallFromSource(source, asset.KindAgent, core.OrchestratorInstallOptions{
-		TargetDir:     targetDir,
-		TargetSystems: targetSystems,
-		NameFilter:    agentFilter,
-		Commit:        registryCommit,
-		Force:         force,
+	// Read existing lock for conflict checks and source-change warnings.
+	existingLock, _ := core.ReadLayeredLockFile(targetDir)
+
+	results, err := orch.InstallFromSource(source, kind, core.OrchestratorInstallOptions{
+		TargetDir:       targetDir,
+		TargetSystems:   targetSystems,
+		NameFilter:      nameFilter,
+		Commit:          registryCommit,
+		Force:           force,
+		Reinstall:       reinstall,
+		Alias:           alias,
+		Lock:            existingLock,
+		ResolveConflict: promptAlias,
 	})
 	if err != nil {
-		return err
-	}
-
-	// Read existing lock for source-change warnings.
-	var existingLock *core.LockFile
-	if !noLock {
-		existingLock, _ = core.ReadLockFile(targetDir)
+		return withConflictHint(err)
 	}
 
-	fmt.Fprintln(os.Stdout, "Wrote agent files to:")
+	report := core.InstallReport{Kind: kind, Registry: registryName, PostInstallMessage: postInstall}
 	for _, r := range results {
-		for _, sysName := range r.Systems {
-			sys, ok := system.ByName(sysName)
-			if !ok {
-				continue
-			}
-			agentDir := sys.AssetDir(asset.KindAgent, targetDir)
-			relPath := filepath.Join(agentDir, r.Asset.Name+".md")
-			fmt.Fprintf(os.Stdout, "  + %-40s (%s)\n", relPath, sys.DisplayName())
+		report.Assets = append(report.Assets, core.NewInstallReportAsset(r, targetDir))
+		if r.Unchanged {
+			continue
 		}
 
 		if !noLock && r.Commit != "" {
@@ -1298,57 +2106,52 @@ func installAgent(
 				src = core.NormalizeSource(source.Host, source.Owner, source.Repo, "")
 			}
 
-			// Warn if source changed.
+			// Warn if source changed (only reachable with --force).
 			if existingLock != nil {
-				for _, existing := range core.AssetsByKind(existingLock, asset.KindAgent) {
+				for _, existing := range core.AssetsByKind(existingLock, kind) {
 					if existing.Name == r.Asset.Name && existing.Source != src {
-						fmt.Fprintf(os.Stderr, "Warning: agent %q source changed from %q to %q\n",
-							r.Asset.Name, existing.Source, src)
+						report.Warn("%s %q source changed from %q to %q", lower, r.Asset.Name, existing.Source, src)
 					}
 				}
 			}
 
-			entry := asset.LockedAsset{
-				Kind:   asset.KindAgent,
-				Name:   r.Asset.Name,
-				Source: src,
-				Commit: r.Commit,
-				Ref:    r.Ref,
-			}
-			if lockErr := core.AddOrUpdateAsset(targetDir, entry); lockErr != nil {
-				fmt.Fprintf(os.Stderr, "Warning: failed to update lock file: %v\n", lockErr)
+			r.Asset.Source = src
+			entry := r.LockEntry(platforms, tags)
+			if lockName, lockErr := writeLockEntry(targetDir, entry, local); lockErr != nil {
+				report.Warn("failed to update lock file: %v", lockErr)
 			} else {
-				fmt.Fprintln(os.Stdout, "\nUpdated duckrow.lock.json")
+				report.LockFile = lockName
+				attestToReport(&report, d, cfg, targetDir, entry)
 			}
 		} else if !noLock && r.Commit == "" {
-			fmt.Fprintf(os.Stderr, "Warning: could not determine commit for %q; not pinned in lock file\n", r.Asset.Name)
+			report.Warn("could not determine commit for %q; not pinned in lock file", r.Asset.Name)
 		}
 	}
-
-	if len(results) == 1 {
-		fmt.Fprintf(os.Stdout, "\nAgent %q installed successfully.\n", results[0].Asset.Name)
-	}
-	return nil
+	return writeInstallReport(report, jsonOutput)
 }
 
-// uninstallAgent handles agent-specific uninstall logic.
-func uninstallAgent(orch *core.Orchestrator, targetDir string, args []string, all, noLock bool) error {
+// uninstallFileAsset handles uninstall logic for agents, commands, and rules.
+func uninstallFileAsset(kind asset.Kind, orch *core.Orchestrator, targetDir string, args []string, all, noLock bool) error {
+	handler, _ := asset.Get(kind)
+	display := handler.DisplayName()
+	lower := strings.ToLower(display)
+
 	if all {
-		// Scan to find all installed agents, then remove each.
+		// Scan to find everything installed, then remove each.
 		allInstalled, err := orch.ScanFolder(targetDir)
 		if err != nil {
 			return fmt.Errorf("scanning folder: %w", err)
 		}
-		agents := allInstalled[asset.KindAgent]
-		if len(agents) == 0 {
-			fmt.Fprintln(os.Stdout, "No agents installed.")
+		assets := allInstalled[kind]
+		if len(assets) == 0 {
+			fmt.Fprintf(os.Stdout, "No %ss installed.\n", lower)
 			return nil
 		}
 
-		// Deduplicate by name (agents appear per-system).
+		// Deduplicate by name (files appear per-system).
 		seen := make(map[string]bool)
 		var uniqueNames []string
-		for _, a := range agents {
+		for _, a := range assets {
 			if !seen[a.Name] {
 				seen[a.Name] = true
 				uniqueNames = append(uniqueNames, a.Name)
@@ -1356,16 +2159,16 @@ func uninstallAgent(orch *core.Orchestrator, targetDir string, args []string, al
 		}
 
 		for _, name := range uniqueNames {
-			if err := orch.RemoveAsset(asset.KindAgent, name, targetDir, nil); err != nil {
+			if err := orch.RemoveAsset(kind, name, targetDir, nil); err != nil {
 				return fmt.Errorf("removing %q: %w", name, err)
 			}
 			fmt.Fprintf(os.Stdout, "Removed: %s\n", name)
 		}
-		fmt.Fprintf(os.Stdout, "\nRemoved %d agent(s).\n", len(uniqueNames))
+		fmt.Fprintf(os.Stdout, "\nRemoved %d %s(s).\n", len(uniqueNames), lower)
 
 		if !noLock {
 			for _, name := range uniqueNames {
-				if lockErr := core.RemoveAssetEntry(targetDir, asset.KindAgent, name); lockErr != nil {
+				if lockErr := core.RemoveLayeredAssetEntry(targetDir, kind, name); lockErr != nil {
 					fmt.Fprintf(os.Stderr, "Warning: failed to update lock file: %v\n", lockErr)
 				}
 			}
@@ -1373,101 +2176,114 @@ func uninstallAgent(orch *core.Orchestrator, targetDir string, args []string, al
 		return nil
 	}
 
-	// Single agent uninstall.
+	// Single uninstall.
 	name := args[0]
 
-	// Verify the agent exists in at least one system before removing.
-	filename := name + ".md"
-	found := false
-	for _, sys := range system.Supporting(asset.KindAgent) {
-		agentDir := sys.AssetDir(asset.KindAgent, targetDir)
-		if agentDir == "" {
-			continue
-		}
-		agentPath := filepath.Join(agentDir, filename)
-		if _, statErr := os.Stat(agentPath); statErr == nil {
-			found = true
-			break
-		}
-	}
-	if !found {
-		return fmt.Errorf("agent %q not found in %s", name, targetDir)
+	// Verify the file exists in at least one system before removing. A
+	// disabled agent counts.
+	if len(core.SystemsWithAsset(kind, name, targetDir)) == 0 {
+		return fmt.Errorf("%s %q not found in %s", lower, name, targetDir)
 	}
 
-	fmt.Fprintf(os.Stdout, "Removing agent %q...\n\n", name)
+	fmt.Fprintf(os.Stdout, "Removing %s %q...\n\n", lower, name)
 
-	if err := orch.RemoveAsset(asset.KindAgent, name, targetDir, nil); err != nil {
+	if err := orch.RemoveAsset(kind, name, targetDir, nil); err != nil {
 		return err
 	}
 
-	// Show which systems the agent was removed from.
+	// Show which systems the file was removed from.
 	fmt.Fprintln(os.Stdout, "Removed from:")
-	for _, sys := range system.Supporting(asset.KindAgent) {
-		agentDir := sys.AssetDir(asset.KindAgent, targetDir)
-		relPath := filepath.Join(agentDir, name+".md")
+	for _, sys := range system.Supporting(kind) {
+		relPath := sys.AssetPath(kind, name, targetDir)
 		fmt.Fprintf(os.Stdout, "  - %-40s (%s)\n", relPath, sys.DisplayName())
 	}
 
 	if !noLock {
-		if lockErr := core.RemoveAssetEntry(targetDir, asset.KindAgent, name); lockErr != nil {
+		if lockErr := core.RemoveLayeredAssetEntry(targetDir, kind, name); lockErr != nil {
 			fmt.Fprintf(os.Stderr, "Warning: failed to update lock file: %v\n", lockErr)
 		} else {
 			fmt.Fprintln(os.Stdout, "\nUpdated duckrow.lock.json")
 		}
 	}
 
-	fmt.Fprintf(os.Stdout, "\nAgent %q removed.\n", name)
+	fmt.Fprintf(os.Stdout, "\n%s %q removed.\n", display, name)
 	return nil
 }
 
-// listAgents lists installed agents with their system associations.
-func listAgents(targetDir string, jsonOutput bool) error {
-	// Scan each agent-capable system individually to build system lists.
-	type agentInfo struct {
-		Name        string   `json:"name"`
-		Description string   `json:"description,omitempty"`
-		Systems     []string `json:"systems"`
+// listFileAssets lists installed agents, commands, or rules with their system
+// associations.
+func listFileAssets(kind asset.Kind, targetDir string, lf *core.LockFile, jsonOutput bool) error {
+	// Scan each capable system individually to build system lists.
+	type fileAssetInfo struct {
+		Name        string          `json:"name"`
+		Description string          `json:"description,omitempty"`
+		Systems     []string        `json:"systems"`
+		Disabled    []string        `json:"disabled,omitempty"` // agents only
+		Origin      core.LockOrigin `json:"origin"`
 	}
 
-	agentMap := make(map[string]*agentInfo) // name -> info
+	infoMap := make(map[string]*fileAssetInfo) // name -> info
 	var order []string
 
-	for _, sys := range system.Supporting(asset.KindAgent) {
-		installed, err := sys.Scan(asset.KindAgent, targetDir)
+	for _, sys := range system.Supporting(kind) {
+		installed, err := sys.Scan(kind, targetDir)
 		if err != nil {
 			continue
 		}
 		for _, a := range installed {
-			info, ok := agentMap[a.Name]
+			info, ok := infoMap[a.Name]
 			if !ok {
-				info = &agentInfo{
+				info = &fileAssetInfo{
 					Name:        a.Name,
 					Description: a.Description,
+					Origin:      lf.Origin(kind, a.Name),
 				}
-				agentMap[a.Name] = info
+				infoMap[a.Name] = info
 				order = append(order, a.Name)
 			}
 			info.Systems = append(info.Systems, sys.DisplayName())
 		}
 	}
 
-	if len(agentMap) == 0 {
+	// Disabled agents aren't found by the scan; list the locked ones too.
+	if kind == asset.KindAgent {
+		for _, locked := range core.AssetsByKind(lf, kind) {
+			disabled := core.DisabledSystems(locked.Name, targetDir)
+			if len(disabled) == 0 {
+				continue
+			}
+			info, ok := infoMap[locked.Name]
+			if !ok {
+				info = &fileAssetInfo{Name: locked.Name, Systems: []string{}, Origin: lf.Origin(kind, locked.Name)}
+				path := disabled[0].AssetPath(kind, locked.Name, targetDir) + system.DisabledSuffix
+				if data, err := asset.ParseAgentFile(path); err == nil {
+					info.Description, _ = data.Frontmatter["description"].(string)
+				}
+				infoMap[locked.Name] = info
+				order = append(order, locked.Name)
+			}
+			info.Disabled = system.DisplayNames(disabled)
+		}
+	}
+
+	if len(infoMap) == 0 {
 		if jsonOutput {
 			fmt.Fprintln(os.Stdout, "[]")
 		} else {
-			fmt.Fprintln(os.Stdout, "No agents installed.")
+			handler, _ := asset.Get(kind)
+			fmt.Fprintf(os.Stdout, "No %ss installed.\n", strings.ToLower(handler.DisplayName()))
 		}
 		return nil
 	}
 
 	// Build sorted list.
-	agents := make([]agentInfo, 0, len(order))
+	assets := make([]fileAssetInfo, 0, len(order))
 	for _, name := range order {
-		agents = append(agents, *agentMap[name])
+		assets = append(assets, *infoMap[name])
 	}
 
 	if jsonOutput {
-		data, err := json.MarshalIndent(agents, "", "  ")
+		data, err := json.MarshalIndent(assets, "", "  ")
 		if err != nil {
 			return fmt.Errorf("marshaling JSON: %w", err)
 		}
@@ -1475,52 +2291,67 @@ func listAgents(targetDir string, jsonOutput bool) error {
 		return nil
 	}
 
-	for _, a := range agents {
-		fmt.Fprintf(os.Stdout, "%-20s %-35s [%s]\n", a.Name, a.Description, joinStrings(a.Systems))
+	for _, a := range assets {
+		disabled := ""
+		if len(a.Disabled) > 0 {
+			disabled = fmt.Sprintf(" (disabled: %s)", joinStrings(a.Disabled))
+		}
+		fmt.Fprintf(os.Stdout, "%-20s %-35s [%s]%s%s\n", a.Name, a.Description, joinStrings(a.Systems), originLabel(lf, kind, a.Name), disabled)
 	}
 	return nil
 }
 
-// syncAgents restores agent files from the lock file.
-func syncAgents(
+// syncFileAssets restores agent, command, or rule files from the lock file.
+func syncFileAssets(
+	kind asset.Kind,
 	lf *core.LockFile,
 	cfg *core.Config,
+	overrides map[string]string,
+	mirrors core.RegistryMirrors,
 	targetDir string,
 	targetSystems []system.System,
-	dryRun, force bool,
+	dryRun, reinstall bool,
 ) (*assetSyncResult, error) {
 	res := &assetSyncResult{}
 
-	lockedAgents := core.AssetsByKind(lf, asset.KindAgent)
-	if len(lockedAgents) == 0 {
+	lockedAssets := core.AssetsByKind(lf, kind)
+	if len(lockedAssets) == 0 {
 		return res, nil
 	}
 
 	orch := core.NewOrchestrator()
 
-	// Resolve target systems for agents.
-	// Unlike skills, agents don't have a canonical location — they're rendered
-	// per-system. During sync we always target all agent-capable systems so
-	// that files are restored for every system, regardless of which system
-	// directories currently exist on disk.
+	// Resolve target systems.
+	// Unlike skills, agents, commands, and rules don't have a canonical
+	// location — they're written per-system. During sync we always target all capable
+	// systems so that files are restored for every system, regardless of
+	// which system directories currently exist on disk.
 	if targetSystems == nil {
-		targetSystems = filterAgentCapable(system.All())
+		targetSystems = filterCapable(system.All(), kind)
 	} else {
-		targetSystems = filterAgentCapable(targetSystems)
+		targetSystems = filterCapable(targetSystems, kind)
 	}
 
-	for _, agent := range lockedAgents {
-		// Check if agent file already exists in any target system.
-		if !force {
-			filename := agent.Name + ".md"
+	for _, locked := range lockedAssets {
+		if skipForPlatform(locked, dryRun) {
+			res.skipped++
+			continue
+		}
+
+		// Check if the file already exists in any target system, enabled
+		// or not.
+		if !reinstall {
 			exists := false
 			for _, sys := range targetSystems {
-				agentDir := sys.AssetDir(asset.KindAgent, targetDir)
-				if agentDir == "" {
+				path := sys.AssetPath(kind, locked.Name, targetDir)
+				if path == "" {
 					continue
 				}
-				agentPath := filepath.Join(agentDir, filename)
-				if _, statErr := os.Stat(agentPath); statErr == nil {
+				if _, statErr := os.Stat(path); statErr == nil {
+					exists = true
+					break
+				}
+				if _, statErr := os.Stat(path + system.DisabledSuffix); statErr == nil {
 					exists = true
 					break
 				}
@@ -1528,22 +2359,22 @@ func syncAgents(
 			if exists {
 				res.skipped++
 				if dryRun {
-					fmt.Fprintf(os.Stdout, "skip: %s (already installed)\n", agent.Name)
+					fmt.Fprintf(os.Stdout, "skip: %s (already installed)\n", locked.Name)
 				}
 				continue
 			}
 		}
 
 		if dryRun {
-			fmt.Fprintf(os.Stdout, "install: %s (commit %s)\n", agent.Name, core.TruncateCommit(agent.Commit))
+			fmt.Fprintf(os.Stdout, "install: %s (commit %s)%s\n", locked.Name, core.TruncateCommit(locked.Commit), platformLabel(locked))
 			res.installed++
 			continue
 		}
 
-		host, owner, repo, subPath, parseErr := core.ParseLockSource(agent.Source)
+		host, owner, repo, subPath, parseErr := core.ParseLockSource(locked.Source)
 		if parseErr != nil {
-			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", agent.Name, parseErr)
-			res.errors++
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", locked.Name, parseErr)
+			res.fail(kind, locked.Name, core.FailureInvalidSource, parseErr)
 			continue
 		}
 
@@ -1556,21 +2387,24 @@ func syncAgents(
 			CloneURL: cloneURL,
 			SubPath:  subPath,
 		}
-		psource.ApplyCloneURLOverride(cfg.Settings.CloneURLOverrides)
+		psource.ApplyMirror(overrides, mirrors.Locked(locked))
 
-		_, installErr := orch.InstallFromSource(psource, asset.KindAgent, core.OrchestratorInstallOptions{
+		_, installErr := orch.InstallFromSource(psource, kind, core.OrchestratorInstallOptions{
 			TargetDir:     targetDir,
 			TargetSystems: targetSystems,
-			NameFilter:    agent.Name,
-			Commit:        agent.Commit,
+			NameFilter:    core.LockedUpstreamName(locked),
+			Commit:        locked.Commit,
+			Alias:         lockedAlias(locked),
+			LegacyNames:   true,
+			Reinstall:     reinstall,
 		})
 		if installErr != nil {
-			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", agent.Name, installErr)
-			res.errors++
+			fmt.Fprintf(os.Stderr, "Error: %s: %v\n", locked.Name, installErr)
+			res.fail(kind, locked.Name, "", installErr)
 			continue
 		}
 
-		fmt.Fprintf(os.Stdout, "Installed: %s\n", agent.Name)
+		fmt.Fprintf(os.Stdout, "Installed: %s%s\n", locked.Name, originLabel(lf, kind, locked.Name))
 		res.installed++
 	}
 
@@ -1581,11 +2415,42 @@ func syncAgents(
 // Helpers
 // ---------------------------------------------------------------------------
 
-// filterAgentCapable returns only systems that support agents.
-func filterAgentCapable(systems []system.System) []system.System {
+// printPostInstallMessage prints the note a registry attaches to an asset
+// for after it is installed, if there is one.
+func printPostInstallMessage(msg string) {
+	msg = strings.TrimSpace(msg)
+	if msg == "" {
+		return
+	}
+	fmt.Fprintln(os.Stdout, "\nNote:")
+	for _, line := range strings.Split(msg, "\n") {
+		fmt.Fprintf(os.Stdout, "  %s\n", strings.TrimRight(line, " \t"))
+	}
+}
+
+// lockedAlias returns the installed name to pass as an alias when a lock
+// entry was installed under a name other than its upstream one.
+func lockedAlias(locked asset.LockedAsset) string {
+	if core.LockedAliasOf(locked) == "" {
+		return ""
+	}
+	return locked.Name
+}
+
+// installedSystems returns the systems an asset of kind is installed for in
+// targetDir, or every system supporting kind if it isn't found.
+func installedSystems(targetDir string, kind asset.Kind, name string) []system.System {
+	if systems := core.SystemsWithAsset(kind, name, targetDir); len(systems) > 0 {
+		return systems
+	}
+	return filterCapable(system.All(), kind)
+}
+
+// filterCapable returns only systems that support kind.
+func filterCapable(systems []system.System, kind asset.Kind) []system.System {
 	var result []system.System
 	for _, s := range systems {
-		if s.Supports(asset.KindAgent) {
+		if s.Supports(kind) {
 			result = append(result, s)
 		}
 	}
@@ -1618,6 +2483,18 @@ func deduplicateSystems(systems []system.System) []system.System {
 
 // resolveMCPConfigPathFromSystem returns the project-relative MCP config path
 // for a system, checking the alternative path first.
+// previewMCPInstall prints a unified diff of the change installing an MCP
+// would make to a system's config file, without writing it.
+func previewMCPInstall(sys system.System, a asset.Asset, targetDir string, force bool) error {
+	name := resolveMCPConfigPathFromSystem(sys, targetDir)
+	return sys.Install(a, targetDir, system.InstallOptions{
+		Force: force,
+		Preview: func(c system.ConfigChange) {
+			fmt.Fprint(os.Stdout, string(c.Diff(name)))
+		},
+	})
+}
+
 func resolveMCPConfigPathFromSystem(sys system.System, projectDir string) string {
 	type configPathResolver interface {
 		ResolveMCPConfigPathRel(projectDir string) string
@@ -0,0 +1,117 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/spf13/cobra"
+)
+
+var attestCmd = &cobra.Command{
+	Use:   "attest",
+	Short: "Verify provenance attestations of installed assets",
+	Long: `With the attest setting on, every install and update in a project writes a
+signed attestation to .duckrow/attestations: the installed files and their
+digests, the source and commit they came from, when, with which duckrow
+version, and on which machine. Attestations are in-toto statements in DSSE
+envelopes, signed with an Ed25519 key created in ~/.duckrow/attestation-key
+on first use.
+
+Commit .duckrow/attestations with the lock file, and hand
+~/.duckrow/attestation-key.pub to whoever audits the project.`,
+}
+
+var attestVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check attestations against the lock file and installed files",
+	Long: `Check every attestation in .duckrow/attestations:
+
+  - it is signed with the key (by default ~/.duckrow/attestation-key.pub,
+    or the public key given with --key)
+  - the attested files are installed and unchanged, and no files were
+    added to the asset
+  - the lock file still records the attested source and commit
+
+Assets in the lock file without an attestation are issues too.
+
+Exits with a non-zero status if any issue is found.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+		keyPath, _ := cmd.Flags().GetString("key")
+		if keyPath == "" {
+			d, err := newDeps()
+			if err != nil {
+				return err
+			}
+			keyPath = core.AttestationPublicKeyPath(d.config.ConfigDir())
+		}
+		pub, err := core.LoadAttestationPublicKey(keyPath)
+		if err != nil {
+			return err
+		}
+
+		verified, issues, err := core.VerifyAttestations(targetDir, pub)
+		if err != nil {
+			return err
+		}
+		if len(issues) == 0 {
+			fmt.Fprintf(os.Stdout, "%d attestation(s) verified.\n", verified)
+			return nil
+		}
+		fmt.Fprintln(os.Stderr, "duckrow: attestations do not match:")
+		for _, issue := range issues {
+			if issue.Kind == "" {
+				fmt.Fprintf(os.Stderr, "  - %s: %s\n", issue.Name, issue.Problem)
+				continue
+			}
+			fmt.Fprintf(os.Stderr, "  - %s\n", issue)
+		}
+		return fmt.Errorf("%d attestation issue(s) found", len(issues))
+	},
+}
+
+// attestInstalled attests an asset installed or updated (op) in targetDir
+// (see core.AttestInstalled).
+func attestInstalled(d *deps, cfg *core.Config, targetDir string, entry asset.LockedAsset, op string) (string, error) {
+	return core.AttestInstalled(cfg, targetDir, entry, core.AttestOptions{
+		ConfigDir: d.config.ConfigDir(),
+		Version:   Version,
+		Operation: op,
+	})
+}
+
+// attestToReport attests an installed asset, noting the attestation, or
+// why it could not be written, in report.
+func attestToReport(report *core.InstallReport, d *deps, cfg *core.Config, targetDir string, entry asset.LockedAsset) {
+	path, err := attestInstalled(d, cfg, targetDir, entry, "install")
+	if err != nil {
+		report.Warn("%v", err)
+	} else if path != "" {
+		report.Attestations = append(report.Attestations, path)
+	}
+}
+
+// printAttestation attests an updated asset, printing where the
+// attestation went or warning why it could not be written.
+func printAttestation(d *deps, cfg *core.Config, targetDir string, entry asset.LockedAsset) {
+	path, err := attestInstalled(d, cfg, targetDir, entry, "update")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+	} else if path != "" {
+		fmt.Fprintf(os.Stdout, "Attested: %s\n", path)
+	}
+}
+
+func init() {
+	attestVerifyCmd.Flags().StringP("dir", "d", "", "Project directory (default: current directory)")
+	attestVerifyCmd.Flags().String("key", "", "Public key to verify with (default: ~/.duckrow/attestation-key.pub)")
+
+	attestCmd.AddCommand(attestVerifyCmd)
+	rootCmd.AddCommand(attestCmd)
+}
@@ -0,0 +1,225 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+)
+
+// passphraseEnvVar supplies the backup passphrase without a prompt, e.g. in
+// provisioning scripts.
+const passphraseEnvVar = "DUCKROW_BACKUP_PASSPHRASE"
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up and restore global duckrow state",
+	Long: `Save the global duckrow state to a single file and restore it, e.g. to move
+to a new machine or recover from a corrupted ~/.duckrow.
+
+A backup holds the settings, the list of registries (not their clones),
+bookmarks, and the global ~/.duckrow/.env.duckrow, optionally encrypted with
+a passphrase.`,
+}
+
+// ---------------------------------------------------------------------------
+// backup create
+// ---------------------------------------------------------------------------
+
+var backupCreateCmd = &cobra.Command{
+	Use:   "create <file>",
+	Short: "Write the global state to a backup file",
+	Long: `Write settings, registries, bookmarks, and the global env to <file>.
+
+The global env usually holds secrets. Use --encrypt to protect it with a
+passphrase (prompted for, or read from DUCKROW_BACKUP_PASSPHRASE), or --no-env
+to leave it out. The file is created readable only by you.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		path := args[0]
+		force, _ := cmd.Flags().GetBool("force")
+		encrypt, _ := cmd.Flags().GetBool("encrypt")
+		noEnv, _ := cmd.Flags().GetBool("no-env")
+		if encrypt && noEnv {
+			return fmt.Errorf("--encrypt and --no-env cannot be used together")
+		}
+		if _, err := os.Stat(path); err == nil && !force {
+			return fmt.Errorf("%s already exists; use --force to overwrite it", path)
+		}
+
+		d, err := newDeps()
+		if err != nil {
+			return err
+		}
+
+		opts := core.BackupOptions{NoEnv: noEnv}
+		if encrypt {
+			opts.Passphrase, err = readPassphrase(true)
+			if err != nil {
+				return err
+			}
+		}
+
+		b, err := d.config.CreateBackup(opts)
+		if err != nil {
+			return err
+		}
+		if err := core.WriteBackup(path, b); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stdout, "Backup written to %s\n", path)
+		fmt.Fprintf(os.Stdout, "  %d registries, %d bookmarks, %s\n",
+			len(b.Registries), len(b.Bookmarks), describeBackupEnv(b))
+		return nil
+	},
+}
+
+// describeBackupEnv says whether and how a backup holds the global env.
+func describeBackupEnv(b *core.Backup) string {
+	switch {
+	case b.EncryptedEnv != nil:
+		return "global env (encrypted)"
+	case b.Env != "":
+		return "global env (not encrypted)"
+	default:
+		return "no global env"
+	}
+}
+
+// ---------------------------------------------------------------------------
+// backup restore
+// ---------------------------------------------------------------------------
+
+var backupRestoreCmd = &cobra.Command{
+	Use:   "restore <file>",
+	Short: "Restore the global state from a backup file",
+	Long: `Restore settings, registries, bookmarks, and the global env from a backup.
+
+Settings are replaced. Registries, bookmarks, and env vars are merged into
+what is already there; entries from the backup win. Registries that have no
+local clone are cloned again.
+
+Pass --settings, --registries, --bookmarks, or --env to restore only those
+parts. With none of them, everything in the backup is restored.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		b, err := core.ReadBackup(args[0])
+		if err != nil {
+			return err
+		}
+
+		opts := core.RestoreOptions{}
+		opts.Settings, _ = cmd.Flags().GetBool("settings")
+		opts.Registries, _ = cmd.Flags().GetBool("registries")
+		opts.Bookmarks, _ = cmd.Flags().GetBool("bookmarks")
+		opts.Env, _ = cmd.Flags().GetBool("env")
+		if opts.Env && !b.HasEnv() {
+			return fmt.Errorf("%s does not contain a global env", args[0])
+		}
+		if b.EncryptedEnv != nil && (opts.All() || opts.Env) {
+			opts.Passphrase, err = readPassphrase(false)
+			if err != nil {
+				return err
+			}
+		}
+
+		d, err := newDeps()
+		if err != nil {
+			return err
+		}
+		res, err := d.config.RestoreBackup(b, opts)
+		if err != nil {
+			return err
+		}
+
+		if res.Settings {
+			fmt.Fprintln(os.Stdout, "Restored settings")
+		}
+		// Clone the restored registries with their restored auth.
+		if cfg, err := d.config.Load(); err == nil {
+			core.SetRegistryAuth(cfg.Registries)
+		}
+		rm := core.NewRegistryManager(d.config.RegistriesDir())
+		for _, reg := range res.Registries {
+			fmt.Fprintf(os.Stdout, "Restored registry: %s (%s)\n", reg.Name, reg.Repo)
+			if _, err := rm.LoadManifest(reg.Repo); err == nil {
+				continue
+			}
+			if reg.Local {
+				if _, _, err := rm.AddLocal(reg.Repo); err != nil {
+					fmt.Fprintf(os.Stderr, "Warning: %s: could not register local registry %s: %v\n", reg.Name, reg.Repo, err)
+				}
+				continue
+			}
+			if _, err := rm.Add(reg.Repo); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: %s: could not clone %s: %v\n", reg.Name, reg.Repo, err)
+			}
+		}
+		if res.Bookmarks > 0 {
+			fmt.Fprintf(os.Stdout, "Restored %d bookmark(s)\n", res.Bookmarks)
+		}
+		if res.EnvVars > 0 {
+			fmt.Fprintf(os.Stdout, "Restored %d global env var(s)\n", res.EnvVars)
+		}
+		return nil
+	},
+}
+
+// readPassphrase returns the backup passphrase from DUCKROW_BACKUP_PASSPHRASE
+// or, on a terminal, asks for it (twice when confirm is set).
+func readPassphrase(confirm bool) (string, error) {
+	if p := os.Getenv(passphraseEnvVar); p != "" {
+		return p, nil
+	}
+	if !isInteractive() {
+		return "", fmt.Errorf("a passphrase is required; set %s or run on a terminal", passphraseEnvVar)
+	}
+
+	p, err := promptSecret("Passphrase: ")
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(p) == "" {
+		return "", fmt.Errorf("empty passphrase")
+	}
+	if confirm {
+		again, err := promptSecret("Repeat passphrase: ")
+		if err != nil {
+			return "", err
+		}
+		if again != p {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+	return p, nil
+}
+
+// promptSecret reads a line from the terminal without echoing it.
+func promptSecret(prompt string) (string, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	data, err := term.ReadPassword(int(os.Stdin.Fd()))
+	fmt.Fprintln(os.Stderr)
+	if err != nil {
+		return "", fmt.Errorf("reading passphrase: %w", err)
+	}
+	return string(data), nil
+}
+
+func init() {
+	backupCreateCmd.Flags().Bool("encrypt", false, "Encrypt the global env with a passphrase")
+	backupCreateCmd.Flags().Bool("no-env", false, "Leave the global env out of the backup")
+	backupCreateCmd.Flags().Bool("force", false, "Overwrite an existing backup file")
+
+	backupRestoreCmd.Flags().Bool("settings", false, "Restore settings")
+	backupRestoreCmd.Flags().Bool("registries", false, "Restore registries")
+	backupRestoreCmd.Flags().Bool("bookmarks", false, "Restore bookmarks")
+	backupRestoreCmd.Flags().Bool("env", false, "Restore the global env")
+
+	backupCmd.AddCommand(backupCreateCmd)
+	backupCmd.AddCommand(backupRestoreCmd)
+	rootCmd.AddCommand(backupCmd)
+}
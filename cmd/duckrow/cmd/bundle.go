@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/spf13/cobra"
+)
+
+// defaultBundleName is the file `duckrow export` writes without --out.
+const defaultBundleName = "duckrow-bundle.tar.gz"
+
+// ---------------------------------------------------------------------------
+// export
+// ---------------------------------------------------------------------------
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Package the installed assets into a portable bundle",
+	Long: `Write the project's installed skills and agents, its duckrow.lock.json, and
+the registry definitions of its MCPs to a tarball, compressed with zstd when
+--out ends in .zst (e.g. bundle.tar.zst) and with gzip otherwise. Apply the
+bundle with 'duckrow import-bundle' to set up the same assets on a machine
+that can't reach the registries.
+
+Every skill and agent in the lock file must be installed (run 'duckrow sync'
+first). Each installed copy is recorded with a digest, checked on import.
+Entries of the personal local.lock.json are not exported, and neither are
+env files.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		out, _ := cmd.Flags().GetString("out")
+		force, _ := cmd.Flags().GetBool("force")
+		if _, err := os.Stat(out); err == nil && !force {
+			return fmt.Errorf("%s already exists; use --force to overwrite it", out)
+		}
+
+		d, err := newDeps()
+		if err != nil {
+			return err
+		}
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+		cfg, err := d.config.Load()
+		if err != nil {
+			return err
+		}
+		rm := core.NewRegistryManager(d.config.RegistriesDir())
+
+		b, err := core.ExportBundle(targetDir, out, core.ExportOptions{
+			MCPConfig: func(locked asset.LockedAsset) (asset.MCPMeta, string, error) {
+				registry, _ := locked.Data["registry"].(string)
+				info, err := rm.FindMCP(cfg.Registries, core.LockedUpstreamName(locked), registry)
+				if err != nil {
+					return asset.MCPMeta{}, "", err
+				}
+				meta, ok := info.MCP.Meta.(asset.MCPMeta)
+				if !ok {
+					return asset.MCPMeta{}, "", fmt.Errorf("registry %q has no config for it", registry)
+				}
+				return meta, info.MCP.Description, nil
+			},
+		})
+		if err != nil {
+			return err
+		}
+
+		var skills, agents int
+		for _, a := range b.Assets {
+			if a.Kind == asset.KindSkill {
+				skills++
+			} else {
+				agents++
+			}
+		}
+		fmt.Fprintf(os.Stdout, "Bundle written to %s\n", out)
+		fmt.Fprintf(os.Stdout, "  %d skill(s), %d agent(s), %d MCP(s)\n", skills, agents, len(b.MCPs))
+		return nil
+	},
+}
+
+// ---------------------------------------------------------------------------
+// import-bundle
+// ---------------------------------------------------------------------------
+
+var importBundleCmd = &cobra.Command{
+	Use:   "import-bundle <file>",
+	Short: "Apply a bundle written by export",
+	Long: `Set up the assets of a bundle written by 'duckrow export' in the project,
+without fetching anything.
+
+Every file in the bundle is checked against its digest, and skills also
+against the digests frozen in the bundled lock file. If anything doesn't
+match, nothing is written. Otherwise the lock file, the skill and agent
+files, the skill links, and the MCP configs are written for the same
+systems as on the exporting machine.
+
+An existing, different duckrow.lock.json or installed copy is not replaced
+without --force. Neither is an existing MCP entry of the same name in a
+system's config file: it is skipped and reported. MCPs that need env vars still need them set, e.g. with
+'duckrow env set'.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		force, _ := cmd.Flags().GetBool("force")
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+
+		res, err := core.ImportBundle(args[0], targetDir, core.ImportOptions{Force: force})
+		if err != nil {
+			var mismatch *core.DigestMismatchError
+			if errors.As(err, &mismatch) {
+				fmt.Fprintln(os.Stderr, "Digest mismatch, nothing was written:")
+				for _, m := range mismatch.Mismatches {
+					fmt.Fprintf(os.Stderr, "  ✗ %s\n", m)
+				}
+				return fmt.Errorf("bundle %s failed verification", args[0])
+			}
+			return err
+		}
+
+		for _, a := range res.Bundle.Assets {
+			fmt.Fprintf(os.Stdout, "Installed: %s %s\n", a.Kind, a.Name)
+		}
+		for _, m := range res.Bundle.MCPs {
+			fmt.Fprintf(os.Stdout, "Installed: mcp %s\n", m.Name)
+		}
+		if len(res.SkippedMCPs) > 0 {
+			fmt.Fprintln(os.Stdout, "Skipped MCP entries that already exist (use --force to overwrite):")
+			for _, f := range res.SkippedMCPs {
+				fmt.Fprintf(os.Stdout, "  ! %-40s %s\n", f.Path, f.Skipped)
+			}
+		}
+		fmt.Fprintf(os.Stdout, "Verified %d digest(s); wrote duckrow.lock.json\n", res.Digests)
+		return nil
+	},
+}
+
+func init() {
+	exportCmd.Flags().StringP("out", "o", defaultBundleName, "Bundle file to write; a .zst name is zstd-compressed")
+	exportCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
+	exportCmd.Flags().Bool("force", false, "Overwrite an existing bundle file")
+
+	importBundleCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
+	importBundleCmd.Flags().Bool("force", false, "Replace an existing lock file and installed copies, and overwrite existing MCP entries")
+
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(importBundleCmd)
+}
@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// maxChangelogCommits caps the commits listed for one update.
+const maxChangelogCommits = 20
+
+// readChangelog reads what an update pulls in, warning on stderr and
+// returning nil if it can't be read. The reader may be nil, when changelogs
+// are turned off.
+func readChangelog(reader *core.ChangelogReader, lf *core.LockFile, kind asset.Kind, u core.UpdateInfo) *core.Changelog {
+	if reader == nil {
+		return nil
+	}
+	locked := asset.LockedAsset{Kind: kind, Name: u.Name, Source: u.Source}
+	if found := core.FindLockedAsset(lf, kind, u.Name); found != nil {
+		locked = *found
+	}
+	cl, err := reader.Read(locked, u.InstalledCommit, u.AvailableCommit)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: %s: changelog unavailable: %v\n", u.Name, err)
+		return nil
+	}
+	return cl
+}
+
+// printChangelog prints the commits an update pulls in, newest first, and
+// the new CHANGELOG.md entries, indented under the update's line.
+func printChangelog(w io.Writer, cl *core.Changelog) {
+	if cl == nil {
+		return
+	}
+	if len(cl.Commits) == 0 && cl.Excerpt == "" {
+		fmt.Fprintln(w, "  No changes to this asset's files.")
+		return
+	}
+	for i, c := range cl.Commits {
+		if i == maxChangelogCommits {
+			fmt.Fprintf(w, "    ... and %d more\n", len(cl.Commits)-i)
+			break
+		}
+		if i == 0 {
+			fmt.Fprintln(w, "  Changes:")
+		}
+		fmt.Fprintf(w, "    %s %s\n", core.TruncateCommit(c.Commit), c.Subject)
+	}
+	if cl.Excerpt != "" {
+		fmt.Fprintln(w, "  CHANGELOG.md:")
+		for _, line := range strings.Split(cl.Excerpt, "\n") {
+			fmt.Fprintln(w, strings.TrimRight("    "+line, " "))
+		}
+	}
+}
@@ -0,0 +1,71 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/system"
+	"github.com/spf13/cobra"
+)
+
+var cleanCmd = &cobra.Command{
+	Use:   "clean --systems <names>",
+	Short: "Remove every duckrow-managed entry from systems",
+	Long: `Remove every duckrow-managed entry from the given systems in a project, e.g.
+when switching editors or untangling a system-specific mess:
+
+  - skill links and copies in the system's skill directory (.cursor/skills, ...)
+  - agent files in the system's agent directory
+  - MCP entries in the system's config file (.cursor/mcp.json, ...)
+
+An entry is duckrow-managed if it is in the lock file; skill symlinks into
+.agents/skills are removed too. The canonical skill copies in .agents/skills,
+which universal systems read directly, and the lock file are left alone, so
+'duckrow sync --systems <names> --reinstall' puts everything back.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		flag, _ := cmd.Flags().GetString("systems")
+		if flag == "" {
+			return fmt.Errorf("--systems is required")
+		}
+		names := strings.Split(flag, ",")
+		for i := range names {
+			names[i] = strings.TrimSpace(names[i])
+		}
+		systems, err := system.ByNames(names)
+		if err != nil {
+			return err
+		}
+
+		orch := core.NewOrchestrator()
+		cleaned, err := orch.CleanSystems(targetDir, systems, dryRun)
+		verb := "Removed"
+		if dryRun {
+			verb = "Would remove"
+		}
+		for _, e := range cleaned {
+			fmt.Fprintf(os.Stdout, "%s: %s %s (%s)\n", verb, e.Kind, e.Name, e.Path)
+		}
+		if err != nil {
+			return err
+		}
+		if len(cleaned) == 0 {
+			fmt.Fprintf(os.Stdout, "Nothing duckrow-managed found for %s.\n", strings.Join(system.DisplayNames(systems), ", "))
+		}
+		return nil
+	},
+}
+
+func init() {
+	cleanCmd.Flags().String("systems", "", "Comma-separated system names to clean (e.g. cursor,claude-code)")
+	cleanCmd.Flags().StringP("dir", "d", "", "Project directory (default: current directory)")
+	cleanCmd.Flags().Bool("dry-run", false, "Show what would be removed without making changes")
+	rootCmd.AddCommand(cleanCmd)
+}
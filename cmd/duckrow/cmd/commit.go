@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var commitCmd = &cobra.Command{
+	Use:   "commit",
+	Short: "Commit asset changes with a generated message",
+	Long: `Commit the lock file and duckrow-managed files with a conventional commit
+message summarizing what changed since HEAD, e.g.:
+
+  chore(duckrow): update assets (1 installed, 1 updated)
+
+  Installed:
+  - skill go-review (1a2b3c4)
+
+  Updated:
+  - skill api-review (1a2b3c4..5d6e7f8)
+
+Besides duckrow.lock.json, the files of the changed assets are committed:
+skill copies and system links, agent files, the system MCP config files
+holding a changed MCP entry, and attestations in .duckrow/attestations,
+except where they are gitignored. Only these paths are committed; anything
+else already staged stays staged. The personal .duckrow/local.lock.json is
+never committed.
+
+An MCP config file that also has other uncommitted changes, such as your
+own settings or the entries of MCPs in the local lock, is left out with a
+warning; stage the intended entries yourself, e.g. with git add -p.
+
+With --dry-run the message and files are printed for copy-paste and
+nothing is staged or committed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		head, err := core.HeadLockFile(targetDir)
+		if err != nil {
+			return err
+		}
+		current, err := core.ReadLockFile(targetDir)
+		if err != nil {
+			return err
+		}
+		changes := core.LockDelta(head, current)
+		if len(changes) == 0 {
+			fmt.Fprintln(os.Stdout, "No asset changes since HEAD.")
+			return nil
+		}
+
+		message := core.CommitMessage(changes)
+		paths, warnings := core.CommitPaths(targetDir, changes)
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stderr, "Warning: %s\n", w)
+		}
+		if dryRun {
+			fmt.Fprint(os.Stdout, message)
+			fmt.Fprintln(os.Stdout, "\nFiles:")
+			for _, p := range paths {
+				fmt.Fprintf(os.Stdout, "  %s\n", p)
+			}
+			return nil
+		}
+
+		if err := core.CommitAssetChanges(targetDir, paths, message); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stdout, "Committed %d asset change(s) in %d path(s):\n", len(changes), len(paths))
+		fmt.Fprint(os.Stdout, message)
+		return nil
+	},
+}
+
+func init() {
+	commitCmd.Flags().StringP("dir", "d", "", "Project directory (default: current directory)")
+	commitCmd.Flags().Bool("dry-run", false, "Print the commit message and files without committing")
+	rootCmd.AddCommand(commitCmd)
+}
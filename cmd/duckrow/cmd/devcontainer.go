@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var devcontainerCmd = &cobra.Command{
+	Use:   "devcontainer",
+	Short: "Integrate with dev containers and Codespaces",
+	Long:  `Configure dev containers (including GitHub Codespaces) to come up with all skills, agents, and MCPs installed.`,
+}
+
+// ---------------------------------------------------------------------------
+// devcontainer inject
+// ---------------------------------------------------------------------------
+
+var devcontainerInjectCmd = &cobra.Command{
+	Use:   "inject",
+	Short: "Run duckrow sync when the dev container is created",
+	Long: `Add duckrow to the project's devcontainer.json so new containers run
+duckrow sync after creation.
+
+The file is edited in place: existing settings and comments are kept. A
+feature providing Homebrew is added (override with --feature, or pass an
+empty value to skip it) and the post-create command installs duckrow and
+syncs the lock file. An existing postCreateCommand is preserved by switching
+it to the object form so both commands run.
+
+If no .devcontainer/devcontainer.json or .devcontainer.json exists, a
+minimal one is created. Running the command again makes no changes.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+		feature, _ := cmd.Flags().GetString("feature")
+		command, _ := cmd.Flags().GetString("command")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		res, err := core.InjectDevcontainer(targetDir, core.DevcontainerOptions{
+			Feature: feature,
+			Command: command,
+			DryRun:  dryRun,
+		})
+		if err != nil {
+			return err
+		}
+
+		relPath := res.Path
+		if rel, err := filepath.Rel(targetDir, res.Path); err == nil {
+			relPath = rel
+		}
+
+		if len(res.Changes) == 0 && !res.Created {
+			fmt.Fprintf(os.Stdout, "%s already runs duckrow sync.\n", relPath)
+			return nil
+		}
+
+		if dryRun {
+			fmt.Fprintf(os.Stdout, "Would write %s:\n\n", relPath)
+			fmt.Fprint(os.Stdout, string(res.Content))
+			return nil
+		}
+
+		if res.Created {
+			fmt.Fprintf(os.Stdout, "Created %s\n", relPath)
+		} else {
+			fmt.Fprintf(os.Stdout, "Updated %s\n", relPath)
+		}
+		for _, c := range res.Changes {
+			fmt.Fprintf(os.Stdout, "  - %s\n", c)
+		}
+		return nil
+	},
+}
+
+func init() {
+	devcontainerInjectCmd.Flags().StringP("dir", "d", "", "Project directory (default: current directory)")
+	devcontainerInjectCmd.Flags().String("feature", core.DefaultDevcontainerFeature, "Dev container feature to add (empty to skip)")
+	devcontainerInjectCmd.Flags().String("command", core.DefaultDevcontainerCommand, "Post-create command to run")
+	devcontainerInjectCmd.Flags().Bool("dry-run", false, "Print the resulting file without writing it")
+
+	devcontainerCmd.AddCommand(devcontainerInjectCmd)
+	rootCmd.AddCommand(devcontainerCmd)
+}
@@ -0,0 +1,83 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that git, registries, and the project are in working order",
+	Long: `Check the environment duckrow runs in and report anything that needs fixing:
+
+  git             git is installed and recent enough
+  config          the config file loads and its paths are writable
+  registries      every registry clone is present and its manifest readable
+  registry-dirs   no directories are left behind by removed registries
+  lock            the lock file matches what is installed (see 'duckrow lock verify')
+  links           no broken or stale skill links (see 'duckrow repair')
+  mcp-env         the env vars locked MCPs require are set
+
+Warnings are worth cleaning up but don't stop duckrow from working. Use
+--json to read the results in CI.
+
+Exits with a non-zero status if any check fails.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d, err := newDeps()
+		if err != nil {
+			return err
+		}
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+
+		checks := core.Doctor(d.config, targetDir)
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			data, err := json.MarshalIndent(checks, "", "  ")
+			if err != nil {
+				return fmt.Errorf("marshaling JSON: %w", err)
+			}
+			fmt.Fprintln(os.Stdout, string(data))
+		} else {
+			printDoctorChecks(checks)
+		}
+
+		if core.DoctorFailed(checks) {
+			return fmt.Errorf("doctor found problems")
+		}
+		return nil
+	},
+}
+
+// printDoctorChecks writes one line per check, its status first, with any
+// details indented below it.
+func printDoctorChecks(checks []core.DoctorCheck) {
+	for _, c := range checks {
+		status := map[core.DoctorStatus]string{
+			core.DoctorOK:   "ok",
+			core.DoctorWarn: "warning",
+			core.DoctorFail: "error",
+		}[c.Status]
+		if accessibleOutput {
+			fmt.Fprintf(os.Stdout, "%s: %s: %s\n", c.Name, status, c.Summary)
+		} else {
+			fmt.Fprintf(os.Stdout, "%-8s %-14s %s\n", status, c.Name, c.Summary)
+		}
+		for _, detail := range c.Details {
+			fmt.Fprintf(os.Stdout, "  - %s\n", detail)
+		}
+	}
+}
+
+func init() {
+	doctorCmd.Flags().StringP("dir", "d", "", "Project directory (default: current directory)")
+	doctorCmd.Flags().Bool("json", false, "Output as JSON")
+	rootCmd.AddCommand(doctorCmd)
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
+	"slices"
 	"syscall"
 
 	"github.com/barysiuk/duckrow/internal/core"
@@ -21,7 +23,11 @@ and is not intended to be invoked directly by users.
 
 It reads the requiredEnv list for the named MCP from duckrow.lock.json, resolves
 values from the process environment, project .env.duckrow, and global
-~/.duckrow/.env.duckrow, then exec's the given command with the filtered environment.`,
+~/.duckrow/.env.duckrow, then exec's the given command with the filtered environment.
+
+{{projectDir}} in the command, its arguments, and env values from env files or
+overrides is replaced with the project directory, and a leading ~ with the home
+directory.`,
 	DisableFlagParsing: true,
 	SilenceUsage:       true,
 	RunE: func(cmd *cobra.Command, args []string) error {
@@ -37,24 +43,43 @@ values from the process environment, project .env.duckrow, and global
 			if err != nil {
 				return fmt.Errorf("getting current directory: %w", err)
 			}
+		} else if targetDir, err = filepath.Abs(targetDir); err != nil {
+			return fmt.Errorf("resolving directory: %w", err)
 		}
 
-		// Read lock file.
-		lf, err := core.ReadLockFile(targetDir)
+		// Read lock file (team lock with the personal local lock layered on top).
+		lf, err := core.ReadLayeredLockFile(targetDir)
 		if err != nil {
 			return fmt.Errorf("reading lock file: %w", err)
 		}
-		if lf == nil {
-			return fmt.Errorf("duckrow.lock.json not found in %s", targetDir)
-		}
 
-		// Find the MCP entry.
+		// Find the MCP entry. An MCP installed with --global runs in any
+		// project, and is recorded in the global lock instead.
 		mcpEntry := core.FindLockedAsset(lf, asset.KindMCP, mcpName)
+		if mcpEntry == nil {
+			global, err := core.ReadLayeredLockFile(core.GlobalLockDir())
+			if err != nil {
+				return fmt.Errorf("reading global lock file: %w", err)
+			}
+			mcpEntry = core.FindLockedAsset(global, asset.KindMCP, mcpName)
+		}
+		if mcpEntry == nil && lf == nil {
+			return fmt.Errorf("duckrow.lock.json not found in %s", targetDir)
+		}
 		if mcpEntry == nil {
 			return fmt.Errorf("MCP %q not found in lock file", mcpName)
 		}
 
-		requiredEnv := lockedRequiredEnvVars(*mcpEntry)
+		requiredEnv := core.LockedRequiredEnv(*mcpEntry)
+
+		// Values set with `duckrow mcp edit` fill in variables that are not
+		// set in the environment or an env file.
+		overrides := core.LockedMCPOverrides(*mcpEntry)
+		for _, name := range overrides.EnvNames() {
+			if !slices.Contains(requiredEnv, name) {
+				requiredEnv = append(requiredEnv, name)
+			}
+		}
 
 		// Resolve environment variables.
 		resolver := core.NewEnvResolver(targetDir, "")
@@ -62,15 +87,29 @@ values from the process environment, project .env.duckrow, and global
 
 		// Warn about missing vars.
 		for _, name := range missing {
+			if value, ok := overrides.Env[name]; ok {
+				resolved[name] = value
+				continue
+			}
 			fmt.Fprintf(os.Stderr, "Warning: env var %s required by MCP %q not found\n", name, mcpName)
 		}
 
 		// Build environment: start with current process env, add resolved vars.
+		// Values the process already has are passed through unexpanded.
 		environ := os.Environ()
 		for k, v := range resolved {
+			if _, ok := os.LookupEnv(k); !ok {
+				v = core.ExpandMCPValue(v, targetDir)
+			}
 			environ = append(environ, k+"="+v)
 		}
 
+		// The config file keeps the registry's placeholders; expand them
+		// for the project the server runs in.
+		for i, arg := range cmdArgs {
+			cmdArgs[i] = core.ExpandMCPValue(arg, targetDir)
+		}
+
 		// Find the command binary.
 		binary, err := exec.LookPath(cmdArgs[0])
 		if err != nil {
@@ -82,27 +121,6 @@ values from the process environment, project .env.duckrow, and global
 	},
 }
 
-func lockedRequiredEnvVars(locked asset.LockedAsset) []string {
-	if locked.Data == nil {
-		return nil
-	}
-	if envs, ok := locked.Data["requiredEnv"]; ok {
-		switch v := envs.(type) {
-		case []string:
-			return v
-		case []interface{}:
-			result := make([]string, 0, len(v))
-			for _, item := range v {
-				if s, ok := item.(string); ok {
-					result = append(result, s)
-				}
-			}
-			return result
-		}
-	}
-	return nil
-}
-
 // parseEnvArgs manually parses the args for `duckrow env`.
 // Expected format: --mcp <name> [-d <dir>] -- <command> [args...]
 func parseEnvArgs(args []string) (mcpName, targetDir string, cmdArgs []string, err error) {
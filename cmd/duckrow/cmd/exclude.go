@@ -0,0 +1,141 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/spf13/cobra"
+)
+
+const excludeRuleHelp = `A rule is [kind:]pattern: an entry name or a glob pattern such as old-*,
+optionally limited to one kind, e.g. skill:legacy-lint. A rule without a
+kind applies to skills, MCPs, and agents alike.`
+
+var excludeCmd = &cobra.Command{
+	Use:   "exclude",
+	Short: "Manage the registry entries a project excludes",
+	Long: `A project can exclude registry entries it never wants, such as a
+deprecated company skill. Excluded entries are hidden from the install
+pickers for the project and skipped by recommended, pattern, and tag
+installs. Installing one by its exact name still works.
+
+The rules are stored in duckrow.lock.json, shared with the team, or with
+--local in the personal .duckrow/local.lock.json. Both apply.
+
+` + excludeRuleHelp,
+}
+
+// ---------------------------------------------------------------------------
+// exclude add
+// ---------------------------------------------------------------------------
+
+var excludeAddCmd = &cobra.Command{
+	Use:   "add <rule>...",
+	Short: "Exclude registry entries from a project",
+	Long:  "Add exclude rules to the project.\n\n" + excludeRuleHelp,
+	Args:  cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+		local, _ := cmd.Flags().GetBool("local")
+
+		added, err := core.AddExcludes(targetDir, args, local)
+		if err != nil {
+			return err
+		}
+		for _, rule := range args {
+			if slices.Contains(added, rule) {
+				fmt.Fprintf(os.Stdout, "Excluded: %s\n", rule)
+			} else {
+				fmt.Fprintf(os.Stdout, "Already excluded: %s\n", rule)
+			}
+		}
+		return nil
+	},
+}
+
+// ---------------------------------------------------------------------------
+// exclude remove
+// ---------------------------------------------------------------------------
+
+var excludeRemoveCmd = &cobra.Command{
+	Use:   "remove <rule>...",
+	Short: "Remove exclude rules from a project",
+	Long: `Remove exclude rules from the project. Each rule must be given exactly as
+'duckrow exclude list' shows it; with --local, from the personal lock.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+		local, _ := cmd.Flags().GetBool("local")
+
+		if err := core.RemoveExcludes(targetDir, args, local); err != nil {
+			return err
+		}
+		for _, rule := range args {
+			fmt.Fprintf(os.Stdout, "Removed: %s\n", rule)
+		}
+		return nil
+	},
+}
+
+// ---------------------------------------------------------------------------
+// exclude list
+// ---------------------------------------------------------------------------
+
+var excludeListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List a project's exclude rules",
+	Long:  `List the project's exclude rules. Rules from the personal local lock are marked (local).`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+
+		var lines []string
+		for _, layer := range []struct {
+			read   func(string) (*core.LockFile, error)
+			suffix string
+		}{
+			{core.ReadLockFile, ""},
+			{core.ReadLocalLockFile, " (local)"},
+		} {
+			lf, err := layer.read(targetDir)
+			if err != nil {
+				return err
+			}
+			if lf == nil {
+				continue
+			}
+			for _, rule := range lf.Exclude {
+				lines = append(lines, rule+layer.suffix)
+			}
+		}
+
+		if len(lines) == 0 {
+			fmt.Fprintln(os.Stdout, "No exclude rules. Use 'duckrow exclude add <rule>' to add one.")
+			return nil
+		}
+		fmt.Fprintln(os.Stdout, strings.Join(lines, "\n"))
+		return nil
+	},
+}
+
+func init() {
+	for _, c := range []*cobra.Command{excludeAddCmd, excludeRemoveCmd, excludeListCmd} {
+		c.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
+		excludeCmd.AddCommand(c)
+	}
+	excludeAddCmd.Flags().Bool("local", false, "Add to the personal local lock instead of duckrow.lock.json")
+	excludeRemoveCmd.Flags().Bool("local", false, "Remove from the personal local lock instead of duckrow.lock.json")
+	rootCmd.AddCommand(excludeCmd)
+}
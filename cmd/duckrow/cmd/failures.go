@@ -0,0 +1,60 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// The bulk operations whose failures are remembered for --retry-failed.
+const (
+	failuresSync   = "sync"
+	failuresUpdate = "update"
+)
+
+// saveFailures records the items a run of op failed on, warning if the state
+// file can't be written. tried reports whether the run covered an asset.
+func saveFailures(d *deps, targetDir, op string, tried func(kind asset.Kind, name string) bool, failed []core.FailedItem) {
+	if err := d.config.SaveFailures(targetDir, op, tried, failed); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to save the failed items for --retry-failed: %v\n", err)
+	}
+}
+
+// printRetryHint tells how to retry the items a run failed on.
+func printRetryHint(failed []core.FailedItem, retryCmd string) {
+	n := 0
+	for _, f := range failed {
+		if f.Name != "" {
+			n++
+		}
+	}
+	if n > 0 {
+		fmt.Fprintf(os.Stderr, "Run '%s --retry-failed' to retry the %d failed item(s).\n", retryCmd, n)
+	}
+}
+
+// stdoutForJSON returns where --json output goes and, while it is set, sends
+// everything else printed to stdout to stderr instead, so progress lines
+// don't break the JSON. The returned func restores stdout.
+func stdoutForJSON(jsonOutput bool) (*os.File, func()) {
+	stdout := os.Stdout
+	if !jsonOutput {
+		return stdout, func() {}
+	}
+	os.Stdout = os.Stderr
+	return stdout, func() { os.Stdout = stdout }
+}
+
+// writeSummaryJSON writes a bulk operation's summary as indented JSON.
+func writeSummaryJSON(w io.Writer, summary any) error {
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling JSON: %w", err)
+	}
+	fmt.Fprintln(w, string(data))
+	return nil
+}
@@ -1,10 +1,15 @@
 package cmd
 
 import (
+	"bufio"
+	"errors"
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
 	"github.com/barysiuk/duckrow/internal/core/system"
 	"github.com/spf13/cobra"
 )
@@ -43,8 +48,31 @@ func resolveTargetDir(cmd *cobra.Command) (string, error) {
 	return cwd, nil
 }
 
+// resolveScope resolves the --global flag into the scope to work in, the
+// directory assets are installed into, and the directory of the lock file
+// that records them. Without --global both are the target directory; with
+// it, they are the home directory and ~/.duckrow.
+func resolveScope(cmd *cobra.Command) (scope system.Scope, targetDir, lockDir string, err error) {
+	if global, _ := cmd.Flags().GetBool("global"); !global {
+		targetDir, err = resolveTargetDir(cmd)
+		return system.ScopeProject, targetDir, targetDir, err
+	}
+	if dir, _ := cmd.Flags().GetString("dir"); dir != "" {
+		return 0, "", "", fmt.Errorf("--global cannot be used with --dir")
+	}
+	if local, _ := cmd.Flags().GetBool("local"); local {
+		return 0, "", "", fmt.Errorf("--global cannot be used with --local")
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return 0, "", "", fmt.Errorf("finding home directory: %w", err)
+	}
+	return system.ScopeGlobal, home, core.GlobalLockDir(), nil
+}
+
 // resolveTargetSystems parses the --systems flag into []system.System.
-// Returns nil (meaning "use defaults") if the flag is empty.
+// If the flag is empty it returns the project's defaultSystems setting, or
+// nil (meaning "use defaults") if the project has none or --global is set.
 // Also checks the hidden --agents alias for backward compatibility.
 func resolveTargetSystems(cmd *cobra.Command) ([]system.System, error) {
 	flag, _ := cmd.Flags().GetString("systems")
@@ -52,9 +80,17 @@ func resolveTargetSystems(cmd *cobra.Command) ([]system.System, error) {
 		// Check hidden --agents alias.
 		flag, _ = cmd.Flags().GetString("agents")
 	}
-	if flag == "" {
+	if global, _ := cmd.Flags().GetBool("global"); flag == "" && global {
 		return nil, nil
 	}
+	if flag == "" {
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return nil, err
+		}
+		systems, _, err := core.DefaultSystems(targetDir)
+		return systems, err
+	}
 
 	names := strings.Split(flag, ",")
 	for i := range names {
@@ -69,3 +105,205 @@ func addSystemsFlag(cmd *cobra.Command) {
 	cmd.Flags().String("agents", "", "Alias for --systems (deprecated)")
 	_ = cmd.Flags().MarkHidden("agents")
 }
+
+// writeLockEntry records an installed asset in the team lock file, or in the
+// personal .duckrow/local.lock.json when local is set. It returns the
+// project-relative name of the lock file that was written, or the path of
+// the global lock when targetDir is core.GlobalLockDir.
+func writeLockEntry(targetDir string, entry asset.LockedAsset, local bool) (string, error) {
+	if local {
+		return ".duckrow/local.lock.json", core.AddOrUpdateLocalAsset(targetDir, entry)
+	}
+	if targetDir == core.GlobalLockDir() {
+		if err := os.MkdirAll(targetDir, 0o755); err != nil {
+			return "", fmt.Errorf("creating %s: %w", targetDir, err)
+		}
+	}
+	return lockFileLabel(targetDir), core.AddOrUpdateAsset(targetDir, entry)
+}
+
+// lockFileLabel names the team lock file in lockDir for messages:
+// duckrow.lock.json, or ~/.duckrow/duckrow.lock.json for the global lock.
+func lockFileLabel(lockDir string) string {
+	if lockDir == core.GlobalLockDir() {
+		return "~/.duckrow/duckrow.lock.json"
+	}
+	return "duckrow.lock.json"
+}
+
+// skipForPlatform reports whether a locked entry is limited to platforms
+// other than this one, and if so says it is skipped.
+func skipForPlatform(locked asset.LockedAsset, dryRun bool) bool {
+	if core.PlatformMatches(locked.Platforms, core.CurrentPlatform()) {
+		return false
+	}
+	label := "Skipped"
+	if dryRun {
+		label = "skip"
+	}
+	fmt.Fprintf(os.Stdout, "%s: %s (only for %s; this is %s)\n",
+		label, locked.Name, strings.Join(locked.Platforms, ", "), core.CurrentPlatform())
+	return true
+}
+
+// platformLabel shows, for dry runs, how a locked entry's platform limits
+// were evaluated, e.g. " [linux/amd64 matches linux, darwin/arm64]". It is
+// empty for entries without limits.
+func platformLabel(locked asset.LockedAsset) string {
+	if len(locked.Platforms) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" [%s matches %s]", core.CurrentPlatform(), strings.Join(locked.Platforms, ", "))
+}
+
+// overridesLabel returns a display suffix for MCPs with local overrides,
+// or "" for MCPs used as the registry defines them.
+func overridesLabel(o core.MCPOverrides) string {
+	if o.IsEmpty() {
+		return ""
+	}
+	return fmt.Sprintf(" [overridden: %s]", o)
+}
+
+// originLabel returns a display suffix for assets that come from the
+// personal local lock, or "" for team lock entries.
+func originLabel(lf *core.LockFile, kind asset.Kind, name string) string {
+	if lf.Origin(kind, name) == core.OriginLocal {
+		return " (local)"
+	}
+	return ""
+}
+
+// skillSizeLimits returns the configured skill size thresholds, or no limits
+// when the user passed --accept-large.
+func skillSizeLimits(cfg *core.Config, acceptLarge bool) core.SizeLimits {
+	if acceptLarge {
+		return core.SizeLimits{}
+	}
+	return cfg.Settings.SkillSizeLimits()
+}
+
+// confirmLargeSkill asks on the terminal whether to install a skill that is
+// over the size limits. Outside a terminal it declines, so scripts must pass
+// --accept-large explicitly.
+func confirmLargeSkill(name string, size core.SkillSize) bool {
+	if !isInteractive() {
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "Skill %q is large (%s). Install anyway? [y/N] ", name, size)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// confirmOverwriteModified asks on the terminal whether to discard local
+// changes to an installed skill. Outside a terminal it declines, so scripts
+// must pass --overwrite-modified explicitly.
+func confirmOverwriteModified(name string, files []string) bool {
+	if !isInteractive() {
+		return false
+	}
+	fmt.Fprintf(os.Stderr, "Skill %q has local modifications:\n", name)
+	for _, f := range files {
+		fmt.Fprintf(os.Stderr, "  %s\n", f)
+	}
+	fmt.Fprint(os.Stderr, "Discard them and install? [y/N] ")
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	answer = strings.ToLower(strings.TrimSpace(answer))
+	return answer == "y" || answer == "yes"
+}
+
+// hydrateOptions returns the commit hydration options for the config. With
+// force, cached commits are resolved again regardless of their age.
+func hydrateOptions(cfg *core.Config, force bool) core.HydrateOptions {
+	return core.HydrateOptions{
+		Overrides: cfg.Settings.CloneURLOverrides,
+		TTL:       cfg.Settings.CommitCacheTTL(),
+		Force:     force,
+		Disabled:  cfg.Settings.DisableHydration,
+	}
+}
+
+// checkForUpdates checks the locked assets of a kind for updates, warning on
+// stderr as soon as a repository cannot be checked.
+func checkForUpdates(lf *core.LockFile, kind asset.Kind, overrides map[string]string, mirrors core.RegistryMirrors, registryCommits map[string]string) []core.UpdateInfo {
+	var updates []core.UpdateInfo
+	offlineRepos := 0
+	core.CheckForUpdatesByRepo(lf, kind, overrides, mirrors, registryCommits, func(r core.RepoUpdates) {
+		switch {
+		case r.Err == nil:
+		case errors.Is(r.Err, core.ErrOffline):
+			offlineRepos++
+		default:
+			fmt.Fprintf(os.Stderr, "Warning: could not check %s for updates: %v\n", r.Repo, r.Err.Err)
+		}
+		updates = append(updates, r.Updates...)
+	})
+	if offlineRepos > 0 {
+		fmt.Fprintf(os.Stderr, "Offline: %d source repo(s) without cached registry commits were not checked.\n", offlineRepos)
+	}
+	return updates
+}
+
+// hydrateForUpdates refreshes the registry commit caches before an update
+// check. Offline, the existing caches are used as they are.
+func hydrateForUpdates(rm *core.RegistryManager, cfg *core.Config) {
+	if core.Offline() {
+		fmt.Fprintln(os.Stderr, "Offline: skipping commit hydration; using cached registry commits.")
+		return
+	}
+	rm.Hydrate(cfg.Registries, hydrateOptions(cfg, false))
+}
+
+// formatAge renders a duration since t in a compact form such as "5m ago".
+func formatAge(t time.Time) string {
+	d := time.Since(t)
+	switch {
+	case d < time.Minute:
+		return "just now"
+	case d < time.Hour:
+		return fmt.Sprintf("%dm ago", int(d.Minutes()))
+	case d < 48*time.Hour:
+		return fmt.Sprintf("%dh ago", int(d.Hours()))
+	}
+	return fmt.Sprintf("%dd ago", int(d.Hours()/24))
+}
+
+// promptAlias tells the user about a name conflict and asks for a different
+// name to install the incoming asset under. Outside a terminal, or when the
+// answer is empty, it returns "" and the install fails with the conflict.
+func promptAlias(c core.AssetConflict) string {
+	if !isInteractive() {
+		return ""
+	}
+	fmt.Fprintf(os.Stderr, "Conflict: %s.\nInstall as (leave empty to cancel): ", c)
+	answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(answer)
+}
+
+// withConflictHint adds the ways out of a name conflict, local changes that
+// would be overwritten, a case-only name collision, an upstream name that
+// cannot be used as-is, or a path that is too long for Windows to the error.
+func withConflictHint(err error) error {
+	var conflictErr *core.ConflictError
+	if errors.As(err, &conflictErr) {
+		return fmt.Errorf("%w; re-run with --as <name> to install it under another name, or --force to replace it", err)
+	}
+	var modifiedErr *core.ModifiedSkillError
+	if errors.As(err, &modifiedErr) {
+		return fmt.Errorf("%w; re-run with --overwrite-modified to discard the changes", err)
+	}
+	var caseErr *core.CaseCollisionError
+	if errors.As(err, &caseErr) {
+		return fmt.Errorf("%w; rename or remove %s, or re-run with --as <name> to install it under another name", err, caseErr.Path)
+	}
+	var nameErr *asset.NameError
+	if errors.As(err, &nameErr) {
+		return fmt.Errorf("%w; re-run with --as <name> to install it under a valid name", err)
+	}
+	var pathErr *core.PathTooLongError
+	if errors.As(err, &pathErr) {
+		return fmt.Errorf("%w\nmove the project to a shorter path, re-run with --as <name> to use a shorter name, or enable Windows long paths (LongPathsEnabled)", err)
+	}
+	return err
+}
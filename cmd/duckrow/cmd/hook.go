@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Manage git hooks that verify the lock file",
+	Long:  `Install and run git hooks that keep duckrow.lock.json consistent with installed assets and the project's policies.`,
+}
+
+// ---------------------------------------------------------------------------
+// hook install
+// ---------------------------------------------------------------------------
+
+var hookInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install pre-commit and pre-push hooks",
+	Long: `Install git hooks that run 'duckrow hook check' before commits and pushes.
+
+The pre-commit hook only runs when staged changes touch .agents/, .cursor/,
+or duckrow.lock.json. The pre-push hook always runs. Hooks previously written
+by duckrow are replaced; other existing hooks are left alone unless --force
+is given.
+
+With --framework pre-commit, nothing is written. Instead a hook entry for the
+pre-commit framework (https://pre-commit.com) is printed, ready to paste into
+.pre-commit-hooks.yaml or under a "repo: local" entry in .pre-commit-config.yaml.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+		framework, _ := cmd.Flags().GetString("framework")
+		force, _ := cmd.Flags().GetBool("force")
+
+		switch framework {
+		case "git":
+			paths, err := core.InstallGitHooks(targetDir, force)
+			if err != nil {
+				var existsErr *core.HookExistsError
+				if errors.As(err, &existsErr) {
+					return fmt.Errorf("%w; use --force to replace it or --framework pre-commit to integrate with pre-commit", err)
+				}
+				return err
+			}
+			for _, p := range paths {
+				fmt.Fprintf(os.Stdout, "Installed hook: %s\n", p)
+			}
+		case "pre-commit":
+			prefix, err := core.GitProjectPrefix(targetDir)
+			if err != nil {
+				return err
+			}
+			fmt.Fprint(os.Stdout, core.PreCommitFrameworkHook(prefix))
+		default:
+			return fmt.Errorf("unknown framework %q (expected git or pre-commit)", framework)
+		}
+		return nil
+	},
+}
+
+// ---------------------------------------------------------------------------
+// hook check
+// ---------------------------------------------------------------------------
+
+var hookCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Verify the lock file matches installed assets and project policies",
+	Long: `Verify that duckrow.lock.json (plus the personal local lock) matches what is
+installed in the folder. Reports locked skills and agents that are missing
+or not pinned to a commit, skills whose files differ from the recorded file
+list, and skills in .agents/skills that are not in the lock file.
+
+Project policies are checked as well: locked entries matched by the
+project's exclude rules, locked skills and agents whose source host is
+outside the allowedHosts setting, and the version control hygiene issues
+'duckrow vcs check' reports, including the gitignorePolicy setting.
+
+Exits with a non-zero status if any issue is found. This is what the hooks
+installed by 'duckrow hook install' run.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+
+		issues, err := core.CheckLockConsistency(targetDir)
+		if err != nil {
+			return err
+		}
+		policyIssues, err := core.CheckLockPolicy(targetDir)
+		if err != nil {
+			return err
+		}
+		vcsIssues, err := core.CheckVCS(targetDir, gitignorePolicy)
+		if err != nil && !errors.Is(err, core.ErrNotGitRepo) {
+			return err
+		}
+		total := len(issues) + len(policyIssues) + len(vcsIssues)
+		if total == 0 {
+			fmt.Fprintln(os.Stdout, "Lock file is consistent.")
+			return nil
+		}
+
+		if len(issues) > 0 {
+			fmt.Fprintln(os.Stderr, "duckrow: lock file is out of sync with installed assets:")
+			for _, issue := range issues {
+				fmt.Fprintf(os.Stderr, "  - %s\n", issue)
+			}
+			fmt.Fprintln(os.Stderr, "Run 'duckrow sync' to install missing assets, or install/uninstall to update the lock file.")
+		}
+		if len(policyIssues) > 0 {
+			fmt.Fprintln(os.Stderr, "duckrow: locked assets break the project's policies:")
+			for _, issue := range policyIssues {
+				fmt.Fprintf(os.Stderr, "  - %s\n", issue)
+			}
+			fmt.Fprintln(os.Stderr, "Uninstall them, or change the exclude rules or the allowedHosts setting.")
+		}
+		if len(vcsIssues) > 0 {
+			fmt.Fprintln(os.Stderr, "duckrow: version control hygiene issues:")
+			for _, issue := range vcsIssues {
+				fmt.Fprintf(os.Stderr, "  - %s\n    %s\n", issue, issue.Hint)
+			}
+		}
+		return fmt.Errorf("%d lock issue(s) found", total)
+	},
+}
+
+func init() {
+	hookInstallCmd.Flags().StringP("dir", "d", "", "Project directory (default: current directory)")
+	hookInstallCmd.Flags().String("framework", "git", "Hook framework: git or pre-commit")
+	hookInstallCmd.Flags().Bool("force", false, "Replace existing hooks not written by duckrow")
+
+	hookCheckCmd.Flags().StringP("dir", "d", "", "Project directory (default: current directory)")
+
+	hookCmd.AddCommand(hookInstallCmd)
+	hookCmd.AddCommand(hookCheckCmd)
+	rootCmd.AddCommand(hookCmd)
+}
@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var initCmd = &cobra.Command{
+	Use:   "init",
+	Short: "Scaffold a registry repository or a project",
+	Long:  `Create the files a new duckrow registry repository or project starts from.`,
+}
+
+// ---------------------------------------------------------------------------
+// init registry
+// ---------------------------------------------------------------------------
+
+var initRegistryCmd = &cobra.Command{
+	Use:   "registry",
+	Short: "Scaffold a registry repository",
+	Long: `Scaffold a registry repository in the target directory:
+
+  duckrow.json                   a manifest with an example skill, MCP, and agent
+  README.md                      how to use the registry and add entries
+  .github/workflows/duckrow.yml  a GitHub Actions workflow that checks the
+                                 manifest and reports entries not pinned to
+                                 a commit, with the commit to pin them to
+
+The registry is named after the directory unless --name is given. Existing
+files are kept unless --force is given.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+		name, _ := cmd.Flags().GetString("name")
+		force, _ := cmd.Flags().GetBool("force")
+		if name == "" {
+			abs, err := filepath.Abs(targetDir)
+			if err != nil {
+				return fmt.Errorf("resolving directory: %w", err)
+			}
+			name = filepath.Base(abs)
+		}
+
+		res, err := core.InitRegistry(targetDir, name, force)
+		printInitResult(res)
+		if err != nil {
+			return err
+		}
+		if len(res.Created) > 0 {
+			fmt.Fprintln(os.Stdout, "\nReplace the example entries in duckrow.json, push the repository, and add it with 'duckrow registry add <clone-url>'.")
+		}
+		return nil
+	},
+}
+
+// ---------------------------------------------------------------------------
+// init project
+// ---------------------------------------------------------------------------
+
+var initProjectCmd = &cobra.Command{
+	Use:   "project",
+	Short: "Prepare a project for duckrow",
+	Long: `Prepare the target directory for duckrow: an empty duckrow.lock.json to
+commit, an .env.duckrow for the env vars MCP servers require, and .gitignore
+entries for .env.duckrow and the personal .duckrow/local.lock.json. Existing
+files are kept.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+		res, err := core.InitProject(targetDir)
+		printInitResult(res)
+		return err
+	},
+}
+
+// printInitResult lists the files an init command created and kept.
+func printInitResult(res *core.InitResult) {
+	if res == nil {
+		return
+	}
+	for _, rel := range res.Created {
+		fmt.Fprintf(os.Stdout, "  + %s\n", rel)
+	}
+	for _, rel := range res.Skipped {
+		fmt.Fprintf(os.Stdout, "  = %s (exists; kept)\n", rel)
+	}
+}
+
+func init() {
+	initRegistryCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
+	initRegistryCmd.Flags().String("name", "", "Registry name (default: the directory name)")
+	initRegistryCmd.Flags().Bool("force", false, "Overwrite existing files")
+	initProjectCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
+
+	initCmd.AddCommand(initRegistryCmd)
+	initCmd.AddCommand(initProjectCmd)
+	rootCmd.AddCommand(initCmd)
+}
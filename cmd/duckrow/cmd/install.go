@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/spf13/cobra"
+)
+
+var installTagCmd = &cobra.Command{
+	Use:   "install --tag <tag>",
+	Short: "Install every registry entry carrying a tag",
+	Long: `Install every registry entry carrying a tag, e.g. all the skills, MCPs,
+and agents a registry tags "backend". Registry authors tag entries with a
+"tags" list in duckrow.json, so a curated collection is one command to
+install and nothing else to maintain.
+
+--kind limits the install to one asset kind. The entries are listed and
+installed once you confirm, or with --yes, in one transaction: if one
+fails, those installed before it are removed again. Entries already in
+the lock file or for another platform are skipped.
+
+The tags are recorded in the lock file, so 'duckrow sync --tag <tag>'
+installs just that collection later.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		tag, _ := cmd.Flags().GetString("tag")
+		kindFlag, _ := cmd.Flags().GetString("kind")
+		registryFilter, _ := cmd.Flags().GetString("registry")
+		if tag == "" {
+			return fmt.Errorf("--tag is required")
+		}
+
+		kinds := asset.Kinds()
+		if kindFlag != "" {
+			if _, ok := asset.Get(asset.Kind(kindFlag)); !ok {
+				return fmt.Errorf("unknown kind %q (want skill, mcp, agent, command, or rule)", kindFlag)
+			}
+			kinds = []asset.Kind{asset.Kind(kindFlag)}
+		}
+
+		d, err := newDeps()
+		if err != nil {
+			return err
+		}
+		cfg, err := d.config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		if registryFilter != "" {
+			reg, err := findRegistry(cfg.Registries, registryFilter)
+			if err != nil {
+				return err
+			}
+			registryFilter = reg.Repo
+		}
+
+		rm := core.NewRegistryManager(d.config.RegistriesDir())
+		matches, err := rm.TaggedAssets(cfg.Registries, kinds, tag, registryFilter)
+		if err != nil {
+			return err
+		}
+		return installRegistryBatch(cmd, cfg, rm, fmt.Sprintf("Tagged %q (%d)", tag, len(matches)), matches)
+	},
+}
+
+func init() {
+	installTagCmd.Flags().String("tag", "", "Tag of the entries to install")
+	installTagCmd.Flags().String("kind", "", "Install only this kind: skill, mcp, agent, command, or rule")
+	installTagCmd.Flags().StringP("registry", "r", "", "Limit to a specific registry")
+	installTagCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
+	installTagCmd.Flags().BoolP("yes", "y", false, "Install without asking")
+	addSystemsFlag(installTagCmd)
+	rootCmd.AddCommand(installTagCmd)
+}
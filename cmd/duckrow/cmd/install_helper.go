@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var installHelperCmd = &cobra.Command{
+	Use:   "install-helper",
+	Short: "Download, verify, and install a duckrow release binary",
+	Long: `Download a duckrow release, verify it, and place the binary in a directory.
+
+Intended for bootstrap scripts and provisioning tools that standardize a team
+on a specific duckrow version without Homebrew or Scoop. Releases are not
+signed, so the archive's SHA256 must be pinned with --checksum, taken from
+the release page or a trusted copy of its checksums.txt. The archive must
+match it and the checksums.txt published with the release.
+
+The version defaults to the version of the running binary.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		version, _ := cmd.Flags().GetString("version")
+		if version == "" {
+			if Version == "dev" {
+				return fmt.Errorf("--version is required for development builds")
+			}
+			version = Version
+		}
+
+		dest, _ := cmd.Flags().GetString("dest")
+		if dest == "" {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return fmt.Errorf("resolving home directory: %w", err)
+			}
+			dest = filepath.Join(home, ".local", "bin")
+		}
+
+		goos, _ := cmd.Flags().GetString("os")
+		goarch, _ := cmd.Flags().GetString("arch")
+		baseURL, _ := cmd.Flags().GetString("base-url")
+		checksum, _ := cmd.Flags().GetString("checksum")
+
+		res, err := core.InstallRelease(core.ReleaseInstallOptions{
+			Version:  version,
+			OS:       goos,
+			Arch:     goarch,
+			DestDir:  dest,
+			BaseURL:  baseURL,
+			Checksum: checksum,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stdout, "Installed: %s\n", res.Path)
+		fmt.Fprintf(os.Stdout, "  Archive: %s\n", res.Archive)
+		fmt.Fprintf(os.Stdout, "  SHA256:  %s\n", res.Checksum)
+		return nil
+	},
+}
+
+func init() {
+	installHelperCmd.Flags().String("version", "", "Release version to install (default: this binary's version)")
+	installHelperCmd.Flags().String("dest", "", "Directory to install the binary into (default: ~/.local/bin)")
+	installHelperCmd.Flags().String("os", runtime.GOOS, "Target operating system")
+	installHelperCmd.Flags().String("arch", runtime.GOARCH, "Target architecture")
+	installHelperCmd.Flags().String("checksum", "", "Expected SHA256 of the release archive (required)")
+	_ = installHelperCmd.MarkFlagRequired("checksum")
+	installHelperCmd.Flags().String("base-url", core.DefaultReleaseBaseURL, "Release download base URL (for mirrors)")
+	rootCmd.AddCommand(installHelperCmd)
+}
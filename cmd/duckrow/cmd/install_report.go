@@ -0,0 +1,129 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// writeInstallReport prints what an install did, as JSON when jsonOutput is
+// set and as text otherwise. Every kind's install goes through here so the
+// output reads the same whatever was installed.
+func writeInstallReport(report core.InstallReport, jsonOutput bool) error {
+	if jsonOutput {
+		return writeInstallReportJSON(os.Stdout, report)
+	}
+	writeInstallReportText(os.Stdout, os.Stderr, report)
+	return nil
+}
+
+// writeInstallReportJSON writes the report as indented JSON.
+func writeInstallReportJSON(w io.Writer, report core.InstallReport) error {
+	if report.Assets == nil {
+		report.Assets = []core.InstallReportAsset{}
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling JSON: %w", err)
+	}
+	fmt.Fprintln(w, string(data))
+	return nil
+}
+
+// writeInstallReportText writes the report for a person: each asset, the
+// files written for each system, the lock file updated, and anything left
+// to do. Warnings go to errW.
+func writeInstallReportText(w, errW io.Writer, report core.InstallReport) {
+	display := string(report.Kind)
+	if handler, ok := asset.Get(report.Kind); ok {
+		display = handler.DisplayName()
+	}
+	what := strings.ToLower(display) + " files"
+	if report.Kind == asset.KindMCP {
+		// MCPs are entries in each system's config rather than files.
+		display, what = "MCP", "MCP config"
+	}
+
+	var files []core.InstallReportFile
+	for _, a := range report.Assets {
+		files = append(files, a.Files...)
+		if a.Unchanged {
+			fmt.Fprintf(w, "Already installed: %s at %s; use --reinstall to install it again\n",
+				a.Name, core.TruncateCommit(a.Commit))
+			if len(a.Systems) > 0 && len(a.Files) == 0 {
+				fmt.Fprintf(w, "  Added for: %s\n", joinStrings(a.Systems))
+			}
+			continue
+		}
+		fmt.Fprintf(w, "Installed: %s\n", a.Name)
+		if a.Namespace != "" {
+			fmt.Fprintf(w, "  Namespaced: %s from registry %s\n", a.AliasOf, a.Namespace)
+		} else if a.AliasOf != "" {
+			fmt.Fprintf(w, "  Alias of: %s\n", a.AliasOf)
+		}
+		if a.Version != "" {
+			fmt.Fprintf(w, "  Version: %s\n", a.Version)
+		}
+		if a.Path != "" {
+			fmt.Fprintf(w, "  Path: %s\n", a.Path)
+		}
+		if len(a.Systems) > 0 && len(a.Files) == 0 {
+			fmt.Fprintf(w, "  Systems: %s\n", joinStrings(a.Systems))
+		}
+		if a.FileCount > 0 {
+			fmt.Fprintf(w, "  Size: %s\n", a.Size())
+		}
+	}
+
+	if len(files) > 0 {
+		fmt.Fprintf(w, "\nWrote %s to:\n", what)
+		for _, f := range files {
+			switch {
+			case f.Error != "":
+				fmt.Fprintf(errW, "  x %-40s error: %s\n", f.Path, f.Error)
+			case f.Skipped != "":
+				fmt.Fprintf(w, "  ! %-40s %s\n", f.Path, f.Skipped)
+			default:
+				fmt.Fprintf(w, "  + %-40s (%s)\n", f.Path, f.System)
+			}
+		}
+	}
+
+	if report.LockFile != "" {
+		fmt.Fprintf(w, "\nUpdated %s\n", report.LockFile)
+	}
+	for _, path := range report.Attestations {
+		fmt.Fprintf(w, "Attested: %s\n", path)
+	}
+
+	var required []string
+	for _, a := range report.Assets {
+		for _, v := range a.RequiredEnv {
+			required = append(required, fmt.Sprintf("  %s  (used by %s)", v, a.Name))
+		}
+	}
+	if len(required) > 0 {
+		fmt.Fprintln(w, "\n! The following environment variables are required:")
+		for _, line := range required {
+			fmt.Fprintln(w, line)
+		}
+		fmt.Fprintln(w, "\n  Add values to .env.duckrow or ~/.duckrow/.env.duckrow")
+	}
+
+	for _, warning := range report.Warnings {
+		fmt.Fprintf(errW, "Warning: %s\n", warning)
+	}
+
+	installed := report.Installed()
+	if len(report.Assets) == 1 && len(installed) == 1 {
+		fmt.Fprintf(w, "\n%s %q installed successfully.\n", display, installed[0].Name)
+	}
+	if len(installed) > 0 {
+		printPostInstallMessage(report.PostInstallMessage)
+	}
+}
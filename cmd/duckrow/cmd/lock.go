@@ -0,0 +1,311 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+	"github.com/spf13/cobra"
+)
+
+var lockCmd = &cobra.Command{
+	Use:   "lock",
+	Short: "Freeze, verify, and normalize the lock file",
+	Long: `Make duckrow.lock.json a reproducibility contract.
+
+'duckrow lock freeze' pins every entry to concrete content, and
+'duckrow lock verify --frozen' fails if anything would install differently.
+'duckrow lock normalize' rewrites the lock and MCP configs in stable order.`,
+}
+
+// ---------------------------------------------------------------------------
+// lock freeze
+// ---------------------------------------------------------------------------
+
+var lockFreezeCmd = &cobra.Command{
+	Use:   "freeze",
+	Short: "Pin every lock entry to concrete commits and digests",
+	Long: `Resolve every floating entry in duckrow.lock.json (and the personal
+.duckrow/local.lock.json) to concrete content:
+
+  - skills and agents without a commit are pinned to the commit their ref
+    (or the default branch) points to now
+  - skills and agents get a digest of the files sync installs for them
+    (data.digest), with ignore rules and partial updates applied
+  - MCPs without a config hash get the one from the configured registries
+
+Values already in the lock are kept. If any entry cannot be resolved, nothing
+is written.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+		opts, err := freezeOptions()
+		if err != nil {
+			return err
+		}
+
+		team, err := core.ReadLockFile(targetDir)
+		if err != nil {
+			return err
+		}
+		local, err := core.ReadLocalLockFile(targetDir)
+		if err != nil {
+			return err
+		}
+		if team == nil && local == nil {
+			return fmt.Errorf("no lock file found in %s", targetDir)
+		}
+
+		orch := core.NewOrchestrator()
+		var frozen []core.FrozenEntry
+		var issues []core.LockIssue
+		for _, lf := range []*core.LockFile{team, local} {
+			if lf == nil {
+				continue
+			}
+			f, i := orch.FreezeLock(lf, opts)
+			frozen = append(frozen, f...)
+			issues = append(issues, i...)
+		}
+
+		if len(issues) > 0 {
+			for _, issue := range issues {
+				fmt.Fprintf(os.Stderr, "Error: %s\n", issue)
+			}
+			return fmt.Errorf("%d lock issue(s) found; lock file not changed", len(issues))
+		}
+		if len(frozen) == 0 {
+			fmt.Fprintln(os.Stdout, "Lock file is already frozen.")
+			return nil
+		}
+
+		if team != nil {
+			if err := core.WriteLockFile(targetDir, team); err != nil {
+				return err
+			}
+		}
+		if local != nil {
+			if err := core.WriteLocalLockFile(targetDir, local); err != nil {
+				return err
+			}
+		}
+		for _, f := range frozen {
+			fmt.Fprintf(os.Stdout, "Frozen: %s %q (%s)\n", f.Kind, f.Name, describeFrozen(f))
+		}
+		return nil
+	},
+}
+
+// describeFrozen lists the values freeze recorded for an entry, e.g.
+// "commit 1a2b3c4, digest sha256:...".
+func describeFrozen(f core.FrozenEntry) string {
+	var parts []string
+	if f.Commit != "" {
+		parts = append(parts, "commit "+core.TruncateCommit(f.Commit))
+	}
+	if f.Digest != "" {
+		parts = append(parts, "digest "+f.Digest)
+	}
+	if f.ConfigHash != "" {
+		parts = append(parts, "config hash "+f.ConfigHash)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// ---------------------------------------------------------------------------
+// lock verify
+// ---------------------------------------------------------------------------
+
+var lockVerifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check the lock file against installed assets or upstream",
+	Long: `Check duckrow.lock.json (plus the personal local lock).
+
+Without flags, this compares the lock with what is installed in the folder,
+like 'duckrow hook check'.
+
+With --frozen, it checks that the lock is a complete reproducibility
+contract instead: every skill and agent is pinned to a commit and has a
+digest that still matches the content at that commit, and every MCP has a
+config hash that matches the configured registries. Every pinned source is
+fetched, so this needs network access.
+
+MCPs with local overrides (see 'duckrow mcp edit') are noted in both modes,
+but are not issues.
+
+Exits with a non-zero status if any issue is found.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+		frozen, _ := cmd.Flags().GetBool("frozen")
+
+		if !frozen {
+			issues, err := core.CheckLockConsistency(targetDir)
+			if err != nil {
+				return err
+			}
+			if lf, err := core.ReadLayeredLockFile(targetDir); err == nil {
+				printMCPOverrides(lf)
+			}
+			if len(issues) == 0 {
+				fmt.Fprintln(os.Stdout, "Lock file is consistent.")
+				return nil
+			}
+			fmt.Fprintln(os.Stderr, "duckrow: lock file is out of sync with installed assets:")
+			for _, issue := range issues {
+				fmt.Fprintf(os.Stderr, "  - %s\n", issue)
+			}
+			return fmt.Errorf("%d lock issue(s) found", len(issues))
+		}
+
+		lf, err := core.ReadLayeredLockFile(targetDir)
+		if err != nil {
+			return err
+		}
+		if lf == nil {
+			return fmt.Errorf("no lock file found in %s", targetDir)
+		}
+		opts, err := freezeOptions()
+		if err != nil {
+			return err
+		}
+
+		printMCPOverrides(lf)
+		issues := core.NewOrchestrator().VerifyFrozen(lf, opts)
+		if len(issues) == 0 {
+			fmt.Fprintln(os.Stdout, "Lock file is frozen.")
+			return nil
+		}
+		fmt.Fprintln(os.Stderr, "duckrow: lock file is not frozen:")
+		for _, issue := range issues {
+			fmt.Fprintf(os.Stderr, "  - %s\n", issue)
+		}
+		fmt.Fprintln(os.Stderr, "Run 'duckrow lock freeze' to pin unpinned entries; mismatches mean upstream content changed.")
+		return fmt.Errorf("%d lock issue(s) found", len(issues))
+	},
+}
+
+// printMCPOverrides notes the MCPs whose registry definition is overridden
+// with `duckrow mcp edit`. Overrides are not issues, but the configs they
+// produce differ from what the registry (and its config hash) describes.
+func printMCPOverrides(lf *core.LockFile) {
+	for _, m := range core.AssetsByKind(lf, asset.KindMCP) {
+		if o := core.LockedMCPOverrides(m); !o.IsEmpty() {
+			fmt.Fprintf(os.Stdout, "Note: mcp %q has local overrides (%s)\n", m.Name, o)
+		}
+	}
+}
+
+// freezeOptions builds the options for freezing and verifying from the user
+// config: clone URL overrides, ignore patterns, and MCP lookups in the
+// configured registries.
+func freezeOptions() (core.FreezeOptions, error) {
+	d, err := newDeps()
+	if err != nil {
+		return core.FreezeOptions{}, err
+	}
+	cfg, err := d.config.Load()
+	if err != nil {
+		return core.FreezeOptions{}, fmt.Errorf("loading config: %w", err)
+	}
+	rm := core.NewRegistryManager(d.config.RegistriesDir())
+
+	return core.FreezeOptions{
+		CloneURLOverrides: cfg.Settings.CloneURLOverrides,
+		Mirrors:           rm.Mirrors(cfg.Registries),
+		IgnorePatterns:    cfg.Settings.IgnorePatterns,
+		MCPConfigHash: func(locked asset.LockedAsset) (string, error) {
+			registry, _ := locked.Data["registry"].(string)
+			info, err := rm.FindMCP(cfg.Registries, core.LockedUpstreamName(locked), registry)
+			if err != nil {
+				return "", err
+			}
+			meta, ok := info.MCP.Meta.(asset.MCPMeta)
+			if !ok {
+				return "", fmt.Errorf("registry entry has no MCP config")
+			}
+			return core.ComputeConfigHash(meta), nil
+		},
+	}, nil
+}
+
+// ---------------------------------------------------------------------------
+// lock normalize
+// ---------------------------------------------------------------------------
+
+var lockNormalizeCmd = &cobra.Command{
+	Use:   "normalize",
+	Short: "Rewrite the lock file and MCP configs in stable order",
+	Long: `Rewrite duckrow.lock.json, the personal .duckrow/local.lock.json, and the
+MCP config files of every system in the folder in the order duckrow now
+writes them:
+
+  - lock entries sorted by kind, then name; file and env lists sorted
+  - MCP entries in system configs (.mcp.json, .cursor/mcp.json,
+    .vscode/mcp.json, opencode.json) sorted by name
+
+Files written by older versions of duckrow may follow install order instead.
+Run this once and commit the result so later changes produce small diffs.
+Files that are already in order are not touched.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+
+		changed, err := core.NormalizeLockFiles(targetDir)
+		if err != nil {
+			return err
+		}
+
+		for _, sys := range system.All() {
+			n, ok := sys.(interface {
+				NormalizeMCPConfig(projectDir string) (string, bool, error)
+			})
+			if !ok {
+				continue
+			}
+			rel, rewritten, err := n.NormalizeMCPConfig(targetDir)
+			if err != nil {
+				return fmt.Errorf("%s: %w", sys.DisplayName(), err)
+			}
+			if rewritten {
+				changed = append(changed, filepath.ToSlash(rel))
+			}
+		}
+
+		if len(changed) == 0 {
+			fmt.Fprintln(os.Stdout, "Everything is already normalized.")
+			return nil
+		}
+		for _, rel := range changed {
+			fmt.Fprintf(os.Stdout, "Normalized: %s\n", rel)
+		}
+		return nil
+	},
+}
+
+func init() {
+	lockFreezeCmd.Flags().StringP("dir", "d", "", "Project directory (default: current directory)")
+
+	lockVerifyCmd.Flags().StringP("dir", "d", "", "Project directory (default: current directory)")
+	lockVerifyCmd.Flags().Bool("frozen", false, "Fail unless every entry is pinned and still resolves to its recorded digest")
+
+	lockNormalizeCmd.Flags().StringP("dir", "d", "", "Project directory (default: current directory)")
+
+	lockCmd.AddCommand(lockFreezeCmd)
+	lockCmd.AddCommand(lockVerifyCmd)
+	lockCmd.AddCommand(lockNormalizeCmd)
+	rootCmd.AddCommand(lockCmd)
+}
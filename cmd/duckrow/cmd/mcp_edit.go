@@ -0,0 +1,326 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"maps"
+	"os"
+	"os/exec"
+	"slices"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+	"github.com/spf13/cobra"
+	"github.com/tailscale/hujson"
+)
+
+// newMCPEditCommand creates `duckrow mcp edit`.
+func newMCPEditCommand() *cobra.Command {
+	editCmd := &cobra.Command{
+		Use:   "edit <name>",
+		Short: "Override an installed MCP's arguments or environment",
+		Long: `Layer local changes over an installed MCP's registry definition.
+
+Overrides are recorded in the MCP's lock entry (data.overrides), so sync
+writes the same config for everyone using the lock:
+
+  --set-arg     replaces the registry's arguments; repeat it for each one
+  --set-env     gives an environment variable a value; duckrow env passes it
+                to the server when the variable is not set in the
+                environment or an .env.duckrow file
+
+Values end up in the lock file, so keep secrets in .env.duckrow instead.
+
+Without flags on a terminal, the overrides are opened in $VISUAL or $EDITOR.
+System configs that have the MCP are rewritten when its arguments change.`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return runMCPEdit(cmd, args[0])
+		},
+	}
+	editCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
+	editCmd.Flags().StringArray("set-arg", nil, "Replace the registry's arguments (repeat for each argument)")
+	editCmd.Flags().Bool("no-args", false, "Run the server without the registry's arguments")
+	editCmd.Flags().Bool("reset-args", false, "Use the registry's arguments again")
+	editCmd.Flags().StringArray("set-env", nil, "Set an environment variable, as NAME=VALUE (repeatable)")
+	editCmd.Flags().StringArray("unset-env", nil, "Remove an environment variable override (repeatable)")
+	editCmd.Flags().Bool("reset", false, "Remove all overrides")
+	return editCmd
+}
+
+func runMCPEdit(cmd *cobra.Command, name string) error {
+	d, err := newDeps()
+	if err != nil {
+		return err
+	}
+	cfg, err := d.config.Load()
+	if err != nil {
+		return fmt.Errorf("loading config: %w", err)
+	}
+	targetDir, err := resolveTargetDir(cmd)
+	if err != nil {
+		return err
+	}
+
+	lf, err := core.ReadLayeredLockFile(targetDir)
+	if err != nil {
+		return fmt.Errorf("reading lock file: %w", err)
+	}
+	locked := core.FindLockedAsset(lf, asset.KindMCP, name)
+	if locked == nil {
+		return fmt.Errorf("MCP %q is not in the lock file", name)
+	}
+	current := core.LockedMCPOverrides(*locked)
+
+	// The registry definition the overrides are layered over. Without it
+	// the lock can still be edited, but configs cannot be rewritten.
+	registry, _ := locked.Data["registry"].(string)
+	var meta *asset.MCPMeta
+	rm := core.NewRegistryManager(d.config.RegistriesDir())
+	if info, err := rm.FindMCP(cfg.Registries, core.LockedUpstreamName(*locked), registry); err == nil {
+		if m, ok := info.MCP.Meta.(asset.MCPMeta); ok {
+			meta = &m
+		}
+	}
+
+	var next core.MCPOverrides
+	if mcpEditFlagsChanged(cmd) {
+		next, err = applyMCPEditFlags(cmd, current)
+	} else if isInteractive() {
+		next, err = editMCPOverridesInEditor(name, registry, meta, current)
+	} else {
+		return fmt.Errorf("nothing to change: pass --set-arg, --set-env, --unset-env, or --reset (or run on a terminal to open an editor)")
+	}
+	if err != nil {
+		return err
+	}
+
+	argsChanged := !sameArgs(current.Args, next.Args)
+	if !argsChanged && maps.Equal(current.Env, next.Env) {
+		fmt.Fprintf(os.Stdout, "No changes to MCP %q.\n", name)
+		return nil
+	}
+
+	updated := core.WithMCPOverrides(*locked, next)
+	lockName, err := writeLockEntry(targetDir, updated, lf.Origin(asset.KindMCP, name) == core.OriginLocal)
+	if err != nil {
+		return fmt.Errorf("updating lock file: %w", err)
+	}
+	if next.IsEmpty() {
+		fmt.Fprintf(os.Stdout, "Removed overrides of MCP %q.\n", name)
+	} else {
+		fmt.Fprintf(os.Stdout, "Overrides of MCP %q: %s\n", name, next)
+	}
+	fmt.Fprintf(os.Stdout, "Updated %s\n", lockName)
+
+	if argsChanged {
+		if meta == nil {
+			fmt.Fprintf(os.Stderr, "Warning: registry %q is not available, so system configs were not rewritten; run 'duckrow mcp sync --force' once it is.\n", registry)
+		} else {
+			rewriteMCPConfigs(name, next.Apply(*meta), targetDir)
+		}
+	}
+	if len(next.Env) > 0 && !maps.Equal(current.Env, next.Env) {
+		fmt.Fprintln(os.Stdout, "\nEnv values are stored in the lock file; keep secrets in .env.duckrow instead.")
+	}
+	return nil
+}
+
+// mcpEditFlagsChanged reports whether any of the editing flags was passed.
+func mcpEditFlagsChanged(cmd *cobra.Command) bool {
+	for _, flag := range []string{"set-arg", "no-args", "reset-args", "set-env", "unset-env", "reset"} {
+		if cmd.Flags().Changed(flag) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyMCPEditFlags returns current with the editing flags applied.
+func applyMCPEditFlags(cmd *cobra.Command, current core.MCPOverrides) (core.MCPOverrides, error) {
+	setArgs, _ := cmd.Flags().GetStringArray("set-arg")
+	noArgs, _ := cmd.Flags().GetBool("no-args")
+	resetArgs, _ := cmd.Flags().GetBool("reset-args")
+	setEnv, _ := cmd.Flags().GetStringArray("set-env")
+	unsetEnv, _ := cmd.Flags().GetStringArray("unset-env")
+	reset, _ := cmd.Flags().GetBool("reset")
+
+	argFlags := 0
+	for _, set := range []bool{len(setArgs) > 0, noArgs, resetArgs} {
+		if set {
+			argFlags++
+		}
+	}
+	if argFlags > 1 {
+		return core.MCPOverrides{}, fmt.Errorf("--set-arg, --no-args, and --reset-args cannot be combined")
+	}
+
+	next := core.MCPOverrides{Args: slices.Clone(current.Args), Env: maps.Clone(current.Env)}
+	if reset {
+		next = core.MCPOverrides{}
+	}
+	switch {
+	case len(setArgs) > 0:
+		next.Args = setArgs
+	case noArgs:
+		next.Args = []string{}
+	case resetArgs:
+		next.Args = nil
+	}
+	for _, name := range unsetEnv {
+		if _, ok := next.Env[name]; !ok {
+			return core.MCPOverrides{}, fmt.Errorf("no env override named %q", name)
+		}
+		delete(next.Env, name)
+	}
+	for _, kv := range setEnv {
+		name, value, err := core.ParseEnvOverride(kv)
+		if err != nil {
+			return core.MCPOverrides{}, err
+		}
+		if next.Env == nil {
+			next.Env = make(map[string]string)
+		}
+		next.Env[name] = value
+	}
+	if len(next.Env) == 0 {
+		next.Env = nil
+	}
+	return next, nil
+}
+
+// sameArgs reports whether two argument overrides are the same, telling an
+// empty list (no arguments) from nil (the registry's arguments).
+func sameArgs(a, b []string) bool {
+	return (a == nil) == (b == nil) && slices.Equal(a, b)
+}
+
+// rewriteMCPConfigs writes meta to the configs of the systems that already
+// have the MCP.
+func rewriteMCPConfigs(name string, meta asset.MCPMeta, targetDir string) {
+	type mcpChecker interface {
+		HasMCP(name string, projectDir string) bool
+	}
+	a := asset.Asset{Kind: asset.KindMCP, Name: name, Meta: meta}
+	rewrote := false
+	for _, sys := range filterMCPCapable(system.All()) {
+		c, ok := sys.(mcpChecker)
+		if !ok || !c.HasMCP(name, targetDir) {
+			continue
+		}
+		if !rewrote {
+			fmt.Fprintln(os.Stdout, "\nRewrote MCP config in:")
+			rewrote = true
+		}
+		configPath := resolveMCPConfigPathFromSystem(sys, targetDir)
+		if err := sys.Install(a, targetDir, system.InstallOptions{Force: true}); err != nil {
+			fmt.Fprintf(os.Stderr, "  x %-24s error: %s\n", configPath, err.Error())
+			continue
+		}
+		fmt.Fprintf(os.Stdout, "  ~ %-24s (%s)\n", configPath, sys.DisplayName())
+	}
+	if !rewrote {
+		fmt.Fprintf(os.Stdout, "\nNo system config has %q yet; 'duckrow mcp sync' writes it with the overrides.\n", name)
+	}
+}
+
+// mcpOverridesFile is the document edited by `duckrow mcp edit` without
+// flags. A missing "args" keeps the registry's arguments.
+type mcpOverridesFile struct {
+	Args *[]string         `json:"args,omitempty"`
+	Env  map[string]string `json:"env"`
+}
+
+// editMCPOverridesInEditor opens the overrides of MCP name in the user's
+// editor and returns them as saved. meta is the registry definition, if it
+// is available, shown for reference.
+func editMCPOverridesInEditor(name, registry string, meta *asset.MCPMeta, current core.MCPOverrides) (core.MCPOverrides, error) {
+	doc := mcpOverridesFile{Env: current.Env}
+	if current.Args != nil {
+		doc.Args = &current.Args
+	}
+	if doc.Env == nil {
+		doc.Env = map[string]string{}
+	}
+	body, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return core.MCPOverrides{}, fmt.Errorf("encoding overrides: %w", err)
+	}
+
+	var b bytes.Buffer
+	fmt.Fprintf(&b, "// Overrides of MCP %q from registry %q.\n", name, registry)
+	if meta != nil {
+		registryArgs, _ := json.Marshal(meta.Args)
+		fmt.Fprintf(&b, "// Registry args: %s\n", registryArgs)
+		if required := core.ExtractRequiredEnv(meta.Env); len(required) > 0 {
+			fmt.Fprintf(&b, "// Registry env:  %s\n", strings.Join(required, ", "))
+		}
+	}
+	b.WriteString("//\n")
+	b.WriteString("// \"args\" replaces the registry's arguments; leave it out to keep them.\n")
+	b.WriteString("// \"env\" values are used when a variable is not set in the environment or\n")
+	b.WriteString("// an .env.duckrow file. They are stored in the lock file: no secrets.\n")
+	b.Write(body)
+	b.WriteByte('\n')
+
+	f, err := os.CreateTemp("", "duckrow-mcp-*.jsonc")
+	if err != nil {
+		return core.MCPOverrides{}, fmt.Errorf("creating temp file: %w", err)
+	}
+	path := f.Name()
+	defer func() { _ = os.Remove(path) }()
+	if _, err := f.Write(b.Bytes()); err != nil {
+		_ = f.Close()
+		return core.MCPOverrides{}, fmt.Errorf("writing temp file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return core.MCPOverrides{}, fmt.Errorf("writing temp file: %w", err)
+	}
+
+	editor := strings.Fields(editorCommand())
+	c := exec.Command(editor[0], append(editor[1:], path)...)
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := c.Run(); err != nil {
+		return core.MCPOverrides{}, fmt.Errorf("running editor: %w", err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return core.MCPOverrides{}, fmt.Errorf("reading edited overrides: %w", err)
+	}
+	data, err = hujson.Standardize(data)
+	if err != nil {
+		return core.MCPOverrides{}, fmt.Errorf("parsing edited overrides: %w", err)
+	}
+	var edited mcpOverridesFile
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.DisallowUnknownFields()
+	if err := dec.Decode(&edited); err != nil {
+		return core.MCPOverrides{}, fmt.Errorf("parsing edited overrides: %w", err)
+	}
+
+	var next core.MCPOverrides
+	if edited.Args != nil {
+		next.Args = *edited.Args
+		if next.Args == nil {
+			next.Args = []string{}
+		}
+	}
+	if len(edited.Env) > 0 {
+		next.Env = edited.Env
+	}
+	return next, nil
+}
+
+// editorCommand returns the user's editor: $VISUAL, then $EDITOR, then vi.
+func editorCommand() string {
+	for _, env := range []string{"VISUAL", "EDITOR"} {
+		if e := strings.TrimSpace(os.Getenv(env)); e != "" {
+			return e
+		}
+	}
+	return "vi"
+}
@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/spf13/cobra"
+)
+
+// notifySettings are the desktopNotifications and notifyWebhookURL
+// settings, set by applySettings.
+var notifySettings struct {
+	desktop bool
+	webhook string
+}
+
+// notifyDone sends the notifications the settings ask for once a
+// long-running operation (update --all, sync) has finished in targetDir,
+// with summary as the message. Dry runs send none. A notification that
+// can't be sent is a warning, not a failure of the operation.
+func notifyDone(cmd *cobra.Command, targetDir, summary string, failed bool) {
+	if !notifySettings.desktop && notifySettings.webhook == "" {
+		return
+	}
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		return
+	}
+
+	n := core.Notification{
+		Title:   cmd.CommandPath(),
+		Message: fmt.Sprintf("%s: %s", filepath.Base(targetDir), summary),
+		Failed:  failed,
+	}
+	if notifySettings.desktop {
+		if err := core.NotifyDesktop(n); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+	if notifySettings.webhook != "" {
+		if err := core.PostWebhook(notifySettings.webhook, n); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		}
+	}
+}
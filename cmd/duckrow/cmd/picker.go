@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/tui"
+	"golang.org/x/term"
+)
+
+// isInteractive reports whether both stdin and stdout are attached to a
+// terminal, i.e. it's safe to show an interactive prompt.
+func isInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// pickRegistryAsset shows an inline picker of registry entries for the given
+// kind. It is used when `<kind> install` is run without arguments. Entries
+// the project in targetDir excludes are left out.
+// Returns nil (and no error) if the user cancels.
+func pickRegistryAsset(d *deps, cfg *core.Config, kind asset.Kind, registryFilter, targetDir string) (*core.RegistryAssetInfo, error) {
+	handler, _ := asset.Get(kind)
+	lower := strings.ToLower(handler.DisplayName())
+
+	if !isInteractive() {
+		return nil, fmt.Errorf("missing %s source or name (run on a terminal to pick from registries interactively)", lower)
+	}
+
+	if len(cfg.Registries) == 0 {
+		return nil, fmt.Errorf("no registries configured; add one with 'duckrow registry add <url>' or pass a %s source", lower)
+	}
+
+	excludes, err := core.ProjectExcludes(targetDir)
+	if err != nil {
+		return nil, err
+	}
+
+	rm := core.NewRegistryManager(d.config.RegistriesDir())
+	var candidates []core.RegistryAssetInfo
+	for _, info := range excludes.Filter(rm.ListAssets(cfg.Registries, kind)) {
+		if registryFilter != "" && info.RegistryName != registryFilter && info.RegistryRepo != registryFilter {
+			continue
+		}
+		candidates = append(candidates, info)
+	}
+	if len(candidates) == 0 {
+		return nil, fmt.Errorf("no %ss found in registries", lower)
+	}
+
+	return tui.PickRegistryAsset(fmt.Sprintf("Select a %s to install", lower), candidates)
+}
@@ -1,9 +1,15 @@
 package cmd
 
 import (
+	"bufio"
+	"encoding/json"
 	"fmt"
 	"os"
+	"path/filepath"
+	"strconv"
 	"strings"
+	"text/tabwriter"
+	"time"
 
 	"github.com/barysiuk/duckrow/internal/core"
 	"github.com/barysiuk/duckrow/internal/core/asset"
@@ -17,10 +23,30 @@ var registryCmd = &cobra.Command{
 }
 
 var registryAddCmd = &cobra.Command{
-	Use:   "add <repo-url>",
+	Use:   "add <repo-url|path>",
 	Short: "Add a skill registry",
 	Long: `Add a private skill registry by cloning its git repository.
-The repository must contain a duckrow.json manifest at its root.`,
+The repository must contain a duckrow.json (or duckrow.yaml) manifest at its root.
+
+An http(s) URL of a manifest file (ending in .json, .yaml or .yml), such
+as one served from S3 or raw.githubusercontent.com, is downloaded instead
+of cloned; 'duckrow registry refresh' downloads it again only when it
+changed. Private files on raw.githubusercontent.com use GITHUB_TOKEN or
+GH_TOKEN.
+
+For a private HTTPS registry in CI, --token-env names the environment
+variable holding a token to clone with (e.g. GH_TOKEN), sent as the basic
+auth password, or as a bearer token with --auth-type bearer. It is saved as
+the registry's auth and also used for skill sources under the same host
+and owner.
+
+A path (absolute, or starting with ./, ../ or ~/) registers a registry
+directory on disk without cloning it. Local registries are live: edits to
+their manifest show up right away, without 'duckrow registry refresh'.
+
+If the manifest lists recommended assets, duckrow offers to install them
+into the current folder (or --dir) right away. They are installed all
+together: if one fails, the others are removed again.`,
 	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		d, err := newDeps()
@@ -33,24 +59,57 @@ The repository must contain a duckrow.json manifest at its root.`,
 			return fmt.Errorf("loading config: %w", err)
 		}
 
+		auth, err := registryAuthFlags(cmd, args[0])
+		if err != nil {
+			return err
+		}
+		if auth != nil {
+			// Clone with the token before it is saved.
+			core.SetRegistryAuth(append(cfg.Registries, core.Registry{Name: args[0], Repo: args[0], Auth: auth}))
+		}
+
 		rm := core.NewRegistryManager(d.config.RegistriesDir())
-		manifest, err := rm.Add(args[0])
+		repo, local := args[0], core.IsLocalRegistryPath(args[0])
+		var manifest *core.RegistryManifest
+		if local {
+			repo, manifest, err = rm.AddLocal(args[0])
+		} else {
+			manifest, err = rm.Add(args[0])
+		}
 		if err != nil {
 			return err
 		}
 
 		// Check if registry with same repo already exists in config
-		for _, r := range cfg.Registries {
-			if r.Repo == args[0] {
+		for i, r := range cfg.Registries {
+			if r.Repo == repo {
+				if r.Local != local || auth != nil {
+					cfg.Registries[i].Local = local
+					if auth != nil {
+						cfg.Registries[i].Auth = auth
+					}
+					if err := d.config.Save(cfg); err != nil {
+						return fmt.Errorf("saving config: %w", err)
+					}
+				}
 				fmt.Fprintf(os.Stdout, "Updated registry: %s (%s)\n", manifest.Name, registrySummary(manifest))
-				return nil
+				return offerRecommended(cmd, d, cfg, manifest, repo)
+			}
+		}
+
+		for _, r := range cfg.Registries {
+			if r.Alias == manifest.Name {
+				fmt.Fprintf(os.Stderr, "Warning: %q is also the alias of registry %s; use the repo URL to refer to the new registry by name\n",
+					manifest.Name, r.Name)
 			}
 		}
 
 		// Add to config
 		cfg.Registries = append(cfg.Registries, core.Registry{
-			Name: manifest.Name,
-			Repo: args[0],
+			Name:  manifest.Name,
+			Repo:  repo,
+			Local: local,
+			Auth:  auth,
 		})
 
 		if err := d.config.Save(cfg); err != nil {
@@ -62,10 +121,126 @@ The repository must contain a duckrow.json manifest at its root.`,
 			fmt.Fprintf(os.Stdout, "  %s\n", manifest.Description)
 		}
 		printManifestWarnings(manifest)
-		return nil
+		return offerRecommended(cmd, d, cfg, manifest, repo)
 	},
 }
 
+// registryAuthFlags returns the auth of the registry at repo given with
+// --token-env and --auth-type, or nil when there is none.
+func registryAuthFlags(cmd *cobra.Command, repo string) (*core.RegistryAuth, error) {
+	tokenEnv, _ := cmd.Flags().GetString("token-env")
+	authType, _ := cmd.Flags().GetString("auth-type")
+	if tokenEnv == "" {
+		if authType != "" {
+			return nil, fmt.Errorf("--auth-type requires --token-env")
+		}
+		return nil, nil
+	}
+	if core.IsLocalRegistryPath(repo) {
+		return nil, fmt.Errorf("--token-env cannot be used with a local registry")
+	}
+	auth := &core.RegistryAuth{TokenEnv: tokenEnv, Type: authType}
+	if err := auth.Validate(); err != nil {
+		return nil, err
+	}
+	return auth, nil
+}
+
+// offerRecommended offers to install the assets a newly added registry
+// recommends into the target directory, all or none of them. With
+// --recommended they are installed without asking; otherwise the user is
+// asked on a terminal, and outside one they are only listed.
+func offerRecommended(cmd *cobra.Command, d *deps, cfg *core.Config, manifest *core.RegistryManifest, repo string) error {
+	install, _ := cmd.Flags().GetBool("recommended")
+	skip, _ := cmd.Flags().GetBool("no-recommended")
+	if install && skip {
+		return fmt.Errorf("--recommended cannot be used with --no-recommended")
+	}
+	if skip {
+		return nil
+	}
+
+	parsed, err := core.ParseManifest(manifest)
+	if err != nil {
+		return err
+	}
+	recommended := core.RecommendedAssets(parsed, repo)
+	if len(recommended) == 0 {
+		if install {
+			fmt.Fprintf(os.Stdout, "Registry %s recommends nothing to install.\n", parsed.Name)
+		}
+		return nil
+	}
+
+	targetDir, err := resolveTargetDir(cmd)
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stdout, "\n%s recommends:\n", parsed.Name)
+	for _, r := range recommended {
+		fmt.Fprintf(os.Stdout, "  %-6s %s\n", r.Kind, r.Entry.Name)
+	}
+	if !install {
+		if !isInteractive() {
+			fmt.Fprintf(os.Stdout, "Install them with: duckrow registry add %s --recommended\n", repo)
+			return nil
+		}
+		fmt.Fprintf(os.Stderr, "Install them into %s? [y/N] ", targetDir)
+		answer, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+		answer = strings.ToLower(strings.TrimSpace(answer))
+		if answer != "y" && answer != "yes" {
+			return nil
+		}
+	}
+	fmt.Fprintln(os.Stdout)
+
+	targetSystems, err := resolveTargetSystems(cmd)
+	if err != nil {
+		return err
+	}
+	results, err := core.NewOrchestrator().InstallRecommended(recommended, core.RecommendedInstallOptions{
+		TargetDir:         targetDir,
+		TargetSystems:     targetSystems,
+		IgnorePatterns:    cfg.Settings.IgnorePatterns,
+		CloneURLOverrides: cfg.Settings.CloneURLOverrides,
+	})
+	if err != nil {
+		return withConflictHint(err)
+	}
+
+	printRecommendedResults(results, targetDir)
+	return nil
+}
+
+// printRecommendedResults reports the outcome of InstallRecommended: what
+// was installed or skipped, the env vars installed MCPs need, and post-
+// install messages.
+func printRecommendedResults(results []core.RecommendedResult, targetDir string) {
+	installed := 0
+	envMap := make(map[string][]string)
+	for _, r := range results {
+		if r.Skipped != "" {
+			fmt.Fprintf(os.Stdout, "Skipped: %s (%s)\n", r.Asset.Entry.Name, r.Skipped)
+			continue
+		}
+		installed++
+		fmt.Fprintf(os.Stdout, "Installed: %s (%s)\n", r.Asset.Entry.Name, r.Asset.Kind)
+		for _, v := range r.RequiredEnv {
+			envMap[v] = append(envMap[v], r.Asset.Entry.Name)
+		}
+	}
+	if installed > 0 {
+		fmt.Fprintf(os.Stdout, "\nUpdated %s\n", filepath.Base(core.LockFilePath(targetDir)))
+	}
+	printRequiredEnvSummary(envMap)
+	for _, r := range results {
+		if r.Skipped == "" {
+			printPostInstallMessage(r.Asset.Entry.PostInstallMessage)
+		}
+	}
+}
+
 var registryListCmd = &cobra.Command{
 	Use:   "list",
 	Short: "List configured registries",
@@ -94,13 +269,13 @@ var registryListCmd = &cobra.Command{
 		for _, reg := range cfg.Registries {
 			manifest, err := rm.LoadManifest(reg.Repo)
 			if err != nil {
-				fmt.Fprintf(os.Stdout, "  %s  %s  (error: %v)\n", reg.Name, reg.Repo, err)
+				fmt.Fprintf(os.Stdout, "  %s  %s  (error: %v)\n", registryLabel(reg), reg.Repo, err)
 				continue
 			}
 
 			parsed, parseErr := core.ParseManifest(manifest)
 			if parseErr != nil {
-				fmt.Fprintf(os.Stdout, "  %s  %s  (parse error: %v)\n", reg.Name, reg.Repo, parseErr)
+				fmt.Fprintf(os.Stdout, "  %s  %s  (parse error: %v)\n", registryLabel(reg), reg.Repo, parseErr)
 				continue
 			}
 
@@ -109,6 +284,8 @@ var registryListCmd = &cobra.Command{
 			skills := parsed.Entries[asset.KindSkill]
 			mcps := parsed.Entries[asset.KindMCP]
 			agents := parsed.Entries[asset.KindAgent]
+			commands := parsed.Entries[asset.KindCommand]
+			rules := parsed.Entries[asset.KindRule]
 			if len(skills) > 0 {
 				parts = append(parts, fmt.Sprintf("%d skills", len(skills)))
 			}
@@ -118,12 +295,18 @@ var registryListCmd = &cobra.Command{
 			if len(agents) > 0 {
 				parts = append(parts, fmt.Sprintf("%d agents", len(agents)))
 			}
+			if len(commands) > 0 {
+				parts = append(parts, fmt.Sprintf("%d commands", len(commands)))
+			}
+			if len(rules) > 0 {
+				parts = append(parts, fmt.Sprintf("%d rules", len(rules)))
+			}
 			summary := "empty"
 			if len(parts) > 0 {
 				summary = strings.Join(parts, ", ")
 			}
 
-			fmt.Fprintf(os.Stdout, "  %s  %s  (%s)\n", reg.Name, reg.Repo, summary)
+			fmt.Fprintf(os.Stdout, "  %s  %s  (%s)\n", registryLabel(reg), reg.Repo, summary)
 
 			if verbose {
 				if len(skills) > 0 {
@@ -144,6 +327,18 @@ var registryListCmd = &cobra.Command{
 						fmt.Fprintf(os.Stdout, "      - %s: %s\n", a.Name, a.Description)
 					}
 				}
+				if len(commands) > 0 {
+					fmt.Fprintln(os.Stdout, "    Commands:")
+					for _, c := range commands {
+						fmt.Fprintf(os.Stdout, "      - %s: %s\n", c.Name, c.Description)
+					}
+				}
+				if len(rules) > 0 {
+					fmt.Fprintln(os.Stdout, "    Rules:")
+					for _, r := range rules {
+						fmt.Fprintf(os.Stdout, "      - %s: %s\n", r.Name, r.Description)
+					}
+				}
 			}
 		}
 		return nil
@@ -166,6 +361,11 @@ var registryRefreshCmd = &cobra.Command{
 			return fmt.Errorf("loading config: %w", err)
 		}
 
+		if core.Offline() {
+			fmt.Fprintln(os.Stderr, "Offline: skipping registry refresh; using local clones.")
+			return nil
+		}
+
 		rm := core.NewRegistryManager(d.config.RegistriesDir())
 
 		if len(args) > 0 {
@@ -202,11 +402,367 @@ var registryRefreshCmd = &cobra.Command{
 	},
 }
 
+var registryHydrateCmd = &cobra.Command{
+	Use:   "hydrate [name-or-repo]",
+	Short: "Resolve latest commits for unpinned registry entries",
+	Long: `Resolve the latest commit of every unpinned skill and agent in a registry
+and cache it as the "available" commit used by outdated and update. Caches
+younger than the commitCacheTTLMinutes setting are reused unless --force is
+given. If no argument is given, all registries are hydrated.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d, err := newDeps()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := d.config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		registries := cfg.Registries
+		if len(args) > 0 {
+			reg, err := findRegistry(cfg.Registries, args[0])
+			if err != nil {
+				return err
+			}
+			registries = []core.Registry{*reg}
+		}
+		if len(registries) == 0 {
+			fmt.Fprintln(os.Stdout, "No registries configured.")
+			return nil
+		}
+
+		force, _ := cmd.Flags().GetBool("force")
+		rm := core.NewRegistryManager(d.config.RegistriesDir())
+		for _, res := range rm.Hydrate(registries, hydrateOptions(cfg, force)) {
+			switch {
+			case res.Unpinned == 0:
+				fmt.Fprintf(os.Stdout, "%s: all entries pinned\n", res.Registry.Name)
+			case res.Disabled:
+				fmt.Fprintf(os.Stdout, "%s: hydration disabled (%d unpinned)\n", res.Registry.Name, res.Unpinned)
+			case res.Offline:
+				fmt.Fprintf(os.Stdout, "%s: offline; keeping cached commits\n", res.Registry.Name)
+			case res.Fresh:
+				cachedAt, _ := rm.CommitCacheTime(res.Registry.Repo)
+				fmt.Fprintf(os.Stdout, "%s: cache is fresh (%s); use --force to re-resolve\n", res.Registry.Name, formatAge(cachedAt))
+			default:
+				fmt.Fprintf(os.Stdout, "%s: resolved %d of %d unpinned commit(s)", res.Registry.Name, res.Resolved, res.Unpinned)
+				if res.Skipped > 0 {
+					fmt.Fprintf(os.Stdout, ", %d opted out", res.Skipped)
+				}
+				fmt.Fprintln(os.Stdout)
+			}
+			for _, repo := range res.Failed {
+				fmt.Fprintf(os.Stderr, "Warning: %s: could not clone %s\n", res.Registry.Name, repo)
+			}
+		}
+		return nil
+	},
+}
+
+var registryStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show registry health",
+	Long:  `Show each configured registry with the number of manifest warnings found on the last add or refresh, and the age of its hydrated commit cache.`,
+	Args:  cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d, err := newDeps()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := d.config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		if len(cfg.Registries) == 0 {
+			fmt.Fprintln(os.Stdout, "No registries configured. Use 'duckrow registry add <url>' to add one.")
+			return nil
+		}
+
+		rm := core.NewRegistryManager(d.config.RegistriesDir())
+
+		ttl := cfg.Settings.CommitCacheTTL()
+
+		t := newTable(os.Stdout, "Registry", "Repo", "Warnings", "Commits")
+		for _, reg := range cfg.Registries {
+			warnings, err := rm.Warnings(reg.Repo)
+			if err != nil {
+				t.row(registryLabel(reg), reg.Repo, fmt.Sprintf("(error: %v)", err))
+				continue
+			}
+			t.row(registryLabel(reg), reg.Repo, strconv.Itoa(len(warnings)), commitCacheStatus(rm, reg, ttl))
+		}
+		return t.flush()
+	},
+}
+
+var registryWarningsCmd = &cobra.Command{
+	Use:   "warnings <name-or-repo>",
+	Short: "Show manifest warnings for a registry",
+	Long:  `Print the validation warnings recorded for a registry's duckrow.json manifest. Accepts a registry name or repo URL.`,
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d, err := newDeps()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := d.config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		reg, err := findRegistry(cfg.Registries, args[0])
+		if err != nil {
+			return err
+		}
+
+		rm := core.NewRegistryManager(d.config.RegistriesDir())
+		warnings, err := rm.Warnings(reg.Repo)
+		if err != nil {
+			return err
+		}
+
+		if len(warnings) == 0 {
+			fmt.Fprintf(os.Stdout, "No warnings for %s.\n", reg.Name)
+			return nil
+		}
+
+		fmt.Fprintf(os.Stdout, "Warnings for %s (%d):\n", reg.Name, len(warnings))
+		for _, w := range warnings {
+			fmt.Fprintf(os.Stdout, "  - %s\n", w)
+		}
+		return nil
+	},
+}
+
+var registryLintCmd = &cobra.Command{
+	Use:   "lint [path-or-url]",
+	Short: "Check a registry manifest before publishing it",
+	Long: `Check a registry manifest more thoroughly than 'registry add' does. The
+argument is a registry directory or manifest file on disk, a hosted manifest
+URL, or a git URL; it defaults to the current directory.
+
+Errors:
+  - the manifest doesn't parse or has no name
+  - two entries of the same kind share a name
+  - a source isn't in host/owner/repo/path form, or its repository
+    can't be reached (skipped with --offline)
+  - an MCP has no command or url, both, an invalid url, or an unknown
+    transport (http, sse, or streamable-http)
+
+Warnings: entries not pinned to a commit, and everything 'registry warnings'
+reports. Use --json to read the results in CI.
+
+Exits with a non-zero status if any errors are found.`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d, err := newDeps()
+		if err != nil {
+			return err
+		}
+		cfg, err := d.config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		location := "."
+		if len(args) > 0 {
+			location = args[0]
+		}
+		res, err := core.LintRegistry(location, cfg.Settings.CloneURLOverrides)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			data, err := json.MarshalIndent(res, "", "  ")
+			if err != nil {
+				return fmt.Errorf("marshaling JSON: %w", err)
+			}
+			fmt.Fprintln(os.Stdout, string(data))
+		} else {
+			printLintResult(res)
+		}
+
+		if n := res.Errors(); n > 0 {
+			return fmt.Errorf("%d registry manifest error(s) found", n)
+		}
+		return nil
+	},
+}
+
+// printLintResult writes one line per lint issue, its severity first,
+// followed by a count.
+func printLintResult(res *core.LintResult) {
+	for _, issue := range res.Issues {
+		fmt.Fprintf(os.Stdout, "%-8s %s\n", issue.Severity, issue.Message)
+	}
+	if !res.SourcesChecked {
+		fmt.Fprintln(os.Stdout, "Offline: source repositories were not checked.")
+	}
+	errors := res.Errors()
+	warnings := len(res.Issues) - errors
+	if errors == 0 && warnings == 0 {
+		fmt.Fprintf(os.Stdout, "No problems found in %s.\n", res.Name)
+		return
+	}
+	fmt.Fprintf(os.Stdout, "%d error(s), %d warning(s)\n", errors, warnings)
+}
+
+var registryDedupeReportCmd = &cobra.Command{
+	Use:   "dedupe-report",
+	Short: "Find skills and agents indexed by more than one registry",
+	Long: `Report entries in different registries that point at the same upstream
+source, possibly under different names. Sources pinned or hydrated to
+different commits are flagged as skew, which helps teams consolidate
+registries that index the same upstream independently.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d, err := newDeps()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := d.config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		rm := core.NewRegistryManager(d.config.RegistriesDir())
+		dups := rm.DuplicateSources(cfg.Registries)
+
+		if jsonOutput, _ := cmd.Flags().GetBool("json"); jsonOutput {
+			if dups == nil {
+				dups = []core.DuplicateSource{}
+			}
+			data, err := json.MarshalIndent(dups, "", "  ")
+			if err != nil {
+				return fmt.Errorf("marshaling JSON: %w", err)
+			}
+			fmt.Fprintln(os.Stdout, string(data))
+			return nil
+		}
+
+		if len(dups) == 0 {
+			fmt.Fprintln(os.Stdout, "No sources are indexed by more than one registry.")
+			return nil
+		}
+
+		skewed := 0
+		for _, dup := range dups {
+			fmt.Fprintf(os.Stdout, "%s (%s)\n", dup.Source, dup.Kind)
+			w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+			for _, e := range dup.Entries {
+				commit := "(unresolved)"
+				if e.Commit != "" {
+					commit = core.TruncateCommit(e.Commit)
+					if e.Pinned {
+						commit += " (pinned)"
+					}
+				}
+				fmt.Fprintf(w, "  %s\t%s\t%s\n", e.RegistryName, e.Name, commit)
+			}
+			_ = w.Flush()
+			if dup.Skew {
+				skewed++
+				mark := "!"
+				if accessibleOutput {
+					mark = "Warning:"
+				}
+				fmt.Fprintf(os.Stdout, "  %s commit skew: %d different commits\n", mark, len(dup.Commits()))
+			}
+			fmt.Fprintln(os.Stdout)
+		}
+		fmt.Fprintf(os.Stdout, "%d duplicated source(s), %d with commit skew.\n", len(dups), skewed)
+		return nil
+	},
+}
+
+var registryAliasCmd = &cobra.Command{
+	Use:   "alias <name-or-repo> [alias]",
+	Short: "Give a registry a short name",
+	Long: `Give a registry a short alias that can be used wherever a registry name is
+accepted: in --registry flags, in registry commands, and as the registry part
+of "<registry>/<name>" when installing (e.g. duckrow skill install a/go-review).
+
+Aliases use lowercase letters, digits, and hyphens, and must not be the name
+or alias of another registry. Setting an alias replaces the previous one;
+--remove clears it.`,
+	Args: cobra.RangeArgs(1, 2),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d, err := newDeps()
+		if err != nil {
+			return err
+		}
+
+		cfg, err := d.config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+
+		found, err := findRegistry(cfg.Registries, args[0])
+		if err != nil {
+			return err
+		}
+		// findRegistry may return a copy; edit the config's entry.
+		var reg *core.Registry
+		for i := range cfg.Registries {
+			if cfg.Registries[i].Repo == found.Repo {
+				reg = &cfg.Registries[i]
+			}
+		}
+
+		remove, _ := cmd.Flags().GetBool("remove")
+		switch {
+		case remove && len(args) == 2:
+			return fmt.Errorf("--remove takes no alias")
+		case remove:
+			if reg.Alias == "" {
+				fmt.Fprintf(os.Stdout, "Registry %s has no alias\n", reg.Name)
+				return nil
+			}
+			old := reg.Alias
+			reg.Alias = ""
+			if err := d.config.Save(cfg); err != nil {
+				return fmt.Errorf("saving config: %w", err)
+			}
+			fmt.Fprintf(os.Stdout, "Removed alias %s from registry %s\n", old, reg.Name)
+			return nil
+		case len(args) == 1:
+			if reg.Alias == "" {
+				fmt.Fprintf(os.Stdout, "Registry %s has no alias\n", reg.Name)
+			} else {
+				fmt.Fprintln(os.Stdout, reg.Alias)
+			}
+			return nil
+		}
+
+		alias := args[1]
+		if err := core.ValidateRegistryAlias(cfg.Registries, reg.Repo, alias); err != nil {
+			return err
+		}
+		reg.Alias = alias
+		if err := d.config.Save(cfg); err != nil {
+			return fmt.Errorf("saving config: %w", err)
+		}
+		fmt.Fprintf(os.Stdout, "Registry %s is now also %s\n", reg.Name, alias)
+		return nil
+	},
+}
+
 var registryRemoveCmd = &cobra.Command{
 	Use:   "remove <name-or-repo>",
 	Short: "Remove a registry",
-	Long:  `Remove a registry from the config and delete its local clone. Accepts a registry name or repo URL.`,
-	Args:  cobra.ExactArgs(1),
+	Long: `Remove a registry from the config and delete its local clone. Accepts a registry name or repo URL.
+
+With --purge, every skill, agent, and MCP installed from the registry in the
+target directory is uninstalled first (see 'duckrow uninstall --registry').`,
+	Args: cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
 		d, err := newDeps()
 		if err != nil {
@@ -224,6 +780,23 @@ var registryRemoveCmd = &cobra.Command{
 			return err
 		}
 
+		// Uninstall the registry's assets while its manifest is still on disk.
+		if purge, _ := cmd.Flags().GetBool("purge"); purge {
+			targetDir, err := resolveTargetDir(cmd)
+			if err != nil {
+				return err
+			}
+			rm := core.NewRegistryManager(d.config.RegistriesDir())
+			manifest, err := rm.LoadManifest(reg.Repo)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: could not read registry manifest; only MCPs will be matched: %v\n", err)
+			}
+			if err := uninstallFromRegistry(targetDir, *reg, manifest, false, false); err != nil {
+				return err
+			}
+			fmt.Fprintln(os.Stdout)
+		}
+
 		// Remove from config (match by repo URL for precision)
 		registries := make([]core.Registry, 0, len(cfg.Registries))
 		for _, r := range cfg.Registries {
@@ -250,8 +823,8 @@ var registryRemoveCmd = &cobra.Command{
 	},
 }
 
-// findRegistry resolves a registry argument (name or repo URL) to a single Registry.
-// If the argument matches a repo URL exactly, that registry is returned.
+// findRegistry resolves a registry argument (name, alias, or repo URL) to a single Registry.
+// If the argument matches a repo URL or an alias exactly, that registry is returned.
 // If it matches a name and only one registry has that name, it is returned.
 // If multiple registries share the name, an error lists the repo URLs.
 func findRegistry(registries []core.Registry, arg string) (*core.Registry, error) {
@@ -262,6 +835,13 @@ func findRegistry(registries []core.Registry, arg string) (*core.Registry, error
 		}
 	}
 
+	// Aliases are unique (see core.ValidateRegistryAlias)
+	for i := range registries {
+		if registries[i].Alias == arg {
+			return &registries[i], nil
+		}
+	}
+
 	// Try name match
 	var matches []core.Registry
 	for _, r := range registries {
@@ -285,6 +865,29 @@ func findRegistry(registries []core.Registry, arg string) (*core.Registry, error
 	}
 }
 
+// registryLabel returns a registry's name, followed by its alias if it has
+// one, e.g. "acme-platform-skills (a)".
+func registryLabel(reg core.Registry) string {
+	if reg.Alias == "" {
+		return reg.Name
+	}
+	return fmt.Sprintf("%s (%s)", reg.Name, reg.Alias)
+}
+
+// commitCacheStatus describes the age of a registry's hydrated commit cache,
+// marking caches older than ttl as stale.
+func commitCacheStatus(rm *core.RegistryManager, reg core.Registry, ttl time.Duration) string {
+	cachedAt, ok := rm.CommitCacheTime(reg.Repo)
+	if !ok {
+		return "not hydrated"
+	}
+	status := formatAge(cachedAt)
+	if time.Since(cachedAt) >= ttl {
+		status += " (stale)"
+	}
+	return status
+}
+
 // printManifestWarnings prints any validation warnings from a registry manifest to stderr.
 func printManifestWarnings(manifest *core.RegistryManifest) {
 	for _, w := range manifest.Warnings {
@@ -305,6 +908,9 @@ func registrySummary(manifest *core.RegistryManifest) string {
 	if len(manifest.Agents) > 0 {
 		parts = append(parts, fmt.Sprintf("%d agents", len(manifest.Agents)))
 	}
+	if len(manifest.Commands) > 0 {
+		parts = append(parts, fmt.Sprintf("%d commands", len(manifest.Commands)))
+	}
 	if len(parts) == 0 {
 		return "empty"
 	}
@@ -312,10 +918,28 @@ func registrySummary(manifest *core.RegistryManifest) string {
 }
 
 func init() {
+	registryAddCmd.Flags().Bool("recommended", false, "Install the registry's recommended assets without asking")
+	registryAddCmd.Flags().Bool("no-recommended", false, "Don't offer to install the registry's recommended assets")
+	registryAddCmd.Flags().StringP("dir", "d", "", "Directory to install recommended assets into (default: current directory)")
+	registryAddCmd.Flags().String("token-env", "", "Environment variable holding a token to clone the registry with, e.g. GH_TOKEN")
+	registryAddCmd.Flags().String("auth-type", "", "How the token is sent: https-basic (default) or bearer")
+	addSystemsFlag(registryAddCmd)
 	registryListCmd.Flags().BoolP("verbose", "v", false, "Show skills and MCPs in each registry")
+	registryDedupeReportCmd.Flags().Bool("json", false, "Output as JSON")
+	registryLintCmd.Flags().Bool("json", false, "Output as JSON")
+	registryHydrateCmd.Flags().Bool("force", false, "Re-resolve commits even if the cache is fresh")
+	registryAliasCmd.Flags().Bool("remove", false, "Clear the registry's alias")
+	registryRemoveCmd.Flags().Bool("purge", false, "Also uninstall everything installed from the registry")
+	registryRemoveCmd.Flags().StringP("dir", "d", "", "Directory to purge (default: current directory)")
 	registryCmd.AddCommand(registryAddCmd)
+	registryCmd.AddCommand(registryAliasCmd)
+	registryCmd.AddCommand(registryDedupeReportCmd)
+	registryCmd.AddCommand(registryHydrateCmd)
+	registryCmd.AddCommand(registryLintCmd)
 	registryCmd.AddCommand(registryListCmd)
 	registryCmd.AddCommand(registryRefreshCmd)
 	registryCmd.AddCommand(registryRemoveCmd)
+	registryCmd.AddCommand(registryStatusCmd)
+	registryCmd.AddCommand(registryWarningsCmd)
 	rootCmd.AddCommand(registryCmd)
 }
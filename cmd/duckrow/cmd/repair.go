@@ -0,0 +1,90 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var repairCmd = &cobra.Command{
+	Use:   "repair",
+	Short: "Fix broken or stale skill links in system directories",
+	Long: `Fix skill entries in system directories (.claude/skills, .cursor/skills, ...)
+that no longer match the canonical copy in .agents/skills.
+
+Broken symlinks to skills that are in the lock file and still installed are
+relinked; broken symlinks to skills that are not in the lock file are
+removed. Copies of locked skills (made where symlinks are unavailable) that
+differ from the canonical copy are replaced. Broken links to locked skills
+whose canonical copy is missing are left for 'duckrow sync' to restore.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		orch := core.NewOrchestrator()
+		issues, err := orch.ScanLinks(targetDir)
+		if err != nil {
+			return fmt.Errorf("scanning links: %w", err)
+		}
+		if len(issues) == 0 {
+			fmt.Fprintln(os.Stdout, "No broken or stale skill links.")
+			return nil
+		}
+
+		if dryRun {
+			for _, issue := range issues {
+				fmt.Fprintf(os.Stdout, "Would %s\n", describeLinkFix(issue))
+			}
+			return nil
+		}
+
+		fixed, err := orch.RepairLinks(targetDir, issues)
+		for _, issue := range fixed {
+			switch issue.Fix {
+			case core.FixRelink:
+				fmt.Fprintf(os.Stdout, "Relinked: %s\n", issue.Path)
+			case core.FixPrune:
+				fmt.Fprintf(os.Stdout, "Pruned: %s\n", issue.Path)
+			}
+		}
+		if err != nil {
+			return err
+		}
+
+		needSync := 0
+		for _, issue := range issues {
+			if issue.Fix == core.FixSync {
+				fmt.Fprintf(os.Stderr, "Skipped: %s: %s is not installed\n", issue.Path, issue.Name)
+				needSync++
+			}
+		}
+		if needSync > 0 {
+			fmt.Fprintln(os.Stderr, "Run 'duckrow sync' to reinstall the skipped skills.")
+		}
+		return nil
+	},
+}
+
+// describeLinkFix says what repair does about an issue, e.g.
+// "relink .claude/skills/foo (broken link)".
+func describeLinkFix(issue core.LinkIssue) string {
+	switch issue.Fix {
+	case core.FixRelink:
+		return fmt.Sprintf("relink %s (%s)", issue.Path, issue.Problem)
+	case core.FixPrune:
+		return fmt.Sprintf("prune %s (%s)", issue.Path, issue.Problem)
+	}
+	return fmt.Sprintf("skip %s (%s; run 'duckrow sync')", issue.Path, issue.Problem)
+}
+
+func init() {
+	repairCmd.Flags().StringP("dir", "d", "", "Project directory (default: current directory)")
+	repairCmd.Flags().Bool("dry-run", false, "Show what would be repaired without making changes")
+	rootCmd.AddCommand(repairCmd)
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Bundle recent sessions and state for a bug report",
+	Long: `Write a tarball to attach to a bug report. It holds:
+
+  version.txt      duckrow, Go, git, and OS versions
+  sessions.log     the last runs of duckrow: their command lines, the git
+                   commands and network requests they made, and any error
+  config.json      the config file (settings, registries, bookmarks)
+  registries.txt   the state of each registry clone
+  duckrow.lock.json, local.lock.json
+                   the project's lock files, if present
+
+Secrets are masked: credentials in URLs, GitHub tokens, Authorization
+headers, and values of names like *_TOKEN or *_PASSWORD. Env files are never
+included. The file is created readable only by you; look it over before
+sharing it.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		output, _ := cmd.Flags().GetString("output")
+		force, _ := cmd.Flags().GetBool("force")
+		if output == "" {
+			output = "duckrow-report-" + time.Now().Format("20060102-150405") + ".tar.gz"
+		}
+		if _, err := os.Stat(output); err == nil && !force {
+			return fmt.Errorf("%s already exists; use --force to overwrite it", output)
+		}
+
+		d, err := newDeps()
+		if err != nil {
+			return err
+		}
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+
+		sessions, err := core.ReadSessions(d.config.ConfigDir())
+		if err != nil {
+			return err
+		}
+		files := []core.ReportFile{
+			{Name: "version.txt", Data: []byte(reportVersion())},
+			{Name: "sessions.log", Data: []byte(core.FormatSessions(sessions))},
+		}
+
+		cfg, err := d.config.Load()
+		if err != nil {
+			files = append(files, core.ReportFile{Name: "config.json", Data: []byte(fmt.Sprintf("error: %v\n", err))})
+		} else {
+			data, err := json.MarshalIndent(cfg, "", "  ")
+			if err != nil {
+				return fmt.Errorf("marshaling config: %w", err)
+			}
+			files = append(files,
+				core.ReportFile{Name: "config.json", Data: append(data, '\n')},
+				core.ReportFile{Name: "registries.txt", Data: []byte(reportRegistries(d, cfg))},
+			)
+		}
+
+		var lockFiles []string
+		for _, path := range []string{core.LockFilePath(targetDir), core.LocalLockFilePath(targetDir)} {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				continue
+			}
+			files = append(files, core.ReportFile{Name: filepath.Base(path), Data: data})
+			lockFiles = append(lockFiles, filepath.Base(path))
+		}
+
+		if err := core.WriteReport(output, files); err != nil {
+			return err
+		}
+
+		fmt.Fprintf(os.Stdout, "Report written to %s\n", output)
+		if len(lockFiles) == 0 {
+			lockFiles = []string{"none"}
+		}
+		fmt.Fprintf(os.Stdout, "  %d session(s), lock files: %s\n", len(sessions), strings.Join(lockFiles, ", "))
+		fmt.Fprintln(os.Stdout, "Secrets are masked, but look the file over before sharing it.")
+		return nil
+	},
+}
+
+// reportVersion describes the duckrow build and the environment it runs in.
+func reportVersion() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "duckrow %s (commit: %s, built: %s)\n", Version, Commit, Date)
+	fmt.Fprintf(&b, "go: %s\n", runtime.Version())
+	fmt.Fprintf(&b, "os: %s/%s\n", runtime.GOOS, runtime.GOARCH)
+	if out, err := exec.Command("git", "--version").Output(); err == nil {
+		fmt.Fprintf(&b, "git: %s\n", strings.TrimPrefix(strings.TrimSpace(string(out)), "git version "))
+	} else {
+		fmt.Fprintf(&b, "git: not found (%v)\n", err)
+	}
+	fmt.Fprintf(&b, "offline: %t\n", core.Offline())
+	return b.String()
+}
+
+// reportRegistries describes each registry clone: what it holds, its
+// manifest warnings, and how fresh its commit cache is.
+func reportRegistries(d *deps, cfg *core.Config) string {
+	if len(cfg.Registries) == 0 {
+		return "No registries configured.\n"
+	}
+	rm := core.NewRegistryManager(d.config.RegistriesDir())
+	ttl := cfg.Settings.CommitCacheTTL()
+
+	var b strings.Builder
+	for _, reg := range cfg.Registries {
+		fmt.Fprintf(&b, "%s  %s\n", registryLabel(reg), reg.Repo)
+		manifest, err := rm.LoadManifest(reg.Repo)
+		if err != nil {
+			fmt.Fprintf(&b, "  error: %v\n", err)
+			continue
+		}
+		fmt.Fprintf(&b, "  contents: %s\n", registrySummary(manifest))
+		fmt.Fprintf(&b, "  commit cache: %s\n", commitCacheStatus(rm, reg, ttl))
+		for _, w := range manifest.Warnings {
+			fmt.Fprintf(&b, "  warning: %s\n", w)
+		}
+	}
+	return b.String()
+}
+
+func init() {
+	reportCmd.Flags().StringP("output", "o", "", "File to write (default duckrow-report-<time>.tar.gz)")
+	reportCmd.Flags().Bool("force", false, "Overwrite an existing file")
+	reportCmd.Flags().StringP("dir", "d", "", "Project directory whose lock files to include (default: current directory)")
+	rootCmd.AddCommand(reportCmd)
+}
@@ -2,10 +2,15 @@ package cmd
 
 import (
 	"fmt"
+	"io"
+	"os"
+	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 
+	"github.com/barysiuk/duckrow/internal/core"
 	"github.com/barysiuk/duckrow/internal/tui"
 )
 
@@ -27,6 +32,16 @@ and see what's installed everywhere - all from a single tool.
 Run without arguments to launch the interactive TUI.`,
 	SilenceUsage:  true,
 	SilenceErrors: true,
+	PersistentPreRun: func(cmd *cobra.Command, args []string) {
+		applySettings(cmd)
+		snapshotLocks(cmd)
+	},
+	PersistentPostRun: func(cmd *cobra.Command, args []string) {
+		maintainVCS()
+		printVerboseStats(cmd)
+		printUpdateHint(cmd)
+		printUpgradeNotice(cmd)
+	},
 	RunE: func(cmd *cobra.Command, args []string) error {
 		d, err := newDeps()
 		if err != nil {
@@ -49,11 +64,167 @@ var versionCmd = &cobra.Command{
 }
 
 func init() {
+	rootCmd.PersistentFlags().Bool("offline", false, "Forbid network access; use only local registry clones and cached commits")
+	rootCmd.PersistentFlags().Duration("clone-timeout", 0, "Timeout for git clones and fetches (default 60s)")
+	rootCmd.PersistentFlags().Duration("pull-timeout", 0, "Timeout for registry pulls (default 30s)")
+	rootCmd.PersistentFlags().Duration("download-timeout", 0, "Timeout for HTTP downloads (default 30s)")
+	rootCmd.PersistentFlags().String("cache-dir", "", "Keep the clone cache in this directory instead of ~/.duckrow/cache (setting: cacheDir)")
+	rootCmd.PersistentFlags().Int("max-requests-per-minute", 0, "Cap requests to remote hosts; negative turns the limit off (default 120)")
+	rootCmd.PersistentFlags().Bool("verbose", false, "Print extra details, such as clone cache hits and network request counts")
+	rootCmd.PersistentFlags().Bool("accessible", false, "Screen-reader friendly output: no box drawing, symbols, or animated spinners, and higher contrast (setting: accessible)")
 	rootCmd.AddCommand(versionCmd)
 	registerAssetCommands()
 }
 
+// applySettings sets offline mode, the host allowlist, registry auth, the
+// network timeouts, the clone cache, the request rate limit, the gitignore
+// policy, notifications, and accessible output from the config, with the
+// global flags taking precedence.
+func applySettings(cmd *cobra.Command) {
+	var settings core.Settings
+	var registries []core.Registry
+	var configDir string
+	if d, err := newDeps(); err == nil {
+		configDir = d.config.ConfigDir()
+		if cfg, err := d.config.Load(); err == nil {
+			settings = cfg.Settings
+			registries = cfg.Registries
+		}
+	}
+
+	offline, _ := cmd.Flags().GetBool("offline")
+	core.SetOffline(offline || settings.Offline)
+	core.SetAllowedHosts(settings.AllowedHosts)
+	core.SetRegistryAuth(registries)
+
+	timeouts := settings.Timeouts()
+	if d, _ := cmd.Flags().GetDuration("clone-timeout"); d > 0 {
+		timeouts.Clone = d
+	}
+	if d, _ := cmd.Flags().GetDuration("pull-timeout"); d > 0 {
+		timeouts.Pull = d
+	}
+	if d, _ := cmd.Flags().GetDuration("download-timeout"); d > 0 {
+		timeouts.Download = d
+	}
+	core.SetTimeouts(timeouts)
+
+	cache := settings.Cache(configDir)
+	if dir, _ := cmd.Flags().GetString("cache-dir"); dir != "" {
+		cache.Dir = dir
+	}
+	core.SetCache(cache)
+
+	rate := settings.RequestsPerMinute()
+	if cmd.Flags().Changed("max-requests-per-minute") {
+		rate, _ = cmd.Flags().GetInt("max-requests-per-minute")
+	}
+	core.SetRequestsPerMinute(rate)
+	core.SetGitHubAPI(core.GitHubAPIConfig{Enabled: settings.UseGitHubAPI(), CacheDir: configDir})
+	core.SetInstallStrategy(settings.Strategy())
+	gitignorePolicy = settings.Gitignore()
+	notifySettings.desktop = settings.DesktopNotifications
+	notifySettings.webhook = settings.NotifyWebhookURL
+
+	accessible, _ := cmd.Flags().GetBool("accessible")
+	accessibleOutput = accessible || settings.Accessible
+	tui.SetAccessible(accessibleOutput)
+}
+
+// printVerboseStats reports the network requests made and how the clone
+// cache served this command when --verbose is given. Commands whose own
+// --verbose means something else (registry list) don't inherit the global
+// flag and print nothing.
+func printVerboseStats(cmd *cobra.Command) {
+	if cmd.Flags().Lookup("verbose") != cmd.Root().PersistentFlags().Lookup("verbose") {
+		return
+	}
+	if verbose, _ := cmd.Flags().GetBool("verbose"); !verbose {
+		return
+	}
+
+	if net := core.CurrentNetworkStats(); net.Requests > 0 {
+		fmt.Fprintf(os.Stderr, "Network: %d request(s), %d GitHub API call(s) (%d not modified), %d delayed by the rate limit (%s), %d retried after Retry-After\n",
+			net.Requests, net.APICalls, net.APINotModified, net.Delayed, net.Throttled.Round(time.Millisecond), net.Retried)
+	}
+
+	cache := core.CurrentCache()
+	stats := core.CurrentCacheStats()
+	if !cache.Enabled() || stats.Hits+stats.Misses == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stderr, "Clone cache: %d hit(s), %d miss(es) in %s\n", stats.Hits, stats.Misses, cache.Dir)
+}
+
+// printUpgradeNotice tells the user on stderr when a newer duckrow release
+// is out. It stays quiet when stderr isn't a terminal, so scripts and CI
+// logs don't get it, when the check is turned off, and after the TUI, which
+// shows the notice itself.
+func printUpgradeNotice(cmd *cobra.Command) {
+	if cmd == cmd.Root() || !term.IsTerminal(int(os.Stderr.Fd())) {
+		return
+	}
+	d, err := newDeps()
+	if err != nil {
+		return
+	}
+	if cfg, err := d.config.Load(); err != nil || cfg.Settings.DisableUpgradeCheck {
+		return
+	}
+	if rel := core.CheckForUpgrade(d.config.ConfigDir(), Version); rel != nil {
+		fmt.Fprintf(os.Stderr, "\nNotice: %s\n", rel.UpgradeNotice(Version))
+	}
+}
+
+// PrintErrorHints writes the suggestions attached to a clone error, if err
+// is one, so CLI failures get the same targeted fixes as the TUI overlay.
+func PrintErrorHints(w io.Writer, err error) {
+	ce, ok := core.IsCloneError(err)
+	if !ok || len(ce.Hints) == 0 {
+		return
+	}
+	fmt.Fprintln(w, "Suggestions:")
+	for _, hint := range ce.Hints {
+		fmt.Fprintf(w, "  * %s\n", hint)
+	}
+}
+
 // Execute runs the root command.
 func Execute() error {
-	return rootCmd.Execute()
+	start := time.Now()
+	cmd, err := rootCmd.ExecuteC()
+	recordSession(cmd, start, err)
+	return err
+}
+
+// recordSession saves what this run logged, for `duckrow report`. Runs
+// that logged nothing and succeeded are not worth keeping; neither are
+// report itself and env, which MCP clients start all the time. Nothing is
+// recorded before ~/.duckrow exists.
+func recordSession(cmd *cobra.Command, start time.Time, err error) {
+	if cmd == nil || cmd == reportCmd || cmd == envCmd {
+		return
+	}
+	entries := core.LogEntries(0)
+	if len(entries) == 0 && err == nil {
+		return
+	}
+	d, depsErr := newDeps()
+	if depsErr != nil {
+		return
+	}
+	if _, statErr := os.Stat(d.config.ConfigDir()); statErr != nil {
+		return
+	}
+
+	s := core.Session{
+		Args:    os.Args[1:],
+		Version: Version,
+		Start:   start,
+		Entries: entries,
+	}
+	if err != nil {
+		s.Error = err.Error()
+	}
+	_ = core.RecordSession(d.config.ConfigDir(), s)
 }
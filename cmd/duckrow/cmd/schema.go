@@ -0,0 +1,43 @@
+package cmd
+
+import (
+	"os"
+
+	"github.com/barysiuk/duckrow/internal/core/schema"
+	"github.com/spf13/cobra"
+)
+
+var schemaCmd = &cobra.Command{
+	Use:   "schema",
+	Short: "Print the JSON Schemas of duckrow's files",
+	Long: `duckrow checks the files it reads against JSON Schemas and reports problems
+with their line and column. The schemas are:
+
+  registry   duckrow.json, a registry manifest
+  lock       duckrow.lock.json and .duckrow/local.lock.json
+  config     ~/.duckrow/config.json
+
+Point an editor at them for completion and inline errors, e.g. with a
+"$schema" field.`,
+}
+
+var schemaPrintCmd = &cobra.Command{
+	Use:   "print <name>",
+	Short: "Print a JSON Schema",
+	Long: `Print the JSON Schema named <name>: registry, lock, or config. File names
+work too, e.g. duckrow.lock.json.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		data, err := schema.Source(args[0])
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+	},
+}
+
+func init() {
+	schemaCmd.AddCommand(schemaPrintCmd)
+	rootCmd.AddCommand(schemaCmd)
+}
@@ -0,0 +1,100 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/spf13/cobra"
+)
+
+var searchCmd = &cobra.Command{
+	Use:   "search <query>",
+	Short: "Search the configured registries",
+	Long: `Search every configured registry for skills, MCPs, agents, commands, and
+rules. Each word of the query must match an entry's name, one of its tags,
+or its description, ignoring case; names also match with the word's letters
+in order, so "gorev" finds "go-review". The best matches are listed first.
+
+--kind limits the search to one asset kind and --registry to one registry.
+Registries are searched as last fetched; run 'duckrow registry refresh' to
+pick up new entries.`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		kindFlag, _ := cmd.Flags().GetString("kind")
+		registryFilter, _ := cmd.Flags().GetString("registry")
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		query := strings.Join(args, " ")
+
+		kinds := asset.Kinds()
+		if kindFlag != "" {
+			if _, ok := asset.Get(asset.Kind(kindFlag)); !ok {
+				return fmt.Errorf("unknown kind %q (want skill, mcp, agent, command, or rule)", kindFlag)
+			}
+			kinds = []asset.Kind{asset.Kind(kindFlag)}
+		}
+
+		d, err := newDeps()
+		if err != nil {
+			return err
+		}
+		cfg, err := d.config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		if registryFilter != "" {
+			reg, err := findRegistry(cfg.Registries, registryFilter)
+			if err != nil {
+				return err
+			}
+			registryFilter = reg.Repo
+		}
+
+		rm := core.NewRegistryManager(d.config.RegistriesDir())
+		results, err := rm.SearchAssets(cfg.Registries, kinds, query, registryFilter)
+		if err != nil {
+			return err
+		}
+
+		if jsonOutput {
+			if results == nil {
+				results = []core.SearchResult{}
+			}
+			data, err := json.MarshalIndent(results, "", "  ")
+			if err != nil {
+				return fmt.Errorf("marshaling JSON: %w", err)
+			}
+			fmt.Fprintln(os.Stdout, string(data))
+			return nil
+		}
+
+		if len(results) == 0 {
+			if len(cfg.Registries) == 0 {
+				fmt.Fprintln(os.Stdout, "No registries configured. Add one with 'duckrow registry add <repo-url>'.")
+				return nil
+			}
+			fmt.Fprintf(os.Stdout, "No registry entries match %q.\n", query)
+			return nil
+		}
+
+		t := newTable(os.Stdout, "Name", "Kind", "Registry", "Description")
+		for _, r := range results {
+			t.row(r.Name, string(r.Kind), r.RegistryName, r.Description)
+		}
+		if err := t.flush(); err != nil {
+			return err
+		}
+		fmt.Fprintln(os.Stdout, "\nInstall one with 'duckrow <kind> install <name>'.")
+		return nil
+	},
+}
+
+func init() {
+	searchCmd.Flags().String("kind", "", "Search only this kind: skill, mcp, agent, command, or rule")
+	searchCmd.Flags().StringP("registry", "r", "", "Search only this registry")
+	searchCmd.Flags().Bool("json", false, "Output as JSON")
+	rootCmd.AddCommand(searchCmd)
+}
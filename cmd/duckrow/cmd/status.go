@@ -77,6 +77,20 @@ func showFolderStatus(path string, tracked bool, mcpDescriptions map[string]stri
 	}
 	fmt.Fprintf(os.Stdout, "Folder: %s %s\n", path, trackLabel)
 
+	defaults, origin, err := core.DefaultSystems(path)
+	switch {
+	case err != nil:
+		fmt.Fprintf(os.Stdout, "  Default systems: %v\n", err)
+	case len(defaults) == 0:
+		fmt.Fprintln(os.Stdout, "  Default systems: auto (universal for skills, detected for MCPs and agents)")
+	default:
+		source := "duckrow.lock.json"
+		if origin == core.OriginLocal {
+			source = ".duckrow/local.lock.json"
+		}
+		fmt.Fprintf(os.Stdout, "  Default systems: %s (from %s)\n", strings.Join(system.Names(defaults), ", "), source)
+	}
+
 	orch := core.NewOrchestrator()
 	allInstalled, err := orch.ScanFolder(path)
 	if err != nil {
@@ -92,71 +106,107 @@ func showFolderStatus(path string, tracked bool, mcpDescriptions map[string]stri
 		for _, s := range skills {
 			// Show relative path from the folder root
 			relPath := skillRelPath(path, s.Path)
-			fmt.Fprintf(os.Stdout, "    - %s [%s]\n", s.Name, relPath)
+			if size, err := core.DirSize(s.Path); err == nil {
+				fmt.Fprintf(os.Stdout, "    - %s [%s] (%s)\n", s.Name, relPath, size)
+			} else {
+				fmt.Fprintf(os.Stdout, "    - %s [%s]\n", s.Name, relPath)
+			}
 			if s.Description != "" {
 				fmt.Fprintf(os.Stdout, "      %s\n", s.Description)
 			}
 		}
 	}
 
+	// Show system skill links that no longer match the canonical copies.
+	if issues, err := orch.ScanLinks(path); err == nil && len(issues) > 0 {
+		fmt.Fprintf(os.Stdout, "  Link problems (%d):\n", len(issues))
+		for _, issue := range issues {
+			fmt.Fprintf(os.Stdout, "    - %s\n", issue)
+		}
+		fmt.Fprintln(os.Stdout, "    Run 'duckrow repair' to fix them.")
+	}
+
+	// Show skills installed into a project they declare they can't work with.
+	if issues, err := orch.ScanRequirements(path); err == nil && len(issues) > 0 {
+		fmt.Fprintf(os.Stdout, "  Unmet requirements (%d):\n", len(issues))
+		for _, issue := range issues {
+			fmt.Fprintf(os.Stdout, "    - %s\n", issue)
+		}
+	}
+
 	// Show MCPs from the lock file (MCPs are config-only, not on disk).
-	lf, _ := core.ReadLockFile(path)
+	lf, _ := core.ReadLayeredLockFile(path)
 	if lf != nil && len(lf.MCPs) > 0 {
 		fmt.Fprintf(os.Stdout, "  MCPs (%d):\n", len(lf.MCPs))
 		for _, m := range lf.MCPs {
 			desc := mcpDescriptions[m.Name]
+			label := originLabel(lf, asset.KindMCP, m.Name)
+			if locked := core.FindLockedAsset(lf, asset.KindMCP, m.Name); locked != nil {
+				label += overridesLabel(core.LockedMCPOverrides(*locked))
+			}
 			if desc != "" {
-				fmt.Fprintf(os.Stdout, "    - %-18s %s\n", m.Name, desc)
+				fmt.Fprintf(os.Stdout, "    - %-18s %s%s\n", m.Name, desc, label)
 			} else {
-				fmt.Fprintf(os.Stdout, "    - %s\n", m.Name)
+				fmt.Fprintf(os.Stdout, "    - %s%s\n", m.Name, label)
 			}
 		}
 	}
 
-	// Show agents — scan each system to show system associations.
-	agentMap := make(map[string]*agentStatusInfo)
-	var agentOrder []string
+	// Show agents, commands, and rules — scan each system to show system
+	// associations.
+	printFileAssetStatus(asset.KindAgent, "Agents", path)
+	printFileAssetStatus(asset.KindCommand, "Commands", path)
+	printFileAssetStatus(asset.KindRule, "Rules", path)
+
+	return nil
+}
+
+// agentStatusInfo tracks agent, command, or rule system associations for
+// status display.
+type agentStatusInfo struct {
+	name        string
+	description string
+	systems     []string
+}
+
+// printFileAssetStatus prints the agents, commands, or rules installed in
+// path, with the systems each one is installed for.
+func printFileAssetStatus(kind asset.Kind, heading, path string) {
+	infoMap := make(map[string]*agentStatusInfo)
+	var order []string
 
-	for _, sys := range system.Supporting(asset.KindAgent) {
-		installed, scanErr := sys.Scan(asset.KindAgent, path)
+	for _, sys := range system.Supporting(kind) {
+		installed, scanErr := sys.Scan(kind, path)
 		if scanErr != nil {
 			continue
 		}
 		for _, a := range installed {
-			info, ok := agentMap[a.Name]
+			info, ok := infoMap[a.Name]
 			if !ok {
 				info = &agentStatusInfo{
 					name:        a.Name,
 					description: a.Description,
 				}
-				agentMap[a.Name] = info
-				agentOrder = append(agentOrder, a.Name)
+				infoMap[a.Name] = info
+				order = append(order, a.Name)
 			}
 			info.systems = append(info.systems, sys.DisplayName())
 		}
 	}
 
-	if len(agentMap) > 0 {
-		fmt.Fprintf(os.Stdout, "  Agents (%d):\n", len(agentMap))
-		for _, name := range agentOrder {
-			info := agentMap[name]
-			sysNames := strings.Join(info.systems, ", ")
-			if info.description != "" {
-				fmt.Fprintf(os.Stdout, "    - %-18s %s  [%s]\n", info.name, info.description, sysNames)
-			} else {
-				fmt.Fprintf(os.Stdout, "    - %-18s [%s]\n", info.name, sysNames)
-			}
+	if len(infoMap) == 0 {
+		return
+	}
+	fmt.Fprintf(os.Stdout, "  %s (%d):\n", heading, len(infoMap))
+	for _, name := range order {
+		info := infoMap[name]
+		sysNames := strings.Join(info.systems, ", ")
+		if info.description != "" {
+			fmt.Fprintf(os.Stdout, "    - %-18s %s  [%s]\n", info.name, info.description, sysNames)
+		} else {
+			fmt.Fprintf(os.Stdout, "    - %-18s [%s]\n", info.name, sysNames)
 		}
 	}
-
-	return nil
-}
-
-// agentStatusInfo tracks agent system associations for status display.
-type agentStatusInfo struct {
-	name        string
-	description string
-	systems     []string
 }
 
 // buildMCPDescriptionMap loads MCP descriptions from configured registries (best-effort).
@@ -2,8 +2,11 @@ package cmd
 
 import (
 	"fmt"
+	"io"
 	"os"
+	"strconv"
 
+	"github.com/barysiuk/duckrow/internal/core"
 	"github.com/barysiuk/duckrow/internal/core/asset"
 	"github.com/spf13/cobra"
 )
@@ -16,53 +19,293 @@ var syncCmd = &cobra.Command{
 Skills whose directories already exist are skipped. Agent files that already
 exist in system agent directories are skipped. MCP entries that already
 exist in agent config files are skipped unless --force is used.
+--force also still reinstalls skills and agents, as --reinstall does; that
+use is deprecated.
+
+With --reinstall, skills and agents that are already present are installed
+again. Skills with local changes are not overwritten unless you confirm it
+or pass --overwrite-modified.
+
+Entries from the personal .duckrow/local.lock.json (written by install --local)
+are layered on top of the team lock and labeled "(local)" in the output.
 
 This command enforces the lock file and does not fetch upstream updates.
 Use duckrow skill outdated and duckrow skill update to move the lock file forward.
 
-This is equivalent to running duckrow skill sync, duckrow mcp sync, and duckrow agent sync in sequence.`,
+Every kind in the lock file is synced in one pass, like running each kind's
+sync in turn, followed by one summary table and one report of the env vars
+the synced MCPs require. Exits with a non-zero status if anything fails.
+
+With --from, the lock file is fetched from a remote location instead: either a
+raw URL to a .json file, or a repo source (owner/repo, a git URL, or a
+canonical host/owner/repo/path) whose duckrow.lock.json is read from a shallow
+clone. The fetched lock is written to the target directory before syncing.
+An existing duckrow.lock.json is only replaced with --force.
+
+With --tag, only lock entries recorded with that registry tag are synced,
+e.g. the collection installed by duckrow install --tag backend.
+
+With --frozen, for CI, nothing is installed if the lock file would need to
+change to describe the result: an entry not pinned to a commit, an MCP whose
+registry config no longer matches the lock's config hash, or a skill in
+.agents/skills that is not in the lock.
+
+The entries that failed to sync are remembered, per folder, in
+~/.duckrow/state.json. With --retry-failed, only those are synced again.
+
+With --json, the summary is printed as one JSON object instead, listing each
+failed entry with its kind, name, error, and error class (e.g. network, auth,
+modified, registry-not-configured), and progress goes to stderr.`,
 	Args: cobra.NoArgs,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		fmt.Fprintln(os.Stdout, "Syncing from duckrow.lock.json...")
+		from, _ := cmd.Flags().GetString("from")
+		retry, _ := cmd.Flags().GetBool("retry-failed")
+		if retry && from != "" {
+			return fmt.Errorf("--retry-failed cannot be used with --from")
+		}
+		jsonOutput, _ := cmd.Flags().GetBool("json")
+		stdout, restore := stdoutForJSON(jsonOutput)
+		defer restore()
+		var jsonOut io.Writer
+		if jsonOutput {
+			jsonOut = stdout
+		}
+
+		var remote *core.LockFile
+		if from != "" {
+			lf, err := fetchRemoteLock(cmd, from)
+			if err != nil {
+				return err
+			}
+			remote = lf
+			fmt.Fprintf(os.Stdout, "Syncing from %s...\n", from)
+		} else {
+			fmt.Fprintln(os.Stdout, "Syncing from duckrow.lock.json...")
+		}
 		fmt.Fprintln(os.Stdout)
 
-		var firstErr error
-
-		for _, kind := range asset.Kinds() {
-			result, err := runAssetSyncInner(cmd, kind)
-
-			handler, _ := asset.Get(kind)
-			display := handler.DisplayName()
-
-			if result != nil {
-				fmt.Fprintf(os.Stdout, "%ss: %d installed, %d skipped, %d errors\n",
-					display, result.installed, result.skipped, result.errors)
-				if kind == asset.KindMCP {
-					printRequiredEnvSummary(result.requiredEnv)
-				}
-				if firstErr == nil && result.errors > 0 {
-					firstErr = fmt.Errorf("%d %s(s) failed to sync", result.errors, display)
-				}
-			} else if err != nil {
-				fmt.Fprintf(os.Stderr, "%ss: error: %v\n", display, err)
-				if firstErr == nil {
-					firstErr = err
-				}
+		if frozen, _ := cmd.Flags().GetBool("frozen"); frozen {
+			if err := checkFrozenSync(cmd, remote); err != nil {
+				return err
 			}
 		}
+		return syncAllKinds(cmd, remote, jsonOut)
+	},
+}
+
+// syncJSON is the summary sync --json prints.
+type syncJSON struct {
+	Installed   int                 `json:"installed"`
+	Skipped     int                 `json:"skipped"`
+	Errors      int                 `json:"errors"`
+	Failed      []core.FailedItem   `json:"failed"`
+	RequiredEnv map[string][]string `json:"requiredEnv,omitempty"`
+}
 
-		if firstErr == nil {
-			fmt.Fprintln(os.Stdout, "\nSynced successfully.")
+// syncAllKinds syncs every asset kind from remote, or from the lock file in
+// the target directory when remote is nil. It ends with one summary table
+// of every kind and one report of the env vars all synced MCPs require, or,
+// when jsonOut is set, with the summary written to it as JSON.
+func syncAllKinds(cmd *cobra.Command, remote *core.LockFile, jsonOut io.Writer) error {
+	targetDir, err := resolveTargetDir(cmd)
+	if err != nil {
+		return err
+	}
+	if remote == nil {
+		// Without a lock file every kind would fail the same way.
+		lf, err := core.ReadLayeredLockFile(targetDir)
+		if err != nil {
+			return fmt.Errorf("reading lock file: %w", err)
+		}
+		if lf == nil {
+			return fmt.Errorf("no duckrow.lock.json found in %s", targetDir)
+		}
+	}
+	if retry, _ := cmd.Flags().GetBool("retry-failed"); retry {
+		d, err := newDeps()
+		if err != nil {
+			return err
 		}
+		if len(d.config.Failures(targetDir, failuresSync)) == 0 {
+			if jsonOut != nil {
+				return writeSummaryJSON(jsonOut, syncJSON{Failed: []core.FailedItem{}})
+			}
+			fmt.Fprintln(os.Stdout, "Nothing to retry: the last sync had no failures.")
+			return nil
+		}
+	}
+
+	if force, _ := cmd.Flags().GetBool("force"); force {
+		fmt.Fprintln(os.Stderr, "Warning: --force also reinstalls skills and agents; that use is deprecated, use --reinstall instead.")
+	}
 
+	type kindSummary struct {
+		display string
+		result  *assetSyncResult
+	}
+	var summaries []kindSummary
+	var firstErr error
+	var total assetSyncResult
+	failed := []core.FailedItem{}
+	requiredEnv := make(map[string][]string)
+	for _, kind := range asset.Kinds() {
+		handler, _ := asset.Get(kind)
+		display := handler.DisplayName()
+
+		result, err := runAssetSyncInner(cmd, kind, remote, true)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "%ss: error: %v\n", display, err)
+			failed = append(failed, core.NewFailedItem(kind, "", "", err))
+			if firstErr == nil {
+				firstErr = err
+			}
+		}
+		summaries = append(summaries, kindSummary{display: display, result: result})
+		if result == nil {
+			continue
+		}
+		total.installed += result.installed
+		total.skipped += result.skipped
+		total.errors += result.errors
+		failed = append(failed, result.failures...)
+		for v, names := range result.requiredEnv {
+			requiredEnv[v] = append(requiredEnv[v], names...)
+		}
+		if firstErr == nil && result.errors > 0 {
+			firstErr = fmt.Errorf("%d %s(s) failed to sync", result.errors, display)
+		}
+	}
+
+	notifyDone(cmd, targetDir, fmt.Sprintf("%d installed, %d skipped, %d errors",
+		total.installed, total.skipped, total.errors), firstErr != nil)
+
+	if jsonOut != nil {
+		out := syncJSON{
+			Installed:   total.installed,
+			Skipped:     total.skipped,
+			Errors:      total.errors,
+			Failed:      failed,
+			RequiredEnv: requiredEnv,
+		}
+		if err := writeSummaryJSON(jsonOut, out); err != nil {
+			return err
+		}
 		return firstErr
-	},
+	}
+
+	fmt.Fprintln(os.Stdout)
+	t := newTable(os.Stdout, "Kind", "Installed", "Skipped", "Errors")
+	for _, s := range summaries {
+		if s.result == nil {
+			t.row(s.display+"s", "-", "-", "error")
+			continue
+		}
+		t.row(s.display+"s", strconv.Itoa(s.result.installed), strconv.Itoa(s.result.skipped), strconv.Itoa(s.result.errors))
+	}
+	t.row("Total", strconv.Itoa(total.installed), strconv.Itoa(total.skipped), strconv.Itoa(total.errors))
+	_ = t.flush()
+	printRequiredEnvSummary(requiredEnv)
+
+	if firstErr == nil {
+		fmt.Fprintln(os.Stdout, "\nSynced successfully.")
+	} else if dryRun, _ := cmd.Flags().GetBool("dry-run"); !dryRun && cmd.Flags().Lookup("retry-failed") != nil {
+		printRetryHint(failed, "duckrow sync")
+	}
+	return firstErr
+}
+
+// checkFrozenSync fails, before anything is installed, if syncing remote
+// (or the lock file in the target directory when remote is nil) would not
+// install exactly what the lock records.
+func checkFrozenSync(cmd *cobra.Command, remote *core.LockFile) error {
+	targetDir, err := resolveTargetDir(cmd)
+	if err != nil {
+		return err
+	}
+	lf := remote
+	if lf == nil {
+		lf, err = core.ReadLayeredLockFile(targetDir)
+		if err != nil {
+			return fmt.Errorf("reading lock file: %w", err)
+		}
+		if lf == nil {
+			return fmt.Errorf("no duckrow.lock.json found in %s", targetDir)
+		}
+	}
+	opts, err := freezeOptions()
+	if err != nil {
+		return err
+	}
+
+	issues, err := core.CheckFrozenSync(targetDir, lf, opts.MCPConfigHash)
+	if err != nil {
+		return err
+	}
+	if len(issues) == 0 {
+		return nil
+	}
+	fmt.Fprintln(os.Stderr, "duckrow: the lock file would need to change to sync this folder:")
+	for _, issue := range issues {
+		fmt.Fprintf(os.Stderr, "  - %s\n", issue)
+	}
+	fmt.Fprintln(os.Stderr, "Run 'duckrow lock freeze' to pin unpinned entries, and install or remove unlocked skills; then commit the lock file.")
+	return fmt.Errorf("%d lock issue(s) found; nothing was installed", len(issues))
+}
+
+// fetchRemoteLock downloads the lock file named by --from and, unless this is
+// a dry run, writes it into the target directory.
+func fetchRemoteLock(cmd *cobra.Command, from string) (*core.LockFile, error) {
+	d, err := newDeps()
+	if err != nil {
+		return nil, err
+	}
+	cfg, err := d.config.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+	targetDir, err := resolveTargetDir(cmd)
+	if err != nil {
+		return nil, err
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+
+	lf, err := core.FetchRemoteLockFile(from, cfg.Settings.CloneURLOverrides)
+	if err != nil {
+		return nil, err
+	}
+	if dryRun {
+		return lf, nil
+	}
+
+	existing, err := core.ReadLockFile(targetDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading lock file: %w", err)
+	}
+	if existing != nil && !force {
+		return nil, fmt.Errorf("duckrow.lock.json already exists in %s; use --force to replace it", targetDir)
+	}
+
+	if err := os.MkdirAll(targetDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating target directory: %w", err)
+	}
+	if err := core.WriteLockFile(targetDir, lf); err != nil {
+		return nil, fmt.Errorf("writing lock file: %w", err)
+	}
+	return lf, nil
 }
 
 func init() {
 	syncCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
+	syncCmd.Flags().String("from", "", "Fetch the lock file from a raw URL or repo instead of the target directory")
 	syncCmd.Flags().Bool("dry-run", false, "Show what would be done without making changes")
-	syncCmd.Flags().Bool("force", false, "Overwrite existing MCP entries in agent config files")
+	syncCmd.Flags().String("tag", "", "Sync only the lock entries installed with this registry tag")
+	syncCmd.Flags().Bool("frozen", false, "Fail without installing anything if the lock file would need to change")
+	syncCmd.Flags().Bool("force", false, "Overwrite existing MCP entries in agent config files (also reinstalls skills and agents; deprecated, use --reinstall)")
+	syncCmd.Flags().Bool("reinstall", false, "Install skills and agents that are already present again")
+	syncCmd.Flags().Bool("overwrite-modified", false, "Discard local changes to skills that are installed again without asking")
+	syncCmd.Flags().Bool("retry-failed", false, "Sync only the entries the last sync in this folder failed on")
+	syncCmd.Flags().Bool("json", false, "Print the summary, with each failure and its error class, as JSON")
 	addSystemsFlag(syncCmd)
 	rootCmd.AddCommand(syncCmd)
 }
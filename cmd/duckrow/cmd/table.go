@@ -0,0 +1,54 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// accessibleOutput is set by --accessible or the accessible setting. Output
+// that relies on layout, like aligned columns, is then written as plain
+// sentences that a screen reader can follow.
+var accessibleOutput bool
+
+// table writes rows in aligned columns under a header. With accessible
+// output each row is one line that names its columns instead, e.g.
+// "test-skill: Installed abc1234, Available (up to date)".
+type table struct {
+	w       io.Writer
+	tw      *tabwriter.Writer
+	headers []string
+}
+
+func newTable(w io.Writer, headers ...string) *table {
+	t := &table{w: w, headers: headers}
+	if !accessibleOutput {
+		t.tw = tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(t.tw, strings.Join(headers, "\t"))
+	}
+	return t
+}
+
+// row writes one row. The first cell names the row.
+func (t *table) row(cells ...string) {
+	if t.tw != nil {
+		fmt.Fprintln(t.tw, strings.Join(cells, "\t"))
+		return
+	}
+	var parts []string
+	for i, cell := range cells[1:] {
+		if i+1 < len(t.headers) {
+			cell = t.headers[i+1] + " " + cell
+		}
+		parts = append(parts, cell)
+	}
+	fmt.Fprintf(t.w, "%s: %s\n", cells[0], strings.Join(parts, ", "))
+}
+
+func (t *table) flush() error {
+	if t.tw == nil {
+		return nil
+	}
+	return t.tw.Flush()
+}
@@ -0,0 +1,125 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/spf13/cobra"
+)
+
+var applyTemplateCmd = &cobra.Command{
+	Use:   "apply-template <repo-url>",
+	Short: "Apply a project template from a repo",
+	Long: `Apply a project template: a repo holding a duckrow.lock.json and, optionally,
+a project/ directory of files (e.g. AGENTS.md or .cursor/rules/) to copy into
+the project. Platform teams can keep one template per stack and have every
+project start from the same agent setup.
+
+<repo-url> is a repo source (owner/repo, a git URL, or host/owner/repo/path);
+a subpath selects a template directory inside the repo.
+
+With --mode merge (the default), the template's lock entries and files are
+added where the project doesn't have them; the project's own entries and
+files are kept. The template's defaultSystems apply only if the project has
+none. With --mode replace, the project's duckrow.lock.json becomes the
+template's and project files are overwritten; assets dropped from the lock
+are not uninstalled.
+
+The project is then synced, unless --no-sync or --dry-run is given.`,
+	Args: cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		from := args[0]
+		modeFlag, _ := cmd.Flags().GetString("mode")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+		noSync, _ := cmd.Flags().GetBool("no-sync")
+		mode, err := core.ParseTemplateMode(modeFlag)
+		if err != nil {
+			return err
+		}
+
+		d, err := newDeps()
+		if err != nil {
+			return err
+		}
+		cfg, err := d.config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+
+		t, err := core.FetchTemplate(from, cfg.Settings.CloneURLOverrides)
+		if err != nil {
+			return err
+		}
+		res, err := core.ApplyTemplate(t, targetDir, mode, dryRun)
+		if err != nil {
+			return err
+		}
+
+		if dryRun {
+			fmt.Fprintf(os.Stdout, "Would apply template %s (%s):\n", from, mode)
+		} else {
+			fmt.Fprintf(os.Stdout, "Applied template %s (%s):\n", from, mode)
+		}
+		printTemplateResult(res)
+
+		if dryRun || noSync {
+			if !dryRun && !res.IsEmpty() {
+				fmt.Fprintln(os.Stdout, "\nRun 'duckrow sync' to install the assets.")
+			}
+			return nil
+		}
+		fmt.Fprintln(os.Stdout)
+		return syncAllKinds(cmd, nil, nil)
+	},
+}
+
+// printTemplateResult lists what applying a template changed.
+func printTemplateResult(res *core.TemplateResult) {
+	if res.IsEmpty() && len(res.Kept) == 0 && len(res.Skipped) == 0 {
+		fmt.Fprintln(os.Stdout, "  Nothing to change; the project already matches the template.")
+		return
+	}
+	for _, name := range res.Added {
+		fmt.Fprintf(os.Stdout, "  + %s\n", name)
+	}
+	for _, name := range res.Kept {
+		fmt.Fprintf(os.Stdout, "  = %s (kept the project's entry)\n", name)
+	}
+	for _, name := range res.Removed {
+		fmt.Fprintf(os.Stdout, "  - %s\n", name)
+	}
+	for _, rule := range res.ExcludeAdded {
+		fmt.Fprintf(os.Stdout, "  + exclude %s\n", rule)
+	}
+	for _, rule := range res.ExcludeRemoved {
+		fmt.Fprintf(os.Stdout, "  - exclude %s\n", rule)
+	}
+	for _, rel := range res.Files {
+		fmt.Fprintf(os.Stdout, "  + %s\n", rel)
+	}
+	for _, rel := range res.Skipped {
+		fmt.Fprintf(os.Stdout, "  = %s (exists; kept)\n", rel)
+	}
+	if res.DefaultSystems != nil {
+		systems := strings.Join(res.DefaultSystems, ", ")
+		if systems == "" {
+			systems = "built-in defaults"
+		}
+		fmt.Fprintf(os.Stdout, "  Default systems: %s\n", systems)
+	}
+}
+
+func init() {
+	applyTemplateCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
+	applyTemplateCmd.Flags().String("mode", string(core.TemplateMerge), "How to apply the template: merge or replace")
+	applyTemplateCmd.Flags().Bool("dry-run", false, "Show what would change without writing anything")
+	applyTemplateCmd.Flags().Bool("no-sync", false, "Update the lock file and project files without installing")
+	addSystemsFlag(applyTemplateCmd)
+	rootCmd.AddCommand(applyTemplateCmd)
+}
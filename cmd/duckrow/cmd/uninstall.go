@@ -0,0 +1,112 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+	"github.com/spf13/cobra"
+)
+
+var uninstallCmd = &cobra.Command{
+	Use:   "uninstall --registry <name-or-repo>",
+	Short: "Remove installed skills, agents, and MCPs by registry",
+	Long: `Remove every installed skill, agent, and MCP whose lock file provenance points
+at the given registry. Useful when off-boarding a registry.
+
+MCPs match on the registry recorded in their lock entry. Skills and agents
+match when the registry lists an entry with the same name in the same
+repository as the locked source. The registry must still be configured so its
+manifest can be read; to remove the registry at the same time, use
+'duckrow registry remove --purge'.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		registryArg, _ := cmd.Flags().GetString("registry")
+		if registryArg == "" {
+			return fmt.Errorf("--registry is required")
+		}
+		noLock, _ := cmd.Flags().GetBool("no-lock")
+		dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+
+		d, err := newDeps()
+		if err != nil {
+			return err
+		}
+		cfg, err := d.config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		reg, err := findRegistry(cfg.Registries, registryArg)
+		if err != nil {
+			return err
+		}
+
+		rm := core.NewRegistryManager(d.config.RegistriesDir())
+		manifest, err := rm.LoadManifest(reg.Repo)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: could not read registry manifest; only MCPs will be matched: %v\n", err)
+		}
+
+		return uninstallFromRegistry(targetDir, *reg, manifest, noLock, dryRun)
+	},
+}
+
+// uninstallFromRegistry removes all assets in targetDir whose lock entries
+// come from reg.
+func uninstallFromRegistry(targetDir string, reg core.Registry, manifest *core.RegistryManifest, noLock, dryRun bool) error {
+	lf, err := core.ReadLayeredLockFile(targetDir)
+	if err != nil {
+		return fmt.Errorf("reading lock file: %w", err)
+	}
+
+	matched := core.LockedFromRegistry(lf, reg, manifest)
+	if len(matched) == 0 {
+		fmt.Fprintf(os.Stdout, "No installed assets from registry %q.\n", reg.Name)
+		return nil
+	}
+
+	orch := core.NewOrchestrator()
+	removed := 0
+	for _, a := range matched {
+		handler, _ := asset.Get(a.Kind)
+		if dryRun {
+			fmt.Fprintf(os.Stdout, "remove: %s %s\n", handler.DisplayName(), a.Name)
+			continue
+		}
+
+		var rmErr error
+		switch a.Kind {
+		case asset.KindSkill:
+			rmErr = uninstallSkill(orch, targetDir, targetDir, []string{a.Name}, false, noLock)
+		case asset.KindMCP:
+			rmErr = uninstallMCP(targetDir, targetDir, system.ScopeProject, []string{a.Name}, false, noLock, false)
+		case asset.KindAgent, asset.KindCommand, asset.KindRule:
+			rmErr = uninstallFileAsset(a.Kind, orch, targetDir, []string{a.Name}, false, noLock)
+		}
+		if rmErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %s %q: %v\n", handler.DisplayName(), a.Name, rmErr)
+			continue
+		}
+		removed++
+	}
+
+	if !dryRun {
+		fmt.Fprintf(os.Stdout, "\nRemoved %d asset(s) from registry %q.\n", removed, reg.Name)
+	}
+	return nil
+}
+
+func init() {
+	uninstallCmd.Flags().StringP("registry", "r", "", "Registry whose assets should be removed")
+	uninstallCmd.Flags().StringP("dir", "d", "", "Target directory (default: current directory)")
+	uninstallCmd.Flags().Bool("no-lock", false, "Remove without updating the lock file")
+	uninstallCmd.Flags().Bool("dry-run", false, "Show what would be removed without making changes")
+	rootCmd.AddCommand(uninstallCmd)
+}
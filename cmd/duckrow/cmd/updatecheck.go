@@ -0,0 +1,127 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// updateCheckCmd is started in the background by printUpdateHint. It checks
+// the project's assets for updates and caches how many there are for the
+// next command's hint.
+var updateCheckCmd = &cobra.Command{
+	Use:    "update-check",
+	Short:  "Check a project for asset updates and cache the result",
+	Hidden: true,
+	Args:   cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d, err := newDeps()
+		if err != nil {
+			return err
+		}
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+		cfg, err := d.config.Load()
+		if err != nil {
+			return fmt.Errorf("loading config: %w", err)
+		}
+		lf, err := core.ReadLayeredLockFile(targetDir)
+		if err != nil || lf == nil {
+			return err
+		}
+
+		rm := core.NewRegistryManager(d.config.RegistriesDir())
+		if !core.Offline() {
+			rm.Hydrate(cfg.Registries, hydrateOptions(cfg, false))
+		}
+		registryCommits := core.BuildRegistryCommitMap(cfg.Registries, rm)
+		overrides := cfg.Settings.CloneURLOverrides
+		mirrors := rm.Mirrors(cfg.Registries)
+
+		available := make(map[asset.Kind]int)
+		for _, kind := range asset.Kinds() {
+			if kind == asset.KindMCP {
+				continue
+			}
+			for _, r := range core.CheckForUpdatesByRepo(lf, kind, overrides, mirrors, registryCommits, nil) {
+				for _, u := range r.Updates {
+					if u.HasUpdate {
+						available[kind]++
+					}
+				}
+			}
+		}
+		return core.RecordUpdates(d.config.ConfigDir(), targetDir, available)
+	},
+}
+
+// printUpdateHint prints a one-line hint when the last background check
+// found updates for the project the command ran in, and starts a new check
+// in the background once a day. The foreground command never waits for it:
+// the hint it finds shows up on a later command. Like the upgrade notice,
+// hints are only printed to a terminal.
+func printUpdateHint(cmd *cobra.Command) {
+	if cmd == cmd.Root() || cmd == updateCheckCmd || cmd.Hidden || !term.IsTerminal(int(os.Stderr.Fd())) {
+		return
+	}
+	if strings.HasPrefix(cmd.Name(), "__") || cmd.Name() == "update" || cmd.Name() == "outdated" {
+		return
+	}
+	d, err := newDeps()
+	if err != nil {
+		return
+	}
+	if cfg, err := d.config.Load(); err != nil || cfg.Settings.DisableUpdateHints {
+		return
+	}
+	dir, err := os.Getwd()
+	if cmd.Flags().Lookup("dir") != nil {
+		dir, err = resolveTargetDir(cmd)
+	}
+	if err != nil {
+		return
+	}
+
+	configDir := d.config.ConfigDir()
+	if u, ok := core.CachedUpdates(configDir, dir); ok {
+		if hint := u.Hint(); hint != "" {
+			fmt.Fprintf(os.Stderr, "\nHint: %s\n", hint)
+		}
+	}
+	if core.Offline() || !core.UpdateCheckDue(configDir, dir) {
+		return
+	}
+	startUpdateCheck(configDir, dir)
+}
+
+// startUpdateCheck runs 'duckrow update-check' for dir as a detached
+// process, after recording the check as started so commands running
+// meanwhile don't start another.
+func startUpdateCheck(configDir, dir string) {
+	exe, err := os.Executable()
+	if err != nil {
+		return
+	}
+	if err := core.RecordUpdates(configDir, dir, nil); err != nil {
+		return
+	}
+	c := exec.Command(exe, "update-check", "--dir", dir)
+	if err := c.Start(); err != nil {
+		return
+	}
+	_ = c.Process.Release()
+}
+
+func init() {
+	updateCheckCmd.Flags().StringP("dir", "d", "", "Project directory (default: current directory)")
+	rootCmd.AddCommand(updateCheckCmd)
+}
@@ -0,0 +1,152 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/spf13/cobra"
+)
+
+// gitignorePolicy is the gitignorePolicy setting, set by applySettings.
+var gitignorePolicy = core.GitignoreOff
+
+var vcsCmd = &cobra.Command{
+	Use:   "vcs",
+	Short: "Check a project's version control hygiene",
+	Long: `Keep what duckrow writes in a project on the right side of version control:
+duckrow.lock.json committed, .env.duckrow and .duckrow/local.lock.json not.
+
+The gitignorePolicy setting makes duckrow keep generated directories in the
+project's .gitignore too, in a block it maintains whenever a command changes
+the lock file:
+
+  off         nothing beyond .env.duckrow and the local lock (the default)
+  canonical   .agents/skills/, the canonical skill copies
+  systems     also every system's skill and agent directory, e.g.
+              .claude/skills/ and .claude/agents/, hand-written ones included`,
+}
+
+// ---------------------------------------------------------------------------
+// vcs check
+// ---------------------------------------------------------------------------
+
+var vcsCheckCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Report version control hygiene issues",
+	Long: `Report version control hygiene issues in a project:
+
+  - duckrow.lock.json is gitignored
+  - .env.duckrow or .duckrow/local.lock.json is not gitignored, or committed
+  - .gitignore does not match the gitignorePolicy setting
+  - files in directories the policy ignores are committed
+
+Exits non-zero if there are issues. --fix fixes what can be fixed by editing
+.gitignore; the rest need a git command or a .gitignore rule removed.`,
+	Args: cobra.NoArgs,
+	RunE: func(cmd *cobra.Command, args []string) error {
+		targetDir, err := resolveTargetDir(cmd)
+		if err != nil {
+			return err
+		}
+		fix, _ := cmd.Flags().GetBool("fix")
+
+		issues, err := core.CheckVCS(targetDir, gitignorePolicy)
+		if errors.Is(err, core.ErrNotGitRepo) {
+			return fmt.Errorf("%s is %w", targetDir, err)
+		}
+		if err != nil {
+			return err
+		}
+
+		if fix {
+			fixed, err := core.FixVCS(targetDir, gitignorePolicy, issues)
+			for _, issue := range fixed {
+				fmt.Fprintf(os.Stdout, "Fixed: %s\n", issue)
+			}
+			if err != nil {
+				return err
+			}
+			var rest []core.VCSIssue
+			for _, issue := range issues {
+				if !issue.Fixable {
+					rest = append(rest, issue)
+				}
+			}
+			issues = rest
+		}
+
+		if len(issues) == 0 {
+			if !fix {
+				fmt.Fprintln(os.Stdout, "No version control issues.")
+			}
+			return nil
+		}
+		for _, issue := range issues {
+			fmt.Fprintf(os.Stdout, "%s\n  %s\n", issue, issue.Hint)
+		}
+		return fmt.Errorf("%d version control issue(s) found", len(issues))
+	},
+}
+
+// lockSnapshot holds the lock files of the command's project as they were
+// before it ran, so maintainVCS can tell whether the command changed them.
+var lockSnapshot struct {
+	dir         string
+	team, local []byte
+}
+
+// snapshotLocks records the lock files of the project the command targets
+// with --dir. Commands without --dir are not tracked.
+func snapshotLocks(cmd *cobra.Command) {
+	if cmd.Flags().Lookup("dir") == nil {
+		return
+	}
+	dir, err := resolveTargetDir(cmd)
+	if err != nil {
+		return
+	}
+	lockSnapshot.dir = dir
+	lockSnapshot.team, lockSnapshot.local = readLocks(dir)
+}
+
+func readLocks(dir string) (team, local []byte) {
+	team, _ = os.ReadFile(core.LockFilePath(dir))
+	local, _ = os.ReadFile(core.LocalLockFilePath(dir))
+	return team, local
+}
+
+// maintainVCS runs after a command that changed the lock files: it updates
+// the .gitignore block the gitignore policy asks for and warns if the lock
+// file itself is gitignored.
+func maintainVCS() {
+	dir := lockSnapshot.dir
+	if dir == "" {
+		return
+	}
+	team, local := readLocks(dir)
+	if bytes.Equal(team, lockSnapshot.team) && bytes.Equal(local, lockSnapshot.local) {
+		return
+	}
+
+	if gitignorePolicy != core.GitignoreOff {
+		changed, err := core.SyncGitignore(dir, gitignorePolicy.GeneratedPaths())
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: %v\n", err)
+		} else if changed {
+			fmt.Fprintf(os.Stdout, "Updated .gitignore (gitignore policy %q)\n", gitignorePolicy)
+		}
+	}
+	if rule := core.LockFileIgnoreRule(dir); rule != "" {
+		fmt.Fprintf(os.Stderr, "Warning: duckrow.lock.json is gitignored by %s, so it won't be committed; run 'duckrow vcs check'\n", rule)
+	}
+}
+
+func init() {
+	vcsCheckCmd.Flags().StringP("dir", "d", "", "Project directory (default: current directory)")
+	vcsCheckCmd.Flags().Bool("fix", false, "Fix the issues that only need .gitignore changes")
+	vcsCmd.AddCommand(vcsCheckCmd)
+	rootCmd.AddCommand(vcsCmd)
+}
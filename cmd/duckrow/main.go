@@ -10,6 +10,7 @@ import (
 func main() {
 	if err := cmd.Execute(); err != nil {
 		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		cmd.PrintErrorHints(os.Stderr, err)
 		os.Exit(1)
 	}
 }
@@ -17,6 +17,7 @@ func TestMain(m *testing.M) {
 		"duckrow": func() {
 			if err := cmd.Execute(); err != nil {
 				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				cmd.PrintErrorHints(os.Stderr, err)
 				os.Exit(1)
 			}
 		},
@@ -83,6 +84,11 @@ func TestScript(t *testing.T) {
 			// write-env-file writes key=value pairs to a .env.duckrow file.
 			// Usage: write-env-file <dir> <key>=<value> [key=value...]
 			"write-env-file": cmdWriteEnvFile,
+
+			// expand-file replaces $VAR references in a file with their values,
+			// for files that need absolute paths such as $WORK.
+			// Usage: expand-file <path>
+			"expand-file": cmdExpandFile,
 		},
 	})
 }
@@ -498,6 +504,25 @@ func cmdWriteEnvFile(ts *testscript.TestScript, neg bool, args []string) {
 	}
 }
 
+// cmdExpandFile replaces $VAR references in a file with their values.
+// Usage: expand-file <path>
+func cmdExpandFile(ts *testscript.TestScript, neg bool, args []string) {
+	if neg {
+		ts.Fatalf("expand-file does not support negation")
+	}
+	if len(args) != 1 {
+		ts.Fatalf("usage: expand-file <path>")
+	}
+	path := ts.MkAbs(args[0])
+	data, err := os.ReadFile(path)
+	if err != nil {
+		ts.Fatalf("reading %s: %v", path, err)
+	}
+	if err := os.WriteFile(path, []byte(os.Expand(string(data), ts.Getenv)), 0o644); err != nil {
+		ts.Fatalf("writing %s: %v", path, err)
+	}
+}
+
 // splitN is a helper that splits a string by sep into at most n parts.
 // If n < 0, returns all parts.
 func splitN(s, sep string, n int) []string {
@@ -182,7 +182,13 @@ type agentManifestEntry struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Source      string `json:"source"`
+	CloneURL    string `json:"cloneUrl,omitempty"`
 	Commit      string `json:"commit,omitempty"`
+	Hydrate     *bool  `json:"hydrate,omitempty"`
+
+	PostInstallMessage string   `json:"postInstallMessage,omitempty"`
+	Platforms          []string `json:"platforms,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
 }
 
 // ParseManifestEntries unmarshals agent entries from a registry manifest.
@@ -197,23 +203,22 @@ func (h *AgentHandler) ParseManifestEntries(raw json.RawMessage) ([]RegistryEntr
 			Name:        e.Name,
 			Description: e.Description,
 			Source:      e.Source,
+			CloneURL:    e.CloneURL,
 			Commit:      e.Commit,
+			NoHydrate:   e.Hydrate != nil && !*e.Hydrate,
 			Meta:        AgentMeta{},
+
+			PostInstallMessage: e.PostInstallMessage,
+			Platforms:          e.Platforms,
+			Tags:               e.Tags,
 		}
 	}
 	return result, nil
 }
 
-// LockData produces a LockedAsset from an agent installation.
-// Agents use the same thin format as skills: source + commit only.
-func (h *AgentHandler) LockData(a Asset, info InstallInfo) LockedAsset {
-	return LockedAsset{
-		Kind:   KindAgent,
-		Name:   a.Name,
-		Source: a.Source,
-		Commit: info.Commit,
-		Ref:    info.Ref,
-	}
+// BuildLockEntry builds the lock entry for an installed agent.
+func (h *AgentHandler) BuildLockEntry(a Asset, info InstallInfo) LockedAsset {
+	return sourceLockEntry(KindAgent, a, info)
 }
 
 func init() { Register(&AgentHandler{}) }
@@ -17,16 +17,49 @@ var SystemOverrideKeys = []string{
 	"gemini-cli",
 }
 
+// toolsStyle is how a system reads an agent's tools field.
+type toolsStyle int
+
+const (
+	toolsList       toolsStyle = iota // YAML list: [Read, Grep]
+	toolsCommaList                    // one string: "Read, Grep"
+	toolsEnabledMap                   // map of tool to enabled: {read: true}
+)
+
+// agentFormat describes the frontmatter conventions of a system's agent
+// files.
+type agentFormat struct {
+	keepName bool       // frontmatter names the agent; otherwise the filename does
+	tools    toolsStyle // shape of the tools field
+}
+
+// agentFormats are the agent file conventions of each agent-capable system:
+//
+//   - claude-code: subagents require a name; tools is a comma-separated string
+//   - opencode: named after the file; tools is a map of tool to enabled
+//   - github-copilot: named after the file; tools is a list
+//   - gemini-cli: subagents require a name; tools is a list
+var agentFormats = map[string]agentFormat{
+	"claude-code":    {keepName: true, tools: toolsCommaList},
+	"opencode":       {tools: toolsEnabledMap},
+	"github-copilot": {tools: toolsList},
+	"gemini-cli":     {keepName: true, tools: toolsList},
+}
+
 // RenderForSystem applies the merge algorithm to produce system-specific
-// agent file content (YAML frontmatter + Markdown body).
+// agent file content (YAML frontmatter + Markdown body). The name is the
+// one the agent is installed under; "" keeps the frontmatter's own.
 //
 // Algorithm:
 //  1. Start with a shallow copy of all top-level fields.
 //  2. Remove ALL system override blocks from the merged result.
-//  3. Apply the target system's override block (if any) — overrides replace.
-//  4. Remove "name" field (derived from filename) — except for Gemini CLI.
+//  3. Reshape top-level fields to the system's conventions (see
+//     agentFormats): the name is kept only where the system requires it,
+//     and tools is written in the form the system reads.
+//  4. Apply the target system's override block (if any) — overrides replace
+//     and are written verbatim.
 //  5. Render merged frontmatter + original body.
-func RenderForSystem(data *AgentData, systemKey string) ([]byte, error) {
+func RenderForSystem(data *AgentData, name, systemKey string) ([]byte, error) {
 	if data == nil {
 		return nil, fmt.Errorf("agent data is nil")
 	}
@@ -45,22 +78,29 @@ func RenderForSystem(data *AgentData, systemKey string) ([]byte, error) {
 		}
 	}
 
-	// 3. Remove ALL system override blocks.
+	// Remove ALL system override blocks.
 	for _, key := range SystemOverrideKeys {
 		delete(merged, key)
 	}
 
+	// 3. Reshape the top-level fields for this system.
+	format := agentFormats[systemKey]
+	switch {
+	case !format.keepName:
+		delete(merged, "name")
+	case name != "":
+		merged["name"] = name
+	}
+	if tools, ok := merged["tools"]; ok {
+		merged["tools"] = renderTools(tools, format.tools)
+	}
+
 	// 4. Apply this system's override block.
 	for k, v := range override {
 		merged[k] = v
 	}
 
-	// 5. Remove "name" field (derived from filename) — except for Gemini CLI.
-	if systemKey != "gemini-cli" {
-		delete(merged, "name")
-	}
-
-	// Render YAML frontmatter with consistent field ordering.
+	// 5. Render YAML frontmatter with consistent field ordering.
 	yamlBytes, err := marshalOrderedYAML(merged)
 	if err != nil {
 		return nil, fmt.Errorf("marshaling frontmatter: %w", err)
@@ -83,6 +123,45 @@ func RenderForSystem(data *AgentData, systemKey string) ([]byte, error) {
 	return buf.Bytes(), nil
 }
 
+// renderTools writes a tools value, a list or a comma-separated string, in
+// the given style. Tool names are passed through verbatim; any other value
+// is left as it is.
+func renderTools(v any, style toolsStyle) any {
+	var names []string
+	switch v := v.(type) {
+	case string:
+		for _, p := range strings.Split(v, ",") {
+			if p = strings.TrimSpace(p); p != "" {
+				names = append(names, p)
+			}
+		}
+	case []string:
+		names = v
+	case []any:
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return v
+			}
+			names = append(names, s)
+		}
+	default:
+		return v
+	}
+
+	switch style {
+	case toolsCommaList:
+		return strings.Join(names, ", ")
+	case toolsEnabledMap:
+		m := make(map[string]any, len(names))
+		for _, n := range names {
+			m[n] = true
+		}
+		return m
+	}
+	return names
+}
+
 // marshalOrderedYAML serializes a map to YAML with a defined field order:
 // 1. name (if present, for Gemini CLI)
 // 2. description
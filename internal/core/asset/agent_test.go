@@ -252,15 +252,15 @@ func TestRenderForSystem_Basic(t *testing.T) {
 		Body: "You are a helpful assistant.",
 	}
 
-	// For non-Gemini systems, "name" should be removed.
-	out, err := RenderForSystem(data, "claude-code")
+	// Systems that name agents after their file don't get "name".
+	out, err := RenderForSystem(data, "test-agent", "opencode")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
 
 	content := string(out)
 	if strings.Contains(content, "name:") {
-		t.Errorf("output should not contain 'name:' for claude-code, got:\n%s", content)
+		t.Errorf("output should not contain 'name:' for opencode, got:\n%s", content)
 	}
 	if !strings.Contains(content, "description: A test agent") {
 		t.Errorf("output should contain description, got:\n%s", content)
@@ -282,7 +282,7 @@ func TestRenderForSystem_GeminiKeepsName(t *testing.T) {
 		Body: "Prompt.",
 	}
 
-	out, err := RenderForSystem(data, "gemini-cli")
+	out, err := RenderForSystem(data, "test-agent", "gemini-cli")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -293,6 +293,79 @@ func TestRenderForSystem_GeminiKeepsName(t *testing.T) {
 	}
 }
 
+func TestRenderForSystem_PerSystem(t *testing.T) {
+	data := &AgentData{
+		Frontmatter: map[string]any{
+			"name":        "upstream-name",
+			"description": "A test agent",
+			"tools":       []any{"Read", "Grep"},
+		},
+		Body: "Prompt.",
+	}
+
+	tests := []struct {
+		system string
+		want   string
+	}{
+		{"claude-code", "name: reviewer\ndescription: A test agent\ntools: Read, Grep\n"},
+		{"opencode", "description: A test agent\ntools:\n  Grep: true\n  Read: true\n"},
+		{"github-copilot", "description: A test agent\ntools:\n  - Read\n  - Grep\n"},
+		{"gemini-cli", "name: reviewer\ndescription: A test agent\ntools:\n  - Read\n  - Grep\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.system, func(t *testing.T) {
+			out, err := RenderForSystem(data, "reviewer", tt.system)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			want := "---\n" + tt.want + "---\n\nPrompt.\n"
+			if string(out) != want {
+				t.Errorf("RenderForSystem(%s) =\n%s\nwant:\n%s", tt.system, out, want)
+			}
+		})
+	}
+}
+
+func TestRenderForSystem_ToolsString(t *testing.T) {
+	data := &AgentData{
+		Frontmatter: map[string]any{
+			"description": "A test agent",
+			"tools":       "Read, Grep",
+		},
+		Body: "Prompt.",
+	}
+
+	out, err := RenderForSystem(data, "reviewer", "gemini-cli")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(out), "tools:\n  - Read\n  - Grep\n") {
+		t.Errorf("expected tools split into a list for gemini-cli, got:\n%s", out)
+	}
+}
+
+func TestRenderForSystem_OverrideToolsVerbatim(t *testing.T) {
+	data := &AgentData{
+		Frontmatter: map[string]any{
+			"description": "A test agent",
+			"tools":       []any{"Read"},
+			"opencode": map[string]any{
+				"tools": map[string]any{"write": false},
+			},
+		},
+		Body: "Prompt.",
+	}
+
+	out, err := RenderForSystem(data, "reviewer", "opencode")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	content := string(out)
+	if !strings.Contains(content, "tools:\n  write: false\n") || strings.Contains(content, "Read") {
+		t.Errorf("expected the opencode tools override written verbatim, got:\n%s", content)
+	}
+}
+
 func TestRenderForSystem_SystemOverrideApplied(t *testing.T) {
 	data := &AgentData{
 		Frontmatter: map[string]any{
@@ -310,7 +383,7 @@ func TestRenderForSystem_SystemOverrideApplied(t *testing.T) {
 	}
 
 	// For claude-code, should use the override model.
-	out, err := RenderForSystem(data, "claude-code")
+	out, err := RenderForSystem(data, "test-agent", "claude-code")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -321,7 +394,7 @@ func TestRenderForSystem_SystemOverrideApplied(t *testing.T) {
 	}
 
 	// For opencode, should use opencode override.
-	out2, err := RenderForSystem(data, "opencode")
+	out2, err := RenderForSystem(data, "test-agent", "opencode")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -331,7 +404,7 @@ func TestRenderForSystem_SystemOverrideApplied(t *testing.T) {
 	}
 
 	// For github-copilot (no override), should use default model.
-	out3, err := RenderForSystem(data, "github-copilot")
+	out3, err := RenderForSystem(data, "test-agent", "github-copilot")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -355,7 +428,7 @@ func TestRenderForSystem_OverrideBlocksRemoved(t *testing.T) {
 	}
 
 	// Render for claude-code — should NOT contain other system keys.
-	out, err := RenderForSystem(data, "claude-code")
+	out, err := RenderForSystem(data, "test-agent", "claude-code")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -380,7 +453,7 @@ func TestRenderForSystem_EmptyBody(t *testing.T) {
 		Body: "",
 	}
 
-	out, err := RenderForSystem(data, "claude-code")
+	out, err := RenderForSystem(data, "test-agent", "claude-code")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -396,7 +469,7 @@ func TestRenderForSystem_EmptyBody(t *testing.T) {
 }
 
 func TestRenderForSystem_NilData(t *testing.T) {
-	_, err := RenderForSystem(nil, "claude-code")
+	_, err := RenderForSystem(nil, "", "claude-code")
 	if err == nil {
 		t.Error("expected error for nil data")
 	}
@@ -415,7 +488,7 @@ func TestRenderForSystem_FieldOrder(t *testing.T) {
 		Body: "Prompt.",
 	}
 
-	out, err := RenderForSystem(data, "gemini-cli")
+	out, err := RenderForSystem(data, "test-agent", "gemini-cli")
 	if err != nil {
 		t.Fatalf("unexpected error: %v", err)
 	}
@@ -876,10 +949,10 @@ func TestAgentHandler_ParseManifestEntries_Invalid(t *testing.T) {
 }
 
 // ---------------------------------------------------------------------------
-// LockData
+// BuildLockEntry
 // ---------------------------------------------------------------------------
 
-func TestAgentHandler_LockData(t *testing.T) {
+func TestAgentHandler_BuildLockEntry(t *testing.T) {
 	h := &AgentHandler{}
 
 	a := Asset{
@@ -892,7 +965,7 @@ func TestAgentHandler_LockData(t *testing.T) {
 		Ref:    "main",
 	}
 
-	locked := h.LockData(a, info)
+	locked := h.BuildLockEntry(a, info)
 	if locked.Kind != KindAgent {
 		t.Errorf("Kind = %q", locked.Kind)
 	}
@@ -15,11 +15,21 @@ import (
 type Kind string
 
 const (
-	KindSkill Kind = "skill"
-	KindMCP   Kind = "mcp"
-	KindAgent Kind = "agent"
+	KindSkill   Kind = "skill"
+	KindMCP     Kind = "mcp"
+	KindAgent   Kind = "agent"
+	KindCommand Kind = "command"
+	KindRule    Kind = "rule"
 )
 
+// IsSystemFile reports whether assets of the kind are single Markdown files
+// written into each supporting system's own directory, as agents, slash
+// commands, and rules are, rather than a shared skill directory or MCP
+// config entries.
+func IsSystemFile(k Kind) bool {
+	return k == KindAgent || k == KindCommand || k == KindRule
+}
+
 // Asset is the system-agnostic envelope describing something to install.
 // It is produced by an asset Handler and consumed by a System.
 type Asset struct {
@@ -56,8 +66,10 @@ type Handler interface {
 	// Registry: unmarshal kind-specific entries from a manifest's raw JSON.
 	ParseManifestEntries(raw json.RawMessage) ([]RegistryEntry, error)
 
-	// Lock file: produce lock data from an installed asset.
-	LockData(a Asset, info InstallInfo) LockedAsset
+	// Lock file: build the lock entry for an installed asset. Every
+	// install path (CLI, TUI, recommended, sync) goes through this, so the
+	// entry's shape is defined in one place per kind.
+	BuildLockEntry(a Asset, info InstallInfo) LockedAsset
 }
 
 // DiscoverOptions controls discovery behavior.
@@ -72,16 +84,95 @@ type RegistryEntry struct {
 	Name        string
 	Description string
 	Source      string
+	CloneURL    string // optional mirror to fetch Source from; Source stays its identity
 	Commit      string // optional pinned commit
+	Version     string // optional semantic version of the pinned commit, skills only
+	NoHydrate   bool   // "hydrate": false — don't resolve the latest commit when unpinned
 	Meta        Meta
+
+	// PostInstallMessage is shown after the asset is installed, e.g. a setup
+	// step the user has to run before using it.
+	PostInstallMessage string
+
+	// Platforms limits the entry to some OS/architectures, e.g.
+	// "darwin/arm64" or "linux". Empty means every platform.
+	Platforms []string
+
+	// Tags group entries into collections, e.g. "backend", that can be
+	// installed together with duckrow install --tag.
+	Tags []string
 }
 
 // InstallInfo carries context from the installation process, used by
-// the handler to produce lock file data.
+// the handler to build the lock entry.
 type InstallInfo struct {
 	Commit   string
 	Ref      string
-	Registry string
+	Registry string // registry name, for assets installed from registry config
+
+	// Files is the effective list of files copied for file-based assets,
+	// when ignore rules were in effect.
+	Files []string
+
+	// AliasOf is the upstream name when the asset was installed under an
+	// alias, and Namespace the namespace it was installed under, if any.
+	AliasOf   string
+	Namespace string
+
+	// Platforms and Tags carry over from the registry entry, or from the
+	// lock entry being updated.
+	Platforms []string
+	Tags      []string
+
+	// Version is the semantic version installed, for skills resolved from
+	// a version tag or a registry entry's version, and Constraint the
+	// version constraint asked for, e.g. "^1.2", if any.
+	Version    string
+	Constraint string
+}
+
+// Lock data keys shared by every kind.
+const (
+	lockFilesKey      = "files"
+	lockAliasOfKey    = "aliasOf"
+	lockNamespaceKey  = "namespace"
+	lockVersionKey    = "version"
+	lockConstraintKey = "constraint"
+)
+
+// sourceLockEntry builds the lock entry for an asset installed from a git
+// source, as skills, agents, commands, and rules are: source and commit,
+// plus the files, alias, namespace, and version when there are any.
+func sourceLockEntry(kind Kind, a Asset, info InstallInfo) LockedAsset {
+	data := make(map[string]any)
+	if len(info.Files) > 0 {
+		data[lockFilesKey] = info.Files
+	}
+	if info.AliasOf != "" {
+		data[lockAliasOfKey] = info.AliasOf
+	}
+	if info.Namespace != "" {
+		data[lockNamespaceKey] = info.Namespace
+	}
+	if info.Version != "" {
+		data[lockVersionKey] = info.Version
+	}
+	if info.Constraint != "" {
+		data[lockConstraintKey] = info.Constraint
+	}
+	if len(data) == 0 {
+		data = nil
+	}
+	return LockedAsset{
+		Kind:      kind,
+		Name:      a.Name,
+		Source:    a.Source,
+		Commit:    info.Commit,
+		Ref:       info.Ref,
+		Data:      data,
+		Platforms: info.Platforms,
+		Tags:      info.Tags,
+	}
 }
 
 // LockedAsset is the kind-agnostic lock file representation of an installed asset.
@@ -92,6 +183,15 @@ type LockedAsset struct {
 	Commit string         `json:"commit,omitempty"`
 	Ref    string         `json:"ref,omitempty"`
 	Data   map[string]any `json:"data,omitempty"` // kind-specific lock fields
+
+	// Platforms limits the entry to some OS/architectures ("darwin/arm64",
+	// "linux", "*/amd64"); sync skips it elsewhere. Empty means every
+	// platform.
+	Platforms []string `json:"platforms,omitempty"`
+
+	// Tags are the registry entry's tags at install time; sync --tag
+	// installs only the entries carrying a tag.
+	Tags []string `json:"tags,omitempty"`
 }
 
 // InstalledAsset represents an asset found on disk in a project folder.
@@ -102,7 +202,8 @@ type InstalledAsset struct {
 	Author      string // only set for file-based assets with metadata
 	Path        string // on-disk location
 	Meta        Meta
-	SystemName  string // which system owns this instance ("" = canonical/shared)
+	SystemName  string   // which system owns this instance ("" = canonical/shared)
+	Systems     []string // systems that see this asset, set by the folder scan
 }
 
 // --- Registry ---
@@ -120,17 +221,17 @@ func All() map[Kind]Handler { return handlers }
 
 // Kinds returns all registered asset kinds in a stable order.
 func Kinds() []Kind {
-	// Return in a deterministic order: skill, mcp, agent, then others.
+	// Return in a deterministic order: skill, mcp, agent, command, then others.
 	var known, other []Kind
 	for k := range handlers {
 		switch k {
-		case KindSkill, KindMCP, KindAgent:
+		case KindSkill, KindMCP, KindAgent, KindCommand, KindRule:
 			known = append(known, k)
 		default:
 			other = append(other, k)
 		}
 	}
-	// Sort known: skill, mcp, agent
+	// Sort known: skill, mcp, agent, command, rule
 	result := make([]Kind, 0, len(handlers))
 	if _, ok := handlers[KindSkill]; ok {
 		result = append(result, KindSkill)
@@ -141,6 +242,12 @@ func Kinds() []Kind {
 	if _, ok := handlers[KindAgent]; ok {
 		result = append(result, KindAgent)
 	}
+	if _, ok := handlers[KindCommand]; ok {
+		result = append(result, KindCommand)
+	}
+	if _, ok := handlers[KindRule]; ok {
+		result = append(result, KindRule)
+	}
 	// Append any other kinds (future extensibility)
 	_ = known // suppress unused
 	result = append(result, other...)
@@ -0,0 +1,249 @@
+package asset
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// CommandMeta holds slash-command metadata.
+type CommandMeta struct{}
+
+// AssetKind implements Meta.
+func (m CommandMeta) AssetKind() Kind { return KindCommand }
+
+// CommandData is a parsed slash-command file: its optional YAML frontmatter
+// (description, argument-hint, allowed-tools, ...) and the Markdown prompt.
+// Like agent frontmatter, it is opaque to duckrow apart from description.
+type CommandData struct {
+	Frontmatter map[string]any // nil when the file has no frontmatter
+	Body        string         // Markdown prompt
+	Raw         []byte         // the file as published
+}
+
+// CommandDataMeta wraps CommandData to satisfy the Meta interface while
+// carrying the full parsed content through the install pipeline.
+type CommandDataMeta struct {
+	CommandMeta
+	Data *CommandData
+}
+
+// commandDirs are the directory names slash commands are discovered in, as
+// laid out by the systems that read them: .claude/commands, .cursor/commands,
+// .opencode/command, or a plain commands/ directory in a repository.
+var commandDirs = map[string]bool{
+	"commands": true,
+	"command":  true,
+}
+
+// CommandHandler discovers and validates slash commands: Markdown files,
+// with or without frontmatter, named after the command they define.
+type CommandHandler struct{}
+
+func (h *CommandHandler) Kind() Kind          { return KindCommand }
+func (h *CommandHandler) DisplayName() string { return "Command" }
+
+// Discover walks basePath for .md files in a commands/ (or command/)
+// directory and returns an Asset for each one found, named after the file.
+// A SubPath pointing at a single .md file, or at a directory of them,
+// discovers those files wherever they live.
+func (h *CommandHandler) Discover(basePath string, opts DiscoverOptions) ([]Asset, error) {
+	searchPath := basePath
+	if opts.SubPath != "" {
+		searchPath = filepath.Join(basePath, opts.SubPath)
+	}
+
+	var assets []Asset
+	seen := make(map[string]bool)
+
+	add := func(path string) {
+		name := strings.TrimSuffix(filepath.Base(path), ".md")
+		if seen[name] || (opts.NameFilter != "" && name != opts.NameFilter) {
+			return
+		}
+		data, err := ParseCommandFile(path)
+		if err != nil {
+			return // skip unparseable files
+		}
+		seen[name] = true
+		assets = append(assets, Asset{
+			Kind:         KindCommand,
+			Name:         name,
+			Description:  data.Description(),
+			PreparedPath: path, // path to the .md file itself
+			Meta:         CommandDataMeta{Data: data},
+		})
+	}
+
+	if info, err := os.Stat(searchPath); err == nil && !info.IsDir() {
+		if strings.HasSuffix(searchPath, ".md") {
+			add(searchPath)
+		}
+		return assets, nil
+	}
+
+	err := filepath.WalkDir(searchPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // skip unreadable entries
+		}
+
+		// Skip hidden directories (except known command locations).
+		if d.IsDir() && path != searchPath {
+			name := d.Name()
+			if strings.HasPrefix(name, ".") {
+				switch name {
+				case ".agents", ".claude", ".cursor", ".opencode":
+					// Allow traversal into these directories.
+				default:
+					return filepath.SkipDir
+				}
+			}
+			switch name {
+			case "node_modules", "vendor", "__pycache__":
+				return filepath.SkipDir
+			}
+		}
+
+		if d.IsDir() || !strings.HasSuffix(d.Name(), ".md") || excludedAgentFiles[d.Name()] {
+			return nil
+		}
+
+		// Only files in a commands directory, or directly in the SubPath
+		// the source points at, are commands.
+		dir := filepath.Dir(path)
+		if !commandDirs[filepath.Base(dir)] && (opts.SubPath == "" || dir != searchPath) {
+			return nil
+		}
+		add(path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking %s: %w", searchPath, err)
+	}
+
+	return assets, nil
+}
+
+// Parse reads a slash command from a .md file at the given path.
+func (h *CommandHandler) Parse(path string) (Meta, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("commands are single .md files, not directories")
+	}
+
+	data, err := ParseCommandFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return CommandDataMeta{Data: data}, nil
+}
+
+// Validate checks that a command asset is well-formed for installation.
+func (h *CommandHandler) Validate(a Asset) error {
+	if a.Name == "" {
+		return fmt.Errorf("command name is required")
+	}
+
+	meta, ok := a.Meta.(CommandDataMeta)
+	if !ok {
+		// Allow plain CommandMeta (e.g., from registry entries without data).
+		if _, ok2 := a.Meta.(CommandMeta); ok2 {
+			return nil
+		}
+		return fmt.Errorf("expected CommandDataMeta or CommandMeta, got %T", a.Meta)
+	}
+
+	if strings.TrimSpace(meta.Data.Body) == "" {
+		return fmt.Errorf("command %q has an empty prompt", a.Name)
+	}
+	return nil
+}
+
+// ParseManifestEntries unmarshals command entries from a registry manifest.
+// They take the same fields as agent entries.
+func (h *CommandHandler) ParseManifestEntries(raw json.RawMessage) ([]RegistryEntry, error) {
+	var entries []agentManifestEntry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("unmarshaling command entries: %w", err)
+	}
+	result := make([]RegistryEntry, len(entries))
+	for i, e := range entries {
+		result[i] = RegistryEntry{
+			Name:        e.Name,
+			Description: e.Description,
+			Source:      e.Source,
+			CloneURL:    e.CloneURL,
+			Commit:      e.Commit,
+			NoHydrate:   e.Hydrate != nil && !*e.Hydrate,
+			Meta:        CommandMeta{},
+
+			PostInstallMessage: e.PostInstallMessage,
+			Platforms:          e.Platforms,
+			Tags:               e.Tags,
+		}
+	}
+	return result, nil
+}
+
+// BuildLockEntry builds the lock entry for an installed slash command.
+func (h *CommandHandler) BuildLockEntry(a Asset, info InstallInfo) LockedAsset {
+	return sourceLockEntry(KindCommand, a, info)
+}
+
+// ParseCommandFile reads a slash-command file.
+func ParseCommandFile(path string) (*CommandData, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return ParseCommandContent(raw, path)
+}
+
+// ParseCommandContent parses a slash command from raw bytes. Frontmatter is
+// optional: a file without it is all prompt. The source parameter is used
+// only for error messages.
+func ParseCommandContent(raw []byte, source string) (*CommandData, error) {
+	if !strings.HasPrefix(strings.TrimSpace(string(raw)), "---") {
+		return &CommandData{Body: string(raw), Raw: raw}, nil
+	}
+	data, err := ParseAgentContent(raw, source)
+	if err != nil {
+		return nil, err
+	}
+	// Drop the blank line after the frontmatter so the prompt rendered on
+	// its own starts with its first line.
+	body := strings.TrimLeft(data.Body, "\r\n")
+	return &CommandData{Frontmatter: data.Frontmatter, Body: body, Raw: raw}, nil
+}
+
+// Description returns the command's frontmatter description or, without
+// one, the first line of its prompt.
+func (d *CommandData) Description() string {
+	if desc, _ := d.Frontmatter["description"].(string); desc != "" {
+		return desc
+	}
+	for _, line := range strings.Split(d.Body, "\n") {
+		if line = strings.TrimSpace(strings.TrimLeft(line, "#")); line != "" {
+			return line
+		}
+	}
+	return ""
+}
+
+// Render returns the command file to write for a system: the file as
+// published for systems that read frontmatter, the prompt alone for those
+// that would show it as text.
+func (d *CommandData) Render(frontmatter bool) []byte {
+	if frontmatter {
+		return d.Raw
+	}
+	return []byte(d.Body)
+}
+
+func init() { Register(&CommandHandler{}) }
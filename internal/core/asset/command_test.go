@@ -0,0 +1,135 @@
+package asset
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestCommandHandler_Registered(t *testing.T) {
+	h, ok := Get(KindCommand)
+	if !ok {
+		t.Fatal("command handler not registered")
+	}
+	if h.DisplayName() != "Command" {
+		t.Errorf("DisplayName() = %q, want %q", h.DisplayName(), "Command")
+	}
+	if !IsSystemFile(KindCommand) {
+		t.Error("IsSystemFile(KindCommand) = false, want true")
+	}
+}
+
+func TestParseCommandContent(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		wantDesc string
+		wantBody string
+		wantFM   bool
+	}{
+		{
+			name:     "frontmatter",
+			content:  "---\ndescription: Review the diff\nargument-hint: [file]\n---\n\nReview $ARGUMENTS.\n",
+			wantDesc: "Review the diff",
+			wantBody: "Review $ARGUMENTS.\n",
+			wantFM:   true,
+		},
+		{
+			name:     "plain prompt",
+			content:  "# Write tests\n\nWrite tests for $ARGUMENTS.\n",
+			wantDesc: "Write tests",
+			wantBody: "# Write tests\n\nWrite tests for $ARGUMENTS.\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := ParseCommandContent([]byte(tt.content), "test.md")
+			if err != nil {
+				t.Fatalf("ParseCommandContent() error: %v", err)
+			}
+			if got := data.Description(); got != tt.wantDesc {
+				t.Errorf("Description() = %q, want %q", got, tt.wantDesc)
+			}
+			if data.Body != tt.wantBody {
+				t.Errorf("Body = %q, want %q", data.Body, tt.wantBody)
+			}
+			if (data.Frontmatter != nil) != tt.wantFM {
+				t.Errorf("Frontmatter = %v, want present = %v", data.Frontmatter, tt.wantFM)
+			}
+			if got := string(data.Render(true)); got != tt.content {
+				t.Errorf("Render(true) = %q, want the file as published", got)
+			}
+			if got := string(data.Render(false)); got != tt.wantBody {
+				t.Errorf("Render(false) = %q, want %q", got, tt.wantBody)
+			}
+		})
+	}
+}
+
+func TestCommandHandler_Discover(t *testing.T) {
+	dir := t.TempDir()
+	files := map[string]string{
+		"commands/review.md":         "---\ndescription: Review the diff\n---\n\nReview it.\n",
+		".claude/commands/test.md":   "Write tests.\n",
+		"commands/README.md":         "# Commands\n",
+		"agents/reviewer.md":         "---\nname: reviewer\ndescription: Reviews\n---\n\nBody.\n",
+		"node_modules/commands/x.md": "Ignored.\n",
+	}
+	for path, content := range files {
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	h := &CommandHandler{}
+	assets, err := h.Discover(dir, DiscoverOptions{})
+	if err != nil {
+		t.Fatalf("Discover() error: %v", err)
+	}
+	got := make(map[string]string)
+	for _, a := range assets {
+		got[a.Name] = a.Description
+	}
+	want := map[string]string{"review": "Review the diff", "test": "Write tests."}
+	if len(got) != len(want) {
+		t.Fatalf("Discover() found %v, want %v", got, want)
+	}
+	for name, desc := range want {
+		if got[name] != desc {
+			t.Errorf("command %q description = %q, want %q", name, got[name], desc)
+		}
+	}
+
+	// A SubPath may point at a single file outside any commands directory.
+	assets, err = h.Discover(dir, DiscoverOptions{SubPath: "agents/reviewer.md"})
+	if err != nil {
+		t.Fatalf("Discover(SubPath) error: %v", err)
+	}
+	if len(assets) != 1 || assets[0].Name != "reviewer" {
+		t.Errorf("Discover(SubPath) = %v, want the reviewer file", assets)
+	}
+
+	assets, err = h.Discover(dir, DiscoverOptions{NameFilter: "test"})
+	if err != nil {
+		t.Fatalf("Discover(NameFilter) error: %v", err)
+	}
+	if len(assets) != 1 || assets[0].Name != "test" {
+		t.Errorf("Discover(NameFilter) = %v, want only test", assets)
+	}
+}
+
+func TestCommandHandler_Validate(t *testing.T) {
+	h := &CommandHandler{}
+	empty := Asset{Kind: KindCommand, Name: "blank", Meta: CommandDataMeta{Data: &CommandData{Body: "  \n"}}}
+	if err := h.Validate(empty); err == nil {
+		t.Error("Validate() accepted a command with an empty prompt")
+	}
+	plain := Asset{Kind: KindCommand, Name: "review", Meta: CommandMeta{}}
+	if err := h.Validate(plain); err != nil {
+		t.Errorf("Validate() error = %v", err)
+	}
+}
@@ -72,6 +72,10 @@ type mcpManifestEntry struct {
 	Env         []string `json:"env,omitempty"`
 	URL         string   `json:"url,omitempty"`
 	Type        string   `json:"type,omitempty"`
+
+	PostInstallMessage string   `json:"postInstallMessage,omitempty"`
+	Platforms          []string `json:"platforms,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
 }
 
 // ParseManifestEntries unmarshals MCP entries from a registry manifest.
@@ -92,56 +96,89 @@ func (h *MCPHandler) ParseManifestEntries(raw json.RawMessage) ([]RegistryEntry,
 				URL:       e.URL,
 				Transport: e.Type,
 			},
+			PostInstallMessage: e.PostInstallMessage,
+			Platforms:          e.Platforms,
+			Tags:               e.Tags,
 		}
 	}
 	return result, nil
 }
 
-// LockData produces a LockedAsset from an MCP installation.
-func (h *MCPHandler) LockData(a Asset, info InstallInfo) LockedAsset {
+// lockURLHashKey is the lock data key holding the URLHash of a remote MCP,
+// which marks the config entries duckrow wrote for it.
+const lockURLHashKey = "urlHash"
+
+// BuildLockEntry builds the lock entry for an installed MCP. MCPs have no
+// source or commit; the entry records the registry the config came from, a
+// hash of the config to detect registry changes, the env vars it needs,
+// and, for remote servers, a hash of the URL written to config files.
+func (h *MCPHandler) BuildLockEntry(a Asset, info InstallInfo) LockedAsset {
 	meta, _ := a.Meta.(MCPMeta)
 	data := map[string]any{
 		"registry":   info.Registry,
-		"configHash": computeConfigHash(meta),
+		"configHash": ConfigHash(meta),
 	}
-	if envKeys := extractRequiredEnv(meta.Env); len(envKeys) > 0 {
+	if meta.URL != "" {
+		data[lockURLHashKey] = URLHash(meta.URL)
+	}
+	if envKeys := RequiredEnv(meta.Env); len(envKeys) > 0 {
 		data["requiredEnv"] = envKeys
 	}
+	if info.AliasOf != "" {
+		data[lockAliasOfKey] = info.AliasOf
+	}
 	return LockedAsset{
-		Kind: KindMCP,
-		Name: a.Name,
-		Data: data,
+		Kind:      KindMCP,
+		Name:      a.Name,
+		Data:      data,
+		Platforms: info.Platforms,
+		Tags:      info.Tags,
 	}
 }
 
-// computeConfigHash produces a deterministic hash of the MCP config for
-// change detection in the lock file.
-func computeConfigHash(meta MCPMeta) string {
-	// Use a canonical JSON encoding of the config fields.
-	canonical := struct {
-		Command   string   `json:"command,omitempty"`
-		Args      []string `json:"args,omitempty"`
-		Env       []string `json:"env,omitempty"`
-		URL       string   `json:"url,omitempty"`
-		Transport string   `json:"type,omitempty"`
-	}{
-		Command:   meta.Command,
-		Args:      meta.Args,
-		Env:       meta.Env,
-		URL:       meta.URL,
-		Transport: meta.Transport,
-	}
-	data, _ := json.Marshal(canonical)
-
-	// Simple hash using crypto/sha256.
-	// Import inline to avoid polluting the package imports when not needed.
-	// We use a package-level import instead.
+// ConfigHash computes a SHA-256 hash of an MCP config's fields, used in the
+// lock file to detect registry changes. The hash input is a deterministic
+// JSON object with the env names sorted; the result has a "sha256:" prefix.
+func ConfigHash(meta MCPMeta) string {
+	m := make(map[string]any)
+	if meta.Command != "" {
+		m["command"] = meta.Command
+	}
+	if len(meta.Args) > 0 {
+		m["args"] = meta.Args
+	}
+	if len(meta.Env) > 0 {
+		sorted := make([]string, len(meta.Env))
+		copy(sorted, meta.Env)
+		sort.Strings(sorted)
+		m["env"] = sorted
+	}
+	if meta.URL != "" {
+		m["url"] = meta.URL
+	}
+	if meta.Transport != "" {
+		m["type"] = meta.Transport
+	}
+	data, _ := json.Marshal(m)
 	return hashBytes(data)
 }
 
-// extractRequiredEnv returns the env var names from the Env list.
-// Returns a sorted, deduplicated copy.
-func extractRequiredEnv(env []string) []string {
+// URLHash computes the SHA-256 hash of a remote MCP's URL, with a "sha256:"
+// prefix. The lock file records it rather than the URL, which may carry a
+// key.
+func URLHash(url string) string {
+	return hashBytes([]byte(url))
+}
+
+// LockedURLHash returns the URL hash recorded in an MCP lock entry, or ""
+// for stdio servers and entries locked by older versions.
+func LockedURLHash(locked LockedAsset) string {
+	h, _ := locked.Data[lockURLHashKey].(string)
+	return h
+}
+
+// RequiredEnv returns a sorted, deduplicated copy of the env var names.
+func RequiredEnv(env []string) []string {
 	if len(env) == 0 {
 		return nil
 	}
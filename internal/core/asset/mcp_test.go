@@ -136,7 +136,8 @@ func TestMCPHandler_ParseManifestEntries(t *testing.T) {
 			"description": "Database access",
 			"command": "npx",
 			"args": ["-y", "@internal/db"],
-			"env": ["DB_URL"]
+			"env": ["DB_URL"],
+			"postInstallMessage": "Run make migrate first."
 		},
 		{
 			"name": "remote-api",
@@ -171,6 +172,12 @@ func TestMCPHandler_ParseManifestEntries(t *testing.T) {
 	if len(meta0.Env) != 1 || meta0.Env[0] != "DB_URL" {
 		t.Errorf("Env = %v, want [DB_URL]", meta0.Env)
 	}
+	if entries[0].PostInstallMessage != "Run make migrate first." {
+		t.Errorf("PostInstallMessage = %q", entries[0].PostInstallMessage)
+	}
+	if entries[1].PostInstallMessage != "" {
+		t.Errorf("entries[1].PostInstallMessage = %q, want empty", entries[1].PostInstallMessage)
+	}
 
 	// Remote entry.
 	meta1, ok := entries[1].Meta.(MCPMeta)
@@ -185,7 +192,7 @@ func TestMCPHandler_ParseManifestEntries(t *testing.T) {
 	}
 }
 
-func TestMCPHandler_LockData(t *testing.T) {
+func TestMCPHandler_BuildLockEntry(t *testing.T) {
 	h := &MCPHandler{}
 
 	a := Asset{
@@ -201,7 +208,7 @@ func TestMCPHandler_LockData(t *testing.T) {
 		Registry: "my-org",
 	}
 
-	locked := h.LockData(a, info)
+	locked := h.BuildLockEntry(a, info)
 	if locked.Kind != KindMCP {
 		t.Errorf("Kind = %q", locked.Kind)
 	}
@@ -234,3 +241,20 @@ func TestMCPHandler_LockData(t *testing.T) {
 		t.Errorf("requiredEnv len = %d, want 1", len(env))
 	}
 }
+
+func TestMCPHandler_BuildLockEntry_Alias(t *testing.T) {
+	h := &MCPHandler{}
+	a := Asset{Kind: KindMCP, Name: "db", Meta: MCPMeta{Command: "npx"}}
+
+	locked := h.BuildLockEntry(a, InstallInfo{Registry: "my-org", AliasOf: "db-server", Tags: []string{"data"}})
+	if locked.Name != "db" || locked.Data["aliasOf"] != "db-server" {
+		t.Errorf("Name = %q, aliasOf = %v", locked.Name, locked.Data["aliasOf"])
+	}
+	if len(locked.Tags) != 1 || locked.Tags[0] != "data" {
+		t.Errorf("Tags = %v", locked.Tags)
+	}
+	// The hash ignores the order of env names.
+	if ConfigHash(MCPMeta{Env: []string{"A", "B"}}) != ConfigHash(MCPMeta{Env: []string{"B", "A"}}) {
+		t.Error("ConfigHash depends on env order")
+	}
+}
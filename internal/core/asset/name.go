@@ -0,0 +1,55 @@
+package asset
+
+import (
+	"fmt"
+	"strings"
+)
+
+// maxNameLength is the longest allowed asset name, the same limit the Agent
+// Skills format places on skill names.
+const maxNameLength = 64
+
+// NameError reports an asset name that cannot be used as-is for a skill
+// directory, symlink, agent file name, or MCP server key.
+type NameError struct {
+	Name   string
+	Reason string
+}
+
+func (e *NameError) Error() string {
+	return fmt.Sprintf("invalid name %q: %s", e.Name, e.Reason)
+}
+
+// ValidateName checks that name uses only lowercase letters, digits, and
+// hyphens, starting with a letter or digit. The returned *NameError says what
+// is wrong, so a registry author or user can fix the name.
+func ValidateName(name string) error {
+	reason := nameProblem(name)
+	if reason == "" {
+		return nil
+	}
+	return &NameError{Name: name, Reason: reason}
+}
+
+func nameProblem(name string) string {
+	switch {
+	case name == "":
+		return "name is empty"
+	case len(name) > maxNameLength:
+		return fmt.Sprintf("longer than %d characters", maxNameLength)
+	case strings.ContainsAny(name, `/\`):
+		return "contains a path separator"
+	case strings.Contains(name, "."):
+		return "contains a dot"
+	case strings.ToLower(name) != name:
+		return "contains uppercase letters; use lowercase letters, digits, and hyphens"
+	case name[0] == '-':
+		return "must start with a letter or digit"
+	}
+	for _, c := range name {
+		if (c < 'a' || c > 'z') && (c < '0' || c > '9') && c != '-' {
+			return fmt.Sprintf("contains %q; use lowercase letters, digits, and hyphens", c)
+		}
+	}
+	return ""
+}
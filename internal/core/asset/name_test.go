@@ -0,0 +1,42 @@
+package asset
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestValidateName(t *testing.T) {
+	for _, name := range []string{"go-review", "db2", "a", strings.Repeat("x", 64)} {
+		if err := ValidateName(name); err != nil {
+			t.Errorf("ValidateName(%q) error = %v", name, err)
+		}
+	}
+
+	tests := []struct {
+		name   string
+		reason string
+	}{
+		{"", "empty"},
+		{"a/b", "path separator"},
+		{`a\b`, "path separator"},
+		{"..", "dot"},
+		{"go.review", "dot"},
+		{"Go-Review", "uppercase"},
+		{"-db", "start with a letter or digit"},
+		{"go review", `contains ' '`},
+		{"go_review", `contains '_'`},
+		{strings.Repeat("x", 65), "longer than 64"},
+	}
+	for _, tt := range tests {
+		err := ValidateName(tt.name)
+		var nameErr *NameError
+		if !errors.As(err, &nameErr) {
+			t.Errorf("ValidateName(%q) = %v, want *NameError", tt.name, err)
+			continue
+		}
+		if !strings.Contains(nameErr.Reason, tt.reason) {
+			t.Errorf("ValidateName(%q) reason = %q, want it to contain %q", tt.name, nameErr.Reason, tt.reason)
+		}
+	}
+}
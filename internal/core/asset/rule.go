@@ -0,0 +1,377 @@
+package asset
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RuleMeta holds editor rule metadata.
+type RuleMeta struct{}
+
+// AssetKind implements Meta.
+func (m RuleMeta) AssetKind() Kind { return KindRule }
+
+// RuleData is a parsed rule file, normalized from whichever format it was
+// published in: a Cursor .mdc rule (description, globs, alwaysApply) or a
+// GitHub Copilot .instructions.md file (description, applyTo).
+type RuleData struct {
+	Description string
+	Globs       []string // files the rule applies to; empty = not path-scoped
+	AlwaysApply bool     // applies to every request, regardless of Globs
+	Body        string   // Markdown instructions
+}
+
+// RuleDataMeta wraps RuleData to satisfy the Meta interface while carrying
+// the full parsed content through the install pipeline.
+type RuleDataMeta struct {
+	RuleMeta
+	Data *RuleData
+}
+
+// ruleExts are the file extensions rules are published with, longest
+// first so a name is derived by stripping the most specific one.
+var ruleExts = []string{".instructions.md", ".mdc", ".md"}
+
+// ruleDirs are the directory names rules are discovered in, as laid out by
+// the systems that read them: .cursor/rules, .github/instructions, or a
+// plain rules/ directory in a repository.
+var ruleDirs = map[string]bool{
+	"rules":        true,
+	"instructions": true,
+}
+
+// RuleHandler discovers and validates editor rules: Markdown files with
+// optional frontmatter, named after the file they are published in.
+type RuleHandler struct{}
+
+func (h *RuleHandler) Kind() Kind          { return KindRule }
+func (h *RuleHandler) DisplayName() string { return "Rule" }
+
+// Discover walks basePath for rule files in a rules/ (or instructions/)
+// directory and returns an Asset for each one found. A SubPath pointing at
+// a single rule file, or at a directory of them, discovers those files
+// wherever they live.
+func (h *RuleHandler) Discover(basePath string, opts DiscoverOptions) ([]Asset, error) {
+	searchPath := basePath
+	if opts.SubPath != "" {
+		searchPath = filepath.Join(basePath, opts.SubPath)
+	}
+
+	var assets []Asset
+	seen := make(map[string]bool)
+
+	add := func(path string) {
+		name := RuleName(filepath.Base(path))
+		if seen[name] || (opts.NameFilter != "" && name != opts.NameFilter) {
+			return
+		}
+		data, err := ParseRuleFile(path)
+		if err != nil {
+			return // skip unparseable files
+		}
+		seen[name] = true
+		assets = append(assets, Asset{
+			Kind:         KindRule,
+			Name:         name,
+			Description:  data.Description,
+			PreparedPath: path, // path to the rule file itself
+			Meta:         RuleDataMeta{Data: data},
+		})
+	}
+
+	if info, err := os.Stat(searchPath); err == nil && !info.IsDir() {
+		if isRuleFile(searchPath) {
+			add(searchPath)
+		}
+		return assets, nil
+	}
+
+	err := filepath.WalkDir(searchPath, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // skip unreadable entries
+		}
+
+		// Skip hidden directories (except known rule locations).
+		if d.IsDir() && path != searchPath {
+			name := d.Name()
+			if strings.HasPrefix(name, ".") {
+				switch name {
+				case ".agents", ".cursor", ".github":
+					// Allow traversal into these directories.
+				default:
+					return filepath.SkipDir
+				}
+			}
+			switch name {
+			case "node_modules", "vendor", "__pycache__":
+				return filepath.SkipDir
+			}
+		}
+
+		if d.IsDir() || !isRuleFile(path) || excludedAgentFiles[d.Name()] {
+			return nil
+		}
+
+		// Only files in a rules directory, or directly in the SubPath the
+		// source points at, are rules.
+		dir := filepath.Dir(path)
+		if !ruleDirs[filepath.Base(dir)] && (opts.SubPath == "" || dir != searchPath) {
+			return nil
+		}
+		add(path)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("walking %s: %w", searchPath, err)
+	}
+
+	return assets, nil
+}
+
+// Parse reads a rule from a file at the given path.
+func (h *RuleHandler) Parse(path string) (Meta, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return nil, err
+	}
+	if info.IsDir() {
+		return nil, fmt.Errorf("rules are single files, not directories")
+	}
+
+	data, err := ParseRuleFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return RuleDataMeta{Data: data}, nil
+}
+
+// Validate checks that a rule asset is well-formed for installation.
+func (h *RuleHandler) Validate(a Asset) error {
+	if a.Name == "" {
+		return fmt.Errorf("rule name is required")
+	}
+
+	meta, ok := a.Meta.(RuleDataMeta)
+	if !ok {
+		// Allow plain RuleMeta (e.g., from registry entries without data).
+		if _, ok2 := a.Meta.(RuleMeta); ok2 {
+			return nil
+		}
+		return fmt.Errorf("expected RuleDataMeta or RuleMeta, got %T", a.Meta)
+	}
+
+	if strings.TrimSpace(meta.Data.Body) == "" {
+		return fmt.Errorf("rule %q has no instructions", a.Name)
+	}
+	return nil
+}
+
+// ParseManifestEntries unmarshals rule entries from a registry manifest.
+// They take the same fields as agent entries.
+func (h *RuleHandler) ParseManifestEntries(raw json.RawMessage) ([]RegistryEntry, error) {
+	var entries []agentManifestEntry
+	if err := json.Unmarshal(raw, &entries); err != nil {
+		return nil, fmt.Errorf("unmarshaling rule entries: %w", err)
+	}
+	result := make([]RegistryEntry, len(entries))
+	for i, e := range entries {
+		result[i] = RegistryEntry{
+			Name:        e.Name,
+			Description: e.Description,
+			Source:      e.Source,
+			CloneURL:    e.CloneURL,
+			Commit:      e.Commit,
+			NoHydrate:   e.Hydrate != nil && !*e.Hydrate,
+			Meta:        RuleMeta{},
+
+			PostInstallMessage: e.PostInstallMessage,
+			Platforms:          e.Platforms,
+			Tags:               e.Tags,
+		}
+	}
+	return result, nil
+}
+
+// BuildLockEntry builds the lock entry for an installed rule.
+func (h *RuleHandler) BuildLockEntry(a Asset, info InstallInfo) LockedAsset {
+	return sourceLockEntry(KindRule, a, info)
+}
+
+// RuleName returns the rule name for a rule file name, without its
+// extension: go-style.mdc, go-style.instructions.md, and go-style.md are
+// all go-style.
+func RuleName(filename string) string {
+	for _, ext := range ruleExts {
+		if strings.HasSuffix(filename, ext) {
+			return strings.TrimSuffix(filename, ext)
+		}
+	}
+	return filename
+}
+
+func isRuleFile(path string) bool {
+	return strings.HasSuffix(path, ".mdc") || strings.HasSuffix(path, ".md")
+}
+
+// ParseRuleFile reads a rule file.
+func ParseRuleFile(path string) (*RuleData, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", path, err)
+	}
+	return ParseRuleContent(raw, path)
+}
+
+// ParseRuleContent parses a rule from raw bytes. Frontmatter is optional: a
+// file without it is all instructions. The source parameter is used only
+// for error messages.
+//
+// Cursor writes globs unquoted (globs: *.ts), which YAML reads as an
+// alias, so frontmatter that isn't valid YAML is read as plain key: value
+// lines instead.
+func ParseRuleContent(raw []byte, source string) (*RuleData, error) {
+	content := string(raw)
+	if !strings.HasPrefix(strings.TrimSpace(content), "---") {
+		return &RuleData{Body: content}, nil
+	}
+
+	start := strings.Index(content, "---")
+	rest := strings.TrimPrefix(strings.TrimPrefix(content[start+3:], "\r"), "\n")
+	end := strings.Index(rest, "\n---")
+	if end < 0 {
+		return nil, fmt.Errorf("no closing frontmatter delimiter in %s", source)
+	}
+	fmContent := rest[:end]
+	body := strings.TrimLeft(rest[end+4:], "\r\n")
+
+	var fm map[string]any
+	if err := yaml.Unmarshal([]byte(fmContent), &fm); err != nil {
+		fm = parsePlainFrontmatter(fmContent)
+	}
+
+	data := &RuleData{Body: body}
+	data.Description, _ = fm["description"].(string)
+	data.Globs = ruleGlobs(fm["globs"])
+	if len(data.Globs) == 0 {
+		data.Globs = ruleGlobs(fm["applyTo"])
+	}
+	switch v := fm["alwaysApply"].(type) {
+	case bool:
+		data.AlwaysApply = v
+	case string:
+		data.AlwaysApply, _ = strconv.ParseBool(v)
+	}
+	// Copilot spells "every file" as applyTo: "**".
+	if len(data.Globs) == 1 && data.Globs[0] == "**" {
+		data.Globs = nil
+		data.AlwaysApply = true
+	}
+	return data, nil
+}
+
+// parsePlainFrontmatter reads frontmatter as key: value lines.
+func parsePlainFrontmatter(content string) map[string]any {
+	fm := make(map[string]any)
+	for _, line := range strings.Split(content, "\n") {
+		key, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		fm[strings.TrimSpace(key)] = strings.Trim(strings.TrimSpace(value), `"'`)
+	}
+	return fm
+}
+
+// ruleGlobs reads a globs or applyTo value, a comma-separated string or a
+// list of strings.
+func ruleGlobs(v any) []string {
+	var parts []string
+	switch v := v.(type) {
+	case string:
+		parts = strings.Split(v, ",")
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				parts = append(parts, s)
+			}
+		}
+	}
+	var globs []string
+	for _, p := range parts {
+		if p = strings.TrimSpace(p); p != "" {
+			globs = append(globs, p)
+		}
+	}
+	return globs
+}
+
+// RenderRuleForSystem renders a rule in the format a system reads:
+//
+//   - cursor: .mdc frontmatter with description, globs, and alwaysApply
+//   - github-copilot: .instructions.md frontmatter with description and
+//     applyTo ("**" for a rule that always applies)
+//
+// Any other system gets the instructions alone.
+func RenderRuleForSystem(data *RuleData, systemKey string) ([]byte, error) {
+	if data == nil {
+		return nil, fmt.Errorf("rule data is nil")
+	}
+
+	var fm []string
+	switch systemKey {
+	case "cursor":
+		// Cursor writes every key, empty ones bare, and reads globs as a raw
+		// comma-separated list rather than YAML.
+		desc, globs := "description:", "globs:"
+		if data.Description != "" {
+			desc += " " + yamlScalar(data.Description)
+		}
+		if len(data.Globs) > 0 {
+			globs += " " + strings.Join(data.Globs, ",")
+		}
+		fm = append(fm, desc, globs, "alwaysApply: "+strconv.FormatBool(data.AlwaysApply))
+	case "github-copilot":
+		if data.Description != "" {
+			fm = append(fm, "description: "+yamlScalar(data.Description))
+		}
+		switch {
+		case data.AlwaysApply:
+			fm = append(fm, `applyTo: "**"`)
+		case len(data.Globs) > 0:
+			fm = append(fm, "applyTo: "+yamlScalar(strings.Join(data.Globs, ",")))
+		}
+	}
+
+	var buf bytes.Buffer
+	if len(fm) > 0 {
+		buf.WriteString("---\n")
+		buf.WriteString(strings.Join(fm, "\n"))
+		buf.WriteString("\n---\n\n")
+	}
+	buf.WriteString(data.Body)
+	if data.Body != "" && !strings.HasSuffix(data.Body, "\n") {
+		buf.WriteString("\n")
+	}
+	return buf.Bytes(), nil
+}
+
+// yamlScalar renders s as a one-line YAML scalar, quoted when YAML would
+// otherwise read it differently.
+func yamlScalar(s string) string {
+	s = strings.Join(strings.Fields(s), " ")
+	out, err := yaml.Marshal(s)
+	if err != nil {
+		return strconv.Quote(s)
+	}
+	return strings.TrimSuffix(string(out), "\n")
+}
+
+func init() { Register(&RuleHandler{}) }
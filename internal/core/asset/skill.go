@@ -21,6 +21,11 @@ type SkillMeta struct {
 	Internal bool   `yaml:"internal,omitempty"`
 	ArgHint  string `yaml:"argument-hint,omitempty"`
 	License  string `yaml:"license,omitempty"`
+
+	// Requires lists glob patterns, relative to the project root, of files
+	// the skill needs to be useful (e.g. go.mod or .eslintrc*). Each pattern
+	// must match at least one file; unmet ones are warned about.
+	Requires []string `yaml:"requires,omitempty"`
 }
 
 // AssetKind implements Meta.
@@ -33,10 +38,11 @@ type skillFrontmatter struct {
 	Description string `yaml:"description"`
 	License     string `yaml:"license,omitempty"`
 	Metadata    struct {
-		Author   string `yaml:"author,omitempty"`
-		Version  string `yaml:"version,omitempty"`
-		Internal bool   `yaml:"internal,omitempty"`
-		ArgHint  string `yaml:"argument-hint,omitempty"`
+		Author   string   `yaml:"author,omitempty"`
+		Version  string   `yaml:"version,omitempty"`
+		Internal bool     `yaml:"internal,omitempty"`
+		ArgHint  string   `yaml:"argument-hint,omitempty"`
+		Requires []string `yaml:"requires,omitempty"`
 	} `yaml:"metadata,omitempty"`
 }
 
@@ -55,34 +61,9 @@ func (h *SkillHandler) Discover(basePath string, opts DiscoverOptions) ([]Asset,
 	}
 
 	var assets []Asset
-	seen := make(map[string]bool)
-
-	err := filepath.WalkDir(searchPath, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
-			return nil // skip unreadable entries
-		}
-
-		// Skip hidden directories (except .agents which is a known skills location).
-		if d.IsDir() && path != searchPath {
-			name := d.Name()
-			if strings.HasPrefix(name, ".") && name != ".agents" {
-				return filepath.SkipDir
-			}
-			switch name {
-			case "node_modules", "vendor", "__pycache__":
-				return filepath.SkipDir
-			}
-		}
-
-		if d.IsDir() || d.Name() != skillFileName {
-			return nil
-		}
 
+	err := walkSkillManifests(searchPath, func(path string) error {
 		skillDir := filepath.Dir(path)
-		if seen[skillDir] {
-			return nil
-		}
-		seen[skillDir] = true
 
 		fm, err := parseSkillFrontmatter(path)
 		if err != nil {
@@ -95,6 +76,7 @@ func (h *SkillHandler) Discover(basePath string, opts DiscoverOptions) ([]Asset,
 			Internal: fm.Metadata.Internal,
 			ArgHint:  fm.Metadata.ArgHint,
 			License:  fm.License,
+			Requires: fm.Metadata.Requires,
 		}
 
 		// Apply internal filter.
@@ -123,6 +105,33 @@ func (h *SkillHandler) Discover(basePath string, opts DiscoverOptions) ([]Asset,
 	return assets, nil
 }
 
+// walkSkillManifests calls fn with the path of each SKILL.md under root,
+// skipping hidden directories (except .agents) and dependency folders.
+func walkSkillManifests(root string, fn func(path string) error) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil // skip unreadable entries
+		}
+
+		// Skip hidden directories (except .agents which is a known skills location).
+		if d.IsDir() && path != root {
+			name := d.Name()
+			if strings.HasPrefix(name, ".") && name != ".agents" {
+				return filepath.SkipDir
+			}
+			switch name {
+			case "node_modules", "vendor", "__pycache__":
+				return filepath.SkipDir
+			}
+		}
+
+		if d.IsDir() || d.Name() != skillFileName {
+			return nil
+		}
+		return fn(path)
+	})
+}
+
 // Parse reads SKILL.md frontmatter at the given directory path.
 func (h *SkillHandler) Parse(path string) (Meta, error) {
 	skillMdPath := filepath.Join(path, skillFileName)
@@ -136,6 +145,7 @@ func (h *SkillHandler) Parse(path string) (Meta, error) {
 		Internal: fm.Metadata.Internal,
 		ArgHint:  fm.Metadata.ArgHint,
 		License:  fm.License,
+		Requires: fm.Metadata.Requires,
 	}, nil
 }
 
@@ -155,7 +165,14 @@ type skillManifestEntry struct {
 	Name        string `json:"name"`
 	Description string `json:"description"`
 	Source      string `json:"source"`
+	CloneURL    string `json:"cloneUrl,omitempty"`
 	Commit      string `json:"commit,omitempty"`
+	Version     string `json:"version,omitempty"`
+	Hydrate     *bool  `json:"hydrate,omitempty"`
+
+	PostInstallMessage string   `json:"postInstallMessage,omitempty"`
+	Platforms          []string `json:"platforms,omitempty"`
+	Tags               []string `json:"tags,omitempty"`
 }
 
 // ParseManifestEntries unmarshals skill entries from a registry manifest.
@@ -170,29 +187,105 @@ func (h *SkillHandler) ParseManifestEntries(raw json.RawMessage) ([]RegistryEntr
 			Name:        e.Name,
 			Description: e.Description,
 			Source:      e.Source,
+			CloneURL:    e.CloneURL,
 			Commit:      e.Commit,
+			Version:     e.Version,
+			NoHydrate:   e.Hydrate != nil && !*e.Hydrate,
 			Meta:        SkillMeta{},
+
+			PostInstallMessage: e.PostInstallMessage,
+			Platforms:          e.Platforms,
+			Tags:               e.Tags,
 		}
 	}
 	return result, nil
 }
 
-// LockData produces a LockedAsset from a skill installation.
-func (h *SkillHandler) LockData(a Asset, info InstallInfo) LockedAsset {
-	return LockedAsset{
-		Kind:   KindSkill,
-		Name:   a.Name,
-		Source: a.Source,
-		Commit: info.Commit,
-		Ref:    info.Ref,
+// BuildLockEntry builds the lock entry for an installed skill.
+func (h *SkillHandler) BuildLockEntry(a Asset, info InstallInfo) LockedAsset {
+	return sourceLockEntry(KindSkill, a, info)
+}
+
+// SkillValidationError lists what is wrong with a skill's SKILL.md.
+type SkillValidationError struct {
+	Path     string // path to the SKILL.md
+	Problems []string
+}
+
+func (e *SkillValidationError) Error() string {
+	return fmt.Sprintf("%s: %s", e.Path, strings.Join(e.Problems, "; "))
+}
+
+// ValidateSkill checks the SKILL.md in dir before it is installed: the
+// frontmatter must be present, closed, and valid YAML, and must have a name
+// and a description. When dirName is not empty, the name must also match it,
+// since agents look skills up by directory name. The returned error is a
+// *SkillValidationError listing every problem found.
+func ValidateSkill(dir, dirName string) error {
+	path := filepath.Join(dir, skillFileName)
+	fm, closed, err := readSkillFrontmatter(path)
+	if err != nil {
+		return &SkillValidationError{Path: path, Problems: []string{err.Error()}}
+	}
+
+	var problems []string
+	if !closed {
+		problems = append(problems, "frontmatter is not closed with ---")
+	}
+	switch {
+	case fm.Name == "":
+		problems = append(problems, "name is missing")
+	case dirName != "" && fm.Name != dirName:
+		problems = append(problems, fmt.Sprintf("name %q does not match directory %q", fm.Name, dirName))
+	}
+	if strings.TrimSpace(fm.Description) == "" {
+		problems = append(problems, "description is missing")
+	}
+	if len(problems) > 0 {
+		return &SkillValidationError{Path: path, Problems: problems}
+	}
+	return nil
+}
+
+// InvalidSkills validates every SKILL.md under basePath (or its subPath) and
+// returns the errors for the ones Discover would skip because they cannot be
+// parsed. It explains why a source that contains skills yielded none.
+func InvalidSkills(basePath, subPath string) []error {
+	searchPath := basePath
+	if subPath != "" {
+		searchPath = filepath.Join(basePath, subPath)
 	}
+
+	var errs []error
+	_ = walkSkillManifests(searchPath, func(path string) error {
+		if _, err := parseSkillFrontmatter(path); err != nil {
+			errs = append(errs, ValidateSkill(filepath.Dir(path), ""))
+		}
+		return nil
+	})
+	return errs
 }
 
 // parseSkillFrontmatter reads YAML frontmatter from a SKILL.md file.
 func parseSkillFrontmatter(path string) (*skillFrontmatter, error) {
+	fm, _, err := readSkillFrontmatter(path)
+	if err != nil {
+		return nil, fmt.Errorf("%w in %s", err, path)
+	}
+
+	if fm.Name == "" {
+		return nil, fmt.Errorf("SKILL.md missing name field: %s", path)
+	}
+
+	return fm, nil
+}
+
+// readSkillFrontmatter parses the frontmatter of a SKILL.md without checking
+// its fields. closed reports whether the closing --- delimiter was found.
+func readSkillFrontmatter(path string) (fm *skillFrontmatter, closed bool, err error) {
 	f, err := os.Open(path)
 	if err != nil {
-		return nil, fmt.Errorf("opening %s: %w", path, err)
+		return nil, false, fmt.Errorf("opening file: %w", err)
 	}
 	defer func() { _ = f.Close() }()
 
@@ -200,10 +293,13 @@ func parseSkillFrontmatter(path string) (*skillFrontmatter, error) {
 
 	// Look for opening ---
 	if !scanner.Scan() {
-		return nil, fmt.Errorf("empty file: %s", path)
+		if err := scanner.Err(); err != nil {
+			return nil, false, fmt.Errorf("reading file: %w", err)
+		}
+		return nil, false, fmt.Errorf("file is empty")
 	}
 	if strings.TrimSpace(scanner.Text()) != "---" {
-		return nil, fmt.Errorf("no frontmatter in %s", path)
+		return nil, false, fmt.Errorf("no frontmatter (file must start with ---)")
 	}
 
 	// Collect frontmatter lines until closing ---
@@ -211,6 +307,7 @@ func parseSkillFrontmatter(path string) (*skillFrontmatter, error) {
 	for scanner.Scan() {
 		line := scanner.Text()
 		if strings.TrimSpace(line) == "---" {
+			closed = true
 			break
 		}
 		frontmatter.WriteString(line)
@@ -218,19 +315,15 @@ func parseSkillFrontmatter(path string) (*skillFrontmatter, error) {
 	}
 
 	if err := scanner.Err(); err != nil {
-		return nil, fmt.Errorf("reading %s: %w", path, err)
-	}
-
-	var fm skillFrontmatter
-	if err := yaml.Unmarshal([]byte(frontmatter.String()), &fm); err != nil {
-		return nil, fmt.Errorf("parsing frontmatter in %s: %w", path, err)
+		return nil, false, fmt.Errorf("reading file: %w", err)
 	}
 
-	if fm.Name == "" {
-		return nil, fmt.Errorf("SKILL.md missing name field: %s", path)
+	fm = &skillFrontmatter{}
+	if err := yaml.Unmarshal([]byte(frontmatter.String()), fm); err != nil {
+		return nil, false, fmt.Errorf("invalid frontmatter YAML: %w", err)
 	}
 
-	return &fm, nil
+	return fm, closed, nil
 }
 
 func init() { Register(&SkillHandler{}) }
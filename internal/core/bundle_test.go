@@ -0,0 +1,274 @@
+package core
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+// setupBundleProject creates a project with a skill linked for Claude Code,
+// an agent, and an MCP configured for Cursor, all recorded in its lock.
+func setupBundleProject(t *testing.T) (string, asset.MCPMeta) {
+	t.Helper()
+	dir := t.TempDir()
+	write := func(rel, content string, mode os.FileMode) {
+		t.Helper()
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), mode); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(".agents/skills/lint/SKILL.md", "---\nname: lint\n---\n# lint", 0o644)
+	write(".agents/skills/lint/scripts/run.sh", "#!/bin/sh\n", 0o755)
+	write(".claude/agents/reviewer.md", "# reviewer", 0o644)
+
+	claude, _ := system.ByName("claude-code")
+	if err := claude.Install(asset.Asset{Kind: asset.KindSkill, Name: "lint"}, dir, system.InstallOptions{}); err != nil {
+		t.Fatal(err)
+	}
+	meta := asset.MCPMeta{Command: "npx", Args: []string{"-y", "db-mcp"}}
+	cursor, _ := system.ByName("cursor")
+	if err := cursor.Install(asset.Asset{Kind: asset.KindMCP, Name: "db", Meta: meta}, dir, system.InstallOptions{}); err != nil {
+		t.Fatal(err)
+	}
+
+	digest, err := contentDigest(filepath.Join(dir, ".agents/skills/lint"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	lf := &LockFile{LockVersion: 3, Assets: []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "lint", Source: "github.com/acme/skills/lint", Commit: "abc1234", Data: map[string]any{digestKey: digest}},
+		{Kind: asset.KindAgent, Name: "reviewer", Source: "github.com/acme/agents/reviewer", Commit: "abc1234"},
+		{Kind: asset.KindMCP, Name: "db", Data: map[string]any{"registry": "team", "configHash": ComputeConfigHash(meta)}},
+	}}
+	if err := WriteLockFile(dir, lf); err != nil {
+		t.Fatal(err)
+	}
+	return dir, meta
+}
+
+func TestBundle_RoundTrip(t *testing.T) {
+	src, meta := setupBundleProject(t)
+	out := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	b, err := ExportBundle(src, out, ExportOptions{
+		MCPConfig: func(asset.LockedAsset) (asset.MCPMeta, string, error) { return meta, "Database", nil },
+	})
+	if err != nil {
+		t.Fatalf("ExportBundle() error = %v", err)
+	}
+	if len(b.Assets) != 2 || len(b.MCPs) != 1 {
+		t.Fatalf("bundle = %+v, want 2 assets and 1 MCP", b)
+	}
+	for _, a := range b.Assets {
+		if a.Kind != asset.KindSkill {
+			continue
+		}
+		if len(a.Systems) != 1 || a.Systems[0] != "claude-code" {
+			t.Errorf("skill systems = %v, want [claude-code]", a.Systems)
+		}
+	}
+	if got := b.MCPs[0].Systems; len(got) != 1 || got[0] != "cursor" {
+		t.Errorf("mcp systems = %v, want [cursor]", got)
+	}
+
+	dst := t.TempDir()
+	res, err := ImportBundle(out, dst, ImportOptions{})
+	if err != nil {
+		t.Fatalf("ImportBundle() error = %v", err)
+	}
+	if res.Digests == 0 {
+		t.Error("ImportBundle() verified no digests")
+	}
+	for _, rel := range []string{".agents/skills/lint", ".claude/agents/reviewer.md"} {
+		want, _ := contentDigest(filepath.Join(src, rel))
+		got, err := contentDigest(filepath.Join(dst, rel))
+		if err != nil || got != want {
+			t.Errorf("%s digest = %q, %v; want %q", rel, got, err, want)
+		}
+	}
+	info, err := os.Stat(filepath.Join(dst, ".agents/skills/lint/scripts/run.sh"))
+	if err != nil || info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("run.sh lost its executable bit: %v, %v", info, err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, ".claude/skills/lint/SKILL.md")); err != nil {
+		t.Errorf("skill not linked for Claude Code: %v", err)
+	}
+	cursor, _ := system.ByName("cursor")
+	if c, ok := cursor.(interface{ HasMCP(string, string) bool }); !ok || !c.HasMCP("db", dst) {
+		t.Error("mcp db not written for Cursor")
+	}
+	srcLock, _ := os.ReadFile(LockFilePath(src))
+	dstLock, _ := os.ReadFile(LockFilePath(dst))
+	if string(srcLock) != string(dstLock) {
+		t.Error("imported lock file differs from the exported one")
+	}
+
+	// Importing again over the same setup needs --force for the files.
+	if _, err := ImportBundle(out, dst, ImportOptions{}); err == nil || !strings.Contains(err.Error(), "already exists") {
+		t.Errorf("second ImportBundle() error = %v, want already exists", err)
+	}
+	if _, err := ImportBundle(out, dst, ImportOptions{Force: true}); err != nil {
+		t.Errorf("ImportBundle(force) error = %v", err)
+	}
+}
+
+func TestBundle_Zstd(t *testing.T) {
+	src, meta := setupBundleProject(t)
+	out := filepath.Join(t.TempDir(), "bundle.tar.zst")
+	if _, err := ExportBundle(src, out, ExportOptions{
+		MCPConfig: func(asset.LockedAsset) (asset.MCPMeta, string, error) { return meta, "", nil },
+	}); err != nil {
+		t.Fatalf("ExportBundle() error = %v", err)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(data, zstdMagic) {
+		t.Fatalf("bundle starts with % x, want the zstd magic number", data[:4])
+	}
+
+	dst := t.TempDir()
+	if _, err := ImportBundle(out, dst, ImportOptions{}); err != nil {
+		t.Fatalf("ImportBundle() error = %v", err)
+	}
+	if _, err := os.Stat(filepath.Join(dst, ".agents/skills/lint/SKILL.md")); err != nil {
+		t.Errorf("skill not imported: %v", err)
+	}
+}
+
+func TestExportBundle_NotInstalled(t *testing.T) {
+	src, meta := setupBundleProject(t)
+	if err := os.RemoveAll(filepath.Join(src, ".agents/skills/lint")); err != nil {
+		t.Fatal(err)
+	}
+	_, err := ExportBundle(src, filepath.Join(t.TempDir(), "b.tar.gz"), ExportOptions{
+		MCPConfig: func(asset.LockedAsset) (asset.MCPMeta, string, error) { return meta, "", nil },
+	})
+	if err == nil || !strings.Contains(err.Error(), `skill "lint" is not installed`) {
+		t.Errorf("ExportBundle() error = %v, want not installed", err)
+	}
+}
+
+func TestImportBundle_DigestMismatch(t *testing.T) {
+	src, meta := setupBundleProject(t)
+	// A skill edited after its digest was frozen no longer matches the lock.
+	if err := os.WriteFile(filepath.Join(src, ".agents/skills/lint/SKILL.md"), []byte("tampered"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(t.TempDir(), "bundle.tar.gz")
+	if _, err := ExportBundle(src, out, ExportOptions{
+		MCPConfig: func(asset.LockedAsset) (asset.MCPMeta, string, error) { return meta, "", nil },
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	dst := t.TempDir()
+	_, err := ImportBundle(out, dst, ImportOptions{})
+	var mismatch *DigestMismatchError
+	if !errors.As(err, &mismatch) {
+		t.Fatalf("ImportBundle() error = %v, want DigestMismatchError", err)
+	}
+	if len(mismatch.Mismatches) != 1 || !strings.Contains(mismatch.Mismatches[0], `skill "lint"`) {
+		t.Errorf("mismatches = %v, want one for skill lint", mismatch.Mismatches)
+	}
+	if entries, _ := os.ReadDir(dst); len(entries) != 0 {
+		t.Errorf("ImportBundle() wrote %d entries despite the mismatch", len(entries))
+	}
+}
+
+// writeTestBundle writes a gzipped tarball of files to a temp file.
+func writeTestBundle(t *testing.T, files map[string][]byte) string {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, data := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o644, Size: int64(len(data))}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	out := filepath.Join(t.TempDir(), "evil.tar.gz")
+	if err := os.WriteFile(out, buf.Bytes(), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return out
+}
+
+func TestImportBundle_HostileManifest(t *testing.T) {
+	lockData, err := json.Marshal(&LockFile{LockVersion: 3, Assets: []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "lint", Source: "github.com/acme/skills/lint", Commit: "abc1234"},
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	payload := []byte("pwned\n")
+	digest := bytesDigest(payload)
+
+	tests := []struct {
+		name  string
+		asset BundledAsset
+		want  string
+	}{
+		{"parent path", BundledAsset{Kind: asset.KindSkill, Name: "lint", Digests: map[string]string{"../escaped.txt": digest}}, "outside where it is installed"},
+		{"project root", BundledAsset{Kind: asset.KindSkill, Name: "lint", Digests: map[string]string{".": digest}}, "outside where it is installed"},
+		{"other file in the project", BundledAsset{Kind: asset.KindSkill, Name: "lint", Digests: map[string]string{".claude/settings.json": digest}}, "outside where it is installed"},
+		{"not in the lock file", BundledAsset{Kind: asset.KindAgent, Name: "intruder", Digests: map[string]string{".claude/agents/intruder.md": digest}}, "not in the bundled lock file"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			manifest, err := json.Marshal(Bundle{BundleVersion: 1, LockDigest: bytesDigest(lockData), Assets: []BundledAsset{tt.asset}})
+			if err != nil {
+				t.Fatal(err)
+			}
+			bundle := writeTestBundle(t, map[string][]byte{
+				bundleManifestName:                   manifest,
+				lockFileName:                         lockData,
+				"escaped.txt":                        payload,
+				"files/.claude/settings.json":        payload,
+				"files/.claude/agents/intruder.md":   payload,
+				"files/.agents/skills/lint/SKILL.md": payload,
+			})
+
+			root := t.TempDir()
+			dst := filepath.Join(root, "proj")
+			if err := os.MkdirAll(dst, 0o755); err != nil {
+				t.Fatal(err)
+			}
+			_, err = ImportBundle(bundle, dst, ImportOptions{Force: true})
+			if err == nil || !strings.Contains(err.Error(), tt.want) {
+				t.Fatalf("ImportBundle() error = %v, want %q", err, tt.want)
+			}
+			if _, err := os.Stat(filepath.Join(root, "escaped.txt")); err == nil {
+				t.Error("ImportBundle() wrote outside the project")
+			}
+			if _, err := os.Stat(dst); err != nil {
+				t.Errorf("ImportBundle() removed the project: %v", err)
+			}
+			if entries, _ := os.ReadDir(dst); len(entries) != 0 {
+				t.Errorf("ImportBundle() wrote %d entries into the project", len(entries))
+			}
+		})
+	}
+}
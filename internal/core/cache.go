@@ -0,0 +1,292 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sync/atomic"
+	"time"
+
+	"github.com/barysiuk/duckrow/internal/filelock"
+)
+
+// Cache configures the clone cache. When Dir is set, sources are cloned
+// once into bare mirrors under Dir/repos, keyed by their URL, and later
+// clones are served from there, fetching only what changed upstream.
+// Without it every install, update check, and sync clones from scratch.
+type Cache struct {
+	Dir string
+
+	// Shared makes the cache usable by several users of one group, e.g. CI
+	// jobs running as different accounts on a build machine: directories
+	// are group-writable and setgid, and files group-writable whatever the
+	// umask.
+	Shared bool
+}
+
+// Enabled reports whether a cache directory is configured.
+func (c Cache) Enabled() bool { return c.Dir != "" }
+
+// defaultCacheDir is the clone cache directory under the config directory
+// when the settings don't name one.
+const defaultCacheDir = "cache"
+
+// Cache returns the clone cache configured in the settings: CacheDir, or
+// the cache directory under configDir by default, unless the cache is
+// turned off.
+func (s Settings) Cache(configDir string) Cache {
+	if s.DisableCloneCache {
+		return Cache{Shared: s.SharedCache}
+	}
+	dir := s.CacheDir
+	switch {
+	case dir != "":
+		dir = expandPath(dir)
+	case configDir != "":
+		dir = filepath.Join(configDir, defaultCacheDir)
+	}
+	return Cache{Dir: dir, Shared: s.SharedCache}
+}
+
+// CacheStats counts how clones were served by the cache during this run.
+type CacheStats struct {
+	Hits   int // served from an existing mirror
+	Misses int // needed a new mirror
+}
+
+// currentCacheValue is process-wide like the timeouts: the CLI sets it once
+// from the settings and flags, and the clone helpers read it.
+var (
+	currentCacheValue atomic.Value
+	cacheHits         atomic.Int64
+	cacheMisses       atomic.Int64
+)
+
+// SetCache sets the clone cache. A zero Cache turns it off.
+func SetCache(c Cache) {
+	currentCacheValue.Store(c)
+}
+
+// CurrentCache returns the clone cache in effect.
+func CurrentCache() Cache {
+	c, _ := currentCacheValue.Load().(Cache)
+	return c
+}
+
+// CurrentCacheStats returns the cache hits and misses so far.
+func CurrentCacheStats() CacheStats {
+	return CacheStats{Hits: int(cacheHits.Load()), Misses: int(cacheMisses.Load())}
+}
+
+const (
+	cacheDirPerm       = 0o755
+	sharedCacheDirPerm = 0o775 | fs.ModeSetgid
+	cacheLockPoll      = 100 * time.Millisecond
+)
+
+// cloneFromCache is cloneRepo and cloneRepoAtCommit backed by the cache:
+// it brings the mirror of url up to date and clones a working copy from it
+// into a temp directory, checked out at commit or else at ref.
+func (c Cache) cloneFromCache(url, ref, commit string) (string, error) {
+	mirror, err := c.mirror(url, commit)
+	if err != nil {
+		return "", err
+	}
+
+	tmpDir, err := os.MkdirTemp("", "duckrow-clone-*")
+	if err != nil {
+		return "", fmt.Errorf("creating temp dir: %w", err)
+	}
+	timeout := CurrentTimeouts().Clone
+
+	// --shared borrows the mirror's objects instead of copying them.
+	args := append(longPathGitArgs(), c.safeDirArgs(mirror)...)
+	args = append(args, "clone", "--quiet", "--shared")
+	switch {
+	case commit != "":
+		args = append(args, "--no-checkout")
+	case ref != "":
+		args = append(args, "--branch", ref)
+	}
+	args = append(args, mirror, tmpDir)
+	cmd := exec.Command("git", args...)
+	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	if output, err := runWithTimeout(cmd, timeout); err != nil {
+		_ = os.RemoveAll(tmpDir)
+		return "", ClassifyCloneError(url, FormatCommand(url, ref), output)
+	}
+
+	if commit != "" {
+		cmd := exec.Command("git", append(longPathGitArgs(), "-C", tmpDir, "checkout", "--quiet", commit)...)
+		cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+		if output, err := runWithTimeout(cmd, timeout); err != nil {
+			_ = os.RemoveAll(tmpDir)
+			return "", fmt.Errorf("commit %s not found in remote (may have been force-pushed away): %s", commit, output)
+		}
+	}
+	return tmpDir, nil
+}
+
+// mirror returns the path of an up-to-date bare mirror of url, creating it
+// on first use. A mirror that already has commit is used as-is without a
+// fetch, and so is any existing mirror in offline mode.
+func (c Cache) mirror(url, commit string) (string, error) {
+	root := filepath.Join(c.Dir, "repos")
+	if err := c.mkdir(root); err != nil {
+		return "", fmt.Errorf("creating cache directory: %w", err)
+	}
+	// A mirror doesn't make a host outside the allowlist reachable.
+	if err := checkHost(url); err != nil {
+		return "", err
+	}
+	key := RegistryDirKey(url)
+	mirror := filepath.Join(root, key+".git")
+	timeout := CurrentTimeouts().Clone
+
+	unlock, err := c.lock(mirror+".lock", timeout)
+	if err != nil {
+		return "", err
+	}
+	defer unlock()
+
+	if !dirExists(mirror) {
+		if err := checkNetwork(url); err != nil {
+			return "", err
+		}
+		throttle(url)
+		if err := c.createMirror(url, root, key, mirror, timeout); err != nil {
+			return "", err
+		}
+		cacheMisses.Add(1)
+		logEvent(LogInfo, "", "cached a new mirror of %s", redactURL(url))
+		return mirror, nil
+	}
+
+	cacheHits.Add(1)
+	logEvent(LogInfo, "", "using the cached mirror of %s", redactURL(url))
+	if (commit != "" && c.hasCommit(mirror, commit)) || Offline() {
+		return mirror, nil
+	}
+
+	throttle(url)
+	env := gitEnv(url)
+	git := append(c.safeDirArgs(mirror), "-C", mirror)
+	fetch := exec.Command("git", append(git, "fetch", "--quiet", "--prune", "origin")...)
+	fetch.Env = env
+	if output, err := runWithTimeout(fetch, timeout); err != nil {
+		return "", ClassifyCloneError(url, "git fetch "+url, output)
+	}
+	if commit != "" && !c.hasCommit(mirror, commit) {
+		// The commit may no longer be on any branch; ask for it directly.
+		fetch := exec.Command("git", append(git, "fetch", "--quiet", "origin", commit)...)
+		fetch.Env = env
+		_, _ = runWithTimeout(fetch, timeout)
+	}
+	return mirror, nil
+}
+
+// createMirror clones a bare mirror of url next to its final location and
+// moves it into place, so other processes never see a half-written mirror.
+func (c Cache) createMirror(url, root, key, mirror string, timeout time.Duration) error {
+	tmp, err := os.MkdirTemp(root, key+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating cache directory: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmp) }()
+
+	args := []string{"clone", "--quiet", "--mirror", "--config", "gc.auto=0"}
+	if c.Shared {
+		// Git then keeps files it writes on later fetches group-writable.
+		args = append(args, "--config", "core.sharedRepository=group")
+	}
+	args = append(args, url, tmp)
+	cmd := exec.Command("git", args...)
+	cmd.Env = gitEnv(url)
+	if output, err := runWithTimeout(cmd, timeout); err != nil {
+		return ClassifyCloneError(url, FormatCommand(url, ""), output)
+	}
+
+	if c.Shared {
+		if err := shareTree(tmp); err != nil {
+			return fmt.Errorf("setting cache permissions: %w", err)
+		}
+	}
+	if err := os.Rename(tmp, mirror); err != nil {
+		return fmt.Errorf("saving cache entry: %w", err)
+	}
+	return nil
+}
+
+// hasCommit reports whether a mirror contains commit.
+func (c Cache) hasCommit(mirror, commit string) bool {
+	args := append(c.safeDirArgs(mirror), "-C", mirror, "cat-file", "-e", commit+"^{commit}")
+	return exec.Command("git", args...).Run() == nil
+}
+
+// safeDirArgs lets git work in a shared mirror created by another user,
+// which it otherwise refuses as having dubious ownership.
+func (c Cache) safeDirArgs(mirror string) []string {
+	if !c.Shared {
+		return nil
+	}
+	return []string{"-c", "safe.directory=" + filepath.ToSlash(mirror)}
+}
+
+// mkdir creates a cache directory, group-writable and setgid for a shared
+// cache regardless of the umask. Existing directories, which may belong to
+// another user, are left as they are.
+func (c Cache) mkdir(dir string) error {
+	if !c.Shared {
+		return os.MkdirAll(dir, cacheDirPerm)
+	}
+	if dirExists(dir) {
+		return nil
+	}
+	if err := os.MkdirAll(dir, sharedCacheDirPerm); err != nil {
+		return err
+	}
+	return os.Chmod(dir, sharedCacheDirPerm)
+}
+
+// shareTree makes every directory under root group-writable and setgid and
+// every file group-readable and -writable.
+func shareTree(root string) error {
+	return filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return os.Chmod(path, sharedCacheDirPerm)
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		return os.Chmod(path, info.Mode().Perm()|0o660)
+	})
+}
+
+// lock takes an exclusive lock on a cache entry: an OS file lock on path,
+// waiting up to timeout for another process to finish with it (see
+// filelock). It returns the function that releases the lock.
+func (c Cache) lock(path string, timeout time.Duration) (func(), error) {
+	perm := os.FileMode(0o644)
+	if c.Shared {
+		perm = 0o664
+	}
+	unlock, err := filelock.Lock(path, perm, timeout, cacheLockPoll)
+	if errors.Is(err, filelock.ErrTimeout) {
+		return nil, fmt.Errorf("timed out waiting for cache lock %s; another duckrow is still using it", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("locking cache entry: %w", err)
+	}
+	if c.Shared {
+		// Whatever the umask, others in the group must be able to open it.
+		_ = os.Chmod(path, perm)
+	}
+	return unlock, nil
+}
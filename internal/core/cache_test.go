@@ -0,0 +1,208 @@
+package core
+
+import (
+	"io/fs"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+)
+
+// useCache turns on the clone cache for one test and returns the stats
+// before it starts, since the counters are process-wide.
+func useCache(t *testing.T, c Cache) CacheStats {
+	t.Helper()
+	SetCache(c)
+	t.Cleanup(func() { SetCache(Cache{}) })
+	return CurrentCacheStats()
+}
+
+func TestCache_CloneHitsAndMisses(t *testing.T) {
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "README.md"), []byte("v1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	setupTestGitRepoInDir(t, src)
+	first := strings.TrimSpace(runGitOutput(t, src, "rev-parse", "HEAD"))
+
+	cache := Cache{Dir: t.TempDir()}
+	before := useCache(t, cache)
+
+	clone := func(commit string) string {
+		t.Helper()
+		var dir string
+		var err error
+		if commit != "" {
+			dir, err = cloneRepoAtCommit(src, commit)
+		} else {
+			dir, err = cloneRepo(src, "", false)
+		}
+		if err != nil {
+			t.Fatalf("clone error = %v", err)
+		}
+		t.Cleanup(func() { _ = os.RemoveAll(dir) })
+		data, err := os.ReadFile(filepath.Join(dir, "README.md"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	if got := clone(""); got != "v1\n" {
+		t.Errorf("first clone README = %q, want v1", got)
+	}
+	if !dirExists(filepath.Join(cache.Dir, "repos", RegistryDirKey(src)+".git")) {
+		t.Error("no mirror created in the cache")
+	}
+
+	// Upstream changes are fetched into the existing mirror.
+	if err := os.WriteFile(filepath.Join(src, "README.md"), []byte("v2\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	gitCommitAll(t, src, "v2")
+	if got := clone(""); got != "v2\n" {
+		t.Errorf("second clone README = %q, want v2", got)
+	}
+	if got := clone(first); got != "v1\n" {
+		t.Errorf("clone at first commit README = %q, want v1", got)
+	}
+
+	stats := CurrentCacheStats()
+	if hits, misses := stats.Hits-before.Hits, stats.Misses-before.Misses; hits != 2 || misses != 1 {
+		t.Errorf("hits, misses = %d, %d; want 2, 1", hits, misses)
+	}
+}
+
+func TestCache_OfflineUsesMirror(t *testing.T) {
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "README.md"), []byte("cached\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	setupTestGitRepoInDir(t, src)
+	useCache(t, Cache{Dir: t.TempDir()})
+
+	dir, err := cloneRepo(src, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = os.RemoveAll(dir)
+
+	// With the upstream gone, only offline mode (no fetch) still works.
+	if err := os.RemoveAll(src); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := cloneRepo(src, "", false); err == nil {
+		t.Error("clone of a removed upstream succeeded online")
+	}
+	SetOffline(true)
+	t.Cleanup(func() { SetOffline(false) })
+	dir, err = cloneRepo(src, "", false)
+	if err != nil {
+		t.Fatalf("offline clone error = %v", err)
+	}
+	defer func() { _ = os.RemoveAll(dir) }()
+	if _, err := os.Stat(filepath.Join(dir, "README.md")); err != nil {
+		t.Errorf("offline clone missing README.md: %v", err)
+	}
+}
+
+func TestCache_SharedPermissions(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("group permissions are not used on Windows")
+	}
+	src := t.TempDir()
+	if err := os.WriteFile(filepath.Join(src, "README.md"), []byte("shared\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	setupTestGitRepoInDir(t, src)
+	cache := Cache{Dir: filepath.Join(t.TempDir(), "cache"), Shared: true}
+	useCache(t, cache)
+
+	dir, err := cloneRepo(src, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_ = os.RemoveAll(dir)
+
+	err = filepath.WalkDir(filepath.Join(cache.Dir, "repos"), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		mode := info.Mode()
+		if mode.Perm()&0o060 != 0o060 {
+			t.Errorf("%s mode %v is not group read-write", path, mode)
+		}
+		if d.IsDir() && mode&fs.ModeSetgid == 0 {
+			t.Errorf("%s mode %v is not setgid", path, mode)
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	out := runGitOutput(t, filepath.Join(cache.Dir, "repos", RegistryDirKey(src)+".git"), "config", "core.sharedRepository")
+	if strings.TrimSpace(out) != "group" {
+		t.Errorf("core.sharedRepository = %q, want group", out)
+	}
+}
+
+func TestCache_Lock(t *testing.T) {
+	c := Cache{Dir: t.TempDir()}
+	path := filepath.Join(c.Dir, "entry.lock")
+
+	unlock, err := c.lock(path, time.Second)
+	if err != nil {
+		t.Fatalf("lock() error = %v", err)
+	}
+	if _, err := c.lock(path, 200*time.Millisecond); err == nil || !strings.Contains(err.Error(), "timed out") {
+		t.Errorf("second lock() error = %v, want timeout", err)
+	}
+	unlock()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Error("unlock did not remove the lock file")
+	}
+
+	// A lock file left behind by a process that died doesn't block.
+	if err := os.WriteFile(path, []byte("12345\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err = c.lock(path, time.Second)
+	if err != nil {
+		t.Fatalf("lock() over a leftover lock file error = %v", err)
+	}
+	unlock()
+}
+
+func TestSettings_Cache(t *testing.T) {
+	configDir := filepath.Join(t.TempDir(), ".duckrow")
+	if c := (Settings{}).Cache(configDir); c.Dir != filepath.Join(configDir, "cache") {
+		t.Errorf("default Cache() = %+v, want %s", c, filepath.Join(configDir, "cache"))
+	}
+	if c := (Settings{}).Cache(""); c.Enabled() {
+		t.Errorf("Cache() without a config dir = %+v, want disabled", c)
+	}
+	if c := (Settings{DisableCloneCache: true}).Cache(configDir); c.Enabled() {
+		t.Errorf("Cache() with disableCloneCache = %+v, want disabled", c)
+	}
+	home, _ := os.UserHomeDir()
+	c := Settings{CacheDir: "~/duckrow-cache", SharedCache: true}.Cache(configDir)
+	if c.Dir != filepath.Join(home, "duckrow-cache") || !c.Shared {
+		t.Errorf("Cache() = %+v, want expanded shared dir", c)
+	}
+}
+
+func runGitOutput(t *testing.T, dir string, args ...string) string {
+	t.Helper()
+	out, err := exec.Command("git", append([]string{"-C", dir}, args...)...).Output()
+	if err != nil {
+		t.Fatalf("git %v failed: %v", args, err)
+	}
+	return string(out)
+}
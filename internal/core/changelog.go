@@ -0,0 +1,190 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// changelogFile is the file in an asset's directory whose new entries are
+// shown along with the commits of an update.
+const changelogFile = "CHANGELOG.md"
+
+// maxChangelogLines caps the CHANGELOG.md excerpt shown for an update.
+const maxChangelogLines = 40
+
+// Changelog is what changed in an asset between two commits: the commits
+// touching it, newest first, and the entries added to the CHANGELOG.md in
+// its directory, if it has one.
+type Changelog struct {
+	Commits []ChangelogCommit `json:"commits"`
+	Excerpt string            `json:"excerpt,omitempty"`
+}
+
+// ChangelogCommit is one commit in a Changelog.
+type ChangelogCommit struct {
+	Commit  string `json:"commit"`
+	Subject string `json:"subject"`
+}
+
+// ChangelogReader reads changelogs for locked assets. Each repository and
+// ref is cloned once, however many of its assets are asked about; Close
+// removes the clones.
+type ChangelogReader struct {
+	overrides map[string]string
+	mirrors   RegistryMirrors
+	clones    map[string]string // url@ref -> clone dir
+	errs      map[string]error
+}
+
+// NewChangelogReader returns a reader that fetches repositories through
+// the given clone URL overrides and registry mirrors.
+func NewChangelogReader(overrides map[string]string, mirrors RegistryMirrors) *ChangelogReader {
+	return &ChangelogReader{
+		overrides: overrides,
+		mirrors:   mirrors,
+		clones:    make(map[string]string),
+		errs:      make(map[string]error),
+	}
+}
+
+// Read returns the changelog of a locked asset between the commits from
+// and to, on the entry's ref.
+func (r *ChangelogReader) Read(locked asset.LockedAsset, from, to string) (*Changelog, error) {
+	host, owner, repo, subPath, err := ParseLockSource(locked.Source)
+	if err != nil {
+		return nil, err
+	}
+	url := lockCloneURL(host, owner, repo, r.overrides, r.mirrors.Locked(locked))
+	dir, err := r.clone(url, locked.Ref)
+	if err != nil {
+		return nil, err
+	}
+	for _, commit := range []string{from, to} {
+		if err := fetchCommit(dir, url, commit); err != nil {
+			return nil, err
+		}
+	}
+
+	args := []string{"-C", dir, "log", "--format=%H %s", from + ".." + to}
+	if subPath != "" {
+		args = append(args, "--", subPath)
+	}
+	output, err := exec.Command("git", args...).Output()
+	if err != nil {
+		return nil, fmt.Errorf("reading history %s..%s: %w", TruncateCommit(from), TruncateCommit(to), err)
+	}
+	cl := &Changelog{Commits: []ChangelogCommit{}}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		commit, subject, ok := strings.Cut(line, " ")
+		if ok {
+			cl.Commits = append(cl.Commits, ChangelogCommit{Commit: commit, Subject: subject})
+		}
+	}
+
+	file := path.Join(subPath, changelogFile)
+	if next, ok := gitShow(dir, to, file); ok {
+		prev, _ := gitShow(dir, from, file)
+		cl.Excerpt = changelogExcerpt(prev, next)
+	}
+	return cl, nil
+}
+
+// Close removes the clones made by Read.
+func (r *ChangelogReader) Close() {
+	for _, dir := range r.clones {
+		_ = os.RemoveAll(dir)
+	}
+	r.clones = make(map[string]string)
+}
+
+// clone returns a full clone of the repository at ref, cloning it on first
+// use. A repository that failed to clone is not tried again.
+func (r *ChangelogReader) clone(url, ref string) (string, error) {
+	key := url + "@" + ref
+	if dir, ok := r.clones[key]; ok {
+		return dir, nil
+	}
+	if err, ok := r.errs[key]; ok {
+		return "", err
+	}
+	dir, err := cloneRepo(url, ref, false)
+	if err != nil {
+		r.errs[key] = err
+		return "", err
+	}
+	r.clones[key] = dir
+	return dir, nil
+}
+
+// fetchCommit makes sure the clone in dir has commit, fetching it from url
+// when it is not on the cloned ref, e.g. a version tag on another branch.
+func fetchCommit(dir, url, commit string) error {
+	if exec.Command("git", "-C", dir, "cat-file", "-e", commit+"^{commit}").Run() == nil {
+		return nil
+	}
+	if err := checkNetwork(url); err != nil {
+		return err
+	}
+	throttle(url)
+	cmd := exec.Command("git", "-C", dir, "fetch", "origin", commit)
+	cmd.Env = gitEnv(url)
+	if _, err := runWithTimeout(cmd, CurrentTimeouts().Clone); err != nil {
+		return fmt.Errorf("commit %s not found in %s", TruncateCommit(commit), url)
+	}
+	return nil
+}
+
+// gitShow returns the contents of file at commit, or false if it has none.
+func gitShow(dir, commit, file string) (string, bool) {
+	output, err := exec.Command("git", "-C", dir, "show", commit+":"+file).Output()
+	if err != nil {
+		return "", false
+	}
+	return string(output), true
+}
+
+// changelogExcerpt returns the entries next adds to prev, on the usual
+// layout of new entries at the top: the lines of next before the point
+// where prev's entries begin, without the title the file opens with. When
+// prev is empty or its entries cannot be found in next, the top of next is
+// returned. The excerpt is capped at maxChangelogLines lines.
+func changelogExcerpt(prev, next string) string {
+	lines := strings.Split(next, "\n")
+	if start := firstContentLine(prev); start != "" {
+		for i, line := range lines {
+			if strings.TrimSpace(line) == start {
+				lines = lines[:i]
+				break
+			}
+		}
+	}
+
+	var kept []string
+	for _, line := range lines {
+		if len(kept) == 0 && (strings.TrimSpace(line) == "" || strings.HasPrefix(line, "# ")) {
+			continue
+		}
+		kept = append(kept, line)
+	}
+	if len(kept) > maxChangelogLines {
+		kept = append(kept[:maxChangelogLines], "...")
+	}
+	return strings.TrimSpace(strings.Join(kept, "\n"))
+}
+
+// firstContentLine returns the first line of s that is not blank and not a
+// top-level title, trimmed.
+func firstContentLine(s string) string {
+	for _, line := range strings.Split(s, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" && !strings.HasPrefix(line, "# ") {
+			return line
+		}
+	}
+	return ""
+}
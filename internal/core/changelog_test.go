@@ -0,0 +1,73 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestChangelogExcerpt(t *testing.T) {
+	prev := "# Changelog\n\n## 1.1.0\n- Older change\n"
+	tests := []struct {
+		name, prev, next, want string
+	}{
+		{"new entries on top", prev, "# Changelog\n\n## 1.2.0\n- New rule\n\n## 1.1.0\n- Older change\n", "## 1.2.0\n- New rule"},
+		{"new file", "", "# Changelog\n\n## 1.0.0\n- First\n", "## 1.0.0\n- First"},
+		{"unchanged", prev, prev, ""},
+	}
+	for _, tt := range tests {
+		if got := changelogExcerpt(tt.prev, tt.next); got != tt.want {
+			t.Errorf("%s: changelogExcerpt() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestChangelogReader_Read(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	sourceDir := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(filepath.Join(sourceDir, rel)), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(sourceDir, rel), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("skills/go-review/SKILL.md", "---\nname: go-review\n---\n")
+	write("skills/go-review/CHANGELOG.md", "# Changelog\n\n## 1.0.0\n- First\n")
+	setupTestGitRepoInDir(t, sourceDir)
+	from, err := GetSkillCommit(sourceDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	write("skills/go-review/SKILL.md", "---\nname: go-review\n---\nCheck contexts.\n")
+	gitCommitAll(t, sourceDir, "Check context propagation")
+	write("README.md", "unrelated\n")
+	gitCommitAll(t, sourceDir, "Update README")
+	write("skills/go-review/CHANGELOG.md", "# Changelog\n\n## 1.1.0\n- Contexts\n\n## 1.0.0\n- First\n")
+	to := gitCommitAll(t, sourceDir, "Release 1.1.0")
+
+	reader := NewChangelogReader(map[string]string{"acme/skills": sourceDir}, nil)
+	defer reader.Close()
+	locked := asset.LockedAsset{Kind: asset.KindSkill, Name: "go-review", Source: "localhost/acme/skills/skills/go-review"}
+	cl, err := reader.Read(locked, from, to)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(cl.Commits) != 2 || cl.Commits[0].Subject != "Release 1.1.0" || cl.Commits[1].Subject != "Check context propagation" {
+		t.Errorf("Commits = %+v, want the two commits touching the skill, newest first", cl.Commits)
+	}
+	if cl.Excerpt != "## 1.1.0\n- Contexts" {
+		t.Errorf("Excerpt = %q, want the new entry", cl.Excerpt)
+	}
+
+	if _, err := reader.Read(locked, from, "0123456789abcdef0123456789abcdef01234567"); err == nil {
+		t.Error("Read() with an unknown commit succeeded, want error")
+	}
+}
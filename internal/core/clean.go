@@ -0,0 +1,151 @@
+package core
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+// CleanedEntry is a duckrow-managed entry that CleanSystems removed from a
+// system, or would remove in a dry run.
+type CleanedEntry struct {
+	System string // system name
+	Kind   asset.Kind
+	Name   string
+	Path   string // project-relative path of the skill link, agent file, or MCP config
+}
+
+// CleanSystems removes every duckrow-managed entry from the given systems in
+// projectDir: skill links and copies in their skill directories, agent
+// files, and MCP entries in their config files. An entry is duckrow-managed
+// if it is in the lock file (team or local layer); skill symlinks into
+// .agents/skills count as well, so links left by --no-lock installs go too.
+// MCP entries duckrow didn't write are kept even if their name is locked.
+// The canonical copies in .agents/skills, and so universal systems' skills,
+// are left alone, as is the lock file. With dryRun nothing is removed.
+func (o *Orchestrator) CleanSystems(projectDir string, systems []system.System, dryRun bool) ([]CleanedEntry, error) {
+	lf, err := ReadLayeredLockFile(projectDir)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []CleanedEntry
+	seen := make(map[string]bool)
+	add := func(sys system.System, kind asset.Kind, name, path string) {
+		key := path + "\x00" + name
+		if seen[key] {
+			return
+		}
+		seen[key] = true
+		entries = append(entries, CleanedEntry{System: sys.Name(), Kind: kind, Name: name, Path: path})
+	}
+
+	for _, sys := range systems {
+		if sys.Supports(asset.KindSkill) && !sys.IsUniversal() {
+			names, err := managedSkillLinks(projectDir, sys, lf)
+			if err != nil {
+				return nil, err
+			}
+			for _, name := range names {
+				add(sys, asset.KindSkill, name, relSlash(projectDir, filepath.Join(sys.AssetDir(asset.KindSkill, projectDir), name)))
+			}
+		}
+		for _, kind := range []asset.Kind{asset.KindAgent, asset.KindCommand, asset.KindRule} {
+			if sys.AssetDir(kind, projectDir) == "" || !sys.Supports(kind) {
+				continue
+			}
+			for _, a := range AssetsByKind(lf, kind) {
+				path := sys.AssetPath(kind, a.Name, projectDir)
+				if _, err := os.Lstat(path); err == nil {
+					add(sys, kind, a.Name, relSlash(projectDir, path))
+				}
+			}
+		}
+		if c, ok := sys.(interface {
+			ManagesMCP(string, string, string) bool
+			ResolveMCPConfigPathRel(string) string
+		}); ok && sys.Supports(asset.KindMCP) {
+			for _, a := range AssetsByKind(lf, asset.KindMCP) {
+				if c.ManagesMCP(a.Name, projectDir, asset.LockedURLHash(a)) {
+					add(sys, asset.KindMCP, a.Name, c.ResolveMCPConfigPathRel(projectDir))
+				}
+			}
+		}
+	}
+
+	if dryRun {
+		return entries, nil
+	}
+	var cleaned []CleanedEntry
+	for _, e := range entries {
+		sys, _ := system.ByName(e.System)
+		var err error
+		if r, ok := sys.(interface {
+			RemoveMCP(string, string, system.RemoveMCPOptions) error
+		}); ok && e.Kind == asset.KindMCP {
+			var opts system.RemoveMCPOptions
+			if locked := FindLockedAsset(lf, asset.KindMCP, e.Name); locked != nil {
+				opts.URLHash = asset.LockedURLHash(*locked)
+			}
+			err = r.RemoveMCP(e.Name, projectDir, opts)
+		} else {
+			err = sys.Remove(e.Kind, e.Name, projectDir)
+		}
+		if err != nil {
+			return cleaned, fmt.Errorf("removing %s %s from %s: %w", e.Kind, e.Name, sys.DisplayName(), err)
+		}
+		cleaned = append(cleaned, e)
+	}
+	return cleaned, nil
+}
+
+// managedSkillLinks returns the names of the duckrow-managed entries in a
+// non-universal system's skill directory: locked skills, and symlinks that
+// point into .agents/skills, broken or not.
+func managedSkillLinks(projectDir string, sys system.System, lf *LockFile) ([]string, error) {
+	skillsDir := sys.AssetDir(asset.KindSkill, projectDir)
+	canonicalDir := filepath.Join(projectDir, canonicalSkillsDir)
+	if skillsDir == "" || skillsDir == canonicalDir {
+		return nil, nil
+	}
+	dirEntries, err := os.ReadDir(skillsDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading %s: %w", relSlash(projectDir, skillsDir), err)
+	}
+
+	locked := make(map[string]bool)
+	for _, a := range AssetsByKind(lf, asset.KindSkill) {
+		locked[sanitizeName(a.Name)] = true
+	}
+	var names []string
+	for _, entry := range dirEntries {
+		name := entry.Name()
+		if locked[name] || (entry.Type()&fs.ModeSymlink != 0 && linksInto(filepath.Join(skillsDir, name), canonicalDir)) {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// linksInto reports whether the symlink at path points into dir.
+func linksInto(path, dir string) bool {
+	target, err := os.Readlink(path)
+	if err != nil {
+		return false
+	}
+	if !filepath.IsAbs(target) {
+		target = filepath.Join(filepath.Dir(path), target)
+	}
+	rel, err := filepath.Rel(dir, target)
+	return err == nil && rel != "." && !strings.HasPrefix(rel, "..")
+}
@@ -0,0 +1,88 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+func TestCleanSystems(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel string) {
+		t.Helper()
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	symlink := func(target, rel string) {
+		t.Helper()
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, path); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	for _, a := range []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "copied", Source: "github.com/o/r/copied"},
+		{Kind: asset.KindAgent, Name: "reviewer", Source: "github.com/o/r/reviewer"},
+	} {
+		if err := AddOrUpdateAsset(dir, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write(".agents/skills/copied/SKILL.md")
+	write(".agents/skills/unlocked/SKILL.md")
+	write(".claude/skills/copied/SKILL.md")                             // copy of a locked skill
+	symlink("../../.agents/skills/unlocked", ".claude/skills/unlocked") // --no-lock install
+	symlink("../../elsewhere/tool", ".claude/skills/tool")              // not duckrow's
+	write(".claude/skills/hand-written/SKILL.md")                       // not duckrow's
+	write(".claude/agents/reviewer.md")
+	write(".claude/agents/mine.md")
+
+	claude, _ := system.ByName("claude-code")
+	orch := NewOrchestrator()
+	planned, err := orch.CleanSystems(dir, []system.System{claude}, true)
+	if err != nil {
+		t.Fatalf("CleanSystems(dry run) error = %v", err)
+	}
+	var paths []string
+	for _, e := range planned {
+		paths = append(paths, e.Path)
+	}
+	want := []string{".claude/skills/copied", ".claude/skills/unlocked", ".claude/agents/reviewer.md"}
+	if !reflect.DeepEqual(paths, want) {
+		t.Fatalf("CleanSystems(dry run) paths = %v, want %v", paths, want)
+	}
+	if _, err := os.Lstat(filepath.Join(dir, ".claude/skills/unlocked")); err != nil {
+		t.Fatalf("dry run removed a link: %v", err)
+	}
+
+	cleaned, err := orch.CleanSystems(dir, []system.System{claude}, false)
+	if err != nil {
+		t.Fatalf("CleanSystems() error = %v", err)
+	}
+	if !reflect.DeepEqual(cleaned, planned) {
+		t.Errorf("CleanSystems() = %v, want %v", cleaned, planned)
+	}
+	for _, rel := range want {
+		if _, err := os.Lstat(filepath.Join(dir, rel)); !os.IsNotExist(err) {
+			t.Errorf("%s still exists", rel)
+		}
+	}
+	for _, rel := range []string{".claude/skills/tool", ".claude/skills/hand-written", ".claude/agents/mine.md", ".agents/skills/copied/SKILL.md"} {
+		if _, err := os.Lstat(filepath.Join(dir, rel)); err != nil {
+			t.Errorf("%s was removed: %v", rel, err)
+		}
+	}
+}
@@ -0,0 +1,307 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+// ChangeType is how an asset changed between two lock files.
+type ChangeType string
+
+const (
+	ChangeInstalled ChangeType = "installed"
+	ChangeUpdated   ChangeType = "updated"
+	ChangeRemoved   ChangeType = "removed"
+)
+
+// AssetChange is one asset that differs between two lock files.
+type AssetChange struct {
+	Type ChangeType
+	Kind asset.Kind
+	Name string
+	// Old and New are the lock entries before and after; Old is nil for
+	// installed assets and New for removed ones.
+	Old, New *asset.LockedAsset
+}
+
+// Detail describes the change in a few words: the commit range of an
+// updated skill or agent, e.g. "1a2b3c4..5d6e7f8", the commit or registry
+// of an installed asset, or what else changed in the lock entry.
+func (c AssetChange) Detail() string {
+	switch c.Type {
+	case ChangeInstalled:
+		if c.New.Commit != "" {
+			return TruncateCommit(c.New.Commit)
+		}
+		if reg, _ := c.New.Data["registry"].(string); reg != "" {
+			return "from " + reg
+		}
+	case ChangeUpdated:
+		switch {
+		case c.Old.Commit != c.New.Commit:
+			return commitLabel(c.Old.Commit) + ".." + commitLabel(c.New.Commit)
+		case c.Old.Source != c.New.Source:
+			return "source " + c.New.Source
+		case c.Old.Data["configHash"] != c.New.Data["configHash"]:
+			return "config changed"
+		}
+		return "lock entry changed"
+	}
+	return ""
+}
+
+func commitLabel(commit string) string {
+	if commit == "" {
+		return "unpinned"
+	}
+	return TruncateCommit(commit)
+}
+
+// LockDelta returns the assets that were installed, updated, or removed
+// going from old to new, in the order of the lock files: changes to assets
+// in new first, then removals. Either lock file may be nil.
+func LockDelta(old, new *LockFile) []AssetChange {
+	var changes []AssetChange
+	if new != nil {
+		for i := range new.Assets {
+			a := &new.Assets[i]
+			prev := FindLockedAsset(old, a.Kind, a.Name)
+			switch {
+			case prev == nil:
+				changes = append(changes, AssetChange{Type: ChangeInstalled, Kind: a.Kind, Name: a.Name, New: a})
+			case !reflect.DeepEqual(*prev, *a):
+				changes = append(changes, AssetChange{Type: ChangeUpdated, Kind: a.Kind, Name: a.Name, Old: prev, New: a})
+			}
+		}
+	}
+	if old != nil {
+		for i := range old.Assets {
+			a := &old.Assets[i]
+			if FindLockedAsset(new, a.Kind, a.Name) == nil {
+				changes = append(changes, AssetChange{Type: ChangeRemoved, Kind: a.Kind, Name: a.Name, Old: a})
+			}
+		}
+	}
+	return changes
+}
+
+// CommitMessage returns a conventional commit message summarizing changes,
+// e.g. "chore(duckrow): install skill go-review" for one change, with a
+// body listing every change grouped by type.
+func CommitMessage(changes []AssetChange) string {
+	counts := make(map[ChangeType]int)
+	for _, c := range changes {
+		counts[c.Type]++
+	}
+
+	var b strings.Builder
+	b.WriteString("chore(duckrow): ")
+	if len(changes) == 1 {
+		c := changes[0]
+		verb := map[ChangeType]string{ChangeInstalled: "install", ChangeUpdated: "update", ChangeRemoved: "remove"}[c.Type]
+		fmt.Fprintf(&b, "%s %s %s", verb, c.Kind, c.Name)
+	} else {
+		var parts []string
+		for _, t := range []ChangeType{ChangeInstalled, ChangeUpdated, ChangeRemoved} {
+			if counts[t] > 0 {
+				parts = append(parts, fmt.Sprintf("%d %s", counts[t], t))
+			}
+		}
+		fmt.Fprintf(&b, "update assets (%s)", strings.Join(parts, ", "))
+	}
+	b.WriteString("\n")
+
+	for _, t := range []ChangeType{ChangeInstalled, ChangeUpdated, ChangeRemoved} {
+		if counts[t] == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s:\n", strings.ToUpper(string(t[:1]))+string(t[1:]))
+		for _, c := range changes {
+			if c.Type != t {
+				continue
+			}
+			fmt.Fprintf(&b, "- %s %s", c.Kind, c.Name)
+			if d := c.Detail(); d != "" {
+				fmt.Fprintf(&b, " (%s)", d)
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+// HeadLockFile returns the project's lock file as committed at HEAD, or
+// nil if there is no commit yet or the lock file is not in it.
+func HeadLockFile(dir string) (*LockFile, error) {
+	data, ok, err := headFile(dir, lockFileName)
+	if err != nil || !ok {
+		return nil, err
+	}
+	lf, err := parseLockFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s at HEAD: %w", lockFileName, err)
+	}
+	return lf, nil
+}
+
+// headFile returns the content of the project-relative file rel as
+// committed at HEAD; ok is false if there is no commit yet or the file is
+// not in it.
+func headFile(dir, rel string) (data []byte, ok bool, err error) {
+	prefix, err := GitProjectPrefix(dir)
+	if err != nil {
+		return nil, false, err
+	}
+	spec := "HEAD:" + path.Join(prefix, rel)
+	if err := exec.Command("git", "-C", dir, "cat-file", "-e", spec).Run(); err != nil {
+		return nil, false, nil
+	}
+	data, err = exec.Command("git", "-C", dir, "show", spec).Output()
+	if err != nil {
+		return nil, false, fmt.Errorf("reading %s: %w", spec, err)
+	}
+	return data, true, nil
+}
+
+// CommitPaths returns the project-relative paths to commit along with the
+// lock file for changes: the canonical copies and system links of skills,
+// the agent files, the system MCP config files holding a changed MCP
+// entry, and the assets' attestations. Paths that git can't stage, because
+// they are gitignored and untracked or neither on disk nor tracked, are
+// left out; so is the personal local lock.
+//
+// An MCP config file is shared with the user's own settings, so it is only
+// committed when the changed MCP entries are all that differ from HEAD.
+// Otherwise it is left out and a warning saying why is returned, since
+// committing it would take along edits the user has not chosen to commit,
+// such as the entries of MCPs in the local lock.
+func CommitPaths(dir string, changes []AssetChange) (paths []string, warnings []string) {
+	var candidates []string
+	seen := make(map[string]bool)
+	add := func(p string) {
+		if p != "" && !seen[p] {
+			seen[p] = true
+			candidates = append(candidates, p)
+		}
+	}
+	add(lockFileName)
+	mcps := make(map[string]bool)
+	for _, c := range changes {
+		name := sanitizeName(c.Name)
+		add(filepath.ToSlash(attestationPath("", c.Kind, c.Name)))
+		switch c.Kind {
+		case asset.KindSkill:
+			add(canonicalSkillsDir + "/" + name)
+			for _, sys := range system.Supporting(asset.KindSkill) {
+				if !sys.IsUniversal() {
+					add(filepath.ToSlash(filepath.Join(sys.AssetDir(asset.KindSkill, ""), name)))
+				}
+			}
+		case asset.KindAgent, asset.KindCommand, asset.KindRule:
+			for _, sys := range system.Supporting(c.Kind) {
+				if p := sys.AssetPath(c.Kind, name, ""); p != "" {
+					add(filepath.ToSlash(p))
+				}
+			}
+		case asset.KindMCP:
+			mcps[c.Name] = true
+		}
+	}
+	configs := make(map[string]mcpConfigSystem)
+	if len(mcps) > 0 {
+		for _, sys := range system.Supporting(asset.KindMCP) {
+			if r, ok := sys.(mcpConfigSystem); ok {
+				if rel := r.ResolveMCPConfigPathRel(dir); rel != "" && configs[rel] == nil {
+					configs[rel] = r
+					add(rel)
+				}
+			}
+		}
+	}
+
+	for _, p := range candidates {
+		if len(gitTrackedFiles(dir, p)) == 0 {
+			if _, err := os.Lstat(filepath.Join(dir, filepath.FromSlash(p))); err != nil {
+				continue
+			}
+			if _, ignored := gitIgnoreRule(dir, p); ignored {
+				continue
+			}
+		}
+		if r := configs[p]; r != nil {
+			commit, warning := commitMCPConfig(dir, p, r, mcps)
+			if warning != "" {
+				warnings = append(warnings, warning)
+			}
+			if !commit {
+				continue
+			}
+		}
+		paths = append(paths, p)
+	}
+	return paths, warnings
+}
+
+// mcpConfigSystem is implemented by systems with an MCP config file.
+type mcpConfigSystem interface {
+	ResolveMCPConfigPathRel(projectDir string) string
+	MCPConfigChanges(before, after string) (names []string, other bool, err error)
+}
+
+// commitMCPConfig reports whether the MCP config file rel should be
+// committed for the changed MCPs: whether it differs from HEAD in one of
+// their entries and in nothing else. When it holds a changed entry but
+// can't be committed, warning says why.
+func commitMCPConfig(dir, rel string, sys mcpConfigSystem, mcps map[string]bool) (commit bool, warning string) {
+	before, _, err := headFile(dir, rel)
+	if err != nil {
+		return false, fmt.Sprintf("leaving %s out: %v", rel, err)
+	}
+	after, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Sprintf("leaving %s out: %v", rel, err)
+	}
+	names, other, err := sys.MCPConfigChanges(string(before), string(after))
+	if err != nil {
+		return false, fmt.Sprintf("leaving %s out: %v", rel, err)
+	}
+	var ours, others []string
+	for _, n := range names {
+		if mcps[n] {
+			ours = append(ours, n)
+		} else {
+			others = append(others, n)
+		}
+	}
+	switch {
+	case len(ours) == 0:
+		return false, ""
+	case len(others) > 0:
+		return false, fmt.Sprintf("leaving %s out: it also changes MCP entries %s; stage it yourself, e.g. with git add -p", rel, strings.Join(others, ", "))
+	case other:
+		return false, fmt.Sprintf("leaving %s out: it has other uncommitted changes; stage it yourself, e.g. with git add -p", rel)
+	}
+	return true, ""
+}
+
+// CommitAssetChanges stages paths in the project's repository and commits
+// only them with message, leaving anything else already staged alone.
+func CommitAssetChanges(dir string, paths []string, message string) error {
+	add := append([]string{"-C", dir, "add", "-A", "--"}, paths...)
+	if out, err := exec.Command("git", add...).CombinedOutput(); err != nil {
+		return fmt.Errorf("git add: %s", strings.TrimSpace(string(out)))
+	}
+	commit := append([]string{"-C", dir, "commit", "--quiet", "-m", message, "--"}, paths...)
+	if out, err := exec.Command("git", commit...).CombinedOutput(); err != nil {
+		return fmt.Errorf("git commit: %s", strings.TrimSpace(string(out)))
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestLockDeltaAndCommitMessage(t *testing.T) {
+	old := &LockFile{Assets: []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "api-review", Source: "github.com/o/r/api-review", Commit: "1111111aaaa"},
+		{Kind: asset.KindSkill, Name: "same", Source: "github.com/o/r/same", Commit: "3333333cccc"},
+		{Kind: asset.KindMCP, Name: "team-db", Data: map[string]any{"registry": "org", "configHash": "sha256:a"}},
+		{Kind: asset.KindAgent, Name: "reviewer", Source: "github.com/o/r/reviewer", Commit: "4444444dddd"},
+	}}
+	new := &LockFile{Assets: []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "api-review", Source: "github.com/o/r/api-review", Commit: "2222222bbbb"},
+		{Kind: asset.KindSkill, Name: "go-vet", Source: "github.com/o/r/go-vet", Commit: "5555555eeee"},
+		{Kind: asset.KindSkill, Name: "same", Source: "github.com/o/r/same", Commit: "3333333cccc"},
+		{Kind: asset.KindMCP, Name: "team-db", Data: map[string]any{"registry": "org", "configHash": "sha256:b"}},
+		{Kind: asset.KindMCP, Name: "search", Data: map[string]any{"registry": "org"}},
+	}}
+
+	changes := LockDelta(old, new)
+	if len(changes) != 5 {
+		t.Fatalf("LockDelta() = %d changes, want 5: %+v", len(changes), changes)
+	}
+
+	want := `chore(duckrow): update assets (2 installed, 2 updated, 1 removed)
+
+Installed:
+- skill go-vet (5555555)
+- mcp search (from org)
+
+Updated:
+- skill api-review (1111111..2222222)
+- mcp team-db (config changed)
+
+Removed:
+- agent reviewer
+`
+	if got := CommitMessage(changes); got != want {
+		t.Errorf("CommitMessage() =\n%s\nwant:\n%s", got, want)
+	}
+
+	one := LockDelta(nil, &LockFile{Assets: new.Assets[1:2]})
+	if got, want := CommitMessage(one), "chore(duckrow): install skill go-vet\n\nInstalled:\n- skill go-vet (5555555)\n"; got != want {
+		t.Errorf("CommitMessage() = %q, want %q", got, want)
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"os"
 	"path/filepath"
 	"sync"
+
+	"github.com/barysiuk/duckrow/internal/core/schema"
 )
 
 const (
@@ -70,6 +72,9 @@ func (cm *ConfigManager) Load() (*Config, error) {
 		return nil, fmt.Errorf("reading config: %w", err)
 	}
 
+	if err := schema.Validate(schema.Config, data); err != nil {
+		return nil, fmt.Errorf("invalid config %s: %w", path, err)
+	}
 	var cfg Config
 	if err := json.Unmarshal(data, &cfg); err != nil {
 		return nil, fmt.Errorf("parsing config: %w", err)
@@ -110,7 +115,8 @@ func (cm *ConfigManager) RegistriesDir() string {
 }
 
 // SaveCloneURLOverride persists a clone URL override for the given repo key.
-// The repoKey should be "owner/repo" (lowercase). This performs an atomic
+// The repoKey should be "owner/repo" (lowercase), or a host-wide key from
+// HostOverrideKey with a URL template value. This performs an atomic
 // load-modify-save so concurrent callers don't lose data.
 func (cm *ConfigManager) SaveCloneURLOverride(repoKey, cloneURL string) error {
 	if repoKey == "" || cloneURL == "" {
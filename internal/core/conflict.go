@@ -0,0 +1,235 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+// aliasOfKey is the lock data field recording the upstream name of an asset
+// that was installed under a different name (an alias).
+const aliasOfKey = "aliasOf"
+
+// AssetConflict describes an asset that would overwrite an installed asset of
+// the same kind and name that came from somewhere else.
+type AssetConflict struct {
+	Kind     asset.Kind
+	Name     string
+	Existing string // provenance of the installed asset
+	Incoming string // provenance of the asset being installed
+}
+
+func (c AssetConflict) String() string {
+	return fmt.Sprintf("%s %q is already installed from %s (installing from %s)",
+		c.Kind, c.Name, describeProvenance(c.Kind, c.Existing), describeProvenance(c.Kind, c.Incoming))
+}
+
+// ConflictError is returned when an install would overwrite an asset from a
+// different source.
+type ConflictError struct {
+	Conflict AssetConflict
+}
+
+func (e *ConflictError) Error() string {
+	return e.Conflict.String()
+}
+
+// LockProvenance returns where a locked asset came from: its source for
+// skills and agents, or its registry for MCPs.
+func LockProvenance(locked asset.LockedAsset) string {
+	if locked.Kind == asset.KindMCP {
+		r, _ := locked.Data["registry"].(string)
+		return r
+	}
+	return locked.Source
+}
+
+// FindConflict reports whether installing (kind, name) would replace a locked
+// asset from a different provenance. Skills and agents also conflict when
+// their names map to the same path. origins lists the provenances that count
+// as the same origin (e.g. a registry's name and repo); the first one is
+// reported as the incoming provenance. Returns nil when there is no such
+// locked entry, its provenance is unknown, or it matches one of origins.
+func FindConflict(lf *LockFile, kind asset.Kind, name string, origins ...string) *AssetConflict {
+	var existing *asset.LockedAsset
+	for _, a := range AssetsByKind(lf, kind) {
+		if a.Name == name || (kind != asset.KindMCP && sanitizeName(a.Name) == sanitizeName(name)) {
+			existing = &a
+			break
+		}
+	}
+	if existing == nil {
+		return nil
+	}
+	have := LockProvenance(*existing)
+	if have == "" {
+		return nil
+	}
+	for _, o := range origins {
+		if o == have {
+			return nil
+		}
+	}
+	incoming := ""
+	if len(origins) > 0 {
+		incoming = origins[0]
+	}
+	return &AssetConflict{Kind: kind, Name: existing.Name, Existing: have, Incoming: incoming}
+}
+
+// FindLockCollisions returns groups of locked assets of the given kind that
+// would be installed at the same path (e.g. "My-Skill" and "my-skill").
+func FindLockCollisions(lf *LockFile, kind asset.Kind) [][]string {
+	byPath := make(map[string][]string)
+	for _, a := range AssetsByKind(lf, kind) {
+		key := sanitizeName(a.Name)
+		byPath[key] = append(byPath[key], a.Name)
+	}
+
+	var groups [][]string
+	for _, names := range byPath {
+		if len(names) > 1 {
+			sort.Strings(names)
+			groups = append(groups, names)
+		}
+	}
+	sort.Slice(groups, func(i, j int) bool { return groups[i][0] < groups[j][0] })
+	return groups
+}
+
+// ValidateAlias checks that an alias can be used as an installed name as-is.
+func ValidateAlias(alias string) error {
+	return asset.ValidateName(alias)
+}
+
+// CheckInstallName validates the name an asset is about to be installed
+// under. Names already recorded in lf for the same kind are accepted as they
+// are, so projects that installed assets before names were validated keep
+// working. lf may be nil.
+func CheckInstallName(lf *LockFile, kind asset.Kind, name string) error {
+	if lf != nil {
+		for _, a := range AssetsByKind(lf, kind) {
+			if a.Name == name {
+				return nil
+			}
+		}
+	}
+	if err := asset.ValidateName(name); err != nil {
+		return fmt.Errorf("cannot install %s: %w", kind, err)
+	}
+	return nil
+}
+
+// LockedAliasOf returns the upstream name of an aliased lock entry, or "" if
+// the asset was installed under its own name.
+func LockedAliasOf(locked asset.LockedAsset) string {
+	s, _ := locked.Data[aliasOfKey].(string)
+	return s
+}
+
+// LockedUpstreamName returns the name to look an asset up by in its source or
+// registry: the alias target for aliased entries, otherwise the entry name.
+func LockedUpstreamName(locked asset.LockedAsset) string {
+	if s := LockedAliasOf(locked); s != "" {
+		return s
+	}
+	return locked.Name
+}
+
+// describeProvenance formats a provenance string for messages.
+func describeProvenance(kind asset.Kind, p string) string {
+	if p == "" {
+		return "an unknown source"
+	}
+	if kind == asset.KindMCP {
+		return fmt.Sprintf("registry %q", p)
+	}
+	return p
+}
+
+// checkDiscoveredCollisions fails when two discovered assets would be
+// installed at the same path.
+func checkDiscoveredCollisions(kind asset.Kind, discovered []asset.Asset) error {
+	seen := make(map[string]string)
+	for _, a := range discovered {
+		key := sanitizeName(a.Name)
+		if other, ok := seen[key]; ok {
+			return fmt.Errorf("%s %q and %q would both be installed as %q; install them one at a time with different names",
+				kind, other, a.Name, key)
+		}
+		seen[key] = a.Name
+	}
+	return nil
+}
+
+// CaseCollisionError is returned when an asset would be written over an
+// existing file or directory whose name differs only by case. On
+// case-insensitive filesystems (macOS, Windows) both names are the same path.
+type CaseCollisionError struct {
+	Kind     asset.Kind
+	Name     string // incoming asset
+	Existing string // name of the existing entry on disk
+	Path     string // project-relative path of the existing entry
+}
+
+func (e *CaseCollisionError) Error() string {
+	return fmt.Sprintf("%s %q collides with existing %q at %s (names differ only by case, which is the same path on case-insensitive filesystems)",
+		e.Kind, e.Name, e.Existing, e.Path)
+}
+
+// checkCaseCollisions fails when an asset about to be installed would land
+// on an existing entry whose name differs only by case: the canonical skill
+// directory and each target system's skill, agent, command, or rule
+// directory are checked.
+// The check runs on every filesystem, so a project that works on Linux
+// doesn't break when it is checked out on macOS or Windows.
+func checkCaseCollisions(kind asset.Kind, projectDir string, targets []system.System, discovered []asset.Asset) error {
+	if kind != asset.KindSkill && !asset.IsSystemFile(kind) {
+		return nil
+	}
+	for _, a := range discovered {
+		var paths []string
+		if kind == asset.KindSkill {
+			paths = append(paths, filepath.Join(projectDir, canonicalSkillsDir, sanitizeName(a.Name)))
+		}
+		for _, sys := range targets {
+			if p := sys.AssetPath(kind, a.Name, projectDir); p != "" {
+				paths = append(paths, p)
+			}
+		}
+		for _, p := range paths {
+			if err := checkCaseCollision(kind, a.Name, projectDir, p); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// checkCaseCollision fails when path's directory has an entry whose name
+// differs from path's only by case.
+func checkCaseCollision(kind asset.Kind, name, projectDir, path string) error {
+	entries, err := os.ReadDir(filepath.Dir(path))
+	if err != nil {
+		return nil
+	}
+	want := filepath.Base(path)
+	// The file extension, if any, so the existing name is reported without it.
+	suffix := strings.TrimPrefix(want, sanitizeName(name))
+	for _, e := range entries {
+		if e.Name() != want && strings.EqualFold(e.Name(), want) {
+			return &CaseCollisionError{
+				Kind:     kind,
+				Name:     name,
+				Existing: strings.TrimSuffix(e.Name(), suffix),
+				Path:     relSlash(projectDir, filepath.Join(filepath.Dir(path), e.Name())),
+			}
+		}
+	}
+	return nil
+}
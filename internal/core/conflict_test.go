@@ -0,0 +1,154 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func testConflictLock() *LockFile {
+	return &LockFile{Assets: []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "go-review", Source: "github.com/org-a/skills/go-review"},
+		{Kind: asset.KindMCP, Name: "db", Data: map[string]any{"registry": "reg-a"}},
+		{Kind: asset.KindMCP, Name: "legacy"},
+	}}
+}
+
+func TestFindConflict(t *testing.T) {
+	lf := testConflictLock()
+
+	tests := []struct {
+		name    string
+		kind    asset.Kind
+		asset   string
+		origins []string
+		want    bool
+	}{
+		{"not locked", asset.KindSkill, "py-review", []string{"github.com/org-b/skills/py-review"}, false},
+		{"same source", asset.KindSkill, "go-review", []string{"github.com/org-a/skills/go-review"}, false},
+		{"different source", asset.KindSkill, "go-review", []string{"github.com/org-b/skills/go-review"}, true},
+		{"same path different case", asset.KindSkill, "Go-Review", []string{"github.com/org-b/skills"}, true},
+		{"same registry by repo", asset.KindMCP, "db", []string{"reg-a-name", "reg-a"}, false},
+		{"different registry", asset.KindMCP, "db", []string{"reg-b", "https://example.com/reg-b.git"}, true},
+		{"unknown provenance", asset.KindMCP, "legacy", []string{"reg-b"}, false},
+		{"other kind", asset.KindAgent, "go-review", []string{"github.com/org-b/agents"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := FindConflict(lf, tt.kind, tt.asset, tt.origins...)
+			if (c != nil) != tt.want {
+				t.Fatalf("FindConflict() = %+v, want conflict %v", c, tt.want)
+			}
+			if c != nil && c.Incoming != tt.origins[0] {
+				t.Errorf("Incoming = %q, want %q", c.Incoming, tt.origins[0])
+			}
+		})
+	}
+}
+
+func TestAssetConflictString(t *testing.T) {
+	c := FindConflict(testConflictLock(), asset.KindMCP, "db", "reg-b")
+	want := `mcp "db" is already installed from registry "reg-a" (installing from registry "reg-b")`
+	if got := c.String(); got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
+
+func TestFindLockCollisions(t *testing.T) {
+	lf := &LockFile{Assets: []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "my-skill"},
+		{Kind: asset.KindSkill, Name: "My Skill"},
+		{Kind: asset.KindSkill, Name: "other"},
+		{Kind: asset.KindAgent, Name: "my-skill"},
+	}}
+
+	groups := FindLockCollisions(lf, asset.KindSkill)
+	if len(groups) != 1 {
+		t.Fatalf("got %d groups, want 1: %v", len(groups), groups)
+	}
+	if groups[0][0] != "My Skill" || groups[0][1] != "my-skill" {
+		t.Errorf("group = %v, want [My Skill my-skill]", groups[0])
+	}
+	if got := FindLockCollisions(lf, asset.KindAgent); len(got) != 0 {
+		t.Errorf("agent collisions = %v, want none", got)
+	}
+}
+
+func TestValidateAlias(t *testing.T) {
+	for _, alias := range []string{"go-review-b", "db2"} {
+		if err := ValidateAlias(alias); err != nil {
+			t.Errorf("ValidateAlias(%q) error = %v", alias, err)
+		}
+	}
+	for _, alias := range []string{"", "Go Review", "-db", "a/b", "x.y"} {
+		if err := ValidateAlias(alias); err == nil {
+			t.Errorf("ValidateAlias(%q) expected error", alias)
+		}
+	}
+}
+
+func TestCheckInstallName(t *testing.T) {
+	lf := &LockFile{Assets: []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "Legacy.Skill"},
+	}}
+
+	if err := CheckInstallName(lf, asset.KindSkill, "Legacy.Skill"); err != nil {
+		t.Errorf("locked legacy name: error = %v, want nil", err)
+	}
+	if err := CheckInstallName(lf, asset.KindAgent, "Legacy.Skill"); err == nil {
+		t.Error("legacy name locked for another kind: expected error")
+	}
+	if err := CheckInstallName(nil, asset.KindSkill, "go-review"); err != nil {
+		t.Errorf("valid name: error = %v, want nil", err)
+	}
+	err := CheckInstallName(nil, asset.KindSkill, "Other.Skill")
+	var nameErr *asset.NameError
+	if !errors.As(err, &nameErr) {
+		t.Fatalf("invalid name: error = %v, want *asset.NameError", err)
+	}
+}
+
+func TestLockedUpstreamName(t *testing.T) {
+	plain := asset.LockedAsset{Kind: asset.KindSkill, Name: "go-review"}
+	if got := LockedUpstreamName(plain); got != "go-review" {
+		t.Errorf("LockedUpstreamName(plain) = %q", got)
+	}
+
+	aliased := OrchestratorInstallResult{
+		Asset:   asset.Asset{Kind: asset.KindSkill, Name: "go-review-b"},
+		AliasOf: "go-review",
+	}.LockEntry(nil, nil)
+	if got := LockedUpstreamName(aliased); got != "go-review" {
+		t.Errorf("LockedUpstreamName(aliased) = %q, want go-review", got)
+	}
+	if got := LockedAliasOf(aliased); got != "go-review" {
+		t.Errorf("LockedAliasOf(aliased) = %q, want go-review", got)
+	}
+}
+
+func TestCheckCaseCollisions(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, ".agents", "skills", "Go-Review"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(dir, ".agents", "skills", "lint"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+
+	// Same name as an existing entry is a reinstall, not a collision.
+	if err := checkCaseCollisions(asset.KindSkill, dir, nil, []asset.Asset{{Name: "lint"}}); err != nil {
+		t.Errorf("exact match: error = %v, want nil", err)
+	}
+
+	err := checkCaseCollisions(asset.KindSkill, dir, nil, []asset.Asset{{Name: "lint"}, {Name: "go-review"}})
+	var caseErr *CaseCollisionError
+	if !errors.As(err, &caseErr) {
+		t.Fatalf("error = %v, want *CaseCollisionError", err)
+	}
+	if caseErr.Name != "go-review" || caseErr.Existing != "Go-Review" || caseErr.Path != ".agents/skills/Go-Review" {
+		t.Errorf("collision = %+v", caseErr)
+	}
+}
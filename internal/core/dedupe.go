@@ -0,0 +1,122 @@
+package core
+
+import (
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// DuplicateSource is an upstream source indexed by more than one registry.
+type DuplicateSource struct {
+	Kind    asset.Kind       `json:"kind"`
+	Source  string           `json:"source"` // normalized canonical source
+	Entries []DuplicateEntry `json:"entries"`
+	Skew    bool             `json:"skew"` // entries resolve to different commits
+}
+
+// DuplicateEntry is one registry's entry for a duplicated source.
+type DuplicateEntry struct {
+	RegistryName string `json:"registry"`
+	RegistryRepo string `json:"registryRepo"`
+	Name         string `json:"name"`
+	Commit       string `json:"commit,omitempty"` // pinned or hydrated commit, if known
+	Pinned       bool   `json:"pinned,omitempty"`
+}
+
+// Commits returns the distinct known commits across the entries, sorted.
+func (d DuplicateSource) Commits() []string {
+	seen := make(map[string]bool)
+	var commits []string
+	for _, e := range d.Entries {
+		if e.Commit != "" && !seen[e.Commit] {
+			seen[e.Commit] = true
+			commits = append(commits, e.Commit)
+		}
+	}
+	sort.Strings(commits)
+	return commits
+}
+
+// DuplicateSources finds skill and agent entries in different registries that
+// point at the same upstream source, possibly under different names or pinned
+// to different commits. Commits come from the manifest pin or, for unpinned
+// entries, the registry's hydrated commit cache. Entries whose source cannot
+// be parsed are ignored.
+func (rm *RegistryManager) DuplicateSources(registries []Registry) []DuplicateSource {
+	groups := make(map[string]*DuplicateSource)
+
+	for _, reg := range registries {
+		manifest, err := rm.LoadManifest(reg.Repo)
+		if err != nil {
+			continue
+		}
+		parsed, err := ParseManifest(manifest)
+		if err != nil {
+			continue
+		}
+		cached := loadCachedCommits(filepath.Join(rm.registriesDir, RegistryDirKey(reg.Repo)))
+
+		for _, kind := range sourceBasedKinds() {
+			for _, entry := range parsed.Entries[kind] {
+				source, ok := normalizeEntrySource(entry.Source)
+				if !ok {
+					continue
+				}
+				key := lockOriginKey(kind, source)
+				g, exists := groups[key]
+				if !exists {
+					g = &DuplicateSource{Kind: kind, Source: source}
+					groups[key] = g
+				}
+
+				commit := entry.Commit
+				if commit == "" {
+					commit = cached[entry.Source]
+				}
+				g.Entries = append(g.Entries, DuplicateEntry{
+					RegistryName: parsed.Name,
+					RegistryRepo: reg.Repo,
+					Name:         entry.Name,
+					Commit:       commit,
+					Pinned:       entry.Commit != "",
+				})
+			}
+		}
+	}
+
+	var result []DuplicateSource
+	for _, g := range groups {
+		registriesSeen := make(map[string]bool)
+		for _, e := range g.Entries {
+			registriesSeen[e.RegistryRepo] = true
+		}
+		if len(registriesSeen) < 2 {
+			continue
+		}
+		g.Skew = len(g.Commits()) > 1
+		result = append(result, *g)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Kind != result[j].Kind {
+			return result[i].Kind < result[j].Kind
+		}
+		return result[i].Source < result[j].Source
+	})
+	return result
+}
+
+// normalizeEntrySource converts a manifest source (canonical or shorthand)
+// to a lower-cased canonical host/owner/repo/path form for comparison.
+func normalizeEntrySource(source string) (string, bool) {
+	if source == "" {
+		return "", false
+	}
+	parsed, err := ParseSource(source)
+	if err != nil || parsed.Owner == "" || parsed.Repo == "" {
+		return "", false
+	}
+	return strings.ToLower(NormalizeSource(parsed.Host, parsed.Owner, parsed.Repo, strings.Trim(parsed.SubPath, "/"))), true
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestRegistryManager_DuplicateSources(t *testing.T) {
+	registriesDir := t.TempDir()
+	rm := NewRegistryManager(registriesDir)
+
+	repoA := "git@example.com:team-a/registry.git"
+	repoB := "git@example.com:team-b/registry.git"
+	createTestRegistryClone(t, registriesDir, repoA, RegistryManifest{
+		Name: "team-a",
+		Skills: skillEntriesToRaw([]testSkillEntry{
+			{Name: "go-review", Source: "github.com/acme/skills/go-review", Commit: "aaa"},
+			{Name: "lint", Source: "github.com/acme/skills/lint"},
+			{Name: "only-a", Source: "github.com/acme/skills/only-a"},
+			// Same source twice within one registry is not a cross-registry duplicate.
+			{Name: "only-a-copy", Source: "github.com/acme/skills/only-a"},
+		}),
+	})
+	regDirB := createTestRegistryClone(t, registriesDir, repoB, RegistryManifest{
+		Name: "team-b",
+		Skills: skillEntriesToRaw([]testSkillEntry{
+			{Name: "golang-review", Source: "github.com/Acme/Skills/go-review", Commit: "bbb"},
+			{Name: "lint", Source: "github.com/acme/skills/lint"},
+		}),
+	})
+	if err := writeCachedCommits(regDirB, map[string]string{"github.com/acme/skills/lint": "ccc"}); err != nil {
+		t.Fatal(err)
+	}
+
+	dups := rm.DuplicateSources([]Registry{{Name: "team-a", Repo: repoA}, {Name: "team-b", Repo: repoB}})
+	if len(dups) != 2 {
+		t.Fatalf("len(dups) = %d, want 2: %+v", len(dups), dups)
+	}
+
+	goReview := dups[0]
+	if goReview.Kind != asset.KindSkill || goReview.Source != "github.com/acme/skills/go-review" {
+		t.Errorf("dups[0] = %s %s, want skill github.com/acme/skills/go-review", goReview.Kind, goReview.Source)
+	}
+	if !goReview.Skew {
+		t.Error("go-review: expected commit skew")
+	}
+	if len(goReview.Entries) != 2 || goReview.Entries[1].Name != "golang-review" || !goReview.Entries[1].Pinned {
+		t.Errorf("go-review entries = %+v", goReview.Entries)
+	}
+
+	lint := dups[1]
+	if lint.Source != "github.com/acme/skills/lint" {
+		t.Errorf("dups[1].Source = %q", lint.Source)
+	}
+	if lint.Skew {
+		t.Error("lint: one known commit should not be reported as skew")
+	}
+	if got := lint.Commits(); len(got) != 1 || got[0] != "ccc" {
+		t.Errorf("lint commits = %v, want [ccc]", got)
+	}
+}
+
+func TestNormalizeEntrySource(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		ok   bool
+	}{
+		{"github.com/Org/Repo/skills/x", "github.com/org/repo/skills/x", true},
+		{"github.com/org/repo", "github.com/org/repo", true},
+		{"", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := normalizeEntrySource(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("normalizeEntrySource(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+// DefaultSystems returns the systems named by the project's defaultSystems
+// setting, used in place of --systems when the flag is not given, and the
+// lock layer that set it. The personal local lock overrides the team lock.
+// It returns nil if neither sets it, meaning the built-in defaults apply:
+// universal systems for skills, and detected systems for MCPs and agents.
+func DefaultSystems(dir string) ([]system.System, LockOrigin, error) {
+	for _, layer := range []struct {
+		read   func(string) (*LockFile, error)
+		name   string
+		origin LockOrigin
+	}{
+		{ReadLocalLockFile, projectDuckrowDir + "/" + localLockFileName, OriginLocal},
+		{ReadLockFile, lockFileName, OriginTeam},
+	} {
+		lf, err := layer.read(dir)
+		if err != nil {
+			return nil, "", err
+		}
+		if lf == nil || len(lf.DefaultSystems) == 0 {
+			continue
+		}
+		systems, err := system.ByNames(lf.DefaultSystems)
+		if err != nil {
+			return nil, "", fmt.Errorf("defaultSystems in %s: %w", layer.name, err)
+		}
+		return systems, layer.origin, nil
+	}
+	return nil, "", nil
+}
+
+// SetLocalDefaultSystems sets defaultSystems in the personal local lock of
+// dir, overriding the team's. An empty list removes the override.
+func SetLocalDefaultSystems(dir string, names []string) error {
+	if _, err := system.ByNames(names); err != nil {
+		return fmt.Errorf("defaultSystems: %w", err)
+	}
+	lf, err := ReadLocalLockFile(dir)
+	if err != nil {
+		return err
+	}
+	if lf == nil {
+		if len(names) == 0 {
+			return nil
+		}
+		lf = &LockFile{LockVersion: currentLockVersion}
+	}
+	lf.DefaultSystems = names
+	return WriteLocalLockFile(dir, lf)
+}
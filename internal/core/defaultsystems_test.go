@@ -0,0 +1,89 @@
+package core
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+func TestDefaultSystems(t *testing.T) {
+	dir := t.TempDir()
+
+	systems, _, err := DefaultSystems(dir)
+	if err != nil || systems != nil {
+		t.Fatalf("no lock: DefaultSystems() = %v, %v; want nil, nil", systems, err)
+	}
+
+	// Set in an empty v3 lock file, which must survive later writes.
+	lock := `{"lockVersion": 3, "defaultSystems": ["claude-code", "cursor"], "assets": []}`
+	if err := os.WriteFile(LockFilePath(dir), []byte(lock), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddOrUpdateAsset(dir, asset.LockedAsset{Kind: asset.KindSkill, Name: "lint", Source: "github.com/o/r/lint"}); err != nil {
+		t.Fatal(err)
+	}
+
+	systems, origin, err := DefaultSystems(dir)
+	if err != nil {
+		t.Fatalf("DefaultSystems() error = %v", err)
+	}
+	if got := system.Names(systems); !reflect.DeepEqual(got, []string{"claude-code", "cursor"}) || origin != OriginTeam {
+		t.Errorf("team: DefaultSystems() = %v (%s), want [claude-code cursor] (team)", got, origin)
+	}
+
+	// The local lock overrides the team lock.
+	if err := WriteLocalLockFile(dir, &LockFile{DefaultSystems: []string{"opencode"}}); err != nil {
+		t.Fatal(err)
+	}
+	systems, origin, err = DefaultSystems(dir)
+	if err != nil {
+		t.Fatalf("DefaultSystems() error = %v", err)
+	}
+	if got := system.Names(systems); !reflect.DeepEqual(got, []string{"opencode"}) || origin != OriginLocal {
+		t.Errorf("local: DefaultSystems() = %v (%s), want [opencode] (local)", got, origin)
+	}
+	if lf, _ := ReadLayeredLockFile(dir); !reflect.DeepEqual(lf.DefaultSystems, []string{"opencode"}) {
+		t.Errorf("layered DefaultSystems = %v, want [opencode]", lf.DefaultSystems)
+	}
+
+	// Unknown names are reported with the file that has them.
+	if err := WriteLocalLockFile(dir, &LockFile{DefaultSystems: []string{"emacs"}}); err != nil {
+		t.Fatal(err)
+	}
+	_, _, err = DefaultSystems(dir)
+	if err == nil || !strings.Contains(err.Error(), ".duckrow/local.lock.json") || !strings.Contains(err.Error(), `unknown system "emacs"`) {
+		t.Errorf("unknown: error = %v", err)
+	}
+}
+
+func TestSetLocalDefaultSystems(t *testing.T) {
+	dir := t.TempDir()
+	if err := SetLocalDefaultSystems(dir, nil); err != nil {
+		t.Fatalf("clearing without a local lock: error = %v", err)
+	}
+	if _, err := os.Stat(LocalLockFilePath(dir)); !os.IsNotExist(err) {
+		t.Error("clearing created a local lock")
+	}
+
+	if err := SetLocalDefaultSystems(dir, []string{"emacs"}); err == nil {
+		t.Error("unknown system accepted")
+	}
+	if err := SetLocalDefaultSystems(dir, []string{"cursor"}); err != nil {
+		t.Fatal(err)
+	}
+	systems, origin, err := DefaultSystems(dir)
+	if got := system.Names(systems); err != nil || !reflect.DeepEqual(got, []string{"cursor"}) || origin != OriginLocal {
+		t.Errorf("DefaultSystems() = %v (%s), %v; want [cursor] (local)", got, origin, err)
+	}
+
+	if err := SetLocalDefaultSystems(dir, nil); err != nil {
+		t.Fatal(err)
+	}
+	if systems, _, _ := DefaultSystems(dir); systems != nil {
+		t.Errorf("DefaultSystems() after clearing = %v, want nil", system.Names(systems))
+	}
+}
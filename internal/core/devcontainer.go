@@ -0,0 +1,266 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/tailscale/hujson"
+)
+
+const (
+	// DefaultDevcontainerFeature provides Homebrew inside the container so
+	// the duckrow binary can be installed from the tap.
+	DefaultDevcontainerFeature = "ghcr.io/meaningful-ooo/devcontainer-features/homebrew:2"
+
+	// DefaultDevcontainerCommand installs duckrow and syncs the project's lock file.
+	DefaultDevcontainerCommand = "brew install barysiuk/tap/duckrow && duckrow sync"
+
+	// devcontainerImage is used when a new devcontainer.json is created.
+	devcontainerImage = "mcr.microsoft.com/devcontainers/base:ubuntu"
+
+	// devcontainerCommandKey names duckrow's entry when postCreateCommand
+	// is in object form.
+	devcontainerCommandKey = "duckrow"
+)
+
+// DevcontainerOptions configures InjectDevcontainer.
+type DevcontainerOptions struct {
+	Feature string // dev container feature to add; "" skips the feature
+	Command string // post-create command; defaults to DefaultDevcontainerCommand
+	DryRun  bool   // compute the result without writing it
+}
+
+// DevcontainerResult describes what InjectDevcontainer did.
+type DevcontainerResult struct {
+	Path    string   // devcontainer.json that was (or would be) written
+	Created bool     // the file did not exist before
+	Changes []string // human-readable list of changes; empty if already set up
+	Content []byte   // resulting file content
+}
+
+// DevcontainerPath returns the devcontainer.json used for a project: an
+// existing .devcontainer/devcontainer.json or .devcontainer.json, or the
+// former if neither exists.
+func DevcontainerPath(projectDir string) string {
+	primary := filepath.Join(projectDir, ".devcontainer", "devcontainer.json")
+	if _, err := os.Stat(primary); err == nil {
+		return primary
+	}
+	alt := filepath.Join(projectDir, ".devcontainer.json")
+	if _, err := os.Stat(alt); err == nil {
+		return alt
+	}
+	return primary
+}
+
+// InjectDevcontainer adds a duckrow feature and a post-create command to the
+// project's devcontainer.json without disturbing existing settings or
+// comments. An existing postCreateCommand is kept: string and array forms
+// are converted to the object form so both commands run. Running it again
+// on an already configured file makes no changes.
+func InjectDevcontainer(projectDir string, opts DevcontainerOptions) (*DevcontainerResult, error) {
+	if opts.Command == "" {
+		opts.Command = DefaultDevcontainerCommand
+	}
+
+	res := &DevcontainerResult{Path: DevcontainerPath(projectDir)}
+
+	content, err := os.ReadFile(res.Path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading %s: %w", res.Path, err)
+		}
+		res.Created = true
+		content = newDevcontainerJSON(projectDir)
+	}
+
+	root, err := hujson.Parse(content)
+	if err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", res.Path, err)
+	}
+	if _, ok := root.Value.(*hujson.Object); !ok {
+		return nil, fmt.Errorf("parsing %s: top-level value is not an object", res.Path)
+	}
+
+	if opts.Feature != "" {
+		changed, err := injectDevcontainerFeature(&root, opts.Feature)
+		if err != nil {
+			return nil, err
+		}
+		if changed {
+			res.Changes = append(res.Changes, fmt.Sprintf("added feature %s", opts.Feature))
+		}
+	}
+
+	change, err := injectPostCreateCommand(&root, opts.Command)
+	if err != nil {
+		return nil, err
+	}
+	if change != "" {
+		res.Changes = append(res.Changes, change)
+	}
+
+	if len(res.Changes) == 0 && !res.Created {
+		res.Content = content
+		return res, nil
+	}
+
+	root.Format()
+	removeTrailingJSONCommas(&root)
+	res.Content = root.Pack()
+
+	if opts.DryRun {
+		return res, nil
+	}
+	if err := os.MkdirAll(filepath.Dir(res.Path), 0o755); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", filepath.Dir(res.Path), err)
+	}
+	tmpPath := res.Path + ".tmp"
+	if err := os.WriteFile(tmpPath, res.Content, 0o644); err != nil {
+		return nil, fmt.Errorf("writing %s: %w", res.Path, err)
+	}
+	if err := os.Rename(tmpPath, res.Path); err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, fmt.Errorf("writing %s: %w", res.Path, err)
+	}
+	return res, nil
+}
+
+// newDevcontainerJSON returns a minimal devcontainer.json for a project.
+func newDevcontainerJSON(projectDir string) []byte {
+	name := filepath.Base(projectDir)
+	if abs, err := filepath.Abs(projectDir); err == nil {
+		name = filepath.Base(abs)
+	}
+	return []byte(fmt.Sprintf("{\n\t\"name\": %s,\n\t\"image\": %s\n}\n",
+		jsonString(name), jsonString(devcontainerImage)))
+}
+
+// injectDevcontainerFeature adds feature to the "features" object unless a
+// feature with the same ID (ignoring the version tag) is already present.
+func injectDevcontainerFeature(root *hujson.Value, feature string) (bool, error) {
+	features := root.Find("/features")
+	if features == nil {
+		patch := fmt.Sprintf(`[{"op":"add","path":"/features","value":{%q:{}}}]`, feature)
+		if err := root.Patch([]byte(patch)); err != nil {
+			return false, fmt.Errorf("adding features: %w", err)
+		}
+		return true, nil
+	}
+
+	obj, ok := features.Value.(*hujson.Object)
+	if !ok {
+		return false, fmt.Errorf("\"features\" is not an object")
+	}
+	id := featureID(feature)
+	for _, m := range obj.Members {
+		if featureID(m.Name.Value.(hujson.Literal).String()) == id {
+			return false, nil
+		}
+	}
+
+	patch := fmt.Sprintf(`[{"op":"add","path":"/features/%s","value":{}}]`, jsonPointerToken(feature))
+	if err := root.Patch([]byte(patch)); err != nil {
+		return false, fmt.Errorf("adding feature %s: %w", feature, err)
+	}
+	return true, nil
+}
+
+// injectPostCreateCommand makes sure command runs after the container is
+// created. It returns a description of the change, or "" if none was needed.
+func injectPostCreateCommand(root *hujson.Value, command string) (string, error) {
+	existing := root.Find("/postCreateCommand")
+	if existing == nil {
+		patch := fmt.Sprintf(`[{"op":"add","path":"/postCreateCommand","value":%s}]`, jsonString(command))
+		if err := root.Patch([]byte(patch)); err != nil {
+			return "", fmt.Errorf("adding postCreateCommand: %w", err)
+		}
+		return "set postCreateCommand", nil
+	}
+
+	packed := standardJSON(existing)
+	escaped := strings.Trim(jsonString(command), `"`)
+	if strings.Contains(string(packed), "duckrow sync") || strings.Contains(string(packed), escaped) {
+		return "", nil
+	}
+
+	if obj, ok := existing.Value.(*hujson.Object); ok {
+		key := devcontainerCommandKey
+		for i := 2; objectHasMember(obj, key); i++ {
+			key = fmt.Sprintf("%s-%d", devcontainerCommandKey, i)
+		}
+		patch := fmt.Sprintf(`[{"op":"add","path":"/postCreateCommand/%s","value":%s}]`,
+			jsonPointerToken(key), jsonString(command))
+		if err := root.Patch([]byte(patch)); err != nil {
+			return "", fmt.Errorf("updating postCreateCommand: %w", err)
+		}
+		return fmt.Sprintf("added %q to postCreateCommand", key), nil
+	}
+
+	// String or array form: keep the original command alongside ours.
+	value := fmt.Sprintf(`{"existing":%s,%q:%s}`, packed, devcontainerCommandKey, jsonString(command))
+	patch := fmt.Sprintf(`[{"op":"replace","path":"/postCreateCommand","value":%s}]`, value)
+	if err := root.Patch([]byte(patch)); err != nil {
+		return "", fmt.Errorf("updating postCreateCommand: %w", err)
+	}
+	return "converted postCreateCommand to object form and added duckrow sync", nil
+}
+
+// featureID strips the version tag from a feature reference.
+func featureID(ref string) string {
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return ref[:i]
+	}
+	return ref
+}
+
+func objectHasMember(obj *hujson.Object, name string) bool {
+	for _, m := range obj.Members {
+		if m.Name.Value.(hujson.Literal).String() == name {
+			return true
+		}
+	}
+	return false
+}
+
+// standardJSON returns v as compact standard JSON.
+func standardJSON(v *hujson.Value) []byte {
+	c := v.Clone()
+	c.Standardize()
+	c.Minimize()
+	return c.Pack()
+}
+
+func jsonString(s string) string {
+	data, _ := json.Marshal(s)
+	return string(data)
+}
+
+// jsonPointerToken escapes a string for use as a JSON Pointer token (RFC 6901).
+func jsonPointerToken(s string) string {
+	return strings.ReplaceAll(strings.ReplaceAll(s, "~", "~0"), "/", "~1")
+}
+
+// removeTrailingJSONCommas walks the JSONC AST and removes trailing commas
+// that hujson.Format adds to multi-line objects and arrays.
+func removeTrailingJSONCommas(v *hujson.Value) {
+	switch vv := v.Value.(type) {
+	case *hujson.Object:
+		for i := range vv.Members {
+			removeTrailingJSONCommas(&vv.Members[i].Value)
+		}
+		if len(vv.Members) > 0 {
+			vv.Members[len(vv.Members)-1].Value.AfterExtra = nil
+		}
+	case *hujson.Array:
+		for i := range vv.Elements {
+			removeTrailingJSONCommas(&vv.Elements[i])
+		}
+		if len(vv.Elements) > 0 {
+			vv.Elements[len(vv.Elements)-1].AfterExtra = nil
+		}
+	}
+}
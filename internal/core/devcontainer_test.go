@@ -0,0 +1,137 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/tailscale/hujson"
+)
+
+// readDevcontainer parses a devcontainer.json (JSONC) into a generic map.
+func readDevcontainer(t *testing.T, path string) map[string]any {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	std, err := hujson.Standardize(data)
+	if err != nil {
+		t.Fatalf("invalid JSONC: %v\n%s", err, data)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(std, &m); err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestInjectDevcontainer_CreatesFile(t *testing.T) {
+	dir := t.TempDir()
+
+	res, err := InjectDevcontainer(dir, DevcontainerOptions{Feature: DefaultDevcontainerFeature})
+	if err != nil {
+		t.Fatalf("InjectDevcontainer() error = %v", err)
+	}
+	if !res.Created {
+		t.Error("expected Created = true")
+	}
+
+	m := readDevcontainer(t, filepath.Join(dir, ".devcontainer", "devcontainer.json"))
+	if m["postCreateCommand"] != DefaultDevcontainerCommand {
+		t.Errorf("postCreateCommand = %v", m["postCreateCommand"])
+	}
+	features, _ := m["features"].(map[string]any)
+	if _, ok := features[DefaultDevcontainerFeature]; !ok {
+		t.Errorf("features = %v, want %s", features, DefaultDevcontainerFeature)
+	}
+	if m["image"] == nil {
+		t.Error("expected an image in a new devcontainer.json")
+	}
+}
+
+func TestInjectDevcontainer_PreservesExisting(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".devcontainer.json")
+	original := `{
+  // team container
+  "name": "app",
+  "features": {
+    "ghcr.io/meaningful-ooo/devcontainer-features/homebrew:1": {}
+  },
+  "postCreateCommand": ["npm", "ci"]
+}
+`
+	if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := InjectDevcontainer(dir, DevcontainerOptions{Feature: DefaultDevcontainerFeature})
+	if err != nil {
+		t.Fatalf("InjectDevcontainer() error = %v", err)
+	}
+	if res.Path != path {
+		t.Errorf("Path = %s, want %s", res.Path, path)
+	}
+	if len(res.Changes) != 1 {
+		t.Errorf("Changes = %v, want only the postCreateCommand change (feature already present)", res.Changes)
+	}
+
+	data, _ := os.ReadFile(path)
+	if !strings.Contains(string(data), "// team container") {
+		t.Errorf("comment was lost:\n%s", data)
+	}
+	m := readDevcontainer(t, path)
+	if m["name"] != "app" {
+		t.Errorf("name = %v, want app", m["name"])
+	}
+	cmds, ok := m["postCreateCommand"].(map[string]any)
+	if !ok {
+		t.Fatalf("postCreateCommand = %#v, want object form", m["postCreateCommand"])
+	}
+	if existing, _ := cmds["existing"].([]any); len(existing) != 2 {
+		t.Errorf("existing command = %v, want [npm ci]", cmds["existing"])
+	}
+	if cmds["duckrow"] != DefaultDevcontainerCommand {
+		t.Errorf("duckrow command = %v", cmds["duckrow"])
+	}
+
+	// A second run is a no-op.
+	res, err = InjectDevcontainer(dir, DevcontainerOptions{Feature: DefaultDevcontainerFeature})
+	if err != nil {
+		t.Fatalf("InjectDevcontainer() second run error = %v", err)
+	}
+	if len(res.Changes) != 0 {
+		t.Errorf("second run Changes = %v, want none", res.Changes)
+	}
+}
+
+func TestInjectDevcontainer_ObjectCommandAndDryRun(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".devcontainer", "devcontainer.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	original := `{"postCreateCommand": {"duckrow": "echo taken"}}`
+	if err := os.WriteFile(path, []byte(original), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := InjectDevcontainer(dir, DevcontainerOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("InjectDevcontainer() error = %v", err)
+	}
+	if !strings.Contains(string(res.Content), `"duckrow-2"`) {
+		t.Errorf("expected a non-clashing key in:\n%s", res.Content)
+	}
+	if strings.Contains(string(res.Content), "features") {
+		t.Errorf("no feature should be added when Feature is empty:\n%s", res.Content)
+	}
+
+	data, _ := os.ReadFile(path)
+	if string(data) != original {
+		t.Errorf("dry run modified the file:\n%s", data)
+	}
+}
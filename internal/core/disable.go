@@ -0,0 +1,65 @@
+package core
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+// DisableAgent disables an installed agent for systems, or for every system
+// it is installed for when systems is nil: each system's agent file is
+// renamed with system.DisabledSuffix, so the system stops reading it. The
+// lock entry is kept, and sync and update leave the agent disabled. It
+// returns the systems the agent was disabled for; none if it already was.
+func DisableAgent(name, targetDir string, systems []system.System) ([]system.System, error) {
+	return toggleAgent(name, targetDir, systems, "", system.DisabledSuffix)
+}
+
+// EnableAgent restores the agent files DisableAgent renamed, for systems or
+// for every system the agent is disabled for when systems is nil. It
+// returns the systems the agent was enabled for; none if it already was.
+func EnableAgent(name, targetDir string, systems []system.System) ([]system.System, error) {
+	return toggleAgent(name, targetDir, systems, system.DisabledSuffix, "")
+}
+
+// DisabledSystems returns the systems an agent is disabled for in
+// targetDir.
+func DisabledSystems(name, targetDir string) []system.System {
+	var result []system.System
+	for _, sys := range system.Supporting(asset.KindAgent) {
+		if pathExists(sys.AssetPath(asset.KindAgent, name, targetDir) + system.DisabledSuffix) {
+			result = append(result, sys)
+		}
+	}
+	return result
+}
+
+// toggleAgent renames each system's agent file from the from suffix to the
+// to suffix.
+func toggleAgent(name, targetDir string, systems []system.System, from, to string) ([]system.System, error) {
+	installed := SystemsWithAsset(asset.KindAgent, name, targetDir)
+	if len(installed) == 0 {
+		return nil, fmt.Errorf("agent %q is not installed", name)
+	}
+	if systems == nil {
+		systems = installed
+	}
+
+	var changed []system.System
+	for _, sys := range systems {
+		if !sys.Supports(asset.KindAgent) {
+			continue
+		}
+		path := sys.AssetPath(asset.KindAgent, name, targetDir)
+		if !pathExists(path + from) {
+			continue
+		}
+		if err := os.Rename(path+from, path+to); err != nil {
+			return changed, fmt.Errorf("renaming agent %q for %s: %w", name, sys.DisplayName(), err)
+		}
+		changed = append(changed, sys)
+	}
+	return changed, nil
+}
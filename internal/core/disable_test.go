@@ -0,0 +1,78 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+func TestDisableEnableAgent(t *testing.T) {
+	dir := t.TempDir()
+	for _, rel := range []string{".claude/agents/reviewer.md", ".opencode/agents/reviewer.md"} {
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("---\nname: reviewer\n---\n\nReview.\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	claude, _ := system.ByName("claude-code")
+	opencode, _ := system.ByName("opencode")
+	claudePath := filepath.Join(dir, ".claude", "agents", "reviewer.md")
+
+	changed, err := DisableAgent("reviewer", dir, []system.System{claude})
+	if err != nil {
+		t.Fatalf("DisableAgent() error = %v", err)
+	}
+	if got := system.DisplayNames(changed); !reflect.DeepEqual(got, []string{claude.DisplayName()}) {
+		t.Errorf("DisableAgent() changed = %v, want [%s]", got, claude.DisplayName())
+	}
+	if pathExists(claudePath) || !pathExists(claudePath+system.DisabledSuffix) {
+		t.Errorf("expected %s to be renamed with %s", claudePath, system.DisabledSuffix)
+	}
+	if got := DisabledSystems("reviewer", dir); len(got) != 1 || got[0].Name() != claude.Name() {
+		t.Errorf("DisabledSystems() = %v, want [claude-code]", system.DisplayNames(got))
+	}
+
+	// A disabled agent still counts as installed.
+	if got := SystemsWithAsset(asset.KindAgent, "reviewer", dir); len(got) != 2 {
+		t.Errorf("SystemsWithAsset() = %v, want both systems", system.DisplayNames(got))
+	}
+
+	// Disabling for every system only touches the one still enabled.
+	changed, err = DisableAgent("reviewer", dir, nil)
+	if err != nil {
+		t.Fatalf("DisableAgent(all) error = %v", err)
+	}
+	if got := system.DisplayNames(changed); !reflect.DeepEqual(got, []string{opencode.DisplayName()}) {
+		t.Errorf("DisableAgent(all) changed = %v, want [%s]", got, opencode.DisplayName())
+	}
+
+	changed, err = DisableAgent("reviewer", dir, nil)
+	if err != nil || len(changed) != 0 {
+		t.Errorf("DisableAgent(again) = %v, %v; want nothing changed", system.DisplayNames(changed), err)
+	}
+
+	changed, err = EnableAgent("reviewer", dir, nil)
+	if err != nil {
+		t.Fatalf("EnableAgent() error = %v", err)
+	}
+	if len(changed) != 2 {
+		t.Errorf("EnableAgent() changed = %v, want both systems", system.DisplayNames(changed))
+	}
+	if !pathExists(claudePath) || pathExists(claudePath+system.DisabledSuffix) {
+		t.Errorf("expected %s to be restored", claudePath)
+	}
+	if got := DisabledSystems("reviewer", dir); len(got) != 0 {
+		t.Errorf("DisabledSystems() after enable = %v, want none", system.DisplayNames(got))
+	}
+
+	if _, err := DisableAgent("missing", dir, nil); err == nil {
+		t.Error("DisableAgent(missing) expected an error")
+	}
+}
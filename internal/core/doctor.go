@@ -0,0 +1,263 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// DoctorStatus is the outcome of a doctor check.
+type DoctorStatus string
+
+const (
+	DoctorOK   DoctorStatus = "ok"
+	DoctorWarn DoctorStatus = "warn" // works, but worth cleaning up
+	DoctorFail DoctorStatus = "fail" // duckrow, or the project, is broken
+)
+
+// DoctorCheck is the result of one check run by `duckrow doctor`.
+type DoctorCheck struct {
+	Name    string       `json:"name"`
+	Status  DoctorStatus `json:"status"`
+	Summary string       `json:"summary"`
+	Details []string     `json:"details,omitempty"`
+}
+
+// minGitVersion is the oldest git duckrow works with: 2.5 is the first to
+// fetch a single commit by its SHA, which installs at a pinned commit do.
+var minGitVersion = [2]int{2, 5}
+
+// gitVersionRe matches the version in `git --version` output, e.g.
+// "git version 2.39.3 (Apple Git-146)" or "git version 2.45.1.windows.1".
+var gitVersionRe = regexp.MustCompile(`(\d+)\.(\d+)(\.\d+)?`)
+
+// Doctor checks the environment duckrow runs in: git, the config and
+// registries in cm, and the project in projectDir. Checks are returned in
+// the order they ran; a check that can't run, e.g. because the config
+// doesn't load, is left out.
+func Doctor(cm *ConfigManager, projectDir string) []DoctorCheck {
+	checks := []DoctorCheck{checkGit()}
+
+	cfg, cfgErr := cm.Load()
+	checks = append(checks, checkConfigPaths(cm, cfgErr))
+	if cfgErr == nil {
+		rm := NewRegistryManager(cm.RegistriesDir())
+		checks = append(checks, checkRegistries(rm, cfg.Registries), checkOrphanedRegistries(rm, cfg.Registries))
+	}
+
+	return append(checks,
+		checkLockDrift(projectDir),
+		checkSkillLinks(projectDir),
+		checkMCPEnv(projectDir, cm.ConfigDir()),
+	)
+}
+
+// DoctorFailed reports whether any check failed.
+func DoctorFailed(checks []DoctorCheck) bool {
+	for _, c := range checks {
+		if c.Status == DoctorFail {
+			return true
+		}
+	}
+	return false
+}
+
+func checkGit() DoctorCheck {
+	c := DoctorCheck{Name: "git"}
+	out, err := exec.Command("git", "--version").Output()
+	if err != nil {
+		c.Status = DoctorFail
+		c.Summary = "git not found: duckrow needs git to clone registries and sources"
+		return c
+	}
+	version := strings.TrimPrefix(strings.TrimSpace(string(out)), "git version ")
+	m := gitVersionRe.FindStringSubmatch(version)
+	if m == nil {
+		c.Status = DoctorWarn
+		c.Summary = fmt.Sprintf("git %s: version not recognized", version)
+		return c
+	}
+	major, _ := strconv.Atoi(m[1])
+	minor, _ := strconv.Atoi(m[2])
+	if major < minGitVersion[0] || (major == minGitVersion[0] && minor < minGitVersion[1]) {
+		c.Status = DoctorFail
+		c.Summary = fmt.Sprintf("git %s is too old; duckrow needs git %d.%d or later", version, minGitVersion[0], minGitVersion[1])
+		return c
+	}
+	c.Status = DoctorOK
+	c.Summary = "git " + version
+	return c
+}
+
+func checkConfigPaths(cm *ConfigManager, loadErr error) DoctorCheck {
+	c := DoctorCheck{Name: "config", Status: DoctorOK, Summary: cm.ConfigPath()}
+	if loadErr != nil {
+		c.Status = DoctorFail
+		c.Summary = loadErr.Error()
+	}
+	for _, path := range []string{cm.ConfigDir(), cm.ConfigPath(), cm.RegistriesDir()} {
+		if err := checkWritable(path); err != nil {
+			c.Status = DoctorFail
+			c.Details = append(c.Details, fmt.Sprintf("%s: %v", path, err))
+		}
+	}
+	if loadErr == nil && len(c.Details) > 0 {
+		c.Summary = fmt.Sprintf("%d config path(s) not writable", len(c.Details))
+	}
+	return c
+}
+
+// checkWritable reports whether path can be written to: an existing file
+// opened for writing, an existing directory by creating a file in it, and a
+// path that doesn't exist yet by the nearest directory above it.
+func checkWritable(path string) error {
+	info, err := os.Stat(path)
+	switch {
+	case os.IsNotExist(err):
+		parent := filepath.Dir(path)
+		if parent == path {
+			return err
+		}
+		return checkWritable(parent)
+	case err != nil:
+		return err
+	case !info.IsDir():
+		f, err := os.OpenFile(path, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		return f.Close()
+	}
+	f, err := os.CreateTemp(path, ".duckrow-doctor-*")
+	if err != nil {
+		return err
+	}
+	_ = f.Close()
+	return os.Remove(f.Name())
+}
+
+func checkRegistries(rm *RegistryManager, registries []Registry) DoctorCheck {
+	c := DoctorCheck{Name: "registries", Status: DoctorOK}
+	for _, reg := range registries {
+		if err := rm.CheckClone(reg.Repo); err != nil {
+			c.Details = append(c.Details, fmt.Sprintf("%s (%s): %v", reg.Name, reg.Repo, err))
+		}
+	}
+	switch {
+	case len(registries) == 0:
+		c.Summary = "no registries configured"
+	case len(c.Details) > 0:
+		c.Status = DoctorFail
+		c.Summary = fmt.Sprintf("%d of %d registry clone(s) broken; remove and add them again", len(c.Details), len(registries))
+	default:
+		c.Summary = fmt.Sprintf("%d registry clone(s) healthy", len(registries))
+	}
+	return c
+}
+
+func checkOrphanedRegistries(rm *RegistryManager, registries []Registry) DoctorCheck {
+	c := DoctorCheck{Name: "registry-dirs", Status: DoctorOK, Summary: "no orphaned registry directories"}
+	orphaned, err := rm.OrphanedDirs(registries)
+	if err != nil {
+		c.Status = DoctorFail
+		c.Summary = err.Error()
+		return c
+	}
+	if len(orphaned) > 0 {
+		c.Status = DoctorWarn
+		c.Summary = fmt.Sprintf("%d director(ies) not used by any registry; they can be deleted", len(orphaned))
+		c.Details = orphaned
+	}
+	return c
+}
+
+func checkLockDrift(projectDir string) DoctorCheck {
+	c := DoctorCheck{Name: "lock"}
+	if !pathExists(LockFilePath(projectDir)) && !pathExists(LocalLockFilePath(projectDir)) {
+		c.Status = DoctorOK
+		c.Summary = "no lock file"
+		return c
+	}
+	issues, err := CheckLockConsistency(projectDir)
+	if err != nil {
+		c.Status = DoctorFail
+		c.Summary = err.Error()
+		return c
+	}
+	if len(issues) == 0 {
+		c.Status = DoctorOK
+		c.Summary = "lock file matches installed assets"
+		return c
+	}
+	c.Status = DoctorFail
+	c.Summary = fmt.Sprintf("%d lock issue(s); run 'duckrow sync'", len(issues))
+	for _, issue := range issues {
+		c.Details = append(c.Details, issue.String())
+	}
+	return c
+}
+
+func checkSkillLinks(projectDir string) DoctorCheck {
+	c := DoctorCheck{Name: "links"}
+	issues, err := NewOrchestrator().ScanLinks(projectDir)
+	if err != nil {
+		c.Status = DoctorFail
+		c.Summary = err.Error()
+		return c
+	}
+	if len(issues) == 0 {
+		c.Status = DoctorOK
+		c.Summary = "no broken or stale skill links"
+		return c
+	}
+	c.Status = DoctorFail
+	c.Summary = fmt.Sprintf("%d broken or stale skill link(s); run 'duckrow repair'", len(issues))
+	for _, issue := range issues {
+		c.Details = append(c.Details, issue.String())
+	}
+	return c
+}
+
+func checkMCPEnv(projectDir, configDir string) DoctorCheck {
+	c := DoctorCheck{Name: "mcp-env", Status: DoctorOK, Summary: "no locked MCPs"}
+	lf, err := ReadLayeredLockFile(projectDir)
+	if err != nil {
+		c.Status = DoctorFail
+		c.Summary = err.Error()
+		return c
+	}
+	mcps := AssetsByKind(lf, asset.KindMCP)
+	if len(mcps) == 0 {
+		return c
+	}
+
+	resolver := NewEnvResolver(projectDir, configDir)
+	for _, m := range mcps {
+		overrides := LockedMCPOverrides(m)
+		_, missing := resolver.ResolveEnv(LockedRequiredEnv(m))
+		var unset []string
+		for _, name := range missing {
+			if _, ok := overrides.Env[name]; !ok {
+				unset = append(unset, name)
+			}
+		}
+		if len(unset) > 0 {
+			sort.Strings(unset)
+			c.Details = append(c.Details, fmt.Sprintf("%s: %s", m.Name, strings.Join(unset, ", ")))
+		}
+	}
+	if len(c.Details) == 0 {
+		c.Summary = fmt.Sprintf("env vars set for %d locked MCP(s)", len(mcps))
+		return c
+	}
+	c.Status = DoctorWarn
+	c.Summary = fmt.Sprintf("%d MCP(s) missing env vars; set them in the environment or .env.duckrow", len(c.Details))
+	return c
+}
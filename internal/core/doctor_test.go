@@ -0,0 +1,138 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestRegistryManager_CheckClone(t *testing.T) {
+	registriesDir := t.TempDir()
+	rm := NewRegistryManager(registriesDir)
+
+	bareRepo := t.TempDir()
+	setupTestGitRepo(t, bareRepo)
+	if _, err := rm.Add(bareRepo); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if err := rm.CheckClone(bareRepo); err != nil {
+		t.Errorf("CheckClone() of a fresh clone = %v, want nil", err)
+	}
+
+	// A manifest without a git repository around it is a broken clone.
+	broken := "git@example.com:my-org/broken.git"
+	createTestRegistryClone(t, registriesDir, broken, RegistryManifest{Name: "broken"})
+	if err := rm.CheckClone(broken); err == nil || !strings.Contains(err.Error(), "broken git clone") {
+		t.Errorf("CheckClone() of a clone without .git = %v, want a broken git clone error", err)
+	}
+
+	if err := rm.CheckClone("git@example.com:my-org/missing.git"); err == nil {
+		t.Error("CheckClone() of a missing clone = nil, want an error")
+	}
+}
+
+func TestRegistryManager_OrphanedDirs(t *testing.T) {
+	registriesDir := t.TempDir()
+	rm := NewRegistryManager(registriesDir)
+
+	kept := "git@example.com:my-org/kept.git"
+	createTestRegistryClone(t, registriesDir, kept, RegistryManifest{Name: "kept"})
+	removed := createTestRegistryClone(t, registriesDir, "git@example.com:my-org/removed.git", RegistryManifest{Name: "removed"})
+	// Files in the registries directory aren't registry clones.
+	if err := os.WriteFile(filepath.Join(registriesDir, "notes.txt"), nil, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := rm.OrphanedDirs([]Registry{{Name: "kept", Repo: kept}})
+	if err != nil {
+		t.Fatalf("OrphanedDirs() error = %v", err)
+	}
+	if len(got) != 1 || got[0] != removed {
+		t.Errorf("OrphanedDirs() = %v, want [%s]", got, removed)
+	}
+
+	got, err = NewRegistryManager(filepath.Join(registriesDir, "missing")).OrphanedDirs(nil)
+	if err != nil || len(got) != 0 {
+		t.Errorf("OrphanedDirs() without a registries directory = %v, %v, want none", got, err)
+	}
+}
+
+func TestCheckWritable(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(file, []byte("{}"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, path := range []string{dir, file, filepath.Join(dir, "not", "yet", "created")} {
+		if err := checkWritable(path); err != nil {
+			t.Errorf("checkWritable(%s) = %v, want nil", path, err)
+		}
+	}
+
+	// A directory that would have to be created inside a file can't be.
+	if err := checkWritable(filepath.Join(file, "registries")); err == nil {
+		t.Error("checkWritable() below a file = nil, want an error")
+	}
+	entries, _ := os.ReadDir(dir)
+	if len(entries) != 1 {
+		t.Errorf("checkWritable() left files behind: %v", entries)
+	}
+}
+
+func TestCheckMCPEnv(t *testing.T) {
+	dir := t.TempDir()
+	configDir := t.TempDir()
+	lf := &LockFile{LockVersion: 1, Assets: []asset.LockedAsset{
+		{Kind: asset.KindMCP, Name: "db", Data: map[string]any{"requiredEnv": []any{"DOCTOR_DB_HOST", "DOCTOR_DB_TOKEN"}}},
+		{Kind: asset.KindMCP, Name: "search", Data: map[string]any{
+			"requiredEnv":   []any{"DOCTOR_SEARCH_KEY"},
+			mcpOverridesKey: map[string]any{"env": map[string]any{"DOCTOR_SEARCH_KEY": "set-by-edit"}},
+		}},
+	}}
+	if err := WriteLockFile(dir, lf); err != nil {
+		t.Fatal(err)
+	}
+	if err := WriteEnvVar(dir, "DOCTOR_DB_HOST", "localhost"); err != nil {
+		t.Fatal(err)
+	}
+
+	c := checkMCPEnv(dir, configDir)
+	if c.Status != DoctorWarn {
+		t.Errorf("Status = %q, want %q", c.Status, DoctorWarn)
+	}
+	if len(c.Details) != 1 || c.Details[0] != "db: DOCTOR_DB_TOKEN" {
+		t.Errorf("Details = %v, want [db: DOCTOR_DB_TOKEN]", c.Details)
+	}
+
+	t.Setenv("DOCTOR_DB_TOKEN", "secret")
+	if c := checkMCPEnv(dir, configDir); c.Status != DoctorOK {
+		t.Errorf("Status with every var set = %q (%v), want %q", c.Status, c.Details, DoctorOK)
+	}
+}
+
+func TestDoctor_FailsOnBrokenRegistry(t *testing.T) {
+	configDir := t.TempDir()
+	cm := NewConfigManagerWithDir(configDir)
+	repo := "git@example.com:my-org/broken.git"
+	if err := cm.Save(&Config{Registries: []Registry{{Name: "broken", Repo: repo}}}); err != nil {
+		t.Fatal(err)
+	}
+	createTestRegistryClone(t, cm.RegistriesDir(), repo, RegistryManifest{Name: "broken"})
+
+	checks := Doctor(cm, t.TempDir())
+	if !DoctorFailed(checks) {
+		t.Fatalf("DoctorFailed() = false, want true: %+v", checks)
+	}
+	for _, c := range checks {
+		if c.Name == "registries" && c.Status != DoctorFail {
+			t.Errorf("registries check = %+v, want it to fail", c)
+		}
+		if c.Name != "registries" && c.Status == DoctorFail {
+			t.Errorf("%s check failed: %+v", c.Name, c)
+		}
+	}
+}
@@ -0,0 +1,133 @@
+package core_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/gittest"
+)
+
+// TestEndToEnd_RegistryInstallSyncUpdate runs the add → install → sync →
+// update flow against a local git server instead of github.com.
+func TestEndToEnd_RegistryInstallSyncUpdate(t *testing.T) {
+	srv := gittest.NewServer(t)
+	overrides := srv.CloneURLOverrides()
+
+	skills := srv.NewRepo("acme", "skills")
+	skills.AddSkill("skills/lint", "lint", "Lints things")
+	skills.WriteFile("skills/lint/rules.md", "v1\n")
+	skills.AddSkill("skills/fmt", "fmt", "Formats things")
+	first := skills.Commit("add skills")
+
+	registry := srv.NewRepo("acme", "registry")
+	registry.WriteJSON("duckrow.json", map[string]any{
+		"name": "acme",
+		"skills": []map[string]string{
+			{"name": "lint", "description": "Lints things", "source": skills.Source("skills", "lint")},
+		},
+	})
+	registry.Commit("add registry")
+
+	// Add the registry and find the skill in it.
+	rm := core.NewRegistryManager(t.TempDir())
+	manifest, err := rm.Add(registry.CloneURL)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	registries := []core.Registry{{Name: manifest.Name, Repo: registry.CloneURL}}
+	info, err := rm.FindSkill(registries, "lint", "")
+	if err != nil {
+		t.Fatalf("FindSkill() error = %v", err)
+	}
+
+	// Install it from the source the registry gives.
+	source, err := core.ParseSource(info.Skill.Source)
+	if err != nil {
+		t.Fatalf("ParseSource(%q) error = %v", info.Skill.Source, err)
+	}
+	if !source.ApplyCloneURLOverride(overrides) {
+		t.Fatalf("no clone URL override for %q", info.Skill.Source)
+	}
+
+	project := t.TempDir()
+	orch := core.NewOrchestrator()
+	results, err := orch.InstallFromSource(source, asset.KindSkill, core.OrchestratorInstallOptions{
+		TargetDir: project,
+	})
+	if err != nil {
+		t.Fatalf("InstallFromSource() error = %v", err)
+	}
+	if len(results) != 1 || results[0].Asset.Name != "lint" || results[0].Commit != first {
+		t.Fatalf("results = %+v, want lint at %s", results, first)
+	}
+	r := results[0]
+	if err := core.AddOrUpdateAsset(project, r.LockEntry(nil, nil)); err != nil {
+		t.Fatal(err)
+	}
+	rules := filepath.Join(project, ".agents", "skills", "lint", "rules.md")
+	assertFile(t, rules, "v1\n")
+
+	// Sync restores a deleted skill from the lock.
+	if err := os.RemoveAll(filepath.Join(project, ".agents", "skills", "lint")); err != nil {
+		t.Fatal(err)
+	}
+	lf, err := core.ReadLockFile(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	res, err := orch.SyncFromLock(lf, core.OrchestratorInstallOptions{
+		TargetDir:         project,
+		CloneURLOverrides: overrides,
+	})
+	if err != nil || len(res.Errors) > 0 || res.Installed != 1 {
+		t.Fatalf("SyncFromLock() = %+v, %v", res, err)
+	}
+	assertFile(t, rules, "v1\n")
+
+	// An upstream change to the skill shows up as an update.
+	skills.WriteFile("skills/lint/rules.md", "v2\n")
+	second := skills.Commit("update lint")
+	updates, err := core.CheckForUpdates(lf, asset.KindSkill, overrides, nil)
+	if err != nil {
+		t.Fatalf("CheckForUpdates() error = %v", err)
+	}
+	if len(updates) != 1 || !updates[0].HasUpdate || updates[0].AvailableCommit != second {
+		t.Fatalf("updates = %+v, want lint available at %s", updates, second)
+	}
+
+	// Updating installs the new commit.
+	_, err = orch.InstallFromSource(source, asset.KindSkill, core.OrchestratorInstallOptions{
+		TargetDir: project,
+		Commit:    updates[0].AvailableCommit,
+	})
+	if err != nil {
+		t.Fatalf("InstallFromSource(update) error = %v", err)
+	}
+	assertFile(t, rules, "v2\n")
+
+	// A change elsewhere in the repo is not an update for lint.
+	skills.WriteFile("skills/fmt/notes.md", "fmt only\n")
+	skills.Commit("touch fmt")
+	lf.Assets[0].Commit = second
+	updates, err = core.CheckForUpdates(lf, asset.KindSkill, overrides, nil)
+	if err != nil {
+		t.Fatalf("CheckForUpdates() error = %v", err)
+	}
+	if len(updates) != 1 || updates[0].HasUpdate {
+		t.Errorf("updates = %+v, want lint up to date", updates)
+	}
+}
+
+func assertFile(t *testing.T, path, want string) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("reading %s: %v", path, err)
+	}
+	if string(data) != want {
+		t.Errorf("%s = %q, want %q", path, data, want)
+	}
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"bufio"
 	"fmt"
+	"io"
 	"os"
 	"path/filepath"
 	"strings"
@@ -12,6 +13,25 @@ const (
 	envFileName = ".env.duckrow"
 )
 
+// ProjectDirPlaceholder stands for the project an MCP server runs in, in
+// the command, args, and env values of its definition.
+const ProjectDirPlaceholder = "{{projectDir}}"
+
+// ExpandMCPValue expands the placeholders an MCP definition may use:
+// ProjectDirPlaceholder becomes projectDir, and a leading ~ (alone or
+// followed by a path separator) the user's home directory. The definition is
+// written to config files and the lock as is, so they stay portable, and
+// `duckrow env` expands it when the server starts.
+func ExpandMCPValue(s, projectDir string) string {
+	s = strings.ReplaceAll(s, ProjectDirPlaceholder, projectDir)
+	if s == "~" || strings.HasPrefix(s, "~/") || strings.HasPrefix(s, "~"+string(filepath.Separator)) {
+		if home, err := os.UserHomeDir(); err == nil {
+			s = home + s[1:]
+		}
+	}
+	return s
+}
+
 // EnvResolver resolves environment variable values for MCP servers.
 // It follows the precedence: process env > project .env.duckrow > global .env.duckrow.
 type EnvResolver struct {
@@ -92,9 +112,13 @@ func parseEnvFile(path string) map[string]string {
 		return nil
 	}
 	defer func() { _ = f.Close() }()
+	return parseEnv(f)
+}
 
+// parseEnv parses .env content in the format described on parseEnvFile.
+func parseEnv(r io.Reader) map[string]string {
 	env := make(map[string]string)
-	scanner := bufio.NewScanner(f)
+	scanner := bufio.NewScanner(r)
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -256,15 +280,21 @@ func WriteEnvVar(dir, name, value string) error {
 // EnsureGitignore adds .env.duckrow to the project's .gitignore if not already present.
 // Creates the .gitignore file if it does not exist.
 func EnsureGitignore(projectDir string) error {
+	return ensureGitignoreEntry(projectDir, envFileName)
+}
+
+// ensureGitignoreEntry adds a line to the project's .gitignore if not already
+// present. Creates the .gitignore file if it does not exist.
+func ensureGitignoreEntry(projectDir, entry string) error {
 	gitignorePath := filepath.Join(projectDir, ".gitignore")
 
-	// Check if .gitignore exists and already contains .env.duckrow.
+	// Check if .gitignore exists and already contains the entry.
 	data, err := os.ReadFile(gitignorePath)
 	if err == nil {
 		// File exists — check if it already contains the entry.
 		lines := strings.Split(string(data), "\n")
 		for _, line := range lines {
-			if strings.TrimSpace(line) == envFileName {
+			if strings.TrimSpace(line) == entry {
 				return nil // Already present.
 			}
 		}
@@ -273,7 +303,7 @@ func EnsureGitignore(projectDir string) error {
 		if !strings.HasSuffix(content, "\n") {
 			content += "\n"
 		}
-		content += envFileName + "\n"
+		content += entry + "\n"
 		return os.WriteFile(gitignorePath, []byte(content), 0o644)
 	}
 
@@ -281,6 +311,6 @@ func EnsureGitignore(projectDir string) error {
 		return fmt.Errorf("reading .gitignore: %w", err)
 	}
 
-	// Create new .gitignore with just the env file entry.
-	return os.WriteFile(gitignorePath, []byte(envFileName+"\n"), 0o644)
+	// Create new .gitignore with just this entry.
+	return os.WriteFile(gitignorePath, []byte(entry+"\n"), 0o644)
 }
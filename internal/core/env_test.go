@@ -119,6 +119,33 @@ func TestParseEnvFile_NoEqualsSign(t *testing.T) {
 	}
 }
 
+// ---------------------------------------------------------------------------
+// ExpandMCPValue tests
+// ---------------------------------------------------------------------------
+
+func TestExpandMCPValue(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+
+	tests := []struct {
+		in, want string
+	}{
+		{"{{projectDir}}/scripts/server.py", "/work/app/scripts/server.py"},
+		{"--root={{projectDir}}", "--root=/work/app"},
+		{"~/tools/bin", home + "/tools/bin"},
+		{"~", home},
+		{"~user/bin", "~user/bin"},
+		{"a~/b", "a~/b"},
+		{"{{other}}", "{{other}}"},
+	}
+	for _, tt := range tests {
+		if got := ExpandMCPValue(tt.in, "/work/app"); got != tt.want {
+			t.Errorf("ExpandMCPValue(%q) = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
 // ---------------------------------------------------------------------------
 // EnvResolver tests
 // ---------------------------------------------------------------------------
@@ -0,0 +1,153 @@
+package core
+
+import (
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// ExcludeRules are a project's exclude rules: registry entries it never
+// wants suggested by pickers or installed by recommended, pattern, or tag
+// installs. Each rule is [kind:]pattern, where pattern is a glob (path.Match
+// syntax) matched against entry names, e.g. "skill:legacy-lint" or "old-*".
+// A rule without a kind applies to every kind. Installing an excluded entry
+// by its exact name still works.
+type ExcludeRules []string
+
+// ValidateExcludeRule reports whether rule is a well-formed exclude rule.
+func ValidateExcludeRule(rule string) error {
+	kind, pattern := splitExcludeRule(rule)
+	if kind != "" {
+		if _, ok := asset.Get(kind); !ok {
+			return fmt.Errorf("exclude rule %q: unknown kind %q (want skill, mcp, agent, command, or rule)", rule, kind)
+		}
+	}
+	if pattern == "" {
+		return fmt.Errorf("exclude rule %q: missing name or pattern", rule)
+	}
+	if _, err := path.Match(pattern, ""); err != nil {
+		return fmt.Errorf("exclude rule %q: %w", rule, err)
+	}
+	return nil
+}
+
+// splitExcludeRule splits a rule into its kind, "" if none, and pattern.
+func splitExcludeRule(rule string) (asset.Kind, string) {
+	if prefix, pattern, ok := strings.Cut(rule, ":"); ok {
+		return asset.Kind(prefix), pattern
+	}
+	return "", rule
+}
+
+// Match reports whether an entry of the given kind and name is excluded.
+func (r ExcludeRules) Match(kind asset.Kind, name string) bool {
+	for _, rule := range r {
+		k, pattern := splitExcludeRule(rule)
+		if k != "" && k != kind {
+			continue
+		}
+		if ok, _ := path.Match(pattern, name); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// Filter returns the registry entries not excluded by r.
+func (r ExcludeRules) Filter(assets []RegistryAssetInfo) []RegistryAssetInfo {
+	if len(r) == 0 {
+		return assets
+	}
+	var kept []RegistryAssetInfo
+	for _, info := range assets {
+		if !r.Match(info.Kind, info.Entry.Name) {
+			kept = append(kept, info)
+		}
+	}
+	return kept
+}
+
+// ProjectExcludes returns the exclude rules of the project in dir: those of
+// the team lock and the personal local lock together.
+func ProjectExcludes(dir string) (ExcludeRules, error) {
+	lf, err := ReadLayeredLockFile(dir)
+	if err != nil || lf == nil {
+		return nil, err
+	}
+	return ExcludeRules(lf.Exclude), nil
+}
+
+// AddExcludes adds rules to the team lock of dir, or to the personal local
+// lock if local is set, and returns those that were not there already.
+func AddExcludes(dir string, rules []string, local bool) ([]string, error) {
+	for _, rule := range rules {
+		if err := ValidateExcludeRule(rule); err != nil {
+			return nil, err
+		}
+	}
+	lf, err := readExcludeLayer(dir, local)
+	if err != nil {
+		return nil, err
+	}
+	var added []string
+	for _, rule := range rules {
+		if !slices.Contains(lf.Exclude, rule) {
+			lf.Exclude = append(lf.Exclude, rule)
+			added = append(added, rule)
+		}
+	}
+	if len(added) == 0 {
+		return nil, nil
+	}
+	return added, writeExcludeLayer(dir, lf, local)
+}
+
+// RemoveExcludes removes rules from the team lock of dir, or from the
+// personal local lock if local is set. It fails, changing nothing, if one
+// of the rules is not there.
+func RemoveExcludes(dir string, rules []string, local bool) error {
+	lf, err := readExcludeLayer(dir, local)
+	if err != nil {
+		return err
+	}
+	for _, rule := range rules {
+		i := slices.Index(lf.Exclude, rule)
+		if i < 0 {
+			return fmt.Errorf("%q is not excluded in %s", rule, excludeLayerName(local))
+		}
+		lf.Exclude = slices.Delete(lf.Exclude, i, i+1)
+	}
+	return writeExcludeLayer(dir, lf, local)
+}
+
+func readExcludeLayer(dir string, local bool) (*LockFile, error) {
+	read := ReadLockFile
+	if local {
+		read = ReadLocalLockFile
+	}
+	lf, err := read(dir)
+	if err != nil {
+		return nil, err
+	}
+	if lf == nil {
+		lf = &LockFile{LockVersion: currentLockVersion}
+	}
+	return lf, nil
+}
+
+func writeExcludeLayer(dir string, lf *LockFile, local bool) error {
+	if local {
+		return WriteLocalLockFile(dir, lf)
+	}
+	return WriteLockFile(dir, lf)
+}
+
+func excludeLayerName(local bool) string {
+	if local {
+		return projectDuckrowDir + "/" + localLockFileName
+	}
+	return lockFileName
+}
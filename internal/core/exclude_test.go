@@ -0,0 +1,92 @@
+package core
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestExcludeRules_Match(t *testing.T) {
+	rules := ExcludeRules{"skill:legacy-lint", "old-*"}
+	tests := []struct {
+		kind asset.Kind
+		name string
+		want bool
+	}{
+		{asset.KindSkill, "legacy-lint", true},
+		{asset.KindMCP, "legacy-lint", false},
+		{asset.KindSkill, "old-review", true},
+		{asset.KindAgent, "old-helper", true},
+		{asset.KindSkill, "go-review", false},
+	}
+	for _, tt := range tests {
+		if got := rules.Match(tt.kind, tt.name); got != tt.want {
+			t.Errorf("Match(%s, %s) = %v, want %v", tt.kind, tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestValidateExcludeRule(t *testing.T) {
+	for rule, wantErr := range map[string]string{
+		"legacy-lint":    "",
+		"mcp:old-*":      "",
+		"plugin:foo":     `unknown kind "plugin"`,
+		"skill:":         "missing name or pattern",
+		"skill:go-[":     "syntax error in pattern",
+		"agent:reviewer": "",
+	} {
+		err := ValidateExcludeRule(rule)
+		if wantErr == "" {
+			if err != nil {
+				t.Errorf("ValidateExcludeRule(%q) = %v", rule, err)
+			}
+		} else if err == nil || !strings.Contains(err.Error(), wantErr) {
+			t.Errorf("ValidateExcludeRule(%q) = %v, want %q", rule, err, wantErr)
+		}
+	}
+}
+
+func TestProjectExcludes_Layers(t *testing.T) {
+	dir := t.TempDir()
+	if _, err := AddExcludes(dir, []string{"skill:legacy-lint", "old-*"}, false); err != nil {
+		t.Fatal(err)
+	}
+	added, err := AddExcludes(dir, []string{"old-*", "mcp:scratch"}, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"old-*", "mcp:scratch"}; !reflect.DeepEqual(added, want) {
+		t.Errorf("added = %v, want %v", added, want)
+	}
+
+	rules, err := ProjectExcludes(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (ExcludeRules{"skill:legacy-lint", "old-*", "mcp:scratch"}); !reflect.DeepEqual(rules, want) {
+		t.Errorf("ProjectExcludes() = %v, want %v", rules, want)
+	}
+
+	if err := RemoveExcludes(dir, []string{"mcp:scratch", "skill:legacy-lint"}, true); err == nil {
+		t.Error("RemoveExcludes() of a team rule from the local lock succeeded")
+	}
+	if err := RemoveExcludes(dir, []string{"skill:legacy-lint"}, false); err != nil {
+		t.Fatal(err)
+	}
+	lf, err := ReadLockFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"old-*"}; !reflect.DeepEqual(lf.Exclude, want) {
+		t.Errorf("team Exclude = %v, want %v", lf.Exclude, want)
+	}
+	local, err := ReadLocalLockFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"old-*", "mcp:scratch"}; !reflect.DeepEqual(local.Exclude, want) {
+		t.Errorf("local Exclude = %v, want %v (a failed remove changes nothing)", local.Exclude, want)
+	}
+}
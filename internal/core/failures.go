@@ -0,0 +1,133 @@
+package core
+
+import (
+	"errors"
+	"slices"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// Failure classes for problems that are not errors from an install: a lock
+// entry that can't be acted on, or a registry that isn't configured.
+// ErrorClass names the rest.
+const (
+	FailureInvalidSource = "invalid-source"
+	FailureNoRegistry    = "registry-not-configured"
+	FailureNoLockEntry   = "lock-entry-missing"
+	FailureCheck         = "check-failed"
+)
+
+// FailedItem is an asset a bulk operation (sync, update --all) failed on,
+// with why, as printed by --json and remembered for --retry-failed.
+type FailedItem struct {
+	Kind  asset.Kind `json:"kind"`
+	Name  string     `json:"name,omitempty"` // empty when the whole kind failed
+	Class string     `json:"class"`
+	Error string     `json:"error"`
+}
+
+// NewFailedItem describes the failure err of the named asset, classed by
+// ErrorClass unless class is given.
+func NewFailedItem(kind asset.Kind, name, class string, err error) FailedItem {
+	if class == "" {
+		class = ErrorClass(err)
+	}
+	return FailedItem{Kind: kind, Name: name, Class: class, Error: err.Error()}
+}
+
+// ErrorClass returns a stable name for the kind of problem err is, for
+// scripts to tell a failure worth retrying, e.g. "network" or "timeout",
+// from one that needs a change first, e.g. "modified" or "auth". Clone
+// failures are classed by CloneErrorKind.Code; anything unrecognized is
+// "error".
+func ErrorClass(err error) string {
+	if ce, ok := IsCloneError(err); ok {
+		return ce.Kind.Code()
+	}
+	var (
+		conflictErr *ConflictError
+		modifiedErr *ModifiedSkillError
+		caseErr     *CaseCollisionError
+		nameErr     *asset.NameError
+	)
+	switch {
+	case errors.Is(err, ErrOffline):
+		return "offline"
+	case errors.Is(err, ErrHostNotAllowed):
+		return "host-not-allowed"
+	case errors.As(err, &conflictErr):
+		return "conflict"
+	case errors.As(err, &modifiedErr):
+		return "modified"
+	case errors.As(err, &caseErr):
+		return "case-collision"
+	case errors.As(err, &nameErr):
+		return "invalid-name"
+	}
+	return "error"
+}
+
+// WithFailed returns a copy of lf with only the assets named in items.
+func (lf *LockFile) WithFailed(items []FailedItem) *LockFile {
+	out := *lf
+	out.Assets = nil
+	for _, a := range lf.Assets {
+		if slices.ContainsFunc(items, func(f FailedItem) bool { return f.Kind == a.Kind && f.Name == a.Name }) {
+			out.Assets = append(out.Assets, a)
+		}
+	}
+	out.populateLegacyFields()
+	return &out
+}
+
+// Failures returns the items the last run of op ("sync" or "update") in
+// folder failed on, to retry.
+func (cm *ConfigManager) Failures(folder, op string) []FailedItem {
+	st, err := cm.LoadState()
+	if err != nil {
+		return nil
+	}
+	return st.Failures[folderKey(folder)][op]
+}
+
+// SaveFailures records the items a run of op in folder failed on. Earlier
+// failures of the items the run tried are cleared first, so a run over some
+// of them, e.g. one kind's, keeps the failures of the rest. Failures of a
+// whole kind, without a name, are not recorded: there is nothing to retry.
+func (cm *ConfigManager) SaveFailures(folder, op string, tried func(kind asset.Kind, name string) bool, failed []FailedItem) error {
+	st, err := cm.LoadState()
+	if err != nil {
+		return err
+	}
+	key := folderKey(folder)
+	var items []FailedItem
+	for _, f := range st.Failures[key][op] {
+		if !tried(f.Kind, f.Name) {
+			items = append(items, f)
+		}
+	}
+	for _, f := range failed {
+		if f.Name != "" {
+			items = append(items, f)
+		}
+	}
+
+	if len(items) == 0 {
+		if len(st.Failures[key][op]) == 0 {
+			return nil
+		}
+		delete(st.Failures[key], op)
+		if len(st.Failures[key]) == 0 {
+			delete(st.Failures, key)
+		}
+		return cm.SaveState(st)
+	}
+	if st.Failures == nil {
+		st.Failures = make(map[string]map[string][]FailedItem)
+	}
+	if st.Failures[key] == nil {
+		st.Failures[key] = make(map[string][]FailedItem)
+	}
+	st.Failures[key][op] = items
+	return cm.SaveState(st)
+}
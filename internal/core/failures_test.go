@@ -0,0 +1,99 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestErrorClass(t *testing.T) {
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&CloneError{Kind: CloneErrNetwork, URL: "https://github.com/o/r.git"}, "network"},
+		{fmt.Errorf("installing: %w", &CloneError{Kind: CloneErrAuth}), "auth"},
+		{fmt.Errorf("checking: %w", ErrOffline), "offline"},
+		{&ConflictError{}, "conflict"},
+		{fmt.Errorf("updating: %w", &ModifiedSkillError{}), "modified"},
+		{errors.New("boom"), "error"},
+	}
+	for _, tt := range tests {
+		if got := ErrorClass(tt.err); got != tt.want {
+			t.Errorf("ErrorClass(%v) = %q, want %q", tt.err, got, tt.want)
+		}
+	}
+}
+
+func TestConfigManager_SaveFailures(t *testing.T) {
+	cm := NewConfigManagerWithDir(t.TempDir())
+	project := t.TempDir()
+	all := func(asset.Kind, string) bool { return true }
+
+	if got := cm.Failures(project, "sync"); got != nil {
+		t.Fatalf("Failures() before save = %v, want nil", got)
+	}
+
+	failed := []FailedItem{
+		{Kind: asset.KindSkill, Name: "a", Class: "network", Error: "unreachable"},
+		{Kind: asset.KindMCP, Name: "db", Class: "registry-not-configured", Error: "no registry"},
+		{Kind: asset.KindAgent, Class: "error", Error: "the whole kind failed"},
+	}
+	if err := cm.SaveFailures(project, "sync", all, failed); err != nil {
+		t.Fatalf("SaveFailures() error: %v", err)
+	}
+	// Failures of a whole kind have nothing to retry and are dropped.
+	if got := cm.Failures(project, "sync"); !reflect.DeepEqual(got, failed[:2]) {
+		t.Errorf("Failures(sync) = %v, want %v", got, failed[:2])
+	}
+	if got := cm.Failures(project, "update"); got != nil {
+		t.Errorf("Failures(update) = %v, want nil", got)
+	}
+
+	// A run over skills only keeps the MCP's failure.
+	skills := func(kind asset.Kind, _ string) bool { return kind == asset.KindSkill }
+	if err := cm.SaveFailures(project, "sync", skills, nil); err != nil {
+		t.Fatalf("SaveFailures() error: %v", err)
+	}
+	if got := cm.Failures(project, "sync"); !reflect.DeepEqual(got, failed[1:2]) {
+		t.Errorf("Failures(sync) after skills run = %v, want %v", got, failed[1:2])
+	}
+
+	// A clean run clears what is left.
+	if err := cm.SaveFailures(project, "sync", all, nil); err != nil {
+		t.Fatalf("SaveFailures() error: %v", err)
+	}
+	if got := cm.Failures(project, "sync"); got != nil {
+		t.Errorf("Failures(sync) after clean run = %v, want nil", got)
+	}
+	st, err := cm.LoadState()
+	if err != nil {
+		t.Fatalf("LoadState() error: %v", err)
+	}
+	if len(st.Failures) != 0 {
+		t.Errorf("state still has failures: %v", st.Failures)
+	}
+}
+
+func TestLockFile_WithFailed(t *testing.T) {
+	lf := &LockFile{LockVersion: 3, Assets: []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "a"},
+		{Kind: asset.KindSkill, Name: "b"},
+		{Kind: asset.KindMCP, Name: "a"},
+	}}
+	got := lf.WithFailed([]FailedItem{
+		{Kind: asset.KindSkill, Name: "b"},
+		{Kind: asset.KindMCP, Name: "a"},
+		{Kind: asset.KindAgent, Name: "gone"},
+	})
+	want := []asset.LockedAsset{lf.Assets[1], lf.Assets[2]}
+	if !reflect.DeepEqual(got.Assets, want) {
+		t.Errorf("WithFailed().Assets = %v, want %v", got.Assets, want)
+	}
+	if len(lf.Assets) != 3 {
+		t.Errorf("WithFailed() changed the lock file: %v", lf.Assets)
+	}
+}
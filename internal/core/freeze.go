@@ -0,0 +1,290 @@
+package core
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// digestKey is the lock data key holding the content digest of a skill or
+// agent, as recorded by FreezeLock.
+const digestKey = "digest"
+
+// LockedDigest returns the content digest recorded in a lock entry, or "" if
+// the entry was never frozen.
+func LockedDigest(locked asset.LockedAsset) string {
+	if locked.Data == nil {
+		return ""
+	}
+	d, _ := locked.Data[digestKey].(string)
+	return d
+}
+
+// FreezeOptions configures FreezeLock and VerifyFrozen.
+type FreezeOptions struct {
+	CloneURLOverrides map[string]string
+	Mirrors           RegistryMirrors
+	IgnorePatterns    []string // global ignore patterns, as used by sync
+
+	// MCPConfigHash returns the config hash the configured registries give
+	// an MCP entry today. When nil, MCP entries are not resolved.
+	MCPConfigHash func(locked asset.LockedAsset) (string, error)
+}
+
+// FrozenEntry describes the values FreezeLock added to one lock entry.
+// Fields that were already recorded are left empty.
+type FrozenEntry struct {
+	Kind       asset.Kind
+	Name       string
+	Commit     string
+	Digest     string
+	ConfigHash string
+}
+
+// FreezeLock pins every entry of lf to concrete content. Skills and agents
+// without a commit are resolved to the commit their ref (or the default
+// branch) points to, and each gets a digest of the files sync installs for
+// it. MCPs without a config hash get the one from the registries. Values
+// already in the lock are kept; use VerifyFrozen to check them.
+//
+// lf is updated in place. Entries that cannot be resolved are returned as
+// issues and left unchanged.
+func (o *Orchestrator) FreezeLock(lf *LockFile, opts FreezeOptions) ([]FrozenEntry, []LockIssue) {
+	var frozen []FrozenEntry
+	var issues []LockIssue
+	for i, locked := range lf.Assets {
+		entry := FrozenEntry{Kind: locked.Kind, Name: locked.Name}
+		updated := locked
+
+		switch locked.Kind {
+		case asset.KindSkill, asset.KindAgent, asset.KindCommand, asset.KindRule:
+			if LockedDigest(locked) != "" && locked.Commit != "" {
+				continue
+			}
+			if updated.Commit == "" {
+				commit, err := resolveLockedCommit(locked, opts)
+				if err != nil {
+					issues = append(issues, LockIssue{locked.Kind, locked.Name, fmt.Sprintf("cannot resolve: %v", err)})
+					continue
+				}
+				updated.Commit = commit
+				entry.Commit = commit
+			}
+			digest, err := o.lockedContentDigest(updated, opts)
+			if err != nil {
+				issues = append(issues, LockIssue{locked.Kind, locked.Name, fmt.Sprintf("cannot resolve: %v", err)})
+				continue
+			}
+			updated.Data = withLockData(locked.Data, digestKey, digest)
+			entry.Digest = digest
+		case asset.KindMCP:
+			if hash, _ := locked.Data["configHash"].(string); hash != "" || opts.MCPConfigHash == nil {
+				continue
+			}
+			hash, err := opts.MCPConfigHash(locked)
+			if err != nil {
+				issues = append(issues, LockIssue{locked.Kind, locked.Name, fmt.Sprintf("cannot resolve: %v", err)})
+				continue
+			}
+			updated.Data = withLockData(locked.Data, "configHash", hash)
+			entry.ConfigHash = hash
+		default:
+			continue
+		}
+
+		lf.Assets[i] = updated
+		frozen = append(frozen, entry)
+	}
+	return frozen, issues
+}
+
+// VerifyFrozen checks that every entry of lf is frozen and still resolves to
+// what was recorded: skills and agents must be pinned, have a digest, and
+// produce the same content at their commit; MCPs must have a config hash
+// that matches the registries. It fetches every pinned source, so it needs
+// network access.
+func (o *Orchestrator) VerifyFrozen(lf *LockFile, opts FreezeOptions) []LockIssue {
+	var issues []LockIssue
+	for _, locked := range lf.Assets {
+		switch locked.Kind {
+		case asset.KindSkill, asset.KindAgent, asset.KindCommand, asset.KindRule:
+			if locked.Commit == "" {
+				issues = append(issues, LockIssue{locked.Kind, locked.Name, "not pinned to a commit"})
+				continue
+			}
+			recorded := LockedDigest(locked)
+			if recorded == "" {
+				issues = append(issues, LockIssue{locked.Kind, locked.Name, "no digest recorded"})
+				continue
+			}
+			digest, err := o.lockedContentDigest(locked, opts)
+			if err != nil {
+				issues = append(issues, LockIssue{locked.Kind, locked.Name, fmt.Sprintf("cannot resolve: %v", err)})
+				continue
+			}
+			if digest != recorded {
+				issues = append(issues, LockIssue{locked.Kind, locked.Name,
+					fmt.Sprintf("content at %s does not match the recorded digest (got %s)", TruncateCommit(locked.Commit), digest)})
+			}
+		case asset.KindMCP:
+			recorded, _ := locked.Data["configHash"].(string)
+			if recorded == "" {
+				issues = append(issues, LockIssue{locked.Kind, locked.Name, "no config hash recorded"})
+				continue
+			}
+			if opts.MCPConfigHash == nil {
+				continue
+			}
+			hash, err := opts.MCPConfigHash(locked)
+			if err != nil {
+				issues = append(issues, LockIssue{locked.Kind, locked.Name, fmt.Sprintf("cannot resolve: %v", err)})
+				continue
+			}
+			if hash != recorded {
+				issues = append(issues, LockIssue{locked.Kind, locked.Name, "registry config differs from the recorded config hash"})
+			}
+		}
+	}
+	return issues
+}
+
+// lockedSource builds the clone source for a lock entry, fetched through
+// any clone URL override or the mirror of the registry entry it came from.
+func lockedSource(locked asset.LockedAsset, overrides map[string]string, mirrors RegistryMirrors) (*ParsedSource, error) {
+	host, owner, repo, subPath, err := ParseLockSource(locked.Source)
+	if err != nil {
+		return nil, err
+	}
+	source := &ParsedSource{
+		Type:     SourceTypeGit,
+		Host:     host,
+		Owner:    owner,
+		Repo:     repo,
+		CloneURL: fmt.Sprintf("https://%s/%s/%s.git", host, owner, repo),
+		SubPath:  subPath,
+		Ref:      locked.Ref,
+	}
+	source.ApplyMirror(overrides, mirrors.Locked(locked))
+	return source, nil
+}
+
+// resolveLockedCommit finds the commit an unpinned entry would be installed
+// from: the last commit touching its path on its ref, or the ref's head when
+// the path isn't tracked as given (e.g. an agent file without extension).
+func resolveLockedCommit(locked asset.LockedAsset, opts FreezeOptions) (string, error) {
+	source, err := lockedSource(locked, opts.CloneURLOverrides, opts.Mirrors)
+	if err != nil {
+		return "", err
+	}
+	tmpDir, err := cloneRepo(source.CloneURL, source.Ref, false)
+	if err != nil {
+		return "", err
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	commit, err := GetSkillCommit(tmpDir, source.SubPath)
+	if err != nil {
+		commit, err = GetSkillCommit(tmpDir, "")
+	}
+	return commit, err
+}
+
+// lockedContentDigest computes the digest of what sync installs for a pinned
+// skill or agent entry. Skills are materialized the way sync does it (with
+// ignore rules and partial updates applied) into a scratch project, so the
+// digest covers exactly the files that end up in .agents/skills.
+func (o *Orchestrator) lockedContentDigest(locked asset.LockedAsset, opts FreezeOptions) (string, error) {
+	source, err := lockedSource(locked, opts.CloneURLOverrides, opts.Mirrors)
+	if err != nil {
+		return "", err
+	}
+
+	if asset.IsSystemFile(locked.Kind) {
+		tmpDir, err := cloneSource(source, locked.Commit)
+		if err != nil {
+			return "", fmt.Errorf("cloning: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(tmpDir) }()
+
+		handler, _ := asset.Get(locked.Kind)
+		discovered, err := handler.Discover(tmpDir, asset.DiscoverOptions{
+			SubPath:         source.SubPath,
+			IncludeInternal: true,
+			NameFilter:      LockedUpstreamName(locked),
+		})
+		if err != nil {
+			return "", fmt.Errorf("discovering %s: %w", locked.Kind, err)
+		}
+		if len(discovered) != 1 {
+			return "", fmt.Errorf("%s %q not found at %s", locked.Kind, LockedUpstreamName(locked), TruncateCommit(locked.Commit))
+		}
+		return contentDigest(discovered[0].PreparedPath)
+	}
+
+	dir, cleanup, err := o.materializeLockedSkill(source, locked, opts.IgnorePatterns)
+	if err != nil {
+		return "", err
+	}
+	defer cleanup()
+	return contentDigest(dir)
+}
+
+// contentDigest returns "sha256:<hex>" over a file's contents, or over the
+// sorted relative paths and contents of every file in a directory.
+func contentDigest(path string) (string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return "", err
+	}
+	h := sha256.New()
+	if !info.IsDir() {
+		if err := hashFile(h, path); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+	}
+
+	files, err := treeFiles(path)
+	if err != nil {
+		return "", err
+	}
+	rels := make([]string, 0, len(files))
+	for rel := range files {
+		rels = append(rels, filepath.ToSlash(rel))
+	}
+	sort.Strings(rels)
+	for _, rel := range rels {
+		fh := sha256.New()
+		if err := hashFile(fh, filepath.Join(path, filepath.FromSlash(rel))); err != nil {
+			return "", err
+		}
+		fmt.Fprintf(h, "%s\x00%x\n", rel, fh.Sum(nil))
+	}
+	return fmt.Sprintf("sha256:%x", h.Sum(nil)), nil
+}
+
+// hashFile writes the contents of the file at path to w.
+func hashFile(w io.Writer, path string) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = f.Close() }()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// withLockData returns a copy of data with key set to value.
+func withLockData(data map[string]any, key string, value any) map[string]any {
+	out := make(map[string]any, len(data)+1)
+	for k, v := range data {
+		out[k] = v
+	}
+	out[key] = value
+	return out
+}
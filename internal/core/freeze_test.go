@@ -0,0 +1,115 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestContentDigest(t *testing.T) {
+	dir := t.TempDir()
+	write := func(rel, content string) {
+		t.Helper()
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("SKILL.md", "# lint")
+	write("docs/guide.md", "guide")
+
+	first, err := contentDigest(dir)
+	if err != nil {
+		t.Fatalf("contentDigest() error = %v", err)
+	}
+	if !strings.HasPrefix(first, "sha256:") {
+		t.Errorf("contentDigest() = %q, want sha256: prefix", first)
+	}
+	again, _ := contentDigest(dir)
+	if again != first {
+		t.Errorf("contentDigest() not stable: %q then %q", first, again)
+	}
+
+	write("docs/guide.md", "changed")
+	if changed, _ := contentDigest(dir); changed == first {
+		t.Error("contentDigest() unchanged after editing a file")
+	}
+
+	// Renaming a file changes the digest even if contents don't.
+	write("docs/guide.md", "guide")
+	if err := os.Rename(filepath.Join(dir, "docs", "guide.md"), filepath.Join(dir, "docs", "other.md")); err != nil {
+		t.Fatal(err)
+	}
+	if renamed, _ := contentDigest(dir); renamed == first {
+		t.Error("contentDigest() unchanged after renaming a file")
+	}
+
+	file, err := contentDigest(filepath.Join(dir, "SKILL.md"))
+	if err != nil || file == first {
+		t.Errorf("contentDigest(file) = %q, %v", file, err)
+	}
+}
+
+func TestFreezeLock_MCP(t *testing.T) {
+	lf := &LockFile{LockVersion: 3, Assets: []asset.LockedAsset{
+		{Kind: asset.KindMCP, Name: "db", Data: map[string]any{"registry": "team"}},
+		{Kind: asset.KindMCP, Name: "kept", Data: map[string]any{"configHash": "sha256:old"}},
+		{Kind: asset.KindMCP, Name: "gone"},
+	}}
+	opts := FreezeOptions{MCPConfigHash: func(locked asset.LockedAsset) (string, error) {
+		if locked.Name == "gone" {
+			return "", errors.New("not found in registries")
+		}
+		return "sha256:" + locked.Name, nil
+	}}
+
+	frozen, issues := NewOrchestrator().FreezeLock(lf, opts)
+	want := []FrozenEntry{{Kind: asset.KindMCP, Name: "db", ConfigHash: "sha256:db"}}
+	if !reflect.DeepEqual(frozen, want) {
+		t.Errorf("frozen = %+v, want %+v", frozen, want)
+	}
+	if len(issues) != 1 || issues[0].Name != "gone" {
+		t.Errorf("issues = %v, want one for gone", issues)
+	}
+	if got := lf.Assets[0].Data; got["configHash"] != "sha256:db" || got["registry"] != "team" {
+		t.Errorf("db data = %v", got)
+	}
+	if got := lf.Assets[1].Data["configHash"]; got != "sha256:old" {
+		t.Errorf("kept configHash = %v, want unchanged", got)
+	}
+}
+
+func TestVerifyFrozen(t *testing.T) {
+	lf := &LockFile{LockVersion: 3, Assets: []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "floating", Source: "github.com/acme/skills/floating"},
+		{Kind: asset.KindAgent, Name: "nodigest", Source: "github.com/acme/agents/nodigest", Commit: "abc1234"},
+		{Kind: asset.KindMCP, Name: "nohash"},
+		{Kind: asset.KindMCP, Name: "changed", Data: map[string]any{"configHash": "sha256:old"}},
+		{Kind: asset.KindMCP, Name: "same", Data: map[string]any{"configHash": "sha256:same"}},
+	}}
+	opts := FreezeOptions{MCPConfigHash: func(locked asset.LockedAsset) (string, error) {
+		return "sha256:" + locked.Name, nil
+	}}
+
+	var got []string
+	for _, issue := range NewOrchestrator().VerifyFrozen(lf, opts) {
+		got = append(got, issue.String())
+	}
+	want := []string{
+		`skill "floating": not pinned to a commit`,
+		`agent "nodigest": no digest recorded`,
+		`mcp "nohash": no config hash recorded`,
+		`mcp "changed": registry config differs from the recorded config hash`,
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("VerifyFrozen() =\n%s\nwant\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
@@ -0,0 +1,276 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// githubAPIBaseURL is the GitHub REST API endpoint; tests point it at a
+// local server.
+var githubAPIBaseURL = "https://api.github.com"
+
+// githubTokenEnvVars are read, in order, for the token used with the GitHub
+// API. GH_TOKEN is what the gh CLI uses.
+var githubTokenEnvVars = []string{"GITHUB_TOKEN", "GH_TOKEN"}
+
+// githubAPICacheFile holds the ETags of earlier GitHub API responses and the
+// commits they resolved to, so repeated checks are answered with 304 Not
+// Modified, which GitHub doesn't count against the rate limit.
+const githubAPICacheFile = "github-api-cache.json"
+
+// shaPattern matches a full commit SHA.
+var shaPattern = regexp.MustCompile(`^[0-9a-f]{40}$`)
+
+// GitHubAPIConfig controls commit resolution through the GitHub API.
+type GitHubAPIConfig struct {
+	// Enabled resolves the commits of github.com sources through the API
+	// instead of git ls-remote and clones.
+	Enabled bool
+
+	// CacheDir is where the ETag cache is kept. Empty disables it.
+	CacheDir string
+}
+
+// UseGitHubAPI reports whether commits should be resolved through the
+// GitHub API: as set by githubAPI, or, when that is unset, whenever a token
+// is configured.
+func (s Settings) UseGitHubAPI() bool {
+	if s.GitHubAPI != nil {
+		return *s.GitHubAPI
+	}
+	return githubToken() != ""
+}
+
+// currentGitHubAPIValue is process-wide like offline mode: the CLI sets it
+// once from the settings.
+var currentGitHubAPIValue atomic.Value
+
+// SetGitHubAPI sets how commits are resolved through the GitHub API.
+func SetGitHubAPI(c GitHubAPIConfig) {
+	currentGitHubAPIValue.Store(c)
+	githubCache.reset(c.CacheDir)
+}
+
+// CurrentGitHubAPI returns the GitHub API settings in effect.
+func CurrentGitHubAPI() GitHubAPIConfig {
+	c, _ := currentGitHubAPIValue.Load().(GitHubAPIConfig)
+	return c
+}
+
+// githubToken returns the token for the GitHub API, or "" to make
+// unauthenticated calls.
+func githubToken() string {
+	for _, name := range githubTokenEnvVars {
+		if v := os.Getenv(name); v != "" {
+			return v
+		}
+	}
+	return ""
+}
+
+// githubAPIRepo reports whether a source repository is resolved through the
+// GitHub API: the API is on and allowed by the host allowlist, and the
+// repository is on github.com without a clone URL override.
+func githubAPIRepo(cloneURL, host, owner, repo string) bool {
+	return CurrentGitHubAPI().Enabled && host == "github.com" &&
+		cloneURL == fmt.Sprintf("https://%s/%s/%s.git", host, owner, repo) &&
+		checkHost(githubAPIBaseURL) == nil
+}
+
+// resolveRef returns the commit a ref of a source repository points to,
+// like lsRemote. For repositories served by the GitHub API the API is asked
+// first; if that fails, e.g. for a private repository without a token, it
+// falls back to ls-remote.
+func resolveRef(cloneURL, host, owner, repo, ref string) (string, error) {
+	if githubAPIRepo(cloneURL, host, owner, repo) {
+		if err := checkNetwork(cloneURL); err != nil {
+			return "", err
+		}
+		if sha, err := githubCommitSHA(owner, repo, ref); err == nil {
+			return sha, nil
+		}
+	}
+	return lsRemote(cloneURL, ref)
+}
+
+// githubCommitSHA asks the GitHub commits API for the commit ref resolves
+// to. An empty ref resolves the default branch.
+func githubCommitSHA(owner, repo, ref string) (string, error) {
+	if ref == "" {
+		ref = "HEAD"
+	}
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/commits/%s", githubAPIBaseURL,
+		url.PathEscape(owner), url.PathEscape(repo), url.PathEscape(ref))
+	// The sha media type returns just the commit SHA as text.
+	return githubAPIGet(endpoint, "application/vnd.github.sha", func(body []byte) (string, error) {
+		sha := strings.TrimSpace(string(body))
+		if !shaPattern.MatchString(sha) {
+			return "", fmt.Errorf("unexpected response for %s/%s@%s", owner, repo, ref)
+		}
+		return sha, nil
+	})
+}
+
+// githubPathCommit asks the GitHub commits API for the latest commit at ref
+// that touches subPath, the per-path equivalent of GetSkillCommit on a
+// clone. An empty subPath is the whole repository.
+func githubPathCommit(owner, repo, ref, subPath string) (string, error) {
+	if subPath == "" || subPath == "." {
+		return githubCommitSHA(owner, repo, ref)
+	}
+	q := url.Values{"path": {subPath}, "per_page": {"1"}}
+	if ref != "" {
+		q.Set("sha", ref)
+	}
+	endpoint := fmt.Sprintf("%s/repos/%s/%s/commits?%s", githubAPIBaseURL,
+		url.PathEscape(owner), url.PathEscape(repo), q.Encode())
+	return githubAPIGet(endpoint, "application/vnd.github+json", func(body []byte) (string, error) {
+		var commits []struct {
+			SHA string `json:"sha"`
+		}
+		if err := json.Unmarshal(body, &commits); err != nil {
+			return "", fmt.Errorf("parsing commits of %s/%s: %w", owner, repo, err)
+		}
+		if len(commits) == 0 || !shaPattern.MatchString(commits[0].SHA) {
+			return "", fmt.Errorf("no commits found for path %q in %s/%s", subPath, owner, repo)
+		}
+		return commits[0].SHA, nil
+	})
+}
+
+// githubAPIGet calls a GitHub API endpoint and turns the response into a
+// value with parse. Responses are cached by ETag: a repeated call sends
+// If-None-Match and reuses the cached value on 304 Not Modified.
+func githubAPIGet(endpoint, accept string, parse func([]byte) (string, error)) (string, error) {
+	req, err := http.NewRequest(http.MethodGet, endpoint, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Accept", accept)
+	req.Header.Set("X-GitHub-Api-Version", "2022-11-28")
+	if token := githubToken(); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	key := accept + " " + strings.TrimPrefix(endpoint, githubAPIBaseURL)
+	cached, hasCached := githubCache.get(key)
+	if hasCached {
+		req.Header.Set("If-None-Match", cached.ETag)
+	}
+
+	netAPICalls.Add(1)
+	client := &http.Client{Timeout: CurrentTimeouts().Download}
+	resp, err := doHTTP(client, req)
+	if err != nil {
+		return "", fmt.Errorf("GitHub API: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	switch {
+	case resp.StatusCode == http.StatusNotModified && hasCached:
+		netAPINotModified.Add(1)
+		return cached.Value, nil
+	case resp.StatusCode != http.StatusOK:
+		return "", fmt.Errorf("GitHub API: %s returned %s", strings.TrimPrefix(endpoint, githubAPIBaseURL), resp.Status)
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return "", fmt.Errorf("GitHub API: %w", err)
+	}
+	value, err := parse(body)
+	if err != nil {
+		return "", fmt.Errorf("GitHub API: %w", err)
+	}
+	if etag := resp.Header.Get("ETag"); etag != "" {
+		githubCache.put(key, githubCacheEntry{ETag: etag, Value: value})
+	}
+	return value, nil
+}
+
+// githubCacheEntry is a cached GitHub API response.
+type githubCacheEntry struct {
+	ETag  string `json:"etag"`
+	Value string `json:"value"`
+}
+
+// githubETagCache is the in-memory copy of the ETag cache file. It is loaded
+// on first use and written back whenever an entry changes.
+type githubETagCache struct {
+	mu      sync.Mutex
+	dir     string
+	loaded  bool
+	entries map[string]githubCacheEntry
+}
+
+var githubCache = &githubETagCache{}
+
+func (c *githubETagCache) reset(dir string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.dir = dir
+	c.loaded = false
+	c.entries = nil
+}
+
+func (c *githubETagCache) get(key string) (githubCacheEntry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	e, ok := c.entries[key]
+	return e, ok
+}
+
+func (c *githubETagCache) put(key string, e githubCacheEntry) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.load()
+	if c.dir == "" || c.entries[key] == e {
+		return
+	}
+	c.entries[key] = e
+	_ = c.save()
+}
+
+// load reads the cache file once. A missing or unreadable file starts an
+// empty cache. Callers hold c.mu.
+func (c *githubETagCache) load() {
+	if c.loaded {
+		return
+	}
+	c.loaded = true
+	c.entries = make(map[string]githubCacheEntry)
+	if c.dir == "" {
+		return
+	}
+	data, err := os.ReadFile(filepath.Join(c.dir, githubAPICacheFile))
+	if err != nil {
+		return
+	}
+	_ = json.Unmarshal(data, &c.entries)
+}
+
+// save writes the cache file. Callers hold c.mu.
+func (c *githubETagCache) save() error {
+	data, err := json.MarshalIndent(c.entries, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return err
+	}
+	path := filepath.Join(c.dir, githubAPICacheFile)
+	tmpPath := path + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, path)
+}
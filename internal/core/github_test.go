@@ -0,0 +1,227 @@
+package core
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// fakeGitHub serves canned GitHub API responses keyed by request URI, with
+// ETags so conditional requests get 304 Not Modified.
+type fakeGitHub struct {
+	responses map[string]string
+	requests  []*http.Request
+}
+
+// newFakeGitHub starts a fake API, points the client at it, and turns the
+// API on with its ETag cache in cacheDir.
+func newFakeGitHub(t *testing.T, cacheDir string, responses map[string]string) *fakeGitHub {
+	t.Helper()
+	f := &fakeGitHub{responses: responses}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		f.requests = append(f.requests, r)
+		body, ok := f.responses[r.URL.RequestURI()]
+		if !ok {
+			http.NotFound(w, r)
+			return
+		}
+		etag := fmt.Sprintf(`"%x"`, sha256.Sum256([]byte(body)))
+		if r.Header.Get("If-None-Match") == etag {
+			w.WriteHeader(http.StatusNotModified)
+			return
+		}
+		w.Header().Set("ETag", etag)
+		_, _ = w.Write([]byte(body))
+	}))
+	t.Cleanup(srv.Close)
+
+	old := githubAPIBaseURL
+	githubAPIBaseURL = srv.URL
+	t.Cleanup(func() { githubAPIBaseURL = old })
+	SetGitHubAPI(GitHubAPIConfig{Enabled: true, CacheDir: cacheDir})
+	t.Cleanup(func() { SetGitHubAPI(GitHubAPIConfig{}) })
+	noRateLimit(t)
+	return f
+}
+
+const (
+	testSHA1 = "0123456789abcdef0123456789abcdef01234567"
+	testSHA2 = "89abcdef0123456789abcdef0123456789abcdef"
+)
+
+func TestSettings_UseGitHubAPI(t *testing.T) {
+	for _, name := range githubTokenEnvVars {
+		t.Setenv(name, "")
+	}
+	if (Settings{}).UseGitHubAPI() {
+		t.Error("UseGitHubAPI() without a token = true, want false")
+	}
+	t.Setenv("GH_TOKEN", "secret")
+	if !(Settings{}).UseGitHubAPI() {
+		t.Error("UseGitHubAPI() with a token = false, want true")
+	}
+	off := false
+	if (Settings{GitHubAPI: &off}).UseGitHubAPI() {
+		t.Error("UseGitHubAPI() turned off = true, want false")
+	}
+}
+
+func TestGitHubCommitSHA(t *testing.T) {
+	f := newFakeGitHub(t, "", map[string]string{
+		"/repos/acme/skills/commits/HEAD": testSHA1,
+		"/repos/acme/skills/commits/v1":   testSHA2,
+	})
+	t.Setenv("GITHUB_TOKEN", "secret")
+
+	before := CurrentNetworkStats()
+	for ref, want := range map[string]string{"": testSHA1, "v1": testSHA2} {
+		got, err := githubCommitSHA("acme", "skills", ref)
+		if err != nil {
+			t.Fatalf("githubCommitSHA(%q) error = %v", ref, err)
+		}
+		if got != want {
+			t.Errorf("githubCommitSHA(%q) = %q, want %q", ref, got, want)
+		}
+	}
+	if got := CurrentNetworkStats().APICalls - before.APICalls; got != 2 {
+		t.Errorf("APICalls = %d, want 2", got)
+	}
+
+	req := f.requests[0]
+	if got := req.Header.Get("Authorization"); got != "Bearer secret" {
+		t.Errorf("Authorization = %q, want the token", got)
+	}
+	if got := req.Header.Get("Accept"); got != "application/vnd.github.sha" {
+		t.Errorf("Accept = %q, want the sha media type", got)
+	}
+
+	if _, err := githubCommitSHA("acme", "missing", ""); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("githubCommitSHA(missing) error = %v, want 404", err)
+	}
+}
+
+func TestGitHubPathCommit(t *testing.T) {
+	newFakeGitHub(t, "", map[string]string{
+		"/repos/acme/skills/commits?path=skills%2Fgo&per_page=1&sha=main": `[{"sha": "` + testSHA2 + `"}]`,
+		"/repos/acme/skills/commits?path=skills%2Fgone&per_page=1":        `[]`,
+		"/repos/acme/skills/commits/HEAD":                                 testSHA1,
+	})
+
+	got, err := githubPathCommit("acme", "skills", "main", "skills/go")
+	if err != nil || got != testSHA2 {
+		t.Errorf("githubPathCommit() = %q, %v; want %q", got, err, testSHA2)
+	}
+	if got, err := githubPathCommit("acme", "skills", "", ""); err != nil || got != testSHA1 {
+		t.Errorf("githubPathCommit(root) = %q, %v; want %q", got, err, testSHA1)
+	}
+	if _, err := githubPathCommit("acme", "skills", "", "skills/gone"); err == nil || !strings.Contains(err.Error(), "no commits") {
+		t.Errorf("githubPathCommit(gone) error = %v, want no commits", err)
+	}
+}
+
+func TestGitHubAPI_ETagCache(t *testing.T) {
+	cacheDir := t.TempDir()
+	f := newFakeGitHub(t, cacheDir, map[string]string{"/repos/acme/skills/commits/HEAD": testSHA1})
+
+	before := CurrentNetworkStats()
+	for i := 0; i < 2; i++ {
+		if got, err := githubCommitSHA("acme", "skills", ""); err != nil || got != testSHA1 {
+			t.Fatalf("call %d: githubCommitSHA() = %q, %v", i, got, err)
+		}
+	}
+	if got := CurrentNetworkStats().APINotModified - before.APINotModified; got != 1 {
+		t.Errorf("APINotModified = %d, want 1", got)
+	}
+	if _, err := os.Stat(filepath.Join(cacheDir, githubAPICacheFile)); err != nil {
+		t.Errorf("cache file not written: %v", err)
+	}
+
+	// A later run loads the cache from disk.
+	SetGitHubAPI(GitHubAPIConfig{Enabled: true, CacheDir: cacheDir})
+	if got, err := githubCommitSHA("acme", "skills", ""); err != nil || got != testSHA1 {
+		t.Fatalf("githubCommitSHA() after reload = %q, %v", got, err)
+	}
+	if last := f.requests[len(f.requests)-1]; last.Header.Get("If-None-Match") == "" {
+		t.Error("reloaded cache did not send If-None-Match")
+	}
+}
+
+func TestResolveRef_UsesGitHubAPI(t *testing.T) {
+	f := newFakeGitHub(t, "", map[string]string{"/repos/acme/skills/commits/main": testSHA2})
+
+	got, err := resolveRef("https://github.com/acme/skills.git", "github.com", "acme", "skills", "main")
+	if err != nil {
+		t.Fatalf("resolveRef() error = %v", err)
+	}
+	if got != testSHA2 {
+		t.Errorf("resolveRef() = %q, want %q", got, testSHA2)
+	}
+
+	// Overridden clone URLs always go through git.
+	src := t.TempDir()
+	setupTestGitRepo(t, src)
+	calls := len(f.requests)
+	if _, err := resolveRef(src, "github.com", "acme", "skills", ""); err != nil {
+		t.Fatalf("resolveRef(override) error = %v", err)
+	}
+	if len(f.requests) != calls {
+		t.Error("resolveRef() called the API for an overridden clone URL")
+	}
+}
+
+func TestCheckForUpdatesByRepo_GitHubAPI(t *testing.T) {
+	const head = "fedcba9876543210fedcba9876543210fedcba98"
+	newFakeGitHub(t, "", map[string]string{
+		"/repos/acme/skills/commits/HEAD":                                      head,
+		"/repos/acme/skills/commits?path=skills%2Fgo&per_page=1&sha=" + head:   `[{"sha": "` + testSHA2 + `"}]`,
+		"/repos/acme/skills/commits?path=skills%2Fdocs&per_page=1&sha=" + head: `[{"sha": "` + testSHA1 + `"}]`,
+	})
+
+	lf := &LockFile{Assets: []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "go", Source: "github.com/acme/skills/skills/go", Commit: testSHA1},
+		{Kind: asset.KindSkill, Name: "docs", Source: "github.com/acme/skills/skills/docs", Commit: testSHA1},
+	}}
+	results := CheckForUpdatesByRepo(lf, asset.KindSkill, nil, nil, nil, nil)
+	if len(results) != 1 || results[0].Err != nil {
+		t.Fatalf("results = %+v, want one repo without error", results)
+	}
+	byName := make(map[string]UpdateInfo)
+	for _, u := range results[0].Updates {
+		byName[u.Name] = u
+	}
+	if u := byName["go"]; u.AvailableCommit != testSHA2 || !u.HasUpdate {
+		t.Errorf("go = %+v, want an update to %s", u, testSHA2)
+	}
+	if u := byName["docs"]; u.AvailableCommit != testSHA1 || u.HasUpdate {
+		t.Errorf("docs = %+v, want up to date", u)
+	}
+}
+
+func TestHydrate_GitHubAPI(t *testing.T) {
+	newFakeGitHub(t, "", map[string]string{
+		"/repos/acme/skills/commits?path=skills%2Fgo&per_page=1": `[{"sha": "` + testSHA2 + `"}]`,
+	})
+
+	registriesDir := t.TempDir()
+	rm := NewRegistryManager(registriesDir)
+	regRepoURL := "git@example.com:org/reg.git"
+	regDir := createTestRegistryClone(t, registriesDir, regRepoURL, RegistryManifest{
+		Name:   "org",
+		Skills: skillEntriesToRaw([]testSkillEntry{{Name: "go", Source: "github.com/acme/skills/skills/go"}}),
+	})
+
+	res := rm.Hydrate([]Registry{{Name: "org", Repo: regRepoURL}}, HydrateOptions{Force: true})
+	if len(res) != 1 || res[0].Resolved != 1 || len(res[0].Failed) != 0 {
+		t.Fatalf("Hydrate() = %+v, want 1 resolved without clones", res)
+	}
+	if got := loadCachedCommits(regDir)["github.com/acme/skills/skills/go"]; got != testSHA2 {
+		t.Errorf("cached commit = %q, want %q", got, testSHA2)
+	}
+}
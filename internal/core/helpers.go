@@ -1,6 +1,7 @@
 package core
 
 import (
+	"errors"
 	"fmt"
 	"io"
 	"io/fs"
@@ -15,30 +16,35 @@ import (
 // canonicalSkillsDir is the project-relative path where skill assets are stored.
 const canonicalSkillsDir = ".agents/skills"
 
-const (
-	cloneTimeout = 60 * time.Second
-)
-
 // excludedFiles are files/dirs excluded when copying skills.
 var excludedFiles = map[string]bool{
 	"README.md":     true,
 	"metadata.json": true,
 	".git":          true,
+	ignoreFileName:  true,
 }
 
 var sanitizeRegexp = regexp.MustCompile(`[^a-zA-Z0-9-]`)
 
-// cloneRepo clones a git repository to a temp directory.
-// When shallow is true, only the latest commit is fetched (faster but cannot
+// cloneRepo clones a git repository to a temp directory, through the clone
+// cache when one is configured (see Cache). When shallow is true, only the latest commit is fetched (faster but cannot
 // resolve per-path commits). When shallow is false, the full history is cloned
 // so that git log can accurately resolve per-path commits.
 func cloneRepo(url string, ref string, shallow bool) (string, error) {
+	if c := CurrentCache(); c.Enabled() {
+		return c.cloneFromCache(url, ref, "")
+	}
+	if err := checkNetwork(url); err != nil {
+		return "", err
+	}
+	throttle(url)
+
 	tmpDir, err := os.MkdirTemp("", "duckrow-clone-*")
 	if err != nil {
 		return "", fmt.Errorf("creating temp dir: %w", err)
 	}
 
-	args := []string{"clone"}
+	args := append(longPathGitArgs(), "clone")
 	if shallow {
 		args = append(args, "--depth", "1")
 	}
@@ -48,9 +54,9 @@ func cloneRepo(url string, ref string, shallow bool) (string, error) {
 	args = append(args, url, tmpDir)
 
 	cmd := exec.Command("git", args...)
-	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Env = gitEnv(url)
 
-	output, err := runWithTimeout(cmd, cloneTimeout)
+	output, err := runWithTimeout(cmd, CurrentTimeouts().Clone)
 	if err != nil {
 		_ = os.RemoveAll(tmpDir)
 		return "", ClassifyCloneError(url, FormatCommand(url, ref), output)
@@ -60,19 +66,29 @@ func cloneRepo(url string, ref string, shallow bool) (string, error) {
 }
 
 // cloneRepoAtCommit fetches a specific commit without full clone history.
-// Uses git init + fetch --depth 1 + checkout FETCH_HEAD.
+// Uses git init + fetch --depth 1 + checkout FETCH_HEAD, or the clone cache
+// when one is configured.
 func cloneRepoAtCommit(url string, commit string) (string, error) {
+	if c := CurrentCache(); c.Enabled() {
+		return c.cloneFromCache(url, "", commit)
+	}
+	if err := checkNetwork(url); err != nil {
+		return "", err
+	}
+	throttle(url)
+
 	tmpDir, err := os.MkdirTemp("", "duckrow-clone-*")
 	if err != nil {
 		return "", fmt.Errorf("creating temp dir: %w", err)
 	}
 
-	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	env := gitEnv(url)
+	timeout := CurrentTimeouts().Clone
 
 	// git init
 	initCmd := exec.Command("git", "init", tmpDir)
 	initCmd.Env = env
-	if output, err := runWithTimeout(initCmd, cloneTimeout); err != nil {
+	if output, err := runWithTimeout(initCmd, timeout); err != nil {
 		_ = os.RemoveAll(tmpDir)
 		return "", fmt.Errorf("git init failed: %s", output)
 	}
@@ -80,7 +96,7 @@ func cloneRepoAtCommit(url string, commit string) (string, error) {
 	// git remote add origin <url>
 	remoteCmd := exec.Command("git", "-C", tmpDir, "remote", "add", "origin", url)
 	remoteCmd.Env = env
-	if output, err := runWithTimeout(remoteCmd, cloneTimeout); err != nil {
+	if output, err := runWithTimeout(remoteCmd, timeout); err != nil {
 		_ = os.RemoveAll(tmpDir)
 		return "", fmt.Errorf("git remote add failed: %s", output)
 	}
@@ -88,15 +104,20 @@ func cloneRepoAtCommit(url string, commit string) (string, error) {
 	// git fetch --depth 1 origin <commit>
 	fetchCmd := exec.Command("git", "-C", tmpDir, "fetch", "--depth", "1", "origin", commit)
 	fetchCmd.Env = env
-	if output, err := runWithTimeout(fetchCmd, cloneTimeout); err != nil {
+	if output, err := runWithTimeout(fetchCmd, timeout); err != nil {
 		_ = os.RemoveAll(tmpDir)
+		// A repository that can't be reached fails the same way a clone
+		// does; only a reachable one can be missing the commit.
+		if kind := classifyOutput(output); kind != CloneErrUnknown && kind != CloneErrRefNotFound {
+			return "", ClassifyCloneError(url, "git fetch --depth 1 origin "+commit, output)
+		}
 		return "", fmt.Errorf("commit %s not found in remote (may have been force-pushed away): %s", commit, output)
 	}
 
 	// git checkout FETCH_HEAD
-	checkoutCmd := exec.Command("git", "-C", tmpDir, "checkout", "FETCH_HEAD")
+	checkoutCmd := exec.Command("git", append(longPathGitArgs(), "-C", tmpDir, "checkout", "FETCH_HEAD")...)
 	checkoutCmd.Env = env
-	if output, err := runWithTimeout(checkoutCmd, cloneTimeout); err != nil {
+	if output, err := runWithTimeout(checkoutCmd, timeout); err != nil {
 		_ = os.RemoveAll(tmpDir)
 		return "", fmt.Errorf("git checkout failed: %s", output)
 	}
@@ -104,7 +125,89 @@ func cloneRepoAtCommit(url string, commit string) (string, error) {
 	return tmpDir, nil
 }
 
-// runWithTimeout runs a command with a timeout.
+// lsRemote resolves the commit a remote ref points to without cloning. An
+// empty ref resolves the remote HEAD. Branches take precedence over tags, and
+// annotated tags are peeled to the commit they point at.
+func lsRemote(url string, ref string) (string, error) {
+	if err := checkNetwork(url); err != nil {
+		return "", err
+	}
+	throttle(url)
+
+	pattern := ref
+	if pattern == "" {
+		pattern = "HEAD"
+	}
+
+	cmd := exec.Command("git", "ls-remote", url, pattern)
+	cmd.Env = gitEnv(url)
+
+	output, err := runWithTimeout(cmd, CurrentTimeouts().Clone)
+	if err != nil {
+		return "", ClassifyCloneError(url, "git ls-remote "+url+" "+pattern, output)
+	}
+
+	refs := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) == 2 {
+			refs[fields[1]] = fields[0]
+		}
+	}
+
+	candidates := []string{"HEAD"}
+	if ref != "" {
+		candidates = []string{"refs/heads/" + ref, "refs/tags/" + ref + "^{}", "refs/tags/" + ref, ref}
+	}
+	for _, c := range candidates {
+		if sha, ok := refs[c]; ok {
+			return sha, nil
+		}
+	}
+	return "", fmt.Errorf("ref %q not found in %s", pattern, url)
+}
+
+// lsRemoteTags lists the tags of a remote repository without cloning it,
+// mapping each tag name to the commit it points to. Annotated tags are
+// peeled to their commit.
+func lsRemoteTags(url string) (map[string]string, error) {
+	if err := checkNetwork(url); err != nil {
+		return nil, err
+	}
+	throttle(url)
+
+	cmd := exec.Command("git", "ls-remote", "--tags", url)
+	cmd.Env = gitEnv(url)
+
+	output, err := runWithTimeout(cmd, CurrentTimeouts().Clone)
+	if err != nil {
+		return nil, ClassifyCloneError(url, "git ls-remote --tags "+url, output)
+	}
+
+	tags := make(map[string]string)
+	peeled := make(map[string]bool)
+	for _, line := range strings.Split(output, "\n") {
+		fields := strings.Fields(line)
+		if len(fields) != 2 {
+			continue
+		}
+		name, ok := strings.CutPrefix(fields[1], "refs/tags/")
+		if !ok {
+			continue
+		}
+		if tag, isPeeled := strings.CutSuffix(name, "^{}"); isPeeled {
+			tags[tag] = fields[0]
+			peeled[tag] = true
+		} else if !peeled[name] {
+			tags[name] = fields[0]
+		}
+	}
+	return tags, nil
+}
+
+// runWithTimeout runs a command with a timeout and records it in the log,
+// with its output when it fails. On timeout the returned output is the
+// timeout message, so it can be classified like git output.
 func runWithTimeout(cmd *exec.Cmd, timeout time.Duration) (string, error) {
 	done := make(chan struct{})
 	var output []byte
@@ -115,19 +218,36 @@ func runWithTimeout(cmd *exec.Cmd, timeout time.Duration) (string, error) {
 		close(done)
 	}()
 
+	line := commandLine(cmd.Args)
 	select {
 	case <-done:
+		if cmdErr != nil {
+			logEvent(LogError, string(output), "%s: %v", line, cmdErr)
+		} else {
+			logEvent(LogDebug, string(output), "%s", line)
+		}
 		return string(output), cmdErr
 	case <-time.After(timeout):
 		if cmd.Process != nil {
 			_ = cmd.Process.Kill()
 		}
-		return "", fmt.Errorf("command timed out after %s", timeout)
+		msg := fmt.Sprintf("command timed out after %s", timeout)
+		logEvent(LogError, "", "%s: %s", line, msg)
+		return msg, errors.New(msg)
 	}
 }
 
 // copyDirectory copies the contents of src to dst, excluding certain files.
 func copyDirectory(src, dst string) error {
+	_, err := copyDirectoryFiltered(src, dst, nil)
+	return err
+}
+
+// walkSkillFiles walks a skill source directory and calls fn for each
+// directory and file that should be materialized, with paths relative to src.
+// Excluded files, names starting with _, and paths matched by the optional
+// ignore matcher are skipped.
+func walkSkillFiles(src string, ignore *IgnoreMatcher, fn func(rel string, isDir bool) error) error {
 	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
 		if err != nil {
 			return err
@@ -155,13 +275,15 @@ func copyDirectory(src, dst string) error {
 			return nil
 		}
 
-		dstPath := filepath.Join(dst, rel)
-
-		if d.IsDir() {
-			return os.MkdirAll(dstPath, 0o755)
+		// Skip paths matched by .duckrowignore / global ignore patterns.
+		if rel != "." && ignore.Match(rel, d.IsDir()) {
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
 		}
 
-		return copyFile(path, dstPath)
+		return fn(rel, d.IsDir())
 	})
 }
 
@@ -213,6 +335,12 @@ func cleanupEmptyDir(dir string) {
 	}
 }
 
+// pathExists returns true if anything exists at the path.
+func pathExists(path string) bool {
+	_, err := os.Lstat(path)
+	return err == nil
+}
+
 // dirExists returns true if the path exists and is a directory.
 func dirExists(path string) bool {
 	info, err := os.Stat(path)
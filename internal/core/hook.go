@@ -0,0 +1,146 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// hookMarker identifies git hooks written by duckrow so they can be
+// updated in place without clobbering hooks from other tools.
+const hookMarker = "# Installed by duckrow hook install"
+
+// HookPaths are the paths whose changes trigger the pre-commit lock check,
+// as an extended regular expression matched against staged file names.
+const HookPaths = `(^|/)(\.agents/|\.cursor/|duckrow\.lock\.json$)`
+
+// hookCheckCommand returns the command the hooks run to verify the lock.
+// Hooks run from the repository root, so a project in a subdirectory is
+// passed with --dir.
+func hookCheckCommand(projectRel string) string {
+	if projectRel == "" || projectRel == "." {
+		return "duckrow hook check"
+	}
+	return fmt.Sprintf("duckrow hook check --dir '%s'", filepath.ToSlash(projectRel))
+}
+
+// GitHookNames are the git hooks installed by InstallGitHooks.
+var GitHookNames = []string{"pre-commit", "pre-push"}
+
+// HookExistsError is returned when a hook not written by duckrow is in the way.
+type HookExistsError struct {
+	Path string
+}
+
+func (e *HookExistsError) Error() string {
+	return fmt.Sprintf("%s already exists and was not installed by duckrow", e.Path)
+}
+
+// GitHookScript returns the shell script for a git hook checking the project
+// at projectRel (relative to the repository root). The pre-commit hook only
+// runs the check when staged changes touch skill or lock paths; the pre-push
+// hook always runs it.
+func GitHookScript(name, projectRel string) string {
+	var b strings.Builder
+	b.WriteString("#!/bin/sh\n")
+	b.WriteString(hookMarker + "\n")
+	b.WriteString("# Verifies that duckrow.lock.json matches the installed skills and agents.\n\n")
+	if name == "pre-commit" {
+		fmt.Fprintf(&b, "git diff --cached --name-only | grep -Eq '%s' || exit 0\n\n", HookPaths)
+	}
+	b.WriteString("if ! command -v duckrow >/dev/null 2>&1; then\n")
+	b.WriteString("  echo \"duckrow not found; skipping lock check\" >&2\n")
+	b.WriteString("  exit 0\n")
+	b.WriteString("fi\n\n")
+	fmt.Fprintf(&b, "exec %s\n", hookCheckCommand(projectRel))
+	return b.String()
+}
+
+// PreCommitFrameworkHook returns a hook definition for the pre-commit
+// framework, usable in .pre-commit-hooks.yaml or under a "repo: local"
+// entry in .pre-commit-config.yaml.
+func PreCommitFrameworkHook(projectRel string) string {
+	return fmt.Sprintf(`- id: duckrow-lock
+  name: duckrow lock check
+  description: Verify duckrow.lock.json matches the installed skills and agents
+  entry: %s
+  language: system
+  files: '%s'
+  pass_filenames: false
+  stages: [pre-commit, pre-push]
+`, hookCheckCommand(projectRel), HookPaths)
+}
+
+// InstallGitHooks writes duckrow's pre-commit and pre-push hooks into the
+// git repository containing dir, honoring core.hooksPath. Existing hooks
+// written by duckrow are replaced; other hooks are only overwritten with
+// force. It returns the paths of the hooks written.
+func InstallGitHooks(dir string, force bool) ([]string, error) {
+	hooksDir, err := gitHooksDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	projectRel, err := GitProjectPrefix(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	// Check every hook before writing any, so a conflict leaves nothing half-installed.
+	for _, name := range GitHookNames {
+		path := filepath.Join(hooksDir, name)
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return nil, fmt.Errorf("reading %s: %w", path, err)
+		}
+		if !force && !strings.Contains(string(data), hookMarker) {
+			return nil, &HookExistsError{Path: path}
+		}
+	}
+
+	if err := os.MkdirAll(hooksDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating hooks directory: %w", err)
+	}
+	var written []string
+	for _, name := range GitHookNames {
+		path := filepath.Join(hooksDir, name)
+		if err := os.WriteFile(path, []byte(GitHookScript(name, projectRel)), 0o755); err != nil {
+			return written, fmt.Errorf("writing %s: %w", path, err)
+		}
+		// WriteFile keeps the mode of an existing file; make sure it's executable.
+		if err := os.Chmod(path, 0o755); err != nil {
+			return written, fmt.Errorf("writing %s: %w", path, err)
+		}
+		written = append(written, path)
+	}
+	return written, nil
+}
+
+// GitProjectPrefix returns the path of dir relative to the root of its git
+// repository ("" at the root).
+func GitProjectPrefix(dir string) (string, error) {
+	cmd := exec.Command("git", "-C", dir, "rev-parse", "--show-prefix")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s is not inside a git repository", dir)
+	}
+	return strings.TrimSuffix(strings.TrimSpace(string(out)), "/"), nil
+}
+
+// gitHooksDir returns the hooks directory of the repository containing dir.
+func gitHooksDir(dir string) (string, error) {
+	cmd := exec.Command("git", "-C", dir, "rev-parse", "--git-path", "hooks")
+	out, err := cmd.Output()
+	if err != nil {
+		return "", fmt.Errorf("%s is not inside a git repository", dir)
+	}
+	hooks := strings.TrimSpace(string(out))
+	if !filepath.IsAbs(hooks) {
+		hooks = filepath.Join(dir, hooks)
+	}
+	return hooks, nil
+}
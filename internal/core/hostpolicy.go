@@ -0,0 +1,115 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// ErrHostNotAllowed is returned (wrapped) by operations that would contact a
+// host outside the allowedHosts setting.
+var ErrHostNotAllowed = errors.New("blocked by host policy")
+
+// allowedHostsValue is process-wide like offline mode: the CLI sets it once
+// from the allowedHosts setting, and every git and HTTP helper consults it.
+var allowedHostsValue atomic.Value
+
+// SetAllowedHosts sets the only hosts duckrow may contact. Entries are host
+// names, matched case-insensitively; "*.example.com" matches any subdomain
+// of example.com. Empty allows every host.
+func SetAllowedHosts(hosts []string) {
+	allowedHostsValue.Store(hosts)
+}
+
+// AllowedHosts returns the host allowlist in effect, or nil when every host
+// is allowed.
+func AllowedHosts() []string {
+	hosts, _ := allowedHostsValue.Load().([]string)
+	return hosts
+}
+
+// checkHost returns an error wrapping ErrHostNotAllowed if an allowlist is
+// set and url refers to a remote host outside it. Local paths and file://
+// URLs are always allowed.
+func checkHost(url string) error {
+	allowed := AllowedHosts()
+	if len(allowed) == 0 || isLocalURL(url) {
+		return nil
+	}
+	host := remoteHost(url)
+	if hostAllowed(host, allowed) {
+		return nil
+	}
+	if host == "" {
+		return fmt.Errorf("%w: cannot tell the host of %s", ErrHostNotAllowed, redactURL(url))
+	}
+	return fmt.Errorf("%w: %s is not in allowedHosts", ErrHostNotAllowed, host)
+}
+
+// CheckMCPHost returns an error wrapping ErrHostNotAllowed if meta is a
+// remote MCP server on a host outside the allowlist. Stdio servers are
+// always allowed.
+func CheckMCPHost(meta asset.MCPMeta) error {
+	if !meta.IsRemote() {
+		return nil
+	}
+	if err := checkHost(meta.URL); err != nil {
+		return fmt.Errorf("MCP server URL: %w", err)
+	}
+	return nil
+}
+
+// remoteHost returns the lower-cased host of a git or HTTP URL, either with
+// a scheme (https://host/..., ssh://user@host:22/...) or in the scp-like
+// user@host:path form, or "" if it has none.
+func remoteHost(rawURL string) string {
+	if strings.Contains(rawURL, "://") {
+		u, err := url.Parse(rawURL)
+		if err != nil {
+			return ""
+		}
+		return strings.ToLower(u.Hostname())
+	}
+	host, _, ok := strings.Cut(rawURL, ":")
+	if !ok {
+		return ""
+	}
+	if _, after, found := strings.Cut(host, "@"); found {
+		host = after
+	}
+	return strings.ToLower(host)
+}
+
+// hostAllowed reports whether host matches an entry of allowed.
+func hostAllowed(host string, allowed []string) bool {
+	if host == "" {
+		return false
+	}
+	for _, pattern := range allowed {
+		pattern = strings.ToLower(strings.TrimSpace(pattern))
+		if suffix, ok := strings.CutPrefix(pattern, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+			continue
+		}
+		if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// checkRedirect stops an HTTP client from following a redirect to a host
+// outside the allowlist.
+func checkRedirect(req *http.Request, via []*http.Request) error {
+	if len(via) >= 10 {
+		return errors.New("stopped after 10 redirects")
+	}
+	return checkHost(req.URL.String())
+}
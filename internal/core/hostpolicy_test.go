@@ -0,0 +1,115 @@
+package core
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestRemoteHost(t *testing.T) {
+	tests := []struct {
+		url  string
+		want string
+	}{
+		{"https://GitHub.com/org/repo.git", "github.com"},
+		{"https://token@git.example.com:8443/org/repo", "git.example.com"},
+		{"ssh://git@example.com:22/org/repo.git", "example.com"},
+		{"git@gitlab.com:org/repo.git", "gitlab.com"},
+		{"gitlab.com:org/repo.git", "gitlab.com"},
+		{"relative/path", ""},
+	}
+	for _, tt := range tests {
+		if got := remoteHost(tt.url); got != tt.want {
+			t.Errorf("remoteHost(%q) = %q, want %q", tt.url, got, tt.want)
+		}
+	}
+}
+
+func TestCheckNetwork_AllowedHosts(t *testing.T) {
+	SetAllowedHosts([]string{"github.com", "*.Corp.example"})
+	t.Cleanup(func() { SetAllowedHosts(nil) })
+
+	tests := []struct {
+		url     string
+		allowed bool
+	}{
+		{"https://github.com/org/repo.git", true},
+		{"git@github.com:org/repo.git", true},
+		{"https://git.corp.example/org/repo.git", true},
+		{"https://a.b.corp.example/registry.json", true},
+		{"https://corp.example/org/repo.git", false},
+		{"https://api.github.com/repos/o/r", false},
+		{"https://github.com.evil.example/org/repo.git", false},
+		{"file:///srv/mirror/repo.git", true},
+		{"/srv/mirror/repo", true},
+	}
+	for _, tt := range tests {
+		err := checkNetwork(tt.url)
+		if tt.allowed && err != nil {
+			t.Errorf("checkNetwork(%q) = %v, want nil", tt.url, err)
+		}
+		if !tt.allowed && !errors.Is(err, ErrHostNotAllowed) {
+			t.Errorf("checkNetwork(%q) = %v, want ErrHostNotAllowed", tt.url, err)
+		}
+	}
+
+	if _, err := lsRemote("https://gitlab.com/org/repo.git", ""); !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("lsRemote() error = %v, want ErrHostNotAllowed", err)
+	}
+	if got := ErrorClass(checkNetwork("https://gitlab.com/org/repo.git")); got != "host-not-allowed" {
+		t.Errorf("ErrorClass() = %q, want host-not-allowed", got)
+	}
+
+	SetAllowedHosts(nil)
+	if err := checkNetwork("https://gitlab.com/org/repo.git"); err != nil {
+		t.Errorf("checkNetwork() without an allowlist = %v, want nil", err)
+	}
+}
+
+func TestCheckMCPHost(t *testing.T) {
+	SetAllowedHosts([]string{"mcp.example.com"})
+	t.Cleanup(func() { SetAllowedHosts(nil) })
+
+	if err := CheckMCPHost(asset.MCPMeta{URL: "https://mcp.example.com/sse", Transport: "sse"}); err != nil {
+		t.Errorf("CheckMCPHost(allowed) = %v, want nil", err)
+	}
+	if err := CheckMCPHost(asset.MCPMeta{Command: "npx"}); err != nil {
+		t.Errorf("CheckMCPHost(stdio) = %v, want nil", err)
+	}
+	err := CheckMCPHost(asset.MCPMeta{URL: "https://other.example.com/mcp", Transport: "http"})
+	if !errors.Is(err, ErrHostNotAllowed) || !strings.Contains(err.Error(), "other.example.com") {
+		t.Errorf("CheckMCPHost(other) = %v, want ErrHostNotAllowed naming the host", err)
+	}
+}
+
+func TestDoHTTP_RedirectOutsideAllowlist(t *testing.T) {
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer target.Close()
+	// The target is reached as localhost, which is not in the allowlist.
+	redirect := strings.Replace(target.URL, "127.0.0.1", "localhost", 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, redirect, http.StatusFound)
+	}))
+	defer srv.Close()
+
+	SetAllowedHosts([]string{"127.0.0.1"})
+	t.Cleanup(func() { SetAllowedHosts(nil) })
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	client := &http.Client{}
+	if _, err := doHTTP(client, req); !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("doHTTP() error = %v, want ErrHostNotAllowed", err)
+	}
+	if client.CheckRedirect != nil {
+		t.Error("doHTTP() set CheckRedirect on the caller's client")
+	}
+
+	req, _ = http.NewRequest(http.MethodGet, redirect, nil)
+	if _, err := doHTTP(&http.Client{}, req); !errors.Is(err, ErrHostNotAllowed) {
+		t.Errorf("doHTTP(localhost) error = %v, want ErrHostNotAllowed", err)
+	}
+}
@@ -0,0 +1,207 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// httpRegistryFile marks an HTTP registry's directory under the registries
+// dir. It records where the manifest is downloaded from and the validators
+// of the last download; the manifest itself is kept next to it.
+const httpRegistryFile = "duckrow.http.json"
+
+// maxManifestSize caps how much of a downloaded manifest is read.
+const maxManifestSize = 10 << 20
+
+// httpRegistrySource is the content of httpRegistryFile.
+type httpRegistrySource struct {
+	URL          string    `json:"url"`
+	ETag         string    `json:"etag,omitempty"`
+	LastModified string    `json:"lastModified,omitempty"`
+	FetchedAt    time.Time `json:"fetchedAt"`
+}
+
+// IsManifestURL reports whether a registry URL points at a hosted manifest
+// file rather than a git repository: an http(s) URL whose path ends in
+// .json, .yaml or .yml, e.g. a file served from S3 or raw.githubusercontent.com.
+func IsManifestURL(rawURL string) bool {
+	if !strings.HasPrefix(rawURL, "https://") && !strings.HasPrefix(rawURL, "http://") {
+		return false
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(filepath.Ext(u.Path)) {
+	case ".json", ".yaml", ".yml":
+		return true
+	}
+	return false
+}
+
+// manifestFileFor returns the name a manifest downloaded from rawURL is
+// stored under, so readManifest parses it in the right format.
+func manifestFileFor(rawURL string) string {
+	if u, err := url.Parse(rawURL); err == nil && isYAMLManifest(strings.ToLower(u.Path)) {
+		return "duckrow.yaml"
+	}
+	return registryManifestFile
+}
+
+// addHTTP downloads a hosted manifest into the registry directory for its
+// URL. Nothing is written unless the manifest parses and has a name.
+func (rm *RegistryManager) addHTTP(rawURL string) (*RegistryManifest, error) {
+	src := httpRegistrySource{URL: rawURL}
+	data, src, err := fetchManifest(src, false)
+	if err != nil {
+		return nil, err
+	}
+	name := manifestFileFor(rawURL)
+	manifest, err := parseManifestFile(name, data)
+	if err != nil {
+		return nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	if manifest.Name == "" {
+		return nil, fmt.Errorf("registry manifest missing required 'name' field")
+	}
+
+	destDir := filepath.Join(rm.registriesDir, RegistryDirKey(rawURL))
+	if dirExists(destDir) {
+		if err := os.RemoveAll(destDir); err != nil {
+			return nil, fmt.Errorf("removing existing registry directory: %w", err)
+		}
+	}
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating registries directory: %w", err)
+	}
+	if err := writeHTTPRegistry(destDir, name, data, src); err != nil {
+		return nil, err
+	}
+
+	pm, parseErr := ParseManifest(manifest)
+	if parseErr == nil {
+		manifest.Warnings = pm.Warnings
+		_ = writeCachedWarnings(destDir, pm.Warnings)
+	}
+	return manifest, nil
+}
+
+// isHTTPRegistry reports whether a registry directory holds a downloaded
+// manifest rather than a clone.
+func isHTTPRegistry(registryDir string) bool {
+	_, err := os.Stat(filepath.Join(registryDir, httpRegistryFile))
+	return err == nil
+}
+
+// refreshHTTPRegistry downloads an HTTP registry's manifest again. The
+// request is conditional on the last download's ETag and Last-Modified, so
+// an unchanged manifest costs a 304 and is kept as is. A new manifest that
+// doesn't parse is not written, leaving the last good one in place.
+func refreshHTTPRegistry(registryDir string) error {
+	raw, err := os.ReadFile(filepath.Join(registryDir, httpRegistryFile))
+	if err != nil {
+		return fmt.Errorf("reading %s: %w", httpRegistryFile, err)
+	}
+	var src httpRegistrySource
+	if err := json.Unmarshal(raw, &src); err != nil {
+		return fmt.Errorf("parsing %s: %w", httpRegistryFile, err)
+	}
+
+	data, src, err := fetchManifest(src, true)
+	if err != nil {
+		return err
+	}
+	name := manifestFileFor(src.URL)
+	if data != nil {
+		if _, err := parseManifestFile(name, data); err != nil {
+			return fmt.Errorf("reading manifest: %w", err)
+		}
+	}
+	return writeHTTPRegistry(registryDir, name, data, src)
+}
+
+// fetchManifest downloads the manifest at src.URL. With conditional set,
+// the request carries src's validators, and a 304 Not Modified returns nil
+// data. The returned source holds the validators of this response.
+func fetchManifest(src httpRegistrySource, conditional bool) ([]byte, httpRegistrySource, error) {
+	u, err := url.Parse(src.URL)
+	if err != nil {
+		return nil, src, fmt.Errorf("invalid manifest URL: %w", err)
+	}
+	if err := checkNetwork(src.URL); err != nil {
+		return nil, src, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, src.URL, nil)
+	if err != nil {
+		return nil, src, fmt.Errorf("downloading manifest: %w", err)
+	}
+	if conditional {
+		if src.ETag != "" {
+			req.Header.Set("If-None-Match", src.ETag)
+		}
+		if src.LastModified != "" {
+			req.Header.Set("If-Modified-Since", src.LastModified)
+		}
+	}
+	// A registry's own auth comes first; private files on GitHub are
+	// otherwise served with the same token the API uses.
+	if header := authorizationHeader(src.URL); header != "" {
+		req.Header.Set("Authorization", header)
+	} else if u.Host == "raw.githubusercontent.com" {
+		if token := githubToken(); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+
+	client := &http.Client{Timeout: CurrentTimeouts().Download}
+	resp, err := doHTTP(client, req)
+	if err != nil {
+		return nil, src, fmt.Errorf("downloading manifest: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	src.FetchedAt = time.Now().UTC()
+	switch {
+	case resp.StatusCode == http.StatusNotModified && conditional:
+		return nil, src, nil
+	case resp.StatusCode != http.StatusOK:
+		return nil, src, fmt.Errorf("downloading manifest: %s returned %s", redactURL(src.URL), resp.Status)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxManifestSize+1))
+	if err != nil {
+		return nil, src, fmt.Errorf("downloading manifest: %w", err)
+	}
+	if len(data) > maxManifestSize {
+		return nil, src, fmt.Errorf("downloading manifest: %s is larger than %d MB", redactURL(src.URL), maxManifestSize>>20)
+	}
+	src.ETag = resp.Header.Get("ETag")
+	src.LastModified = resp.Header.Get("Last-Modified")
+	return data, src, nil
+}
+
+// writeHTTPRegistry stores a downloaded manifest and its source in a
+// registry directory. Nil data keeps the stored manifest.
+func writeHTTPRegistry(registryDir, name string, data []byte, src httpRegistrySource) error {
+	if data != nil {
+		if err := os.WriteFile(filepath.Join(registryDir, name), data, 0o644); err != nil {
+			return fmt.Errorf("writing %s: %w", name, err)
+		}
+	}
+	raw, err := json.MarshalIndent(src, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling %s: %w", httpRegistryFile, err)
+	}
+	if err := os.WriteFile(filepath.Join(registryDir, httpRegistryFile), raw, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", httpRegistryFile, err)
+	}
+	return nil
+}
@@ -0,0 +1,161 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// manifestServer serves a registry manifest with an ETag and counts the
+// full downloads and 304 responses.
+type manifestServer struct {
+	mu           sync.Mutex
+	body         string
+	etag         string
+	downloads    int
+	notModified  int
+	lastModified string
+}
+
+func (s *manifestServer) set(body, etag string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.body, s.etag = body, etag
+}
+
+func (s *manifestServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if r.URL.Path != "/registry/duckrow.json" {
+		http.NotFound(w, r)
+		return
+	}
+	if s.etag != "" && r.Header.Get("If-None-Match") == s.etag {
+		s.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	if s.etag == "" && s.lastModified != "" && r.Header.Get("If-Modified-Since") == s.lastModified {
+		s.notModified++
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	s.downloads++
+	if s.etag != "" {
+		w.Header().Set("ETag", s.etag)
+	}
+	if s.lastModified != "" {
+		w.Header().Set("Last-Modified", s.lastModified)
+	}
+	_, _ = w.Write([]byte(s.body))
+}
+
+const (
+	httpManifestV1 = `{"name": "hosted", "skills": [{"name": "lint", "source": "github.com/acme/skills/lint"}]}`
+	httpManifestV2 = `{"name": "hosted", "skills": [
+		{"name": "lint", "source": "github.com/acme/skills/lint"},
+		{"name": "format", "source": "github.com/acme/skills/format"}
+	]}`
+)
+
+func TestIsManifestURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://raw.githubusercontent.com/acme/registry/main/duckrow.json": true,
+		"https://bucket.s3.amazonaws.com/duckrow.yaml?X-Amz-Signature=abc":  true,
+		"http://intranet/registry.yml":                                      true,
+		"https://github.com/acme/registry.git":                              false,
+		"https://github.com/acme/registry":                                  false,
+		"git@github.com:acme/registry.git":                                  false,
+		"./duckrow.json":                                                    false,
+	}
+	for in, want := range tests {
+		if got := IsManifestURL(in); got != want {
+			t.Errorf("IsManifestURL(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
+
+func TestRegistryManager_HTTPRegistry(t *testing.T) {
+	srv := &manifestServer{}
+	srv.set(httpManifestV1, `"v1"`)
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	repo := ts.URL + "/registry/duckrow.json"
+
+	rm := NewRegistryManager(t.TempDir())
+	manifest, err := rm.Add(repo)
+	if err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if manifest.Name != "hosted" {
+		t.Errorf("Name = %q, want hosted", manifest.Name)
+	}
+	if loaded, err := rm.LoadManifest(repo); err != nil || len(loaded.Skills) != 1 {
+		t.Fatalf("LoadManifest() = %v, %v; want 1 skill", loaded, err)
+	}
+
+	// An unchanged manifest is answered with 304 and kept.
+	if _, err := rm.Refresh(repo); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if srv.downloads != 1 || srv.notModified != 1 {
+		t.Errorf("downloads = %d, 304s = %d; want 1, 1", srv.downloads, srv.notModified)
+	}
+	if loaded, err := rm.LoadManifest(repo); err != nil || len(loaded.Skills) != 1 {
+		t.Errorf("LoadManifest() after 304 = %v, %v; want 1 skill", loaded, err)
+	}
+
+	// A changed manifest is downloaded again.
+	srv.set(httpManifestV2, `"v2"`)
+	refreshed, err := rm.Refresh(repo)
+	if err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if len(refreshed.Skills) != 2 {
+		t.Errorf("Refresh() has %d skills, want 2", len(refreshed.Skills))
+	}
+
+	// A broken manifest leaves the last good one in place.
+	srv.set(`{"name": `, `"v3"`)
+	if _, err := rm.Refresh(repo); err == nil {
+		t.Error("Refresh() of a broken manifest: expected error")
+	}
+	if loaded, err := rm.LoadManifest(repo); err != nil || len(loaded.Skills) != 2 {
+		t.Errorf("LoadManifest() after a failed refresh = %v, %v; want 2 skills", loaded, err)
+	}
+
+	if _, err := rm.Add(ts.URL + "/missing/duckrow.json"); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("Add(missing) error = %v, want 404", err)
+	}
+}
+
+func TestRegistryManager_HTTPRegistryLastModified(t *testing.T) {
+	srv := &manifestServer{lastModified: "Wed, 21 Oct 2026 07:28:00 GMT"}
+	srv.set(httpManifestV1, "")
+	ts := httptest.NewServer(srv)
+	defer ts.Close()
+	repo := ts.URL + "/registry/duckrow.json"
+
+	rm := NewRegistryManager(t.TempDir())
+	if _, err := rm.Add(repo); err != nil {
+		t.Fatalf("Add() error = %v", err)
+	}
+	if _, err := rm.Refresh(repo); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if srv.downloads != 1 || srv.notModified != 1 {
+		t.Errorf("downloads = %d, 304s = %d; want 1, 1", srv.downloads, srv.notModified)
+	}
+}
+
+func TestRegistryManager_HTTPRegistryOffline(t *testing.T) {
+	SetOffline(true)
+	t.Cleanup(func() { SetOffline(false) })
+
+	rm := NewRegistryManager(t.TempDir())
+	if _, err := rm.Add("https://example.com/registry/duckrow.json"); err == nil || !strings.Contains(err.Error(), "offline") {
+		t.Errorf("Add() offline error = %v, want offline", err)
+	}
+}
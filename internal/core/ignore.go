@@ -0,0 +1,238 @@
+package core
+
+import (
+	"bufio"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// ignoreFileName is the per-skill ignore file. It lives in the skill source
+// directory and uses a gitignore-like syntax.
+const ignoreFileName = ".duckrowignore"
+
+// IgnoreMatcher decides which files are left out when a skill is copied into
+// a project. Patterns follow a subset of gitignore syntax:
+//
+//   - blank lines and lines starting with # are ignored
+//   - a leading ! re-includes a previously excluded path
+//   - a trailing / matches directories only
+//   - patterns without a / match the base name at any depth
+//   - patterns with a / are anchored to the skill root
+//   - * and ? match within a path segment, ** matches across segments
+type IgnoreMatcher struct {
+	rules []ignoreRule
+}
+
+type ignoreRule struct {
+	re      *regexp.Regexp
+	negate  bool
+	dirOnly bool
+}
+
+// NewIgnoreMatcher compiles the given patterns. Later patterns take precedence
+// over earlier ones, so global defaults should come first.
+func NewIgnoreMatcher(patterns []string) (*IgnoreMatcher, error) {
+	m := &IgnoreMatcher{}
+	for _, p := range patterns {
+		p = strings.TrimSpace(p)
+		if p == "" || strings.HasPrefix(p, "#") {
+			continue
+		}
+
+		rule := ignoreRule{}
+		if strings.HasPrefix(p, "!") {
+			rule.negate = true
+			p = p[1:]
+		}
+		if strings.HasSuffix(p, "/") {
+			rule.dirOnly = true
+			p = strings.TrimSuffix(p, "/")
+		}
+		anchored := strings.Contains(p, "/")
+		p = strings.TrimPrefix(p, "/")
+		if p == "" {
+			continue
+		}
+
+		expr := globToRegexp(p)
+		if anchored {
+			expr = "^" + expr + "$"
+		} else {
+			expr = "(^|/)" + expr + "$"
+		}
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ignore pattern %q: %w", p, err)
+		}
+		rule.re = re
+		m.rules = append(m.rules, rule)
+	}
+	return m, nil
+}
+
+// LoadIgnoreMatcher builds a matcher from the global patterns followed by the
+// .duckrowignore file in skillDir, if present.
+func LoadIgnoreMatcher(skillDir string, global []string) (*IgnoreMatcher, error) {
+	patterns := append([]string(nil), global...)
+
+	f, err := os.Open(filepath.Join(skillDir, ignoreFileName))
+	if err == nil {
+		defer func() { _ = f.Close() }()
+		scanner := bufio.NewScanner(f)
+		for scanner.Scan() {
+			patterns = append(patterns, scanner.Text())
+		}
+		if err := scanner.Err(); err != nil {
+			return nil, fmt.Errorf("reading %s: %w", ignoreFileName, err)
+		}
+	} else if !os.IsNotExist(err) {
+		return nil, fmt.Errorf("reading %s: %w", ignoreFileName, err)
+	}
+
+	return NewIgnoreMatcher(patterns)
+}
+
+// Empty reports whether the matcher has no rules.
+func (m *IgnoreMatcher) Empty() bool {
+	return m == nil || len(m.rules) == 0
+}
+
+// Match reports whether a slash-separated path relative to the skill root
+// should be ignored.
+func (m *IgnoreMatcher) Match(relPath string, isDir bool) bool {
+	if m == nil {
+		return false
+	}
+	relPath = filepath.ToSlash(relPath)
+	ignored := false
+	for _, r := range m.rules {
+		if r.dirOnly && !isDir {
+			continue
+		}
+		if r.re.MatchString(relPath) {
+			ignored = !r.negate
+		}
+	}
+	return ignored
+}
+
+// globToRegexp converts a gitignore-style glob to a regular expression body.
+func globToRegexp(glob string) string {
+	var b strings.Builder
+	for i := 0; i < len(glob); i++ {
+		c := glob[i]
+		switch c {
+		case '*':
+			if i+1 < len(glob) && glob[i+1] == '*' {
+				i++
+				if i+1 < len(glob) && glob[i+1] == '/' {
+					i++
+					b.WriteString("(.*/)?")
+				} else {
+					b.WriteString(".*")
+				}
+			} else {
+				b.WriteString("[^/]*")
+			}
+		case '?':
+			b.WriteString("[^/]")
+		default:
+			b.WriteString(regexp.QuoteMeta(string(c)))
+		}
+	}
+	return b.String()
+}
+
+// copyDirectoryFiltered copies src to dst like copyDirectory, additionally
+// skipping paths matched by the ignore matcher. It returns the sorted,
+// slash-separated list of files that were copied.
+func copyDirectoryFiltered(src, dst string, ignore *IgnoreMatcher) ([]string, error) {
+	var files []string
+	err := walkSkillFiles(src, ignore, func(rel string, isDir bool) error {
+		dstPath := filepath.Join(dst, rel)
+		if isDir {
+			return os.MkdirAll(dstPath, 0o755)
+		}
+		files = append(files, filepath.ToSlash(rel))
+		return copyFile(filepath.Join(src, rel), dstPath)
+	})
+	if err != nil {
+		return nil, err
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+// LockedFiles returns the effective file list recorded in a lock entry's
+// "files" field, or nil if none was recorded.
+func LockedFiles(locked asset.LockedAsset) []string {
+	if locked.Data == nil {
+		return nil
+	}
+	switch v := locked.Data["files"].(type) {
+	case []string:
+		return v
+	case []any:
+		files := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				files = append(files, s)
+			}
+		}
+		return files
+	}
+	return nil
+}
+
+// VerifySkillFiles compares a skill's canonical copy in projectDir against
+// the effective file list recorded in its lock entry. It returns recorded
+// files that are missing on disk and files on disk that were not recorded.
+// Entries without a recorded file list are not checked.
+func VerifySkillFiles(projectDir string, locked asset.LockedAsset) (missing, unexpected []string, err error) {
+	recorded := LockedFiles(locked)
+	if recorded == nil {
+		return nil, nil, nil
+	}
+
+	skillDir := filepath.Join(projectDir, canonicalSkillsDir, sanitizeName(locked.Name))
+	onDisk := make(map[string]bool)
+	err = filepath.WalkDir(skillDir, func(path string, d fs.DirEntry, walkErr error) error {
+		if walkErr != nil {
+			return walkErr
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, relErr := filepath.Rel(skillDir, path)
+		if relErr != nil {
+			return relErr
+		}
+		onDisk[filepath.ToSlash(rel)] = true
+		return nil
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("scanning %s: %w", locked.Name, err)
+	}
+
+	want := make(map[string]bool, len(recorded))
+	for _, f := range recorded {
+		want[f] = true
+		if !onDisk[f] {
+			missing = append(missing, f)
+		}
+	}
+	for f := range onDisk {
+		if !want[f] {
+			unexpected = append(unexpected, f)
+		}
+	}
+	sort.Strings(unexpected)
+	return missing, unexpected, nil
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestIgnoreMatcher_Match(t *testing.T) {
+	m, err := NewIgnoreMatcher([]string{
+		"# comment",
+		"",
+		"*.png",
+		"fixtures/",
+		"/docs/internal",
+		"**/testdata/**",
+		"!keep.png",
+	})
+	if err != nil {
+		t.Fatalf("NewIgnoreMatcher() error = %v", err)
+	}
+
+	tests := []struct {
+		path  string
+		isDir bool
+		want  bool
+	}{
+		{"SKILL.md", false, false},
+		{"logo.png", false, true},
+		{"assets/logo.png", false, true},
+		{"keep.png", false, false},
+		{"fixtures", true, true},
+		{"nested/fixtures", true, true},
+		{"fixtures", false, false},
+		{"docs/internal", true, true},
+		{"other/docs/internal", true, false},
+		{"pkg/testdata/a.json", false, true},
+	}
+	for _, tt := range tests {
+		if got := m.Match(tt.path, tt.isDir); got != tt.want {
+			t.Errorf("Match(%q, %v) = %v, want %v", tt.path, tt.isDir, got, tt.want)
+		}
+	}
+}
+
+func TestCopyDirectoryFiltered(t *testing.T) {
+	src := t.TempDir()
+	for path, content := range map[string]string{
+		"SKILL.md":           "---\nname: s\n---\n",
+		"prompt.md":          "prompt",
+		"fixtures/data.json": "{}",
+		"media/demo.mp4":     "video",
+		ignoreFileName:       "fixtures/\n",
+		"_private/notes.txt": "skip",
+		"scripts/run.sh":     "#!/bin/sh",
+	} {
+		p := filepath.Join(src, path)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ignore, err := LoadIgnoreMatcher(src, []string{"*.mp4"})
+	if err != nil {
+		t.Fatalf("LoadIgnoreMatcher() error = %v", err)
+	}
+
+	dst := t.TempDir()
+	files, err := copyDirectoryFiltered(src, dst, ignore)
+	if err != nil {
+		t.Fatalf("copyDirectoryFiltered() error = %v", err)
+	}
+
+	want := []string{"SKILL.md", "prompt.md", "scripts/run.sh"}
+	if !reflect.DeepEqual(files, want) {
+		t.Errorf("files = %v, want %v", files, want)
+	}
+	for _, gone := range []string{"fixtures", "media/demo.mp4", ignoreFileName} {
+		if _, err := os.Stat(filepath.Join(dst, gone)); !os.IsNotExist(err) {
+			t.Errorf("%s should not have been copied", gone)
+		}
+	}
+}
+
+func TestVerifySkillFiles(t *testing.T) {
+	dir := t.TempDir()
+	skillDir := filepath.Join(dir, canonicalSkillsDir, "my-skill")
+	if err := os.MkdirAll(skillDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"SKILL.md", "extra.txt"} {
+		if err := os.WriteFile(filepath.Join(skillDir, f), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	locked := asset.LockedAsset{
+		Kind: asset.KindSkill,
+		Name: "my-skill",
+		// Lock data decoded from JSON holds []any, not []string.
+		Data: map[string]any{"files": []any{"SKILL.md", "prompt.md"}},
+	}
+	missing, unexpected, err := VerifySkillFiles(dir, locked)
+	if err != nil {
+		t.Fatalf("VerifySkillFiles() error = %v", err)
+	}
+	if !reflect.DeepEqual(missing, []string{"prompt.md"}) {
+		t.Errorf("missing = %v, want [prompt.md]", missing)
+	}
+	if !reflect.DeepEqual(unexpected, []string{"extra.txt"}) {
+		t.Errorf("unexpected = %v, want [extra.txt]", unexpected)
+	}
+
+	// Entries without a recorded file list are not checked.
+	locked.Data = nil
+	missing, unexpected, err = VerifySkillFiles(dir, locked)
+	if err != nil || missing != nil || unexpected != nil {
+		t.Errorf("VerifySkillFiles() without files = %v, %v, %v; want nil", missing, unexpected, err)
+	}
+}
@@ -0,0 +1,201 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// InitResult lists the files an init command wrote and the existing ones it
+// left alone, as paths relative to the directory it ran in.
+type InitResult struct {
+	Created []string
+	Skipped []string
+}
+
+// registryWorkflowPath is where InitRegistry writes the pinning workflow.
+const registryWorkflowPath = ".github/workflows/duckrow.yml"
+
+// InitRegistry scaffolds a registry repository in dir: a duckrow.json
+// manifest named name with an example skill, MCP, and agent, a README, and
+// a GitHub Actions workflow that checks the manifest and reports unpinned
+// entries with the commit to pin them to. Existing files are kept unless
+// force is set.
+func InitRegistry(dir, name string, force bool) (*InitResult, error) {
+	files := []struct{ rel, content string }{
+		{"duckrow.json", registryManifestTemplate(name)},
+		{"README.md", registryReadmeTemplate(name)},
+		{registryWorkflowPath, registryWorkflowTemplate},
+	}
+	res := &InitResult{}
+	for _, f := range files {
+		path := filepath.Join(dir, filepath.FromSlash(f.rel))
+		if !force && pathExists(path) {
+			res.Skipped = append(res.Skipped, f.rel)
+			continue
+		}
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			return res, fmt.Errorf("creating directory for %s: %w", f.rel, err)
+		}
+		if err := os.WriteFile(path, []byte(f.content), 0o644); err != nil {
+			return res, fmt.Errorf("writing %s: %w", f.rel, err)
+		}
+		res.Created = append(res.Created, f.rel)
+	}
+	return res, nil
+}
+
+// InitProject prepares dir for duckrow: an empty duckrow.lock.json, an
+// .env.duckrow for the env vars MCPs require, and .gitignore entries for
+// the env file and the personal lock file. Existing files are kept.
+func InitProject(dir string) (*InitResult, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating directory %s: %w", dir, err)
+	}
+	res := &InitResult{}
+
+	if pathExists(LockFilePath(dir)) {
+		res.Skipped = append(res.Skipped, lockFileName)
+	} else {
+		if err := WriteLockFile(dir, &LockFile{}); err != nil {
+			return res, err
+		}
+		res.Created = append(res.Created, lockFileName)
+	}
+
+	envPath := filepath.Join(dir, envFileName)
+	if pathExists(envPath) {
+		res.Skipped = append(res.Skipped, envFileName)
+	} else {
+		if err := os.WriteFile(envPath, []byte(projectEnvTemplate), 0o600); err != nil {
+			return res, fmt.Errorf("writing %s: %w", envFileName, err)
+		}
+		res.Created = append(res.Created, envFileName)
+	}
+
+	for _, entry := range []string{envFileName, projectDuckrowDir + "/" + localLockFileName} {
+		if err := ensureGitignoreEntry(dir, entry); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+const projectEnvTemplate = `# Values for the env vars your MCP servers require, one NAME=value per line.
+# This file is gitignored: keep secrets here, not in duckrow.lock.json.
+`
+
+func registryManifestTemplate(name string) string {
+	return fmt.Sprintf(`{
+  "version": 2,
+  "name": %q,
+  "description": "Skills, MCP servers, and agents approved for %s",
+  "assets": {
+    "skill": [
+      {
+        "name": "example-skill",
+        "description": "Replace with a skill from one of your repositories",
+        "source": "github.com/your-org/skills/skills/example-skill"
+      }
+    ],
+    "mcp": [
+      {
+        "name": "example-mcp",
+        "description": "Replace with an MCP server your team uses",
+        "command": "npx",
+        "args": ["-y", "@your-org/mcp-example"],
+        "env": ["EXAMPLE_API_TOKEN"]
+      }
+    ],
+    "agent": [
+      {
+        "name": "example-agent",
+        "description": "Replace with an agent from one of your repositories",
+        "source": "github.com/your-org/agents/example-agent"
+      }
+    ]
+  }
+}
+`, name, name)
+}
+
+func registryReadmeTemplate(name string) string {
+	return fmt.Sprintf(`# %s
+
+A [duckrow](https://github.com/barysiuk/duckrow) registry: the skills, MCP
+servers, and agents our team installs by name.
+
+## Using it
+
+`+"```"+`bash
+duckrow registry add <clone-url-of-this-repo>
+
+duckrow skill install example-skill
+duckrow mcp install example-mcp
+duckrow agent install example-agent
+`+"```"+`
+
+## Adding an entry
+
+Add it to `+"`duckrow.json`"+` under its kind (`+"`skill`, `mcp`, `agent`, `command`, or `rule`"+`) and
+open a pull request. Skills and agents point at a `+"`source`"+`: a path in a git
+repository, such as `+"`github.com/your-org/skills/skills/code-review`"+`.
+
+Print the manifest's JSON Schema with `+"`duckrow schema print registry`"+`.
+
+## Pinning
+
+An entry with a `+"`commit`"+` installs that exact commit; one without installs the
+latest and is updated whenever its source changes. The workflow in
+`+"`"+registryWorkflowPath+"`"+` flags unpinned entries on every pull request and
+prints the commit to pin each one to.
+`, name)
+}
+
+// registryWorkflowTemplate checks a registry manifest on every pull
+// request: that it passes duckrow registry lint, and that every entry with
+// a source is pinned to a commit.
+const registryWorkflowTemplate = `# Checks duckrow.json on every pull request: it must pass duckrow registry
+# lint, and every entry with a source must be pinned to a commit. Lint also
+# checks that source repositories can be reached. Unpinned entries are
+# reported with the latest commit of their repository, ready to paste in.
+# Remove the pinning step to let entries track the latest commit.
+# jq reads duckrow.json, so keep it free of comments.
+name: duckrow
+
+on:
+  pull_request:
+  workflow_dispatch:
+
+jobs:
+  check:
+    runs-on: ubuntu-latest
+    steps:
+      - uses: actions/checkout@v4
+
+      - uses: actions/setup-go@v5
+        with:
+          go-version: stable
+
+      - name: Install duckrow
+        run: go install github.com/barysiuk/duckrow/cmd/duckrow@latest
+
+      - name: Check the manifest
+        run: duckrow registry lint
+
+      - name: Check that entries are pinned
+        run: |
+          status=0
+          for entry in $(jq -r '.assets[][] | select(.source and (.commit | not)) | "\(.name)=\(.source)"' duckrow.json); do
+            name=${entry%%=*}
+            source=${entry#*=}
+            case ${source%%/*} in
+              *.*) repo=$(echo "$source" | cut -d/ -f1-3) ;;
+              *) repo=github.com/$(echo "$source" | cut -d/ -f1-2) ;;
+            esac
+            sha=$(git ls-remote "https://$repo" HEAD | cut -f1)
+            echo "::error file=duckrow.json::$name is not pinned; add \"commit\": \"$sha\""
+            status=1
+          done
+          exit $status
+`
@@ -0,0 +1,96 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestInitRegistry(t *testing.T) {
+	dir := t.TempDir()
+
+	res, err := InitRegistry(dir, "acme", false)
+	if err != nil {
+		t.Fatalf("InitRegistry() error = %v", err)
+	}
+	want := []string{"duckrow.json", "README.md", registryWorkflowPath}
+	if !reflect.DeepEqual(res.Created, want) || len(res.Skipped) != 0 {
+		t.Errorf("InitRegistry() = %+v, want all of %v created", res, want)
+	}
+
+	// The scaffolded manifest reads and validates like any registry's.
+	raw, err := readManifest(dir)
+	if err != nil {
+		t.Fatalf("readManifest() error = %v", err)
+	}
+	pm, err := ParseManifest(raw)
+	if err != nil {
+		t.Fatalf("ParseManifest() error = %v", err)
+	}
+	if pm.Name != "acme" {
+		t.Errorf("manifest name = %q, want acme", pm.Name)
+	}
+	for _, kind := range []asset.Kind{asset.KindSkill, asset.KindMCP, asset.KindAgent} {
+		if len(pm.Entries[kind]) != 1 {
+			t.Errorf("manifest has %d %s entries, want 1", len(pm.Entries[kind]), kind)
+		}
+	}
+
+	// A second run keeps the edited files; --force overwrites them.
+	readme := filepath.Join(dir, "README.md")
+	if err := os.WriteFile(readme, []byte("# Ours\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	res, err = InitRegistry(dir, "acme", false)
+	if err != nil || len(res.Created) != 0 || !reflect.DeepEqual(res.Skipped, want) {
+		t.Errorf("InitRegistry(again) = %+v, %v; want everything kept", res, err)
+	}
+	if _, err := InitRegistry(dir, "acme", true); err != nil {
+		t.Fatalf("InitRegistry(force) error = %v", err)
+	}
+	if data, _ := os.ReadFile(readme); !strings.HasPrefix(string(data), "# acme\n") {
+		t.Errorf("README.md not overwritten with --force:\n%s", data)
+	}
+}
+
+func TestInitProject(t *testing.T) {
+	dir := t.TempDir()
+
+	res, err := InitProject(dir)
+	if err != nil {
+		t.Fatalf("InitProject() error = %v", err)
+	}
+	if want := []string{lockFileName, envFileName}; !reflect.DeepEqual(res.Created, want) {
+		t.Errorf("InitProject() created %v, want %v", res.Created, want)
+	}
+
+	lf, err := ReadLockFile(dir)
+	if err != nil {
+		t.Fatalf("ReadLockFile() error = %v", err)
+	}
+	if len(lf.Assets) != 0 {
+		t.Errorf("lock file has %d assets, want none", len(lf.Assets))
+	}
+	gitignore, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range []string{".env.duckrow", ".duckrow/local.lock.json"} {
+		if !strings.Contains(string(gitignore), entry+"\n") {
+			t.Errorf(".gitignore missing %s:\n%s", entry, gitignore)
+		}
+	}
+
+	// Running it again changes nothing.
+	res, err = InitProject(dir)
+	if err != nil || len(res.Created) != 0 || len(res.Skipped) != 2 {
+		t.Errorf("InitProject(again) = %+v, %v; want everything kept", res, err)
+	}
+	if again, _ := os.ReadFile(filepath.Join(dir, ".gitignore")); string(again) != string(gitignore) {
+		t.Errorf(".gitignore changed on a second run:\n%s", again)
+	}
+}
@@ -0,0 +1,148 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+// InstallReport is what an install did, whatever the asset kind. The CLI
+// prints it as text or JSON and the TUI summarizes it when the wizard
+// finishes, so every kind reports the same facts in the same shape.
+type InstallReport struct {
+	Kind     asset.Kind `json:"kind"`
+	Registry string     `json:"registry,omitempty"` // registry name, for registry installs
+
+	Assets []InstallReportAsset `json:"assets"`
+
+	// LockFile is the lock file that was updated, relative to the project
+	// (or ~-relative for --global), or "" if none was.
+	LockFile string `json:"lockFile,omitempty"`
+
+	// Attestations are the attestations written, relative to the project,
+	// when the attest setting is on.
+	Attestations []string `json:"attestations,omitempty"`
+
+	// PostInstallMessage is the registry entry's note for the user, e.g.
+	// a setup step to run before using the asset.
+	PostInstallMessage string `json:"postInstallMessage,omitempty"`
+
+	// Warnings are problems that did not stop the install.
+	Warnings []string `json:"warnings,omitempty"`
+}
+
+// InstallReportAsset is one asset in an InstallReport.
+type InstallReportAsset struct {
+	Name      string `json:"name"`
+	AliasOf   string `json:"aliasOf,omitempty"`
+	Namespace string `json:"namespace,omitempty"`
+	Commit    string `json:"commit,omitempty"`
+	Version   string `json:"version,omitempty"` // semantic version, for versioned skills
+
+	// Unchanged is set when the asset was already installed at Commit, so
+	// nothing was copied.
+	Unchanged bool `json:"unchanged,omitempty"`
+
+	// Path is the shared on-disk copy, for skills.
+	Path string `json:"path,omitempty"`
+
+	// Systems lists the systems the asset was installed or linked for.
+	Systems []string `json:"systems,omitempty"`
+
+	// SizeBytes and FileCount are the footprint of the installed copy, for
+	// skills.
+	SizeBytes int64 `json:"sizeBytes,omitempty"`
+	FileCount int   `json:"fileCount,omitempty"`
+
+	// Files lists the per-system files or config files written.
+	Files []InstallReportFile `json:"files,omitempty"`
+
+	// MissingRequirements lists project files the asset needs that the
+	// target directory lacks.
+	MissingRequirements []string `json:"missingRequirements,omitempty"`
+
+	// RequiredEnv lists the env vars an MCP needs.
+	RequiredEnv []string `json:"requiredEnv,omitempty"`
+}
+
+// InstallReportFile is a file an install wrote, or skipped, for one system.
+type InstallReportFile struct {
+	Path   string `json:"path"`
+	System string `json:"system"` // display name
+
+	// Key is the entry written in the config file, e.g. "mcpServers.github",
+	// for MCPs.
+	Key string `json:"key,omitempty"`
+
+	// Skipped says why the file was left alone, e.g. "already exists",
+	// and Error why writing it failed; both are empty when it was written.
+	Skipped string `json:"skipped,omitempty"`
+	Error   string `json:"error,omitempty"`
+}
+
+// MCPConfigKey returns the key an MCP named name is written under in sys's
+// config file, e.g. "mcpServers.github", or "" if sys has no MCP config.
+func MCPConfigKey(sys system.System, name string) string {
+	if s, ok := sys.(interface{ MCPConfigKey() string }); ok && s.MCPConfigKey() != "" {
+		return s.MCPConfigKey() + "." + name
+	}
+	return ""
+}
+
+// Warn adds a warning to the report.
+func (r *InstallReport) Warn(format string, args ...any) {
+	r.Warnings = append(r.Warnings, fmt.Sprintf(format, args...))
+}
+
+// Installed returns the assets that were installed, leaving out those that
+// were already installed and unchanged.
+func (r InstallReport) Installed() []InstallReportAsset {
+	var installed []InstallReportAsset
+	for _, a := range r.Assets {
+		if !a.Unchanged {
+			installed = append(installed, a)
+		}
+	}
+	return installed
+}
+
+// Size returns the footprint of the installed copy, zero when unknown.
+func (a InstallReportAsset) Size() SkillSize {
+	return SkillSize{Bytes: a.SizeBytes, Files: a.FileCount}
+}
+
+// NewInstallReportAsset describes an orchestrator install result. For
+// agents, commands, and rules it lists the file written for each system in
+// targetDir.
+func NewInstallReportAsset(r OrchestratorInstallResult, targetDir string) InstallReportAsset {
+	a := InstallReportAsset{
+		Name:                r.Asset.Name,
+		AliasOf:             r.AliasOf,
+		Namespace:           r.Namespace,
+		Commit:              r.Commit,
+		Version:             r.Version,
+		Unchanged:           r.Unchanged,
+		Systems:             r.Systems,
+		MissingRequirements: r.MissingRequirements,
+	}
+	if asset.IsSystemFile(r.Asset.Kind) {
+		for _, name := range r.Systems {
+			sys, ok := system.ByName(name)
+			if !ok {
+				continue
+			}
+			a.Files = append(a.Files, InstallReportFile{
+				Path:   sys.AssetPath(r.Asset.Kind, r.Asset.Name, targetDir),
+				System: sys.DisplayName(),
+			})
+		}
+		return a
+	}
+	if !r.Unchanged {
+		a.Path = r.Asset.PreparedPath
+		a.SizeBytes = r.Size.Bytes
+		a.FileCount = r.Size.Files
+	}
+	return a
+}
@@ -0,0 +1,54 @@
+package core
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestNewInstallReportAsset(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("skill", func(t *testing.T) {
+		got := NewInstallReportAsset(OrchestratorInstallResult{
+			Asset:   asset.Asset{Kind: asset.KindSkill, Name: "org-a--go-review", PreparedPath: "/p"},
+			Systems: []string{"claude-code"},
+			Commit:  "abc123",
+			Size:    SkillSize{Bytes: 10, Files: 2},
+			AliasOf: "go-review", Namespace: "org-a",
+		}, dir)
+		want := InstallReportAsset{
+			Name: "org-a--go-review", AliasOf: "go-review", Namespace: "org-a", Commit: "abc123",
+			Path: "/p", Systems: []string{"claude-code"}, SizeBytes: 10, FileCount: 2,
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("NewInstallReportAsset() = %+v, want %+v", got, want)
+		}
+	})
+
+	t.Run("agent lists a file per system", func(t *testing.T) {
+		got := NewInstallReportAsset(OrchestratorInstallResult{
+			Asset:   asset.Asset{Kind: asset.KindAgent, Name: "reviewer"},
+			Systems: []string{"claude-code"},
+		}, dir)
+		if len(got.Files) != 1 || got.Files[0].System != "Claude Code" ||
+			got.Files[0].Path != filepath.Join(dir, ".claude", "agents", "reviewer.md") {
+			t.Errorf("Files = %+v", got.Files)
+		}
+		if got.Path != "" || got.Size() != (SkillSize{}) {
+			t.Errorf("Path, Size = %q, %v; want none for agents", got.Path, got.Size())
+		}
+	})
+
+	t.Run("unchanged", func(t *testing.T) {
+		report := InstallReport{Assets: []InstallReportAsset{
+			NewInstallReportAsset(OrchestratorInstallResult{Asset: asset.Asset{Kind: asset.KindSkill, Name: "a"}, Unchanged: true}, dir),
+			NewInstallReportAsset(OrchestratorInstallResult{Asset: asset.Asset{Kind: asset.KindSkill, Name: "b"}}, dir),
+		}}
+		if got := report.Installed(); len(got) != 1 || got[0].Name != "b" {
+			t.Errorf("Installed() = %+v, want only b", got)
+		}
+	})
+}
@@ -0,0 +1,213 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+// LinkProblem classifies an entry in a non-universal system's skill
+// directory that no longer matches the canonical install.
+type LinkProblem string
+
+const (
+	// LinkBroken is a symlink whose target does not exist, typically left
+	// behind when a skill was deleted by hand.
+	LinkBroken LinkProblem = "broken link"
+	// LinkStaleCopy is a copy (made where symlinks are unavailable) of a
+	// locked skill that differs from the canonical copy.
+	LinkStaleCopy LinkProblem = "stale copy"
+)
+
+// LinkFix is what RepairLinks does about a LinkIssue.
+type LinkFix string
+
+const (
+	FixRelink LinkFix = "relink" // point the entry at the canonical copy again
+	FixPrune  LinkFix = "prune"  // remove the entry
+	FixSync   LinkFix = "sync"   // canonical copy is missing; sync restores it
+)
+
+// LinkIssue is a problem with one entry in a system's skill directory.
+type LinkIssue struct {
+	System  string // system name
+	Name    string // entry name in the system's skill directory
+	Path    string // project-relative path of the link or copy
+	Problem LinkProblem
+	Fix     LinkFix
+}
+
+func (i LinkIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Path, i.Problem)
+}
+
+// ScanLinks checks the skill directories of non-universal systems in
+// projectDir for broken symlinks and for copies of locked skills that drifted
+// from the canonical copy. Real directories that aren't in the lock file may
+// be hand-written skills and are left alone.
+func (o *Orchestrator) ScanLinks(projectDir string) ([]LinkIssue, error) {
+	lf, err := ReadLayeredLockFile(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	locked := make(map[string]bool)
+	for _, a := range AssetsByKind(lf, asset.KindSkill) {
+		locked[sanitizeName(a.Name)] = true
+	}
+
+	canonicalDir := filepath.Join(projectDir, canonicalSkillsDir)
+	seenDirs := make(map[string]bool)
+	var issues []LinkIssue
+
+	for _, sys := range system.Supporting(asset.KindSkill) {
+		if sys.IsUniversal() {
+			continue
+		}
+		skillsDir := sys.AssetDir(asset.KindSkill, projectDir)
+		if skillsDir == "" || skillsDir == canonicalDir || seenDirs[skillsDir] {
+			continue
+		}
+		seenDirs[skillsDir] = true
+
+		entries, err := os.ReadDir(skillsDir)
+		if err != nil {
+			continue
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			path := filepath.Join(skillsDir, name)
+			canonical := filepath.Join(canonicalDir, name)
+			issue := LinkIssue{System: sys.Name(), Name: name, Path: relSlash(projectDir, path)}
+
+			switch {
+			case entry.Type()&fs.ModeSymlink != 0:
+				if _, err := os.Stat(path); err == nil {
+					continue
+				}
+				issue.Problem = LinkBroken
+				switch {
+				case !locked[name]:
+					issue.Fix = FixPrune
+				case dirExists(canonical):
+					issue.Fix = FixRelink
+				default:
+					issue.Fix = FixSync
+				}
+			case entry.IsDir() && locked[name] && dirExists(canonical):
+				same, err := sameTree(path, canonical)
+				if err != nil {
+					return nil, fmt.Errorf("comparing %s: %w", issue.Path, err)
+				}
+				if same {
+					continue
+				}
+				issue.Problem = LinkStaleCopy
+				issue.Fix = FixRelink
+			default:
+				continue
+			}
+			issues = append(issues, issue)
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool { return issues[i].Path < issues[j].Path })
+	return issues, nil
+}
+
+// RepairLinks applies the fix for each issue: relinked entries point at the
+// canonical copy again (or are re-copied where symlinks are unavailable), and
+// pruned entries are removed. Issues that need a sync are left alone. It
+// returns the issues that were fixed.
+func (o *Orchestrator) RepairLinks(projectDir string, issues []LinkIssue) ([]LinkIssue, error) {
+	var fixed []LinkIssue
+	for _, issue := range issues {
+		sys, ok := system.ByName(issue.System)
+		if !ok {
+			continue
+		}
+		switch issue.Fix {
+		case FixRelink:
+			a := asset.Asset{Kind: asset.KindSkill, Name: issue.Name}
+			if err := sys.Install(a, projectDir, systemInstallOptions(true)); err != nil {
+				return fixed, fmt.Errorf("relinking %s: %w", issue.Path, err)
+			}
+		case FixPrune:
+			if err := sys.Remove(asset.KindSkill, issue.Name, projectDir); err != nil {
+				return fixed, fmt.Errorf("pruning %s: %w", issue.Path, err)
+			}
+		default:
+			continue
+		}
+		fixed = append(fixed, issue)
+	}
+	return fixed, nil
+}
+
+// sameTree reports whether two directories contain the same files with the
+// same contents.
+func sameTree(a, b string) (bool, error) {
+	filesA, err := treeFiles(a)
+	if err != nil {
+		return false, err
+	}
+	filesB, err := treeFiles(b)
+	if err != nil {
+		return false, err
+	}
+	if len(filesA) != len(filesB) {
+		return false, nil
+	}
+	for rel := range filesA {
+		if !filesB[rel] {
+			return false, nil
+		}
+		dataA, err := os.ReadFile(filepath.Join(a, rel))
+		if err != nil {
+			return false, err
+		}
+		dataB, err := os.ReadFile(filepath.Join(b, rel))
+		if err != nil {
+			return false, err
+		}
+		if !bytes.Equal(dataA, dataB) {
+			return false, nil
+		}
+	}
+	return true, nil
+}
+
+// treeFiles returns the set of regular files under root, relative to it.
+func treeFiles(root string) (map[string]bool, error) {
+	files := make(map[string]bool)
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		files[rel] = true
+		return nil
+	})
+	return files, err
+}
+
+// relSlash returns path relative to base with forward slashes, or path
+// itself if it is not under base.
+func relSlash(base, path string) string {
+	rel, err := filepath.Rel(base, path)
+	if err != nil {
+		return path
+	}
+	return filepath.ToSlash(rel)
+}
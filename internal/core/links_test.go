@@ -0,0 +1,146 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestScanLinks(t *testing.T) {
+	dir := t.TempDir()
+	writeSkill := func(rel, desc string) {
+		t.Helper()
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		content := "---\nname: " + filepath.Base(rel) + "\ndescription: " + desc + "\n---\n"
+		if err := os.WriteFile(filepath.Join(path, "SKILL.md"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	symlink := func(target, rel string) {
+		t.Helper()
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.Symlink(target, path); err != nil {
+			t.Skipf("symlinks not supported: %v", err)
+		}
+	}
+
+	for _, name := range []string{"linked", "copied", "relinkable", "deleted"} {
+		if err := AddOrUpdateAsset(dir, asset.LockedAsset{Kind: asset.KindSkill, Name: name, Source: "github.com/o/r/" + name}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeSkill(".agents/skills/linked", "current")
+	writeSkill(".agents/skills/copied", "current")
+	writeSkill(".agents/skills/relinkable", "current")
+
+	symlink("../../.agents/skills/linked", ".claude/skills/linked")     // healthy
+	writeSkill(".claude/skills/copied", "old")                          // stale copy
+	symlink("../../nowhere", ".claude/skills/relinkable")               // broken, locked
+	symlink("../../.agents/skills/deleted", ".claude/skills/deleted")   // broken, canonical gone
+	symlink("../../.agents/skills/unlocked", ".claude/skills/unlocked") // broken, not locked
+	writeSkill(".claude/skills/hand-written", "mine")                   // not managed by duckrow
+
+	issues, err := NewOrchestrator().ScanLinks(dir)
+	if err != nil {
+		t.Fatalf("ScanLinks() error = %v", err)
+	}
+
+	want := map[string]struct {
+		problem LinkProblem
+		fix     LinkFix
+	}{
+		".claude/skills/copied":     {LinkStaleCopy, FixRelink},
+		".claude/skills/deleted":    {LinkBroken, FixSync},
+		".claude/skills/relinkable": {LinkBroken, FixRelink},
+		".claude/skills/unlocked":   {LinkBroken, FixPrune},
+	}
+	if len(issues) != len(want) {
+		t.Fatalf("ScanLinks() = %v, want %d issues", issues, len(want))
+	}
+	for _, issue := range issues {
+		w, ok := want[issue.Path]
+		if !ok {
+			t.Errorf("unexpected issue %v", issue)
+			continue
+		}
+		if issue.Problem != w.problem || issue.Fix != w.fix {
+			t.Errorf("%s: got (%s, %s), want (%s, %s)", issue.Path, issue.Problem, issue.Fix, w.problem, w.fix)
+		}
+	}
+
+	fixed, err := NewOrchestrator().RepairLinks(dir, issues)
+	if err != nil {
+		t.Fatalf("RepairLinks() error = %v", err)
+	}
+	if len(fixed) != 3 {
+		t.Errorf("RepairLinks() fixed %d issues, want 3", len(fixed))
+	}
+
+	remaining, err := NewOrchestrator().ScanLinks(dir)
+	if err != nil {
+		t.Fatalf("ScanLinks() after repair error = %v", err)
+	}
+	if len(remaining) != 1 || remaining[0].Fix != FixSync {
+		t.Errorf("after repair = %v, want only the issue needing sync", remaining)
+	}
+}
+
+func TestScanFolder_Systems(t *testing.T) {
+	t.Setenv("HOME", t.TempDir()) // no globally installed systems
+	dir := t.TempDir()
+	writeSkill := func(rel string) {
+		t.Helper()
+		path := filepath.Join(dir, rel)
+		if err := os.MkdirAll(path, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		content := "---\nname: " + filepath.Base(rel) + "\ndescription: test\n---\n"
+		if err := os.WriteFile(filepath.Join(path, "SKILL.md"), []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	writeSkill(".agents/skills/shared")
+	writeSkill(".agents/skills/unlinked")
+	writeSkill(".claude/skills/claude-only")
+	if err := os.Symlink("../../.agents/skills/shared", filepath.Join(dir, ".claude/skills/shared")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	assets, err := NewOrchestrator().ScanFolder(dir)
+	if err != nil {
+		t.Fatalf("ScanFolder() error = %v", err)
+	}
+	got := make(map[string][]string)
+	for _, a := range assets[asset.KindSkill] {
+		got[a.Name] = a.Systems
+	}
+
+	has := func(name, sys string) bool {
+		for _, s := range got[name] {
+			if s == sys {
+				return true
+			}
+		}
+		return false
+	}
+	if !has("shared", "claude-code") || !has("shared", "codex") {
+		t.Errorf("shared systems = %v, want claude-code and codex", got["shared"])
+	}
+	if has("unlinked", "claude-code") || !has("unlinked", "codex") {
+		t.Errorf("unlinked systems = %v, want codex but not claude-code", got["unlinked"])
+	}
+	if s := got["claude-only"]; len(s) != 1 || s[0] != "claude-code" {
+		t.Errorf("claude-only systems = %v, want [claude-code]", s)
+	}
+	if has("shared", "cursor") {
+		t.Errorf("shared systems = %v, want no cursor", got["shared"])
+	}
+}
@@ -0,0 +1,241 @@
+package core
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"slices"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// LintSeverity is how serious a registry lint issue is.
+type LintSeverity string
+
+const (
+	LintError   LintSeverity = "error"   // the manifest is broken for its users
+	LintWarning LintSeverity = "warning" // works, but worth fixing before publishing
+)
+
+// LintIssue is one problem found by LintManifest.
+type LintIssue struct {
+	Severity LintSeverity `json:"severity"`
+	Message  string       `json:"message"`
+}
+
+// LintResult is the outcome of linting a registry manifest.
+type LintResult struct {
+	Name   string      `json:"name,omitempty"`
+	Issues []LintIssue `json:"issues"`
+	// SourcesChecked is false when offline mode kept the source
+	// repositories from being contacted.
+	SourcesChecked bool `json:"sourcesChecked"`
+}
+
+// Errors returns the number of error issues in r.
+func (r *LintResult) Errors() int {
+	n := 0
+	for _, issue := range r.Issues {
+		if issue.Severity == LintError {
+			n++
+		}
+	}
+	return n
+}
+
+// mcpTransports are the transports a remote MCP's "type" may name.
+var mcpTransports = []string{"http", "sse", "streamable-http"}
+
+// mcpPlaceholderRe matches {{...}} placeholders in an MCP's command and
+// args; only ProjectDirPlaceholder is expanded.
+var mcpPlaceholderRe = regexp.MustCompile(`\{\{[^{}]*\}\}`)
+
+// LintRegistry reads the registry manifest at location and lints it. The
+// location is a registry directory or manifest file on disk, a hosted
+// manifest URL, or a git URL, which is cloned. A manifest that fails to
+// parse is reported as an error issue; failing to get at it at all returns
+// an error.
+func LintRegistry(location string, overrides map[string]string) (*LintResult, error) {
+	location = strings.TrimSpace(location)
+	if location == "" {
+		return nil, fmt.Errorf("registry location is required")
+	}
+
+	var manifest *RegistryManifest
+	var parseErr error
+	switch path := expandPath(location); {
+	case IsManifestURL(location):
+		data, _, err := fetchManifest(httpRegistrySource{URL: location}, false)
+		if err != nil {
+			return nil, err
+		}
+		manifest, parseErr = parseManifestFile(manifestFileFor(location), data)
+	case dirExists(path):
+		if _, err := findManifest(path); err != nil {
+			return nil, err
+		}
+		manifest, parseErr = readManifest(path)
+	case pathExists(path):
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading manifest: %w", err)
+		}
+		manifest, parseErr = parseManifestFile(filepath.Base(path), data)
+	case IsLocalRegistryPath(location):
+		return nil, fmt.Errorf("%s not found", location)
+	default:
+		tmpDir, err := os.MkdirTemp("", "duckrow-registry-*")
+		if err != nil {
+			return nil, fmt.Errorf("creating temp dir: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(tmpDir) }()
+		if err := gitClone(location, "", tmpDir, CurrentTimeouts().Clone); err != nil {
+			return nil, fmt.Errorf("cloning registry: %w", err)
+		}
+		if _, err := findManifest(tmpDir); err != nil {
+			return nil, err
+		}
+		manifest, parseErr = readManifest(tmpDir)
+	}
+	if parseErr != nil {
+		return &LintResult{Issues: []LintIssue{{Severity: LintError, Message: parseErr.Error()}}}, nil
+	}
+	return LintManifest(manifest, overrides), nil
+}
+
+// LintManifest checks a registry manifest more thoroughly than
+// ParseManifest. Errors: a missing name, entries that share a name within
+// a kind, sources not in host/owner/repo/path form or whose repository
+// can't be reached, and MCPs with an invalid command, url, or transport,
+// or an unknown placeholder.
+// Warnings: entries not pinned to a commit, and everything ParseManifest
+// warns about. overrides are the clone URL overrides from the config;
+// sources are not contacted in offline mode.
+func LintManifest(raw *RegistryManifest, overrides map[string]string) *LintResult {
+	res := &LintResult{Name: raw.Name, SourcesChecked: !Offline()}
+	reported := make(map[string]bool)
+	report := func(sev LintSeverity, format string, args ...any) {
+		msg := fmt.Sprintf(format, args...)
+		if !reported[msg] {
+			reported[msg] = true
+			res.Issues = append(res.Issues, LintIssue{Severity: sev, Message: msg})
+		}
+	}
+
+	pm, err := ParseManifest(raw)
+	if err != nil {
+		report(LintError, "%v", err)
+		return res
+	}
+	if raw.Name == "" {
+		report(LintError, "manifest is missing the required 'name' field")
+	}
+
+	for _, kind := range asset.Kinds() {
+		seen := make(map[string]bool)
+		for _, e := range pm.Entries[kind] {
+			if e.Name != "" && seen[e.Name] {
+				report(LintError, "%s %q is listed more than once", kind, e.Name)
+			}
+			seen[e.Name] = true
+		}
+	}
+
+	// Repositories to check, in the order their first entry appears. Entries
+	// with a cloneUrl are checked through their mirror.
+	type lintRepo struct {
+		repo   string
+		mirror string
+	}
+	var repos []lintRepo
+	repoEntries := make(map[lintRepo][]string)
+	for _, kind := range sourceBasedKinds() {
+		for _, e := range pm.Entries[kind] {
+			if e.Source == "" {
+				continue
+			}
+			if !isCanonicalSource(e.Source) {
+				report(LintError, "%s %q has non-canonical source %q (expected host/owner/repo/path format)", kind, e.Name, e.Source)
+				continue
+			}
+			if e.Commit == "" {
+				report(LintWarning, "%s %q is not pinned to a commit", kind, e.Name)
+			}
+			if e.Version != "" {
+				if _, err := ParseVersion(e.Version); err != nil {
+					report(LintError, "%s %q has %v", kind, e.Name, err)
+				}
+			}
+			rk := lintRepo{repo: repoKey(e.Source), mirror: e.CloneURL}
+			if _, ok := repoEntries[rk]; !ok {
+				repos = append(repos, rk)
+			}
+			repoEntries[rk] = append(repoEntries[rk], fmt.Sprintf("%s %q", kind, e.Name))
+		}
+	}
+
+	for _, e := range pm.Entries[asset.KindMCP] {
+		meta, ok := e.Meta.(asset.MCPMeta)
+		if !ok {
+			continue
+		}
+		for _, msg := range lintMCP(meta) {
+			report(LintError, "MCP %q %s", e.Name, msg)
+		}
+	}
+
+	// ParseManifest's own checks for the same problems are already
+	// reported above as errors.
+	for _, w := range pm.Warnings {
+		if strings.Contains(w, "non-canonical source") || strings.Contains(w, "'command' and 'url'") {
+			continue
+		}
+		report(LintWarning, "%s", w)
+	}
+
+	if res.SourcesChecked {
+		for _, rk := range repos {
+			host, owner, repo, _, err := ParseLockSource(rk.repo)
+			if err != nil {
+				continue
+			}
+			cloneURL := lockCloneURL(host, owner, repo, overrides, rk.mirror)
+			if _, err := resolveRef(cloneURL, host, owner, repo, ""); err != nil {
+				report(LintError, "source repository %s can't be reached (used by %s): %v", rk.repo, strings.Join(repoEntries[rk], ", "), err)
+			}
+		}
+	}
+	return res
+}
+
+// lintMCP returns what is wrong with an MCP entry's server definition.
+func lintMCP(meta asset.MCPMeta) []string {
+	var problems []string
+	switch {
+	case !meta.IsStdio() && !meta.IsRemote():
+		problems = append(problems, "has neither 'command' nor 'url' (one is required)")
+	case meta.IsStdio() && meta.IsRemote():
+		problems = append(problems, "has both 'command' and 'url' (only one allowed)")
+	case meta.IsStdio() && meta.Transport != "" && meta.Transport != "stdio":
+		problems = append(problems, fmt.Sprintf("runs a command but sets transport %q (transports are for 'url' servers)", meta.Transport))
+	}
+	for _, arg := range append([]string{meta.Command}, meta.Args...) {
+		for _, p := range mcpPlaceholderRe.FindAllString(arg, -1) {
+			if p != ProjectDirPlaceholder {
+				problems = append(problems, fmt.Sprintf("uses unknown placeholder %q (only %s is expanded)", p, ProjectDirPlaceholder))
+			}
+		}
+	}
+	if meta.IsRemote() {
+		if u, err := url.Parse(meta.URL); err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			problems = append(problems, fmt.Sprintf("has invalid url %q (expected an http or https URL)", meta.URL))
+		}
+		if meta.Transport != "" && !slices.Contains(mcpTransports, meta.Transport) {
+			problems = append(problems, fmt.Sprintf("has unknown transport %q (expected %s)", meta.Transport, strings.Join(mcpTransports, ", ")))
+		}
+	}
+	return problems
+}
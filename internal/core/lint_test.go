@@ -0,0 +1,99 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLintManifest_Sources(t *testing.T) {
+	repoDir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(repoDir, "skills", "ok"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "skills", "ok", "SKILL.md"), []byte("---\nname: ok\n---\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	setupTestGitRepoInDir(t, repoDir)
+
+	raw, err := parseManifestFile("duckrow.json", []byte(`{
+  "name": "acme",
+  "assets": {
+    "skill": [
+      {"name": "ok", "source": "example.com/acme/skills/skills/ok"},
+      {"name": "gone", "source": "example.com/acme/missing/skills/gone", "commit": "0123456789abcdef0123456789abcdef01234567"}
+    ]
+  }
+}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+	overrides := map[string]string{
+		"acme/skills":  repoDir,
+		"acme/missing": filepath.Join(t.TempDir(), "missing"),
+	}
+
+	res := LintManifest(raw, overrides)
+	if !res.SourcesChecked {
+		t.Fatal("SourcesChecked = false, want sources checked when online")
+	}
+	if res.Errors() != 1 {
+		t.Fatalf("Errors() = %d, want 1; issues: %+v", res.Errors(), res.Issues)
+	}
+	var unreachable, unpinned bool
+	for _, issue := range res.Issues {
+		switch {
+		case issue.Severity == LintError && strings.Contains(issue.Message, "example.com/acme/missing can't be reached (used by skill \"gone\")"):
+			unreachable = true
+		case issue.Severity == LintWarning && issue.Message == `skill "ok" is not pinned to a commit`:
+			unpinned = true
+		}
+	}
+	if !unreachable || !unpinned {
+		t.Errorf("issues = %+v, want the missing repository as an error and the unpinned skill as a warning", res.Issues)
+	}
+
+	// Offline, sources are left alone.
+	SetOffline(true)
+	t.Cleanup(func() { SetOffline(false) })
+	res = LintManifest(raw, overrides)
+	if res.SourcesChecked || res.Errors() != 0 {
+		t.Errorf("offline LintManifest() = %+v, want no source checks", res)
+	}
+}
+
+func TestLintMCP(t *testing.T) {
+	tests := []struct {
+		name string
+		meta string
+		want string
+	}{
+		{"stdio", `{"name": "m", "command": "npx"}`, ""},
+		{"remote", `{"name": "m", "url": "https://x.example.com/mcp", "type": "sse"}`, ""},
+		{"neither", `{"name": "m"}`, "neither 'command' nor 'url'"},
+		{"transport on stdio", `{"name": "m", "command": "npx", "type": "http"}`, `sets transport "http"`},
+		{"unknown transport", `{"name": "m", "url": "https://x.example.com", "type": "ws"}`, `unknown transport "ws"`},
+		{"relative url", `{"name": "m", "url": "/mcp", "type": "http"}`, `invalid url "/mcp"`},
+		{"project dir", `{"name": "m", "command": "python", "args": ["{{projectDir}}/server.py"]}`, ""},
+		{"unknown placeholder", `{"name": "m", "command": "python", "args": ["{{repoRoot}}/server.py"]}`, `unknown placeholder "{{repoRoot}}"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			raw, err := parseManifestFile("duckrow.json", []byte(`{"name": "acme", "assets": {"mcp": [`+tt.meta+`]}}`))
+			if err != nil {
+				t.Fatal(err)
+			}
+			res := LintManifest(raw, nil)
+			got := ""
+			for _, issue := range res.Issues {
+				if issue.Severity == LintError {
+					got = issue.Message
+				}
+			}
+			if tt.want == "" && got != "" || !strings.Contains(got, tt.want) {
+				t.Errorf("LintManifest() error = %q, want one containing %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,165 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+const (
+	projectDuckrowDir = ".duckrow"
+	localLockFileName = "local.lock.json"
+)
+
+// LockOrigin identifies which lock layer an asset entry came from.
+type LockOrigin string
+
+const (
+	// OriginTeam is the committed duckrow.lock.json shared by the team.
+	OriginTeam LockOrigin = "team"
+	// OriginLocal is the per-user .duckrow/local.lock.json (gitignored).
+	OriginLocal LockOrigin = "local"
+)
+
+// LocalLockFilePath returns the full path to the per-user local lock file
+// in the given project directory.
+func LocalLockFilePath(dir string) string {
+	return filepath.Join(dir, projectDuckrowDir, localLockFileName)
+}
+
+// ReadLocalLockFile reads the per-user local lock file.
+// Returns nil, nil if the file does not exist.
+func ReadLocalLockFile(dir string) (*LockFile, error) {
+	lf, err := readLockFileAt(LocalLockFilePath(dir))
+	if err != nil {
+		return nil, fmt.Errorf("local lock: %w", err)
+	}
+	return lf, nil
+}
+
+// WriteLocalLockFile writes the per-user local lock file atomically and makes
+// sure it is listed in the project's .gitignore.
+func WriteLocalLockFile(dir string, lf *LockFile) error {
+	if err := prepareLocalLockDir(dir); err != nil {
+		return err
+	}
+	return writeLockFileAt(LocalLockFilePath(dir), lf)
+}
+
+// AddOrUpdateLocalAsset upserts a locked asset by (kind, name) in the local lock.
+func AddOrUpdateLocalAsset(dir string, entry asset.LockedAsset) error {
+	if err := prepareLocalLockDir(dir); err != nil {
+		return err
+	}
+	return addOrUpdateAssetAt(LocalLockFilePath(dir), entry)
+}
+
+// RemoveLocalAssetEntry removes a locked asset by (kind, name) from the local lock.
+// No-op if the local lock file does not exist or the asset is not found.
+func RemoveLocalAssetEntry(dir string, kind asset.Kind, name string) error {
+	return removeAssetEntryAt(LocalLockFilePath(dir), kind, name)
+}
+
+// RemoveLayeredAssetEntry removes a locked asset from both the team and
+// local lock files, along with its attestation, which would no longer
+// verify.
+func RemoveLayeredAssetEntry(dir string, kind asset.Kind, name string) error {
+	if err := RemoveAssetEntry(dir, kind, name); err != nil {
+		return err
+	}
+	if err := RemoveLocalAssetEntry(dir, kind, name); err != nil {
+		return err
+	}
+	return RemoveAttestation(dir, kind, name)
+}
+
+// ReadLayeredLockFile reads the team lock and layers the local lock on top.
+// Local entries are added to the result and override team entries with the
+// same (kind, name), and a local defaultSystems setting overrides the team's. Use Origin on the result to find where an entry came from.
+// Returns nil, nil if neither lock file exists.
+func ReadLayeredLockFile(dir string) (*LockFile, error) {
+	team, err := ReadLockFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	local, err := ReadLocalLockFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	if team == nil && local == nil {
+		return nil, nil
+	}
+
+	merged := &LockFile{
+		LockVersion: currentLockVersion,
+		origins:     make(map[string]LockOrigin),
+	}
+	index := make(map[string]int)
+
+	add := func(lf *LockFile, origin LockOrigin) {
+		if lf == nil {
+			return
+		}
+		if len(lf.DefaultSystems) > 0 {
+			merged.DefaultSystems = lf.DefaultSystems
+		}
+		for _, rule := range lf.Exclude {
+			if !slices.Contains(merged.Exclude, rule) {
+				merged.Exclude = append(merged.Exclude, rule)
+			}
+		}
+		for _, a := range lf.Assets {
+			key := lockOriginKey(a.Kind, a.Name)
+			if i, ok := index[key]; ok {
+				merged.Assets[i] = a
+			} else {
+				index[key] = len(merged.Assets)
+				merged.Assets = append(merged.Assets, a)
+			}
+			merged.origins[key] = origin
+		}
+	}
+	add(team, OriginTeam)
+	add(local, OriginLocal)
+
+	sort.SliceStable(merged.Assets, func(i, j int) bool {
+		if merged.Assets[i].Kind != merged.Assets[j].Kind {
+			return merged.Assets[i].Kind < merged.Assets[j].Kind
+		}
+		return merged.Assets[i].Name < merged.Assets[j].Name
+	})
+	merged.populateLegacyFields()
+	return merged, nil
+}
+
+// Origin returns the lock layer a (kind, name) entry came from.
+// Lock files not produced by ReadLayeredLockFile report OriginTeam.
+func (lf *LockFile) Origin(kind asset.Kind, name string) LockOrigin {
+	if lf == nil || lf.origins == nil {
+		return OriginTeam
+	}
+	if o, ok := lf.origins[lockOriginKey(kind, name)]; ok {
+		return o
+	}
+	return OriginTeam
+}
+
+func lockOriginKey(kind asset.Kind, name string) string {
+	return string(kind) + "/" + name
+}
+
+// prepareLocalLockDir creates the project .duckrow directory and gitignores
+// the local lock file so personal entries never end up in the team repo.
+func prepareLocalLockDir(dir string) error {
+	if err := os.MkdirAll(filepath.Join(dir, projectDuckrowDir), 0o755); err != nil {
+		return fmt.Errorf("creating %s directory: %w", projectDuckrowDir, err)
+	}
+	if err := ensureGitignoreEntry(dir, projectDuckrowDir+"/"+localLockFileName); err != nil {
+		return fmt.Errorf("updating .gitignore: %w", err)
+	}
+	return nil
+}
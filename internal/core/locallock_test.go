@@ -0,0 +1,164 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestReadLayeredLockFile_NeitherExists(t *testing.T) {
+	lf, err := ReadLayeredLockFile(t.TempDir())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if lf != nil {
+		t.Fatalf("expected nil lock file, got %+v", lf)
+	}
+}
+
+func TestReadLayeredLockFile_MergesLayers(t *testing.T) {
+	dir := t.TempDir()
+
+	team := []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "shared", Source: "github.com/org/repo/shared", Commit: "aaa"},
+		{Kind: asset.KindSkill, Name: "overridden", Source: "github.com/org/repo/overridden", Commit: "bbb"},
+	}
+	for _, a := range team {
+		if err := AddOrUpdateAsset(dir, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	local := []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "overridden", Source: "github.com/me/fork/overridden", Commit: "ccc"},
+		{Kind: asset.KindMCP, Name: "personal-db", Data: map[string]any{"registry": "me"}},
+	}
+	for _, a := range local {
+		if err := AddOrUpdateLocalAsset(dir, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	lf, err := ReadLayeredLockFile(dir)
+	if err != nil {
+		t.Fatalf("ReadLayeredLockFile() error = %v", err)
+	}
+	if len(lf.Assets) != 3 {
+		t.Fatalf("len(Assets) = %d, want 3", len(lf.Assets))
+	}
+
+	overridden := FindLockedAsset(lf, asset.KindSkill, "overridden")
+	if overridden == nil || overridden.Commit != "ccc" {
+		t.Errorf("overridden = %+v, want local entry with commit ccc", overridden)
+	}
+
+	tests := []struct {
+		kind asset.Kind
+		name string
+		want LockOrigin
+	}{
+		{asset.KindSkill, "shared", OriginTeam},
+		{asset.KindSkill, "overridden", OriginLocal},
+		{asset.KindMCP, "personal-db", OriginLocal},
+		{asset.KindSkill, "missing", OriginTeam},
+	}
+	for _, tt := range tests {
+		if got := lf.Origin(tt.kind, tt.name); got != tt.want {
+			t.Errorf("Origin(%s, %s) = %q, want %q", tt.kind, tt.name, got, tt.want)
+		}
+	}
+
+	if len(lf.MCPs) != 1 {
+		t.Errorf("len(MCPs) = %d, want 1 (legacy fields should be populated)", len(lf.MCPs))
+	}
+
+	// The team lock itself is untouched by local installs.
+	teamLF, err := ReadLockFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := FindLockedAsset(teamLF, asset.KindSkill, "overridden"); got == nil || got.Commit != "bbb" {
+		t.Errorf("team entry = %+v, want commit bbb", got)
+	}
+}
+
+func TestLockFile_WithTag(t *testing.T) {
+	dir := t.TempDir()
+	if err := AddOrUpdateAsset(dir, asset.LockedAsset{Kind: asset.KindSkill, Name: "go-vet", Source: "github.com/org/repo/go-vet", Commit: "aaa", Tags: []string{"backend"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddOrUpdateAsset(dir, asset.LockedAsset{Kind: asset.KindSkill, Name: "css-lint", Source: "github.com/org/repo/css-lint", Commit: "bbb"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddOrUpdateLocalAsset(dir, asset.LockedAsset{Kind: asset.KindMCP, Name: "db", Tags: []string{"backend"}}); err != nil {
+		t.Fatal(err)
+	}
+	lf, err := ReadLayeredLockFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tagged := lf.WithTag("backend")
+	if len(tagged.Assets) != 2 || FindLockedAsset(tagged, asset.KindSkill, "css-lint") != nil {
+		t.Errorf("WithTag() assets = %+v, want go-vet and db", tagged.Assets)
+	}
+	if got := tagged.Origin(asset.KindMCP, "db"); got != OriginLocal {
+		t.Errorf("Origin(mcp, db) = %q, want %q", got, OriginLocal)
+	}
+	if len(lf.Assets) != 3 {
+		t.Errorf("WithTag() changed the original lock: %d assets", len(lf.Assets))
+	}
+}
+
+func TestAddOrUpdateLocalAsset_Gitignore(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".gitignore"), []byte("node_modules/"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	entry := asset.LockedAsset{Kind: asset.KindSkill, Name: "mine", Source: "github.com/me/repo", Commit: "abc"}
+	if err := AddOrUpdateLocalAsset(dir, entry); err != nil {
+		t.Fatalf("AddOrUpdateLocalAsset() error = %v", err)
+	}
+	// Second write must not duplicate the .gitignore entry.
+	if err := AddOrUpdateLocalAsset(dir, entry); err != nil {
+		t.Fatalf("AddOrUpdateLocalAsset() error = %v", err)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := strings.Count(string(data), ".duckrow/local.lock.json"); got != 1 {
+		t.Errorf(".gitignore contains local lock %d times, want 1:\n%s", got, data)
+	}
+	if !strings.HasPrefix(string(data), "node_modules/\n") {
+		t.Errorf(".gitignore lost existing content:\n%s", data)
+	}
+}
+
+func TestRemoveLayeredAssetEntry(t *testing.T) {
+	dir := t.TempDir()
+	entry := asset.LockedAsset{Kind: asset.KindAgent, Name: "reviewer", Source: "github.com/org/agents", Commit: "abc"}
+	if err := AddOrUpdateAsset(dir, entry); err != nil {
+		t.Fatal(err)
+	}
+	if err := AddOrUpdateLocalAsset(dir, entry); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := RemoveLayeredAssetEntry(dir, asset.KindAgent, "reviewer"); err != nil {
+		t.Fatalf("RemoveLayeredAssetEntry() error = %v", err)
+	}
+
+	lf, err := ReadLayeredLockFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FindLockedAsset(lf, asset.KindAgent, "reviewer") != nil {
+		t.Error("expected entry to be removed from both layers")
+	}
+}
@@ -0,0 +1,183 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// LockIssue is a single inconsistency between a project's lock file and the
+// assets installed in it.
+type LockIssue struct {
+	Kind    asset.Kind
+	Name    string
+	Problem string
+}
+
+func (i LockIssue) String() string {
+	return fmt.Sprintf("%s %q: %s", i.Kind, i.Name, i.Problem)
+}
+
+// CheckLockConsistency compares the (layered) lock file with what is
+// installed in dir. It reports locked skills and agents that are not
+// installed or not pinned to a commit, skills whose files drifted from the
+// recorded file list, and skills in the canonical directory that are not in
+// the lock. Agents and skills in system directories may be hand-written, so
+// they are not reported as unlocked. MCP entries live in system config files
+// and are not checked.
+func CheckLockConsistency(dir string) ([]LockIssue, error) {
+	lf, err := ReadLayeredLockFile(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	installed, err := NewOrchestrator().ScanFolder(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []LockIssue
+	for _, kind := range []asset.Kind{asset.KindSkill, asset.KindAgent, asset.KindCommand, asset.KindRule} {
+		locked := make(map[string]bool)
+		for _, a := range AssetsByKind(lf, kind) {
+			locked[a.Name] = true
+
+			if a.Commit == "" {
+				issues = append(issues, LockIssue{kind, a.Name, "not pinned to a commit"})
+			}
+			if !isAssetPresent(a, dir) {
+				issues = append(issues, LockIssue{kind, a.Name, "in lock file but not installed"})
+				continue
+			}
+			if kind == asset.KindSkill {
+				missing, unexpected, err := VerifySkillFiles(dir, a)
+				if err != nil {
+					return nil, err
+				}
+				if len(missing) > 0 {
+					issues = append(issues, LockIssue{kind, a.Name,
+						"missing files: " + strings.Join(missing, ", ")})
+				}
+				if len(unexpected) > 0 {
+					issues = append(issues, LockIssue{kind, a.Name,
+						"files not in lock: " + strings.Join(unexpected, ", ")})
+				}
+			}
+		}
+
+		if kind != asset.KindSkill {
+			continue
+		}
+		canonical := filepath.Join(dir, canonicalSkillsDir)
+		for _, a := range installed[kind] {
+			if !locked[a.Name] && filepath.Dir(a.Path) == canonical {
+				issues = append(issues, LockIssue{kind, a.Name, "installed but not in lock file"})
+			}
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Kind != issues[j].Kind {
+			return issues[i].Kind < issues[j].Kind
+		}
+		return issues[i].Name < issues[j].Name
+	})
+	return issues, nil
+}
+
+// CheckLockPolicy reports locked assets that break the project's policies:
+// entries matched by the project's exclude rules, and skills, agents,
+// commands, and rules whose source host is outside the allowedHosts
+// setting. MCP server URLs are not in the lock file; they are checked when
+// an MCP is installed or synced.
+func CheckLockPolicy(dir string) ([]LockIssue, error) {
+	lf, err := ReadLayeredLockFile(dir)
+	if err != nil || lf == nil {
+		return nil, err
+	}
+	excludes := ExcludeRules(lf.Exclude)
+	allowed := AllowedHosts()
+
+	var issues []LockIssue
+	for _, a := range lf.Assets {
+		if excludes.Match(a.Kind, a.Name) {
+			issues = append(issues, LockIssue{a.Kind, a.Name, "excluded by the project's exclude rules"})
+		}
+		if a.Kind == asset.KindMCP || len(allowed) == 0 {
+			continue
+		}
+		host, _, _, _, err := ParseLockSource(a.Source)
+		if err != nil {
+			continue
+		}
+		if !hostAllowed(strings.ToLower(host), allowed) {
+			issues = append(issues, LockIssue{a.Kind, a.Name, fmt.Sprintf("source host %s is not in allowedHosts", host)})
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Kind != issues[j].Kind {
+			return issues[i].Kind < issues[j].Kind
+		}
+		return issues[i].Name < issues[j].Name
+	})
+	return issues, nil
+}
+
+// CheckFrozenSync reports what would keep a sync of lf into dir from
+// installing exactly what lf records, which `sync --frozen` refuses:
+// skills and agents not pinned to a commit, since sync installs whatever
+// is latest; MCPs whose registry config no longer matches their recorded
+// config hash; and skills in the canonical directory that are not in lf.
+// mcpConfigHash returns an MCP's config hash in the registries today; when
+// nil, MCPs are not checked.
+func CheckFrozenSync(dir string, lf *LockFile, mcpConfigHash func(asset.LockedAsset) (string, error)) ([]LockIssue, error) {
+	var issues []LockIssue
+	locked := make(map[string]bool)
+	for _, a := range lf.Assets {
+		switch a.Kind {
+		case asset.KindSkill, asset.KindAgent, asset.KindCommand, asset.KindRule:
+			if a.Kind == asset.KindSkill {
+				locked[a.Name] = true
+			}
+			if a.Commit == "" {
+				issues = append(issues, LockIssue{a.Kind, a.Name, "not pinned to a commit"})
+			}
+		case asset.KindMCP:
+			recorded, _ := a.Data["configHash"].(string)
+			if recorded == "" || mcpConfigHash == nil {
+				continue
+			}
+			hash, err := mcpConfigHash(a)
+			if err != nil {
+				issues = append(issues, LockIssue{a.Kind, a.Name, fmt.Sprintf("cannot resolve: %v", err)})
+				continue
+			}
+			if hash != recorded {
+				issues = append(issues, LockIssue{a.Kind, a.Name, "registry config differs from the recorded config hash"})
+			}
+		}
+	}
+
+	installed, err := NewOrchestrator().ScanFolder(dir)
+	if err != nil {
+		return nil, err
+	}
+	canonical := filepath.Join(dir, canonicalSkillsDir)
+	for _, a := range installed[asset.KindSkill] {
+		if !locked[a.Name] && filepath.Dir(a.Path) == canonical {
+			issues = append(issues, LockIssue{asset.KindSkill, a.Name, "installed but not in lock file"})
+		}
+	}
+
+	sort.SliceStable(issues, func(i, j int) bool {
+		if issues[i].Kind != issues[j].Kind {
+			return issues[i].Kind < issues[j].Kind
+		}
+		return issues[i].Name < issues[j].Name
+	})
+	return issues, nil
+}
@@ -0,0 +1,167 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// writeCanonicalSkill creates an installed skill in the canonical directory.
+func writeCanonicalSkill(t *testing.T, dir, name string, files ...string) {
+	t.Helper()
+	skillDir := filepath.Join(dir, canonicalSkillsDir, name)
+	if err := os.MkdirAll(skillDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	md := "---\nname: " + name + "\ndescription: test\n---\n"
+	if err := os.WriteFile(filepath.Join(skillDir, "SKILL.md"), []byte(md), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range files {
+		if err := os.WriteFile(filepath.Join(skillDir, f), []byte("x"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+func TestCheckLockConsistency(t *testing.T) {
+	dir := t.TempDir()
+
+	writeCanonicalSkill(t, dir, "ok")
+	writeCanonicalSkill(t, dir, "drifted", "extra.md")
+	writeCanonicalSkill(t, dir, "unlocked")
+
+	for _, a := range []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "ok", Source: "github.com/o/r/ok", Commit: "abc"},
+		{Kind: asset.KindSkill, Name: "drifted", Source: "github.com/o/r/drifted", Commit: "abc",
+			Data: map[string]any{"files": []string{"SKILL.md"}}},
+		{Kind: asset.KindSkill, Name: "missing", Source: "github.com/o/r/missing", Commit: "abc"},
+		{Kind: asset.KindAgent, Name: "reviewer", Source: "github.com/o/agents"},
+		{Kind: asset.KindMCP, Name: "db"},
+	} {
+		if err := AddOrUpdateAsset(dir, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	issues, err := CheckLockConsistency(dir)
+	if err != nil {
+		t.Fatalf("CheckLockConsistency() error = %v", err)
+	}
+
+	var got []string
+	for _, i := range issues {
+		got = append(got, i.String())
+	}
+	want := []string{
+		`agent "reviewer": not pinned to a commit`,
+		`agent "reviewer": in lock file but not installed`,
+		`skill "drifted": files not in lock: extra.md`,
+		`skill "missing": in lock file but not installed`,
+		`skill "unlocked": installed but not in lock file`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCheckLockConsistency_Clean(t *testing.T) {
+	issues, err := CheckLockConsistency(t.TempDir())
+	if err != nil {
+		t.Fatalf("CheckLockConsistency() error = %v", err)
+	}
+	if len(issues) != 0 {
+		t.Errorf("issues = %v, want none for an empty folder", issues)
+	}
+}
+
+func TestCheckLockPolicy(t *testing.T) {
+	dir := t.TempDir()
+	for _, a := range []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "ok", Source: "github.com/o/r/ok", Commit: "abc"},
+		{Kind: asset.KindSkill, Name: "legacy-lint", Source: "github.com/o/r/legacy-lint", Commit: "abc"},
+		{Kind: asset.KindAgent, Name: "reviewer", Source: "gitlab.com/o/agents", Commit: "abc"},
+		{Kind: asset.KindMCP, Name: "db"},
+	} {
+		if err := AddOrUpdateAsset(dir, a); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := AddExcludes(dir, []string{"skill:legacy-*"}, true); err != nil {
+		t.Fatal(err)
+	}
+	SetAllowedHosts([]string{"GitHub.com"})
+	t.Cleanup(func() { SetAllowedHosts(nil) })
+
+	issues, err := CheckLockPolicy(dir)
+	if err != nil {
+		t.Fatalf("CheckLockPolicy() error = %v", err)
+	}
+	var got []string
+	for _, i := range issues {
+		got = append(got, i.String())
+	}
+	want := []string{
+		`agent "reviewer": source host gitlab.com is not in allowedHosts`,
+		`skill "legacy-lint": excluded by the project's exclude rules`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestCheckFrozenSync(t *testing.T) {
+	dir := t.TempDir()
+	writeCanonicalSkill(t, dir, "ok")
+	writeCanonicalSkill(t, dir, "unlocked")
+
+	lf := &LockFile{Assets: []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "ok", Source: "github.com/o/r/ok", Commit: "abc"},
+		// Not installed yet: sync installs it, so that is no issue.
+		{Kind: asset.KindSkill, Name: "missing", Source: "github.com/o/r/missing", Commit: "abc"},
+		{Kind: asset.KindAgent, Name: "reviewer", Source: "github.com/o/agents"},
+		{Kind: asset.KindMCP, Name: "db", Data: map[string]any{"configHash": "old"}},
+		{Kind: asset.KindMCP, Name: "docs", Data: map[string]any{"configHash": "same"}},
+		{Kind: asset.KindMCP, Name: "unhashed"},
+	}}
+	hashes := map[string]string{"db": "new", "docs": "same", "unhashed": "any"}
+	mcpConfigHash := func(a asset.LockedAsset) (string, error) { return hashes[a.Name], nil }
+
+	issues, err := CheckFrozenSync(dir, lf, mcpConfigHash)
+	if err != nil {
+		t.Fatalf("CheckFrozenSync() error = %v", err)
+	}
+	var got []string
+	for _, i := range issues {
+		got = append(got, i.String())
+	}
+	want := []string{
+		`agent "reviewer": not pinned to a commit`,
+		`mcp "db": registry config differs from the recorded config hash`,
+		`skill "unlocked": installed but not in lock file`,
+	}
+	if strings.Join(got, "\n") != strings.Join(want, "\n") {
+		t.Errorf("issues:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+}
+
+func TestGitHookScript(t *testing.T) {
+	pre := GitHookScript("pre-commit", "")
+	if !strings.Contains(pre, hookMarker) || !strings.Contains(pre, "exec duckrow hook check\n") {
+		t.Errorf("pre-commit script:\n%s", pre)
+	}
+	if !strings.Contains(pre, "git diff --cached") {
+		t.Error("pre-commit hook should only run for staged skill or lock changes")
+	}
+
+	push := GitHookScript("pre-push", "apps/web")
+	if strings.Contains(push, "git diff --cached") {
+		t.Error("pre-push hook should always run")
+	}
+	if !strings.Contains(push, "duckrow hook check --dir 'apps/web'") {
+		t.Errorf("pre-push script should target the project subdirectory:\n%s", push)
+	}
+}
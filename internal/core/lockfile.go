@@ -1,16 +1,17 @@
 package core
 
 import (
-	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strings"
 
 	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/schema"
 )
 
 const (
@@ -25,12 +26,25 @@ const (
 // and populateLegacyFields(). They are NOT serialized — the canonical data
 // lives in Assets.
 type LockFile struct {
-	LockVersion int                 `json:"lockVersion"`
-	Assets      []asset.LockedAsset `json:"assets"`
+	LockVersion int `json:"lockVersion"`
+
+	// DefaultSystems names the systems to target when --systems is not
+	// given. Empty means the built-in defaults; see DefaultSystems.
+	DefaultSystems []string `json:"defaultSystems,omitempty"`
+
+	// Exclude lists registry entries the project never wants suggested or
+	// installed in bulk, as [kind:]pattern rules; see ExcludeRules.
+	Exclude []string `json:"exclude,omitempty"`
+
+	Assets []asset.LockedAsset `json:"assets"`
 
 	// Computed compat fields — populated by ReadLockFile / populateLegacyFields.
 	Skills []LockedSkill `json:"-"`
 	MCPs   []LockedMCP   `json:"-"`
+
+	// origins records which lock layer each asset came from. Only set on
+	// lock files returned by ReadLayeredLockFile; see Origin.
+	origins map[string]LockOrigin
 }
 
 // LockFilePath returns the full path to the lock file in the given directory.
@@ -38,6 +52,13 @@ func LockFilePath(dir string) string {
 	return filepath.Join(dir, lockFileName)
 }
 
+// GlobalLockDir returns the directory of the global lock file,
+// ~/.duckrow/duckrow.lock.json, which records the assets installed for the
+// user with --global rather than into a project.
+func GlobalLockDir() string {
+	return GlobalConfigDir()
+}
+
 // populateLegacyFields rebuilds the Skills and MCPs computed fields from Assets.
 // Called by ReadLockFile after parsing or migrating.
 func (lf *LockFile) populateLegacyFields() {
@@ -49,7 +70,11 @@ func (lf *LockFile) populateLegacyFields() {
 // Returns nil, nil if the file does not exist.
 // Handles v1/v2 formats by migrating them to v3 in memory.
 func ReadLockFile(dir string) (*LockFile, error) {
-	path := LockFilePath(dir)
+	return readLockFileAt(LockFilePath(dir))
+}
+
+// readLockFileAt reads and parses a lock file at an explicit path.
+func readLockFileAt(path string) (*LockFile, error) {
 	data, err := os.ReadFile(path)
 	if err != nil {
 		if os.IsNotExist(err) {
@@ -57,6 +82,19 @@ func ReadLockFile(dir string) (*LockFile, error) {
 		}
 		return nil, fmt.Errorf("reading lock file: %w", err)
 	}
+	lf, err := parseLockFile(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return lf, nil
+}
+
+// parseLockFile validates lock file contents against the lock schema and
+// decodes them, migrating legacy formats to v3.
+func parseLockFile(data []byte) (*LockFile, error) {
+	if err := schema.Validate(schema.Lock, data); err != nil {
+		return nil, fmt.Errorf("invalid lock file: %w", err)
+	}
 
 	// Try v3 first.
 	var lf LockFile
@@ -78,6 +116,8 @@ func ReadLockFile(dir string) (*LockFile, error) {
 	}
 
 	migrated := migrateLegacyLockFile(&legacy)
+	migrated.DefaultSystems = lf.DefaultSystems
+	migrated.Exclude = lf.Exclude
 	migrated.populateLegacyFields()
 	return migrated, nil
 }
@@ -141,29 +181,15 @@ func migrateLegacyLockFile(legacy *legacyLockFile) *LockFile {
 // WriteLockFile writes the lock file to the given directory atomically.
 // Assets are sorted by (kind, name) for deterministic output.
 func WriteLockFile(dir string, lf *LockFile) error {
-	lf.LockVersion = currentLockVersion
-
-	// Ensure Assets is never nil to serialize as [] instead of null.
-	if lf.Assets == nil {
-		lf.Assets = []asset.LockedAsset{}
-	}
-
-	// Sort assets by (kind, name) for deterministic output.
-	sort.Slice(lf.Assets, func(i, j int) bool {
-		if lf.Assets[i].Kind != lf.Assets[j].Kind {
-			return lf.Assets[i].Kind < lf.Assets[j].Kind
-		}
-		return lf.Assets[i].Name < lf.Assets[j].Name
-	})
+	return writeLockFileAt(LockFilePath(dir), lf)
+}
 
-	data, err := json.MarshalIndent(lf, "", "  ")
+// writeLockFileAt writes a lock file to an explicit path atomically.
+func writeLockFileAt(path string, lf *LockFile) error {
+	data, err := encodeLockFile(lf)
 	if err != nil {
-		return fmt.Errorf("marshaling lock file: %w", err)
+		return err
 	}
-	// Ensure trailing newline.
-	data = append(data, '\n')
-
-	path := LockFilePath(dir)
 
 	// Atomic write: write to temp file, then rename.
 	tmpPath := path + ".tmp"
@@ -178,11 +204,118 @@ func WriteLockFile(dir string, lf *LockFile) error {
 	return nil
 }
 
+// encodeLockFile produces the canonical bytes of a lock file: current
+// version, sorted entries, two-space indentation, and a trailing newline.
+// The same lock always encodes to the same bytes, so rewrites only show up
+// in diffs when something changed.
+func encodeLockFile(lf *LockFile) ([]byte, error) {
+	lf.LockVersion = currentLockVersion
+
+	// Ensure Assets is never nil to serialize as [] instead of null.
+	if lf.Assets == nil {
+		lf.Assets = []asset.LockedAsset{}
+	}
+	sortLockFile(lf)
+
+	data, err := json.MarshalIndent(lf, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("marshaling lock file: %w", err)
+	}
+	// Ensure trailing newline.
+	return append(data, '\n'), nil
+}
+
+// sortLockFile puts a lock file in canonical order: assets by (kind, name),
+// with the source breaking ties, and the unordered lists in entry data
+// (files, requiredEnv) sorted. Data keys need no sorting; encoding/json
+// writes map keys in order.
+func sortLockFile(lf *LockFile) {
+	sort.SliceStable(lf.Assets, func(i, j int) bool {
+		a, b := lf.Assets[i], lf.Assets[j]
+		if a.Kind != b.Kind {
+			return a.Kind < b.Kind
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.Source < b.Source
+	})
+	for _, a := range lf.Assets {
+		for _, key := range []string{"files", "requiredEnv"} {
+			if list, ok := sortedStringList(a.Data[key]); ok {
+				a.Data[key] = list
+			}
+		}
+	}
+}
+
+// sortedStringList returns a sorted copy of a string list stored in lock
+// data, which is []string when built in memory and []any when read back.
+// It reports false for anything else.
+func sortedStringList(v any) ([]string, bool) {
+	var list []string
+	switch v := v.(type) {
+	case []string:
+		list = append([]string(nil), v...)
+	case []any:
+		list = make([]string, 0, len(v))
+		for _, item := range v {
+			s, ok := item.(string)
+			if !ok {
+				return nil, false
+			}
+			list = append(list, s)
+		}
+	default:
+		return nil, false
+	}
+	sort.Strings(list)
+	return list, true
+}
+
+// NormalizeLockFiles rewrites the project's lock files (duckrow.lock.json
+// and the local lock) in canonical form, migrating older lock versions
+// along the way. It returns the project-relative paths of the files that
+// changed; files that are missing or already canonical are left alone.
+func NormalizeLockFiles(dir string) ([]string, error) {
+	var changed []string
+	for _, path := range []string{LockFilePath(dir), LocalLockFilePath(dir)} {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			if os.IsNotExist(err) {
+				continue
+			}
+			return changed, fmt.Errorf("reading lock file: %w", err)
+		}
+		rel, _ := filepath.Rel(dir, path)
+		lf, err := parseLockFile(data)
+		if err != nil {
+			return changed, fmt.Errorf("%s: %w", rel, err)
+		}
+		normalized, err := encodeLockFile(lf)
+		if err != nil {
+			return changed, err
+		}
+		if string(normalized) == string(data) {
+			continue
+		}
+		if err := writeLockFileAt(path, lf); err != nil {
+			return changed, err
+		}
+		changed = append(changed, filepath.ToSlash(rel))
+	}
+	return changed, nil
+}
+
 // --- Generic CRUD (never inspects the Data field) ---
 
 // AddOrUpdateAsset upserts a locked asset by (kind, name).
 func AddOrUpdateAsset(dir string, entry asset.LockedAsset) error {
-	lf, err := ReadLockFile(dir)
+	return addOrUpdateAssetAt(LockFilePath(dir), entry)
+}
+
+func addOrUpdateAssetAt(path string, entry asset.LockedAsset) error {
+	lf, err := readLockFileAt(path)
 	if err != nil {
 		return err
 	}
@@ -202,13 +335,17 @@ func AddOrUpdateAsset(dir string, entry asset.LockedAsset) error {
 		lf.Assets = append(lf.Assets, entry)
 	}
 
-	return WriteLockFile(dir, lf)
+	return writeLockFileAt(path, lf)
 }
 
 // RemoveAssetEntry removes a locked asset by (kind, name).
 // No-op if the lock file does not exist or the asset is not found.
 func RemoveAssetEntry(dir string, kind asset.Kind, name string) error {
-	lf, err := ReadLockFile(dir)
+	return removeAssetEntryAt(LockFilePath(dir), kind, name)
+}
+
+func removeAssetEntryAt(path string, kind asset.Kind, name string) error {
+	lf, err := readLockFileAt(path)
 	if err != nil {
 		return err
 	}
@@ -224,7 +361,7 @@ func RemoveAssetEntry(dir string, kind asset.Kind, name string) error {
 	}
 	lf.Assets = filtered
 
-	return WriteLockFile(dir, lf)
+	return writeLockFileAt(path, lf)
 }
 
 // FindLockedAsset returns the locked entry for a (kind, name) pair, or nil.
@@ -240,6 +377,20 @@ func FindLockedAsset(lf *LockFile, kind asset.Kind, name string) *asset.LockedAs
 	return nil
 }
 
+// WithTag returns a copy of lf holding only the assets tagged tag. Lock
+// layers are kept, so Origin still reports where each asset came from.
+func (lf *LockFile) WithTag(tag string) *LockFile {
+	out := *lf
+	out.Assets = nil
+	for _, a := range lf.Assets {
+		if slices.Contains(a.Tags, tag) {
+			out.Assets = append(out.Assets, a)
+		}
+	}
+	out.populateLegacyFields()
+	return &out
+}
+
 // AssetsByKind returns all locked assets of the given kind.
 func AssetsByKind(lf *LockFile, kind asset.Kind) []asset.LockedAsset {
 	if lf == nil {
@@ -258,48 +409,31 @@ func AssetsByKind(lf *LockFile, kind asset.Kind) []asset.LockedAsset {
 
 // ExtractRequiredEnv returns a sorted, deduplicated copy of the env var names.
 func ExtractRequiredEnv(env []string) []string {
-	if len(env) == 0 {
-		return nil
-	}
-	seen := make(map[string]bool, len(env))
-	var result []string
-	for _, name := range env {
-		if !seen[name] {
-			seen[name] = true
-			result = append(result, name)
+	return asset.RequiredEnv(env)
+}
+
+// LockedRequiredEnv returns the env var names a locked MCP requires, as
+// recorded in its lock entry.
+func LockedRequiredEnv(locked asset.LockedAsset) []string {
+	switch v := locked.Data["requiredEnv"].(type) {
+	case []string:
+		return v
+	case []any:
+		result := make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				result = append(result, s)
+			}
 		}
+		return result
 	}
-	sort.Strings(result)
-	return result
+	return nil
 }
 
 // ComputeConfigHash computes a SHA-256 hash of an MCP entry's config-relevant
-// fields. The hash input is a deterministic JSON object.
-// The returned hash has a "sha256:" prefix.
+// fields. The returned hash has a "sha256:" prefix.
 func ComputeConfigHash(meta asset.MCPMeta) string {
-	m := make(map[string]interface{})
-	if meta.Command != "" {
-		m["command"] = meta.Command
-	}
-	if len(meta.Args) > 0 {
-		m["args"] = meta.Args
-	}
-	if len(meta.Env) > 0 {
-		sorted := make([]string, len(meta.Env))
-		copy(sorted, meta.Env)
-		sort.Strings(sorted)
-		m["env"] = sorted
-	}
-	if meta.URL != "" {
-		m["url"] = meta.URL
-	}
-	if meta.Transport != "" {
-		m["type"] = meta.Transport
-	}
-
-	data, _ := json.Marshal(m)
-	h := sha256.Sum256(data)
-	return "sha256:" + fmt.Sprintf("%x", h)
+	return asset.ConfigHash(meta)
 }
 
 // GetSkillCommit returns the git commit SHA that last modified the given sub-path
@@ -341,9 +475,18 @@ func ParseLockSource(source string) (host, owner, repo, subPath string, err erro
 	host = parts[0]
 	owner = parts[1]
 	repo = parts[2]
+	if host == "" || owner == "" || repo == "" {
+		return "", "", "", "", fmt.Errorf("invalid lock source %q: expected at least host/owner/repo", source)
+	}
 	if len(parts) > 3 {
 		subPath = strings.Join(parts[3:], "/")
 	}
+	if subPath == "." {
+		subPath = ""
+	}
+	if escapesRepo(subPath) {
+		return "", "", "", "", fmt.Errorf("invalid lock source %q: path %q points outside the repository", source, subPath)
+	}
 	return host, owner, repo, subPath, nil
 }
 
@@ -410,104 +553,3 @@ func BuildPathIndex(registryCommits map[string]string) map[string]string {
 	}
 	return index
 }
-
-// CheckForUpdates checks each locked asset of the given kind for available
-// updates. It works for any source-based kind (skills, agents) that uses
-// commit-pinned lock entries.
-func CheckForUpdates(lf *LockFile, kind asset.Kind, overrides map[string]string, registryCommits map[string]string) ([]UpdateInfo, error) {
-	var results []UpdateInfo
-
-	pathIndex := BuildPathIndex(registryCommits)
-
-	// Get all assets of the requested kind from the lock file.
-	assets := AssetsByKind(lf, kind)
-
-	type pendingAsset struct {
-		asset   asset.LockedAsset
-		subPath string
-	}
-
-	type repoRefKey struct {
-		repo string
-		ref  string
-	}
-	repoGroups := make(map[repoRefKey][]pendingAsset)
-	var repoGroupOrder []repoRefKey
-
-	for _, a := range assets {
-		if regCommit := LookupRegistryCommit(a.Source, registryCommits, pathIndex); regCommit != "" {
-			results = append(results, UpdateInfo{
-				Name:            a.Name,
-				Source:          a.Source,
-				InstalledCommit: a.Commit,
-				AvailableCommit: regCommit,
-				HasUpdate:       a.Commit != regCommit,
-			})
-			continue
-		}
-
-		key := repoRefKey{repo: repoKey(a.Source), ref: a.Ref}
-		if _, exists := repoGroups[key]; !exists {
-			repoGroupOrder = append(repoGroupOrder, key)
-		}
-		repoGroups[key] = append(repoGroups[key], pendingAsset{
-			asset:   a,
-			subPath: skillSubPath(a.Source),
-		})
-	}
-
-	for _, key := range repoGroupOrder {
-		pending := repoGroups[key]
-		host, owner, repo, _, err := ParseLockSource(pending[0].asset.Source)
-		if err != nil {
-			for _, ps := range pending {
-				results = append(results, UpdateInfo{
-					Name:            ps.asset.Name,
-					Source:          ps.asset.Source,
-					InstalledCommit: ps.asset.Commit,
-					AvailableCommit: ps.asset.Commit,
-					HasUpdate:       false,
-				})
-			}
-			continue
-		}
-
-		cloneURL := fmt.Sprintf("https://%s/%s/%s.git", host, owner, repo)
-		repoKeyStr := strings.ToLower(owner) + "/" + strings.ToLower(repo)
-		if override, ok := overrides[repoKeyStr]; ok && override != "" {
-			cloneURL = override
-		}
-
-		tmpDir, cloneErr := cloneRepo(cloneURL, key.ref, false)
-		if cloneErr != nil {
-			for _, ps := range pending {
-				results = append(results, UpdateInfo{
-					Name:            ps.asset.Name,
-					Source:          ps.asset.Source,
-					InstalledCommit: ps.asset.Commit,
-					AvailableCommit: ps.asset.Commit,
-					HasUpdate:       false,
-				})
-			}
-			continue
-		}
-
-		for _, ps := range pending {
-			available, commitErr := GetSkillCommit(tmpDir, ps.subPath)
-			if commitErr != nil {
-				available = ps.asset.Commit
-			}
-			results = append(results, UpdateInfo{
-				Name:            ps.asset.Name,
-				Source:          ps.asset.Source,
-				InstalledCommit: ps.asset.Commit,
-				AvailableCommit: available,
-				HasUpdate:       ps.asset.Commit != available,
-			})
-		}
-
-		_ = os.RemoveAll(tmpDir)
-	}
-
-	return results, nil
-}
@@ -111,6 +111,82 @@ func TestWriteLockFile_SortsSkills(t *testing.T) {
 	}
 }
 
+func TestWriteLockFile_Deterministic(t *testing.T) {
+	dir := t.TempDir()
+	write := func(assets ...asset.LockedAsset) string {
+		t.Helper()
+		if err := WriteLockFile(dir, &LockFile{Assets: assets}); err != nil {
+			t.Fatal(err)
+		}
+		data, err := os.ReadFile(LockFilePath(dir))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	skill := asset.LockedAsset{Kind: asset.KindSkill, Name: "lint", Source: "github.com/o/r/lint", Commit: "aaa",
+		Data: map[string]any{"files": []string{"b.md", "SKILL.md", "a.md"}}}
+	mcp := asset.LockedAsset{Kind: asset.KindMCP, Name: "db",
+		Data: map[string]any{"registry": "acme", "requiredEnv": []any{"TOKEN", "HOST"}}}
+	agent := asset.LockedAsset{Kind: asset.KindAgent, Name: "review", Source: "github.com/o/r/agents/review.md", Commit: "bbb"}
+
+	first := write(skill, mcp, agent)
+	if second := write(agent, skill, mcp); second != first {
+		t.Errorf("output depends on entry order:\n%s\nvs\n%s", first, second)
+	}
+	compact := strings.Join(strings.Fields(first), "")
+	if !strings.Contains(compact, `"files":["SKILL.md","a.md","b.md"]`) {
+		t.Errorf("files not sorted:\n%s", first)
+	}
+	if !strings.Contains(compact, `"requiredEnv":["HOST","TOKEN"]`) {
+		t.Errorf("requiredEnv not sorted:\n%s", first)
+	}
+	if strings.Index(first, `"review"`) > strings.Index(first, `"db"`) ||
+		strings.Index(first, `"db"`) > strings.Index(first, `"lint"`) {
+		t.Errorf("assets not sorted by kind:\n%s", first)
+	}
+}
+
+func TestNormalizeLockFiles(t *testing.T) {
+	dir := t.TempDir()
+	unsorted := `{
+  "lockVersion": 3,
+  "assets": [
+    {"kind": "skill", "name": "zeta", "source": "github.com/o/r/zeta", "commit": "aaa"},
+    {"kind": "skill", "name": "alpha", "source": "github.com/o/r/alpha", "commit": "bbb"}
+  ]
+}
+`
+	if err := os.WriteFile(LockFilePath(dir), []byte(unsorted), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	changed, err := NormalizeLockFiles(dir)
+	if err != nil {
+		t.Fatalf("NormalizeLockFiles() error = %v", err)
+	}
+	if len(changed) != 1 || changed[0] != lockFileName {
+		t.Fatalf("changed = %v, want [%s]", changed, lockFileName)
+	}
+	lf, err := ReadLockFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lf.Assets[0].Name != "alpha" || lf.Assets[1].Name != "zeta" {
+		t.Errorf("assets = %+v, want alpha before zeta", lf.Assets)
+	}
+
+	// A second run finds nothing to do.
+	changed, err = NormalizeLockFiles(dir)
+	if err != nil {
+		t.Fatalf("NormalizeLockFiles() error = %v", err)
+	}
+	if len(changed) != 0 {
+		t.Errorf("changed = %v, want none", changed)
+	}
+}
+
 func TestWriteLockFile_OmitsEmptyRef(t *testing.T) {
 	dir := t.TempDir()
 	lf := &LockFile{
@@ -986,3 +1062,30 @@ func TestComputeConfigHash(t *testing.T) {
 		}
 	})
 }
+
+// FuzzParseLockSource checks that ParseLockSource never panics, never
+// accepts a source that points outside its repository, and that accepted
+// sources round-trip through NormalizeSource.
+func FuzzParseLockSource(f *testing.F) {
+	for _, s := range sourceSeeds {
+		f.Add(s)
+	}
+	f.Add("github.com/owner/repo/")
+	f.Add("//")
+	f.Fuzz(func(t *testing.T, source string) {
+		host, owner, repo, subPath, err := ParseLockSource(source)
+		if err != nil {
+			return
+		}
+		if host == "" || owner == "" || repo == "" {
+			t.Errorf("ParseLockSource(%q) = %q, %q, %q: empty component", source, host, owner, repo)
+		}
+		if escapesRepo(subPath) {
+			t.Errorf("ParseLockSource(%q): subpath %q escapes the repository", source, subPath)
+		}
+		h, o, r, s, err := ParseLockSource(NormalizeSource(host, owner, repo, subPath))
+		if err != nil || h != host || o != owner || r != repo || s != subPath {
+			t.Errorf("round trip of %q: got %q %q %q %q (%v)", source, h, o, r, s, err)
+		}
+	})
+}
@@ -0,0 +1,122 @@
+package core
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LogLevel is the severity of a log entry.
+type LogLevel int
+
+const (
+	LogDebug LogLevel = iota // git commands and HTTP requests
+	LogInfo                  // notable steps, e.g. a clone served by the cache
+	LogWarn                  // recoverable problems, e.g. rate-limit retries
+	LogError                 // failed commands, with their output
+)
+
+// String returns the lowercase name of the level.
+func (l LogLevel) String() string {
+	switch l {
+	case LogDebug:
+		return "debug"
+	case LogInfo:
+		return "info"
+	case LogWarn:
+		return "warn"
+	case LogError:
+		return "error"
+	}
+	return fmt.Sprintf("level(%d)", int(l))
+}
+
+// LogEntry is one event in the log.
+type LogEntry struct {
+	Seq     int       `json:"seq"` // increases by one per entry, across trimming
+	Time    time.Time `json:"time"`
+	Level   LogLevel  `json:"level"`
+	Message string    `json:"message"`
+
+	// Output is the output of a command, if any, e.g. what git printed
+	// before it failed.
+	Output string `json:"output,omitempty"`
+}
+
+const (
+	// maxLogEntries bounds the log. Older entries are dropped first.
+	maxLogEntries = 1000
+
+	// maxLogOutput bounds the output kept per entry. Git reports errors
+	// last, so the tail is kept.
+	maxLogOutput = 16 << 10
+)
+
+// eventLog is process-wide like the network counters: every git command and
+// HTTP request records to it, and the TUI's log viewer reads it back.
+var eventLog struct {
+	mu      sync.Mutex
+	entries []LogEntry
+	seq     int
+}
+
+// logEvent records an entry in the log.
+func logEvent(level LogLevel, output, format string, args ...any) {
+	output = strings.TrimRight(output, "\n")
+	if len(output) > maxLogOutput {
+		output = "..." + output[len(output)-maxLogOutput:]
+	}
+	eventLog.mu.Lock()
+	defer eventLog.mu.Unlock()
+	eventLog.seq++
+	eventLog.entries = append(eventLog.entries, LogEntry{
+		Seq:     eventLog.seq,
+		Time:    time.Now(),
+		Level:   level,
+		Message: fmt.Sprintf(format, args...),
+		Output:  output,
+	})
+	if n := len(eventLog.entries) - maxLogEntries; n > 0 {
+		eventLog.entries = append(eventLog.entries[:0:0], eventLog.entries[n:]...)
+	}
+}
+
+// LogEntries returns the entries recorded after seq, oldest first. Zero
+// returns the whole log.
+func LogEntries(after int) []LogEntry {
+	eventLog.mu.Lock()
+	defer eventLog.mu.Unlock()
+	var out []LogEntry
+	for _, e := range eventLog.entries {
+		if e.Seq > after {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// commandLine formats the arguments of a command for the log, with the
+// credentials of any URL among them masked.
+func commandLine(args []string) string {
+	out := make([]string, len(args))
+	for i, arg := range args {
+		out[i] = redactURL(arg)
+	}
+	return strings.Join(out, " ")
+}
+
+// redactURL masks the user info of a URL, where clone URLs carry tokens.
+// Anything else is returned unchanged.
+func redactURL(s string) string {
+	if !strings.Contains(s, "://") {
+		return s
+	}
+	u, err := url.Parse(s)
+	if err != nil || u.User == nil {
+		return s
+	}
+	u.User = nil
+	return strings.Replace(u.String(), "://", "://***@", 1)
+}
@@ -0,0 +1,81 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"unicode/utf16"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+// maxWindowsPath is MAX_PATH, the longest path (in UTF-16 code units,
+// including the terminating NUL) that Windows programs can open unless long
+// paths are enabled system-wide.
+const maxWindowsPath = 260
+
+// pathLengthLimit returns the longest path installed files may have, or 0
+// when there is no practical limit. It is a variable so tests can exercise
+// the check on any platform.
+var pathLengthLimit = platformPathLimit
+
+// PathTooLongError is returned when installing an asset would create a path
+// longer than the platform allows.
+type PathTooLongError struct {
+	Name   string // asset being installed
+	Path   string
+	Length int
+	Limit  int
+}
+
+func (e *PathTooLongError) Error() string {
+	return fmt.Sprintf("installing %q would create a path of %d characters, over the Windows limit of %d: %s",
+		e.Name, e.Length, e.Limit-1, e.Path)
+}
+
+// pathLength returns the length of p as Windows counts it: in UTF-16 code
+// units, so non-ASCII file names are measured correctly.
+func pathLength(p string) int {
+	return len(utf16.Encode([]rune(p)))
+}
+
+// checkPathLengths fails before anything is copied if a skill's files would
+// end up at paths over the platform limit, either in the canonical directory
+// or as seen through a system's skill directory.
+func checkPathLengths(projectDir string, targets []system.System, discovered []asset.Asset, ignorePatterns []string) error {
+	limit := pathLengthLimit()
+	if limit == 0 {
+		return nil
+	}
+	absProject, err := filepath.Abs(projectDir)
+	if err != nil {
+		return err
+	}
+
+	roots := []string{filepath.Join(absProject, canonicalSkillsDir)}
+	for _, sys := range targets {
+		if dir := sys.AssetDir(asset.KindSkill, absProject); dir != "" && dir != roots[0] {
+			roots = append(roots, dir)
+		}
+	}
+
+	for _, a := range discovered {
+		ignore, err := LoadIgnoreMatcher(a.PreparedPath, ignorePatterns)
+		if err != nil {
+			return err
+		}
+		err = walkSkillFiles(a.PreparedPath, ignore, func(rel string, _ bool) error {
+			for _, root := range roots {
+				p := filepath.Join(root, sanitizeName(a.Name), rel)
+				if n := pathLength(p); n >= limit {
+					return &PathTooLongError{Name: a.Name, Path: p, Length: n, Limit: limit}
+				}
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,13 @@
+//go:build !windows
+
+package core
+
+// platformPathLimit returns 0: other platforms have no path limit that
+// skill installs run into in practice.
+func platformPathLimit() int { return 0 }
+
+// longPath returns p unchanged; only Windows needs the \\?\ form.
+func longPath(p string) string { return p }
+
+// longPathGitArgs returns no extra git options outside Windows.
+func longPathGitArgs() []string { return nil }
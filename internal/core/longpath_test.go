@@ -0,0 +1,74 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+func TestPathLength(t *testing.T) {
+	tests := []struct {
+		path string
+		want int
+	}{
+		{"skills/lint", 11},
+		{"résumé.md", 9},
+		{"日本語.md", 6},
+		{"🦆.md", 5}, // outside the BMP: a surrogate pair
+	}
+	for _, tt := range tests {
+		if got := pathLength(tt.path); got != tt.want {
+			t.Errorf("pathLength(%q) = %d, want %d", tt.path, got, tt.want)
+		}
+	}
+}
+
+func TestCheckPathLengths(t *testing.T) {
+	project := t.TempDir()
+	src := t.TempDir()
+	deep := filepath.Join(src, "references", strings.Repeat("a", 40))
+	if err := os.MkdirAll(deep, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(deep, "guide.md"), []byte("x"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	skills := []asset.Asset{{Kind: asset.KindSkill, Name: "lint", PreparedPath: src}}
+	claude, _ := system.ByName("claude-code")
+	targets := []system.System{claude}
+
+	orig := pathLengthLimit
+	t.Cleanup(func() { pathLengthLimit = orig })
+
+	// No limit: nothing to check.
+	pathLengthLimit = func() int { return 0 }
+	if err := checkPathLengths(project, targets, skills, nil); err != nil {
+		t.Fatalf("no limit: error = %v", err)
+	}
+
+	longest := filepath.Join(project, ".agents", "skills", "lint", "references", strings.Repeat("a", 40), "guide.md")
+	pathLengthLimit = func() int { return pathLength(longest) + 1 }
+	if err := checkPathLengths(project, targets, skills, nil); err != nil {
+		t.Fatalf("under limit: error = %v", err)
+	}
+
+	pathLengthLimit = func() int { return pathLength(longest) }
+	err := checkPathLengths(project, targets, skills, nil)
+	var tooLong *PathTooLongError
+	if !errors.As(err, &tooLong) {
+		t.Fatalf("error = %v, want *PathTooLongError", err)
+	}
+	if tooLong.Name != "lint" || tooLong.Path != longest || tooLong.Length != pathLength(longest) {
+		t.Errorf("error = %+v", tooLong)
+	}
+
+	// Ignored files are not installed, so they don't count.
+	if err := checkPathLengths(project, targets, skills, []string{"references/"}); err != nil {
+		t.Errorf("ignored: error = %v", err)
+	}
+}
@@ -0,0 +1,52 @@
+//go:build windows
+
+package core
+
+import (
+	"path/filepath"
+	"strings"
+
+	"golang.org/x/sys/windows/registry"
+)
+
+// platformPathLimit returns MAX_PATH unless long paths are enabled in the
+// registry, in which case tools that opt in can open any path.
+func platformPathLimit() int {
+	if longPathsEnabled() {
+		return 0
+	}
+	return maxWindowsPath
+}
+
+// longPathsEnabled reports whether the LongPathsEnabled policy is set.
+func longPathsEnabled() bool {
+	k, err := registry.OpenKey(registry.LOCAL_MACHINE,
+		`SYSTEM\CurrentControlSet\Control\FileSystem`, registry.QUERY_VALUE)
+	if err != nil {
+		return false
+	}
+	defer func() { _ = k.Close() }()
+	v, _, err := k.GetIntegerValue("LongPathsEnabled")
+	return err == nil && v == 1
+}
+
+// longPath returns p as an absolute path in the \\?\ form, which Win32 file
+// APIs accept beyond MAX_PATH. Paths that already use it are returned as is.
+func longPath(p string) string {
+	if strings.HasPrefix(p, `\\?\`) {
+		return p
+	}
+	abs, err := filepath.Abs(p)
+	if err != nil {
+		return p
+	}
+	if strings.HasPrefix(abs, `\\`) {
+		return `\\?\UNC\` + abs[2:]
+	}
+	return `\\?\` + abs
+}
+
+// longPathGitArgs lets git check out files with paths beyond MAX_PATH.
+func longPathGitArgs() []string {
+	return []string{"-c", "core.longpaths=true"}
+}
@@ -0,0 +1,187 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/schema"
+	"github.com/tailscale/hujson"
+	"gopkg.in/yaml.v3"
+)
+
+// manifestFiles are the file names a registry manifest may have, in the
+// order they are looked for. duckrow.json may hold comments and trailing
+// commas (JSONC); duckrow.yaml and duckrow.yml are YAML.
+var manifestFiles = []string{registryManifestFile, "duckrow.yaml", "duckrow.yml"}
+
+// findManifest returns the path of the registry manifest in dir.
+func findManifest(dir string) (string, error) {
+	for _, name := range manifestFiles {
+		path := filepath.Join(dir, name)
+		_, err := os.Stat(path)
+		if err == nil {
+			return path, nil
+		}
+		if !os.IsNotExist(err) {
+			return "", fmt.Errorf("reading %s: %w", name, err)
+		}
+	}
+	return "", fmt.Errorf("%s not found in repository", registryManifestFile)
+}
+
+// decodeManifest converts the contents of the manifest file name to
+// standard JSON and checks it against the registry schema. Errors give the
+// line and column in the original file and, for syntax errors, a hint
+// about the format the file seems to be in.
+func decodeManifest(name string, data []byte) ([]byte, error) {
+	if isYAMLManifest(name) {
+		return decodeYAMLManifest(name, data)
+	}
+
+	v, err := hujson.Parse(data)
+	if err != nil {
+		msg := strings.TrimPrefix(err.Error(), "hujson: ")
+		return nil, fmt.Errorf("invalid %s: %s (hint: %s)", name, msg, jsonManifestHint(name, data))
+	}
+	// Comments and trailing commas become spaces, so offsets still match
+	// the file.
+	v.Standardize()
+	std := v.Pack()
+	if err := schema.Validate(schema.Registry, std); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return std, nil
+}
+
+func isYAMLManifest(name string) bool {
+	ext := filepath.Ext(name)
+	return ext == ".yaml" || ext == ".yml"
+}
+
+// jsonManifestHint guesses why a JSON manifest failed to parse.
+func jsonManifestHint(name string, data []byte) string {
+	if looksLikeYAML(data) {
+		return fmt.Sprintf("%s looks like YAML; rename it to duckrow.yaml", name)
+	}
+	return fmt.Sprintf("%s is JSON with comments and trailing commas allowed; keys and strings need double quotes", name)
+}
+
+// looksLikeYAML reports whether data is a YAML mapping rather than a
+// malformed JSON object.
+func looksLikeYAML(data []byte) bool {
+	trimmed := bytes.TrimSpace(data)
+	if len(trimmed) == 0 || trimmed[0] == '{' || trimmed[0] == '[' || trimmed[0] == '/' {
+		return false
+	}
+	var m map[string]any
+	return yaml.Unmarshal(data, &m) == nil && len(m) > 0
+}
+
+// decodeYAMLManifest is decodeManifest for duckrow.yaml. Schema problems
+// are reported at their position in the YAML file.
+func decodeYAMLManifest(name string, data []byte) ([]byte, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		msg := strings.TrimPrefix(err.Error(), "yaml: ")
+		if hint := yamlManifestHint(data); hint != "" {
+			return nil, fmt.Errorf("invalid %s: %s (hint: %s)", name, msg, hint)
+		}
+		return nil, fmt.Errorf("invalid %s: %s", name, msg)
+	}
+	var v any
+	if err := doc.Decode(&v); err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	std, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+
+	if err := schema.Validate(schema.Registry, std); err != nil {
+		var schemaErr *schema.Error
+		if errors.As(err, &schemaErr) {
+			for i := range schemaErr.Problems {
+				p := &schemaErr.Problems[i]
+				p.Line, p.Column = yamlPosition(&doc, p.Path)
+			}
+			sort.SliceStable(schemaErr.Problems, func(i, j int) bool {
+				a, b := schemaErr.Problems[i], schemaErr.Problems[j]
+				if a.Line != b.Line {
+					return a.Line < b.Line
+				}
+				return a.Column < b.Column
+			})
+		}
+		return nil, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return std, nil
+}
+
+// yamlManifestHint guesses why a YAML manifest failed to parse, or returns
+// "" if nothing stands out.
+func yamlManifestHint(data []byte) string {
+	for _, line := range strings.Split(string(data), "\n") {
+		if strings.HasPrefix(strings.TrimLeft(line, " "), "\t") {
+			return "YAML indentation must use spaces, not tabs"
+		}
+	}
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '/') {
+		return "the file looks like JSON; rename it to duckrow.json"
+	}
+	return ""
+}
+
+// yamlPosition returns the line and column of the node at a schema problem
+// path such as assets.skill[0].name, or of the deepest node on the way
+// there.
+func yamlPosition(doc *yaml.Node, path string) (line, column int) {
+	n := doc
+	if n.Kind == yaml.DocumentNode && len(n.Content) > 0 {
+		n = n.Content[0]
+	}
+	for {
+		if n.Kind == yaml.AliasNode && n.Alias != nil {
+			n = n.Alias
+		}
+		if path == "" {
+			return n.Line, n.Column
+		}
+		next, rest := yamlChild(n, path)
+		if next == nil {
+			return n.Line, n.Column
+		}
+		n, path = next, rest
+	}
+}
+
+// yamlChild returns the child of n named by the first element of path and
+// the rest of the path.
+func yamlChild(n *yaml.Node, path string) (*yaml.Node, string) {
+	switch n.Kind {
+	case yaml.SequenceNode:
+		end := strings.IndexByte(path, ']')
+		if path[0] != '[' || end < 0 {
+			return nil, ""
+		}
+		i, err := strconv.Atoi(path[1:end])
+		if err != nil || i < 0 || i >= len(n.Content) {
+			return nil, ""
+		}
+		return n.Content[i], strings.TrimPrefix(path[end+1:], ".")
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			rest, ok := strings.CutPrefix(path, n.Content[i].Value)
+			if ok && (rest == "" || rest[0] == '.' || rest[0] == '[') {
+				return n.Content[i+1], strings.TrimPrefix(rest, ".")
+			}
+		}
+	}
+	return nil, ""
+}
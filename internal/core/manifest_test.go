@@ -0,0 +1,112 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestReadManifest_Formats(t *testing.T) {
+	tests := []struct {
+		name    string
+		file    string
+		content string
+		wantErr string // substring; "" means success
+	}{
+		{
+			name: "jsonc",
+			file: "duckrow.json",
+			content: `{
+  // Team registry
+  "name": "team",
+  "skills": [
+    {"name": "lint", "source": "acme/skills/lint"}, /* pinned later */
+  ],
+}`,
+		},
+		{
+			name: "yaml",
+			file: "duckrow.yaml",
+			content: `# Team registry
+name: team
+skills:
+  - name: lint
+    source: acme/skills/lint
+`,
+		},
+		{
+			name:    "yml",
+			file:    "duckrow.yml",
+			content: "name: team\nskills: []\n",
+		},
+		{
+			name:    "yaml in duckrow.json",
+			file:    "duckrow.json",
+			content: "name: team\nskills:\n  - name: lint\n",
+			wantErr: "invalid duckrow.json: line 1, column 1: invalid literal: name (hint: duckrow.json looks like YAML; rename it to duckrow.yaml)",
+		},
+		{
+			name:    "single quotes in duckrow.json",
+			file:    "duckrow.json",
+			content: "{\n  'name': 'team'\n}",
+			wantErr: "line 2, column 3: invalid character '\\'' at start of value (hint: duckrow.json is JSON with comments",
+		},
+		{
+			name:    "schema problem in yaml",
+			file:    "duckrow.yaml",
+			content: "name: team\nskills:\n  - name: lint\n    hydrate: sometimes\n",
+			wantErr: "invalid duckrow.yaml: line 4, column 14: skills[0].hydrate: expected boolean, got string",
+		},
+		{
+			name:    "tabs in yaml",
+			file:    "duckrow.yaml",
+			content: "name: team\nskills:\n\t- name: lint\n",
+			wantErr: "(hint: YAML indentation must use spaces, not tabs)",
+		},
+		{
+			name:    "jsonc in duckrow.yaml",
+			file:    "duckrow.yaml",
+			content: "{\n  // Team registry\n  \"name\": \"team\",\n}",
+			wantErr: "(hint: the file looks like JSON; rename it to duckrow.json)",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			if err := os.WriteFile(filepath.Join(dir, tt.file), []byte(tt.content), 0o644); err != nil {
+				t.Fatal(err)
+			}
+			m, err := readManifest(dir)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Fatalf("readManifest() error = %v, want %q", err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("readManifest() error = %v", err)
+			}
+			if m.Name != "team" {
+				t.Errorf("Name = %q, want team", m.Name)
+			}
+		})
+	}
+}
+
+func TestReadManifest_PrefersJSON(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "duckrow.json"), []byte(`{"name": "from-json"}`), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "duckrow.yaml"), []byte("name: from-yaml\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	m, err := readManifest(dir)
+	if err != nil {
+		t.Fatalf("readManifest() error = %v", err)
+	}
+	if m.Name != "from-json" {
+		t.Errorf("Name = %q, want from-json", m.Name)
+	}
+}
@@ -0,0 +1,137 @@
+package core
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// mcpOverridesKey is the lock data key that records local overrides of an
+// MCP's registry definition.
+const mcpOverridesKey = "overrides"
+
+// MCPOverrides are local changes layered over an MCP's registry definition,
+// made with `duckrow mcp edit`. They are kept under data.overrides in the
+// lock entry so sync writes the same config for everyone using the lock.
+type MCPOverrides struct {
+	// Args, when non-nil, replace the registry's arguments. An empty,
+	// non-nil list runs the server without arguments.
+	Args []string
+	// Env holds values for environment variables. `duckrow env` passes them
+	// to the server when they are not set in the environment or an
+	// .env.duckrow file.
+	Env map[string]string
+}
+
+// IsEmpty reports whether o changes nothing.
+func (o MCPOverrides) IsEmpty() bool {
+	return o.Args == nil && len(o.Env) == 0
+}
+
+// EnvNames returns the names of the overridden environment variables,
+// sorted.
+func (o MCPOverrides) EnvNames() []string {
+	names := make([]string, 0, len(o.Env))
+	for name := range o.Env {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// String summarizes what is overridden, e.g. "args, env LOG_LEVEL".
+func (o MCPOverrides) String() string {
+	var parts []string
+	if o.Args != nil {
+		parts = append(parts, "args")
+	}
+	if len(o.Env) > 0 {
+		parts = append(parts, "env "+strings.Join(o.EnvNames(), ", "))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Apply returns meta with the overrides layered on top.
+func (o MCPOverrides) Apply(meta asset.MCPMeta) asset.MCPMeta {
+	if o.Args != nil {
+		meta.Args = slices.Clone(o.Args)
+	}
+	return meta
+}
+
+// ParseEnvOverride splits a NAME=VALUE override.
+func ParseEnvOverride(s string) (name, value string, err error) {
+	name, value, ok := strings.Cut(s, "=")
+	if !ok || name == "" || strings.ContainsAny(name, " \t") {
+		return "", "", fmt.Errorf("invalid env override %q (want NAME=VALUE)", s)
+	}
+	return name, value, nil
+}
+
+// LockedMCPOverrides returns the overrides recorded in a lock entry. The
+// result is empty for entries without any.
+func LockedMCPOverrides(locked asset.LockedAsset) MCPOverrides {
+	var o MCPOverrides
+	m, ok := locked.Data[mcpOverridesKey].(map[string]any)
+	if !ok {
+		return o
+	}
+	switch v := m["args"].(type) {
+	case []string:
+		o.Args = slices.Clone(v)
+	case []any:
+		o.Args = make([]string, 0, len(v))
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				o.Args = append(o.Args, s)
+			}
+		}
+	}
+	switch v := m["env"].(type) {
+	case map[string]string:
+		o.Env = make(map[string]string, len(v))
+		for name, value := range v {
+			o.Env[name] = value
+		}
+	case map[string]any:
+		o.Env = make(map[string]string, len(v))
+		for name, value := range v {
+			if s, ok := value.(string); ok {
+				o.Env[name] = s
+			}
+		}
+	}
+	return o
+}
+
+// WithMCPOverrides returns a copy of locked recording o, or recording no
+// overrides when o is empty.
+func WithMCPOverrides(locked asset.LockedAsset, o MCPOverrides) asset.LockedAsset {
+	data := make(map[string]any, len(locked.Data)+1)
+	for k, v := range locked.Data {
+		data[k] = v
+	}
+	delete(data, mcpOverridesKey)
+	if !o.IsEmpty() {
+		m := make(map[string]any, 2)
+		if o.Args != nil {
+			m["args"] = slices.Clone(o.Args)
+		}
+		if len(o.Env) > 0 {
+			env := make(map[string]any, len(o.Env))
+			for name, value := range o.Env {
+				env[name] = value
+			}
+			m["env"] = env
+		}
+		data[mcpOverridesKey] = m
+	}
+	if len(data) == 0 {
+		data = nil
+	}
+	locked.Data = data
+	return locked
+}
@@ -0,0 +1,80 @@
+package core
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestMCPOverrides_Apply(t *testing.T) {
+	meta := asset.MCPMeta{Command: "db-server", Args: []string{"--port", "5432"}, Env: []string{"DB_URL"}}
+
+	if got := (MCPOverrides{}).Apply(meta); !reflect.DeepEqual(got, meta) {
+		t.Errorf("empty overrides: Apply() = %+v, want %+v", got, meta)
+	}
+	got := MCPOverrides{Args: []string{"--port", "6543"}}.Apply(meta)
+	if !reflect.DeepEqual(got.Args, []string{"--port", "6543"}) || got.Command != "db-server" {
+		t.Errorf("Apply() = %+v", got)
+	}
+	if got := (MCPOverrides{Args: []string{}}).Apply(meta); len(got.Args) != 0 {
+		t.Errorf("no args: Apply().Args = %v, want none", got.Args)
+	}
+}
+
+func TestMCPOverrides_String(t *testing.T) {
+	o := MCPOverrides{Args: []string{}, Env: map[string]string{"ZED": "1", "LOG_LEVEL": "debug"}}
+	if got, want := o.String(), "args, env LOG_LEVEL, ZED"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+	if !(MCPOverrides{}).IsEmpty() || o.IsEmpty() {
+		t.Error("IsEmpty() wrong")
+	}
+}
+
+func TestLockedMCPOverrides_RoundTrip(t *testing.T) {
+	locked := asset.LockedAsset{Kind: asset.KindMCP, Name: "db", Data: map[string]any{"registry": "reg"}}
+	want := MCPOverrides{Args: []string{"--port", "6543"}, Env: map[string]string{"LOG_LEVEL": "debug"}}
+
+	updated := WithMCPOverrides(locked, want)
+	if got := LockedMCPOverrides(updated); !reflect.DeepEqual(got, want) {
+		t.Errorf("in memory: LockedMCPOverrides() = %+v, want %+v", got, want)
+	}
+	if _, ok := locked.Data[mcpOverridesKey]; ok {
+		t.Error("WithMCPOverrides modified the original entry")
+	}
+
+	// As read back from a lock file.
+	data, err := json.Marshal(updated)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var read asset.LockedAsset
+	if err := json.Unmarshal(data, &read); err != nil {
+		t.Fatal(err)
+	}
+	if got := LockedMCPOverrides(read); !reflect.DeepEqual(got, want) {
+		t.Errorf("from JSON: LockedMCPOverrides() = %+v, want %+v", got, want)
+	}
+
+	cleared := WithMCPOverrides(read, MCPOverrides{})
+	if _, ok := cleared.Data[mcpOverridesKey]; ok || cleared.Data["registry"] != "reg" {
+		t.Errorf("cleared Data = %v", cleared.Data)
+	}
+}
+
+func TestParseEnvOverride(t *testing.T) {
+	name, value, err := ParseEnvOverride("LOG_LEVEL=a=b")
+	if err != nil || name != "LOG_LEVEL" || value != "a=b" {
+		t.Errorf("ParseEnvOverride() = %q, %q, %v", name, value, err)
+	}
+	if _, _, err := ParseEnvOverride("EMPTY="); err != nil {
+		t.Errorf("empty value: error = %v", err)
+	}
+	for _, s := range []string{"LOG_LEVEL", "=x", "A B=x"} {
+		if _, _, err := ParseEnvOverride(s); err == nil {
+			t.Errorf("ParseEnvOverride(%q) expected error", s)
+		}
+	}
+}
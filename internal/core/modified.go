@@ -0,0 +1,193 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+// ModifiedSkillError is returned when installing over a skill whose files
+// were changed locally since it was installed, and the change was neither
+// allowed (OverwriteModified) nor confirmed (ConfirmOverwrite).
+type ModifiedSkillError struct {
+	Name  string
+	Files []string // project-relative paths that differ from the locked commit
+}
+
+func (e *ModifiedSkillError) Error() string {
+	return fmt.Sprintf("skill %q has local modifications (%s)", e.Name, summarizeFiles(e.Files, 3))
+}
+
+// summarizeFiles lists up to n files, followed by how many more there are.
+func summarizeFiles(files []string, n int) string {
+	if len(files) <= n {
+		return strings.Join(files, ", ")
+	}
+	return fmt.Sprintf("%s and %d more", strings.Join(files[:n], ", "), len(files)-n)
+}
+
+// checkModified fails with a *ModifiedSkillError when the installed copy of
+// a locked skill was changed locally and opts don't allow overwriting it.
+// a is the skill about to be installed over it, resolved to commit.
+func (o *Orchestrator) checkModified(
+	source *ParsedSource,
+	a asset.Asset,
+	commit string,
+	locked asset.LockedAsset,
+	opts OrchestratorInstallOptions,
+) error {
+	if opts.OverwriteModified || locked.Commit == "" ||
+		!dirExists(filepath.Join(opts.TargetDir, canonicalSkillsDir, sanitizeName(locked.Name))) {
+		return nil
+	}
+
+	var files []string
+	if locked.Source == a.Source && locked.Commit == commit && LockedPartial(locked) == nil {
+		// The clone at hand has the locked content; copy it out instead of
+		// fetching it again.
+		scratch, err := os.MkdirTemp("", "duckrow-modified-*")
+		if err != nil {
+			return fmt.Errorf("creating temp dir: %w", err)
+		}
+		defer func() { _ = os.RemoveAll(scratch) }()
+		if _, err := copyToCanonical(a, scratch, opts.IgnorePatterns); err != nil {
+			return err
+		}
+		files, err = modifiedFiles(filepath.Join(scratch, canonicalSkillsDir, sanitizeName(a.Name)), locked.Name, opts.TargetDir)
+		if err != nil {
+			return fmt.Errorf("checking %q for local modifications: %w", locked.Name, err)
+		}
+	} else {
+		lockedSrc, err := lockedSource(locked, opts.CloneURLOverrides, opts.Mirrors)
+		if err != nil {
+			return fmt.Errorf("checking %q for local modifications: %w", locked.Name, err)
+		}
+		// Prefer the clone URL in use when the entry is from the same repo,
+		// so overrides applied by the caller carry over.
+		if lockedSrc.Host == source.Host && lockedSrc.Owner == source.Owner && lockedSrc.Repo == source.Repo {
+			lockedSrc.CloneURL = source.CloneURL
+		}
+		expected, cleanup, err := o.materializeLockedSkill(lockedSrc, locked, opts.IgnorePatterns)
+		if err != nil {
+			return fmt.Errorf("checking %q for local modifications: %w", locked.Name, err)
+		}
+		defer cleanup()
+		files, err = modifiedFiles(expected, locked.Name, opts.TargetDir)
+		if err != nil {
+			return fmt.Errorf("checking %q for local modifications: %w", locked.Name, err)
+		}
+	}
+
+	if len(files) == 0 {
+		return nil
+	}
+	if opts.ConfirmOverwrite != nil && opts.ConfirmOverwrite(locked.Name, files) {
+		return nil
+	}
+	return &ModifiedSkillError{Name: locked.Name, Files: files}
+}
+
+// materializeLockedSkill installs a pinned skill entry the way sync does it
+// (with ignore rules and partial updates applied) into a scratch project.
+// It returns the skill's directory there and a function removing the
+// project.
+func (o *Orchestrator) materializeLockedSkill(
+	source *ParsedSource,
+	locked asset.LockedAsset,
+	ignorePatterns []string,
+) (string, func(), error) {
+	project, err := os.MkdirTemp("", "duckrow-locked-*")
+	if err != nil {
+		return "", nil, fmt.Errorf("creating temp dir: %w", err)
+	}
+	cleanup := func() { _ = os.RemoveAll(project) }
+
+	installOpts := OrchestratorInstallOptions{
+		TargetDir:      project,
+		Commit:         locked.Commit,
+		NameFilter:     LockedUpstreamName(locked),
+		IgnorePatterns: ignorePatterns,
+		NoValidate:     true,
+		LegacyNames:    true,
+	}
+	if LockedAliasOf(locked) != "" {
+		installOpts.Alias = locked.Name
+	}
+	if _, err := o.InstallFromSource(source, asset.KindSkill, installOpts); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	if p := LockedPartial(locked); p != nil {
+		if _, _, err := o.UpdatePaths(source, locked, p.Commit, p.Paths, installOpts); err != nil {
+			cleanup()
+			return "", nil, err
+		}
+	}
+	return filepath.Join(project, canonicalSkillsDir, sanitizeName(locked.Name)), cleanup, nil
+}
+
+// modifiedFiles compares the canonical copy of skill name in targetDir, and
+// any system copies of it, against the expected tree.
+func modifiedFiles(expected, name, targetDir string) ([]string, error) {
+	dirs := []string{filepath.Join(targetDir, canonicalSkillsDir, sanitizeName(name))}
+	for _, sys := range system.Supporting(asset.KindSkill) {
+		if sys.IsUniversal() {
+			continue
+		}
+		dir := filepath.Join(sys.AssetDir(asset.KindSkill, targetDir), sanitizeName(name))
+		if info, err := os.Lstat(dir); err == nil && info.IsDir() && !slices.Contains(dirs, dir) {
+			dirs = append(dirs, dir)
+		}
+	}
+
+	want, err := treeFiles(expected)
+	if err != nil {
+		return nil, err
+	}
+	var changed []string
+	for _, dir := range dirs {
+		have, err := treeFiles(dir)
+		if err != nil {
+			return nil, err
+		}
+		for rel := range have {
+			same := false
+			if want[rel] {
+				same, err = sameFile(filepath.Join(expected, rel), filepath.Join(dir, rel))
+				if err != nil {
+					return nil, err
+				}
+			}
+			if !same {
+				changed = append(changed, relSlash(targetDir, filepath.Join(dir, rel)))
+			}
+		}
+		for rel := range want {
+			if !have[rel] {
+				changed = append(changed, relSlash(targetDir, filepath.Join(dir, rel)))
+			}
+		}
+	}
+	sort.Strings(changed)
+	return changed, nil
+}
+
+// sameFile reports whether two files have the same contents.
+func sameFile(a, b string) (bool, error) {
+	dataA, err := os.ReadFile(a)
+	if err != nil {
+		return false, err
+	}
+	dataB, err := os.ReadFile(b)
+	if err != nil {
+		return false, err
+	}
+	return bytes.Equal(dataA, dataB), nil
+}
@@ -0,0 +1,106 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// writeSkillFile writes content to path, creating parent directories.
+func writeSkillFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestModifiedFiles(t *testing.T) {
+	expected := t.TempDir()
+	writeSkillFile(t, filepath.Join(expected, "SKILL.md"), "skill")
+	writeSkillFile(t, filepath.Join(expected, "docs", "guide.md"), "guide")
+	writeSkillFile(t, filepath.Join(expected, "gone.md"), "gone")
+
+	project := t.TempDir()
+	canonical := filepath.Join(project, canonicalSkillsDir, "lint")
+	writeSkillFile(t, filepath.Join(canonical, "SKILL.md"), "skill")
+	writeSkillFile(t, filepath.Join(canonical, "docs", "guide.md"), "edited")
+	writeSkillFile(t, filepath.Join(canonical, "notes.md"), "added")
+
+	got, err := modifiedFiles(expected, "lint", project)
+	if err != nil {
+		t.Fatalf("modifiedFiles() error = %v", err)
+	}
+	want := []string{
+		".agents/skills/lint/docs/guide.md",
+		".agents/skills/lint/gone.md",
+		".agents/skills/lint/notes.md",
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("modifiedFiles() = %v, want %v", got, want)
+	}
+
+	// A system copy is checked too; a link to the canonical copy is not.
+	copyDir := filepath.Join(project, ".claude", "skills", "lint")
+	writeSkillFile(t, filepath.Join(copyDir, "SKILL.md"), "changed")
+	if err := os.MkdirAll(filepath.Join(project, ".cursor", "skills"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(canonical, filepath.Join(project, ".cursor", "skills", "lint")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+	got, err = modifiedFiles(expected, "lint", project)
+	if err != nil {
+		t.Fatalf("modifiedFiles() error = %v", err)
+	}
+	for _, f := range got {
+		if filepath.Dir(filepath.Dir(f)) == ".cursor/skills" {
+			t.Errorf("modifiedFiles() reported linked copy: %s", f)
+		}
+	}
+	if !slices.Contains(got, ".claude/skills/lint/SKILL.md") {
+		t.Errorf("modifiedFiles() = %v, want the system copy's SKILL.md", got)
+	}
+}
+
+func TestModifiedSkillError(t *testing.T) {
+	err := &ModifiedSkillError{Name: "lint", Files: []string{"a", "b", "c", "d", "e"}}
+	if got, want := err.Error(), `skill "lint" has local modifications (a, b, c and 2 more)`; got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestIsUnchanged(t *testing.T) {
+	project := t.TempDir()
+	writeSkillFile(t, filepath.Join(project, canonicalSkillsDir, "lint", "SKILL.md"), "skill")
+
+	locked := asset.LockedAsset{Kind: asset.KindSkill, Name: "lint", Source: "github.com/o/r/lint", Commit: "abc123"}
+	a := asset.Asset{Kind: asset.KindSkill, Name: "lint", Source: "github.com/o/r/lint"}
+
+	if !isUnchanged(locked, a, "abc123", "", project) {
+		t.Error("same source and commit: isUnchanged() = false, want true")
+	}
+	if isUnchanged(locked, a, "def456", "", project) {
+		t.Error("other commit: isUnchanged() = true, want false")
+	}
+	if isUnchanged(locked, asset.Asset{Name: "lint", Source: "github.com/x/r/lint"}, "abc123", "", project) {
+		t.Error("other source: isUnchanged() = true, want false")
+	}
+	if isUnchanged(locked, a, "abc123", "upstream", project) {
+		t.Error("now an alias: isUnchanged() = true, want false")
+	}
+	if isUnchanged(locked, a, "abc123", "", t.TempDir()) {
+		t.Error("not present: isUnchanged() = true, want false")
+	}
+	partial := locked
+	partial.Data = map[string]any{"partial": map[string]any{"commit": "def456", "paths": []any{"SKILL.md"}}}
+	if isUnchanged(partial, a, "abc123", "", project) {
+		t.Error("partial update: isUnchanged() = true, want false")
+	}
+}
@@ -0,0 +1,72 @@
+package core
+
+import (
+	"fmt"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// NamespaceMode is when skills from registries are installed under a
+// registry-scoped name, e.g. org-a--go-review instead of go-review, so
+// same-named skills from different registries can sit side by side.
+type NamespaceMode string
+
+const (
+	// NamespaceNever installs skills under their own names. The default.
+	NamespaceNever NamespaceMode = "never"
+	// NamespaceOnConflict installs a skill under its namespaced name when
+	// its own name is taken by a skill from another source.
+	NamespaceOnConflict NamespaceMode = "on-conflict"
+	// NamespaceAlways installs every registry skill under its namespaced
+	// name.
+	NamespaceAlways NamespaceMode = "always"
+)
+
+// NamespaceModes lists the valid namespace modes.
+func NamespaceModes() []NamespaceMode {
+	return []NamespaceMode{NamespaceNever, NamespaceOnConflict, NamespaceAlways}
+}
+
+// ParseNamespaceMode validates a namespace mode. An empty mode is the
+// default.
+func ParseNamespaceMode(s string) (NamespaceMode, error) {
+	switch NamespaceMode(s) {
+	case "", NamespaceNever:
+		return NamespaceNever, nil
+	case NamespaceOnConflict, NamespaceAlways:
+		return NamespaceMode(s), nil
+	}
+	return "", fmt.Errorf("unknown skill namespace mode %q (want %q, %q, or %q)",
+		s, NamespaceNever, NamespaceOnConflict, NamespaceAlways)
+}
+
+// Namespaces returns the configured namespace mode. An invalid value falls
+// back to the default.
+func (s Settings) Namespaces() NamespaceMode {
+	m, err := ParseNamespaceMode(s.SkillNamespaces)
+	if err != nil {
+		return NamespaceNever
+	}
+	return m
+}
+
+// namespaceKey is the lock data field recording the namespace a skill was
+// installed under. Its upstream name is recorded under aliasOfKey.
+const namespaceKey = "namespace"
+
+// namespaceSeparator joins a namespace and a name. A double hyphen keeps
+// the result a valid asset name (see asset.ValidateName).
+const namespaceSeparator = "--"
+
+// NamespacedName returns the name a skill is installed under in namespace,
+// typically the name of the registry it comes from.
+func NamespacedName(namespace, name string) string {
+	return sanitizeName(namespace) + namespaceSeparator + name
+}
+
+// LockedNamespace returns the namespace a locked asset was installed
+// under, or "" if it wasn't namespaced.
+func LockedNamespace(locked asset.LockedAsset) string {
+	s, _ := locked.Data[namespaceKey].(string)
+	return s
+}
@@ -0,0 +1,66 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestParseNamespaceMode(t *testing.T) {
+	tests := []struct {
+		in   string
+		want NamespaceMode
+	}{
+		{"", NamespaceNever},
+		{"never", NamespaceNever},
+		{"on-conflict", NamespaceOnConflict},
+		{"always", NamespaceAlways},
+	}
+	for _, tt := range tests {
+		got, err := ParseNamespaceMode(tt.in)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseNamespaceMode(%q) = %q, %v; want %q", tt.in, got, err, tt.want)
+		}
+	}
+	if _, err := ParseNamespaceMode("sometimes"); err == nil {
+		t.Error("ParseNamespaceMode(sometimes) expected error")
+	}
+	if got := (Settings{SkillNamespaces: "sometimes"}).Namespaces(); got != NamespaceNever {
+		t.Errorf("Namespaces() with invalid setting = %q, want never", got)
+	}
+}
+
+func TestNamespacedName(t *testing.T) {
+	tests := []struct {
+		namespace, name, want string
+	}{
+		{"org-a", "go-review", "org-a--go-review"},
+		{"Org A", "go-review", "org-a--go-review"},
+	}
+	for _, tt := range tests {
+		got := NamespacedName(tt.namespace, tt.name)
+		if got != tt.want {
+			t.Errorf("NamespacedName(%q, %q) = %q, want %q", tt.namespace, tt.name, got, tt.want)
+		}
+		if err := ValidateAlias(got); err != nil {
+			t.Errorf("NamespacedName(%q, %q) = %q is not a valid name: %v", tt.namespace, tt.name, got, err)
+		}
+	}
+}
+
+func TestLockedNamespace(t *testing.T) {
+	locked := OrchestratorInstallResult{
+		Asset:     asset.Asset{Kind: asset.KindSkill, Name: "org-a--go-review"},
+		AliasOf:   "go-review",
+		Namespace: "org-a",
+	}.LockEntry(nil, nil)
+	if got := LockedNamespace(locked); got != "org-a" {
+		t.Errorf("LockedNamespace() = %q, want org-a", got)
+	}
+	if got := LockedUpstreamName(locked); got != "go-review" {
+		t.Errorf("LockedUpstreamName() = %q, want go-review", got)
+	}
+	if got := LockedNamespace(asset.LockedAsset{Kind: asset.KindSkill, Name: "go-review"}); got != "" {
+		t.Errorf("LockedNamespace(plain) = %q, want empty", got)
+	}
+}
@@ -0,0 +1,126 @@
+package core
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// Notification summarizes a completed long-running operation, such as
+// update --all or a sync, for a desktop notification or a webhook.
+type Notification struct {
+	Title   string // e.g. "duckrow sync"
+	Message string // e.g. "~/proj: 3 installed, 1 skipped, 0 errors"
+	Failed  bool
+}
+
+// Heading returns the title, marked when the operation failed.
+func (n Notification) Heading() string {
+	if n.Failed {
+		return n.Title + " failed"
+	}
+	return n.Title
+}
+
+// Text returns the notification as one line, e.g. for a chat message.
+func (n Notification) Text() string {
+	return n.Heading() + ": " + n.Message
+}
+
+// NotifyDesktop shows n as a desktop notification: through osascript on
+// macOS, notify-send on Linux and the BSDs, and a PowerShell toast on
+// Windows. It fails if the tool is not installed.
+func NotifyDesktop(n Notification) error {
+	cmd, err := desktopNotifyCmd(runtime.GOOS, n)
+	if err != nil {
+		return err
+	}
+	if cmd.Err != nil {
+		return fmt.Errorf("desktop notification: %s not found", cmd.Args[0])
+	}
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("desktop notification: %s: %v %s", cmd.Args[0], err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// windowsToastScript shows a toast with the title and message passed in the
+// environment, so neither needs quoting for PowerShell.
+const windowsToastScript = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] > $null
+$t = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$n = $t.GetElementsByTagName('text')
+$n.Item(0).AppendChild($t.CreateTextNode($env:DUCKROW_NOTIFY_TITLE)) > $null
+$n.Item(1).AppendChild($t.CreateTextNode($env:DUCKROW_NOTIFY_MESSAGE)) > $null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('duckrow').Show([Windows.UI.Notifications.ToastNotification]::new($t))`
+
+// desktopNotifyCmd returns the command showing n on goos. The title and
+// message are passed as arguments or environment variables, never spliced
+// into a script.
+func desktopNotifyCmd(goos string, n Notification) (*exec.Cmd, error) {
+	title := n.Heading()
+	switch goos {
+	case "darwin":
+		return exec.Command("osascript",
+			"-e", "on run argv",
+			"-e", "display notification (item 2 of argv) with title (item 1 of argv)",
+			"-e", "end run",
+			title, n.Message), nil
+	case "windows":
+		cmd := exec.Command("powershell", "-NoProfile", "-NonInteractive", "-Command", windowsToastScript)
+		cmd.Env = append(os.Environ(), "DUCKROW_NOTIFY_TITLE="+title, "DUCKROW_NOTIFY_MESSAGE="+n.Message)
+		return cmd, nil
+	case "linux", "freebsd", "openbsd", "netbsd":
+		urgency := "normal"
+		if n.Failed {
+			urgency = "critical"
+		}
+		return exec.Command("notify-send", "--app-name=duckrow", "--urgency="+urgency, title, n.Message), nil
+	}
+	return nil, fmt.Errorf("desktop notifications are not supported on %s", goos)
+}
+
+// PostWebhook posts n to rawURL as JSON with a "text" field, the payload
+// Slack incoming webhooks and most chat tools accept. It respects offline
+// mode. Webhook URLs carry their secret in the path, so errors name only the
+// host and the URL is never logged.
+func PostWebhook(rawURL string, n Notification) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("notification webhook: not an http(s) URL")
+	}
+	if err := checkNetwork(u.Scheme + "://" + u.Host); err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]string{"text": n.Text()})
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: CurrentTimeouts().Download, CheckRedirect: checkRedirect}
+	req, err := http.NewRequest(http.MethodPost, rawURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("notification webhook: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		var ue *url.Error
+		if errors.As(err, &ue) {
+			err = ue.Err
+		}
+		return fmt.Errorf("notification webhook: %s: %w", u.Host, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("notification webhook: %s returned %s", u.Host, resp.Status)
+	}
+	return nil
+}
@@ -0,0 +1,121 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestNotification_Text(t *testing.T) {
+	n := Notification{Title: "duckrow sync", Message: "proj: 3 installed, 0 skipped, 0 errors"}
+	if got, want := n.Text(), "duckrow sync: proj: 3 installed, 0 skipped, 0 errors"; got != want {
+		t.Errorf("Text() = %q, want %q", got, want)
+	}
+	n.Failed = true
+	if got, want := n.Text(), "duckrow sync failed: proj: 3 installed, 0 skipped, 0 errors"; got != want {
+		t.Errorf("Text() failed = %q, want %q", got, want)
+	}
+}
+
+func TestDesktopNotifyCmd(t *testing.T) {
+	n := Notification{Title: "duckrow sync", Message: `proj: "quoted" $(whoami)`, Failed: true}
+
+	tests := []struct {
+		goos     string
+		name     string
+		wantArgs []string // must appear verbatim as arguments
+		wantEnv  []string
+	}{
+		{"darwin", "osascript", []string{"duckrow sync failed", n.Message}, nil},
+		{"linux", "notify-send", []string{"--urgency=critical", "duckrow sync failed", n.Message}, nil},
+		{"windows", "powershell", nil, []string{"DUCKROW_NOTIFY_TITLE=duckrow sync failed", "DUCKROW_NOTIFY_MESSAGE=" + n.Message}},
+	}
+	for _, tt := range tests {
+		cmd, err := desktopNotifyCmd(tt.goos, n)
+		if err != nil {
+			t.Fatalf("desktopNotifyCmd(%s) error: %v", tt.goos, err)
+		}
+		if cmd.Args[0] != tt.name {
+			t.Errorf("desktopNotifyCmd(%s) runs %q, want %q", tt.goos, cmd.Args[0], tt.name)
+		}
+		for _, a := range tt.wantArgs {
+			if !slices.Contains(cmd.Args[1:], a) {
+				t.Errorf("desktopNotifyCmd(%s) args %q missing %q", tt.goos, cmd.Args, a)
+			}
+		}
+		for _, e := range tt.wantEnv {
+			if !slices.Contains(cmd.Env, e) {
+				t.Errorf("desktopNotifyCmd(%s) env missing %q", tt.goos, e)
+			}
+		}
+		for _, a := range cmd.Args {
+			if a != n.Message && strings.Contains(a, "whoami") {
+				t.Errorf("desktopNotifyCmd(%s) spliced the message into %q", tt.goos, a)
+			}
+		}
+	}
+
+	if _, err := desktopNotifyCmd("plan9", n); err == nil {
+		t.Error("desktopNotifyCmd(plan9) expected an error")
+	}
+}
+
+func TestPostWebhook(t *testing.T) {
+	var got map[string]string
+	var contentType string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		if r.Method != http.MethodPost {
+			w.WriteHeader(http.StatusMethodNotAllowed)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&got); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		if r.URL.Path == "/services/secret/fail" {
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer srv.Close()
+
+	n := Notification{Title: "duckrow skill update", Message: "proj: 2 updated, 1 up-to-date, 0 errors"}
+	if err := PostWebhook(srv.URL+"/services/secret", n); err != nil {
+		t.Fatalf("PostWebhook() error: %v", err)
+	}
+	if got["text"] != n.Text() {
+		t.Errorf("posted text = %q, want %q", got["text"], n.Text())
+	}
+	if contentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", contentType)
+	}
+
+	err := PostWebhook(srv.URL+"/services/secret/fail", n)
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Fatalf("PostWebhook() to failing hook = %v, want a 404 error", err)
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("error %q leaks the webhook path", err)
+	}
+
+	if err := PostWebhook("hooks.example.com/services/secret", n); err == nil {
+		t.Error("PostWebhook() without a scheme expected an error")
+	}
+}
+
+func TestPostWebhook_Offline(t *testing.T) {
+	SetOffline(true)
+	t.Cleanup(func() { SetOffline(false) })
+
+	err := PostWebhook("https://hooks.example.com/services/secret", Notification{Title: "duckrow sync"})
+	if !errors.Is(err, ErrOffline) {
+		t.Fatalf("PostWebhook() offline = %v, want ErrOffline", err)
+	}
+	if strings.Contains(err.Error(), "secret") {
+		t.Errorf("error %q leaks the webhook path", err)
+	}
+}
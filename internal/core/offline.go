@@ -0,0 +1,52 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+)
+
+// ErrOffline is returned (wrapped) by operations that would need network
+// access while offline mode is on.
+var ErrOffline = errors.New("offline mode")
+
+// offlineMode is process-wide: the CLI sets it once from --offline or the
+// offline setting, and every git and HTTP helper consults it.
+var offlineMode atomic.Bool
+
+// SetOffline turns offline mode on or off.
+func SetOffline(on bool) {
+	offlineMode.Store(on)
+}
+
+// Offline reports whether offline mode is on.
+func Offline() bool {
+	return offlineMode.Load()
+}
+
+// checkNetwork returns an error wrapping ErrOffline if offline mode is on and
+// url refers to a remote location, or wrapping ErrHostNotAllowed if its host
+// is outside the allowlist. Local paths and file:// URLs are allowed, so
+// clones from local mirrors keep working offline.
+func checkNetwork(url string) error {
+	if isLocalURL(url) {
+		return nil
+	}
+	if Offline() {
+		return fmt.Errorf("%w: cannot reach %s", ErrOffline, url)
+	}
+	return checkHost(url)
+}
+
+// isLocalURL reports whether a git or HTTP location is on the local
+// filesystem.
+func isLocalURL(url string) bool {
+	if url == "" || strings.HasPrefix(url, "file://") || filepath.IsAbs(url) {
+		return true
+	}
+	// Remote URLs have a scheme (https://, ssh://) or use the scp-like
+	// user@host:path form; both contain a colon.
+	return !strings.Contains(url, ":")
+}
@@ -0,0 +1,49 @@
+package core
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestCheckNetwork(t *testing.T) {
+	SetOffline(true)
+	t.Cleanup(func() { SetOffline(false) })
+
+	tests := []struct {
+		url     string
+		allowed bool
+	}{
+		{"https://github.com/org/repo.git", false},
+		{"git@github.com:org/repo.git", false},
+		{"ssh://git@example.com/org/repo.git", false},
+		{"file:///srv/mirror/repo.git", true},
+		{"/srv/mirror/repo", true},
+		{"relative/mirror", true},
+	}
+	for _, tt := range tests {
+		err := checkNetwork(tt.url)
+		if tt.allowed && err != nil {
+			t.Errorf("checkNetwork(%q) = %v, want nil", tt.url, err)
+		}
+		if !tt.allowed && !errors.Is(err, ErrOffline) {
+			t.Errorf("checkNetwork(%q) = %v, want ErrOffline", tt.url, err)
+		}
+	}
+
+	SetOffline(false)
+	if err := checkNetwork("https://github.com/org/repo.git"); err != nil {
+		t.Errorf("checkNetwork() online = %v, want nil", err)
+	}
+}
+
+func TestCloneRepo_Offline(t *testing.T) {
+	SetOffline(true)
+	t.Cleanup(func() { SetOffline(false) })
+
+	if _, err := cloneRepo("https://github.com/org/repo.git", "", true); !errors.Is(err, ErrOffline) {
+		t.Errorf("cloneRepo() error = %v, want ErrOffline", err)
+	}
+	if _, err := lsRemote("https://github.com/org/repo.git", ""); !errors.Is(err, ErrOffline) {
+		t.Errorf("lsRemote() error = %v, want ErrOffline", err)
+	}
+}
@@ -4,6 +4,8 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 
 	"github.com/barysiuk/duckrow/internal/core/asset"
 	"github.com/barysiuk/duckrow/internal/core/system"
@@ -12,19 +14,79 @@ import (
 // Orchestrator coordinates asset handlers and systems for install, remove,
 // scan, and sync operations. It lives in the core package so it can import
 // both the asset and system sub-packages without circular dependencies.
-type Orchestrator struct{}
+type Orchestrator struct {
+	scope system.Scope
+}
 
-// NewOrchestrator creates an Orchestrator.
+// NewOrchestrator creates an Orchestrator that installs into projects.
 func NewOrchestrator() *Orchestrator {
 	return &Orchestrator{}
 }
 
+// NewOrchestratorInScope creates an Orchestrator that installs to scope's
+// locations. In system.ScopeGlobal the target directory is the user's home
+// directory, and systems are resolved to their user-level locations.
+func NewOrchestratorInScope(scope system.Scope) *Orchestrator {
+	return &Orchestrator{scope: scope}
+}
+
 // OrchestratorInstallResult is the outcome of an asset installation.
 type OrchestratorInstallResult struct {
 	Asset   asset.Asset
 	Systems []string // system names that received the asset
 	Commit  string
 	Ref     string
+
+	// Files is the effective list of files copied for file-based assets,
+	// relative to the asset directory. Only set when ignore rules were in
+	// effect, so the lock can record exactly what was materialized.
+	Files []string
+
+	// Size is the footprint of the installed copy for file-based assets.
+	Size SkillSize
+
+	// AliasOf is the upstream name when the asset was installed under an
+	// alias, or "" otherwise.
+	AliasOf string
+
+	// Namespace is the namespace the asset was installed under (see
+	// NamespacedName), or "" otherwise. AliasOf is set along with it.
+	Namespace string
+
+	// Version and Constraint carry over from the install options.
+	Version    string
+	Constraint string
+
+	// Unchanged is set when the asset was already installed at Commit from
+	// the same source, so nothing was copied. Systems then lists only the
+	// systems it was newly linked or written for.
+	Unchanged bool
+
+	// MissingRequirements lists the project files the skill declares it
+	// needs (metadata.requires) that the target directory lacks.
+	MissingRequirements []string
+}
+
+// LockEntry returns the lock entry for the installed asset, built by its
+// kind's handler. Platforms and tags carry over from the registry entry or
+// the lock entry being updated.
+func (r OrchestratorInstallResult) LockEntry(platforms, tags []string) asset.LockedAsset {
+	handler, ok := asset.Get(r.Asset.Kind)
+	if !ok {
+		return asset.LockedAsset{Kind: r.Asset.Kind, Name: r.Asset.Name}
+	}
+	return handler.BuildLockEntry(r.Asset, asset.InstallInfo{
+		Commit:    r.Commit,
+		Ref:       r.Ref,
+		Files:     r.Files,
+		AliasOf:   r.AliasOf,
+		Namespace: r.Namespace,
+		Platforms: platforms,
+		Tags:      tags,
+
+		Version:    r.Version,
+		Constraint: r.Constraint,
+	})
 }
 
 // OrchestratorInstallOptions configures an installation.
@@ -35,6 +97,67 @@ type OrchestratorInstallOptions struct {
 	NameFilter      string // install only this specific asset
 	Commit          string // pin to a specific commit (for sync)
 	Force           bool
+	IgnorePatterns  []string // global ignore patterns applied before .duckrowignore
+
+	// Version is the semantic version Commit was resolved from and
+	// VersionConstraint the constraint asked for, if any (see
+	// ResolveSkillVersion). Both are recorded in the lock entry.
+	Version           string
+	VersionConstraint string
+
+	// CloneURLOverrides redirects lock sources to other clone URLs in
+	// SyncFromLock and when fetching a locked skill to check it for local
+	// changes (see LookupCloneURLOverride). Mirrors does the same for the
+	// entries of registries that declare a cloneUrl, where no override
+	// applies.
+	CloneURLOverrides map[string]string
+	Mirrors           RegistryMirrors
+
+	// NoValidate skips the SKILL.md frontmatter checks done before a skill
+	// is copied. Installs from the lock file set it, since those skills
+	// were already accepted into the project.
+	NoValidate bool
+	// LegacyNames accepts asset names outside the safe charset (see
+	// asset.ValidateName). Set when reinstalling lock entries, so names
+	// recorded before validation existed keep working.
+	LegacyNames bool
+
+	// Limits are the size thresholds checked for file-based assets before
+	// anything is copied. The zero value disables the check.
+	Limits SizeLimits
+	// ConfirmLarge is asked whether to proceed with an asset over Limits.
+	// When nil, oversized assets fail with a *LargeSkillError.
+	ConfirmLarge func(name string, size SkillSize) bool
+
+	// Alias installs the (single) discovered asset under a different name.
+	// The upstream name is reported in the result's AliasOf.
+	Alias string
+	// Namespace, typically the registry a skill comes from, is used to
+	// install skills under a namespaced name as NamespaceMode says. An
+	// explicit Alias wins, but is recorded as namespaced when it is the
+	// namespaced name (as when reinstalling a lock entry).
+	Namespace     string
+	NamespaceMode NamespaceMode
+	// Lock is checked for name conflicts: an asset whose name is already
+	// locked from a different source is not overwritten unless Force is set.
+	// It also tells which assets are installed already (see Reinstall and
+	// OverwriteModified). When nil, none of these checks are done.
+	Lock *LockFile
+	// ResolveConflict is asked for an alias to install a conflicting asset
+	// under instead. When nil or it returns "", the install fails with a
+	// *ConflictError.
+	ResolveConflict func(c AssetConflict) string
+
+	// Reinstall copies assets again even when they are installed at the
+	// resolved commit already. Without it, such assets are left as they
+	// are and reported as Unchanged (only checked when Lock is set).
+	Reinstall bool
+	// OverwriteModified replaces skills whose installed files were changed
+	// locally without asking. Otherwise ConfirmOverwrite is asked, and when
+	// it is nil or declines, the install fails with a *ModifiedSkillError.
+	// Skills are only checked when Lock is set and records them.
+	OverwriteModified bool
+	ConfirmOverwrite  func(name string, files []string) bool
 }
 
 // InstallFromSource is the main install entry point.
@@ -69,6 +192,11 @@ func (o *Orchestrator) InstallFromSource(
 		return nil, fmt.Errorf("discovering %s assets: %w", handler.DisplayName(), err)
 	}
 	if len(discovered) == 0 {
+		if kind == asset.KindSkill && !opts.NoValidate {
+			if errs := asset.InvalidSkills(tmpDir, source.SubPath); len(errs) > 0 {
+				return nil, &InvalidSkillError{Errs: relativizeSkillErrors(tmpDir, errs)}
+			}
+		}
 		return nil, fmt.Errorf("no %s assets found in source", handler.DisplayName())
 	}
 
@@ -78,14 +206,138 @@ func (o *Orchestrator) InstallFromSource(
 			return nil, fmt.Errorf("invalid %s %q: %w", handler.DisplayName(), a.Name, err)
 		}
 	}
+	if kind == asset.KindSkill && !opts.NoValidate {
+		var errs []error
+		for _, a := range discovered {
+			// A skill at the repository root has no directory name of its
+			// own to match (it would be the temporary clone directory).
+			dirName := ""
+			if a.PreparedPath != tmpDir {
+				dirName = filepath.Base(a.PreparedPath)
+			}
+			if err := asset.ValidateSkill(a.PreparedPath, dirName); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		if len(errs) > 0 {
+			return nil, &InvalidSkillError{Errs: relativizeSkillErrors(tmpDir, errs)}
+		}
+	}
 
-	// 4. Resolve target systems
+	// 4. Resolve names. Sources are filled in first so conflicts can be
+	// checked against the lock before anything is written.
+	if opts.Alias != "" {
+		if len(discovered) != 1 {
+			return nil, fmt.Errorf("cannot install %d %s assets under one name; select a single asset",
+				len(discovered), handler.DisplayName())
+		}
+		if err := ValidateAlias(opts.Alias); err != nil {
+			return nil, err
+		}
+	}
+	aliasOf := make([]string, len(discovered))
+	namespaces := make([]string, len(discovered))
+	namespace := ""
+	if kind == asset.KindSkill && opts.Alias == "" {
+		namespace = opts.Namespace
+	}
+	for i := range discovered {
+		a := &discovered[i]
+		if a.Source == "" {
+			a.Source = discoveredSource(source, tmpDir, *a)
+		}
+		switch {
+		case opts.Alias != "":
+			aliasOf[i] = a.Name
+			if kind == asset.KindSkill && opts.Namespace != "" && opts.Alias == NamespacedName(opts.Namespace, a.Name) {
+				// Reinstalling a namespaced skill under its recorded name.
+				namespaces[i] = opts.Namespace
+			}
+			a.Name = opts.Alias
+		case namespace != "" && opts.NamespaceMode == NamespaceAlways:
+			aliasOf[i] = a.Name
+			namespaces[i] = namespace
+			a.Name = NamespacedName(namespace, a.Name)
+			if err := ValidateAlias(a.Name); err != nil {
+				return nil, err
+			}
+		case !opts.LegacyNames:
+			if err := CheckInstallName(opts.Lock, kind, a.Name); err != nil {
+				return nil, err
+			}
+		}
+		if opts.Force || opts.Lock == nil {
+			continue
+		}
+		c := FindConflict(opts.Lock, kind, a.Name, a.Source)
+		if c == nil {
+			continue
+		}
+		alias := ""
+		if namespace != "" && opts.NamespaceMode == NamespaceOnConflict && namespaces[i] == "" {
+			if name := NamespacedName(namespace, a.Name); FindConflict(opts.Lock, kind, name, a.Source) == nil {
+				alias = name
+				namespaces[i] = namespace
+			}
+		}
+		if alias == "" && opts.ResolveConflict != nil {
+			alias = opts.ResolveConflict(*c)
+		}
+		if alias == "" {
+			return nil, &ConflictError{Conflict: *c}
+		}
+		if err := ValidateAlias(alias); err != nil {
+			return nil, err
+		}
+		if c := FindConflict(opts.Lock, kind, alias, a.Source); c != nil {
+			return nil, &ConflictError{Conflict: *c}
+		}
+		if aliasOf[i] == "" {
+			aliasOf[i] = a.Name
+		}
+		a.Name = alias
+	}
+	if err := checkDiscoveredCollisions(kind, discovered); err != nil {
+		return nil, err
+	}
+
+	// 5. Check sizes before touching the project, so a rejected asset
+	// doesn't leave a partial install behind.
+	sizes := make(map[string]SkillSize)
+	if kind == asset.KindSkill {
+		for _, a := range discovered {
+			ignore, err := LoadIgnoreMatcher(a.PreparedPath, opts.IgnorePatterns)
+			if err != nil {
+				return nil, err
+			}
+			size, err := measureSkill(a.PreparedPath, ignore)
+			if err != nil {
+				return nil, fmt.Errorf("measuring %q: %w", a.Name, err)
+			}
+			sizes[a.Name] = size
+			if !opts.Limits.Exceeded(size) {
+				continue
+			}
+			if opts.ConfirmLarge == nil || !opts.ConfirmLarge(a.Name, size) {
+				return nil, &LargeSkillError{Name: a.Name, Size: size, Limits: opts.Limits}
+			}
+		}
+	}
+
+	// 6. Resolve target systems
 	targets := opts.TargetSystems
-	if len(targets) == 0 {
+	if len(targets) == 0 && o.scope == system.ScopeGlobal {
+		// Default: the systems installed on this machine, or all of them,
+		// since none reads the canonical ~/.agents/skills copy directly.
+		if targets = system.Detect(); len(targets) == 0 {
+			targets = system.All()
+		}
+	} else if len(targets) == 0 {
 		// Default: universal systems only. Non-universal systems require
 		// explicit targeting (e.g. via --agents flag).
 		targets = system.Universal()
 	}
+	targets = system.InScope(targets, o.scope)
 	// Filter to systems that support this kind
 	var compatible []system.System
 	for _, s := range targets {
@@ -94,51 +346,114 @@ func (o *Orchestrator) InstallFromSource(
 		}
 	}
 
-	// 5. Install each asset into each compatible system
+	// Names that differ from existing entries only by case would overwrite
+	// them on case-insensitive filesystems.
+	if err := checkCaseCollisions(kind, opts.TargetDir, compatible, discovered); err != nil {
+		return nil, err
+	}
+	if kind == asset.KindSkill {
+		if err := checkPathLengths(opts.TargetDir, compatible, discovered, opts.IgnorePatterns); err != nil {
+			return nil, err
+		}
+	}
+
+	// 7. Resolve commits. Assets installed at the same commit from the same
+	// source are kept as they are; skills about to be replaced are checked
+	// for local changes first.
+	commits := make([]string, len(discovered))
+	unchanged := make([]*asset.LockedAsset, len(discovered))
+	for i, a := range discovered {
+		commit := opts.Commit
+		if commit == "" {
+			commit, _ = getAssetCommit(tmpDir, a)
+		}
+		commits[i] = commit
+
+		locked := FindLockedAsset(opts.Lock, kind, a.Name)
+		if locked == nil {
+			continue
+		}
+		if !opts.Reinstall && isUnchanged(*locked, a, commit, aliasOf[i], opts.TargetDir) {
+			unchanged[i] = locked
+			continue
+		}
+		if kind == asset.KindSkill {
+			if err := o.checkModified(source, a, commit, *locked, opts); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	// 8. Install each asset into each compatible system
 	var results []OrchestratorInstallResult
-	for _, a := range discovered {
+	for i, a := range discovered {
+		if locked := unchanged[i]; locked != nil {
+			var added []string
+			for _, sys := range compatible {
+				if installedFor(sys, kind, a.Name, opts.TargetDir) {
+					continue
+				}
+				if err := sys.Install(a, opts.TargetDir, systemInstallOptions(false)); err != nil {
+					return nil, fmt.Errorf("installing %q for %s: %w",
+						a.Name, sys.DisplayName(), err)
+				}
+				added = append(added, sys.Name())
+			}
+			results = append(results, OrchestratorInstallResult{
+				Asset:      a,
+				Systems:    added,
+				Commit:     commits[i],
+				Ref:        source.Ref,
+				Files:      LockedFiles(*locked),
+				Size:       sizes[a.Name],
+				AliasOf:    aliasOf[i],
+				Namespace:  namespaces[i],
+				Version:    opts.Version,
+				Constraint: opts.VersionConstraint,
+				Unchanged:  true,
+			})
+			continue
+		}
+
 		// For file-based assets (skills), copy to canonical location first.
+		var copiedFiles []string
 		if kind == asset.KindSkill {
-			if err := copyToCanonical(a, opts.TargetDir); err != nil {
+			files, err := copyToCanonical(a, opts.TargetDir, opts.IgnorePatterns)
+			if err != nil {
 				return nil, fmt.Errorf("copying %q to canonical location: %w", a.Name, err)
 			}
+			copiedFiles = files
 		}
 
-		var installedFor []string
+		// Files of an asset locked from this source are duckrow's own to
+		// replace; anything else takes Force.
+		overwrite := opts.Force || opts.Reinstall
+		if locked := FindLockedAsset(opts.Lock, kind, a.Name); locked != nil && locked.Source == a.Source {
+			overwrite = true
+		}
+
+		var installedSystems []string
 		for _, sys := range compatible {
-			if err := sys.Install(a, opts.TargetDir, system.InstallOptions{
-				Force: opts.Force,
-			}); err != nil {
+			if err := sys.Install(a, opts.TargetDir, systemInstallOptions(overwrite)); err != nil {
 				return nil, fmt.Errorf("installing %q for %s: %w",
 					a.Name, sys.DisplayName(), err)
 			}
-			installedFor = append(installedFor, sys.Name())
-		}
-
-		// Populate source if the handler didn't set it (e.g. skill/agent
-		// discovery doesn't know the origin URL). This ensures lock file
-		// entries always contain a valid source for sync.
-		if a.Source == "" {
-			relPath := ""
-			if a.PreparedPath != "" {
-				if rel, err := filepath.Rel(tmpDir, a.PreparedPath); err == nil && rel != "." {
-					relPath = filepath.ToSlash(rel)
-				}
-			}
-			a.Source = NormalizeSource(source.Host, source.Owner, source.Repo, relPath)
-		}
-
-		// Resolve commit for lock file
-		commit := opts.Commit
-		if commit == "" {
-			commit, _ = getAssetCommit(tmpDir, a)
+			installedSystems = append(installedSystems, sys.Name())
 		}
 
 		results = append(results, OrchestratorInstallResult{
-			Asset:   a,
-			Systems: installedFor,
-			Commit:  commit,
-			Ref:     source.Ref,
+			Asset:      a,
+			Systems:    installedSystems,
+			Commit:     commits[i],
+			Ref:        source.Ref,
+			Files:      copiedFiles,
+			Size:       sizes[a.Name],
+			AliasOf:    aliasOf[i],
+			Namespace:  namespaces[i],
+			Version:    opts.Version,
+			Constraint: opts.VersionConstraint,
+
+			MissingRequirements: MissingRequirements(SkillRequires(a.Meta), opts.TargetDir),
 		})
 	}
 
@@ -206,7 +521,7 @@ func (o *Orchestrator) RemoveAsset(
 		targetSystems = system.All()
 	}
 
-	for _, sys := range targetSystems {
+	for _, sys := range system.InScope(targetSystems, o.scope) {
 		if !sys.Supports(kind) {
 			continue
 		}
@@ -223,13 +538,46 @@ func (o *Orchestrator) RemoveAsset(
 	return nil
 }
 
+// UpdateAsset installs a new revision of the locked asset from source over
+// the installed one. Nothing is removed first: the revision is cloned and
+// validated, and a skill's installed files are checked for local changes
+// (see OverwriteModified), before anything in the project is touched, so an
+// update that fails leaves the installed copy as it was. An agent disabled
+// for a system is written to its disabled file and stays disabled.
+func (o *Orchestrator) UpdateAsset(
+	source *ParsedSource,
+	kind asset.Kind,
+	locked asset.LockedAsset,
+	opts OrchestratorInstallOptions,
+) ([]OrchestratorInstallResult, error) {
+	opts.Lock = &LockFile{Assets: []asset.LockedAsset{locked}}
+	opts.Force = true
+	opts.Reinstall = true
+	return o.InstallFromSource(source, kind, opts)
+}
+
 // ScanFolder discovers all installed assets of all kinds in a project folder.
 func (o *Orchestrator) ScanFolder(
 	projectDir string,
 ) (map[asset.Kind][]asset.InstalledAsset, error) {
 	result := make(map[asset.Kind][]asset.InstalledAsset)
 
-	systems := system.DetectInFolder(projectDir)
+	// Universal systems go first so that a skill's canonical copy in
+	// .agents/skills/ is the one reported, not a system's link to it.
+	detected := system.DetectInFolder(projectDir)
+	if o.scope == system.ScopeGlobal {
+		// Every system's user-level directories are looked at.
+		detected = system.InScope(system.All(), o.scope)
+	}
+	var systems, linked []system.System
+	for _, sys := range detected {
+		if sys.IsUniversal() {
+			systems = append(systems, sys)
+		} else {
+			linked = append(linked, sys)
+		}
+	}
+	systems = append(systems, linked...)
 	for _, kind := range asset.Kinds() {
 		for _, sys := range systems {
 			if !sys.Supports(kind) {
@@ -240,6 +588,9 @@ func (o *Orchestrator) ScanFolder(
 				return nil, fmt.Errorf("scanning %s for %s: %w",
 					sys.DisplayName(), kind, err)
 			}
+			for i := range installed {
+				installed[i].Systems = []string{sys.Name()}
+			}
 			result[kind] = deduplicateInstalled(result[kind], installed)
 		}
 	}
@@ -261,8 +612,15 @@ func (o *Orchestrator) SyncFromLock(
 			continue
 		}
 
+		if !PlatformMatches(locked.Platforms, CurrentPlatform()) {
+			result.Warnings = append(result.Warnings,
+				fmt.Sprintf("skipping %s %q: only for %s", handler.DisplayName(), locked.Name, strings.Join(locked.Platforms, ", ")))
+			result.Skipped++
+			continue
+		}
+
 		// Check if already installed
-		if !opts.Force && isAssetPresent(locked, opts.TargetDir) {
+		if !opts.Reinstall && isAssetPresent(locked, opts.TargetDir) {
 			result.Skipped++
 			continue
 		}
@@ -273,12 +631,23 @@ func (o *Orchestrator) SyncFromLock(
 				fmt.Errorf("%s %q: invalid source: %w", handler.DisplayName(), locked.Name, err))
 			continue
 		}
+		source.ApplyMirror(opts.CloneURLOverrides, opts.Mirrors.Locked(locked))
 
 		installOpts := opts
 		installOpts.Commit = locked.Commit
-		installOpts.NameFilter = locked.Name
+		installOpts.NameFilter = LockedUpstreamName(locked)
+		installOpts.NoValidate = true
+		installOpts.LegacyNames = true
+		if LockedAliasOf(locked) != "" {
+			installOpts.Alias = locked.Name
+		}
 
 		_, err = o.InstallFromSource(source, locked.Kind, installOpts)
+		if err == nil {
+			if p := LockedPartial(locked); p != nil {
+				_, _, err = o.UpdatePaths(source, locked, p.Commit, p.Paths, installOpts)
+			}
+		}
 		if err != nil {
 			result.Errors = append(result.Errors,
 				fmt.Errorf("%s %q: %w", handler.DisplayName(), locked.Name, err))
@@ -307,20 +676,47 @@ func cloneSource(source *ParsedSource, commit string) (string, error) {
 	return cloneRepo(source.CloneURL, source.Ref, false)
 }
 
-// copyToCanonical copies a discovered asset's files to the canonical location.
-func copyToCanonical(a asset.Asset, targetDir string) error {
+// discoveredSource returns the canonical lock source for an asset the handler
+// didn't set one for (e.g. skill/agent discovery doesn't know the origin URL).
+// This ensures lock file entries always contain a valid source for sync.
+func discoveredSource(source *ParsedSource, repoDir string, a asset.Asset) string {
+	relPath := ""
+	if a.PreparedPath != "" {
+		if rel, err := filepath.Rel(repoDir, a.PreparedPath); err == nil && rel != "." {
+			relPath = filepath.ToSlash(rel)
+		}
+	}
+	return NormalizeSource(source.Host, source.Owner, source.Repo, relPath)
+}
+
+// copyToCanonical copies a discovered asset's files to the canonical location,
+// honoring the global ignore patterns and the asset's .duckrowignore.
+// When any ignore rules apply, it returns the effective list of copied files.
+func copyToCanonical(a asset.Asset, targetDir string, ignorePatterns []string) ([]string, error) {
 	sanitized := sanitizeName(a.Name)
 	canonicalDir := filepath.Join(targetDir, canonicalSkillsDir, sanitized)
 
+	ignore, err := LoadIgnoreMatcher(a.PreparedPath, ignorePatterns)
+	if err != nil {
+		return nil, err
+	}
+
 	// Clean and recreate.
 	if err := os.RemoveAll(canonicalDir); err != nil {
-		return fmt.Errorf("cleaning canonical dir: %w", err)
+		return nil, fmt.Errorf("cleaning canonical dir: %w", err)
 	}
 	if err := os.MkdirAll(canonicalDir, 0o755); err != nil {
-		return fmt.Errorf("creating canonical dir: %w", err)
+		return nil, fmt.Errorf("creating canonical dir: %w", err)
 	}
 
-	return copyDirectory(a.PreparedPath, canonicalDir)
+	files, err := copyDirectoryFiltered(a.PreparedPath, longPath(canonicalDir), ignore)
+	if err != nil {
+		return nil, err
+	}
+	if ignore.Empty() {
+		return nil, nil
+	}
+	return files, nil
 }
 
 // removeCanonical removes the canonical copy of a skill.
@@ -348,22 +744,17 @@ func getAssetCommit(repoDir string, a asset.Asset) (string, error) {
 
 // isAssetPresent checks if an asset from the lock file is already installed.
 func isAssetPresent(locked asset.LockedAsset, targetDir string) bool {
-	switch locked.Kind {
-	case asset.KindSkill:
+	switch {
+	case locked.Kind == asset.KindSkill:
 		// Check if canonical directory exists.
 		canonical := filepath.Join(targetDir, canonicalSkillsDir, locked.Name)
 		info, err := os.Stat(canonical)
 		return err == nil && info.IsDir()
-	case asset.KindAgent:
-		// Check if any system has the rendered agent file.
-		filename := sanitizeName(locked.Name) + ".md"
-		for _, sys := range system.Supporting(asset.KindAgent) {
-			agentDir := sys.AssetDir(asset.KindAgent, targetDir)
-			if agentDir == "" {
-				continue
-			}
-			agentPath := filepath.Join(agentDir, filename)
-			if _, err := os.Stat(agentPath); err == nil {
+	case asset.IsSystemFile(locked.Kind):
+		// Check if any system has the agent, command, or rule file, or a
+		// disabled copy of it.
+		for _, sys := range system.Supporting(locked.Kind) {
+			if installedFor(sys, locked.Kind, locked.Name, targetDir) {
 				return true
 			}
 		}
@@ -374,21 +765,66 @@ func isAssetPresent(locked asset.LockedAsset, targetDir string) bool {
 	}
 }
 
+// isUnchanged reports whether a discovered asset is what the lock entry
+// already installed: same source, commit, and alias, with no partial
+// update on top, and present in the project.
+func isUnchanged(locked asset.LockedAsset, a asset.Asset, commit, aliasOf, targetDir string) bool {
+	return locked.Commit != "" && locked.Commit == commit &&
+		locked.Source == a.Source &&
+		LockedAliasOf(locked) == aliasOf &&
+		LockedPartial(locked) == nil &&
+		isAssetPresent(locked, targetDir)
+}
+
+// SystemsWithAsset returns the systems supporting kind that have an asset
+// installed under name in targetDir, whether or not the folder scan
+// detects them.
+func SystemsWithAsset(kind asset.Kind, name, targetDir string) []system.System {
+	var result []system.System
+	for _, sys := range system.Supporting(kind) {
+		if installedFor(sys, kind, name, targetDir) {
+			result = append(result, sys)
+		}
+	}
+	return result
+}
+
+// installedFor reports whether sys has an asset installed under name,
+// counting an agent disabled for it.
+func installedFor(sys system.System, kind asset.Kind, name, targetDir string) bool {
+	path := sys.AssetPath(kind, name, targetDir)
+	if path == "" {
+		return false
+	}
+	if _, err := os.Lstat(path); err == nil {
+		return true
+	}
+	_, err := os.Lstat(path + system.DisabledSuffix)
+	return err == nil
+}
+
 // deduplicateInstalled merges new assets into existing, deduplicating by name.
+// A duplicate adds its systems to the asset already found.
 func deduplicateInstalled(existing, new []asset.InstalledAsset) []asset.InstalledAsset {
-	seen := make(map[string]bool)
-	for _, a := range existing {
-		seen[a.Name] = true
+	seen := make(map[string]int)
+	for i, a := range existing {
+		seen[a.Name] = i
 	}
 
 	result := make([]asset.InstalledAsset, len(existing))
 	copy(result, existing)
 
 	for _, a := range new {
-		if !seen[a.Name] {
-			result = append(result, a)
-			seen[a.Name] = true
+		if i, ok := seen[a.Name]; ok {
+			for _, name := range a.Systems {
+				if !slices.Contains(result[i].Systems, name) {
+					result[i].Systems = append(result[i].Systems, name)
+				}
+			}
+			continue
 		}
+		result = append(result, a)
+		seen[a.Name] = len(result) - 1
 	}
 	return result
 }
@@ -0,0 +1,245 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// partialKey is the lock data key that records a partial update.
+const partialKey = "partial"
+
+// PartialUpdate records that some paths of an installed skill were refreshed
+// from a newer commit while the rest stays at the lock entry's commit. It is
+// kept under data.partial in the lock entry so sync can reproduce the mix.
+type PartialUpdate struct {
+	Commit string   // commit the paths were taken from
+	Paths  []string // skill-relative paths; a trailing / selects a directory
+}
+
+// String describes the update, e.g. "docs/, SKILL.md @ 1a2b3c4".
+func (p PartialUpdate) String() string {
+	return fmt.Sprintf("%s @ %s", strings.Join(p.Paths, ", "), TruncateCommit(p.Commit))
+}
+
+// LockedPartial returns the partial update recorded in a lock entry, or nil
+// if the whole skill is at the entry's commit.
+func LockedPartial(locked asset.LockedAsset) *PartialUpdate {
+	if locked.Data == nil {
+		return nil
+	}
+	m, ok := locked.Data[partialKey].(map[string]any)
+	if !ok {
+		return nil
+	}
+	p := &PartialUpdate{}
+	p.Commit, _ = m["commit"].(string)
+	switch v := m["paths"].(type) {
+	case []string:
+		p.Paths = v
+	case []any:
+		for _, item := range v {
+			if s, ok := item.(string); ok {
+				p.Paths = append(p.Paths, s)
+			}
+		}
+	}
+	if p.Commit == "" || len(p.Paths) == 0 {
+		return nil
+	}
+	return p
+}
+
+// CleanPartialPaths checks and normalizes the paths given to a partial
+// update: they must be relative to the skill root and stay inside it.
+// Directories keep (or gain, if given as "dir/") a trailing slash.
+func CleanPartialPaths(paths []string) ([]string, error) {
+	seen := make(map[string]bool)
+	var cleaned []string
+	for _, p := range paths {
+		p = strings.TrimSpace(filepath.ToSlash(p))
+		if p == "" {
+			continue
+		}
+		dir := strings.HasSuffix(p, "/")
+		c := path.Clean(p)
+		if path.IsAbs(c) || c == "." || c == ".." || strings.HasPrefix(c, "../") {
+			return nil, fmt.Errorf("invalid path %q: must be inside the skill", p)
+		}
+		if dir {
+			c += "/"
+		}
+		if !seen[c] {
+			seen[c] = true
+			cleaned = append(cleaned, c)
+		}
+	}
+	if len(cleaned) == 0 {
+		return nil, fmt.Errorf("no paths given")
+	}
+	return cleaned, nil
+}
+
+// partialPathMatch reports whether the skill-relative file rel is selected
+// by one of paths. A path selects the file itself and, unless it names a
+// file exactly, everything below it.
+func partialPathMatch(rel string, paths []string) bool {
+	for _, p := range paths {
+		dir := strings.TrimSuffix(p, "/")
+		if rel == dir && !strings.HasSuffix(p, "/") {
+			return true
+		}
+		if strings.HasPrefix(rel, dir+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// UpdatePaths refreshes the given paths of an installed skill to commit and
+// leaves every other file as it is. Files under the paths that no longer
+// exist upstream are removed. Paths from an earlier partial update are
+// refreshed along with the new ones, so one commit covers all of them.
+//
+// It returns the updated lock entry, with the partial update recorded (or
+// cleared, when commit is the entry's own commit), and the files that were
+// written or removed.
+func (o *Orchestrator) UpdatePaths(
+	source *ParsedSource,
+	locked asset.LockedAsset,
+	commit string,
+	paths []string,
+	opts OrchestratorInstallOptions,
+) (asset.LockedAsset, []string, error) {
+	paths, err := CleanPartialPaths(paths)
+	if err != nil {
+		return locked, nil, err
+	}
+	if prev := LockedPartial(locked); prev != nil {
+		paths, _ = CleanPartialPaths(append(prev.Paths, paths...))
+	}
+	sort.Strings(paths)
+
+	canonicalDir := filepath.Join(opts.TargetDir, canonicalSkillsDir, sanitizeName(locked.Name))
+	if !dirExists(canonicalDir) {
+		return locked, nil, fmt.Errorf("skill %q is not installed; run 'duckrow sync' first", locked.Name)
+	}
+
+	tmpDir, err := cloneSource(source, commit)
+	if err != nil {
+		return locked, nil, fmt.Errorf("cloning: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	handler, _ := asset.Get(asset.KindSkill)
+	discovered, err := handler.Discover(tmpDir, asset.DiscoverOptions{
+		SubPath:         source.SubPath,
+		IncludeInternal: true,
+		NameFilter:      LockedUpstreamName(locked),
+	})
+	if err != nil {
+		return locked, nil, fmt.Errorf("discovering skill: %w", err)
+	}
+	if len(discovered) != 1 {
+		return locked, nil, fmt.Errorf("skill %q not found at %s", LockedUpstreamName(locked), TruncateCommit(commit))
+	}
+	src := discovered[0].PreparedPath
+
+	ignore, err := LoadIgnoreMatcher(src, opts.IgnorePatterns)
+	if err != nil {
+		return locked, nil, err
+	}
+
+	// Collect the selected files upstream.
+	upstream := make(map[string]bool)
+	err = walkSkillFiles(src, ignore, func(rel string, isDir bool) error {
+		rel = filepath.ToSlash(rel)
+		if !isDir && partialPathMatch(rel, paths) {
+			upstream[rel] = true
+		}
+		return nil
+	})
+	if err != nil {
+		return locked, nil, err
+	}
+
+	// Remove selected files that are gone upstream.
+	var changed []string
+	local, err := treeFiles(canonicalDir)
+	if err != nil {
+		return locked, nil, err
+	}
+	for rel := range local {
+		rel = filepath.ToSlash(rel)
+		if partialPathMatch(rel, paths) && !upstream[rel] {
+			if err := os.Remove(filepath.Join(canonicalDir, filepath.FromSlash(rel))); err != nil {
+				return locked, nil, err
+			}
+			changed = append(changed, rel)
+		}
+	}
+
+	upstreamFiles := make([]string, 0, len(upstream))
+	for rel := range upstream {
+		upstreamFiles = append(upstreamFiles, rel)
+	}
+	for _, p := range paths {
+		if !containsMatch(upstreamFiles, p) && !containsMatch(changed, p) {
+			return locked, nil, fmt.Errorf("path %q not found in skill %q at %s", p, locked.Name, TruncateCommit(commit))
+		}
+	}
+
+	for rel := range upstream {
+		dst := filepath.Join(canonicalDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			return locked, nil, err
+		}
+		if err := copyFile(filepath.Join(src, filepath.FromSlash(rel)), longPath(dst)); err != nil {
+			return locked, nil, fmt.Errorf("copying %s: %w", rel, err)
+		}
+		changed = append(changed, rel)
+	}
+	sort.Strings(changed)
+
+	updated := locked
+	updated.Data = make(map[string]any, len(locked.Data)+1)
+	for k, v := range locked.Data {
+		updated.Data[k] = v
+	}
+	delete(updated.Data, partialKey)
+	delete(updated.Data, digestKey) // content changed; freeze again
+	if commit != locked.Commit {
+		updated.Data[partialKey] = map[string]any{"commit": commit, "paths": paths}
+	}
+	if LockedFiles(locked) != nil {
+		files, err := treeFiles(canonicalDir)
+		if err != nil {
+			return locked, nil, err
+		}
+		list := make([]string, 0, len(files))
+		for rel := range files {
+			list = append(list, filepath.ToSlash(rel))
+		}
+		sort.Strings(list)
+		updated.Data["files"] = list
+	}
+	if len(updated.Data) == 0 {
+		updated.Data = nil
+	}
+	return updated, changed, nil
+}
+
+// containsMatch reports whether any of files is selected by path p.
+func containsMatch(files []string, p string) bool {
+	for _, f := range files {
+		if partialPathMatch(f, []string{p}) {
+			return true
+		}
+	}
+	return false
+}
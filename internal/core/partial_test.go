@@ -0,0 +1,73 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestCleanPartialPaths(t *testing.T) {
+	got, err := CleanPartialPaths([]string{" docs/ ", "SKILL.md", "./scripts/../scripts/run.sh", "docs/", ""})
+	if err != nil {
+		t.Fatalf("CleanPartialPaths() error = %v", err)
+	}
+	want := []string{"docs/", "SKILL.md", "scripts/run.sh"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("CleanPartialPaths() = %v, want %v", got, want)
+	}
+
+	for _, bad := range []string{"../x", "/etc/passwd", ".", "docs/../.."} {
+		if _, err := CleanPartialPaths([]string{bad}); err == nil {
+			t.Errorf("CleanPartialPaths(%q) = nil error, want error", bad)
+		}
+	}
+	if _, err := CleanPartialPaths(nil); err == nil {
+		t.Error("CleanPartialPaths(nil) = nil error, want error")
+	}
+}
+
+func TestPartialPathMatch(t *testing.T) {
+	paths := []string{"docs/", "SKILL.md", "scripts"}
+	tests := []struct {
+		rel  string
+		want bool
+	}{
+		{"docs/guide.md", true},
+		{"docs/a/b.md", true},
+		{"docs", false}, // a file named like a selected directory
+		{"SKILL.md", true},
+		{"SKILL.md.bak", false},
+		{"scripts", true},
+		{"scripts/run.sh", true},
+		{"docsx/guide.md", false},
+	}
+	for _, tt := range tests {
+		if got := partialPathMatch(tt.rel, paths); got != tt.want {
+			t.Errorf("partialPathMatch(%q) = %v, want %v", tt.rel, got, tt.want)
+		}
+	}
+}
+
+func TestLockedPartial(t *testing.T) {
+	if p := LockedPartial(asset.LockedAsset{Name: "lint"}); p != nil {
+		t.Errorf("no data: LockedPartial() = %+v, want nil", p)
+	}
+
+	// As decoded from JSON.
+	locked := asset.LockedAsset{Name: "lint", Data: map[string]any{
+		"partial": map[string]any{"commit": "abcdef1234567", "paths": []any{"docs/", "SKILL.md"}},
+	}}
+	p := LockedPartial(locked)
+	if p == nil || p.Commit != "abcdef1234567" || !reflect.DeepEqual(p.Paths, []string{"docs/", "SKILL.md"}) {
+		t.Fatalf("LockedPartial() = %+v", p)
+	}
+	if got := p.String(); got != "docs/, SKILL.md @ abcdef1" {
+		t.Errorf("String() = %q", got)
+	}
+
+	locked.Data["partial"] = map[string]any{"commit": "abc"}
+	if p := LockedPartial(locked); p != nil {
+		t.Errorf("no paths: LockedPartial() = %+v, want nil", p)
+	}
+}
@@ -0,0 +1,68 @@
+package core
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// CurrentPlatform returns the platform duckrow runs on, as "GOOS/GOARCH".
+func CurrentPlatform() string {
+	return runtime.GOOS + "/" + runtime.GOARCH
+}
+
+// PlatformMatches reports whether platform ("GOOS/GOARCH") is one of
+// platforms. An entry is an OS ("darwin"), an OS and architecture
+// ("darwin/arm64"), or either part as "*" ("*/amd64"). No entries match
+// every platform.
+func PlatformMatches(platforms []string, platform string) bool {
+	if len(platforms) == 0 {
+		return true
+	}
+	goos, goarch, _ := strings.Cut(platform, "/")
+	for _, p := range platforms {
+		wantOS, wantArch, hasArch := strings.Cut(strings.ToLower(strings.TrimSpace(p)), "/")
+		if wantOS != "*" && wantOS != goos {
+			continue
+		}
+		if hasArch && wantArch != "*" && wantArch != goarch {
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// ValidatePlatform checks that p has the form PlatformMatches expects.
+func ValidatePlatform(p string) error {
+	goos, goarch, hasArch := strings.Cut(p, "/")
+	if goos == "" || (hasArch && goarch == "") || strings.Contains(goarch, "/") {
+		return fmt.Errorf("invalid platform %q (expected os, os/arch, or */arch)", p)
+	}
+	return nil
+}
+
+// PlatformError is returned when installing an asset that its registry
+// entry limits to other platforms.
+type PlatformError struct {
+	Kind      asset.Kind
+	Name      string
+	Platforms []string
+	Platform  string // the current platform
+}
+
+func (e *PlatformError) Error() string {
+	return fmt.Sprintf("%s %q is only available on %s (this is %s)",
+		e.Kind, e.Name, strings.Join(e.Platforms, ", "), e.Platform)
+}
+
+// CheckPlatform returns a *PlatformError when entry is limited to
+// platforms other than the current one.
+func CheckPlatform(kind asset.Kind, entry asset.RegistryEntry) error {
+	if PlatformMatches(entry.Platforms, CurrentPlatform()) {
+		return nil
+	}
+	return &PlatformError{Kind: kind, Name: entry.Name, Platforms: entry.Platforms, Platform: CurrentPlatform()}
+}
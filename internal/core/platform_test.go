@@ -0,0 +1,54 @@
+package core
+
+import (
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestPlatformMatches(t *testing.T) {
+	tests := []struct {
+		platforms []string
+		want      bool
+	}{
+		{nil, true},
+		{[]string{"linux"}, true},
+		{[]string{"linux/amd64"}, true},
+		{[]string{"Linux/AMD64"}, true},
+		{[]string{"*/amd64"}, true},
+		{[]string{"linux/*"}, true},
+		{[]string{"*"}, true},
+		{[]string{"linux/arm64"}, false},
+		{[]string{"darwin", "windows/amd64"}, false},
+		{[]string{"darwin", "linux/amd64"}, true},
+	}
+	for _, tt := range tests {
+		if got := PlatformMatches(tt.platforms, "linux/amd64"); got != tt.want {
+			t.Errorf("PlatformMatches(%v, linux/amd64) = %v, want %v", tt.platforms, got, tt.want)
+		}
+	}
+}
+
+func TestValidatePlatform(t *testing.T) {
+	for _, p := range []string{"linux", "darwin/arm64", "*/amd64", "*"} {
+		if err := ValidatePlatform(p); err != nil {
+			t.Errorf("ValidatePlatform(%q) error = %v", p, err)
+		}
+	}
+	for _, p := range []string{"", "/amd64", "linux/", "linux/amd64/v2"} {
+		if err := ValidatePlatform(p); err == nil {
+			t.Errorf("ValidatePlatform(%q) = nil, want error", p)
+		}
+	}
+}
+
+func TestCheckPlatform(t *testing.T) {
+	if err := CheckPlatform(asset.KindMCP, asset.RegistryEntry{Name: "db"}); err != nil {
+		t.Errorf("no platforms: CheckPlatform() = %v, want nil", err)
+	}
+	err := CheckPlatform(asset.KindMCP, asset.RegistryEntry{Name: "db", Platforms: []string{"plan9/arm"}})
+	want := `mcp "db" is only available on plan9/arm (this is ` + CurrentPlatform() + `)`
+	if err == nil || err.Error() != want {
+		t.Errorf("CheckPlatform() = %v, want %q", err, want)
+	}
+}
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultRequestsPerMinute caps network requests (clones, fetches,
+// ls-remote, HTTP and API calls) to remote hosts. Bursts of update checks and
+// hydration across large lock files otherwise look like abuse to GitHub.
+const DefaultRequestsPerMinute = 120
+
+const (
+	// maxRetryAfter is the longest Retry-After wait honored. Hosts asking for
+	// longer fail the request instead of hanging the command.
+	maxRetryAfter = time.Minute
+
+	// maxRateLimitRetries bounds how often one HTTP request is retried after
+	// being rate limited.
+	maxRateLimitRetries = 3
+)
+
+// RequestsPerMinute returns the configured request rate limit. Zero uses the
+// default; a negative value turns the limiter off.
+func (s Settings) RequestsPerMinute() int {
+	if s.MaxRequestsPerMinute == 0 {
+		return DefaultRequestsPerMinute
+	}
+	return s.MaxRequestsPerMinute
+}
+
+// NetworkStats counts the network requests made during this run.
+type NetworkStats struct {
+	Requests  int // requests to remote hosts
+	Delayed   int // requests held back by the rate limiter
+	Retried   int // HTTP requests retried after Retry-After
+	APICalls  int // GitHub API calls, included in Requests
+	Throttled time.Duration
+
+	// APINotModified counts GitHub API calls answered from the ETag cache.
+	APINotModified int
+}
+
+// requestLimiter spaces requests at least interval apart, plus up to a
+// quarter of it in random jitter so concurrent runs don't march in step.
+type requestLimiter struct {
+	mu       sync.Mutex
+	interval time.Duration
+	next     time.Time
+}
+
+// limiter and the counters are process-wide like offline mode: the CLI sets
+// the rate once from the settings, and every network helper goes through
+// throttle.
+var (
+	limiter = &requestLimiter{interval: time.Minute / DefaultRequestsPerMinute}
+
+	netRequests  atomic.Int64
+	netDelayed   atomic.Int64
+	netRetried   atomic.Int64
+	netAPICalls  atomic.Int64
+	netThrottled atomic.Int64
+
+	netAPINotModified atomic.Int64
+)
+
+// SetRequestsPerMinute sets the request rate limit. Zero or a negative value
+// turns the limiter off.
+func SetRequestsPerMinute(n int) {
+	limiter.mu.Lock()
+	defer limiter.mu.Unlock()
+	limiter.interval = 0
+	if n > 0 {
+		limiter.interval = time.Minute / time.Duration(n)
+	}
+	limiter.next = time.Time{}
+}
+
+// CurrentNetworkStats returns the network counters so far.
+func CurrentNetworkStats() NetworkStats {
+	return NetworkStats{
+		Requests:  int(netRequests.Load()),
+		Delayed:   int(netDelayed.Load()),
+		Retried:   int(netRetried.Load()),
+		APICalls:  int(netAPICalls.Load()),
+		Throttled: time.Duration(netThrottled.Load()),
+
+		APINotModified: int(netAPINotModified.Load()),
+	}
+}
+
+// throttle counts a request to url and waits for its turn under the rate
+// limit. Local paths and servers on this machine are neither counted nor
+// limited.
+func throttle(rawURL string) {
+	if isLocalURL(rawURL) || isLoopbackURL(rawURL) {
+		return
+	}
+	netRequests.Add(1)
+	if wait := limiter.reserve(time.Now()); wait > 0 {
+		netDelayed.Add(1)
+		netThrottled.Add(int64(wait))
+		time.Sleep(wait)
+	}
+}
+
+// isLoopbackURL reports whether a URL with a scheme points at localhost.
+func isLoopbackURL(rawURL string) bool {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Host == "" {
+		return false
+	}
+	host := u.Hostname()
+	if host == "localhost" {
+		return true
+	}
+	ip := net.ParseIP(host)
+	return ip != nil && ip.IsLoopback()
+}
+
+// reserve takes the next free slot and returns how long to wait for it.
+func (l *requestLimiter) reserve(now time.Time) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.interval <= 0 {
+		return 0
+	}
+	start := now
+	if l.next.After(now) {
+		start = l.next
+	}
+	l.next = start.Add(l.interval + rand.N(l.interval/4+1))
+	return start.Sub(now)
+}
+
+// doHTTP sends req through the rate limiter. A response that is rate limited
+// (429, or 403 with Retry-After or an exhausted GitHub quota) is retried
+// after the wait the host asks for, up to maxRetryAfter. Requests to, and
+// redirects to, hosts outside the allowlist are refused.
+func doHTTP(client *http.Client, req *http.Request) (*http.Response, error) {
+	if err := checkHost(req.URL.String()); err != nil {
+		return nil, err
+	}
+	if client.CheckRedirect == nil {
+		// A copy, so the caller's client is left as it was.
+		c := *client
+		c.CheckRedirect = checkRedirect
+		client = &c
+	}
+	for attempt := 0; ; attempt++ {
+		throttle(req.URL.String())
+		resp, err := client.Do(req)
+		if err != nil {
+			logEvent(LogError, "", "%s %s: %v", req.Method, redactURL(req.URL.String()), err)
+			return nil, err
+		}
+		logEvent(LogDebug, "", "%s %s: %s", req.Method, redactURL(req.URL.String()), resp.Status)
+		wait, limited := retryAfter(resp, time.Now())
+		if !limited {
+			return resp, nil
+		}
+		_ = resp.Body.Close()
+		if attempt >= maxRateLimitRetries || wait > maxRetryAfter {
+			err := fmt.Errorf("rate limited by %s; try again in %s", req.URL.Host, wait.Round(time.Second))
+			logEvent(LogError, "", "%v", err)
+			return nil, err
+		}
+		logEvent(LogWarn, "", "rate limited by %s; retrying in %s", req.URL.Host, wait.Round(time.Second))
+		netRetried.Add(1)
+		time.Sleep(wait)
+	}
+}
+
+// retryAfter reports whether resp is a rate-limit response and how long the
+// host asks to wait. Retry-After may be seconds or an HTTP date; GitHub's
+// X-RateLimit-Reset is a Unix time.
+func retryAfter(resp *http.Response, now time.Time) (time.Duration, bool) {
+	if resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusForbidden {
+		return 0, false
+	}
+	if v := resp.Header.Get("Retry-After"); v != "" {
+		if secs, err := strconv.Atoi(v); err == nil {
+			return max(time.Duration(secs)*time.Second, 0), true
+		}
+		if t, err := http.ParseTime(v); err == nil {
+			return max(t.Sub(now), 0), true
+		}
+	}
+	if resp.Header.Get("X-RateLimit-Remaining") == "0" {
+		if reset, err := strconv.ParseInt(resp.Header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+			return max(time.Unix(reset, 0).Sub(now), 0), true
+		}
+	}
+	if resp.StatusCode == http.StatusTooManyRequests {
+		return time.Second, true
+	}
+	return 0, false
+}
@@ -0,0 +1,141 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// noRateLimit turns the limiter off for one test.
+func noRateLimit(t *testing.T) {
+	t.Helper()
+	SetRequestsPerMinute(-1)
+	t.Cleanup(func() { SetRequestsPerMinute(DefaultRequestsPerMinute) })
+}
+
+func TestSettings_RequestsPerMinute(t *testing.T) {
+	if got := (Settings{}).RequestsPerMinute(); got != DefaultRequestsPerMinute {
+		t.Errorf("default RequestsPerMinute() = %d, want %d", got, DefaultRequestsPerMinute)
+	}
+	if got := (Settings{MaxRequestsPerMinute: -1}).RequestsPerMinute(); got != -1 {
+		t.Errorf("RequestsPerMinute() = %d, want -1", got)
+	}
+}
+
+func TestRequestLimiter_Reserve(t *testing.T) {
+	l := &requestLimiter{interval: time.Second}
+	now := time.Now()
+
+	if wait := l.reserve(now); wait != 0 {
+		t.Errorf("first reserve() = %s, want 0", wait)
+	}
+	wait := l.reserve(now)
+	if wait < time.Second || wait > time.Second+time.Second/4 {
+		t.Errorf("second reserve() = %s, want the interval plus up to 25%% jitter", wait)
+	}
+	third := l.reserve(now)
+	if third < wait+time.Second {
+		t.Errorf("third reserve() = %s, want after the second slot (%s)", third, wait)
+	}
+
+	// A quiet period doesn't bank slots for a later burst.
+	later := now.Add(time.Hour)
+	if wait := l.reserve(later); wait != 0 {
+		t.Errorf("reserve() after a pause = %s, want 0", wait)
+	}
+
+	l = &requestLimiter{}
+	if wait := l.reserve(now); wait != 0 {
+		t.Errorf("reserve() with the limiter off = %s, want 0", wait)
+	}
+}
+
+func TestThrottle_SkipsLocalPaths(t *testing.T) {
+	noRateLimit(t)
+	before := CurrentNetworkStats()
+	throttle("/srv/mirror/repo")
+	throttle("http://127.0.0.1:8080/org/repo.git")
+	throttle("https://github.com/org/repo.git")
+	if got := CurrentNetworkStats().Requests - before.Requests; got != 1 {
+		t.Errorf("counted %d requests, want 1 (local paths and servers are not counted)", got)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	resp := func(status int, headers ...string) *http.Response {
+		r := &http.Response{StatusCode: status, Header: http.Header{}}
+		for i := 0; i < len(headers); i += 2 {
+			r.Header.Set(headers[i], headers[i+1])
+		}
+		return r
+	}
+
+	tests := []struct {
+		name    string
+		resp    *http.Response
+		wait    time.Duration
+		limited bool
+	}{
+		{"ok", resp(200), 0, false},
+		{"not found", resp(404, "Retry-After", "5"), 0, false},
+		{"seconds", resp(429, "Retry-After", "7"), 7 * time.Second, true},
+		{"date", resp(429, "Retry-After", now.Add(30*time.Second).Format(http.TimeFormat)), 30 * time.Second, true},
+		{"secondary limit", resp(403, "Retry-After", "60"), time.Minute, true},
+		{"quota", resp(403, "X-RateLimit-Remaining", "0", "X-RateLimit-Reset", strconv.FormatInt(now.Add(2*time.Minute).Unix(), 10)), 2 * time.Minute, true},
+		{"forbidden", resp(403), 0, false},
+		{"bare 429", resp(429), time.Second, true},
+	}
+	for _, tt := range tests {
+		wait, limited := retryAfter(tt.resp, now)
+		if wait != tt.wait || limited != tt.limited {
+			t.Errorf("%s: retryAfter() = %s, %v; want %s, %v", tt.name, wait, limited, tt.wait, tt.limited)
+		}
+	}
+}
+
+func TestDoHTTP_HonorsRetryAfter(t *testing.T) {
+	noRateLimit(t)
+	var calls atomic.Int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls.Add(1) == 1 {
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer srv.Close()
+
+	before := CurrentNetworkStats()
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	resp, err := doHTTP(srv.Client(), req)
+	if err != nil {
+		t.Fatalf("doHTTP() error = %v", err)
+	}
+	_ = resp.Body.Close()
+	if resp.StatusCode != http.StatusOK || calls.Load() != 2 {
+		t.Errorf("status %d after %d calls, want 200 after 2", resp.StatusCode, calls.Load())
+	}
+	if got := CurrentNetworkStats().Retried - before.Retried; got != 1 {
+		t.Errorf("Retried = %d, want 1", got)
+	}
+}
+
+func TestDoHTTP_GivesUpOnLongWaits(t *testing.T) {
+	noRateLimit(t)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodGet, srv.URL, nil)
+	if _, err := doHTTP(srv.Client(), req); err == nil || !strings.Contains(err.Error(), "rate limited") {
+		t.Errorf("doHTTP() error = %v, want rate limited", err)
+	}
+}
@@ -0,0 +1,262 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+// RecommendedAssets returns the entries the manifest recommends, in kind
+// order, as assets of the registry at repo. Names the manifest does not
+// define are left out; ParseManifest warns about them.
+func RecommendedAssets(pm *ParsedManifest, repo string) []RegistryAssetInfo {
+	var infos []RegistryAssetInfo
+	for _, kind := range asset.Kinds() {
+		for _, name := range pm.Recommended[kind] {
+			for _, e := range pm.Entries[kind] {
+				if e.Name == name {
+					infos = append(infos, RegistryAssetInfo{
+						RegistryName: pm.Name,
+						RegistryRepo: repo,
+						Kind:         kind,
+						Entry:        e,
+					})
+					break
+				}
+			}
+		}
+	}
+	return infos
+}
+
+// RecommendedInstallOptions configures InstallRecommended.
+type RecommendedInstallOptions struct {
+	TargetDir string
+	// TargetSystems are the systems to install for; nil installs skills
+	// for the universal systems and MCPs and agents for the systems
+	// detected in TargetDir, as single installs do.
+	TargetSystems     []system.System
+	IgnorePatterns    []string
+	CloneURLOverrides map[string]string
+}
+
+// RecommendedResult is the outcome for one recommended asset.
+type RecommendedResult struct {
+	Asset RegistryAssetInfo
+	// Skipped is why the asset was left alone, or "" if it was installed.
+	Skipped     string
+	RequiredEnv []string // env vars an installed MCP needs
+}
+
+// RecommendedError is returned when one of the recommended assets fails to
+// install. Everything installed before it has been removed again.
+type RecommendedError struct {
+	Asset RegistryAssetInfo
+	Err   error
+}
+
+func (e *RecommendedError) Error() string {
+	return fmt.Sprintf("installing recommended %s %q: %v (nothing was installed)", e.Asset.Kind, e.Asset.Entry.Name, e.Err)
+}
+
+func (e *RecommendedError) Unwrap() error { return e.Err }
+
+// installedRecommended is an asset installed by InstallRecommended, kept
+// so it can be removed if a later one fails.
+type installedRecommended struct {
+	kind    asset.Kind
+	name    string
+	systems []system.System
+	lock    asset.LockedAsset
+
+	requiredEnv []string
+}
+
+// InstallRecommended installs assets into opts.TargetDir as one
+// transaction: either all of them are installed and locked, or, when one
+// fails, the ones installed before it are removed, the lock file is
+// restored, and a *RecommendedError is returned. Assets already in the lock,
+// excluded by the project, or for other platforms are skipped.
+func (o *Orchestrator) InstallRecommended(assets []RegistryAssetInfo, opts RecommendedInstallOptions) ([]RecommendedResult, error) {
+	lf, err := ReadLayeredLockFile(opts.TargetDir)
+	if err != nil {
+		return nil, fmt.Errorf("reading lock file: %w", err)
+	}
+	var excludes ExcludeRules
+	if lf != nil {
+		excludes = lf.Exclude
+	}
+
+	results := make([]RecommendedResult, 0, len(assets))
+	var done []installedRecommended
+	for _, info := range assets {
+		result := RecommendedResult{Asset: info}
+		if excludes.Match(info.Kind, info.Entry.Name) {
+			result.Skipped = "excluded in this project"
+			results = append(results, result)
+			continue
+		}
+		var platformErr *PlatformError
+		if err := CheckPlatform(info.Kind, info.Entry); errors.As(err, &platformErr) {
+			result.Skipped = "only for " + strings.Join(platformErr.Platforms, ", ")
+			results = append(results, result)
+			continue
+		}
+		if FindLockedAsset(lf, info.Kind, info.Entry.Name) != nil {
+			result.Skipped = "already installed"
+			results = append(results, result)
+			continue
+		}
+
+		installed, err := o.installRecommended(info, lf, opts)
+		if err != nil {
+			o.removeRecommended(done, opts.TargetDir)
+			return nil, &RecommendedError{Asset: info, Err: err}
+		}
+		done = append(done, installed)
+		result.RequiredEnv = installed.requiredEnv
+		results = append(results, result)
+	}
+
+	if err := lockRecommended(done, opts.TargetDir); err != nil {
+		o.removeRecommended(done, opts.TargetDir)
+		return nil, err
+	}
+	return results, nil
+}
+
+// installRecommended installs one recommended asset and returns what to
+// lock and, on failure, remove.
+func (o *Orchestrator) installRecommended(info RegistryAssetInfo, lf *LockFile, opts RecommendedInstallOptions) (installedRecommended, error) {
+	entry := info.Entry
+	installed := installedRecommended{kind: info.Kind, name: entry.Name}
+
+	if info.Kind == asset.KindMCP {
+		meta, ok := entry.Meta.(asset.MCPMeta)
+		if !ok {
+			return installed, fmt.Errorf("invalid MCP metadata")
+		}
+		if err := CheckMCPHost(meta); err != nil {
+			return installed, err
+		}
+		if err := CheckInstallName(lf, asset.KindMCP, entry.Name); err != nil {
+			return installed, err
+		}
+		if c := FindConflict(lf, asset.KindMCP, entry.Name, info.RegistryName, info.RegistryRepo); c != nil {
+			return installed, &ConflictError{Conflict: *c}
+		}
+		systems := opts.TargetSystems
+		if systems == nil {
+			systems = system.DetectInFolder(opts.TargetDir)
+		}
+		systems = supportingSystems(systems, asset.KindMCP)
+		if len(systems) == 0 && opts.TargetSystems == nil {
+			systems = system.Supporting(asset.KindMCP)
+		}
+		if len(systems) == 0 {
+			return installed, fmt.Errorf("none of the target systems support MCP configurations")
+		}
+		a := asset.Asset{Kind: asset.KindMCP, Name: entry.Name, Description: entry.Description, Meta: meta}
+		for _, sys := range systems {
+			if err := sys.Install(a, opts.TargetDir, system.InstallOptions{}); err != nil {
+				o.removeRecommended([]installedRecommended{installed}, opts.TargetDir)
+				return installed, fmt.Errorf("writing %s config: %w", sys.DisplayName(), err)
+			}
+			installed.systems = append(installed.systems, sys)
+		}
+
+		installed.requiredEnv = ExtractRequiredEnv(meta.Env)
+		handler, _ := asset.Get(asset.KindMCP)
+		installed.lock = handler.BuildLockEntry(a, asset.InstallInfo{
+			Registry:  info.RegistryName,
+			Platforms: entry.Platforms,
+			Tags:      entry.Tags,
+		})
+		return installed, nil
+	}
+
+	source, err := ParseSource(entry.Source)
+	if err != nil {
+		return installed, fmt.Errorf("invalid source %q: %w", entry.Source, err)
+	}
+	source.ApplyMirror(opts.CloneURLOverrides, entry.CloneURL)
+	targetSystems := opts.TargetSystems
+	if targetSystems != nil && info.Kind == asset.KindSkill {
+		targetSystems = system.Universal()
+		for _, sys := range opts.TargetSystems {
+			if !slices.ContainsFunc(targetSystems, func(s system.System) bool { return s.Name() == sys.Name() }) {
+				targetSystems = append(targetSystems, sys)
+			}
+		}
+	}
+	results, err := o.InstallFromSource(source, info.Kind, OrchestratorInstallOptions{
+		TargetDir:         opts.TargetDir,
+		TargetSystems:     targetSystems,
+		IncludeInternal:   true,
+		NameFilter:        entry.Name,
+		Commit:            entry.Commit,
+		IgnorePatterns:    opts.IgnorePatterns,
+		CloneURLOverrides: opts.CloneURLOverrides,
+		Lock:              lf,
+	})
+	if err != nil {
+		return installed, err
+	}
+	if len(results) == 0 {
+		return installed, fmt.Errorf("%s %q not found in %s", info.Kind, entry.Name, entry.Source)
+	}
+	r := results[0]
+	installed.name = r.Asset.Name
+	if r.Commit == "" {
+		o.removeRecommended([]installedRecommended{installed}, opts.TargetDir)
+		return installed, fmt.Errorf("could not determine the commit of %q", entry.Name)
+	}
+	installed.lock = r.LockEntry(entry.Platforms, entry.Tags)
+	return installed, nil
+}
+
+// lockRecommended adds the installed assets to the lock file, restoring
+// the file as it was if any of them cannot be added.
+func lockRecommended(done []installedRecommended, targetDir string) error {
+	if len(done) == 0 {
+		return nil
+	}
+	path := LockFilePath(targetDir)
+	before, readErr := os.ReadFile(path)
+	for _, d := range done {
+		if err := AddOrUpdateAsset(targetDir, d.lock); err != nil {
+			switch {
+			case readErr == nil:
+				_ = os.WriteFile(path, before, 0o644)
+			case os.IsNotExist(readErr):
+				_ = os.Remove(path)
+			}
+			return fmt.Errorf("updating lock file: %w", err)
+		}
+	}
+	return nil
+}
+
+// removeRecommended removes assets installed by InstallRecommended, most
+// recent first. Errors are ignored: it runs after another error.
+func (o *Orchestrator) removeRecommended(done []installedRecommended, targetDir string) {
+	for _, d := range slices.Backward(done) {
+		_ = o.RemoveAsset(d.kind, d.name, targetDir, d.systems)
+	}
+}
+
+// supportingSystems returns the systems that support kind.
+func supportingSystems(systems []system.System, kind asset.Kind) []system.System {
+	var out []system.System
+	for _, sys := range systems {
+		if sys.Supports(kind) {
+			out = append(out, sys)
+		}
+	}
+	return out
+}
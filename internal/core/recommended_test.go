@@ -0,0 +1,109 @@
+package core
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+func TestRecommendedAssets(t *testing.T) {
+	var raw RegistryManifest
+	if err := json.Unmarshal([]byte(`{
+		"name": "my-org",
+		"skills": [{"name": "go-review", "source": "github.com/o/r/go-review"}],
+		"mcps": [{"name": "db", "command": "dbtool"}, {"name": "cache", "command": "cachetool"}],
+		"recommended": {"mcp": ["db"], "skill": ["go-review", "ghost"], "widget": ["x"]}
+	}`), &raw); err != nil {
+		t.Fatal(err)
+	}
+	pm, err := ParseManifest(&raw)
+	if err != nil {
+		t.Fatalf("ParseManifest() error = %v", err)
+	}
+	for _, want := range []string{`recommended skill "ghost" is not in the manifest`, `unknown asset kind "widget" in recommended`} {
+		if !slices.ContainsFunc(pm.Warnings, func(w string) bool { return strings.Contains(w, want) }) {
+			t.Errorf("Warnings = %v, want one containing %q", pm.Warnings, want)
+		}
+	}
+
+	got := RecommendedAssets(pm, "git@example.com:o/registry.git")
+	var names []string
+	for _, info := range got {
+		names = append(names, string(info.Kind)+"/"+info.Entry.Name)
+		if info.RegistryName != "my-org" || info.RegistryRepo != "git@example.com:o/registry.git" {
+			t.Errorf("%s: registry = %q %q", info.Entry.Name, info.RegistryName, info.RegistryRepo)
+		}
+	}
+	if want := []string{"skill/go-review", "mcp/db"}; !slices.Equal(names, want) {
+		t.Errorf("RecommendedAssets() = %v, want %v", names, want)
+	}
+}
+
+func TestInstallRecommended(t *testing.T) {
+	project := t.TempDir()
+	systems, err := system.ByNames([]string{"claude-code"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	mcp := func(name string, platforms ...string) RegistryAssetInfo {
+		return RegistryAssetInfo{
+			RegistryName: "my-org",
+			Kind:         asset.KindMCP,
+			Entry: asset.RegistryEntry{
+				Name:      name,
+				Meta:      asset.MCPMeta{Command: name + "-tool", Env: []string{"TOKEN"}},
+				Platforms: platforms,
+			},
+		}
+	}
+	if err := AddOrUpdateAsset(project, asset.LockedAsset{Kind: asset.KindMCP, Name: "locked"}); err != nil {
+		t.Fatal(err)
+	}
+	before, err := os.ReadFile(LockFilePath(project))
+	if err != nil {
+		t.Fatal(err)
+	}
+	opts := RecommendedInstallOptions{TargetDir: project, TargetSystems: systems}
+
+	// A failure removes what was installed before it and keeps the lock.
+	broken := RegistryAssetInfo{Kind: asset.KindMCP, Entry: asset.RegistryEntry{Name: "broken"}}
+	_, err = NewOrchestrator().InstallRecommended([]RegistryAssetInfo{mcp("db"), broken}, opts)
+	var recErr *RecommendedError
+	if !errors.As(err, &recErr) || recErr.Asset.Entry.Name != "broken" {
+		t.Fatalf("InstallRecommended() error = %v, want a *RecommendedError for broken", err)
+	}
+	if data, _ := os.ReadFile(filepath.Join(project, ".mcp.json")); strings.Contains(string(data), "db-tool") {
+		t.Errorf(".mcp.json still has db after a failed install: %s", data)
+	}
+	if after, _ := os.ReadFile(LockFilePath(project)); string(after) != string(before) {
+		t.Errorf("lock file changed after a failed install:\n%s", after)
+	}
+
+	results, err := NewOrchestrator().InstallRecommended([]RegistryAssetInfo{mcp("db"), mcp("locked"), mcp("mac", "plan9")}, opts)
+	if err != nil {
+		t.Fatalf("InstallRecommended() error = %v", err)
+	}
+	want := map[string]string{"db": "", "locked": "already installed", "mac": "only for plan9"}
+	for _, r := range results {
+		if r.Skipped != want[r.Asset.Entry.Name] {
+			t.Errorf("%s: Skipped = %q, want %q", r.Asset.Entry.Name, r.Skipped, want[r.Asset.Entry.Name])
+		}
+	}
+	if len(results) != 3 || !slices.Equal(results[0].RequiredEnv, []string{"TOKEN"}) {
+		t.Errorf("results = %+v, want db needing TOKEN first", results)
+	}
+	lf, err := ReadLockFile(project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if FindLockedAsset(lf, asset.KindMCP, "db") == nil || FindLockedAsset(lf, asset.KindMCP, "mac") != nil {
+		t.Errorf("lock assets = %+v, want db and not mac", lf.Assets)
+	}
+}
@@ -4,10 +4,15 @@ import (
 	"crypto/sha256"
 	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
+	"path"
 	"path/filepath"
+	"regexp"
+	"slices"
+	"sort"
 	"strings"
 	"time"
 
@@ -16,8 +21,11 @@ import (
 
 const (
 	registryManifestFile = "duckrow.json"
-	registryCloneTimeout = 60 * time.Second
-	registryPullTimeout  = 30 * time.Second
+
+	// localRegistryFile marks a local registry's directory under the
+	// registries dir. It holds the absolute path of the registry on disk;
+	// the directory keeps only caches, never a copy of the manifest.
+	localRegistryFile = "duckrow.local"
 )
 
 // RegistryManager handles registry operations: add, remove, refresh, and list assets.
@@ -30,6 +38,13 @@ func NewRegistryManager(registriesDir string) *RegistryManager {
 	return &RegistryManager{registriesDir: registriesDir}
 }
 
+// dirKeyUnsafe matches characters that RegistryDirKey replaces with dashes.
+var dirKeyUnsafe = regexp.MustCompile(`[^a-z0-9._-]`)
+
+// maxDirKeyReadable caps the readable part of a registry directory key, so
+// hostile URLs can't exceed file name limits.
+const maxDirKeyReadable = 100
+
 // RegistryDirKey derives a unique, filesystem-safe directory name from a repo URL.
 // This ensures that two registries with different repos but the same manifest name
 // are stored separately on disk.
@@ -53,9 +68,13 @@ func RegistryDirKey(repoURL string) string {
 			readable = readable[slashIdx+1:]
 		}
 	}
-	// Replace path separators with dashes
-	readable = strings.ReplaceAll(readable, "/", "-")
-	readable = strings.ReplaceAll(readable, string(filepath.Separator), "-")
+	// Replace path separators and anything else that isn't safe in a
+	// directory name on every platform with dashes. Typical URLs only
+	// contain safe characters, so their keys are unchanged.
+	readable = dirKeyUnsafe.ReplaceAllString(readable, "-")
+	if len(readable) > maxDirKeyReadable {
+		readable = readable[:maxDirKeyReadable]
+	}
 
 	// Add a short hash for uniqueness
 	h := sha256.Sum256([]byte(repoURL))
@@ -70,17 +89,22 @@ func RegistryDirKey(repoURL string) string {
 // --- Manifest types ---
 
 // RegistryManifest is the parsed duckrow.json from a registry repo.
-// It supports both v1 (Skills/MCPs/Agents arrays) and v2 (Assets map) formats.
+// It supports both v1 (Skills/MCPs/Agents/Commands arrays) and v2 (Assets map) formats.
 // The Version field discriminates: 0 or 1 = v1, 2 = v2.
 type RegistryManifest struct {
 	Version     int                        `json:"version,omitempty"`
 	Name        string                     `json:"name"`
 	Description string                     `json:"description,omitempty"`
+	Hydrate     *bool                      `json:"hydrate,omitempty"` // false opts the whole registry out of commit hydration
 	Assets      map[string]json.RawMessage `json:"assets,omitempty"`
+	// Recommended names, by kind, the entries to offer for installing
+	// right after the registry is added, e.g. {"skill": ["go-review"]}.
+	Recommended map[string][]string `json:"recommended,omitempty"`
 	// v1 legacy fields — populated when reading v1 manifests, converted internally.
 	Skills   []json.RawMessage `json:"skills,omitempty"`
 	MCPs     []json.RawMessage `json:"mcps,omitempty"`
 	Agents   []json.RawMessage `json:"agents,omitempty"`
+	Commands []json.RawMessage `json:"commands,omitempty"`
 	Warnings []string          `json:"-"` // validation warnings, not serialized
 }
 
@@ -88,7 +112,9 @@ type RegistryManifest struct {
 type ParsedManifest struct {
 	Name        string
 	Description string
+	NoHydrate   bool // manifest sets "hydrate": false
 	Entries     map[asset.Kind][]asset.RegistryEntry
+	Recommended map[asset.Kind][]string // see RegistryManifest.Recommended
 	Warnings    []string
 }
 
@@ -97,6 +123,7 @@ func ParseManifest(raw *RegistryManifest) (*ParsedManifest, error) {
 	pm := &ParsedManifest{
 		Name:        raw.Name,
 		Description: raw.Description,
+		NoHydrate:   raw.Hydrate != nil && !*raw.Hydrate,
 		Entries:     make(map[asset.Kind][]asset.RegistryEntry),
 		Warnings:    raw.Warnings,
 	}
@@ -104,7 +131,7 @@ func ParseManifest(raw *RegistryManifest) (*ParsedManifest, error) {
 	// Build the assets map — either from v2 Assets field or v1 legacy fields.
 	assetsMap := raw.Assets
 	if len(assetsMap) == 0 {
-		// v1 format: convert Skills/MCPs/Agents arrays to the assets map.
+		// v1 format: convert Skills/MCPs/Agents/Commands arrays to the assets map.
 		assetsMap = make(map[string]json.RawMessage)
 		if len(raw.Skills) > 0 {
 			skillsJSON, err := json.Marshal(raw.Skills)
@@ -127,6 +154,13 @@ func ParseManifest(raw *RegistryManifest) (*ParsedManifest, error) {
 			}
 			assetsMap[string(asset.KindAgent)] = agentsJSON
 		}
+		if len(raw.Commands) > 0 {
+			commandsJSON, err := json.Marshal(raw.Commands)
+			if err != nil {
+				return nil, fmt.Errorf("marshaling v1 commands: %w", err)
+			}
+			assetsMap[string(asset.KindCommand)] = commandsJSON
+		}
 	}
 
 	for kindStr, data := range assetsMap {
@@ -145,6 +179,48 @@ func ParseManifest(raw *RegistryManifest) (*ParsedManifest, error) {
 	}
 
 	// Validate entries and add warnings.
+	for _, kind := range asset.Kinds() {
+		for _, e := range pm.Entries[kind] {
+			if e.Name == "" {
+				continue
+			}
+			var nameErr *asset.NameError
+			if errors.As(asset.ValidateName(e.Name), &nameErr) {
+				pm.Warnings = append(pm.Warnings,
+					fmt.Sprintf("%s %q has an invalid name: %s (install it with --as <name>)", kind, e.Name, nameErr.Reason))
+			}
+			for _, p := range e.Platforms {
+				if err := ValidatePlatform(p); err != nil {
+					pm.Warnings = append(pm.Warnings, fmt.Sprintf("%s %q has an %v", kind, e.Name, err))
+				}
+			}
+		}
+	}
+	recommendedKinds := make([]string, 0, len(raw.Recommended))
+	for kindStr := range raw.Recommended {
+		recommendedKinds = append(recommendedKinds, kindStr)
+	}
+	sort.Strings(recommendedKinds)
+	for _, kindStr := range recommendedKinds {
+		kind := asset.Kind(kindStr)
+		names := raw.Recommended[kindStr]
+		if _, ok := asset.Get(kind); !ok {
+			pm.Warnings = append(pm.Warnings,
+				fmt.Sprintf("unknown asset kind %q in recommended; skipping", kindStr))
+			continue
+		}
+		for _, name := range names {
+			if !slices.ContainsFunc(pm.Entries[kind], func(e asset.RegistryEntry) bool { return e.Name == name }) {
+				pm.Warnings = append(pm.Warnings,
+					fmt.Sprintf("recommended %s %q is not in the manifest", kind, name))
+				continue
+			}
+			if pm.Recommended == nil {
+				pm.Recommended = make(map[asset.Kind][]string)
+			}
+			pm.Recommended[kind] = append(pm.Recommended[kind], name)
+		}
+	}
 	if skills, ok := pm.Entries[asset.KindSkill]; ok {
 		for _, s := range skills {
 			if s.Source != "" && !isCanonicalSource(s.Source) {
@@ -184,11 +260,16 @@ func ParseManifest(raw *RegistryManifest) (*ParsedManifest, error) {
 // Add clones a registry repo and returns the parsed manifest.
 // The clone is stored in a directory derived from the repo URL to avoid
 // collisions when different repos share the same manifest name.
+// An http(s) URL of a manifest file is downloaded instead of cloned (see
+// IsManifestURL). Use AddLocal to register a directory on disk.
 func (rm *RegistryManager) Add(repoURL string) (*RegistryManifest, error) {
 	repoURL = strings.TrimSpace(repoURL)
 	if repoURL == "" {
 		return nil, fmt.Errorf("repository URL is required")
 	}
+	if IsManifestURL(repoURL) {
+		return rm.addHTTP(repoURL)
+	}
 
 	// Clone to a temp directory first to read the manifest
 	tmpDir, err := os.MkdirTemp("", "duckrow-registry-*")
@@ -197,7 +278,7 @@ func (rm *RegistryManager) Add(repoURL string) (*RegistryManifest, error) {
 	}
 	defer func() { _ = os.RemoveAll(tmpDir) }()
 
-	if err := gitClone(repoURL, "", tmpDir, registryCloneTimeout); err != nil {
+	if err := gitClone(repoURL, "", tmpDir, CurrentTimeouts().Clone); err != nil {
 		return nil, fmt.Errorf("cloning registry: %w", err)
 	}
 
@@ -226,20 +307,120 @@ func (rm *RegistryManager) Add(repoURL string) (*RegistryManifest, error) {
 	}
 
 	// Clone directly to the final location (cleaner than moving)
-	if err := gitClone(repoURL, "", destDir, registryCloneTimeout); err != nil {
+	if err := gitClone(repoURL, "", destDir, CurrentTimeouts().Clone); err != nil {
 		return nil, fmt.Errorf("cloning registry to final location: %w", err)
 	}
 
-	// Populate warnings by parsing through handlers.
+	// Populate warnings by parsing through handlers and persist them so
+	// they can be inspected later with `registry warnings`.
 	pm, parseErr := ParseManifest(manifest)
 	if parseErr == nil {
 		manifest.Warnings = pm.Warnings
+		_ = writeCachedWarnings(destDir, pm.Warnings)
 	}
 
 	return manifest, nil
 }
 
+// IsLocalRegistryPath reports whether a registry argument names a directory
+// on disk rather than a git URL: an absolute path, or one starting with
+// ./, ../ or ~/.
+func IsLocalRegistryPath(arg string) bool {
+	arg = strings.TrimSpace(arg)
+	if arg == "." || arg == ".." || arg == "~" || filepath.IsAbs(arg) {
+		return true
+	}
+	for _, prefix := range []string{"./", "../", "~/", ".\\", "..\\"} {
+		if strings.HasPrefix(arg, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+// AddLocal registers a registry directory on disk without cloning it and
+// returns its absolute path, which is the registry's repo from then on,
+// along with its manifest. Local registries are live: LoadManifest reads
+// the manifest straight from the directory, so edits show up without a
+// refresh.
+func (rm *RegistryManager) AddLocal(path string) (string, *RegistryManifest, error) {
+	path = strings.TrimSpace(path)
+	if path == "" {
+		return "", nil, fmt.Errorf("registry path is required")
+	}
+	absPath, err := filepath.Abs(expandPath(path))
+	if err != nil {
+		return "", nil, fmt.Errorf("resolving registry path: %w", err)
+	}
+	if !dirExists(absPath) {
+		return "", nil, fmt.Errorf("registry directory %s not found", absPath)
+	}
+
+	manifest, err := readManifest(absPath)
+	if err != nil {
+		return "", nil, fmt.Errorf("reading manifest: %w", err)
+	}
+	if manifest.Name == "" {
+		return "", nil, fmt.Errorf("registry manifest missing required 'name' field")
+	}
+
+	destDir := filepath.Join(rm.registriesDir, RegistryDirKey(absPath))
+	if dirExists(destDir) {
+		if err := os.RemoveAll(destDir); err != nil {
+			return "", nil, fmt.Errorf("removing existing registry directory: %w", err)
+		}
+	}
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return "", nil, fmt.Errorf("creating registries directory: %w", err)
+	}
+	if err := os.WriteFile(filepath.Join(destDir, localRegistryFile), []byte(absPath+"\n"), 0o644); err != nil {
+		return "", nil, fmt.Errorf("writing %s: %w", localRegistryFile, err)
+	}
+
+	pm, parseErr := ParseManifest(manifest)
+	if parseErr == nil {
+		manifest.Warnings = pm.Warnings
+		_ = writeCachedWarnings(destDir, pm.Warnings)
+	}
+
+	return absPath, manifest, nil
+}
+
+// localRegistryPath returns the directory a local registry was added from,
+// or "" if the registry directory is a clone.
+func localRegistryPath(registryDir string) string {
+	data, err := os.ReadFile(filepath.Join(registryDir, localRegistryFile))
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// IsLocal reports whether a registry was added from a directory on disk
+// with AddLocal.
+func (rm *RegistryManager) IsLocal(repoURL string) bool {
+	return localRegistryPath(filepath.Join(rm.registriesDir, RegistryDirKey(repoURL))) != ""
+}
+
+// sourceDir returns the directory holding a registry's manifest: the
+// directory on disk for a local registry, otherwise the clone.
+func (rm *RegistryManager) sourceDir(repoURL string) (string, error) {
+	dir := filepath.Join(rm.registriesDir, RegistryDirKey(repoURL))
+	if !dirExists(dir) {
+		return "", fmt.Errorf("registry clone for %q not found", repoURL)
+	}
+	if local := localRegistryPath(dir); local != "" {
+		if !dirExists(local) {
+			return "", fmt.Errorf("local registry directory %s not found", local)
+		}
+		return local, nil
+	}
+	return dir, nil
+}
+
 // Remove deletes a registry clone from disk using the repo URL to locate it.
+// For a local registry only duckrow's caches are removed; the directory it
+// was added from is left alone.
 func (rm *RegistryManager) Remove(repoURL string) error {
 	repoURL = strings.TrimSpace(repoURL)
 	if repoURL == "" {
@@ -259,7 +440,10 @@ func (rm *RegistryManager) Remove(repoURL string) error {
 	return nil
 }
 
-// Refresh runs git pull on a registry clone to update it.
+// Refresh runs git pull on a registry clone to update it. An HTTP registry's
+// manifest is downloaded again unless the server reports it unchanged.
+// Local registries are never pulled; their manifest is re-read and its
+// warnings re-cached.
 func (rm *RegistryManager) Refresh(repoURL string) (*RegistryManifest, error) {
 	repoURL = strings.TrimSpace(repoURL)
 	if repoURL == "" {
@@ -268,19 +452,35 @@ func (rm *RegistryManager) Refresh(repoURL string) (*RegistryManifest, error) {
 
 	dirKey := RegistryDirKey(repoURL)
 	dir := filepath.Join(rm.registriesDir, dirKey)
-	if !dirExists(dir) {
-		return nil, fmt.Errorf("registry clone for %q not found", repoURL)
+	srcDir, err := rm.sourceDir(repoURL)
+	if err != nil {
+		return nil, err
 	}
 
-	if err := gitPull(dir, registryPullTimeout); err != nil {
-		return nil, fmt.Errorf("refreshing registry %q: %w", repoURL, err)
+	switch {
+	case srcDir != dir:
+		// Local registries are read in place.
+	case isHTTPRegistry(dir):
+		if err := refreshHTTPRegistry(dir); err != nil {
+			return nil, fmt.Errorf("refreshing registry %q: %w", repoURL, err)
+		}
+	default:
+		if err := gitPull(dir, CurrentTimeouts().Pull); err != nil {
+			return nil, fmt.Errorf("refreshing registry %q: %w", repoURL, err)
+		}
 	}
 
-	manifest, err := readManifest(dir)
+	manifest, err := readManifest(srcDir)
 	if err != nil {
 		return nil, fmt.Errorf("reading manifest after refresh: %w", err)
 	}
 
+	pm, parseErr := ParseManifest(manifest)
+	if parseErr == nil {
+		manifest.Warnings = pm.Warnings
+		_ = writeCachedWarnings(dir, pm.Warnings)
+	}
+
 	return manifest, nil
 }
 
@@ -302,16 +502,63 @@ func (rm *RegistryManager) RefreshAll(registries []Registry) (map[string]*Regist
 }
 
 // LoadManifest reads and parses the manifest for a registry identified by repo URL.
+// A local registry's manifest is read straight from its directory.
 func (rm *RegistryManager) LoadManifest(repoURL string) (*RegistryManifest, error) {
-	dirKey := RegistryDirKey(repoURL)
-	dir := filepath.Join(rm.registriesDir, dirKey)
-	if !dirExists(dir) {
-		return nil, fmt.Errorf("registry clone for %q not found", repoURL)
+	dir, err := rm.sourceDir(repoURL)
+	if err != nil {
+		return nil, err
 	}
 
 	return readManifest(dir)
 }
 
+// CheckClone reports what, if anything, is wrong with a registry's copy on
+// disk: a missing clone or local directory, a git clone without a commit
+// checked out, or a manifest that can't be read.
+func (rm *RegistryManager) CheckClone(repoURL string) error {
+	dir, err := rm.sourceDir(repoURL)
+	if err != nil {
+		return err
+	}
+	clone := filepath.Join(rm.registriesDir, RegistryDirKey(repoURL))
+	if dir == clone && !isHTTPRegistry(clone) {
+		out, err := exec.Command("git", "-C", clone, "rev-parse", "--verify", "HEAD").CombinedOutput()
+		if err != nil {
+			return fmt.Errorf("broken git clone: %s", strings.TrimSpace(string(out)))
+		}
+	}
+	if _, err := readManifest(dir); err != nil {
+		return fmt.Errorf("reading manifest: %w", err)
+	}
+	return nil
+}
+
+// OrphanedDirs returns the directories in the registries directory that no
+// configured registry uses, e.g. left behind by a registry removed by
+// editing the config file.
+func (rm *RegistryManager) OrphanedDirs(registries []Registry) ([]string, error) {
+	entries, err := os.ReadDir(rm.registriesDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading registries directory: %w", err)
+	}
+
+	used := make(map[string]bool, len(registries))
+	for _, reg := range registries {
+		used[RegistryDirKey(reg.Repo)] = true
+	}
+
+	var orphaned []string
+	for _, e := range entries {
+		if e.IsDir() && !used[e.Name()] {
+			orphaned = append(orphaned, filepath.Join(rm.registriesDir, e.Name()))
+		}
+	}
+	return orphaned, nil
+}
+
 // LoadAllManifests loads manifests for all given registries.
 // Registries that fail to load are silently skipped.
 // The returned map is keyed by repo URL.
@@ -408,6 +655,109 @@ func (rm *RegistryManager) FindAsset(registries []Registry, kind asset.Kind, nam
 	}
 }
 
+// IsAssetPattern reports whether name is a glob pattern, such as go-*,
+// rather than a single asset name.
+func IsAssetPattern(name string) bool {
+	return strings.ContainsAny(name, "*?[")
+}
+
+// MatchAssets returns the registry entries of a kind whose names match the
+// glob pattern (path.Match syntax), sorted by name. If registryFilter is
+// non-empty, only that registry (matched by name, alias, or repo URL) is
+// searched. A matching name found in several registries is an error, as
+// with FindSkill, and so is a pattern that matches nothing.
+func (rm *RegistryManager) MatchAssets(registries []Registry, kind asset.Kind, pattern, registryFilter string) ([]RegistryAssetInfo, error) {
+	if _, err := path.Match(pattern, ""); err != nil {
+		return nil, fmt.Errorf("invalid pattern %q: %w", pattern, err)
+	}
+	matches, err := rm.selectAssets(registries, []asset.Kind{kind}, registryFilter, func(e asset.RegistryEntry) bool {
+		ok, _ := path.Match(pattern, e.Name)
+		return ok
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no %ss in registries match %q", kind, pattern)
+	}
+	return matches, nil
+}
+
+// TaggedAssets returns the registry entries of the given kinds that carry
+// tag, sorted by kind and name. registryFilter and ambiguous names are
+// handled as in MatchAssets, and finding no entries is an error.
+func (rm *RegistryManager) TaggedAssets(registries []Registry, kinds []asset.Kind, tag, registryFilter string) ([]RegistryAssetInfo, error) {
+	matches, err := rm.selectAssets(registries, kinds, registryFilter, func(e asset.RegistryEntry) bool {
+		return slices.Contains(e.Tags, tag)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(matches) == 0 {
+		return nil, fmt.Errorf("no registry entries are tagged %q", tag)
+	}
+	return matches, nil
+}
+
+// selectAssets returns the entries of the given kinds for which keep
+// returns true, sorted by kind and name, searching only the registry
+// matching registryFilter if it is non-empty. The same kind and name
+// selected in two registries is an error.
+func (rm *RegistryManager) selectAssets(registries []Registry, kinds []asset.Kind, registryFilter string, keep func(asset.RegistryEntry) bool) ([]RegistryAssetInfo, error) {
+	searchRegistries, err := filterRegistries(registries, registryFilter)
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []RegistryAssetInfo
+	for _, kind := range kinds {
+		var ofKind []RegistryAssetInfo
+		for _, info := range rm.ListAssets(searchRegistries, kind) {
+			if keep(info.Entry) {
+				ofKind = append(ofKind, info)
+			}
+		}
+		sort.SliceStable(ofKind, func(i, j int) bool {
+			return ofKind[i].Entry.Name < ofKind[j].Entry.Name
+		})
+		matches = append(matches, ofKind...)
+	}
+
+	for i := 1; i < len(matches); i++ {
+		a, b := matches[i-1], matches[i]
+		if a.Kind != b.Kind || a.Entry.Name != b.Entry.Name {
+			continue
+		}
+		var registryNames []string
+		for _, m := range matches {
+			if m.Kind == b.Kind && m.Entry.Name == b.Entry.Name {
+				registryNames = append(registryNames, fmt.Sprintf("%s (%s)", m.RegistryName, m.RegistryRepo))
+			}
+		}
+		return nil, fmt.Errorf("%s %q found in multiple registries; use --registry to disambiguate:\n  %s",
+			b.Kind, b.Entry.Name, strings.Join(registryNames, "\n  "))
+	}
+	return matches, nil
+}
+
+// filterRegistries returns the registries matching registryFilter by name,
+// alias, or repo URL, or all of them if it is empty.
+func filterRegistries(registries []Registry, registryFilter string) ([]Registry, error) {
+	if registryFilter == "" {
+		return registries, nil
+	}
+	var filtered []Registry
+	for _, r := range registries {
+		if r.Matches(registryFilter) {
+			filtered = append(filtered, r)
+		}
+	}
+	if len(filtered) == 0 {
+		return nil, fmt.Errorf("registry %q not found", registryFilter)
+	}
+	return filtered, nil
+}
+
 // --- Unified registry asset info ---
 
 // RegistryAssetInfo associates a registry entry with its registry and asset kind.
@@ -528,8 +878,31 @@ func (rm *RegistryManager) ListMCPs(registries []Registry) []RegistryMCPInfo {
 	return mcps
 }
 
+// ValidateRegistryAlias checks that alias can be given to the registry with
+// the given repo: it must be a valid asset-style name, and must not be the
+// name, alias, or repo of any other configured registry, so that it always
+// refers to exactly one.
+func ValidateRegistryAlias(registries []Registry, repo, alias string) error {
+	var nameErr *asset.NameError
+	if err := asset.ValidateName(alias); errors.As(err, &nameErr) {
+		return fmt.Errorf("invalid registry alias %q: %s", alias, nameErr.Reason)
+	}
+	for _, r := range registries {
+		if r.Repo == repo {
+			continue
+		}
+		switch alias {
+		case r.Alias:
+			return fmt.Errorf("alias %q is already used by registry %s (%s)", alias, r.Name, r.Repo)
+		case r.Name, r.Repo:
+			return fmt.Errorf("alias %q is the name of registry %s", alias, r.Repo)
+		}
+	}
+	return nil
+}
+
 // FindSkill searches all registries for a skill by name.
-// If registryFilter is non-empty, only that registry (matched by name or repo URL) is searched.
+// If registryFilter is non-empty, only that registry (matched by name, alias, or repo URL) is searched.
 // Returns an error if the skill is not found or if the name is ambiguous across registries.
 func (rm *RegistryManager) FindSkill(registries []Registry, skillName, registryFilter string) (*RegistrySkillInfo, error) {
 	if skillName == "" {
@@ -540,7 +913,7 @@ func (rm *RegistryManager) FindSkill(registries []Registry, skillName, registryF
 	if registryFilter != "" {
 		var filtered []Registry
 		for _, r := range registries {
-			if r.Name == registryFilter || r.Repo == registryFilter {
+			if r.Matches(registryFilter) {
 				filtered = append(filtered, r)
 			}
 		}
@@ -596,7 +969,7 @@ func (rm *RegistryManager) FindSkill(registries []Registry, skillName, registryF
 }
 
 // FindMCP searches all registries for an MCP by name.
-// If registryFilter is non-empty, only that registry (matched by name or repo URL) is searched.
+// If registryFilter is non-empty, only that registry (matched by name, alias, or repo URL) is searched.
 // Returns an error if the MCP is not found or if the name is ambiguous across registries.
 func (rm *RegistryManager) FindMCP(registries []Registry, mcpName, registryFilter string) (*RegistryMCPInfo, error) {
 	if mcpName == "" {
@@ -607,7 +980,7 @@ func (rm *RegistryManager) FindMCP(registries []Registry, mcpName, registryFilte
 	if registryFilter != "" {
 		var filtered []Registry
 		for _, r := range registries {
-			if r.Name == registryFilter || r.Repo == registryFilter {
+			if r.Matches(registryFilter) {
 				filtered = append(filtered, r)
 			}
 		}
@@ -711,26 +1084,86 @@ func BuildRegistryCommitMap(registries []Registry, rm *RegistryManager) map[stri
 	return commits
 }
 
+// --- Registry mirrors ---
+
+// RegistryMirrors are the cloneUrl mirrors registry entries declare, keyed
+// by the entry's kind, name, and source repository. A mirror only applies to
+// its own entry: the same repository installed directly, or under another
+// entry, is fetched from its source, and the lock file records the source
+// either way.
+type RegistryMirrors map[registryMirrorKey]string
+
+type registryMirrorKey struct {
+	kind asset.Kind
+	name string
+	repo string // host/owner/repo, lowercased
+}
+
+// Mirrors returns the mirrors the entries of the configured registries
+// declare. When several registries list the same entry, the first one's
+// mirror is used.
+func (rm *RegistryManager) Mirrors(registries []Registry) RegistryMirrors {
+	mirrors := make(RegistryMirrors)
+	for _, reg := range registries {
+		manifest, err := rm.LoadManifest(reg.Repo)
+		if err != nil {
+			continue
+		}
+		parsed, err := ParseManifest(manifest)
+		if err != nil {
+			continue
+		}
+		for _, kind := range sourceBasedKinds() {
+			for _, e := range parsed.Entries[kind] {
+				if e.CloneURL == "" || e.Source == "" {
+					continue
+				}
+				key := registryMirrorKey{kind: kind, name: e.Name, repo: strings.ToLower(repoKey(e.Source))}
+				if _, ok := mirrors[key]; !ok {
+					mirrors[key] = e.CloneURL
+				}
+			}
+		}
+	}
+	return mirrors
+}
+
+// Locked returns the mirror to fetch a locked asset from: the cloneUrl of
+// the registry entry it was installed from, or "" if no entry of the same
+// kind and name has a mirror for its source repository, host included.
+func (m RegistryMirrors) Locked(locked asset.LockedAsset) string {
+	return m[registryMirrorKey{
+		kind: locked.Kind,
+		name: LockedUpstreamName(locked),
+		repo: strings.ToLower(repoKey(locked.Source)),
+	}]
+}
+
 const cachedCommitsFile = "duckrow.commits.json"
 
 // loadCachedCommits reads the cached commits file from a registry directory.
 // Returns an empty map if the file doesn't exist or can't be parsed.
 func loadCachedCommits(registryDir string) map[string]string {
-	path := filepath.Join(registryDir, cachedCommitsFile)
-	data, err := os.ReadFile(path)
-	if err != nil {
+	cached := loadCachedCommitsFile(registryDir)
+	if cached == nil || cached.Commits == nil {
 		return make(map[string]string)
 	}
+	return cached.Commits
+}
 
-	var cached CachedCommits
-	if err := json.Unmarshal(data, &cached); err != nil {
-		return make(map[string]string)
+// loadCachedCommitsFile reads the full cached commits file, including its
+// generation time. Returns nil if the file doesn't exist or can't be parsed.
+func loadCachedCommitsFile(registryDir string) *CachedCommits {
+	data, err := os.ReadFile(filepath.Join(registryDir, cachedCommitsFile))
+	if err != nil {
+		return nil
 	}
 
-	if cached.Commits == nil {
-		return make(map[string]string)
+	var cached CachedCommits
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil
 	}
-	return cached.Commits
+	return &cached
 }
 
 // writeCachedCommits writes resolved commits to the cache file in a registry directory.
@@ -752,6 +1185,130 @@ func writeCachedCommits(registryDir string, commits map[string]string) error {
 	return nil
 }
 
+const cachedWarningsFile = "duckrow.warnings.json"
+
+// loadCachedWarnings reads the cached warnings file from a registry directory.
+// Returns nil if the file doesn't exist or can't be parsed.
+func loadCachedWarnings(registryDir string) *CachedWarnings {
+	data, err := os.ReadFile(filepath.Join(registryDir, cachedWarningsFile))
+	if err != nil {
+		return nil
+	}
+
+	var cached CachedWarnings
+	if err := json.Unmarshal(data, &cached); err != nil {
+		return nil
+	}
+	return &cached
+}
+
+// writeCachedWarnings writes manifest warnings to the cache file in a registry directory.
+func writeCachedWarnings(registryDir string, warnings []string) error {
+	if warnings == nil {
+		warnings = []string{}
+	}
+	cached := CachedWarnings{
+		GeneratedAt: time.Now().UTC(),
+		Warnings:    warnings,
+	}
+
+	data, err := json.MarshalIndent(cached, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling cached warnings: %w", err)
+	}
+
+	path := filepath.Join(registryDir, cachedWarningsFile)
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("writing %s: %w", cachedWarningsFile, err)
+	}
+	return nil
+}
+
+// Warnings returns the manifest validation warnings for a registry.
+// Persisted warnings from the last add/refresh are used when available;
+// otherwise the manifest is parsed and the result is cached. A local
+// registry's manifest can change at any time, so it is always parsed.
+func (rm *RegistryManager) Warnings(repoURL string) ([]string, error) {
+	dir := filepath.Join(rm.registriesDir, RegistryDirKey(repoURL))
+	srcDir, err := rm.sourceDir(repoURL)
+	if err != nil {
+		return nil, err
+	}
+
+	if cached := loadCachedWarnings(dir); cached != nil && srcDir == dir {
+		return cached.Warnings, nil
+	}
+
+	manifest, err := readManifest(srcDir)
+	if err != nil {
+		return nil, err
+	}
+	pm, err := ParseManifest(manifest)
+	if err != nil {
+		return nil, err
+	}
+	_ = writeCachedWarnings(dir, pm.Warnings)
+	return pm.Warnings, nil
+}
+
+// WarningCounts returns the number of manifest warnings per registry,
+// keyed by repo URL. Registries whose warnings can't be loaded are omitted.
+func (rm *RegistryManager) WarningCounts(registries []Registry) map[string]int {
+	counts := make(map[string]int)
+	for _, reg := range registries {
+		warnings, err := rm.Warnings(reg.Repo)
+		if err != nil {
+			continue
+		}
+		counts[reg.Repo] = len(warnings)
+	}
+	return counts
+}
+
+// DefaultCommitCacheTTL is how long hydrated registry commits are reused when
+// the commitCacheTTLMinutes setting is unset.
+const DefaultCommitCacheTTL = time.Hour
+
+// CommitCacheTTL returns the configured commit cache TTL. Unset falls back to
+// the default; a negative value returns zero, meaning the cache is always
+// stale.
+func (s Settings) CommitCacheTTL() time.Duration {
+	switch {
+	case s.CommitCacheTTLMinutes == 0:
+		return DefaultCommitCacheTTL
+	case s.CommitCacheTTLMinutes < 0:
+		return 0
+	}
+	return time.Duration(s.CommitCacheTTLMinutes) * time.Minute
+}
+
+// HydrateOptions configures commit hydration.
+type HydrateOptions struct {
+	// Overrides maps "owner/repo" keys to clone URL overrides for private
+	// repositories.
+	Overrides map[string]string
+	// TTL is how long a registry's cached commits are reused. A registry is
+	// hydrated again when its cache is older than TTL or is missing any of
+	// the manifest's unpinned sources. Zero means the cache is always stale.
+	TTL time.Duration
+	// Force hydrates every registry regardless of cache age.
+	Force bool
+	// Disabled turns hydration off for all registries.
+	Disabled bool
+}
+
+// HydrateResult reports what hydration did for one registry.
+type HydrateResult struct {
+	Registry Registry
+	Fresh    bool     // cache was within TTL; nothing was resolved
+	Disabled bool     // hydration is turned off for this registry
+	Offline  bool     // offline mode is on; the cached commits were kept
+	Unpinned int      // unpinned source-based assets in the manifest
+	Skipped  int      // unpinned assets that opt out with "hydrate": false
+	Resolved int      // commits resolved and written to the cache
+	Failed   []string // repos that could not be cloned
+}
+
 // HydrateRegistryCommits resolves the latest commit SHA for each unpinned
 // source-based asset in the configured registries. Unpinned assets are those
 // with a Source but no Commit field in the registry manifest.
@@ -763,98 +1320,223 @@ func writeCachedCommits(registryDir string, commits map[string]string) error {
 //
 // Clone errors are logged and skipped — hydration is best-effort.
 // The overrides parameter maps "owner/repo" keys to clone URL overrides
-// for private repositories.
+// for private repositories. Every registry is hydrated regardless of cache
+// age; use Hydrate to honor a TTL.
 func (rm *RegistryManager) HydrateRegistryCommits(registries []Registry, overrides map[string]string) {
+	rm.Hydrate(registries, HydrateOptions{Overrides: overrides, Force: true})
+}
+
+// Hydrate is HydrateRegistryCommits with cache TTL control. Registries whose
+// cached commits are still fresh are skipped unless opts.Force is set.
+func (rm *RegistryManager) Hydrate(registries []Registry, opts HydrateOptions) []HydrateResult {
+	var results []HydrateResult
 	for _, reg := range registries {
-		regDir := filepath.Join(rm.registriesDir, RegistryDirKey(reg.Repo))
-		manifest, err := rm.LoadManifest(reg.Repo)
-		if err != nil {
-			continue
+		res, ok := rm.hydrateRegistry(reg, opts)
+		if ok {
+			results = append(results, res)
 		}
+	}
+	return results
+}
 
-		parsed, err := ParseManifest(manifest)
-		if err != nil {
-			continue
-		}
+// hydrateRegistry hydrates a single registry. It returns false if the
+// registry's manifest could not be loaded. Hydration can be turned off
+// globally (opts.Disabled), per registry (in the config or the manifest), or
+// per entry; those assets are left without an available commit, and update
+// checks fall back to resolving the locked sources directly.
+func (rm *RegistryManager) hydrateRegistry(reg Registry, opts HydrateOptions) (HydrateResult, bool) {
+	res := HydrateResult{Registry: reg}
+	regDir := filepath.Join(rm.registriesDir, RegistryDirKey(reg.Repo))
+	manifest, err := rm.LoadManifest(reg.Repo)
+	if err != nil {
+		return res, false
+	}
 
-		// Collect unpinned assets (have Source but no Commit).
-		type unpinnedAsset struct {
-			source  string
-			subPath string
-		}
-		type repoRefKey struct {
-			repo string
-			ref  string // always "" for registry assets (they don't have a ref field)
-		}
+	parsed, err := ParseManifest(manifest)
+	if err != nil {
+		return res, false
+	}
 
-		repoGroups := make(map[repoRefKey][]unpinnedAsset)
-		var repoGroupOrder []repoRefKey
+	// Collect unpinned assets (have Source but no Commit).
+	type unpinnedAsset struct {
+		source  string
+		subPath string
+	}
+	type repoRefKey struct {
+		repo   string
+		ref    string // always "" for registry assets (they don't have a ref field)
+		mirror string // the entries' cloneUrl, if any
+	}
 
-		for _, kind := range sourceBasedKinds() {
-			for _, entry := range parsed.Entries[kind] {
-				if entry.Source == "" || entry.Commit != "" {
-					continue // skip: no source or already pinned
-				}
+	repoGroups := make(map[repoRefKey][]unpinnedAsset)
+	var repoGroupOrder []repoRefKey
 
-				rk := repoKey(entry.Source)
-				sp := skillSubPath(entry.Source)
-				key := repoRefKey{repo: rk}
+	res.Disabled = opts.Disabled || parsed.NoHydrate || (reg.Hydrate != nil && !*reg.Hydrate)
 
-				if _, exists := repoGroups[key]; !exists {
-					repoGroupOrder = append(repoGroupOrder, key)
-				}
-				repoGroups[key] = append(repoGroups[key], unpinnedAsset{
-					source:  entry.Source,
-					subPath: sp,
-				})
+	for _, kind := range sourceBasedKinds() {
+		for _, entry := range parsed.Entries[kind] {
+			if entry.Source == "" || entry.Commit != "" {
+				continue // skip: no source or already pinned
+			}
+			res.Unpinned++
+			if res.Disabled {
+				continue
+			}
+			if entry.NoHydrate {
+				res.Skipped++
+				continue
 			}
+
+			rk := repoKey(entry.Source)
+			sp := skillSubPath(entry.Source)
+			key := repoRefKey{repo: rk, mirror: entry.CloneURL}
+
+			if _, exists := repoGroups[key]; !exists {
+				repoGroupOrder = append(repoGroupOrder, key)
+			}
+			repoGroups[key] = append(repoGroups[key], unpinnedAsset{
+				source:  entry.Source,
+				subPath: sp,
+			})
 		}
+	}
+
+	if len(repoGroups) == 0 {
+		// All assets are pinned or opted out. Drop any cache left from
+		// earlier hydration so its commits aren't reported as available.
+		_ = os.Remove(filepath.Join(regDir, cachedCommitsFile))
+		return res, true
+	}
 
-		if len(repoGroups) == 0 {
-			continue // all assets are pinned
+	// Reuse a fresh cache that covers every unpinned source.
+	if !opts.Force && opts.TTL > 0 {
+		if cached := loadCachedCommitsFile(regDir); cached != nil && time.Since(cached.GeneratedAt) < opts.TTL {
+			covered := true
+			for _, entries := range repoGroups {
+				for _, e := range entries {
+					if _, ok := cached.Commits[e.source]; !ok {
+						covered = false
+					}
+				}
+			}
+			if covered {
+				res.Fresh = true
+				return res, true
+			}
 		}
+	}
 
-		// Resolve commits for each repo group.
-		resolved := make(map[string]string)
+	if Offline() {
+		res.Offline = true
+		return res, true
+	}
+
+	// Resolve commits for each repo group.
+	resolved := make(map[string]string)
+
+	for _, key := range repoGroupOrder {
+		entries := repoGroups[key]
+
+		// Parse source to build clone URL.
+		host, owner, repo, _, parseErr := ParseLockSource(entries[0].source)
+		if parseErr != nil {
+			continue
+		}
 
-		for _, key := range repoGroupOrder {
-			entries := repoGroups[key]
+		// Apply clone URL override, or the entries' mirror.
+		cloneURL := lockCloneURL(host, owner, repo, opts.Overrides, key.mirror)
 
-			// Parse source to build clone URL.
-			host, owner, repo, _, parseErr := ParseLockSource(entries[0].source)
-			if parseErr != nil {
+		// The GitHub API answers per-path queries without a clone; entries
+		// it can't resolve fall through to the clone below.
+		if githubAPIRepo(cloneURL, host, owner, repo) {
+			for len(entries) > 0 {
+				commit, err := githubPathCommit(owner, repo, key.ref, entries[0].subPath)
+				if err != nil {
+					break
+				}
+				resolved[entries[0].source] = commit
+				entries = entries[1:]
+			}
+			if len(entries) == 0 {
 				continue
 			}
+		}
 
-			cloneURL := fmt.Sprintf("https://%s/%s/%s.git", host, owner, repo)
+		tmpDir, cloneErr := cloneRepo(cloneURL, key.ref, false)
+		if cloneErr != nil {
+			res.Failed = append(res.Failed, key.repo)
+			continue // best-effort: skip repos that fail to clone
+		}
 
-			// Apply clone URL override.
-			repoKeyStr := strings.ToLower(owner) + "/" + strings.ToLower(repo)
-			if override, ok := overrides[repoKeyStr]; ok && override != "" {
-				cloneURL = override
+		for _, e := range entries {
+			commit, commitErr := GetSkillCommit(tmpDir, e.subPath)
+			if commitErr != nil {
+				continue
 			}
+			resolved[e.source] = commit
+		}
 
-			tmpDir, cloneErr := cloneRepo(cloneURL, key.ref, false)
-			if cloneErr != nil {
-				continue // best-effort: skip repos that fail to clone
-			}
+		_ = os.RemoveAll(tmpDir)
+	}
 
-			for _, e := range entries {
-				commit, commitErr := GetSkillCommit(tmpDir, e.subPath)
-				if commitErr != nil {
-					continue
+	// Write resolved commits to cache file.
+	if len(resolved) > 0 {
+		_ = writeCachedCommits(regDir, resolved)
+	}
+	res.Resolved = len(resolved)
+	return res, true
+}
+
+// CommitCacheTime returns when a registry's hydrated commits were last
+// written, or false if it has no commit cache.
+func (rm *RegistryManager) CommitCacheTime(repoURL string) (time.Time, bool) {
+	cached := loadCachedCommitsFile(filepath.Join(rm.registriesDir, RegistryDirKey(repoURL)))
+	if cached == nil || cached.GeneratedAt.IsZero() {
+		return time.Time{}, false
+	}
+	return cached.GeneratedAt, true
+}
+
+// LockedFromRegistry returns the lock entries whose provenance points at reg.
+// MCPs record their registry (by name or repo) in the lock. Skills and agents
+// match when a manifest entry of the same kind and name lives in the same
+// owner/repo as the locked source, regardless of host.
+func LockedFromRegistry(lf *LockFile, reg Registry, manifest *RegistryManifest) []asset.LockedAsset {
+	if lf == nil {
+		return nil
+	}
+
+	// owner/repo of each manifest entry, keyed by kind and name.
+	entryRepos := make(map[string]string)
+	if manifest != nil {
+		if parsed, err := ParseManifest(manifest); err == nil {
+			for _, kind := range sourceBasedKinds() {
+				for _, e := range parsed.Entries[kind] {
+					src, err := ParseSource(e.Source)
+					if err != nil {
+						continue
+					}
+					entryRepos[lockOriginKey(kind, e.Name)] = src.Owner + "/" + src.Repo
 				}
-				resolved[e.source] = commit
 			}
-
-			_ = os.RemoveAll(tmpDir)
 		}
+	}
 
-		// Write resolved commits to cache file.
-		if len(resolved) > 0 {
-			_ = writeCachedCommits(regDir, resolved)
+	var result []asset.LockedAsset
+	for _, a := range lf.Assets {
+		switch a.Kind {
+		case asset.KindMCP:
+			if r, _ := a.Data["registry"].(string); r != "" && (r == reg.Name || r == reg.Repo) {
+				result = append(result, a)
+			}
+		default:
+			repo, ok := entryRepos[lockOriginKey(a.Kind, LockedUpstreamName(a))]
+			if ok && a.Source != "" && SourcePathKey(repoKey(a.Source)) == repo {
+				result = append(result, a)
+			}
 		}
 	}
+	return result
 }
 
 // --- Internal helpers ---
@@ -862,24 +1544,34 @@ func (rm *RegistryManager) HydrateRegistryCommits(registries []Registry, overrid
 // sourceBasedKinds returns asset kinds that use source+commit tracking
 // (as opposed to config-only kinds like MCP).
 func sourceBasedKinds() []asset.Kind {
-	return []asset.Kind{asset.KindSkill, asset.KindAgent}
+	return []asset.Kind{asset.KindSkill, asset.KindAgent, asset.KindCommand, asset.KindRule}
 }
 
-// readManifest reads and parses the duckrow.json manifest from a directory.
+// readManifest reads and parses the registry manifest from a directory:
+// duckrow.json (JSONC), or duckrow.yaml if there is no duckrow.json.
 // Supports both v1 and v2 formats transparently.
 func readManifest(dir string) (*RegistryManifest, error) {
-	path := filepath.Join(dir, registryManifestFile)
+	path, err := findManifest(dir)
+	if err != nil {
+		return nil, err
+	}
+	name := filepath.Base(path)
 	data, err := os.ReadFile(path)
 	if err != nil {
-		if os.IsNotExist(err) {
-			return nil, fmt.Errorf("%s not found in repository", registryManifestFile)
-		}
-		return nil, fmt.Errorf("reading %s: %w", registryManifestFile, err)
+		return nil, fmt.Errorf("reading %s: %w", name, err)
 	}
+	return parseManifestFile(name, data)
+}
 
+// parseManifestFile parses the contents of the manifest file name.
+func parseManifestFile(name string, data []byte) (*RegistryManifest, error) {
+	std, err := decodeManifest(name, data)
+	if err != nil {
+		return nil, err
+	}
 	var manifest RegistryManifest
-	if err := json.Unmarshal(data, &manifest); err != nil {
-		return nil, fmt.Errorf("parsing %s: %w", registryManifestFile, err)
+	if err := json.Unmarshal(std, &manifest); err != nil {
+		return nil, fmt.Errorf("parsing %s: %w", name, err)
 	}
 
 	// Auto-detect v1 format: has Skills/MCPs arrays but no Assets map.
@@ -892,14 +1584,19 @@ func readManifest(dir string) (*RegistryManifest, error) {
 // gitClone clones a repository to the given directory.
 // On failure it returns a *CloneError with classified diagnostics.
 func gitClone(url, ref, destDir string, timeout time.Duration) error {
-	args := []string{"clone", "--depth", "1"}
+	if err := checkNetwork(url); err != nil {
+		return err
+	}
+	throttle(url)
+
+	args := append(longPathGitArgs(), "clone", "--depth", "1")
 	if ref != "" {
 		args = append(args, "--branch", ref)
 	}
 	args = append(args, url, destDir)
 
 	cmd := exec.Command("git", args...)
-	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Env = gitEnv(url)
 
 	output, err := runWithTimeout(cmd, timeout)
 	if err != nil {
@@ -912,15 +1609,19 @@ func gitClone(url, ref, destDir string, timeout time.Duration) error {
 // gitPull runs git pull in the given directory.
 // On failure it returns a *CloneError with classified diagnostics.
 func gitPull(dir string, timeout time.Duration) error {
-	cmd := exec.Command("git", "pull", "--ff-only")
+	remote := gitRemoteURL(dir)
+	if err := checkNetwork(remote); err != nil {
+		return err
+	}
+	throttle(remote)
+
+	cmd := exec.Command("git", append(longPathGitArgs(), "pull", "--ff-only")...)
 	cmd.Dir = dir
-	cmd.Env = append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	cmd.Env = gitEnv(remote)
 
 	output, err := runWithTimeout(cmd, timeout)
 	if err != nil {
-		// Determine the remote URL for error classification.
-		remoteURL := gitRemoteURL(dir)
-		return ClassifyCloneError(remoteURL, "git pull --ff-only", output)
+		return ClassifyCloneError(remote, "git pull --ff-only", output)
 	}
 
 	return nil
@@ -5,19 +5,23 @@ import (
 	"os"
 	"os/exec"
 	"path/filepath"
+	"reflect"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/barysiuk/duckrow/internal/core/asset"
 )
 
 // testSkillEntry is a test helper that mirrors the old SkillEntry for constructing test manifests.
 type testSkillEntry struct {
-	Name        string `json:"name"`
-	Description string `json:"description,omitempty"`
-	Source      string `json:"source,omitempty"`
-	Commit      string `json:"commit,omitempty"`
-	Internal    bool   `json:"internal,omitempty"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Source      string   `json:"source,omitempty"`
+	CloneURL    string   `json:"cloneUrl,omitempty"`
+	Commit      string   `json:"commit,omitempty"`
+	Internal    bool     `json:"internal,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
 }
 
 func skillEntriesToRaw(entries []testSkillEntry) []json.RawMessage {
@@ -38,6 +42,7 @@ type testMCPEntry struct {
 	Env         []string `json:"env,omitempty"`
 	URL         string   `json:"url,omitempty"`
 	Type        string   `json:"type,omitempty"`
+	Tags        []string `json:"tags,omitempty"`
 }
 
 func mcpEntriesToRaw(entries []testMCPEntry) []json.RawMessage {
@@ -125,6 +130,23 @@ func TestRegistryDirKey(t *testing.T) {
 			t.Errorf("key %q seems too short", key)
 		}
 	})
+
+	t.Run("keeps existing keys for plain URLs", func(t *testing.T) {
+		key := RegistryDirKey("https://github.com/myorg/skills.git")
+		if !strings.HasPrefix(key, "myorg-skills-") {
+			t.Errorf("key %q, want myorg-skills- prefix", key)
+		}
+	})
+
+	t.Run("replaces unsafe characters", func(t *testing.T) {
+		key := RegistryDirKey("https://example.com/a/b:c?d=<e>")
+		if !strings.HasPrefix(key, "a-b-c-d--e--") {
+			t.Errorf("key %q, want a-b-c-d--e-- prefix", key)
+		}
+		if long := RegistryDirKey("https://example.com/" + strings.Repeat("x", 1000)); len(long) > maxDirKeyReadable+9 {
+			t.Errorf("key for long URL is %d bytes", len(long))
+		}
+	})
 }
 
 func TestReadManifest(t *testing.T) {
@@ -246,6 +268,72 @@ func TestRegistryManager_LoadManifest(t *testing.T) {
 	})
 }
 
+func TestRegistryManager_Warnings(t *testing.T) {
+	t.Run("computes and persists warnings on first read", func(t *testing.T) {
+		registriesDir := t.TempDir()
+		rm := NewRegistryManager(registriesDir)
+
+		repoURL := "git@example.com:my-org/skills.git"
+		regDir := createTestRegistryClone(t, registriesDir, repoURL, RegistryManifest{
+			Name: "my-org",
+			Skills: skillEntriesToRaw([]testSkillEntry{
+				{Name: "lint-rules", Source: "org/lint"},
+				{Name: "go-review", Source: "github.com/org/skills/go-review"},
+			}),
+		})
+
+		warnings, err := rm.Warnings(repoURL)
+		if err != nil {
+			t.Fatalf("Warnings() error = %v", err)
+		}
+		if len(warnings) != 1 || !strings.Contains(warnings[0], "lint-rules") {
+			t.Errorf("Warnings() = %v, want one warning about lint-rules", warnings)
+		}
+
+		cached := loadCachedWarnings(regDir)
+		if cached == nil {
+			t.Fatal("expected warnings to be cached")
+		}
+		if len(cached.Warnings) != 1 {
+			t.Errorf("cached warnings = %v, want 1", cached.Warnings)
+		}
+	})
+
+	t.Run("prefers cached warnings", func(t *testing.T) {
+		registriesDir := t.TempDir()
+		rm := NewRegistryManager(registriesDir)
+
+		repoURL := "git@example.com:my-org/skills.git"
+		regDir := createTestRegistryClone(t, registriesDir, repoURL, RegistryManifest{Name: "my-org"})
+		if err := writeCachedWarnings(regDir, []string{"stale warning"}); err != nil {
+			t.Fatal(err)
+		}
+
+		warnings, err := rm.Warnings(repoURL)
+		if err != nil {
+			t.Fatalf("Warnings() error = %v", err)
+		}
+		if len(warnings) != 1 || warnings[0] != "stale warning" {
+			t.Errorf("Warnings() = %v, want [stale warning]", warnings)
+		}
+
+		counts := rm.WarningCounts([]Registry{{Name: "my-org", Repo: repoURL}, {Name: "missing", Repo: "git@example.com:x/y.git"}})
+		if counts[repoURL] != 1 {
+			t.Errorf("WarningCounts[%q] = %d, want 1", repoURL, counts[repoURL])
+		}
+		if _, ok := counts["git@example.com:x/y.git"]; ok {
+			t.Error("WarningCounts should omit registries that can't be loaded")
+		}
+	})
+
+	t.Run("error when registry not found", func(t *testing.T) {
+		rm := NewRegistryManager(t.TempDir())
+		if _, err := rm.Warnings("git@example.com:nonexistent.git"); err == nil {
+			t.Fatal("expected error for nonexistent registry")
+		}
+	})
+}
+
 func TestRegistryManager_LoadAllManifests(t *testing.T) {
 	registriesDir := t.TempDir()
 	rm := NewRegistryManager(registriesDir)
@@ -495,11 +583,174 @@ func TestRegistryManager_FindSkill(t *testing.T) {
 	})
 }
 
+func TestRegistryManager_MatchAssets(t *testing.T) {
+	registriesDir := t.TempDir()
+	rm := NewRegistryManager(registriesDir)
+
+	repoA := "git@example.com:org-a/skills.git"
+	repoB := "git@example.com:org-b/skills.git"
+	createTestRegistryClone(t, registriesDir, repoA, RegistryManifest{
+		Name: "org-a",
+		Skills: skillEntriesToRaw([]testSkillEntry{
+			{Name: "go-vet", Source: "org-a/go-vet"},
+			{Name: "go-review", Source: "org-a/go-review"},
+			{Name: "py-review", Source: "org-a/py-review"},
+		}),
+	})
+	createTestRegistryClone(t, registriesDir, repoB, RegistryManifest{
+		Name: "org-b",
+		Skills: skillEntriesToRaw([]testSkillEntry{
+			{Name: "go-review", Source: "org-b/go-review"},
+		}),
+	})
+	registries := []Registry{
+		{Name: "org-a", Repo: repoA},
+		{Name: "org-b", Repo: repoB},
+	}
+
+	t.Run("matches sorted by name", func(t *testing.T) {
+		matches, err := rm.MatchAssets(registries, asset.KindSkill, "go-*", "org-a")
+		if err != nil {
+			t.Fatalf("MatchAssets() error = %v", err)
+		}
+		var names []string
+		for _, m := range matches {
+			names = append(names, m.Entry.Name)
+		}
+		if want := []string{"go-review", "go-vet"}; !reflect.DeepEqual(names, want) {
+			t.Errorf("names = %v, want %v", names, want)
+		}
+	})
+
+	t.Run("ambiguous across registries", func(t *testing.T) {
+		_, err := rm.MatchAssets(registries, asset.KindSkill, "go-*", "")
+		if err == nil || !containsStr(err.Error(), `skill "go-review" found in multiple registries`) {
+			t.Errorf("error = %v, want ambiguity error", err)
+		}
+	})
+
+	t.Run("no matches", func(t *testing.T) {
+		_, err := rm.MatchAssets(registries, asset.KindSkill, "rs-*", "")
+		if err == nil || !containsStr(err.Error(), `no skills in registries match "rs-*"`) {
+			t.Errorf("error = %v, want no-match error", err)
+		}
+	})
+
+	t.Run("bad pattern", func(t *testing.T) {
+		if _, err := rm.MatchAssets(registries, asset.KindSkill, "go-[", ""); err == nil {
+			t.Error("expected error for malformed pattern")
+		}
+	})
+}
+
+func TestRegistryManager_TaggedAssets(t *testing.T) {
+	registriesDir := t.TempDir()
+	rm := NewRegistryManager(registriesDir)
+
+	repo := "git@example.com:org-a/skills.git"
+	createTestRegistryClone(t, registriesDir, repo, RegistryManifest{
+		Name: "org-a",
+		Skills: skillEntriesToRaw([]testSkillEntry{
+			{Name: "go-vet", Source: "org-a/go-vet", Tags: []string{"backend", "go"}},
+			{Name: "css-lint", Source: "org-a/css-lint", Tags: []string{"frontend"}},
+			{Name: "api-review", Source: "org-a/api-review", Tags: []string{"backend"}},
+		}),
+		MCPs: mcpEntriesToRaw([]testMCPEntry{
+			{Name: "db", Command: "dbtool", Tags: []string{"backend"}},
+		}),
+	})
+	registries := []Registry{{Name: "org-a", Repo: repo}}
+
+	names := func(infos []RegistryAssetInfo) []string {
+		var out []string
+		for _, m := range infos {
+			out = append(out, string(m.Kind)+" "+m.Entry.Name)
+		}
+		return out
+	}
+
+	t.Run("every kind", func(t *testing.T) {
+		matches, err := rm.TaggedAssets(registries, asset.Kinds(), "backend", "")
+		if err != nil {
+			t.Fatalf("TaggedAssets() error = %v", err)
+		}
+		if got, want := names(matches), []string{"skill api-review", "skill go-vet", "mcp db"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("matches = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("one kind", func(t *testing.T) {
+		matches, err := rm.TaggedAssets(registries, []asset.Kind{asset.KindMCP}, "backend", "")
+		if err != nil {
+			t.Fatalf("TaggedAssets() error = %v", err)
+		}
+		if got, want := names(matches), []string{"mcp db"}; !reflect.DeepEqual(got, want) {
+			t.Errorf("matches = %v, want %v", got, want)
+		}
+	})
+
+	t.Run("unknown tag", func(t *testing.T) {
+		_, err := rm.TaggedAssets(registries, asset.Kinds(), "mobile", "")
+		if err == nil || !containsStr(err.Error(), `no registry entries are tagged "mobile"`) {
+			t.Errorf("error = %v, want no-match error", err)
+		}
+	})
+}
+
 // containsStr is a simple substring check for test assertions.
 func containsStr(s, substr string) bool {
 	return strings.Contains(s, substr)
 }
 
+func TestValidateRegistryAlias(t *testing.T) {
+	registries := []Registry{
+		{Name: "acme-platform", Repo: "git@example.com:acme/platform.git", Alias: "ap"},
+		{Name: "acme-data", Repo: "git@example.com:acme/data.git"},
+	}
+
+	tests := []struct {
+		name    string
+		repo    string
+		alias   string
+		wantErr string
+	}{
+		{name: "free alias", repo: "git@example.com:acme/data.git", alias: "ad"},
+		{name: "own alias again", repo: "git@example.com:acme/platform.git", alias: "ap"},
+		{name: "own name", repo: "git@example.com:acme/data.git", alias: "acme-data"},
+		{name: "invalid", repo: "git@example.com:acme/data.git", alias: "Acme Data", wantErr: "invalid registry alias"},
+		{name: "alias of another", repo: "git@example.com:acme/data.git", alias: "ap", wantErr: "already used by registry acme-platform"},
+		{name: "name of another", repo: "git@example.com:acme/data.git", alias: "acme-platform", wantErr: "is the name of registry"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateRegistryAlias(registries, tt.repo, tt.alias)
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Errorf("ValidateRegistryAlias() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Errorf("ValidateRegistryAlias() error = %v, want %q", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestRegistryMatches(t *testing.T) {
+	r := Registry{Name: "acme-platform", Repo: "git@example.com:acme/platform.git", Alias: "ap"}
+	for _, ref := range []string{"acme-platform", "git@example.com:acme/platform.git", "ap"} {
+		if !r.Matches(ref) {
+			t.Errorf("Matches(%q) = false, want true", ref)
+		}
+	}
+	for _, ref := range []string{"", "platform", "acme"} {
+		if r.Matches(ref) {
+			t.Errorf("Matches(%q) = true, want false", ref)
+		}
+	}
+}
+
 func TestRegistryManager_Remove(t *testing.T) {
 	t.Run("removes registry clone", func(t *testing.T) {
 		registriesDir := t.TempDir()
@@ -549,6 +800,86 @@ func TestRegistryManager_Remove(t *testing.T) {
 	})
 }
 
+func TestIsLocalRegistryPath(t *testing.T) {
+	tests := []struct {
+		arg  string
+		want bool
+	}{
+		{"./registry", true},
+		{"../registry", true},
+		{"~/registry", true},
+		{".", true},
+		{"/srv/registry", true},
+		{"registry", false},
+		{"git@github.com:org/registry.git", false},
+		{"https://github.com/org/registry.git", false},
+	}
+	for _, tt := range tests {
+		if got := IsLocalRegistryPath(tt.arg); got != tt.want {
+			t.Errorf("IsLocalRegistryPath(%q) = %v, want %v", tt.arg, got, tt.want)
+		}
+	}
+}
+
+func TestRegistryManager_AddLocal(t *testing.T) {
+	registriesDir := t.TempDir()
+	rm := NewRegistryManager(registriesDir)
+
+	src := filepath.Join(t.TempDir(), "team-registry")
+	createTestManifest(t, src, RegistryManifest{
+		Name:   "team",
+		Skills: skillEntriesToRaw([]testSkillEntry{{Name: "lint", Source: "github.com/org/repo/lint"}}),
+	})
+
+	repo, manifest, err := rm.AddLocal(src)
+	if err != nil {
+		t.Fatalf("AddLocal() error = %v", err)
+	}
+	if repo != src || manifest.Name != "team" {
+		t.Fatalf("AddLocal() = %q, %q; want %q, team", repo, manifest.Name, src)
+	}
+	if !rm.IsLocal(repo) {
+		t.Error("IsLocal() = false after AddLocal")
+	}
+	if _, err := os.Stat(filepath.Join(registriesDir, RegistryDirKey(repo), registryManifestFile)); !os.IsNotExist(err) {
+		t.Error("AddLocal copied the manifest into the registries dir")
+	}
+
+	// Edits show up without a refresh.
+	createTestManifest(t, src, RegistryManifest{
+		Name: "team",
+		Skills: skillEntriesToRaw([]testSkillEntry{
+			{Name: "lint", Source: "github.com/org/repo/lint"},
+			{Name: "format", Source: "github.com/org/repo/format"},
+		}),
+	})
+	loaded, err := rm.LoadManifest(repo)
+	if err != nil {
+		t.Fatalf("LoadManifest() error = %v", err)
+	}
+	if len(loaded.Skills) != 2 {
+		t.Errorf("LoadManifest() has %d skills, want 2", len(loaded.Skills))
+	}
+	if _, err := rm.Refresh(repo); err != nil {
+		t.Errorf("Refresh() error = %v", err)
+	}
+
+	// Removing the registry leaves the directory it was added from alone.
+	if err := rm.Remove(repo); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if !dirExists(src) {
+		t.Error("Remove() deleted the local registry directory")
+	}
+	if _, err := rm.LoadManifest(repo); err == nil {
+		t.Error("LoadManifest() after Remove: expected error")
+	}
+
+	if _, _, err := rm.AddLocal(filepath.Join(t.TempDir(), "missing")); err == nil {
+		t.Error("AddLocal() of a missing directory: expected error")
+	}
+}
+
 // Integration tests that require git — skipped with -short
 func TestRegistryManager_Add_Integration(t *testing.T) {
 	if testing.Short() {
@@ -984,6 +1315,62 @@ func TestBuildRegistryCommitMap(t *testing.T) {
 	})
 }
 
+func TestRegistryManager_Mirrors(t *testing.T) {
+	registriesDir := t.TempDir()
+	rm := NewRegistryManager(registriesDir)
+
+	repoA := "git@example.com:org/reg-a.git"
+	repoB := "git@example.com:org/reg-b.git"
+	createTestRegistryClone(t, registriesDir, repoA, RegistryManifest{
+		Name: "org-a",
+		Skills: skillEntriesToRaw([]testSkillEntry{
+			{Name: "mirrored", Source: "github.com/Org/Repo/mirrored", CloneURL: "https://git.corp.example/mirror/org-repo.git"},
+			{Name: "plain", Source: "github.com/org/repo/plain"},
+		}),
+	})
+	createTestRegistryClone(t, registriesDir, repoB, RegistryManifest{
+		Name: "org-b",
+		Skills: skillEntriesToRaw([]testSkillEntry{
+			{Name: "mirrored", Source: "github.com/org/repo/mirrored", CloneURL: "https://elsewhere.example/org-repo.git"},
+			{Name: "other", Source: "github.com/org/repo/other", CloneURL: "https://elsewhere.example/org-repo.git"},
+		}),
+	})
+	mirrors := rm.Mirrors([]Registry{{Name: "org-a", Repo: repoA}, {Name: "org-b", Repo: repoB}})
+
+	locked := func(name, source string) asset.LockedAsset {
+		return asset.LockedAsset{Kind: asset.KindSkill, Name: name, Source: source}
+	}
+	tests := []struct {
+		name   string
+		locked asset.LockedAsset
+		want   string
+	}{
+		{"the first registry's entry wins", locked("mirrored", "github.com/org/repo/mirrored"), "https://git.corp.example/mirror/org-repo.git"},
+		{"entry of another registry", locked("other", "github.com/org/repo/other"), "https://elsewhere.example/org-repo.git"},
+		{"entry without a mirror, same repository", locked("plain", "github.com/org/repo/plain"), ""},
+		{"same repository, not a registry entry", locked("direct", "github.com/org/repo/direct"), ""},
+		{"same owner/repo on another host", locked("mirrored", "gitlab.com/org/repo/mirrored"), ""},
+		{"same name from another repository", locked("mirrored", "github.com/fork/repo/mirrored"), ""},
+		{"agent of the same name", asset.LockedAsset{Kind: asset.KindAgent, Name: "mirrored", Source: "github.com/org/repo/mirrored"}, ""},
+	}
+	for _, tt := range tests {
+		if got := mirrors.Locked(tt.locked); got != tt.want {
+			t.Errorf("%s: Locked() = %q, want %q", tt.name, got, tt.want)
+		}
+	}
+
+	// The user's overrides win over a mirror.
+	source := &ParsedSource{Host: "github.com", Owner: "org", Repo: "repo", CloneURL: "https://github.com/org/repo.git"}
+	source.ApplyMirror(map[string]string{"github.com/*": "https://proxy.example/{owner}/{repo}.git"}, mirrors.Locked(tests[0].locked))
+	if source.CloneURL != "https://proxy.example/org/repo.git" {
+		t.Errorf("ApplyMirror() with an override = %q, want the override", source.CloneURL)
+	}
+	source.ApplyMirror(nil, mirrors.Locked(tests[0].locked))
+	if source.CloneURL != "https://git.corp.example/mirror/org-repo.git" {
+		t.Errorf("ApplyMirror() = %q, want the mirror", source.CloneURL)
+	}
+}
+
 func TestLoadCachedCommits(t *testing.T) {
 	t.Run("returns empty map for missing file", func(t *testing.T) {
 		dir := t.TempDir()
@@ -1716,3 +2103,217 @@ func TestRegistryManager_FindMCP(t *testing.T) {
 		}
 	})
 }
+
+func TestRegistryManager_Hydrate_TTL(t *testing.T) {
+	setup := func(t *testing.T, cached map[string]string) (*RegistryManager, []Registry, HydrateOptions) {
+		t.Helper()
+		registriesDir := t.TempDir()
+		rm := NewRegistryManager(registriesDir)
+		repoURL := "git@example.com:org/reg.git"
+		regDir := createTestRegistryClone(t, registriesDir, repoURL, RegistryManifest{
+			Name: "org",
+			Skills: skillEntriesToRaw([]testSkillEntry{
+				{Name: "a", Source: "localhost/testorg/testrepo/a"},
+				{Name: "b", Source: "localhost/testorg/testrepo/b"},
+			}),
+		})
+		if cached != nil {
+			if err := writeCachedCommits(regDir, cached); err != nil {
+				t.Fatal(err)
+			}
+		}
+		// Point the source at a path that doesn't exist, so any clone fails
+		// and is reported in Failed.
+		opts := HydrateOptions{
+			Overrides: map[string]string{"testorg/testrepo": filepath.Join(t.TempDir(), "missing")},
+			TTL:       time.Hour,
+		}
+		return rm, []Registry{{Name: "org", Repo: repoURL}}, opts
+	}
+	full := map[string]string{
+		"localhost/testorg/testrepo/a": "aaa",
+		"localhost/testorg/testrepo/b": "bbb",
+	}
+
+	t.Run("fresh cache is reused", func(t *testing.T) {
+		rm, regs, opts := setup(t, full)
+		res := rm.Hydrate(regs, opts)
+		if len(res) != 1 || !res[0].Fresh || len(res[0].Failed) != 0 {
+			t.Fatalf("Hydrate() = %+v, want fresh without clone attempts", res)
+		}
+		if res[0].Unpinned != 2 {
+			t.Errorf("Unpinned = %d, want 2", res[0].Unpinned)
+		}
+	})
+
+	t.Run("force ignores fresh cache", func(t *testing.T) {
+		rm, regs, opts := setup(t, full)
+		opts.Force = true
+		res := rm.Hydrate(regs, opts)
+		if len(res) != 1 || res[0].Fresh || len(res[0].Failed) != 1 {
+			t.Fatalf("Hydrate() = %+v, want one failed clone attempt", res)
+		}
+	})
+
+	t.Run("cache missing a source is stale", func(t *testing.T) {
+		rm, regs, opts := setup(t, map[string]string{"localhost/testorg/testrepo/a": "aaa"})
+		if res := rm.Hydrate(regs, opts); res[0].Fresh {
+			t.Fatalf("Hydrate() = %+v, want re-resolve", res)
+		}
+	})
+
+	t.Run("zero TTL is always stale", func(t *testing.T) {
+		rm, regs, opts := setup(t, full)
+		opts.TTL = 0
+		if res := rm.Hydrate(regs, opts); res[0].Fresh {
+			t.Fatalf("Hydrate() = %+v, want re-resolve", res)
+		}
+	})
+
+	t.Run("cache time is reported", func(t *testing.T) {
+		rm, regs, _ := setup(t, full)
+		at, ok := rm.CommitCacheTime(regs[0].Repo)
+		if !ok || time.Since(at) > time.Minute {
+			t.Errorf("CommitCacheTime() = %v, %v", at, ok)
+		}
+		if _, ok := rm.CommitCacheTime("git@example.com:org/other.git"); ok {
+			t.Error("expected no cache time for unknown registry")
+		}
+	})
+}
+
+func TestSettings_CommitCacheTTL(t *testing.T) {
+	tests := []struct {
+		minutes int
+		want    time.Duration
+	}{
+		{0, DefaultCommitCacheTTL},
+		{15, 15 * time.Minute},
+		{-1, 0},
+	}
+	for _, tt := range tests {
+		if got := (Settings{CommitCacheTTLMinutes: tt.minutes}).CommitCacheTTL(); got != tt.want {
+			t.Errorf("CommitCacheTTL(%d) = %v, want %v", tt.minutes, got, tt.want)
+		}
+	}
+}
+
+func TestRegistryManager_Hydrate_OptOut(t *testing.T) {
+	no := false
+	missing := map[string]string{"testorg/testrepo": filepath.Join(t.TempDir(), "missing")}
+
+	newRegistry := func(t *testing.T, manifest RegistryManifest) (*RegistryManager, Registry, string) {
+		t.Helper()
+		registriesDir := t.TempDir()
+		repoURL := "git@example.com:org/reg.git"
+		regDir := createTestRegistryClone(t, registriesDir, repoURL, manifest)
+		return NewRegistryManager(registriesDir), Registry{Name: "org", Repo: repoURL}, regDir
+	}
+	entries := skillEntriesToRaw([]testSkillEntry{
+		{Name: "a", Source: "localhost/testorg/testrepo/a"},
+		{Name: "b", Source: "localhost/testorg/testrepo/b"},
+	})
+
+	t.Run("global setting", func(t *testing.T) {
+		rm, reg, _ := newRegistry(t, RegistryManifest{Name: "org", Skills: entries})
+		res := rm.Hydrate([]Registry{reg}, HydrateOptions{Overrides: missing, Disabled: true})
+		if !res[0].Disabled || res[0].Unpinned != 2 || len(res[0].Failed) != 0 {
+			t.Errorf("Hydrate() = %+v, want disabled without clone attempts", res[0])
+		}
+	})
+
+	t.Run("registry config", func(t *testing.T) {
+		rm, reg, _ := newRegistry(t, RegistryManifest{Name: "org", Skills: entries})
+		reg.Hydrate = &no
+		if res := rm.Hydrate([]Registry{reg}, HydrateOptions{Overrides: missing}); !res[0].Disabled {
+			t.Errorf("Hydrate() = %+v, want disabled", res[0])
+		}
+	})
+
+	t.Run("manifest drops stale cache", func(t *testing.T) {
+		rm, reg, regDir := newRegistry(t, RegistryManifest{Name: "org", Hydrate: &no, Skills: entries})
+		if err := writeCachedCommits(regDir, map[string]string{"localhost/testorg/testrepo/a": "old"}); err != nil {
+			t.Fatal(err)
+		}
+		if res := rm.Hydrate([]Registry{reg}, HydrateOptions{Overrides: missing}); !res[0].Disabled {
+			t.Errorf("Hydrate() = %+v, want disabled", res[0])
+		}
+		if commits := BuildRegistryCommitMap([]Registry{reg}, rm); len(commits) != 0 {
+			t.Errorf("commit map = %v, want stale cache dropped", commits)
+		}
+	})
+
+	t.Run("entry opt-out", func(t *testing.T) {
+		raw := []json.RawMessage{
+			json.RawMessage(`{"name":"a","source":"localhost/testorg/testrepo/a","hydrate":false}`),
+			json.RawMessage(`{"name":"b","source":"localhost/testorg/testrepo/b"}`),
+		}
+		rm, reg, _ := newRegistry(t, RegistryManifest{Name: "org", Skills: raw})
+		res := rm.Hydrate([]Registry{reg}, HydrateOptions{Overrides: missing})
+		if res[0].Disabled || res[0].Skipped != 1 || len(res[0].Failed) != 1 {
+			t.Errorf("Hydrate() = %+v, want one opted out and one clone attempt", res[0])
+		}
+	})
+}
+
+// FuzzRegistryDirKey checks that registry directory keys are safe, single
+// path components and that different repo URLs don't share a directory.
+func FuzzRegistryDirKey(f *testing.F) {
+	for _, s := range sourceSeeds {
+		f.Add(s, "https://github.com/owner/repo")
+	}
+	f.Add("https://github.com/Owner/Repo", "https://github.com/owner/repo")
+	f.Add("git@github.com:owner/repo.git", "https://github.com/owner/repo.git")
+	f.Add("https://example.com/a/b:c", "https://example.com/a/b/c")
+	f.Fuzz(func(t *testing.T, a, b string) {
+		key := RegistryDirKey(a)
+		if key == "" || key == "." || key == ".." || len(key) > 255 {
+			t.Fatalf("RegistryDirKey(%q) = %q: not a usable directory name", a, key)
+		}
+		for _, r := range key {
+			if !(r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.') {
+				t.Fatalf("RegistryDirKey(%q) = %q: unsafe character %q", a, key, r)
+			}
+		}
+		if RegistryDirKey(a) != key {
+			t.Fatalf("RegistryDirKey(%q) is not deterministic", a)
+		}
+		if a != b && RegistryDirKey(b) == key {
+			t.Errorf("RegistryDirKey(%q) == RegistryDirKey(%q) == %q", a, b, key)
+		}
+	})
+}
+
+// FuzzParseManifest feeds arbitrary duckrow.json contents through the
+// manifest parser, which must reject or warn about them but never panic.
+func FuzzParseManifest(f *testing.F) {
+	f.Add([]byte(`{"name":"team","skills":[{"name":"lint","source":"github.com/acme/skills/lint"}]}`))
+	f.Add([]byte(`{"version":2,"name":"team","assets":{"skill":[{"name":"lint","source":"github.com/acme/skills/lint","commit":"abc"}],"mcp":[{"name":"db","command":"npx","args":["db"],"env":{"URL":"x"}}],"agent":[{"name":"review","source":"github.com/acme/agents/review.md"}]}}`))
+	f.Add([]byte(`{"name":"team","mcps":[{"name":"remote","url":"https://mcp.example.com","type":"http"},{"command":"x"}]}`))
+	f.Add([]byte(`{"name":"team","assets":{"rule":[{}],"skill":{}}}`))
+	f.Add([]byte(`{"name":"team","skills":[{"name":"../../etc","source":"owner/repo/../../etc"}]}`))
+	f.Add([]byte(`{"name":"team","hydrate":false,"skills":[null,1,"x"]}`))
+	f.Fuzz(func(t *testing.T, data []byte) {
+		var raw RegistryManifest
+		if err := json.Unmarshal(data, &raw); err != nil {
+			return
+		}
+		pm, err := ParseManifest(&raw)
+		if err != nil {
+			return
+		}
+		if pm == nil {
+			t.Fatal("ParseManifest() = nil, nil")
+		}
+		for kind, entries := range pm.Entries {
+			for _, e := range entries {
+				if e.Source == "" {
+					continue
+				}
+				if ps, err := ParseSource(e.Source); err == nil && escapesRepo(ps.SubPath) {
+					t.Errorf("%s %q: source %q escapes its repository", kind, e.Name, e.Source)
+				}
+			}
+		}
+	})
+}
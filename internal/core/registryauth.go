@@ -0,0 +1,171 @@
+package core
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync/atomic"
+)
+
+// Registry auth types.
+const (
+	// AuthHTTPSBasic sends the token as the password of HTTP basic auth,
+	// which GitHub, GitLab, Gitea, and Bitbucket accept for git over HTTPS.
+	AuthHTTPSBasic = "https-basic"
+	// AuthBearer sends the token as a bearer token, e.g. for Azure DevOps.
+	AuthBearer = "bearer"
+)
+
+// defaultAuthUsername is the basic auth user name when none is configured.
+// GitHub documents it for tokens; other hosts accept any name.
+const defaultAuthUsername = "x-access-token"
+
+// Validate reports an error if the auth configuration cannot be used.
+func (a RegistryAuth) Validate() error {
+	if a.TokenEnv == "" {
+		return fmt.Errorf("auth: tokenEnv is required")
+	}
+	switch a.Type {
+	case "", AuthHTTPSBasic, AuthBearer:
+		return nil
+	default:
+		return fmt.Errorf("auth: unknown type %q (want %q or %q)", a.Type, AuthHTTPSBasic, AuthBearer)
+	}
+}
+
+// header returns the Authorization header value for the token in TokenEnv,
+// or "" when the variable is not set.
+func (a RegistryAuth) header() string {
+	token := os.Getenv(a.TokenEnv)
+	if token == "" {
+		return ""
+	}
+	if a.Type == AuthBearer {
+		return "Bearer " + token
+	}
+	username := a.Username
+	if username == "" {
+		username = defaultAuthUsername
+	}
+	return "Basic " + base64.StdEncoding.EncodeToString([]byte(username+":"+token))
+}
+
+// registryAuthScope is where a registry's auth applies: HTTPS URLs under
+// prefix, the registry's host and owner.
+type registryAuthScope struct {
+	prefix   string
+	registry string
+	auth     RegistryAuth
+}
+
+// registryAuthValue is process-wide like the host allowlist: the CLI sets
+// it once from the configured registries, and every git and HTTP helper
+// consults it.
+var registryAuthValue atomic.Value
+
+// SetRegistryAuth sets the credentials of the registries that configure
+// auth. A registry's token is sent to HTTPS URLs under its repository's
+// host and owner, so the skill sources it lists from the same organization
+// are cloned with it too. Registries without auth, or not served over
+// HTTPS, are skipped, as are ones whose auth does not validate.
+func SetRegistryAuth(registries []Registry) {
+	var scopes []registryAuthScope
+	for _, r := range registries {
+		if r.Auth == nil || r.Auth.Validate() != nil {
+			continue
+		}
+		prefix := authPrefix(r.Repo)
+		if prefix == "" {
+			continue
+		}
+		scopes = append(scopes, registryAuthScope{prefix: prefix, registry: r.Name, auth: *r.Auth})
+	}
+	registryAuthValue.Store(scopes)
+}
+
+// authPrefix returns the scheme, host, and first path segment (the owner)
+// of an HTTPS URL, or "" for any other URL.
+func authPrefix(rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme != "https" || u.Host == "" {
+		return ""
+	}
+	owner, _, _ := strings.Cut(strings.TrimPrefix(u.Path, "/"), "/")
+	prefix := "https://" + strings.ToLower(u.Host)
+	if owner != "" {
+		prefix += "/" + owner
+	}
+	return prefix
+}
+
+// registryAuthFor returns the auth scope that applies to rawURL, the one
+// with the longest prefix when several do.
+func registryAuthFor(rawURL string) (registryAuthScope, bool) {
+	scopes, _ := registryAuthValue.Load().([]registryAuthScope)
+	target := authPrefix(rawURL)
+	if target == "" {
+		return registryAuthScope{}, false
+	}
+	var best registryAuthScope
+	found := false
+	for _, s := range scopes {
+		if (target == s.prefix || strings.HasPrefix(target+"/", s.prefix+"/")) && len(s.prefix) > len(best.prefix) {
+			best, found = s, true
+		}
+	}
+	return best, found
+}
+
+// authorizationHeader returns the Authorization header to send to rawURL,
+// or "" when no registry auth applies or its token is not set.
+func authorizationHeader(rawURL string) string {
+	scope, ok := registryAuthFor(rawURL)
+	if !ok {
+		return ""
+	}
+	return scope.auth.header()
+}
+
+// gitEnv returns the environment for a git command that contacts remote:
+// interactive credential prompts are turned off, and when registry auth
+// applies to remote its token is passed as an extra HTTP header for that
+// URL prefix only. The header goes through GIT_CONFIG_* variables rather
+// than -c so the token does not show up in the process list.
+func gitEnv(remote string) []string {
+	env := append(os.Environ(), "GIT_TERMINAL_PROMPT=0")
+	scope, ok := registryAuthFor(remote)
+	if !ok {
+		return env
+	}
+	header := scope.auth.header()
+	if header == "" {
+		return env
+	}
+	n, _ := strconv.Atoi(os.Getenv("GIT_CONFIG_COUNT"))
+	return append(env,
+		"GIT_CONFIG_COUNT="+strconv.Itoa(n+1),
+		fmt.Sprintf("GIT_CONFIG_KEY_%d=http.%s/.extraHeader", n, scope.prefix),
+		fmt.Sprintf("GIT_CONFIG_VALUE_%d=Authorization: %s", n, header),
+	)
+}
+
+// registryAuthHint suggests how to fix a rejected HTTPS clone of cloneURL
+// with registry auth: setting the token variable of the registry whose
+// auth applies, checking the token if it was set, or else configuring auth.
+// It returns "" for URLs other than HTTPS ones.
+func registryAuthHint(cloneURL string) string {
+	scope, ok := registryAuthFor(cloneURL)
+	switch {
+	case authPrefix(cloneURL) == "":
+		return ""
+	case !ok:
+		return `For CI, give the registry a token in ~/.duckrow/config.json: "auth": {"tokenEnv": "GH_TOKEN", "type": "https-basic"}`
+	case os.Getenv(scope.auth.TokenEnv) == "":
+		return fmt.Sprintf("Set %s: registry %s reads its token from it", scope.auth.TokenEnv, scope.registry)
+	default:
+		return fmt.Sprintf("The token in %s (registry %s auth) was rejected; check it has read access to this repository", scope.auth.TokenEnv, scope.registry)
+	}
+}
@@ -0,0 +1,95 @@
+package core
+
+import (
+	"encoding/base64"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestRegistryAuth_GitEnv(t *testing.T) {
+	t.Setenv("GIT_CONFIG_COUNT", "")
+	t.Setenv("ACME_TOKEN", "s3cret")
+	t.Setenv("CORP_TOKEN", "")
+	SetRegistryAuth([]Registry{
+		{Name: "acme", Repo: "https://github.com/acme/registry.git", Auth: &RegistryAuth{TokenEnv: "ACME_TOKEN"}},
+		{Name: "corp", Repo: "https://git.corp.example/team/registry.git", Auth: &RegistryAuth{TokenEnv: "CORP_TOKEN", Type: AuthBearer}},
+		{Name: "ssh", Repo: "git@github.com:other/registry.git", Auth: &RegistryAuth{TokenEnv: "ACME_TOKEN"}},
+		{Name: "public", Repo: "https://github.com/public/registry.git"},
+	})
+	t.Cleanup(func() { SetRegistryAuth(nil) })
+
+	// Sources under the registry's owner get its token, scoped to the owner.
+	env := gitEnv("https://github.com/acme/skills.git")
+	basic := "Basic " + base64.StdEncoding.EncodeToString([]byte("x-access-token:s3cret"))
+	if !slices.Contains(env, "GIT_CONFIG_KEY_0=http.https://github.com/acme/.extraHeader") ||
+		!slices.Contains(env, "GIT_CONFIG_VALUE_0=Authorization: "+basic) {
+		t.Errorf("gitEnv(acme source) = %v, want a basic auth header for github.com/acme", authEnv(env))
+	}
+	if !slices.Contains(env, "GIT_TERMINAL_PROMPT=0") {
+		t.Error("gitEnv() should turn off credential prompts")
+	}
+
+	// Other owners, SSH URLs, and unset tokens get no header.
+	for _, remote := range []string{
+		"https://github.com/other/registry.git",
+		"https://github.com/acmecorp/skills.git",
+		"git@github.com:acme/skills.git",
+		"https://git.corp.example/team/skills.git",
+	} {
+		if got := authEnv(gitEnv(remote)); len(got) != 0 {
+			t.Errorf("gitEnv(%s) = %v, want no auth", remote, got)
+		}
+	}
+
+	t.Setenv("CORP_TOKEN", "t0ken")
+	if got := authorizationHeader("https://git.corp.example/team/duckrow.json"); got != "Bearer t0ken" {
+		t.Errorf("authorizationHeader(corp) = %q, want bearer token", got)
+	}
+}
+
+func TestRegistryAuth_Validate(t *testing.T) {
+	if err := (RegistryAuth{}).Validate(); err == nil {
+		t.Error("Validate() without tokenEnv should fail")
+	}
+	if err := (RegistryAuth{TokenEnv: "T", Type: "digest"}).Validate(); err == nil {
+		t.Error("Validate() with an unknown type should fail")
+	}
+	if err := (RegistryAuth{TokenEnv: "T", Type: AuthBearer}).Validate(); err != nil {
+		t.Errorf("Validate() = %v", err)
+	}
+}
+
+func TestRegistryAuthHint(t *testing.T) {
+	t.Setenv("ACME_TOKEN", "")
+	SetRegistryAuth([]Registry{
+		{Name: "acme", Repo: "https://github.com/acme/registry.git", Auth: &RegistryAuth{TokenEnv: "ACME_TOKEN"}},
+	})
+	t.Cleanup(func() { SetRegistryAuth(nil) })
+
+	hints := hintsForError(CloneErrAuth, "https", "https://github.com/other/repo.git")
+	if !slices.ContainsFunc(hints, func(h string) bool { return strings.Contains(h, `"tokenEnv": "GH_TOKEN"`) }) {
+		t.Errorf("hints = %v, want a suggestion to configure registry auth", hints)
+	}
+	if got := registryAuthHint("https://github.com/acme/skills.git"); !strings.Contains(got, "Set ACME_TOKEN") {
+		t.Errorf("hint with the token unset = %q", got)
+	}
+	t.Setenv("ACME_TOKEN", "bad")
+	if got := registryAuthHint("https://github.com/acme/skills.git"); !strings.Contains(got, "was rejected") {
+		t.Errorf("hint with the token set = %q", got)
+	}
+	if got := registryAuthHint("git@github.com:acme/skills.git"); got != "" {
+		t.Errorf("hint for SSH = %q, want none", got)
+	}
+}
+
+// authEnv returns the GIT_CONFIG_* entries of env.
+func authEnv(env []string) []string {
+	var out []string
+	for _, e := range env {
+		if strings.HasPrefix(e, "GIT_CONFIG_") && !strings.HasPrefix(e, "GIT_CONFIG_COUNT=") {
+			out = append(out, e)
+		}
+	}
+	return out
+}
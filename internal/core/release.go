@@ -0,0 +1,224 @@
+package core
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	// DefaultReleaseBaseURL is where tagged duckrow releases are published.
+	DefaultReleaseBaseURL = "https://github.com/barysiuk/duckrow/releases/download"
+
+	// releaseChecksumsFile is the SHA256SUMS-style manifest published with
+	// each release ("<sha256>  <filename>" per line).
+	releaseChecksumsFile = "checksums.txt"
+
+	// maxReleaseDownloadSize caps a release file download; archives are
+	// tens of megabytes.
+	maxReleaseDownloadSize = 200 << 20
+)
+
+// ReleaseInstallOptions configures InstallRelease.
+type ReleaseInstallOptions struct {
+	Version  string // release version, with or without a leading "v"
+	OS       string // GOOS of the binary to install
+	Arch     string // GOARCH of the binary to install
+	DestDir  string // directory to place the binary in
+	BaseURL  string // release download base URL; defaults to DefaultReleaseBaseURL
+	Checksum string // expected archive SHA256 (required); it must also match checksums.txt
+}
+
+// ReleaseInstallResult describes an installed binary.
+type ReleaseInstallResult struct {
+	Path     string // installed binary
+	Archive  string // archive file name that was downloaded
+	Checksum string // verified SHA256 of the archive
+}
+
+// ReleaseArchiveName returns the archive file name for a release, matching
+// the names produced by the release build.
+func ReleaseArchiveName(version, goos, goarch string) string {
+	ext := "tar.gz"
+	if goos == "windows" {
+		ext = "zip"
+	}
+	return fmt.Sprintf("duckrow_%s_%s_%s.%s", strings.TrimPrefix(version, "v"), goos, goarch, ext)
+}
+
+// InstallRelease downloads a duckrow release archive, verifies it against
+// the pinned checksum and the release's checksums.txt, and atomically
+// places the binary in DestDir. Releases are not signed, so checksums.txt
+// comes from the same host as the archive and can't be trusted on its own:
+// the pinned checksum is required.
+func InstallRelease(opts ReleaseInstallOptions) (*ReleaseInstallResult, error) {
+	if opts.Version == "" {
+		return nil, fmt.Errorf("release version is required")
+	}
+	if opts.Checksum == "" {
+		return nil, fmt.Errorf("the archive's SHA256 checksum is required: %s comes from the download host and can't be trusted on its own", releaseChecksumsFile)
+	}
+	if b, err := hex.DecodeString(opts.Checksum); err != nil || len(b) != sha256.Size {
+		return nil, fmt.Errorf("pinned checksum %q is not a SHA256 in hex", opts.Checksum)
+	}
+	version := strings.TrimPrefix(opts.Version, "v")
+	baseURL := strings.TrimSuffix(opts.BaseURL, "/")
+	if baseURL == "" {
+		baseURL = DefaultReleaseBaseURL
+	}
+	releaseURL := baseURL + "/v" + version
+	archive := ReleaseArchiveName(version, opts.OS, opts.Arch)
+
+	client := &http.Client{Timeout: CurrentTimeouts().Download}
+
+	sums, err := downloadRelease(client, releaseURL+"/"+releaseChecksumsFile)
+	if err != nil {
+		return nil, err
+	}
+	expected, err := lookupChecksum(sums, archive)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(opts.Checksum, expected) {
+		return nil, fmt.Errorf("pinned checksum %s does not match %s in %s (%s)",
+			opts.Checksum, archive, releaseChecksumsFile, expected)
+	}
+
+	data, err := downloadRelease(client, releaseURL+"/"+archive)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(data)
+	actual := hex.EncodeToString(sum[:])
+	if !strings.EqualFold(actual, expected) {
+		return nil, fmt.Errorf("checksum mismatch for %s: expected %s, got %s", archive, expected, actual)
+	}
+
+	binName := "duckrow"
+	if opts.OS == "windows" {
+		binName += ".exe"
+	}
+	var bin []byte
+	if strings.HasSuffix(archive, ".zip") {
+		bin, err = extractFromZip(data, binName)
+	} else {
+		bin, err = extractFromTarGz(data, binName)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("extracting %s: %w", archive, err)
+	}
+
+	if err := os.MkdirAll(opts.DestDir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", opts.DestDir, err)
+	}
+	dest := filepath.Join(opts.DestDir, binName)
+	tmpPath := dest + ".tmp"
+	if err := os.WriteFile(tmpPath, bin, 0o755); err != nil {
+		return nil, fmt.Errorf("writing %s: %w", dest, err)
+	}
+	if err := os.Rename(tmpPath, dest); err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, fmt.Errorf("writing %s: %w", dest, err)
+	}
+
+	return &ReleaseInstallResult{Path: dest, Archive: archive, Checksum: actual}, nil
+}
+
+// downloadRelease fetches a release file into memory. Redirects, e.g. from
+// GitHub to its release asset storage, are checked against the host
+// allowlist like the first request.
+func downloadRelease(client *http.Client, url string) ([]byte, error) {
+	if err := checkNetwork(url); err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", url, err)
+	}
+	resp, err := doHTTP(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", url, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s: %s", url, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxReleaseDownloadSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", url, err)
+	}
+	if len(data) > maxReleaseDownloadSize {
+		return nil, fmt.Errorf("downloading %s: larger than %d MB", url, maxReleaseDownloadSize>>20)
+	}
+	return data, nil
+}
+
+// lookupChecksum finds the SHA256 for name in SHA256SUMS-formatted data.
+func lookupChecksum(sums []byte, name string) (string, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(sums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) != 2 {
+			continue
+		}
+		// sha256sum marks binary mode with a leading '*'.
+		if strings.TrimPrefix(fields[1], "*") == name {
+			return strings.ToLower(fields[0]), nil
+		}
+	}
+	return "", fmt.Errorf("%s is not listed in %s", name, releaseChecksumsFile)
+}
+
+func extractFromTarGz(data []byte, name string) ([]byte, error) {
+	gz, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = gz.Close() }()
+
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		if hdr.Typeflag == tar.TypeReg && filepath.Base(hdr.Name) == name {
+			return io.ReadAll(tr)
+		}
+	}
+	return nil, fmt.Errorf("%s not found in archive", name)
+}
+
+func extractFromZip(data []byte, name string) ([]byte, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, err
+	}
+	for _, f := range zr.File {
+		if f.FileInfo().IsDir() || filepath.Base(f.Name) != name {
+			continue
+		}
+		rc, err := f.Open()
+		if err != nil {
+			return nil, err
+		}
+		defer func() { _ = rc.Close() }()
+		return io.ReadAll(rc)
+	}
+	return nil, fmt.Errorf("%s not found in archive", name)
+}
@@ -0,0 +1,186 @@
+package core
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// buildTarGz returns a gzipped tarball containing the given files.
+func buildTarGz(t *testing.T, files map[string]string) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for name, content := range files {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0o755, Size: int64(len(content)), Typeflag: tar.TypeReg}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := tw.Write([]byte(content)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if err := gz.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+// newReleaseServer serves a fake v1.2.3 release with the given checksums.
+func newReleaseServer(t *testing.T, archive []byte, sums string) *httptest.Server {
+	t.Helper()
+	name := ReleaseArchiveName("1.2.3", "linux", "amd64")
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/v1.2.3/checksums.txt":
+			_, _ = w.Write([]byte(sums))
+		case "/v1.2.3/" + name:
+			_, _ = w.Write(archive)
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestInstallRelease(t *testing.T) {
+	archive := buildTarGz(t, map[string]string{"README.md": "docs", "duckrow": "#!binary"})
+	sum := sha256.Sum256(archive)
+	hexSum := hex.EncodeToString(sum[:])
+	name := ReleaseArchiveName("1.2.3", "linux", "amd64")
+	srv := newReleaseServer(t, archive, fmt.Sprintf("deadbeef  other.tar.gz\n%s  %s\n", hexSum, name))
+
+	dest := t.TempDir()
+	res, err := InstallRelease(ReleaseInstallOptions{
+		Version:  "v1.2.3",
+		OS:       "linux",
+		Arch:     "amd64",
+		DestDir:  dest,
+		BaseURL:  srv.URL,
+		Checksum: strings.ToUpper(hexSum),
+	})
+	if err != nil {
+		t.Fatalf("InstallRelease() error = %v", err)
+	}
+	if res.Checksum != hexSum {
+		t.Errorf("Checksum = %s, want %s", res.Checksum, hexSum)
+	}
+
+	data, err := os.ReadFile(filepath.Join(dest, "duckrow"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "#!binary" {
+		t.Errorf("binary content = %q", data)
+	}
+	info, _ := os.Stat(filepath.Join(dest, "duckrow"))
+	if info.Mode().Perm()&0o100 == 0 {
+		t.Errorf("binary is not executable: %v", info.Mode())
+	}
+}
+
+func TestInstallRelease_ChecksumMismatch(t *testing.T) {
+	archive := buildTarGz(t, map[string]string{"duckrow": "#!binary"})
+	name := ReleaseArchiveName("1.2.3", "linux", "amd64")
+	wrong := strings.Repeat("0", 64)
+
+	tests := []struct {
+		name     string
+		sums     string
+		checksum string
+		wantErr  string
+	}{
+		{"archive does not match checksums.txt", wrong + "  " + name + "\n", wrong, "checksum mismatch"},
+		{"pinned checksum differs", wrong + "  " + name + "\n", strings.Repeat("1", 64), "pinned checksum"},
+		{"archive not listed", wrong + "  other.tar.gz\n", wrong, "not listed"},
+		{"no pinned checksum", wrong + "  " + name + "\n", "", "checksum is required"},
+		{"pinned checksum malformed", wrong + "  " + name + "\n", "3f5a", "not a SHA256"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := newReleaseServer(t, archive, tt.sums)
+			dest := t.TempDir()
+			_, err := InstallRelease(ReleaseInstallOptions{
+				Version: "1.2.3", OS: "linux", Arch: "amd64",
+				DestDir: dest, BaseURL: srv.URL, Checksum: tt.checksum,
+			})
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+				t.Fatalf("InstallRelease() error = %v, want %q", err, tt.wantErr)
+			}
+			if _, err := os.Stat(filepath.Join(dest, "duckrow")); !os.IsNotExist(err) {
+				t.Error("binary should not be installed on verification failure")
+			}
+		})
+	}
+}
+
+func TestInstallRelease_RedirectOutsideAllowlist(t *testing.T) {
+	archive := buildTarGz(t, map[string]string{"duckrow": "#!binary"})
+	sum := sha256.Sum256(archive)
+	hexSum := hex.EncodeToString(sum[:])
+	name := ReleaseArchiveName("1.2.3", "linux", "amd64")
+	target := newReleaseServer(t, archive, hexSum+"  "+name+"\n")
+	// The target is reached as localhost, which is not in the allowlist.
+	redirect := strings.Replace(target.URL, "127.0.0.1", "localhost", 1)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, redirect+r.URL.Path, http.StatusFound)
+	}))
+	t.Cleanup(srv.Close)
+
+	SetAllowedHosts([]string{"127.0.0.1"})
+	t.Cleanup(func() { SetAllowedHosts(nil) })
+
+	dest := t.TempDir()
+	_, err := InstallRelease(ReleaseInstallOptions{
+		Version: "1.2.3", OS: "linux", Arch: "amd64",
+		DestDir: dest, BaseURL: srv.URL, Checksum: hexSum,
+	})
+	if !errors.Is(err, ErrHostNotAllowed) {
+		t.Fatalf("InstallRelease() error = %v, want ErrHostNotAllowed", err)
+	}
+	if _, err := os.Stat(filepath.Join(dest, "duckrow")); !os.IsNotExist(err) {
+		t.Error("binary should not be installed when a redirect is refused")
+	}
+}
+
+func TestInstallRelease_DownloadTimeout(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		time.Sleep(500 * time.Millisecond)
+	}))
+	t.Cleanup(srv.Close)
+
+	SetTimeouts(Timeouts{Download: 50 * time.Millisecond})
+	t.Cleanup(func() { SetTimeouts(Timeouts{}) })
+
+	_, err := InstallRelease(ReleaseInstallOptions{
+		Version: "1.2.3", OS: "linux", Arch: "amd64",
+		DestDir: t.TempDir(), BaseURL: srv.URL, Checksum: strings.Repeat("0", 64),
+	})
+	if err == nil || !strings.Contains(err.Error(), "Timeout") {
+		t.Fatalf("InstallRelease() error = %v, want the download timeout", err)
+	}
+}
+
+func TestReleaseArchiveName(t *testing.T) {
+	if got := ReleaseArchiveName("v0.5.0", "darwin", "arm64"); got != "duckrow_0.5.0_darwin_arm64.tar.gz" {
+		t.Errorf("got %s", got)
+	}
+	if got := ReleaseArchiveName("0.5.0", "windows", "amd64"); got != "duckrow_0.5.0_windows_amd64.zip" {
+		t.Errorf("got %s", got)
+	}
+}
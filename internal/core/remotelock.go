@@ -0,0 +1,89 @@
+package core
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// FetchRemoteLockFile retrieves a lock file without cloning the whole project.
+//
+// An http(s) URL whose path ends in .json is downloaded directly (e.g. a raw
+// file URL). Anything else is treated as a repo source — shorthand, URL, or
+// SSH, optionally with a subpath — which is shallow-cloned so the lock file
+// can be read from the subpath (or the repo root). A subpath ending in .json
+// names the lock file itself.
+func FetchRemoteLockFile(from string, overrides map[string]string) (*LockFile, error) {
+	from = strings.TrimSpace(from)
+	if isRawLockURL(from) {
+		return downloadLockFile(from)
+	}
+
+	source, err := ParseSource(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid lock file source: %w", err)
+	}
+	source.ApplyCloneURLOverride(overrides)
+
+	tmpDir, err := cloneRepo(source.CloneURL, source.Ref, true)
+	if err != nil {
+		return nil, fmt.Errorf("cloning: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	path := filepath.Join(tmpDir, filepath.FromSlash(source.SubPath))
+	if !strings.HasSuffix(source.SubPath, ".json") {
+		path = filepath.Join(path, lockFileName)
+	}
+	lf, err := readLockFileAt(path)
+	if err != nil {
+		return nil, err
+	}
+	if lf == nil {
+		return nil, fmt.Errorf("no lock file found at %s", from)
+	}
+	return lf, nil
+}
+
+// isRawLockURL reports whether from is an http(s) URL pointing at a .json file.
+func isRawLockURL(from string) bool {
+	if !strings.HasPrefix(from, "https://") && !strings.HasPrefix(from, "http://") {
+		return false
+	}
+	u, err := url.Parse(from)
+	if err != nil {
+		return false
+	}
+	return strings.HasSuffix(u.Path, ".json")
+}
+
+// downloadLockFile fetches and parses a lock file over HTTP.
+func downloadLockFile(rawURL string) (*LockFile, error) {
+	if err := checkNetwork(rawURL); err != nil {
+		return nil, err
+	}
+
+	client := &http.Client{Timeout: CurrentTimeouts().Download}
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return nil, fmt.Errorf("downloading lock file: %w", err)
+	}
+	resp, err := doHTTP(client, req)
+	if err != nil {
+		return nil, fmt.Errorf("downloading lock file: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("downloading lock file: %s returned %s", rawURL, resp.Status)
+	}
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, fmt.Errorf("downloading lock file: %w", err)
+	}
+	return parseLockFile(data)
+}
@@ -0,0 +1,58 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestFetchRemoteLockFile_RawURL(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/team/duckrow.lock.json" {
+			http.NotFound(w, r)
+			return
+		}
+		_, _ = w.Write([]byte(`{
+  "lockVersion": 3,
+  "assets": [
+    {"kind": "skill", "name": "go-review", "source": "github.com/acme/skills/go-review", "commit": "abc123"}
+  ]
+}`))
+	}))
+	defer srv.Close()
+
+	lf, err := FetchRemoteLockFile(srv.URL+"/team/duckrow.lock.json", nil)
+	if err != nil {
+		t.Fatalf("FetchRemoteLockFile() error = %v", err)
+	}
+	got := FindLockedAsset(lf, asset.KindSkill, "go-review")
+	if got == nil || got.Commit != "abc123" {
+		t.Errorf("go-review = %+v, want commit abc123", got)
+	}
+	if len(lf.Skills) != 1 {
+		t.Errorf("len(Skills) = %d, want 1 (legacy fields should be populated)", len(lf.Skills))
+	}
+
+	_, err = FetchRemoteLockFile(srv.URL+"/missing.json", nil)
+	if err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("FetchRemoteLockFile(missing) error = %v, want 404", err)
+	}
+}
+
+func TestIsRawLockURL(t *testing.T) {
+	tests := map[string]bool{
+		"https://raw.githubusercontent.com/acme/app/main/duckrow.lock.json": true,
+		"http://example.com/lock.json?token=x":                              true,
+		"https://github.com/acme/app":                                       false,
+		"acme/app":                                                          false,
+		"github.com/acme/app/duckrow.lock.json":                             false,
+	}
+	for in, want := range tests {
+		if got := isRawLockURL(in); got != want {
+			t.Errorf("isRawLockURL(%q) = %v, want %v", in, got, want)
+		}
+	}
+}
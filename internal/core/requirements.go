@@ -0,0 +1,58 @@
+package core
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// RequirementIssue is an installed skill whose required project files
+// (metadata.requires in its SKILL.md) are missing from the project.
+type RequirementIssue struct {
+	Skill   string
+	Missing []string // the patterns that matched nothing
+}
+
+func (i RequirementIssue) String() string {
+	return fmt.Sprintf("%s needs %s", i.Skill, strings.Join(i.Missing, ", "))
+}
+
+// SkillRequires returns the project file patterns a skill declares it needs.
+func SkillRequires(meta asset.Meta) []string {
+	if m, ok := meta.(asset.SkillMeta); ok {
+		return m.Requires
+	}
+	return nil
+}
+
+// MissingRequirements returns the patterns in requires that match no file
+// in projectDir. Patterns are globs relative to the project root, with /
+// as the separator; a malformed pattern counts as missing.
+func MissingRequirements(requires []string, projectDir string) []string {
+	var missing []string
+	for _, pattern := range requires {
+		matches, err := filepath.Glob(filepath.Join(projectDir, filepath.FromSlash(pattern)))
+		if err != nil || len(matches) == 0 {
+			missing = append(missing, pattern)
+		}
+	}
+	return missing
+}
+
+// ScanRequirements checks the skills installed in projectDir against the
+// project files they declare they need.
+func (o *Orchestrator) ScanRequirements(projectDir string) ([]RequirementIssue, error) {
+	installed, err := o.ScanFolder(projectDir)
+	if err != nil {
+		return nil, err
+	}
+	var issues []RequirementIssue
+	for _, s := range installed[asset.KindSkill] {
+		if missing := MissingRequirements(SkillRequires(s.Meta), projectDir); len(missing) > 0 {
+			issues = append(issues, RequirementIssue{Skill: s.Name, Missing: missing})
+		}
+	}
+	return issues, nil
+}
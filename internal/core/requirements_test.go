@@ -0,0 +1,43 @@
+package core
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+func TestMissingRequirements(t *testing.T) {
+	project := t.TempDir()
+	writeSkillFile(t, filepath.Join(project, "go.mod"), "module x")
+	writeSkillFile(t, filepath.Join(project, ".eslintrc.json"), "{}")
+	writeSkillFile(t, filepath.Join(project, "cmd", "app", "main.go"), "package main")
+
+	got := MissingRequirements([]string{"go.mod", ".eslintrc*", "cmd/*/main.go", "Cargo.toml", "[bad"}, project)
+	if want := []string{"Cargo.toml", "[bad"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("MissingRequirements() = %v, want %v", got, want)
+	}
+	if got := MissingRequirements(nil, project); got != nil {
+		t.Errorf("MissingRequirements(nil) = %v, want nil", got)
+	}
+}
+
+func TestScanRequirements(t *testing.T) {
+	project := t.TempDir()
+	writeSkillFile(t, filepath.Join(project, canonicalSkillsDir, "go-review", "SKILL.md"),
+		"---\nname: go-review\ndescription: Reviews Go\nmetadata:\n  requires: [go.mod]\n---\n")
+	writeSkillFile(t, filepath.Join(project, canonicalSkillsDir, "notes", "SKILL.md"),
+		"---\nname: notes\ndescription: Takes notes\n---\n")
+
+	issues, err := NewOrchestrator().ScanRequirements(project)
+	if err != nil {
+		t.Fatalf("ScanRequirements() error = %v", err)
+	}
+	if len(issues) != 1 || issues[0].String() != "go-review needs go.mod" {
+		t.Errorf("ScanRequirements() = %v, want go-review needing go.mod", issues)
+	}
+
+	writeSkillFile(t, filepath.Join(project, "go.mod"), "module x")
+	if issues, _ := NewOrchestrator().ScanRequirements(project); len(issues) != 0 {
+		t.Errorf("ScanRequirements() = %v after adding go.mod, want none", issues)
+	}
+}
@@ -0,0 +1,318 @@
+// Package schema holds the JSON Schemas of duckrow's files and validates
+// files against them, reporting problems with their line and column.
+//
+// The schemas are the published contract for duckrow.json,
+// duckrow.lock.json, and config.json. The validator implements the subset
+// of JSON Schema they use: type, enum, properties, required,
+// additionalProperties (as a schema), items, minimum, minLength, and
+// local $refs.
+package schema
+
+import (
+	"bytes"
+	"embed"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/tailscale/hujson"
+)
+
+//go:embed *.schema.json
+var files embed.FS
+
+// Schema names.
+const (
+	Registry = "registry" // duckrow.json
+	Lock     = "lock"     // duckrow.lock.json and .duckrow/local.lock.json
+	Config   = "config"   // ~/.duckrow/config.json
+)
+
+// fileNames maps the files each schema describes to the schema's name.
+var fileNames = map[string]string{
+	"duckrow.json":      Registry,
+	"duckrow.lock.json": Lock,
+	"local.lock.json":   Lock,
+	"config.json":       Config,
+}
+
+// Names returns the schema names, sorted.
+func Names() []string {
+	return []string{Config, Lock, Registry}
+}
+
+// Resolve returns the schema name for a schema name or a file name such as
+// duckrow.lock.json.
+func Resolve(name string) (string, bool) {
+	for _, n := range Names() {
+		if n == name {
+			return n, true
+		}
+	}
+	n, ok := fileNames[name]
+	return n, ok
+}
+
+// Source returns the JSON text of a schema.
+func Source(name string) ([]byte, error) {
+	n, ok := Resolve(name)
+	if !ok {
+		return nil, fmt.Errorf("unknown schema %q (want one of %s)", name, strings.Join(Names(), ", "))
+	}
+	return files.ReadFile(n + ".schema.json")
+}
+
+// Problem is one way a file does not match its schema.
+type Problem struct {
+	Line, Column int
+	Path         string // e.g. assets[0].kind; empty for the whole file
+	Message      string
+}
+
+func (p Problem) String() string {
+	if p.Path == "" {
+		return fmt.Sprintf("line %d, column %d: %s", p.Line, p.Column, p.Message)
+	}
+	return fmt.Sprintf("line %d, column %d: %s: %s", p.Line, p.Column, p.Path, p.Message)
+}
+
+// Error lists the problems found in a file.
+type Error struct {
+	Problems []Problem
+}
+
+func (e *Error) Error() string {
+	parts := make([]string, len(e.Problems))
+	for i, p := range e.Problems {
+		parts[i] = p.String()
+	}
+	return strings.Join(parts, "; ")
+}
+
+// Validate checks data against the named schema. It returns an *Error
+// listing syntax errors or every schema violation, or nil.
+func Validate(name string, data []byte) error {
+	s, err := load(name)
+	if err != nil {
+		return err
+	}
+
+	// Standard JSON only: hujson would also accept comments and trailing
+	// commas, which encoding/json then rejects.
+	var syntax *json.SyntaxError
+	if err := json.Unmarshal(data, new(any)); errors.As(err, &syntax) {
+		// Offset counts the bytes read, including the offending one.
+		line, col := lineColumn(data, max(int(syntax.Offset)-1, 0))
+		return &Error{Problems: []Problem{{Line: line, Column: col, Message: syntax.Error()}}}
+	} else if err != nil {
+		return &Error{Problems: []Problem{{Line: 1, Column: 1, Message: err.Error()}}}
+	}
+	root, err := hujson.Parse(data)
+	if err != nil {
+		return &Error{Problems: []Problem{{Line: 1, Column: 1, Message: err.Error()}}}
+	}
+
+	v := &validator{data: data, root: s}
+	v.check(s, &root, "")
+	if len(v.problems) == 0 {
+		return nil
+	}
+	sort.SliceStable(v.problems, func(i, j int) bool {
+		a, b := v.problems[i], v.problems[j]
+		if a.Line != b.Line {
+			return a.Line < b.Line
+		}
+		return a.Column < b.Column
+	})
+	return &Error{Problems: v.problems}
+}
+
+// node is the part of a JSON Schema the validator understands.
+type node struct {
+	Ref                  string           `json:"$ref"`
+	Type                 types            `json:"type"`
+	Enum                 []string         `json:"enum"`
+	Properties           map[string]*node `json:"properties"`
+	Required             []string         `json:"required"`
+	AdditionalProperties *node            `json:"additionalProperties"`
+	Items                *node            `json:"items"`
+	Minimum              *float64         `json:"minimum"`
+	MinLength            *int             `json:"minLength"`
+	Defs                 map[string]*node `json:"$defs"`
+}
+
+// types is a schema "type": one name or a list of them.
+type types []string
+
+func (t *types) UnmarshalJSON(b []byte) error {
+	var one string
+	if err := json.Unmarshal(b, &one); err == nil {
+		*t = types{one}
+		return nil
+	}
+	var many []string
+	if err := json.Unmarshal(b, &many); err != nil {
+		return err
+	}
+	*t = many
+	return nil
+}
+
+// load parses an embedded schema.
+func load(name string) (*node, error) {
+	data, err := Source(name)
+	if err != nil {
+		return nil, err
+	}
+	var s node
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("schema %s: %w", name, err)
+	}
+	return &s, nil
+}
+
+type validator struct {
+	data     []byte
+	root     *node
+	problems []Problem
+}
+
+func (v *validator) report(val *hujson.Value, path, format string, args ...any) {
+	line, col := lineColumn(v.data, val.StartOffset)
+	v.problems = append(v.problems, Problem{Line: line, Column: col, Path: path, Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) check(s *node, val *hujson.Value, path string) {
+	if s.Ref != "" {
+		name, ok := strings.CutPrefix(s.Ref, "#/$defs/")
+		def := v.root.Defs[name]
+		if !ok || def == nil {
+			v.report(val, path, "schema error: unresolved $ref %q", s.Ref)
+			return
+		}
+		s = def
+	}
+
+	got := typeOf(val)
+	if len(s.Type) > 0 && !matchesType(s.Type, got) {
+		v.report(val, path, "expected %s, got %s", strings.Join(s.Type, " or "), got)
+		return
+	}
+	if len(s.Enum) > 0 {
+		str, ok := stringValue(val)
+		if !ok || !contains(s.Enum, str) {
+			quoted := make([]string, len(s.Enum))
+			for i, e := range s.Enum {
+				quoted[i] = strconv.Quote(e)
+			}
+			v.report(val, path, "must be one of %s", strings.Join(quoted, ", "))
+			return
+		}
+	}
+
+	switch x := val.Value.(type) {
+	case *hujson.Object:
+		seen := make(map[string]bool, len(x.Members))
+		for i := range x.Members {
+			m := &x.Members[i]
+			key, _ := stringValue(&m.Name)
+			seen[key] = true
+			sub := s.Properties[key]
+			if sub == nil {
+				sub = s.AdditionalProperties
+			}
+			if sub != nil {
+				v.check(sub, &m.Value, joinPath(path, key))
+			}
+		}
+		for _, req := range s.Required {
+			if !seen[req] {
+				v.report(val, path, "missing required field %q", req)
+			}
+		}
+	case *hujson.Array:
+		if s.Items != nil {
+			for i := range x.Elements {
+				v.check(s.Items, &x.Elements[i], fmt.Sprintf("%s[%d]", path, i))
+			}
+		}
+	case hujson.Literal:
+		if s.Minimum != nil && (got == "integer" || got == "number") {
+			if f, err := strconv.ParseFloat(string(x), 64); err == nil && f < *s.Minimum {
+				v.report(val, path, "must be at least %v", *s.Minimum)
+			}
+		}
+		if s.MinLength != nil && got == "string" {
+			if str, _ := stringValue(val); len([]rune(str)) < *s.MinLength {
+				v.report(val, path, "must not be empty")
+			}
+		}
+	}
+}
+
+// typeOf returns the JSON Schema type of a value. Numbers without a
+// fraction or exponent are integers.
+func typeOf(val *hujson.Value) string {
+	switch val.Value.Kind() {
+	case '{':
+		return "object"
+	case '[':
+		return "array"
+	case '"':
+		return "string"
+	case 't', 'f':
+		return "boolean"
+	case 'n':
+		return "null"
+	}
+	if lit, ok := val.Value.(hujson.Literal); ok && !bytes.ContainsAny(lit, ".eE") {
+		return "integer"
+	}
+	return "number"
+}
+
+func matchesType(want types, got string) bool {
+	for _, t := range want {
+		if t == got || (t == "number" && got == "integer") {
+			return true
+		}
+	}
+	return false
+}
+
+func stringValue(val *hujson.Value) (string, bool) {
+	lit, ok := val.Value.(hujson.Literal)
+	if !ok || lit.Kind() != '"' {
+		return "", false
+	}
+	return lit.String(), true
+}
+
+func contains(list []string, s string) bool {
+	for _, x := range list {
+		if x == s {
+			return true
+		}
+	}
+	return false
+}
+
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// lineColumn returns the 1-based line and column of offset n in b.
+func lineColumn(b []byte, n int) (line, column int) {
+	if n > len(b) {
+		n = len(b)
+	}
+	line = 1 + bytes.Count(b[:n], []byte("\n"))
+	column = 1 + n - (bytes.LastIndexByte(b[:n], '\n') + 1)
+	return line, column
+}
@@ -0,0 +1,138 @@
+package schema
+
+import (
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestSchemasParse(t *testing.T) {
+	for _, name := range Names() {
+		data, err := Source(name)
+		if err != nil {
+			t.Fatalf("Source(%q) error = %v", name, err)
+		}
+		if !json.Valid(data) {
+			t.Errorf("schema %q is not valid JSON", name)
+		}
+		if _, err := load(name); err != nil {
+			t.Errorf("load(%q) error = %v", name, err)
+		}
+	}
+}
+
+func TestResolve(t *testing.T) {
+	tests := []struct {
+		in, want string
+		ok       bool
+	}{
+		{"lock", Lock, true},
+		{"duckrow.lock.json", Lock, true},
+		{"local.lock.json", Lock, true},
+		{"duckrow.json", Registry, true},
+		{"config.json", Config, true},
+		{"settings", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := Resolve(tt.in)
+		if got != tt.want || ok != tt.ok {
+			t.Errorf("Resolve(%q) = %q, %v; want %q, %v", tt.in, got, ok, tt.want, tt.ok)
+		}
+	}
+	if _, err := Source("settings"); err == nil {
+		t.Error("Source(settings) expected error")
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tests := []struct {
+		name   string
+		schema string
+		data   string
+		want   []Problem
+	}{
+		{
+			name:   "valid lock",
+			schema: Lock,
+			data: `{
+  "lockVersion": 3,
+  "assets": [
+    {"kind": "skill", "name": "go-review", "source": "github.com/acme/skills/go-review", "commit": "abc1234"},
+    {"kind": "mcp", "name": "db", "data": {"registry": "team", "requiredEnv": ["DB_URL"]}}
+  ]
+}`,
+		},
+		{
+			name:   "legacy lock",
+			schema: Lock,
+			data:   `{"lockVersion": 1, "skills": [{"name": "lint", "source": "acme/skills", "commit": "abc"}]}`,
+		},
+		{
+			name:   "wrong types",
+			schema: Lock,
+			data: `{
+  "lockVersion": "3",
+  "assets": [
+    {"kind": "plugin", "name": "x", "commit": 123}
+  ]
+}`,
+			want: []Problem{
+				{Line: 2, Column: 18, Path: "lockVersion", Message: "expected integer, got string"},
+				{Line: 4, Column: 14, Path: "assets[0].kind", Message: `must be one of "skill", "mcp", "agent", "command", "rule"`},
+				{Line: 4, Column: 47, Path: "assets[0].commit", Message: "expected string, got integer"},
+			},
+		},
+		{
+			name:   "missing required",
+			schema: Lock,
+			data:   `{"assets": [{"kind": "skill"}]}`,
+			want: []Problem{
+				{Line: 1, Column: 13, Path: "assets[0]", Message: `missing required field "name"`},
+			},
+		},
+		{
+			name:   "syntax error",
+			schema: Registry,
+			data:   "{\n  \"name\": \"team\",\n}",
+			want: []Problem{
+				{Line: 3, Column: 1, Message: "invalid character '}' looking for beginning of object key string"},
+			},
+		},
+		{
+			name:   "registry entries",
+			schema: Registry,
+			data:   `{"name": "team", "assets": {"skill": [{"name": "a", "source": "acme/a", "hydrate": "no"}], "widget": []}}`,
+			want: []Problem{
+				{Line: 1, Column: 84, Path: "assets.skill[0].hydrate", Message: "expected boolean, got string"},
+			},
+		},
+		{
+			name:   "config map values and enums",
+			schema: Config,
+			data:   `{"folders": null, "settings": {"cloneURLOverrides": {"a/b": 1}, "installStrategy": "hardlink"}}`,
+			want: []Problem{
+				{Line: 1, Column: 61, Path: "settings.cloneURLOverrides.a/b", Message: "expected string, got integer"},
+				{Line: 1, Column: 84, Path: "settings.installStrategy", Message: `must be one of "symlink", "copy"`},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := Validate(tt.schema, []byte(tt.data))
+			if tt.want == nil {
+				if err != nil {
+					t.Fatalf("Validate() error = %v", err)
+				}
+				return
+			}
+			var schemaErr *Error
+			if !errors.As(err, &schemaErr) {
+				t.Fatalf("Validate() error = %v, want *Error", err)
+			}
+			if !reflect.DeepEqual(schemaErr.Problems, tt.want) {
+				t.Errorf("Validate() problems =\n%+v\nwant\n%+v", schemaErr.Problems, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,139 @@
+package core
+
+import (
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// SearchResult is a registry entry matching a search query.
+type SearchResult struct {
+	Kind         asset.Kind `json:"kind"`
+	Name         string     `json:"name"`
+	Description  string     `json:"description,omitempty"`
+	Tags         []string   `json:"tags,omitempty"`
+	Source       string     `json:"source,omitempty"`
+	RegistryName string     `json:"registry"`
+	RegistryRepo string     `json:"registryRepo"`
+	// Score ranks the match; higher is better.
+	Score int `json:"score"`
+}
+
+// Scores for one query term, by where it matched. A term matching in
+// several places counts the best one.
+const (
+	scoreNameExact    = 100
+	scoreNamePrefix   = 60
+	scoreNameContains = 40
+	scoreTag          = 30
+	scoreTagPrefix    = 20
+	scoreNameFuzzy    = 15
+	scoreDescription  = 10
+)
+
+// SearchAssets returns the registry entries of the given kinds matching
+// query, best matches first. The query is split into words, and every word
+// must match the entry's name, one of its tags, or its description, ignoring
+// case. Names also match fuzzily, with the word's letters in order, e.g.
+// "gorev" finds "go-review". If registryFilter is non-empty, only that
+// registry (matched by name, alias, or repo URL) is searched. Unlike
+// installing, an entry found in several registries is listed once for each.
+func (rm *RegistryManager) SearchAssets(registries []Registry, kinds []asset.Kind, query, registryFilter string) ([]SearchResult, error) {
+	searchRegistries, err := filterRegistries(registries, registryFilter)
+	if err != nil {
+		return nil, err
+	}
+	terms := strings.Fields(strings.ToLower(query))
+
+	var results []SearchResult
+	for _, kind := range kinds {
+		for _, info := range rm.ListAssets(searchRegistries, kind) {
+			score := searchScore(info.Entry, terms)
+			if score == 0 {
+				continue
+			}
+			results = append(results, SearchResult{
+				Kind:         kind,
+				Name:         info.Entry.Name,
+				Description:  info.Entry.Description,
+				Tags:         info.Entry.Tags,
+				Source:       info.Entry.Source,
+				RegistryName: info.RegistryName,
+				RegistryRepo: info.RegistryRepo,
+				Score:        score,
+			})
+		}
+	}
+
+	kindOrder := asset.Kinds()
+	sort.SliceStable(results, func(i, j int) bool {
+		a, b := results[i], results[j]
+		if a.Score != b.Score {
+			return a.Score > b.Score
+		}
+		if a.Kind != b.Kind {
+			return slices.Index(kindOrder, a.Kind) < slices.Index(kindOrder, b.Kind)
+		}
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		return a.RegistryName < b.RegistryName
+	})
+	return results, nil
+}
+
+// searchScore returns how well e matches the lowercase terms, or 0 if some
+// term doesn't match. No terms match nothing.
+func searchScore(e asset.RegistryEntry, terms []string) int {
+	if len(terms) == 0 {
+		return 0
+	}
+	name := strings.ToLower(e.Name)
+	description := strings.ToLower(e.Description)
+
+	total := 0
+	for _, term := range terms {
+		best := 0
+		switch {
+		case name == term:
+			best = scoreNameExact
+		case strings.HasPrefix(name, term):
+			best = scoreNamePrefix
+		case strings.Contains(name, term):
+			best = scoreNameContains
+		}
+		for _, tag := range e.Tags {
+			tag = strings.ToLower(tag)
+			if tag == term {
+				best = max(best, scoreTag)
+			} else if strings.HasPrefix(tag, term) {
+				best = max(best, scoreTagPrefix)
+			}
+		}
+		if best == 0 && fuzzyMatch(name, term) {
+			best = scoreNameFuzzy
+		}
+		if best == 0 && strings.Contains(description, term) {
+			best = scoreDescription
+		}
+		if best == 0 {
+			return 0
+		}
+		total += best
+	}
+	return total
+}
+
+// fuzzyMatch reports whether the letters of term appear in s in order.
+func fuzzyMatch(s, term string) bool {
+	for _, r := range term {
+		i := strings.IndexRune(s, r)
+		if i < 0 {
+			return false
+		}
+		s = s[i+len(string(r)):]
+	}
+	return true
+}
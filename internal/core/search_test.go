@@ -0,0 +1,80 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestRegistryManager_SearchAssets(t *testing.T) {
+	registriesDir := t.TempDir()
+	rm := NewRegistryManager(registriesDir)
+
+	repoA := "git@example.com:org-a/skills.git"
+	repoB := "git@example.com:org-b/skills.git"
+	createTestRegistryClone(t, registriesDir, repoA, RegistryManifest{
+		Name: "org-a",
+		Skills: skillEntriesToRaw([]testSkillEntry{
+			{Name: "go-review", Source: "org-a/go-review", Description: "Review Go code", Tags: []string{"go"}},
+			{Name: "py-review", Source: "org-a/py-review", Description: "Review Python code"},
+			{Name: "css-lint", Source: "org-a/css-lint", Tags: []string{"frontend"}},
+		}),
+		MCPs: mcpEntriesToRaw([]testMCPEntry{
+			{Name: "review-db", Command: "dbtool"},
+		}),
+	})
+	createTestRegistryClone(t, registriesDir, repoB, RegistryManifest{
+		Name: "org-b",
+		Skills: skillEntriesToRaw([]testSkillEntry{
+			{Name: "go-review", Source: "org-b/go-review"},
+		}),
+	})
+	registries := []Registry{
+		{Name: "org-a", Repo: repoA},
+		{Name: "org-b", Repo: repoB},
+	}
+
+	search := func(t *testing.T, kinds []asset.Kind, query, registry string) []string {
+		t.Helper()
+		results, err := rm.SearchAssets(registries, kinds, query, registry)
+		if err != nil {
+			t.Fatalf("SearchAssets() error = %v", err)
+		}
+		var got []string
+		for _, r := range results {
+			got = append(got, string(r.Kind)+":"+r.Name+"@"+r.RegistryName)
+		}
+		return got
+	}
+
+	tests := []struct {
+		name     string
+		kinds    []asset.Kind
+		query    string
+		registry string
+		want     []string
+	}{
+		{"name prefix before contains", asset.Kinds(), "review", "",
+			[]string{"mcp:review-db@org-a", "skill:go-review@org-a", "skill:go-review@org-b", "skill:py-review@org-a"}},
+		{"kind filter", []asset.Kind{asset.KindSkill}, "review", "org-b", []string{"skill:go-review@org-b"}},
+		{"description", asset.Kinds(), "python", "", []string{"skill:py-review@org-a"}},
+		{"tag", asset.Kinds(), "front", "", []string{"skill:css-lint@org-a"}},
+		{"fuzzy name", asset.Kinds(), "gorev", "", []string{"skill:go-review@org-a", "skill:go-review@org-b"}},
+		{"every word must match", asset.Kinds(), "review GO", "", []string{"skill:go-review@org-a", "skill:go-review@org-b"}},
+		{"no match", asset.Kinds(), "rust", "", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := search(t, tt.kinds, tt.query, tt.registry); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SearchAssets(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	t.Run("unknown registry", func(t *testing.T) {
+		if _, err := rm.SearchAssets(registries, asset.Kinds(), "review", "nope"); err == nil {
+			t.Error("expected error for unknown registry")
+		}
+	})
+}
@@ -0,0 +1,338 @@
+package core
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// versionKey and constraintKey are the lock data fields recording the
+// version a skill was installed at and the constraint it was asked for.
+const (
+	versionKey    = "version"
+	constraintKey = "constraint"
+)
+
+// Version is a semantic version, MAJOR.MINOR.PATCH with an optional
+// pre-release such as "rc.1". Build metadata is dropped.
+type Version struct {
+	Major, Minor, Patch int
+	Pre                 string
+}
+
+// ParseVersion parses a semantic version. A leading "v" is allowed, as in
+// git tags, and missing minor and patch numbers count as zero, so "v1.2"
+// is 1.2.0.
+func ParseVersion(s string) (Version, error) {
+	v, _, err := parseVersion(s)
+	return v, err
+}
+
+// parseVersion is ParseVersion, also returning how many of the major,
+// minor, and patch numbers s gives.
+func parseVersion(s string) (Version, int, error) {
+	var v Version
+	str := strings.TrimPrefix(strings.TrimSpace(s), "v")
+	str, _, _ = strings.Cut(str, "+")
+	str, v.Pre, _ = strings.Cut(str, "-")
+	parts := strings.Split(str, ".")
+	if str == "" || len(parts) > 3 {
+		return Version{}, 0, fmt.Errorf("invalid version %q", s)
+	}
+	nums := []*int{&v.Major, &v.Minor, &v.Patch}
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 {
+			return Version{}, 0, fmt.Errorf("invalid version %q", s)
+		}
+		*nums[i] = n
+	}
+	return v, len(parts), nil
+}
+
+// String formats the version without a "v" prefix, e.g. "1.2.0-rc.1".
+func (v Version) String() string {
+	s := fmt.Sprintf("%d.%d.%d", v.Major, v.Minor, v.Patch)
+	if v.Pre != "" {
+		s += "-" + v.Pre
+	}
+	return s
+}
+
+// Compare returns -1, 0, or 1 as v is lower than, equal to, or higher than
+// w. A pre-release is lower than its release; pre-releases compare as
+// semver orders them, identifier by identifier (see comparePre).
+func (v Version) Compare(w Version) int {
+	for _, d := range []int{v.Major - w.Major, v.Minor - w.Minor, v.Patch - w.Patch} {
+		if d != 0 {
+			if d < 0 {
+				return -1
+			}
+			return 1
+		}
+	}
+	switch {
+	case v.Pre == w.Pre:
+		return 0
+	case v.Pre == "":
+		return 1
+	case w.Pre == "":
+		return -1
+	}
+	return comparePre(v.Pre, w.Pre)
+}
+
+// comparePre compares two pre-releases by their dot-separated identifiers,
+// left to right: numeric identifiers compare as numbers and are lower than
+// alphanumeric ones, which compare as strings. When all identifiers are
+// equal, the pre-release with fewer is lower, so rc.2 < rc.10 and
+// alpha < alpha.1 < alpha.beta.
+func comparePre(a, b string) int {
+	as, bs := strings.Split(a, "."), strings.Split(b, ".")
+	for i := 0; i < len(as) && i < len(bs); i++ {
+		an, aErr := strconv.ParseUint(as[i], 10, 64)
+		bn, bErr := strconv.ParseUint(bs[i], 10, 64)
+		switch {
+		case aErr == nil && bErr == nil:
+			if an != bn {
+				if an < bn {
+					return -1
+				}
+				return 1
+			}
+		case aErr == nil:
+			return -1
+		case bErr == nil:
+			return 1
+		default:
+			if c := strings.Compare(as[i], bs[i]); c != 0 {
+				return c
+			}
+		}
+	}
+	switch {
+	case len(as) < len(bs):
+		return -1
+	case len(as) > len(bs):
+		return 1
+	}
+	return 0
+}
+
+// UpdateLevel names the part of the version that changes from v to w:
+// "major", "minor", "patch", or "" when w is not higher than v. A change
+// of pre-release only is a patch.
+func (v Version) UpdateLevel(w Version) string {
+	switch {
+	case v.Compare(w) >= 0:
+		return ""
+	case w.Major != v.Major:
+		return "major"
+	case w.Minor != v.Minor:
+		return "minor"
+	}
+	return "patch"
+}
+
+// VersionConstraint is a set of versions, such as "^1.2", "~1.2.3",
+// ">=1.0 <2.0", or "1.2.3". The empty constraint, "*", and "latest" allow
+// every release.
+type VersionConstraint struct {
+	raw    string
+	bounds []versionBound
+}
+
+type versionBound struct {
+	op string // one of =, >, >=, <, <=
+	v  Version
+}
+
+// ParseVersionConstraint parses a constraint: space-separated bounds that
+// must all hold, each an operator (=, >, >=, <, <=, ^, ~) and a version.
+// A bare version is an exact match.
+//
+// "^" allows changes that leave the first non-zero number given alone:
+// "^1.2.3" is <2.0.0, "^0.2.3" is <0.3.0, and "^0.0.3" is <0.0.4. When
+// every number given is zero, the last one may change: "^0.0" is <0.1.0
+// and "^0" is <1.0.0. "~" allows patch changes when a minor number is
+// given and minor changes otherwise: "~1.2.3" and "~1.2" are <1.3.0, "~1"
+// is <2.0.0.
+func ParseVersionConstraint(s string) (VersionConstraint, error) {
+	c := VersionConstraint{raw: strings.TrimSpace(s)}
+	if c.raw == "" || c.raw == "*" || c.raw == "latest" {
+		return c, nil
+	}
+	for _, field := range strings.Fields(c.raw) {
+		rest := strings.TrimLeft(field, "=<>^~")
+		op := field[:len(field)-len(rest)]
+		v, given, err := parseVersion(rest)
+		if err != nil {
+			return VersionConstraint{}, fmt.Errorf("invalid version constraint %q: %w", s, err)
+		}
+		switch op {
+		case "", "=":
+			c.bounds = append(c.bounds, versionBound{"=", v})
+		case ">", ">=", "<", "<=":
+			c.bounds = append(c.bounds, versionBound{op, v})
+		case "^":
+			c.bounds = append(c.bounds, versionBound{">=", v}, versionBound{"<", caretUpper(v, given)})
+		case "~":
+			upper := Version{Major: v.Major + 1}
+			if given >= 2 {
+				upper = Version{Major: v.Major, Minor: v.Minor + 1}
+			}
+			c.bounds = append(c.bounds, versionBound{">=", v}, versionBound{"<", upper})
+		default:
+			return VersionConstraint{}, fmt.Errorf("invalid version constraint %q: unknown operator %q", s, op)
+		}
+	}
+	return c, nil
+}
+
+// caretUpper returns the exclusive upper bound of "^v", where given is
+// how many of v's major, minor, and patch numbers were written.
+func caretUpper(v Version, given int) Version {
+	switch {
+	case v.Major > 0 || given == 1:
+		return Version{Major: v.Major + 1}
+	case v.Minor > 0 || given == 2:
+		return Version{Minor: v.Minor + 1}
+	}
+	return Version{Patch: v.Patch + 1}
+}
+
+// String returns the constraint as written.
+func (c VersionConstraint) String() string { return c.raw }
+
+// Allows reports whether v satisfies the constraint. Pre-releases are only
+// allowed when asked for exactly.
+func (c VersionConstraint) Allows(v Version) bool {
+	if v.Pre != "" && !(len(c.bounds) == 1 && c.bounds[0].op == "=") {
+		return false
+	}
+	for _, b := range c.bounds {
+		cmp := v.Compare(b.v)
+		ok := false
+		switch b.op {
+		case "=":
+			ok = cmp == 0
+		case ">":
+			ok = cmp > 0
+		case ">=":
+			ok = cmp >= 0
+		case "<":
+			ok = cmp < 0
+		case "<=":
+			ok = cmp <= 0
+		}
+		if !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// Latest returns the highest version tag the constraint allows.
+func (c VersionConstraint) Latest(tags []VersionTag) (VersionTag, bool) {
+	for _, t := range tags {
+		if c.Allows(t.Version) {
+			return t, true
+		}
+	}
+	return VersionTag{}, false
+}
+
+// VersionTag is a git tag naming a version of an asset.
+type VersionTag struct {
+	Version Version
+	Tag     string
+	Commit  string
+}
+
+// VersionTags picks the version tags of the named asset out of a
+// repository's tags (tag name to commit), highest version first. Tags
+// such as "go-review/v1.2.0" version one asset of a repository holding
+// several; when there are none for the asset, plain "v1.2.0" and "1.2.0"
+// tags version the whole repository.
+func VersionTags(tags map[string]string, name string) []VersionTag {
+	var prefixed, plain []VersionTag
+	for tag, commit := range tags {
+		rest, isPrefixed := strings.CutPrefix(tag, name+"/")
+		if strings.Contains(rest, "/") {
+			continue
+		}
+		v, err := ParseVersion(rest)
+		if err != nil {
+			continue
+		}
+		vt := VersionTag{Version: v, Tag: tag, Commit: commit}
+		if isPrefixed {
+			prefixed = append(prefixed, vt)
+		} else {
+			plain = append(plain, vt)
+		}
+	}
+	if len(prefixed) > 0 {
+		plain = prefixed
+	}
+	sort.Slice(plain, func(i, j int) bool {
+		if c := plain[i].Version.Compare(plain[j].Version); c != 0 {
+			return c > 0
+		}
+		return plain[i].Tag < plain[j].Tag
+	})
+	return plain
+}
+
+// ResolveSkillVersion resolves a version constraint for a registry skill to
+// a version and the commit to install. The version tags of the skill's
+// repository are tried first; with no tag matching, the registry entry's
+// own version and commit are used if the constraint allows that version.
+// The commit is "" when the entry's version matches but it pins no commit.
+func ResolveSkillVersion(source *ParsedSource, entry asset.RegistryEntry, constraint VersionConstraint) (version, commit string, err error) {
+	tags, listErr := lsRemoteTags(source.CloneURL)
+	var available []VersionTag
+	if listErr == nil {
+		available = VersionTags(tags, entry.Name)
+		if vt, ok := constraint.Latest(available); ok {
+			return vt.Version.String(), vt.Commit, nil
+		}
+	}
+	if entry.Version != "" {
+		if v, err := ParseVersion(entry.Version); err == nil && constraint.Allows(v) {
+			return v.String(), entry.Commit, nil
+		}
+	}
+	if listErr != nil {
+		return "", "", fmt.Errorf("listing versions of %s: %w", entry.Name, listErr)
+	}
+
+	var versions []string
+	for _, t := range available {
+		versions = append(versions, t.Version.String())
+	}
+	if entry.Version != "" && len(versions) == 0 {
+		versions = append(versions, entry.Version)
+	}
+	if len(versions) == 0 {
+		return "", "", fmt.Errorf("no version of %s matches %s: it has no version tags and the registry gives no version", entry.Name, constraint)
+	}
+	return "", "", fmt.Errorf("no version of %s matches %s (available: %s)", entry.Name, constraint, strings.Join(versions, ", "))
+}
+
+// LockedVersion returns the version a locked asset was installed at, or ""
+// if it wasn't installed by version.
+func LockedVersion(locked asset.LockedAsset) string {
+	s, _ := locked.Data[versionKey].(string)
+	return s
+}
+
+// LockedVersionConstraint returns the version constraint a locked asset was
+// installed with, or "" if none was given.
+func LockedVersionConstraint(locked asset.LockedAsset) string {
+	s, _ := locked.Data[constraintKey].(string)
+	return s
+}
@@ -0,0 +1,153 @@
+package core
+
+import (
+	"testing"
+)
+
+func TestParseVersion(t *testing.T) {
+	tests := []struct {
+		in   string
+		want string
+		err  bool
+	}{
+		{"1.2.3", "1.2.3", false},
+		{"v1.2.3", "1.2.3", false},
+		{"1.2", "1.2.0", false},
+		{"v2", "2.0.0", false},
+		{"1.0.0-rc.1", "1.0.0-rc.1", false},
+		{"1.0.0+build.5", "1.0.0", false},
+		{"", "", true},
+		{"1.2.3.4", "", true},
+		{"1.x", "", true},
+		{"release", "", true},
+	}
+	for _, tt := range tests {
+		v, err := ParseVersion(tt.in)
+		if (err != nil) != tt.err {
+			t.Errorf("ParseVersion(%q) error = %v, want error %v", tt.in, err, tt.err)
+			continue
+		}
+		if err == nil && v.String() != tt.want {
+			t.Errorf("ParseVersion(%q) = %s, want %s", tt.in, v, tt.want)
+		}
+	}
+}
+
+func TestVersion_CompareAndUpdateLevel(t *testing.T) {
+	tests := []struct {
+		a, b  string
+		cmp   int
+		level string
+	}{
+		{"1.2.3", "1.2.3", 0, ""},
+		{"1.2.3", "1.2.4", -1, "patch"},
+		{"1.2.3", "1.3.0", -1, "minor"},
+		{"1.2.3", "2.0.0", -1, "major"},
+		{"2.0.0", "1.9.9", 1, ""},
+		{"1.0.0-rc.1", "1.0.0", -1, "patch"},
+		{"1.0.0-beta", "1.0.0-rc.1", -1, "patch"},
+		{"1.0.0-rc.2", "1.0.0-rc.10", -1, "patch"},
+		{"1.0.0-rc.10", "1.0.0-rc.2", 1, ""},
+		{"1.0.0-alpha", "1.0.0-alpha.1", -1, "patch"},
+		{"1.0.0-alpha.1", "1.0.0-alpha.beta", -1, "patch"},
+		{"1.0.0-alpha.beta", "1.0.0-beta", -1, "patch"},
+		{"1.0.0-beta.11", "1.0.0-rc.1", -1, "patch"},
+		{"1.0.0-1", "1.0.0-alpha", -1, "patch"},
+	}
+	for _, tt := range tests {
+		a, b := mustVersion(t, tt.a), mustVersion(t, tt.b)
+		if got := a.Compare(b); got != tt.cmp {
+			t.Errorf("%s.Compare(%s) = %d, want %d", tt.a, tt.b, got, tt.cmp)
+		}
+		if got := a.UpdateLevel(b); got != tt.level {
+			t.Errorf("%s.UpdateLevel(%s) = %q, want %q", tt.a, tt.b, got, tt.level)
+		}
+	}
+}
+
+func TestVersionConstraint_Allows(t *testing.T) {
+	tests := []struct {
+		constraint string
+		allowed    []string
+		rejected   []string
+	}{
+		{"", []string{"0.1.0", "3.0.0"}, []string{"1.0.0-rc.1"}},
+		{"*", []string{"1.0.0"}, nil},
+		{"^1.2", []string{"1.2.0", "1.9.3"}, []string{"1.1.9", "2.0.0"}},
+		{"^0.3.1", []string{"0.3.1", "0.3.9"}, []string{"0.4.0", "0.3.0"}},
+		{"^0.0.3", []string{"0.0.3"}, []string{"0.0.4", "0.1.0"}},
+		{"^0.0", []string{"0.0.0", "0.0.9"}, []string{"0.1.0"}},
+		{"^0", []string{"0.0.1", "0.9.0"}, []string{"1.0.0"}},
+		{"^1", []string{"1.0.0", "1.9.9"}, []string{"2.0.0"}},
+		{"~1.2.3", []string{"1.2.3", "1.2.9"}, []string{"1.3.0", "1.2.2"}},
+		{"~1.2", []string{"1.2.0", "1.2.9"}, []string{"1.3.0"}},
+		{"~1", []string{"1.0.0", "1.9.0"}, []string{"2.0.0", "0.9.0"}},
+		{"~0.2.3", []string{"0.2.3", "0.2.9"}, []string{"0.3.0"}},
+		{">=1.0 <2.0", []string{"1.0.0", "1.99.0"}, []string{"0.9.0", "2.0.0"}},
+		{"1.2.3", []string{"1.2.3"}, []string{"1.2.4"}},
+		{"v1.2", []string{"1.2.0"}, []string{"1.2.1"}},
+		{"1.0.0-rc.1", []string{"1.0.0-rc.1"}, []string{"1.0.0"}},
+	}
+	for _, tt := range tests {
+		c, err := ParseVersionConstraint(tt.constraint)
+		if err != nil {
+			t.Fatalf("ParseVersionConstraint(%q): %v", tt.constraint, err)
+		}
+		for _, v := range tt.allowed {
+			if !c.Allows(mustVersion(t, v)) {
+				t.Errorf("%q should allow %s", tt.constraint, v)
+			}
+		}
+		for _, v := range tt.rejected {
+			if c.Allows(mustVersion(t, v)) {
+				t.Errorf("%q should not allow %s", tt.constraint, v)
+			}
+		}
+	}
+
+	for _, bad := range []string{"^x", "!=1.0", ">=1.0 <two"} {
+		if _, err := ParseVersionConstraint(bad); err == nil {
+			t.Errorf("ParseVersionConstraint(%q) succeeded, want error", bad)
+		}
+	}
+}
+
+func TestVersionTags(t *testing.T) {
+	tags := map[string]string{
+		"v1.0.0":             "a",
+		"v1.2.0":             "b",
+		"v2.0.0":             "c",
+		"release":            "d",
+		"go-review/v1.1.0":   "e",
+		"go-review/v1.3.0":   "f",
+		"other-skill/v9.0.0": "g",
+	}
+
+	got := VersionTags(tags, "go-review")
+	if len(got) != 2 || got[0].Tag != "go-review/v1.3.0" || got[1].Tag != "go-review/v1.1.0" {
+		t.Errorf("VersionTags(go-review) = %+v, want its own tags, highest first", got)
+	}
+
+	got = VersionTags(tags, "sql-style")
+	var names []string
+	for _, vt := range got {
+		names = append(names, vt.Tag)
+	}
+	if len(got) != 3 || got[0].Tag != "v2.0.0" || got[2].Tag != "v1.0.0" {
+		t.Errorf("VersionTags(sql-style) = %v, want the repository's plain tags, highest first", names)
+	}
+
+	c, _ := ParseVersionConstraint("^1")
+	if vt, ok := c.Latest(got); !ok || vt.Commit != "b" {
+		t.Errorf("Latest(^1) = %+v, %v; want v1.2.0", vt, ok)
+	}
+}
+
+func mustVersion(t *testing.T, s string) Version {
+	t.Helper()
+	v, err := ParseVersion(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return v
+}
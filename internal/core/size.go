@@ -0,0 +1,137 @@
+package core
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Default thresholds above which installing a skill requires confirmation.
+const (
+	DefaultMaxSkillSizeMB = 10
+	DefaultMaxSkillFiles  = 500
+)
+
+// SkillSize is the on-disk footprint of a skill.
+type SkillSize struct {
+	Bytes int64
+	Files int
+}
+
+// String formats the size for display, e.g. "1.2 MB, 14 files".
+func (s SkillSize) String() string {
+	files := "files"
+	if s.Files == 1 {
+		files = "file"
+	}
+	return fmt.Sprintf("%s, %d %s", FormatBytes(s.Bytes), s.Files, files)
+}
+
+// FormatBytes renders a byte count using binary units.
+func FormatBytes(n int64) string {
+	const unit = 1024
+	if n < unit {
+		return fmt.Sprintf("%d B", n)
+	}
+	div, exp := int64(unit), 0
+	for m := n / unit; m >= unit; m /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(n)/float64(div), "KMGTPE"[exp])
+}
+
+// SizeLimits are the thresholds checked before a skill is installed.
+// A zero field disables that check.
+type SizeLimits struct {
+	MaxBytes int64
+	MaxFiles int
+}
+
+// String formats the limits for display, e.g. "10.0 MB, 500 files".
+func (l SizeLimits) String() string {
+	var parts []string
+	if l.MaxBytes > 0 {
+		parts = append(parts, FormatBytes(l.MaxBytes))
+	}
+	if l.MaxFiles > 0 {
+		parts = append(parts, fmt.Sprintf("%d files", l.MaxFiles))
+	}
+	return strings.Join(parts, ", ")
+}
+
+// Exceeded reports whether size is over either threshold.
+func (l SizeLimits) Exceeded(size SkillSize) bool {
+	return (l.MaxBytes > 0 && size.Bytes > l.MaxBytes) ||
+		(l.MaxFiles > 0 && size.Files > l.MaxFiles)
+}
+
+// SkillSizeLimits returns the configured size thresholds. Unset values fall
+// back to the defaults; negative values disable the check.
+func (s Settings) SkillSizeLimits() SizeLimits {
+	mb := s.MaxSkillSizeMB
+	if mb == 0 {
+		mb = DefaultMaxSkillSizeMB
+	}
+	files := s.MaxSkillFiles
+	if files == 0 {
+		files = DefaultMaxSkillFiles
+	}
+	var limits SizeLimits
+	if mb > 0 {
+		limits.MaxBytes = int64(mb) << 20
+	}
+	if files > 0 {
+		limits.MaxFiles = files
+	}
+	return limits
+}
+
+// LargeSkillError is returned when a skill exceeds the size limits and the
+// install was not confirmed.
+type LargeSkillError struct {
+	Name   string
+	Size   SkillSize
+	Limits SizeLimits
+}
+
+func (e *LargeSkillError) Error() string {
+	return fmt.Sprintf("skill %q is too large (%s; limit %s)", e.Name, e.Size, e.Limits)
+}
+
+// measureSkill computes the size of the files that would be copied from src,
+// applying the same exclusion rules as the copy itself.
+func measureSkill(src string, ignore *IgnoreMatcher) (SkillSize, error) {
+	var size SkillSize
+	err := walkSkillFiles(src, ignore, func(rel string, isDir bool) error {
+		if isDir {
+			return nil
+		}
+		info, err := os.Stat(filepath.Join(src, rel))
+		if err != nil {
+			return err
+		}
+		size.Bytes += info.Size()
+		size.Files++
+		return nil
+	})
+	return size, err
+}
+
+// DirSize returns the total size and file count of an installed asset
+// directory, such as a skill's canonical copy.
+func DirSize(dir string) (SkillSize, error) {
+	var size SkillSize
+	err := filepath.Walk(dir, func(_ string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			size.Bytes += info.Size()
+			size.Files++
+		}
+		return nil
+	})
+	return size, err
+}
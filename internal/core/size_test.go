@@ -0,0 +1,91 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSettings_SkillSizeLimits(t *testing.T) {
+	tests := []struct {
+		name     string
+		settings Settings
+		want     SizeLimits
+	}{
+		{"defaults", Settings{}, SizeLimits{MaxBytes: DefaultMaxSkillSizeMB << 20, MaxFiles: DefaultMaxSkillFiles}},
+		{"custom", Settings{MaxSkillSizeMB: 1, MaxSkillFiles: 20}, SizeLimits{MaxBytes: 1 << 20, MaxFiles: 20}},
+		{"disabled", Settings{MaxSkillSizeMB: -1, MaxSkillFiles: -1}, SizeLimits{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.settings.SkillSizeLimits(); got != tt.want {
+				t.Errorf("SkillSizeLimits() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSizeLimits_Exceeded(t *testing.T) {
+	limits := SizeLimits{MaxBytes: 100, MaxFiles: 2}
+	tests := []struct {
+		size SkillSize
+		want bool
+	}{
+		{SkillSize{Bytes: 100, Files: 2}, false},
+		{SkillSize{Bytes: 101, Files: 1}, true},
+		{SkillSize{Bytes: 10, Files: 3}, true},
+	}
+	for _, tt := range tests {
+		if got := limits.Exceeded(tt.size); got != tt.want {
+			t.Errorf("Exceeded(%+v) = %v, want %v", tt.size, got, tt.want)
+		}
+	}
+	if (SizeLimits{}).Exceeded(SkillSize{Bytes: 1 << 40, Files: 1 << 20}) {
+		t.Error("zero limits should never be exceeded")
+	}
+}
+
+func TestFormatBytes(t *testing.T) {
+	tests := map[int64]string{
+		0:           "0 B",
+		1023:        "1023 B",
+		1536:        "1.5 KB",
+		10 << 20:    "10.0 MB",
+		3 << 30 / 2: "1.5 GB",
+	}
+	for n, want := range tests {
+		if got := FormatBytes(n); got != want {
+			t.Errorf("FormatBytes(%d) = %q, want %q", n, got, want)
+		}
+	}
+}
+
+func TestMeasureSkill_RespectsIgnore(t *testing.T) {
+	src := t.TempDir()
+	for path, content := range map[string]string{
+		"SKILL.md":       "12345",
+		"media/demo.mp4": "0123456789",
+		"README.md":      "excluded",
+		ignoreFileName:   "media/\n",
+	} {
+		p := filepath.Join(src, path)
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	ignore, err := LoadIgnoreMatcher(src, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	size, err := measureSkill(src, ignore)
+	if err != nil {
+		t.Fatalf("measureSkill() error = %v", err)
+	}
+	if size != (SkillSize{Bytes: 5, Files: 1}) {
+		t.Errorf("measureSkill() = %+v, want 5 bytes in 1 file", size)
+	}
+}
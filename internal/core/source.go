@@ -13,6 +13,10 @@ var ownerRepoPattern = regexp.MustCompile(`^[a-zA-Z0-9_.-]+/[a-zA-Z0-9_.-]+$`)
 // ownerRepoPathPattern matches "owner/repo/path/to/skill" format (3+ segments).
 var ownerRepoPathPattern = regexp.MustCompile(`^([a-zA-Z0-9_.-]+)/([a-zA-Z0-9_.-]+)/(.+)$`)
 
+// canonicalSourcePattern matches "host[:port]/owner/repo[/path]". The host
+// must contain a dot to tell it apart from an owner.
+var canonicalSourcePattern = regexp.MustCompile(`^([a-zA-Z0-9][a-zA-Z0-9_.-]*\.[a-zA-Z0-9_.-]*(?::[0-9]+)?)/([a-zA-Z0-9_.-]+)/([^/]+)(?:/(.+))?$`)
+
 // ParseSource parses a skill source string into a structured ParsedSource.
 //
 // Supported formats:
@@ -25,8 +29,23 @@ var ownerRepoPathPattern = regexp.MustCompile(`^([a-zA-Z0-9_.-]+)/([a-zA-Z0-9_.-
 //   - "https://gitlab.com/owner/repo"     → GitLab HTTPS URL
 //   - "https://git.example.com/owner/repo" → Any git host HTTPS URL
 //
-// Local paths (./foo, ../foo, /foo, ~/foo) are explicitly rejected.
+// Local paths (./foo, ../foo, /foo, ~/foo) are explicitly rejected, as are
+// subpaths that point outside the repository.
 func ParseSource(input string) (*ParsedSource, error) {
+	ps, err := parseSource(input)
+	if err != nil {
+		return nil, err
+	}
+	if escapesRepo(ps.SubPath) {
+		return nil, fmt.Errorf("invalid source %q: path %q points outside the repository", input, ps.SubPath)
+	}
+	if ps.SubPath == "." {
+		ps.SubPath = ""
+	}
+	return ps, nil
+}
+
+func parseSource(input string) (*ParsedSource, error) {
 	input = strings.TrimSpace(input)
 	if input == "" {
 		return nil, fmt.Errorf("empty source")
@@ -65,29 +84,17 @@ func ParseSource(input string) (*ParsedSource, error) {
 		}
 	}
 
-	// Canonical source: host/owner/repo[/path/to/skill]
+	// Canonical source: host[:port]/owner/repo[/path/to/skill]
 	// Detected when the first segment contains a dot (hostname indicator).
-	if m := ownerRepoPathPattern.FindStringSubmatch(input); m != nil && strings.Contains(m[1], ".") {
-		host := m[1]
-		// Remaining is owner/repo[/subpath] — split further.
-		rest := m[2] + "/" + m[3] // rejoin segments after host
-		restParts := strings.SplitN(rest, "/", 3)
-		if len(restParts) < 2 {
-			return nil, fmt.Errorf("canonical source %q must have at least host/owner/repo", input)
-		}
-		owner := restParts[0]
-		repo := restParts[1]
-		var subPath string
-		if len(restParts) == 3 {
-			subPath = restParts[2]
-		}
+	if m := canonicalSourcePattern.FindStringSubmatch(input); m != nil {
+		host, owner, repo := m[1], m[2], m[3]
 		return &ParsedSource{
 			Type:     SourceTypeGit,
 			Host:     host,
 			Owner:    owner,
 			Repo:     repo,
 			CloneURL: fmt.Sprintf("https://%s/%s/%s.git", host, owner, repo),
-			SubPath:  subPath,
+			SubPath:  m[4],
 		}, nil
 	}
 
@@ -137,7 +144,7 @@ func parseSSHSource(input string) (*ParsedSource, error) {
 		CloneURL: input,
 	}
 
-	if len(segments) == 2 {
+	if len(segments) == 2 && segments[0] != "" && segments[1] != "" {
 		result.Owner = segments[0]
 		result.Repo = segments[1]
 	}
@@ -156,20 +163,89 @@ func (ps *ParsedSource) RepoKey() string {
 }
 
 // ApplyCloneURLOverride replaces CloneURL with the override value if one
-// exists for this source's RepoKey. Returns true if an override was applied.
+// exists for this source's repository (see LookupCloneURLOverride). Returns
+// true if an override was applied.
 func (ps *ParsedSource) ApplyCloneURLOverride(overrides map[string]string) bool {
-	if len(overrides) == 0 {
+	override, ok := LookupCloneURLOverride(overrides, ps.Host, ps.Owner, ps.Repo)
+	if !ok {
 		return false
 	}
-	key := ps.RepoKey()
-	if key == "" {
-		return false
+	ps.CloneURL = override
+	return true
+}
+
+// ApplyMirror points CloneURL at mirror, a registry entry's cloneUrl for
+// this source ("" for none), unless a clone URL override applies: the
+// user's overrides win over a registry's mirror.
+func (ps *ParsedSource) ApplyMirror(overrides map[string]string, mirror string) {
+	if !ps.ApplyCloneURLOverride(overrides) && mirror != "" {
+		ps.CloneURL = mirror
+	}
+}
+
+// HostOverrideKey returns the clone URL override key that applies to every
+// repository on a host, e.g. "github.com/*".
+func HostOverrideKey(host string) string {
+	return strings.ToLower(host) + "/*"
+}
+
+// LookupCloneURLOverride returns the clone URL override for a repository.
+// An exact "owner/repo" entry wins over a host-wide "host/*" entry, whose
+// value is a URL template with {owner} and {repo} placeholders.
+func LookupCloneURLOverride(overrides map[string]string, host, owner, repo string) (string, bool) {
+	if len(overrides) == 0 || owner == "" || repo == "" {
+		return "", false
 	}
+	key := strings.ToLower(owner) + "/" + strings.ToLower(repo)
 	if override, ok := overrides[key]; ok && override != "" {
-		ps.CloneURL = override
-		return true
+		return override, true
 	}
-	return false
+	if host == "" {
+		return "", false
+	}
+	if tmpl, ok := overrides[HostOverrideKey(host)]; ok && tmpl != "" {
+		return strings.NewReplacer("{owner}", owner, "{repo}", repo).Replace(tmpl), true
+	}
+	return "", false
+}
+
+// ValidateCloneURLOverride checks a clone URL override before it is saved:
+// the key is "owner/repo" or a host-wide "host/*", and a host-wide value is
+// a template containing {owner} and {repo}.
+func ValidateCloneURLOverride(key, cloneURL string) error {
+	if key == "" || cloneURL == "" {
+		return fmt.Errorf("clone URL override needs both a pattern and a URL")
+	}
+	if strings.ContainsAny(key, " \t") || strings.ContainsAny(cloneURL, " \t") {
+		return fmt.Errorf("clone URL override %q: pattern and URL must not contain spaces", key)
+	}
+	parts := strings.Split(key, "/")
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return fmt.Errorf("clone URL override pattern %q: want owner/repo or host/*", key)
+	}
+	if parts[1] == "*" {
+		if !strings.Contains(cloneURL, "{owner}") || !strings.Contains(cloneURL, "{repo}") {
+			return fmt.Errorf("clone URL override %q: a host-wide URL must contain {owner} and {repo}", key)
+		}
+	} else if strings.Contains(key, "*") {
+		return fmt.Errorf("clone URL override pattern %q: only host/* may use a wildcard", key)
+	}
+	return nil
+}
+
+// CloneURLTemplate turns a clone URL that works for owner/repo into a
+// host-wide override template by replacing the repository path with
+// {owner}/{repo}. It returns false if the URL does not contain the path.
+func CloneURLTemplate(cloneURL, owner, repo string) (string, bool) {
+	if owner == "" || repo == "" {
+		return "", false
+	}
+	path := owner + "/" + repo
+	i := strings.LastIndex(strings.ToLower(cloneURL), strings.ToLower(path))
+	if i < 0 {
+		return "", false
+	}
+	return cloneURL[:i] + "{owner}/{repo}" + cloneURL[i+len(path):], true
 }
 
 func parseHTTPSource(input string) (*ParsedSource, error) {
@@ -186,7 +262,7 @@ func parseHTTPSource(input string) (*ParsedSource, error) {
 		Host: u.Host,
 	}
 
-	if len(pathParts) >= 2 {
+	if len(pathParts) >= 2 && pathParts[0] != "" && strings.TrimSuffix(pathParts[1], ".git") != "" {
 		result.Owner = pathParts[0]
 		result.Repo = strings.TrimSuffix(pathParts[1], ".git")
 
@@ -207,3 +283,18 @@ func parseHTTPSource(input string) (*ParsedSource, error) {
 
 	return result, nil
 }
+
+// escapesRepo reports whether a source subpath points outside the
+// repository: absolute, or with a ".." segment. Such paths can only come
+// from mistyped or hostile input and would read files next to the clone.
+func escapesRepo(subPath string) bool {
+	if strings.HasPrefix(subPath, "/") || strings.HasPrefix(subPath, "\\") {
+		return true
+	}
+	for _, seg := range strings.FieldsFunc(subPath, func(r rune) bool { return r == '/' || r == '\\' }) {
+		if seg == ".." {
+			return true
+		}
+	}
+	return false
+}
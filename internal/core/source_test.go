@@ -218,6 +218,38 @@ func TestParseSource_LocalPathRejected(t *testing.T) {
 	}
 }
 
+func TestParseSource_EscapingSubpathRejected(t *testing.T) {
+	cases := []string{
+		"owner/repo/../../etc",
+		"github.com/owner/repo/skills/../../..",
+		"https://github.com/owner/repo/tree/main/../../x",
+	}
+	for _, input := range cases {
+		t.Run(input, func(t *testing.T) {
+			_, err := ParseSource(input)
+			if err == nil {
+				t.Fatalf("expected error for %q, got nil", input)
+			}
+			if !strings.Contains(err.Error(), "points outside the repository") {
+				t.Errorf("error = %q, want it to contain %q", err.Error(), "points outside the repository")
+			}
+		})
+	}
+}
+
+func TestParseSource_CanonicalWithPort(t *testing.T) {
+	src, err := ParseSource("git.example.com:8443/team/repo/skills/lint")
+	if err != nil {
+		t.Fatalf("ParseSource() error: %v", err)
+	}
+	if src.Host != "git.example.com:8443" || src.Owner != "team" || src.Repo != "repo" || src.SubPath != "skills/lint" {
+		t.Errorf("got %s/%s/%s %q", src.Host, src.Owner, src.Repo, src.SubPath)
+	}
+	if src.CloneURL != "https://git.example.com:8443/team/repo.git" {
+		t.Errorf("CloneURL = %q, want %q", src.CloneURL, "https://git.example.com:8443/team/repo.git")
+	}
+}
+
 func TestParseSource_CanonicalGitHub(t *testing.T) {
 	src, err := ParseSource("github.com/pandadoc-studio/skills/skills/communication/slack-digest")
 	if err != nil {
@@ -474,4 +506,150 @@ func TestApplyCloneURLOverride(t *testing.T) {
 			t.Error("ApplyCloneURLOverride() returned true for source without owner/repo")
 		}
 	})
+
+	t.Run("host-wide template", func(t *testing.T) {
+		withHost := map[string]string{
+			"pandadoc-studio/skills": "git@github.com-work:pandadoc-studio/skills.git",
+			"github.com/*":           "git@github.com:{owner}/{repo}.git",
+		}
+		src, _ := ParseSource("vercel-labs/agent-skills")
+		if !src.ApplyCloneURLOverride(withHost) {
+			t.Fatal("ApplyCloneURLOverride() returned false, want host template applied")
+		}
+		if src.CloneURL != "git@github.com:vercel-labs/agent-skills.git" {
+			t.Errorf("CloneURL = %q, want expanded template", src.CloneURL)
+		}
+
+		// The exact repo entry wins over the host template.
+		src, _ = ParseSource("pandadoc-studio/skills")
+		src.ApplyCloneURLOverride(withHost)
+		if src.CloneURL != "git@github.com-work:pandadoc-studio/skills.git" {
+			t.Errorf("CloneURL = %q, want repo override", src.CloneURL)
+		}
+
+		// Other hosts are unaffected.
+		src, _ = ParseSource("https://gitlab.com/acme/skills")
+		if src.ApplyCloneURLOverride(withHost) {
+			t.Errorf("host template applied to gitlab.com: %q", src.CloneURL)
+		}
+	})
+}
+
+func TestCloneURLTemplate(t *testing.T) {
+	tests := []struct {
+		url    string
+		want   string
+		wantOK bool
+	}{
+		{"git@github.com:Acme/Skills.git", "git@github.com:{owner}/{repo}.git", true},
+		{"https://proxy.corp/github/acme/skills", "https://proxy.corp/github/{owner}/{repo}", true},
+		{"https://mirror.corp/skills.git", "", false},
+	}
+	for _, tt := range tests {
+		got, ok := CloneURLTemplate(tt.url, "acme", "skills")
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("CloneURLTemplate(%q) = %q, %v; want %q, %v", tt.url, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+// sourceSeeds are real-world source shapes used to seed the source fuzzers.
+var sourceSeeds = []string{
+	"owner/repo",
+	"owner/repo@skill-name",
+	"owner/repo/skills/lint",
+	"github.com/owner/repo",
+	"github.com/owner/repo/skills/lint",
+	"gitlab.com/group/subgroup/repo",
+	"git.example.com:8443/team/repo/skills/lint",
+	"git@github.com:owner/repo.git",
+	"git@gitlab.com:group/subgroup/repo.git",
+	"git@git.internal.co:team/repo",
+	"https://github.com/owner/repo",
+	"https://github.com/owner/repo.git",
+	"https://github.com/owner/repo/",
+	"https://github.com/owner/repo/tree/main/skills/lint",
+	"https://github.com/owner/repo/tree/v1.2.0",
+	"https://gitlab.com/group/subgroup/repo.git",
+	"https://git.example.com:8443/team/repo.git",
+	"http://localhost:3000/owner/repo",
+	"https://user@bitbucket.org/team/repo.git",
+	"owner/repo/../../etc",
+	"https://github.com/owner/repo/tree/main/../../x",
+	"https://github.com//repo",
+	"../local",
+	"",
+}
+
+// FuzzParseSource checks that ParseSource never panics, that accepted
+// sources never point outside their repository, and that canonical lock
+// sources built from a parsed source parse back to the same components.
+func FuzzParseSource(f *testing.F) {
+	for _, s := range sourceSeeds {
+		f.Add(s)
+	}
+	f.Fuzz(func(t *testing.T, input string) {
+		ps, err := ParseSource(input)
+		if err != nil {
+			return
+		}
+		if ps == nil {
+			t.Fatalf("ParseSource(%q) = nil, nil", input)
+		}
+		if ps.CloneURL == "" {
+			t.Errorf("ParseSource(%q): empty CloneURL", input)
+		}
+		if (ps.Owner == "") != (ps.Repo == "") {
+			t.Errorf("ParseSource(%q): owner %q, repo %q: want both or neither", input, ps.Owner, ps.Repo)
+		}
+		if escapesRepo(ps.SubPath) {
+			t.Errorf("ParseSource(%q): subpath %q escapes the repository", input, ps.SubPath)
+		}
+
+		// Lock sources are re-parsed by sync; those in canonical form
+		// (host with a dot and optional port, safe owner/repo) must
+		// round-trip.
+		canonical := NormalizeSource(ps.Host, ps.Owner, ps.Repo, ps.SubPath)
+		m := canonicalSourcePattern.FindStringSubmatch(canonical)
+		if m == nil || m[1] != ps.Host || !ownerRepoPattern.MatchString(ps.Owner+"/"+ps.Repo) ||
+			ps.SubPath != strings.TrimSpace(ps.SubPath) {
+			return
+		}
+		again, err := ParseSource(canonical)
+		if err != nil {
+			t.Fatalf("ParseSource(%q) from %q: %v", canonical, input, err)
+		}
+		if again.Host != ps.Host || again.Owner != ps.Owner || again.Repo != ps.Repo || again.SubPath != ps.SubPath {
+			t.Errorf("round trip of %q via %q: got %s/%s/%s %q, want %s/%s/%s %q", input, canonical,
+				again.Host, again.Owner, again.Repo, again.SubPath, ps.Host, ps.Owner, ps.Repo, ps.SubPath)
+		}
+	})
+}
+
+func TestValidateCloneURLOverride(t *testing.T) {
+	tests := []struct {
+		key, url string
+		wantErr  string
+	}{
+		{"acme/skills", "git@github.com:acme/skills.git", ""},
+		{"github.com/*", "git@github.com:{owner}/{repo}.git", ""},
+		{"github.com/*", "git@github.com:acme/skills.git", "{owner} and {repo}"},
+		{"acme", "git@github.com:acme/skills.git", "owner/repo or host/*"},
+		{"acme/skills/extra", "git@github.com:acme/skills.git", "owner/repo or host/*"},
+		{"*/skills", "git@github.com:acme/skills.git", "wildcard"},
+		{"acme/skills", "", "both"},
+		{"acme/skills", "git@host: acme", "spaces"},
+	}
+	for _, tt := range tests {
+		err := ValidateCloneURLOverride(tt.key, tt.url)
+		if tt.wantErr == "" {
+			if err != nil {
+				t.Errorf("ValidateCloneURLOverride(%q, %q) error = %v", tt.key, tt.url, err)
+			}
+			continue
+		}
+		if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+			t.Errorf("ValidateCloneURLOverride(%q, %q) error = %v, want %q", tt.key, tt.url, err, tt.wantErr)
+		}
+	}
 }
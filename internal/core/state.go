@@ -0,0 +1,182 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+const stateFileName = "state.json"
+
+// State is what duckrow remembers between runs, stored at
+// ~/.duckrow/state.json. Unlike Config it is written by duckrow itself and
+// is not meant to be edited by hand.
+type State struct {
+	// LastSystems records the systems last selected in the TUI install
+	// wizard, keyed by absolute folder path and then asset kind.
+	LastSystems map[string]map[asset.Kind][]string `json:"lastSystems,omitempty"`
+
+	// Sessions records where the TUI was left, keyed by the absolute
+	// directory it was launched from.
+	Sessions map[string]TUISession `json:"sessions,omitempty"`
+
+	// FolderViews records the TUI's folder view per absolute folder path.
+	FolderViews map[string]FolderView `json:"folderViews,omitempty"`
+
+	// Failures records the items the last sync or update --all failed on,
+	// keyed by absolute folder path and then operation, for --retry-failed.
+	Failures map[string]map[string][]FailedItem `json:"failures,omitempty"`
+}
+
+// TUISession is where the TUI was left: the view and folder it showed, and
+// the install wizard's choices if one was open.
+type TUISession struct {
+	View   string         `json:"view,omitempty"`
+	Folder string         `json:"folder,omitempty"`
+	Wizard *WizardSession `json:"wizard,omitempty"`
+}
+
+// WizardSession is an install wizard's asset and the systems checked in it.
+type WizardSession struct {
+	Kind     asset.Kind `json:"kind"`
+	Name     string     `json:"name"`
+	Registry string     `json:"registry,omitempty"` // repo URL of the registry the asset is from
+	Systems  []string   `json:"systems"`
+}
+
+// FolderView is the TUI's folder view of one folder: the active tab and
+// the item selected in each tab, by name.
+type FolderView struct {
+	Tab      asset.Kind            `json:"tab,omitempty"`
+	Selected map[asset.Kind]string `json:"selected,omitempty"`
+}
+
+// StatePath returns the full path to the state file.
+func (cm *ConfigManager) StatePath() string {
+	return filepath.Join(cm.configDir, stateFileName)
+}
+
+// LoadState reads the state from disk. Returns an empty state if the file
+// doesn't exist.
+func (cm *ConfigManager) LoadState() (*State, error) {
+	cm.mu.RLock()
+	defer cm.mu.RUnlock()
+
+	data, err := os.ReadFile(cm.StatePath())
+	if err != nil {
+		if os.IsNotExist(err) {
+			return &State{}, nil
+		}
+		return nil, fmt.Errorf("reading state: %w", err)
+	}
+
+	var st State
+	if err := json.Unmarshal(data, &st); err != nil {
+		return nil, fmt.Errorf("parsing state: %w", err)
+	}
+	return &st, nil
+}
+
+// SaveState writes the state to disk atomically.
+func (cm *ConfigManager) SaveState(st *State) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+
+	if err := os.MkdirAll(cm.configDir, 0o755); err != nil {
+		return fmt.Errorf("creating config directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(st, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshaling state: %w", err)
+	}
+
+	tmpPath := cm.StatePath() + ".tmp"
+	if err := os.WriteFile(tmpPath, data, 0o644); err != nil {
+		return fmt.Errorf("writing state: %w", err)
+	}
+	if err := os.Rename(tmpPath, cm.StatePath()); err != nil {
+		_ = os.Remove(tmpPath)
+		return fmt.Errorf("saving state: %w", err)
+	}
+	return nil
+}
+
+// LastSystems returns the system names last selected when installing an
+// asset of the given kind into folder, and whether a selection was saved.
+// A saved selection may be empty.
+func (cm *ConfigManager) LastSystems(folder string, kind asset.Kind) ([]string, bool) {
+	st, err := cm.LoadState()
+	if err != nil {
+		return nil, false
+	}
+	names, ok := st.LastSystems[folderKey(folder)][kind]
+	return names, ok
+}
+
+// SaveLastSystems records the systems selected when installing an asset of
+// the given kind into folder, replacing any earlier selection.
+func (cm *ConfigManager) SaveLastSystems(folder string, kind asset.Kind, names []string) error {
+	st, err := cm.LoadState()
+	if err != nil {
+		return err
+	}
+	if st.LastSystems == nil {
+		st.LastSystems = make(map[string]map[asset.Kind][]string)
+	}
+	key := folderKey(folder)
+	if st.LastSystems[key] == nil {
+		st.LastSystems[key] = make(map[asset.Kind][]string)
+	}
+	if names == nil {
+		names = []string{}
+	}
+	st.LastSystems[key][kind] = names
+	return cm.SaveState(st)
+}
+
+// TUISession returns the TUI session last saved for the directory it was
+// launched from, and whether one was saved.
+func (cm *ConfigManager) TUISession(dir string) (TUISession, bool) {
+	st, err := cm.LoadState()
+	if err != nil {
+		return TUISession{}, false
+	}
+	s, ok := st.Sessions[folderKey(dir)]
+	return s, ok
+}
+
+// FolderViews returns the TUI folder views saved for each folder.
+func (cm *ConfigManager) FolderViews() map[string]FolderView {
+	st, err := cm.LoadState()
+	if err != nil || st.FolderViews == nil {
+		return make(map[string]FolderView)
+	}
+	return st.FolderViews
+}
+
+// SaveTUISession records the TUI session for the directory it was launched
+// from, and replaces the saved folder views with views.
+func (cm *ConfigManager) SaveTUISession(dir string, s TUISession, views map[string]FolderView) error {
+	st, err := cm.LoadState()
+	if err != nil {
+		return err
+	}
+	if st.Sessions == nil {
+		st.Sessions = make(map[string]TUISession)
+	}
+	st.Sessions[folderKey(dir)] = s
+	st.FolderViews = views
+	return cm.SaveState(st)
+}
+
+// folderKey normalizes a folder path for use as a state key.
+func folderKey(folder string) string {
+	if abs, err := filepath.Abs(folder); err == nil {
+		return abs
+	}
+	return filepath.Clean(folder)
+}
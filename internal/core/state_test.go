@@ -0,0 +1,89 @@
+package core
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestConfigManager_LastSystems(t *testing.T) {
+	cm := NewConfigManagerWithDir(t.TempDir())
+	project := t.TempDir()
+
+	if names, ok := cm.LastSystems(project, asset.KindSkill); ok || names != nil {
+		t.Fatalf("LastSystems() before save = %v, %v; want nil, false", names, ok)
+	}
+
+	if err := cm.SaveLastSystems(project, asset.KindSkill, []string{"cursor", "claude-code"}); err != nil {
+		t.Fatalf("SaveLastSystems() error: %v", err)
+	}
+	// An empty selection is remembered too.
+	if err := cm.SaveLastSystems(project, asset.KindMCP, nil); err != nil {
+		t.Fatalf("SaveLastSystems() error: %v", err)
+	}
+
+	names, ok := cm.LastSystems(project, asset.KindSkill)
+	if !ok || !reflect.DeepEqual(names, []string{"cursor", "claude-code"}) {
+		t.Errorf("LastSystems(skill) = %v, %v; want [cursor claude-code], true", names, ok)
+	}
+	if names, ok := cm.LastSystems(project, asset.KindMCP); !ok || len(names) != 0 {
+		t.Errorf("LastSystems(mcp) = %v, %v; want [], true", names, ok)
+	}
+	if _, ok := cm.LastSystems(project, asset.KindAgent); ok {
+		t.Error("LastSystems(agent) found a selection that was never saved")
+	}
+	if _, ok := cm.LastSystems(t.TempDir(), asset.KindSkill); ok {
+		t.Error("LastSystems() for another folder found a selection")
+	}
+
+	// Saving replaces the earlier selection.
+	if err := cm.SaveLastSystems(project, asset.KindSkill, []string{"opencode"}); err != nil {
+		t.Fatalf("SaveLastSystems() error: %v", err)
+	}
+	if names, _ := cm.LastSystems(project, asset.KindSkill); !reflect.DeepEqual(names, []string{"opencode"}) {
+		t.Errorf("LastSystems(skill) after resave = %v, want [opencode]", names)
+	}
+}
+
+func TestConfigManager_TUISession(t *testing.T) {
+	cm := NewConfigManagerWithDir(t.TempDir())
+	launch := t.TempDir()
+
+	if _, ok := cm.TUISession(launch); ok {
+		t.Fatal("TUISession() before save found a session")
+	}
+	if views := cm.FolderViews(); len(views) != 0 {
+		t.Fatalf("FolderViews() before save = %v, want empty", views)
+	}
+
+	// The session sits beside the wizard's last systems.
+	if err := cm.SaveLastSystems(launch, asset.KindSkill, []string{"cursor"}); err != nil {
+		t.Fatalf("SaveLastSystems() error: %v", err)
+	}
+	session := TUISession{
+		View:   "install-wizard",
+		Folder: launch,
+		Wizard: &WizardSession{Kind: asset.KindSkill, Name: "lint", Systems: []string{"cursor"}},
+	}
+	views := map[string]FolderView{
+		launch: {Tab: asset.KindMCP, Selected: map[asset.Kind]string{asset.KindMCP: "db"}},
+	}
+	if err := cm.SaveTUISession(launch, session, views); err != nil {
+		t.Fatalf("SaveTUISession() error: %v", err)
+	}
+
+	got, ok := cm.TUISession(launch)
+	if !ok || !reflect.DeepEqual(got, session) {
+		t.Errorf("TUISession() = %+v, %v; want %+v, true", got, ok, session)
+	}
+	if _, ok := cm.TUISession(t.TempDir()); ok {
+		t.Error("TUISession() for another directory found a session")
+	}
+	if got := cm.FolderViews(); !reflect.DeepEqual(got, views) {
+		t.Errorf("FolderViews() = %v, want %v", got, views)
+	}
+	if _, ok := cm.LastSystems(launch, asset.KindSkill); !ok {
+		t.Error("SaveTUISession() dropped the saved last systems")
+	}
+}
@@ -0,0 +1,70 @@
+package core
+
+import (
+	"fmt"
+	"sync/atomic"
+
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+// InstallStrategy is how skills reach non-universal systems, whose skill
+// directories sit outside .agents/skills/.
+type InstallStrategy string
+
+const (
+	// StrategySymlink links each system's skill directory to the canonical
+	// copy, copying only where symlinks can't be created. The default.
+	StrategySymlink InstallStrategy = "symlink"
+	// StrategyCopy always copies, for tools or sync setups that don't
+	// follow symlinks.
+	StrategyCopy InstallStrategy = "copy"
+)
+
+// InstallStrategies lists the valid install strategies.
+func InstallStrategies() []InstallStrategy {
+	return []InstallStrategy{StrategySymlink, StrategyCopy}
+}
+
+// ParseInstallStrategy validates an install strategy name. An empty name is
+// the default.
+func ParseInstallStrategy(s string) (InstallStrategy, error) {
+	switch InstallStrategy(s) {
+	case "", StrategySymlink:
+		return StrategySymlink, nil
+	case StrategyCopy:
+		return StrategyCopy, nil
+	}
+	return "", fmt.Errorf("unknown install strategy %q (want %q or %q)", s, StrategySymlink, StrategyCopy)
+}
+
+// Strategy returns the configured install strategy. An invalid value falls
+// back to the default.
+func (s Settings) Strategy() InstallStrategy {
+	st, err := ParseInstallStrategy(s.InstallStrategy)
+	if err != nil {
+		return StrategySymlink
+	}
+	return st
+}
+
+// currentStrategyValue is process-wide like the timeouts: the CLI sets it
+// once from the settings, and every skill install reads it.
+var currentStrategyValue atomic.Value
+
+// SetInstallStrategy sets the install strategy.
+func SetInstallStrategy(s InstallStrategy) {
+	currentStrategyValue.Store(s)
+}
+
+// CurrentInstallStrategy returns the install strategy in effect.
+func CurrentInstallStrategy() InstallStrategy {
+	if s, ok := currentStrategyValue.Load().(InstallStrategy); ok && s != "" {
+		return s
+	}
+	return StrategySymlink
+}
+
+// systemInstallOptions are the options every system install gets.
+func systemInstallOptions(force bool) system.InstallOptions {
+	return system.InstallOptions{Force: force, Copy: CurrentInstallStrategy() == StrategyCopy}
+}
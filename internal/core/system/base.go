@@ -1,14 +1,19 @@
 package system
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
 	"sort"
 	"strings"
+	"time"
 
 	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/filelock"
 	"github.com/tailscale/hujson"
 )
 
@@ -21,16 +26,24 @@ type BaseSystem struct {
 	skillsDir       string       // project-relative skill directory
 	altSkillsDirs   []string     // additional native skill directories
 	agentsDir       string       // project-relative agents directory (e.g., ".claude/agents")
+	agentExt        string       // agent file extension (e.g., ".agent.md"); "" is ".md"
+	commandsDir     string       // project-relative slash commands directory (e.g., ".claude/commands")
+	plainCommands   bool         // command files are written without frontmatter
+	rulesDir        string       // project-relative rules directory (e.g., ".cursor/rules")
+	ruleExt         string       // rule file extension (e.g., ".mdc")
 	globalSkillsDir string       // global skill directory (with ~ or $VAR)
 	detectPaths     []string     // files/dirs to check for global installation
 	configSignals   []string     // project files indicating active use
 	supportedKinds  []asset.Kind // asset kinds this system supports
+	scope           Scope        // ScopeGlobal when built by globalBase
 
 	// MCP config (for systems that support MCP)
-	mcpConfigPath    string // project-relative MCP config file
-	mcpConfigPathAlt string // alternative config path checked first
-	mcpConfigKey     string // JSON key in config (e.g., "mcpServers")
-	mcpConfigFormat  string // "jsonc" or "" (strict JSON)
+	mcpConfigPath          string // project-relative MCP config file
+	mcpConfigPathAlt       string // alternative config path checked first
+	mcpConfigKey           string // JSON key in config (e.g., "mcpServers")
+	mcpConfigFormat        string // "jsonc" or "" (strict JSON)
+	globalMCPConfigPath    string // global MCP config file (with ~ or $VAR)
+	globalMCPConfigPathAlt string // alternative global config path checked first
 }
 
 func (b *BaseSystem) Name() string        { return b.name }
@@ -50,6 +63,32 @@ func (b *BaseSystem) SupportedKinds() []asset.Kind {
 	return b.supportedKinds
 }
 
+func (b *BaseSystem) Scope() Scope { return b.scope }
+
+// globalBase returns a copy of b that installs to the system's user-level
+// locations, with paths relative to the home directory. Only skills and
+// MCPs have global locations, and only for systems that define them.
+func (b *BaseSystem) globalBase() BaseSystem {
+	g := *b
+	g.scope = ScopeGlobal
+	g.skillsDir = homeRelPath(b.globalSkillsDir)
+	g.altSkillsDirs = nil
+	g.agentsDir, g.commandsDir, g.rulesDir = "", "", ""
+	g.mcpConfigPath = homeRelPath(b.globalMCPConfigPath)
+	g.mcpConfigPathAlt = homeRelPath(b.globalMCPConfigPathAlt)
+	// Only a system reading ~/.agents/skills itself shares the canonical
+	// copy; the others get a link to it, whatever they do in a project.
+	g.universal = g.skillsDir == ".agents/skills"
+
+	g.supportedKinds = nil
+	for _, k := range b.supportedKinds {
+		if (k == asset.KindSkill && g.skillsDir != "") || (k == asset.KindMCP && g.mcpConfigPath != "") {
+			g.supportedKinds = append(g.supportedKinds, k)
+		}
+	}
+	return g
+}
+
 func (b *BaseSystem) IsInstalled() bool {
 	for _, p := range b.detectPaths {
 		if dirExists(expandPath(p)) {
@@ -93,11 +132,41 @@ func (b *BaseSystem) AssetDir(kind asset.Kind, projectDir string) string {
 			return filepath.Join(projectDir, b.agentsDir)
 		}
 		return ""
+	case asset.KindCommand:
+		if b.commandsDir != "" {
+			return filepath.Join(projectDir, b.commandsDir)
+		}
+		return ""
+	case asset.KindRule:
+		if b.rulesDir != "" {
+			return filepath.Join(projectDir, b.rulesDir)
+		}
+		return ""
 	default:
 		return ""
 	}
 }
 
+// AssetPath returns where an asset of the given kind and name is installed
+// for this system: the skill directory, or the agent, command, or rule file.
+// It returns "" for kinds the system has no directory for, and for MCPs.
+func (b *BaseSystem) AssetPath(kind asset.Kind, name string, projectDir string) string {
+	dir := b.AssetDir(kind, projectDir)
+	if dir == "" {
+		return ""
+	}
+	switch kind {
+	case asset.KindSkill:
+		return filepath.Join(dir, sanitizeName(name))
+	case asset.KindAgent:
+		return filepath.Join(dir, sanitizeName(name)+b.agentFileExt())
+	case asset.KindRule:
+		return filepath.Join(dir, sanitizeName(name)+b.ruleExt)
+	default:
+		return filepath.Join(dir, sanitizeName(name)+".md")
+	}
+}
+
 // SkillsDir returns the project-relative skill directory path.
 func (b *BaseSystem) SkillsDir() string { return b.skillsDir }
 
@@ -134,6 +203,10 @@ func (b *BaseSystem) Install(a asset.Asset, projectDir string, opts InstallOptio
 		return b.installMCP(a, projectDir, opts)
 	case asset.KindAgent:
 		return b.installAgent(a, projectDir, opts)
+	case asset.KindCommand:
+		return b.installCommand(a, projectDir, opts)
+	case asset.KindRule:
+		return b.installRule(a, projectDir, opts)
 	default:
 		return fmt.Errorf("system %s does not support asset kind %s", b.name, a.Kind)
 	}
@@ -145,9 +218,13 @@ func (b *BaseSystem) Remove(kind asset.Kind, name string, projectDir string) err
 	case asset.KindSkill:
 		return b.removeSkill(name, projectDir)
 	case asset.KindMCP:
-		return b.removeMCP(name, projectDir)
+		return b.RemoveMCP(name, projectDir, RemoveMCPOptions{})
 	case asset.KindAgent:
 		return b.removeAgent(name, projectDir)
+	case asset.KindCommand:
+		return b.removeCommand(name, projectDir)
+	case asset.KindRule:
+		return b.removeRule(name, projectDir)
 	default:
 		return fmt.Errorf("system %s does not support asset kind %s", b.name, kind)
 	}
@@ -160,6 +237,10 @@ func (b *BaseSystem) Scan(kind asset.Kind, projectDir string) ([]asset.Installed
 		return b.scanSkills(projectDir)
 	case asset.KindAgent:
 		return b.scanAgents(projectDir)
+	case asset.KindCommand:
+		return b.scanCommands(projectDir)
+	case asset.KindRule:
+		return b.scanRules(projectDir)
 	default:
 		return nil, nil
 	}
@@ -170,8 +251,9 @@ func (b *BaseSystem) Scan(kind asset.Kind, projectDir string) ([]asset.Installed
 // installSkill handles the default skill installation.
 // Universal systems: files already in .agents/skills/, nothing extra needed.
 // Non-universal systems: create a symlink from their skillsDir to the
-// canonical location, falling back to a full copy if symlink fails.
-func (b *BaseSystem) installSkill(a asset.Asset, projectDir string, _ InstallOptions) error {
+// canonical location, falling back to a full copy if symlink fails, or copy
+// outright when opts.Copy is set.
+func (b *BaseSystem) installSkill(a asset.Asset, projectDir string, opts InstallOptions) error {
 	if b.universal {
 		// Universal systems read from .agents/skills/ directly.
 		// The orchestrator handles copying to the canonical location.
@@ -191,6 +273,13 @@ func (b *BaseSystem) installSkill(a asset.Asset, projectDir string, _ InstallOpt
 	// Remove existing link/dir if present.
 	_ = os.RemoveAll(linkPath)
 
+	if opts.Copy {
+		if err := copyDirectory(canonicalDir, linkPath); err != nil {
+			return fmt.Errorf("copying skill for %s: %w", b.displayName, err)
+		}
+		return nil
+	}
+
 	// Create relative symlink.
 	rel, err := filepath.Rel(agentSkillDir, canonicalDir)
 	if err != nil {
@@ -246,11 +335,12 @@ func (b *BaseSystem) scanSkills(projectDir string) ([]asset.InstalledAsset, erro
 		}
 
 		for _, entry := range entries {
-			if !entry.IsDir() {
+			skillPath := filepath.Join(absDir, entry.Name())
+			// Skills linked in from .agents/skills/ are symlinks to directories.
+			if !entry.IsDir() && (entry.Type()&os.ModeSymlink == 0 || !dirExists(skillPath)) {
 				continue
 			}
 
-			skillPath := filepath.Join(absDir, entry.Name())
 			skillMdPath := filepath.Join(skillPath, "SKILL.md")
 
 			handler, ok := asset.Get(asset.KindSkill)
@@ -357,6 +447,11 @@ func parseSkillMdForScan(path string) (*skillScanInfo, error) {
 
 // --- Agent Installation ---
 
+// DisabledSuffix is appended to an agent file's name to disable the agent
+// for a system without uninstalling it: the system no longer reads the
+// file, but it is kept, and updated, until the agent is enabled again.
+const DisabledSuffix = ".disabled"
+
 // installAgent renders the agent for this system and writes it to the agents dir.
 func (b *BaseSystem) installAgent(a asset.Asset, projectDir string, opts InstallOptions) error {
 	if b.agentsDir == "" {
@@ -373,8 +468,11 @@ func (b *BaseSystem) installAgent(a asset.Asset, projectDir string, opts Install
 		return fmt.Errorf("creating agents dir for %s: %w", b.displayName, err)
 	}
 
-	filename := sanitizeName(a.Name) + ".md"
-	filePath := filepath.Join(agentsPath, filename)
+	filePath := b.AssetPath(asset.KindAgent, a.Name, projectDir)
+	// A disabled agent is updated in place and stays disabled.
+	if pathExists(filePath + DisabledSuffix) {
+		filePath += DisabledSuffix
+	}
 
 	// Check for existing file.
 	if pathExists(filePath) && !opts.Force {
@@ -382,7 +480,7 @@ func (b *BaseSystem) installAgent(a asset.Asset, projectDir string, opts Install
 	}
 
 	// Render agent content for this system using the merge algorithm.
-	rendered, err := asset.RenderForSystem(meta.Data, b.name)
+	rendered, err := asset.RenderForSystem(meta.Data, a.Name, b.name)
 	if err != nil {
 		return fmt.Errorf("rendering agent %q for %s: %w", a.Name, b.displayName, err)
 	}
@@ -391,24 +489,51 @@ func (b *BaseSystem) installAgent(a asset.Asset, projectDir string, opts Install
 		return fmt.Errorf("writing agent file for %s: %w", b.displayName, err)
 	}
 
+	// Drop a copy written under the plain .md name before this system had
+	// its own extension, so the agent isn't listed twice.
+	if legacy := b.legacyAgentPath(a.Name, projectDir); legacy != "" {
+		_ = os.Remove(legacy)
+	}
+
 	return nil
 }
 
-// removeAgent removes an agent .md file from this system's agents directory.
+// agentFileExt returns the extension agent files are written with.
+func (b *BaseSystem) agentFileExt() string {
+	if b.agentExt == "" {
+		return ".md"
+	}
+	return b.agentExt
+}
+
+// legacyAgentPath returns the plain .md path an agent was written to before
+// this system had its own extension, or "" if it never had one.
+func (b *BaseSystem) legacyAgentPath(name, projectDir string) string {
+	if b.agentFileExt() == ".md" {
+		return ""
+	}
+	return filepath.Join(projectDir, b.agentsDir, sanitizeName(name)+".md")
+}
+
+// removeAgent removes an agent file from this system's agents directory.
 func (b *BaseSystem) removeAgent(name string, projectDir string) error {
 	if b.agentsDir == "" {
 		return nil
 	}
 
-	filename := sanitizeName(name) + ".md"
-	filePath := filepath.Join(projectDir, b.agentsDir, filename)
-
-	if !pathExists(filePath) {
-		return nil // nothing to remove
+	filePath := b.AssetPath(asset.KindAgent, name, projectDir)
+	removed := false
+	for _, filePath := range []string{filePath, filePath + DisabledSuffix, b.legacyAgentPath(name, projectDir)} {
+		if filePath == "" || !pathExists(filePath) {
+			continue
+		}
+		if err := os.Remove(filePath); err != nil {
+			return fmt.Errorf("removing agent %s for %s: %w", name, b.displayName, err)
+		}
+		removed = true
 	}
-
-	if err := os.Remove(filePath); err != nil {
-		return fmt.Errorf("removing agent %s for %s: %w", name, b.displayName, err)
+	if !removed {
+		return nil // nothing to remove
 	}
 
 	// Clean up empty agents directory, then its parent.
@@ -419,7 +544,7 @@ func (b *BaseSystem) removeAgent(name string, projectDir string) error {
 	return nil
 }
 
-// scanAgents finds agent .md files installed for this system.
+// scanAgents finds agent files installed for this system.
 func (b *BaseSystem) scanAgents(projectDir string) ([]asset.InstalledAsset, error) {
 	if b.agentsDir == "" {
 		return nil, nil
@@ -443,8 +568,12 @@ func (b *BaseSystem) scanAgents(projectDir string) ([]asset.InstalledAsset, erro
 			continue
 		}
 
-		// Derive name from filename (strip .md extension).
-		name := strings.TrimSuffix(entry.Name(), ".md")
+		// Derive name from filename (strip the agent extension, or .md for a
+		// file written before this system had its own).
+		name := strings.TrimSuffix(entry.Name(), b.agentFileExt())
+		if name == entry.Name() {
+			name = strings.TrimSuffix(name, ".md")
+		}
 
 		description, _ := data.Frontmatter["description"].(string)
 
@@ -466,106 +595,259 @@ func (b *BaseSystem) scanAgents(projectDir string) ([]asset.InstalledAsset, erro
 	return result, nil
 }
 
-// --- MCP Installation ---
+// --- Command Installation ---
 
-// installMCP writes an MCP config entry into this system's config file.
-// This is the default implementation; systems with unique formats override it.
-func (b *BaseSystem) installMCP(a asset.Asset, projectDir string, opts InstallOptions) error {
-	if b.mcpConfigPath == "" {
-		return fmt.Errorf("system %s does not support MCP configuration", b.displayName)
+// installCommand writes a slash command to this system's commands dir.
+func (b *BaseSystem) installCommand(a asset.Asset, projectDir string, opts InstallOptions) error {
+	if b.commandsDir == "" {
+		return fmt.Errorf("system %s does not support commands", b.displayName)
 	}
 
-	meta, ok := a.Meta.(asset.MCPMeta)
+	meta, ok := a.Meta.(asset.CommandDataMeta)
 	if !ok {
-		return fmt.Errorf("expected MCPMeta, got %T", a.Meta)
+		return fmt.Errorf("expected CommandDataMeta, got %T", a.Meta)
 	}
 
-	configPath := b.resolveMCPConfigPath(projectDir)
+	commandsPath := filepath.Join(projectDir, b.commandsDir)
+	if err := os.MkdirAll(commandsPath, 0o755); err != nil {
+		return fmt.Errorf("creating commands dir for %s: %w", b.displayName, err)
+	}
 
-	// Read existing config file content (or start with empty object).
-	content, err := readConfigFile(configPath)
-	if err != nil {
-		return fmt.Errorf("reading config: %w", err)
+	filePath := filepath.Join(commandsPath, sanitizeName(a.Name)+".md")
+	if pathExists(filePath) && !opts.Force {
+		return ErrAlreadyExists
 	}
-	if content == "" {
-		content = "{}"
+
+	if err := os.WriteFile(filePath, meta.Data.Render(!b.plainCommands), 0o644); err != nil {
+		return fmt.Errorf("writing command file for %s: %w", b.displayName, err)
 	}
+	return nil
+}
 
-	// Parse as JSONC/JWCC AST — preserves comments and whitespace.
-	root, err := hujson.Parse([]byte(content))
-	if err != nil {
-		return fmt.Errorf("parsing config: %w", err)
+// removeCommand removes a slash command .md file from this system's
+// commands directory.
+func (b *BaseSystem) removeCommand(name string, projectDir string) error {
+	if b.commandsDir == "" {
+		return nil
 	}
 
-	// Check if entry already exists.
-	entryPtr := "/" + jsonPointerEscape(b.mcpConfigKey) + "/" + jsonPointerEscape(a.Name)
-	if root.Find(entryPtr) != nil && !opts.Force {
-		return ErrAlreadyExists
+	filePath := filepath.Join(projectDir, b.commandsDir, sanitizeName(name)+".md")
+	if !pathExists(filePath) {
+		return nil // nothing to remove
 	}
 
-	// Build the MCP config value as JSON.
-	mcpValueJSON := b.buildMCPConfig(a.Name, meta)
+	if err := os.Remove(filePath); err != nil {
+		return fmt.Errorf("removing command %s for %s: %w", name, b.displayName, err)
+	}
 
-	// Determine the patch operation.
-	op := "add"
-	if root.Find(entryPtr) != nil {
-		op = "replace"
+	// Clean up empty commands directory, then its parent.
+	commandsPath := filepath.Join(projectDir, b.commandsDir)
+	cleanupEmptyDir(commandsPath)
+	cleanupEmptyDir(filepath.Dir(commandsPath))
+
+	return nil
+}
+
+// scanCommands finds slash command .md files installed for this system.
+func (b *BaseSystem) scanCommands(projectDir string) ([]asset.InstalledAsset, error) {
+	if b.commandsDir == "" {
+		return nil, nil
 	}
 
-	// Ensure the top-level config key object exists.
-	topKeyPtr := "/" + jsonPointerEscape(b.mcpConfigKey)
-	if root.Find(topKeyPtr) == nil {
-		topKeyPatch := fmt.Sprintf(`[{"op":"add","path":%q,"value":{}}]`, topKeyPtr)
-		if err := root.Patch([]byte(topKeyPatch)); err != nil {
-			return fmt.Errorf("creating config key %q: %w", b.mcpConfigKey, err)
+	commandsPath := filepath.Join(projectDir, b.commandsDir)
+	entries, err := os.ReadDir(commandsPath)
+	if err != nil {
+		return nil, nil // directory doesn't exist
+	}
+
+	var result []asset.InstalledAsset
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+			continue
 		}
+
+		filePath := filepath.Join(commandsPath, entry.Name())
+		data, err := asset.ParseCommandFile(filePath)
+		if err != nil {
+			continue
+		}
+
+		result = append(result, asset.InstalledAsset{
+			Kind:        asset.KindCommand,
+			Name:        strings.TrimSuffix(entry.Name(), ".md"),
+			Description: data.Description(),
+			Path:        filePath,
+			Meta:        asset.CommandMeta{},
+			SystemName:  b.name,
+		})
 	}
 
-	// Apply the patch.
-	patch := fmt.Sprintf(`[{"op":%q,"path":%q,"value":%s}]`, op, entryPtr, mcpValueJSON)
-	if err := root.Patch([]byte(patch)); err != nil {
-		return fmt.Errorf("writing MCP entry: %w", err)
+	// ReadDir returns entries sorted by filename, so result is sorted by name.
+	return result, nil
+}
+
+// --- Rule Installation ---
+
+// installRule renders the rule in this system's format and writes it to the
+// rules dir.
+func (b *BaseSystem) installRule(a asset.Asset, projectDir string, opts InstallOptions) error {
+	if b.rulesDir == "" {
+		return fmt.Errorf("system %s does not support rules", b.displayName)
 	}
 
-	// Format and finalize.
-	output := b.finalizeConfig(&root)
+	meta, ok := a.Meta.(asset.RuleDataMeta)
+	if !ok {
+		return fmt.Errorf("expected RuleDataMeta, got %T", a.Meta)
+	}
+
+	rulesPath := filepath.Join(projectDir, b.rulesDir)
+	if err := os.MkdirAll(rulesPath, 0o755); err != nil {
+		return fmt.Errorf("creating rules dir for %s: %w", b.displayName, err)
+	}
+
+	filePath := b.AssetPath(asset.KindRule, a.Name, projectDir)
+	if pathExists(filePath) && !opts.Force {
+		return ErrAlreadyExists
+	}
+
+	rendered, err := asset.RenderRuleForSystem(meta.Data, b.name)
+	if err != nil {
+		return fmt.Errorf("rendering rule %q for %s: %w", a.Name, b.displayName, err)
+	}
 
-	return writeConfigFile(configPath, string(output))
+	if err := os.WriteFile(filePath, rendered, 0o644); err != nil {
+		return fmt.Errorf("writing rule file for %s: %w", b.displayName, err)
+	}
+	return nil
 }
 
-// removeMCP removes an MCP entry from this system's config file.
-func (b *BaseSystem) removeMCP(name string, projectDir string) error {
-	if b.mcpConfigPath == "" {
+// removeRule removes a rule file from this system's rules directory.
+func (b *BaseSystem) removeRule(name string, projectDir string) error {
+	if b.rulesDir == "" {
 		return nil
 	}
 
-	configPath := b.resolveMCPConfigPath(projectDir)
+	filePath := b.AssetPath(asset.KindRule, name, projectDir)
+	if !pathExists(filePath) {
+		return nil // nothing to remove
+	}
 
-	content, err := readConfigFile(configPath)
-	if err != nil {
-		return fmt.Errorf("reading config: %w", err)
+	if err := os.Remove(filePath); err != nil {
+		return fmt.Errorf("removing rule %s for %s: %w", name, b.displayName, err)
 	}
-	if content == "" {
-		return nil // no config file
+
+	// Clean up empty rules directory, then its parent.
+	rulesPath := filepath.Join(projectDir, b.rulesDir)
+	cleanupEmptyDir(rulesPath)
+	cleanupEmptyDir(filepath.Dir(rulesPath))
+
+	return nil
+}
+
+// scanRules finds rule files installed for this system.
+func (b *BaseSystem) scanRules(projectDir string) ([]asset.InstalledAsset, error) {
+	if b.rulesDir == "" {
+		return nil, nil
 	}
 
-	root, err := hujson.Parse([]byte(content))
+	rulesPath := filepath.Join(projectDir, b.rulesDir)
+	entries, err := os.ReadDir(rulesPath)
 	if err != nil {
-		return fmt.Errorf("parsing config: %w", err)
+		return nil, nil // directory doesn't exist
 	}
 
-	entryPtr := "/" + jsonPointerEscape(b.mcpConfigKey) + "/" + jsonPointerEscape(name)
-	if root.Find(entryPtr) == nil {
-		return nil // entry not found
+	var result []asset.InstalledAsset
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), b.ruleExt) {
+			continue
+		}
+
+		filePath := filepath.Join(rulesPath, entry.Name())
+		data, err := asset.ParseRuleFile(filePath)
+		if err != nil {
+			continue
+		}
+
+		result = append(result, asset.InstalledAsset{
+			Kind:        asset.KindRule,
+			Name:        strings.TrimSuffix(entry.Name(), b.ruleExt),
+			Description: data.Description,
+			Path:        filePath,
+			Meta:        asset.RuleMeta{},
+			SystemName:  b.name,
+		})
 	}
 
-	patch := fmt.Sprintf(`[{"op":"remove","path":%q}]`, entryPtr)
-	if err := root.Patch([]byte(patch)); err != nil {
-		return fmt.Errorf("removing MCP entry: %w", err)
+	// ReadDir returns entries sorted by filename, so result is sorted by name.
+	return result, nil
+}
+
+// --- MCP Installation ---
+
+// installMCP writes an MCP config entry into this system's config file.
+// This is the default implementation; systems with unique formats override it.
+func (b *BaseSystem) installMCP(a asset.Asset, projectDir string, opts InstallOptions) error {
+	if b.mcpConfigPath == "" {
+		return fmt.Errorf("system %s does not support MCP configuration", b.displayName)
+	}
+
+	meta, ok := a.Meta.(asset.MCPMeta)
+	if !ok {
+		return fmt.Errorf("expected MCPMeta, got %T", a.Meta)
 	}
 
-	output := b.finalizeConfig(&root)
-	return writeConfigFile(configPath, string(output))
+	configPath := b.resolveMCPConfigPath(projectDir)
+	return b.writeMCPEntry(configPath, a.Name, b.buildMCPConfig(a.Name, meta), opts)
+}
+
+// RemoveMCPOptions configures RemoveMCP.
+type RemoveMCPOptions struct {
+	// URLHash is the hash of the remote server URL duckrow wrote, as
+	// recorded in the lock file (see asset.LockedURLHash).
+	URLHash string
+	// TakeOwnership removes the entry even if duckrow didn't write it.
+	TakeOwnership bool
+}
+
+// RemoveMCP removes the MCP entry name from this system's config file.
+// An entry duckrow didn't write (see isManagedMCPEntry) is left in place
+// and ErrUnmanaged returned, unless opts.TakeOwnership is set.
+func (b *BaseSystem) RemoveMCP(name string, projectDir string, opts RemoveMCPOptions) error {
+	if b.mcpConfigPath == "" {
+		return nil
+	}
+
+	configPath := b.resolveMCPConfigPath(projectDir)
+	entryPtr := "/" + jsonPointerEscape(b.mcpConfigKey) + "/" + jsonPointerEscape(name)
+	return updateConfigFile(configPath, nil, func(content string) (string, error) {
+		if content == "" {
+			return content, nil // no config file
+		}
+
+		root, err := hujson.Parse([]byte(content))
+		if err != nil {
+			return "", fmt.Errorf("parsing config: %w", err)
+		}
+		entry := root.Find(entryPtr)
+		if entry == nil {
+			return content, nil // entry not found
+		}
+		if !opts.TakeOwnership && !isManagedMCPEntry(*entry, opts.URLHash) {
+			return "", fmt.Errorf("MCP %q in %s was %w", name, filepath.Base(configPath), ErrUnmanaged)
+		}
+
+		patch := fmt.Sprintf(`[{"op":"remove","path":%q}]`, entryPtr)
+		if err := root.Patch([]byte(patch)); err != nil {
+			return "", fmt.Errorf("removing MCP entry: %w", err)
+		}
+		// Close an object left empty as {}, not over the lines it spanned.
+		if top := root.Find("/" + jsonPointerEscape(b.mcpConfigKey)); top != nil {
+			if obj, ok := top.Value.(*hujson.Object); ok && len(obj.Members) == 0 {
+				obj.AfterExtra = nil
+			}
+		}
+		return string(b.finalizeConfig(&root)), nil
+	})
 }
 
 // buildMCPConfig produces the default MCP JSON value for stdio MCPs.
@@ -581,7 +863,7 @@ func (b *BaseSystem) buildMCPConfig(name string, meta asset.MCPMeta) string {
 			"command": "duckrow",
 			"args":    wrapperArgs,
 		}
-		data, _ := json.MarshalIndent(m, "\t\t", "\t")
+		data, _ := json.Marshal(m)
 		return string(data)
 	}
 
@@ -594,7 +876,7 @@ func (b *BaseSystem) buildMCPConfig(name string, meta asset.MCPMeta) string {
 		"type": mcpType,
 		"url":  meta.URL,
 	}
-	data, _ := json.MarshalIndent(m, "\t\t", "\t")
+	data, _ := json.Marshal(m)
 	return string(data)
 }
 
@@ -625,9 +907,143 @@ func (b *BaseSystem) ResolveMCPConfigPathRel(projectDir string) string {
 	return b.mcpConfigPath
 }
 
-// finalizeConfig formats the JSONC AST and produces final output bytes.
+// HasMCP reports whether this system's MCP config file in the project has
+// an entry named name.
+func (b *BaseSystem) HasMCP(name string, projectDir string) bool {
+	if b.mcpConfigPath == "" {
+		return false
+	}
+	content, err := readConfigFile(b.resolveMCPConfigPath(projectDir))
+	if err != nil || content == "" {
+		return false
+	}
+	root, err := hujson.Parse([]byte(content))
+	if err != nil {
+		return false
+	}
+	return root.Find("/"+jsonPointerEscape(b.mcpConfigKey)+"/"+jsonPointerEscape(name)) != nil
+}
+
+// ManagesMCP reports whether this system's MCP config file in the project
+// has an entry named name that duckrow wrote. urlHash is the URL hash the
+// lock file records for it, if any (see isManagedMCPEntry).
+func (b *BaseSystem) ManagesMCP(name string, projectDir string, urlHash string) bool {
+	if b.mcpConfigPath == "" {
+		return false
+	}
+	content, err := readConfigFile(b.resolveMCPConfigPath(projectDir))
+	if err != nil || content == "" {
+		return false
+	}
+	root, err := hujson.Parse([]byte(content))
+	if err != nil {
+		return false
+	}
+	entry := root.Find("/" + jsonPointerEscape(b.mcpConfigKey) + "/" + jsonPointerEscape(name))
+	return entry != nil && isManagedMCPEntry(*entry, urlHash)
+}
+
+// MCPConfigChanges compares two versions of this system's MCP config file,
+// e.g. as committed and on disk, and returns the names of the MCP entries
+// that differ between them, sorted, and whether anything outside those
+// entries differs as well. Either version may be empty for a missing file.
+func (b *BaseSystem) MCPConfigChanges(before, after string) (names []string, other bool, err error) {
+	parse := func(content string) (map[string]any, error) {
+		m := make(map[string]any)
+		if strings.TrimSpace(content) == "" {
+			return m, nil
+		}
+		v, err := hujson.Parse([]byte(content))
+		if err != nil {
+			return nil, err
+		}
+		v.Standardize()
+		if err := json.Unmarshal(v.Pack(), &m); err != nil {
+			return nil, err
+		}
+		return m, nil
+	}
+	old, err := parse(before)
+	if err != nil {
+		return nil, false, fmt.Errorf("parsing %s: %w", b.mcpConfigPath, err)
+	}
+	cur, err := parse(after)
+	if err != nil {
+		return nil, false, fmt.Errorf("parsing %s: %w", b.mcpConfigPath, err)
+	}
+
+	oldEntries, oldOK := old[b.mcpConfigKey].(map[string]any)
+	curEntries, curOK := cur[b.mcpConfigKey].(map[string]any)
+	for name, entry := range curEntries {
+		if prev, ok := oldEntries[name]; !ok || !reflect.DeepEqual(prev, entry) {
+			names = append(names, name)
+		}
+	}
+	for name := range oldEntries {
+		if _, ok := curEntries[name]; !ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	// A config key holding anything but an object is compared as is.
+	if (old[b.mcpConfigKey] != nil && !oldOK) || (cur[b.mcpConfigKey] != nil && !curOK) {
+		other = !reflect.DeepEqual(old[b.mcpConfigKey], cur[b.mcpConfigKey])
+	}
+	delete(old, b.mcpConfigKey)
+	delete(cur, b.mcpConfigKey)
+	return names, other || !reflect.DeepEqual(old, cur), nil
+}
+
+// isManagedMCPEntry reports whether duckrow wrote an MCP config entry.
+// Stdio servers run through the duckrow env wrapper, as "command":
+// "duckrow" with "args": ["env", "--mcp", ...], or OpenCode's "command":
+// ["duckrow", "env", "--mcp", ...]. A remote server is duckrow's when its
+// URL matches urlHash, the hash the lock file recorded when it was
+// installed. Lock entries written by older versions have no hash; their
+// servers are taken to be duckrow's when they carry only the "type" and
+// "url" duckrow writes, since one with headers or other settings was
+// written by hand.
+func isManagedMCPEntry(v hujson.Value, urlHash string) bool {
+	v = v.Clone()
+	v.Standardize()
+	var entry map[string]any
+	if err := json.Unmarshal(v.Pack(), &entry); err != nil {
+		return false
+	}
+
+	if command, ok := entry["command"]; ok {
+		var argv []any
+		switch c := command.(type) {
+		case string:
+			args, _ := entry["args"].([]any)
+			argv = append([]any{c}, args...)
+		case []any:
+			argv = c
+		}
+		return len(argv) >= 3 && argv[0] == "duckrow" && argv[1] == "env" && argv[2] == "--mcp"
+	}
+	url, ok := entry["url"].(string)
+	if !ok {
+		return false
+	}
+	if urlHash != "" {
+		return asset.URLHash(url) == urlHash
+	}
+	for key := range entry {
+		if key != "type" && key != "url" {
+			return false
+		}
+	}
+	return true
+}
+
+// finalizeConfig produces the final output bytes of the JSONC AST.
+// Entries under the MCP config key are sorted by name so the file does not
+// change with install order; the rest of the file keeps its layout, since
+// writeMCPEntry lays out only the entry it writes.
 func (b *BaseSystem) finalizeConfig(root *hujson.Value) []byte {
-	root.Format()
+	b.sortMCPEntries(root)
 	removeTrailingCommas(root)
 
 	if b.mcpConfigFormat != "jsonc" {
@@ -637,6 +1053,67 @@ func (b *BaseSystem) finalizeConfig(root *hujson.Value) []byte {
 	return root.Pack()
 }
 
+// sortMCPEntries orders the members of the MCP config key object by name
+// and reports whether the order changed. Comments attached to an entry move
+// with it.
+func (b *BaseSystem) sortMCPEntries(root *hujson.Value) bool {
+	v := root.Find("/" + jsonPointerEscape(b.mcpConfigKey))
+	if v == nil {
+		return false
+	}
+	obj, ok := v.Value.(*hujson.Object)
+	if !ok {
+		return false
+	}
+	less := func(i, j int) bool {
+		return memberName(obj.Members[i]) < memberName(obj.Members[j])
+	}
+	if sort.SliceIsSorted(obj.Members, less) {
+		return false
+	}
+	sort.SliceStable(obj.Members, less)
+	return true
+}
+
+// memberName returns the unquoted name of an object member.
+func memberName(m hujson.ObjectMember) string {
+	if lit, ok := m.Name.Value.(hujson.Literal); ok {
+		return lit.String()
+	}
+	return ""
+}
+
+// NormalizeMCPConfig rewrites this system's MCP config file in the project
+// with its entries sorted by name, as installs write it. It returns the
+// project-relative path and whether the file changed. A missing file, or one
+// whose entries are already in order, is left alone.
+func (b *BaseSystem) NormalizeMCPConfig(projectDir string) (string, bool, error) {
+	rel := b.ResolveMCPConfigPathRel(projectDir)
+	if rel == "" {
+		return "", false, nil
+	}
+	configPath := filepath.Join(projectDir, rel)
+	changed := false
+	err := updateConfigFile(configPath, nil, func(content string) (string, error) {
+		changed = false
+		if content == "" {
+			return content, nil
+		}
+		root, err := hujson.Parse([]byte(content))
+		if err != nil {
+			return "", fmt.Errorf("parsing %s: %w", rel, err)
+		}
+		if changed = b.sortMCPEntries(&root); !changed {
+			return content, nil
+		}
+		return string(b.finalizeConfig(&root)), nil
+	})
+	if err != nil {
+		return rel, false, err
+	}
+	return rel, changed, nil
+}
+
 // --- Shared Helpers ---
 
 // expandPath expands ~ to home directory and $VAR / $XDG_CONFIG to env values.
@@ -657,7 +1134,11 @@ func expandPath(p string) string {
 			if key == "XDG_CONFIG" {
 				return ""
 			}
-			return os.Getenv(key)
+			if v := os.Getenv(key); v != "" || key != "CODEX_HOME" {
+				return v
+			}
+			home, _ := os.UserHomeDir()
+			return filepath.Join(home, ".codex")
 		})
 	}
 
@@ -673,6 +1154,25 @@ func expandPath(p string) string {
 	return p
 }
 
+// homeRelPath expands a global path and makes it relative to the home
+// directory, the directory assets are installed into in ScopeGlobal. It
+// returns "" for "".
+func homeRelPath(p string) string {
+	if p == "" {
+		return ""
+	}
+	expanded := expandPath(p)
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return expanded
+	}
+	rel, err := filepath.Rel(home, expanded)
+	if err != nil {
+		return expanded
+	}
+	return filepath.ToSlash(rel)
+}
+
 func dirExists(path string) bool {
 	info, err := os.Stat(path)
 	return err == nil && info.IsDir()
@@ -733,8 +1233,21 @@ func writeConfigFile(path string, content string) error {
 	if err := os.MkdirAll(dir, 0o755); err != nil {
 		return fmt.Errorf("creating directory %s: %w", dir, err)
 	}
-	tmpPath := path + ".tmp"
-	if err := os.WriteFile(tmpPath, []byte(content), 0o644); err != nil {
+	// A unique temp file, so concurrent writers never share one.
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return fmt.Errorf("writing temp file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	_, err = tmp.WriteString(content)
+	if closeErr := tmp.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Chmod(tmpPath, 0o644)
+	}
+	if err != nil {
+		_ = os.Remove(tmpPath)
 		return fmt.Errorf("writing temp file: %w", err)
 	}
 	if err := os.Rename(tmpPath, path); err != nil {
@@ -744,6 +1257,178 @@ func writeConfigFile(path string, content string) error {
 	return nil
 }
 
+// detectIndent returns the indentation unit of a JSON document: the leading
+// whitespace of its first indented line, or a tab if no line is indented.
+func detectIndent(content string) string {
+	for _, line := range strings.Split(content, "\n")[1:] {
+		trimmed := strings.TrimLeft(line, " \t")
+		if trimmed == "" || len(trimmed) == len(line) {
+			continue
+		}
+		if line[0] == '\t' {
+			return "\t"
+		}
+		return line[:len(line)-len(strings.TrimLeft(line, " "))]
+	}
+	return "\t"
+}
+
+// layoutMember lays out the object member at the JSON pointer ptr, just
+// added or replaced by a patch, in the style of the members around it: on
+// its own line with its value indented by indent, or inline in an object
+// written on one line. Nothing else in the document is reformatted.
+func layoutMember(root *hujson.Value, ptr, indent string) {
+	i := strings.LastIndex(ptr, "/")
+	parent := root.Find(ptr[:i])
+	if parent == nil {
+		return
+	}
+	obj, ok := parent.Value.(*hujson.Object)
+	if !ok {
+		return
+	}
+	idx := -1
+	for j := range obj.Members {
+		if jsonPointerEscape(memberName(obj.Members[j])) == ptr[i+1:] {
+			idx = j
+		}
+	}
+	if idx < 0 {
+		return
+	}
+	m := &obj.Members[idx]
+
+	// Follow a sibling: on its own line, at its indentation, or inline.
+	prefix := strings.Repeat(indent, strings.Count(ptr, "/"))
+	multiline := true
+	for j, sib := range obj.Members {
+		if j == idx {
+			continue
+		}
+		ws := string(sib.Name.BeforeExtra)
+		nl := strings.LastIndex(ws, "\n")
+		multiline = nl >= 0
+		if multiline && strings.TrimLeft(ws[nl+1:], " \t") == "" {
+			prefix = ws[nl+1:]
+		}
+		break
+	}
+
+	var buf bytes.Buffer
+	packed := bytes.TrimSpace(m.Value.Pack())
+	var err error
+	if multiline {
+		err = json.Indent(&buf, packed, prefix, indent)
+	} else {
+		err = json.Compact(&buf, packed)
+	}
+	if err != nil {
+		return
+	}
+	value, err := hujson.Parse(buf.Bytes())
+	if err != nil {
+		return
+	}
+	value.BeforeExtra = []byte(" ")
+	value.AfterExtra = nil
+	m.Value = value
+
+	switch {
+	case multiline:
+		if !strings.Contains(string(m.Name.BeforeExtra), "\n") {
+			m.Name.BeforeExtra = []byte("\n" + prefix)
+		}
+		if !strings.Contains(string(obj.AfterExtra), "\n") {
+			obj.AfterExtra = []byte("\n" + strings.TrimSuffix(prefix, indent))
+		}
+	case idx > 0 && len(m.Name.BeforeExtra) == 0:
+		m.Name.BeforeExtra = []byte(" ")
+	}
+}
+
+// configWriteAttempts is how many times updateConfigFile reads, changes,
+// and tries to write a config file that keeps changing under it.
+const configWriteAttempts = 10
+
+// Config file locks are waited on for configLockTimeout, polling every
+// configLockPoll.
+const (
+	configLockTimeout = 10 * time.Second
+	configLockPoll    = 20 * time.Millisecond
+)
+
+// updateConfigFile applies update, a read-modify-write, to the config file
+// at path. update gets the current content ("" if the file doesn't exist)
+// and returns the new content; nothing is written if it is unchanged. With
+// preview set, the change is passed to preview instead of being written.
+//
+// Several processes may update the same file at once, e.g. a sync in CI
+// and a developer's install. The write happens under an advisory lock
+// shared by duckrow processes, and only if the file still holds what
+// update was given; otherwise it is read and updated again.
+func updateConfigFile(path string, preview func(ConfigChange), update func(content string) (string, error)) error {
+	for attempt := 1; ; attempt++ {
+		before, err := readConfigFile(path)
+		if err != nil {
+			return fmt.Errorf("reading config: %w", err)
+		}
+		after, err := update(before)
+		if err != nil || after == before {
+			return err
+		}
+		if preview != nil {
+			preview(ConfigChange{Path: path, Before: before, After: after})
+			return nil
+		}
+		written, err := writeConfigFileIfUnchanged(path, before, after)
+		if err != nil || written {
+			return err
+		}
+		if attempt == configWriteAttempts {
+			return fmt.Errorf("%s kept changing while it was being updated; try again", path)
+		}
+	}
+}
+
+// writeConfigFileIfUnchanged writes after to path under its lock, if the
+// file still holds before. It reports whether it wrote.
+func writeConfigFileIfUnchanged(path, before, after string) (bool, error) {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return false, fmt.Errorf("creating directory %s: %w", dir, err)
+	}
+	unlock, err := lockConfigFile(path)
+	if err != nil {
+		return false, err
+	}
+	defer unlock()
+
+	current, err := readConfigFile(path)
+	if err != nil {
+		return false, fmt.Errorf("reading config: %w", err)
+	}
+	if current != before {
+		return false, nil
+	}
+	return true, writeConfigFile(path, after)
+}
+
+// lockConfigFile takes the advisory lock on a config file: an OS file lock
+// on path.lock, waiting up to configLockTimeout for another process to
+// finish with it (see filelock). It returns the function that releases the
+// lock.
+func lockConfigFile(path string) (func(), error) {
+	lockPath := path + ".lock"
+	unlock, err := filelock.Lock(lockPath, 0o644, configLockTimeout, configLockPoll)
+	if errors.Is(err, filelock.ErrTimeout) {
+		return nil, fmt.Errorf("timed out waiting for lock %s; another duckrow is still updating %s", lockPath, path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("locking %s: %w", path, err)
+	}
+	return unlock, nil
+}
+
 // jsonPointerEscape escapes a string for use as a JSON Pointer token (RFC 6901).
 func jsonPointerEscape(s string) string {
 	result := make([]byte, 0, len(s))
@@ -841,28 +1526,50 @@ func parseJSONC(content string) (*hujson.Value, error) {
 	return &root, nil
 }
 
-// patchAndWrite is a shared helper for systems that override MCP installation.
-// It ensures the top-level key exists, applies an add/replace patch, and writes.
-func (b *BaseSystem) patchAndWrite(root *hujson.Value, entryPtr, valueJSON, configPath string) error {
-	op := "add"
-	if root.Find(entryPtr) != nil {
-		op = "replace"
-	}
+// writeMCPEntry adds the MCP entry name, whose value is valueJSON, to the
+// config file at configPath, or with opts.Force replaces it. It ensures the
+// top-level key exists and lays out what it adds in the file's indentation
+// style. Systems that override MCP installation share it.
+func (b *BaseSystem) writeMCPEntry(configPath, name, valueJSON string, opts InstallOptions) error {
+	entryPtr := "/" + jsonPointerEscape(b.mcpConfigKey) + "/" + jsonPointerEscape(name)
+	return updateConfigFile(configPath, opts.Preview, func(before string) (string, error) {
+		content := before
+		if content == "" {
+			content = "{}"
+		}
+		root, err := parseJSONC(content)
+		if err != nil {
+			return "", err
+		}
 
-	// Ensure the top-level config key object exists.
-	topKeyPtr := "/" + jsonPointerEscape(b.mcpConfigKey)
-	if root.Find(topKeyPtr) == nil {
-		topKeyPatch := fmt.Sprintf(`[{"op":"add","path":%q,"value":{}}]`, topKeyPtr)
-		if err := root.Patch([]byte(topKeyPatch)); err != nil {
-			return fmt.Errorf("creating config key %q: %w", b.mcpConfigKey, err)
+		op := "add"
+		if root.Find(entryPtr) != nil {
+			if !opts.Force {
+				return "", ErrAlreadyExists
+			}
+			op = "replace"
+		}
+		indent := detectIndent(before)
+
+		// Ensure the top-level config key object exists.
+		topKeyPtr := "/" + jsonPointerEscape(b.mcpConfigKey)
+		if root.Find(topKeyPtr) == nil {
+			topKeyPatch := fmt.Sprintf(`[{"op":"add","path":%q,"value":{}}]`, topKeyPtr)
+			if err := root.Patch([]byte(topKeyPatch)); err != nil {
+				return "", fmt.Errorf("creating config key %q: %w", b.mcpConfigKey, err)
+			}
+			layoutMember(root, topKeyPtr, indent)
 		}
-	}
 
-	patch := fmt.Sprintf(`[{"op":%q,"path":%q,"value":%s}]`, op, entryPtr, valueJSON)
-	if err := root.Patch([]byte(patch)); err != nil {
-		return fmt.Errorf("writing MCP entry: %w", err)
-	}
+		patch := fmt.Sprintf(`[{"op":%q,"path":%q,"value":%s}]`, op, entryPtr, valueJSON)
+		if err := root.Patch([]byte(patch)); err != nil {
+			return "", fmt.Errorf("writing MCP entry: %w", err)
+		}
+		layoutMember(root, entryPtr, indent)
+		if before == "" {
+			root.AfterExtra = []byte("\n")
+		}
 
-	output := b.finalizeConfig(root)
-	return writeConfigFile(configPath, string(output))
+		return string(b.finalizeConfig(root)), nil
+	})
 }
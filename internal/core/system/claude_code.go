@@ -15,16 +15,27 @@ func NewClaudeCode() *ClaudeCode {
 		universal:       false,
 		skillsDir:       ".claude/skills",
 		agentsDir:       ".claude/agents",
+		commandsDir:     ".claude/commands",
 		globalSkillsDir: "~/.claude/skills",
 		detectPaths:     []string{"~/.claude"},
 		configSignals:   []string{"CLAUDE.md", ".claude", ".mcp.json"},
-		supportedKinds:  []asset.Kind{asset.KindSkill, asset.KindMCP, asset.KindAgent},
+		supportedKinds:  []asset.Kind{asset.KindSkill, asset.KindMCP, asset.KindAgent, asset.KindCommand},
 		mcpConfigPath:   ".mcp.json",
 		mcpConfigKey:    "mcpServers",
+
+		globalMCPConfigPath: "~/.claude.json",
 	}}
 }
 
 // Claude Code uses the default BaseSystem behavior for both skills (symlink)
 // and MCPs (standard { "command": "...", "args": [...] } format).
 
+// InScope implements System.
+func (c *ClaudeCode) InScope(scope Scope) System {
+	if scope == ScopeGlobal {
+		return &ClaudeCode{NewClaudeCode().globalBase()}
+	}
+	return NewClaudeCode()
+}
+
 func init() { Register(NewClaudeCode()) }
@@ -23,4 +23,12 @@ func NewCodex() *Codex {
 
 // Codex is skills-only and uses the default universal BaseSystem behavior.
 
+// InScope implements System.
+func (c *Codex) InScope(scope Scope) System {
+	if scope == ScopeGlobal {
+		return &Codex{NewCodex().globalBase()}
+	}
+	return NewCodex()
+}
+
 func init() { Register(NewCodex()) }
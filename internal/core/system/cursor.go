@@ -14,17 +14,33 @@ func NewCursor() *Cursor {
 		displayName:     "Cursor",
 		universal:       false,
 		skillsDir:       ".cursor/skills",
+		commandsDir:     ".cursor/commands",
+		plainCommands:   true,
+		rulesDir:        ".cursor/rules",
+		ruleExt:         ".mdc",
 		globalSkillsDir: "~/.cursor/skills",
 		detectPaths:     []string{"~/.cursor"},
 		configSignals:   []string{".cursor"},
-		supportedKinds:  []asset.Kind{asset.KindSkill, asset.KindMCP},
+		supportedKinds:  []asset.Kind{asset.KindSkill, asset.KindMCP, asset.KindCommand, asset.KindRule},
 		mcpConfigPath:   ".cursor/mcp.json",
 		mcpConfigKey:    "mcpServers",
 		mcpConfigFormat: "jsonc",
+
+		globalMCPConfigPath: "~/.cursor/mcp.json",
 	}}
 }
 
 // Cursor uses the default BaseSystem behavior for both skills (symlink)
-// and MCPs (standard { "command": "...", "args": [...] } format).
+// and MCPs (standard { "command": "...", "args": [...] } format). Its
+// commands are plain Markdown, so they are written without frontmatter, and
+// its rules are .mdc files with description, globs, and alwaysApply.
+
+// InScope implements System.
+func (c *Cursor) InScope(scope Scope) System {
+	if scope == ScopeGlobal {
+		return &Cursor{NewCursor().globalBase()}
+	}
+	return NewCursor()
+}
 
 func init() { Register(NewCursor()) }
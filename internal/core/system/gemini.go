@@ -24,4 +24,12 @@ func NewGeminiCLI() *GeminiCLI {
 
 // Gemini CLI is skills-only and uses the default universal BaseSystem behavior.
 
+// InScope implements System.
+func (g *GeminiCLI) InScope(scope Scope) System {
+	if scope == ScopeGlobal {
+		return &GeminiCLI{NewGeminiCLI().globalBase()}
+	}
+	return NewGeminiCLI()
+}
+
 func init() { Register(NewGeminiCLI()) }
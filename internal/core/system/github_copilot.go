@@ -20,10 +20,13 @@ func NewGitHubCopilot() *GitHubCopilot {
 		skillsDir:       ".agents/skills",
 		altSkillsDirs:   []string{".github/skills"},
 		agentsDir:       ".github/agents",
+		agentExt:        ".agent.md",
+		rulesDir:        ".github/instructions",
+		ruleExt:         ".instructions.md",
 		globalSkillsDir: "~/.copilot/skills",
 		detectPaths:     []string{"~/.copilot"},
 		configSignals:   []string{".github/copilot-instructions.md", ".vscode/mcp.json"},
-		supportedKinds:  []asset.Kind{asset.KindSkill, asset.KindMCP, asset.KindAgent},
+		supportedKinds:  []asset.Kind{asset.KindSkill, asset.KindMCP, asset.KindAgent, asset.KindRule},
 		mcpConfigPath:   ".vscode/mcp.json",
 		mcpConfigKey:    "servers",
 		mcpConfigFormat: "jsonc",
@@ -46,24 +49,6 @@ func (g *GitHubCopilot) installMCPCopilot(a asset.Asset, projectDir string, opts
 
 	configPath := g.resolveMCPConfigPath(projectDir)
 
-	content, err := readConfigFile(configPath)
-	if err != nil {
-		return err
-	}
-	if content == "" {
-		content = "{}"
-	}
-
-	root, err := parseJSONC(content)
-	if err != nil {
-		return err
-	}
-
-	entryPtr := "/" + jsonPointerEscape(g.mcpConfigKey) + "/" + jsonPointerEscape(a.Name)
-	if root.Find(entryPtr) != nil && !opts.Force {
-		return ErrAlreadyExists
-	}
-
 	var mcpValueJSON string
 	if meta.IsStdio() {
 		// GitHub Copilot: { "type": "stdio", "command": "duckrow", "args": [...] }
@@ -76,7 +61,7 @@ func (g *GitHubCopilot) installMCPCopilot(a asset.Asset, projectDir string, opts
 			"command": "duckrow",
 			"args":    wrapperArgs,
 		}
-		data, _ := json.MarshalIndent(m, "\t\t", "\t")
+		data, _ := json.Marshal(m)
 		mcpValueJSON = string(data)
 	} else {
 		mcpType := meta.Transport
@@ -87,11 +72,19 @@ func (g *GitHubCopilot) installMCPCopilot(a asset.Asset, projectDir string, opts
 			"type": mcpType,
 			"url":  meta.URL,
 		}
-		data, _ := json.MarshalIndent(m, "\t\t", "\t")
+		data, _ := json.Marshal(m)
 		mcpValueJSON = string(data)
 	}
 
-	return g.patchAndWrite(root, entryPtr, mcpValueJSON, configPath)
+	return g.writeMCPEntry(configPath, a.Name, mcpValueJSON, opts)
+}
+
+// InScope implements System.
+func (g *GitHubCopilot) InScope(scope Scope) System {
+	if scope == ScopeGlobal {
+		return &GitHubCopilot{NewGitHubCopilot().globalBase()}
+	}
+	return NewGitHubCopilot()
 }
 
 func init() { Register(NewGitHubCopilot()) }
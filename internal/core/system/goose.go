@@ -24,4 +24,12 @@ func NewGoose() *Goose {
 // Goose is skills-only, non-universal. Uses default BaseSystem behavior
 // (symlink from .goose/skills/ to .agents/skills/).
 
+// InScope implements System.
+func (g *Goose) InScope(scope Scope) System {
+	if scope == ScopeGlobal {
+		return &Goose{NewGoose().globalBase()}
+	}
+	return NewGoose()
+}
+
 func init() { Register(NewGoose()) }
@@ -20,14 +20,18 @@ func NewOpenCode() *OpenCode {
 		skillsDir:        ".agents/skills",
 		altSkillsDirs:    []string{".opencode/skills"},
 		agentsDir:        ".opencode/agents",
+		commandsDir:      ".opencode/command",
 		globalSkillsDir:  "$XDG_CONFIG/opencode/skills",
 		detectPaths:      []string{"$XDG_CONFIG/opencode"},
 		configSignals:    []string{"opencode.json", "opencode.jsonc"},
-		supportedKinds:   []asset.Kind{asset.KindSkill, asset.KindMCP, asset.KindAgent},
+		supportedKinds:   []asset.Kind{asset.KindSkill, asset.KindMCP, asset.KindAgent, asset.KindCommand},
 		mcpConfigPath:    "opencode.json",
 		mcpConfigPathAlt: "opencode.jsonc",
 		mcpConfigKey:     "mcp",
 		mcpConfigFormat:  "jsonc",
+
+		globalMCPConfigPath:    "$XDG_CONFIG/opencode/opencode.json",
+		globalMCPConfigPathAlt: "$XDG_CONFIG/opencode/opencode.jsonc",
 	}}
 }
 
@@ -47,24 +51,6 @@ func (o *OpenCode) installMCPOpenCode(a asset.Asset, projectDir string, opts Ins
 
 	configPath := o.resolveMCPConfigPath(projectDir)
 
-	content, err := readConfigFile(configPath)
-	if err != nil {
-		return err
-	}
-	if content == "" {
-		content = "{}"
-	}
-
-	root, err := parseJSONC(content)
-	if err != nil {
-		return err
-	}
-
-	entryPtr := "/" + jsonPointerEscape(o.mcpConfigKey) + "/" + jsonPointerEscape(a.Name)
-	if root.Find(entryPtr) != nil && !opts.Force {
-		return ErrAlreadyExists
-	}
-
 	var mcpValueJSON string
 	if meta.IsStdio() {
 		// OpenCode: { "type": "local", "command": ["duckrow", "env", "--mcp", ...] }
@@ -77,7 +63,7 @@ func (o *OpenCode) installMCPOpenCode(a asset.Asset, projectDir string, opts Ins
 			"type":    "local",
 			"command": cmdArray,
 		}
-		data, _ := json.MarshalIndent(m, "\t\t", "\t")
+		data, _ := json.Marshal(m)
 		mcpValueJSON = string(data)
 	} else {
 		// OpenCode: { "type": "remote", "url": "..." }
@@ -85,11 +71,19 @@ func (o *OpenCode) installMCPOpenCode(a asset.Asset, projectDir string, opts Ins
 			"type": "remote",
 			"url":  meta.URL,
 		}
-		data, _ := json.MarshalIndent(m, "\t\t", "\t")
+		data, _ := json.Marshal(m)
 		mcpValueJSON = string(data)
 	}
 
-	return o.patchAndWrite(root, entryPtr, mcpValueJSON, configPath)
+	return o.writeMCPEntry(configPath, a.Name, mcpValueJSON, opts)
+}
+
+// InScope implements System.
+func (o *OpenCode) InScope(scope Scope) System {
+	if scope == ScopeGlobal {
+		return &OpenCode{NewOpenCode().globalBase()}
+	}
+	return NewOpenCode()
 }
 
 func init() { Register(NewOpenCode()) }
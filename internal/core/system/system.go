@@ -12,12 +12,18 @@ import (
 	"strings"
 
 	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/rogpeppe/go-internal/diff"
 )
 
 // ErrAlreadyExists is returned by Install when an asset is already present
 // and Force is not set. Consumers should treat this as a skip, not a failure.
 var ErrAlreadyExists = errors.New("already exists")
 
+// ErrUnmanaged is returned by Remove for an MCP config entry that duckrow
+// didn't write, e.g. one the user added by hand before adopting duckrow.
+// See BaseSystem.RemoveMCP to remove it anyway.
+var ErrUnmanaged = errors.New("not written by duckrow")
+
 // System defines how an AI coding tool integrates with duckrow.
 // Each system is a self-contained unit that knows its own paths,
 // detection logic, config formats, and how to accept assets.
@@ -44,14 +50,59 @@ type System interface {
 
 	// Paths
 	AssetDir(kind asset.Kind, projectDir string) string
+	AssetPath(kind asset.Kind, name string, projectDir string) string
 
 	// Classification
 	IsUniversal() bool // shares .agents/skills/ directly
+
+	// Scope
+	Scope() Scope               // where the system installs assets
+	InScope(scope Scope) System // the system installing to scope's locations
+}
+
+// Scope is where a system installs assets: into a project, or for the user
+// across every project.
+type Scope int
+
+const (
+	// ScopeProject installs into the project directory passed to Install.
+	ScopeProject Scope = iota
+	// ScopeGlobal installs into the user-level locations a system reads
+	// for every project (~/.claude/skills, ~/.cursor/mcp.json, ...). The
+	// directory passed to Install is the user's home directory.
+	ScopeGlobal
+)
+
+// String returns "project" or "global".
+func (s Scope) String() string {
+	if s == ScopeGlobal {
+		return "global"
+	}
+	return "project"
 }
 
 // InstallOptions for system-level installation.
 type InstallOptions struct {
 	Force bool
+	Copy  bool // copy skills instead of symlinking them to the canonical copy
+
+	// Preview makes an MCP install a dry run: the change to the config
+	// file is passed to Preview instead of being written.
+	Preview func(ConfigChange)
+}
+
+// ConfigChange is the change an MCP install makes to a system's config
+// file, as reported to InstallOptions.Preview.
+type ConfigChange struct {
+	Path   string // config file path
+	Before string // current content; "" if the file doesn't exist
+	After  string // content the install writes
+}
+
+// Diff returns a unified diff of the change, with name (typically the
+// project-relative path) in its headers, or nil if nothing changes.
+func (c ConfigChange) Diff(name string) []byte {
+	return diff.Diff("a/"+name, []byte(c.Before), "b/"+name, []byte(c.After))
 }
 
 // --- Registry ---
@@ -175,6 +226,18 @@ func NonUniversal() []System {
 	return result
 }
 
+// InScope returns systems as they install to scope, leaving out those
+// with no location for any asset kind there.
+func InScope(systems []System, scope Scope) []System {
+	var result []System
+	for _, s := range systems {
+		if scoped := s.InScope(scope); len(scoped.SupportedKinds()) > 0 {
+			result = append(result, scoped)
+		}
+	}
+	return result
+}
+
 // Names returns the machine names of the given systems.
 func Names(systems []System) []string {
 	names := make([]string, len(systems))
@@ -1,9 +1,15 @@
 package system
 
 import (
+	"errors"
+	"fmt"
 	"os"
 	"path/filepath"
+	"reflect"
+	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/barysiuk/duckrow/internal/core/asset"
 )
@@ -234,6 +240,70 @@ func TestExpandPath(t *testing.T) {
 	}
 }
 
+func TestInScope_Global(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+	t.Setenv("CODEX_HOME", "")
+
+	tests := []struct {
+		name      string
+		skillDir  string // relative to home; "" = no global skills
+		mcpConfig string // relative to home; "" = no global MCPs
+	}{
+		{"claude-code", ".claude/skills", ".claude.json"},
+		{"cursor", ".cursor/skills", ".cursor/mcp.json"},
+		{"opencode", ".config/opencode/skills", ".config/opencode/opencode.json"},
+		{"codex", ".codex/skills", ""},
+		{"github-copilot", ".copilot/skills", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			project, _ := ByName(tt.name)
+			sys := project.InScope(ScopeGlobal)
+			if sys.Scope() != ScopeGlobal || sys.Name() != tt.name {
+				t.Fatalf("InScope(ScopeGlobal) = %s in %s", sys.Name(), sys.Scope())
+			}
+			if sys.IsUniversal() {
+				t.Error("expected no system to share ~/.agents/skills globally")
+			}
+			if got, want := sys.AssetDir(asset.KindSkill, home), filepath.Join(home, tt.skillDir); got != want {
+				t.Errorf("AssetDir(skill) = %q, want %q", got, want)
+			}
+			if sys.Supports(asset.KindAgent) || sys.Supports(asset.KindCommand) || sys.Supports(asset.KindRule) {
+				t.Errorf("expected only skills and MCPs globally, got %v", sys.SupportedKinds())
+			}
+			if sys.Supports(asset.KindMCP) != (tt.mcpConfig != "") {
+				t.Fatalf("Supports(mcp) = %v", sys.Supports(asset.KindMCP))
+			}
+			if tt.mcpConfig == "" {
+				return
+			}
+			err := sys.Install(asset.Asset{
+				Kind: asset.KindMCP,
+				Name: "db",
+				Meta: asset.MCPMeta{Command: "psql"},
+			}, home, InstallOptions{})
+			if err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+			data, err := os.ReadFile(filepath.Join(home, tt.mcpConfig))
+			if err != nil || !strings.Contains(string(data), `"db"`) {
+				t.Errorf("expected db in %s, got %q (%v)", tt.mcpConfig, data, err)
+			}
+
+			if back := sys.InScope(ScopeProject); back.Scope() != ScopeProject || !back.Supports(asset.KindMCP) {
+				t.Errorf("InScope(ScopeProject) = %s in %s", back.Name(), back.Scope())
+			}
+		})
+	}
+
+	// Every system has a global skill directory, so none is left out.
+	if got := len(InScope(All(), ScopeGlobal)); got != len(All()) {
+		t.Errorf("InScope(All(), ScopeGlobal) returned %d systems, want %d", got, len(All()))
+	}
+}
+
 func TestSanitizeName(t *testing.T) {
 	tests := []struct {
 		input string
@@ -255,3 +325,664 @@ func TestSanitizeName(t *testing.T) {
 		})
 	}
 }
+
+func TestInstallMCP_SortsEntries(t *testing.T) {
+	dir := t.TempDir()
+	claude, _ := ByName("claude-code")
+
+	for _, name := range []string{"zeta", "alpha", "mid"} {
+		a := asset.Asset{Kind: asset.KindMCP, Name: name, Meta: asset.MCPMeta{Command: name}}
+		if err := claude.Install(a, dir, InstallOptions{}); err != nil {
+			t.Fatalf("Install(%s) error = %v", name, err)
+		}
+	}
+
+	data, err := os.ReadFile(filepath.Join(dir, ".mcp.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if !(strings.Index(content, `"alpha"`) < strings.Index(content, `"mid"`) &&
+		strings.Index(content, `"mid"`) < strings.Index(content, `"zeta"`)) {
+		t.Errorf("entries not sorted by name:\n%s", content)
+	}
+}
+
+func TestInstallMCP_PreservesFormatting(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".cursor", "mcp.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := `{
+  "someOtherKey": "v",
+  "mcpServers": {
+    "zz": {"command": "zz", "args": ["--flag"]}
+  }
+}
+`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cursor, _ := ByName("cursor")
+
+	a := asset.Asset{Kind: asset.KindMCP, Name: "db", Meta: asset.MCPMeta{URL: "https://db.example.com"}}
+	if err := cursor.Install(a, dir, InstallOptions{}); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := `{
+  "someOtherKey": "v",
+  "mcpServers": {
+    "db": {
+      "type": "http",
+      "url": "https://db.example.com"
+    },
+    "zz": {"command": "zz", "args": ["--flag"]}
+  }
+}
+`
+	if string(data) != want {
+		t.Errorf("config =\n%s\nwant\n%s", data, want)
+	}
+
+	// Removing the entry leaves the file as it was.
+	if err := cursor.Remove(asset.KindMCP, "db", dir); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if data, _ := os.ReadFile(path); string(data) != config {
+		t.Errorf("config after Remove() =\n%s\nwant\n%s", data, config)
+	}
+}
+
+func TestDetectIndent(t *testing.T) {
+	tests := []struct{ content, want string }{
+		{"", "\t"},
+		{"{}", "\t"},
+		{"{\n  \"a\": 1\n}\n", "  "},
+		{"{\n\n    \"a\": 1\n}\n", "    "},
+		{"{\n\t\"a\": 1\n}\n", "\t"},
+	}
+	for _, tt := range tests {
+		if got := detectIndent(tt.content); got != tt.want {
+			t.Errorf("detectIndent(%q) = %q, want %q", tt.content, got, tt.want)
+		}
+	}
+}
+
+func TestInstallMCP_Concurrent(t *testing.T) {
+	dir := t.TempDir()
+	cursor, _ := ByName("cursor")
+
+	// Installs racing on one config file each keep their entry.
+	const n = 20
+	var wg sync.WaitGroup
+	errs := make([]error, n)
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			a := asset.Asset{Kind: asset.KindMCP, Name: fmt.Sprintf("mcp-%02d", i), Meta: asset.MCPMeta{Command: "x"}}
+			errs[i] = cursor.Install(a, dir, InstallOptions{})
+		}(i)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Errorf("Install(mcp-%02d) error = %v", i, err)
+		}
+	}
+
+	h := cursor.(interface {
+		HasMCP(name, projectDir string) bool
+	})
+	for i := 0; i < n; i++ {
+		if name := fmt.Sprintf("mcp-%02d", i); !h.HasMCP(name, dir) {
+			t.Errorf("%s lost to a concurrent write", name)
+		}
+	}
+	entries, _ := os.ReadDir(filepath.Join(dir, ".cursor"))
+	if len(entries) != 1 {
+		t.Errorf(".cursor holds %d files, want only mcp.json (lock or temp files left behind)", len(entries))
+	}
+}
+
+func TestLockConfigFile_BreaksStaleLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.json")
+	if err := os.WriteFile(path+".lock", []byte("1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * configLockTimeout)
+	if err := os.Chtimes(path+".lock", old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	unlock, err := lockConfigFile(path)
+	if err != nil {
+		t.Fatalf("lockConfigFile() error = %v", err)
+	}
+	unlock()
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock not released: %v", err)
+	}
+}
+
+func TestLockConfigFile_StaleLockTwoBreakers(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "mcp.json")
+	if err := os.WriteFile(path+".lock", []byte("1\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	old := time.Now().Add(-2 * configLockTimeout)
+	if err := os.Chtimes(path+".lock", old, old); err != nil {
+		t.Fatal(err)
+	}
+
+	// Both find the same leftover lock file, and every lock they take looks
+	// just as old; only one may hold it at a time.
+	var mu sync.Mutex
+	holders, most := 0, 0
+	var wg sync.WaitGroup
+	for i := 0; i < 2; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 20; j++ {
+				unlock, err := lockConfigFile(path)
+				if err != nil {
+					t.Errorf("lockConfigFile() error = %v", err)
+					return
+				}
+				mu.Lock()
+				holders++
+				most = max(most, holders)
+				mu.Unlock()
+				_ = os.Chtimes(path+".lock", old, old)
+				time.Sleep(time.Millisecond)
+				mu.Lock()
+				holders--
+				mu.Unlock()
+				unlock()
+			}
+		}()
+	}
+	wg.Wait()
+	if most != 1 {
+		t.Errorf("%d breakers held the lock at once, want 1", most)
+	}
+	if _, err := os.Stat(path + ".lock"); !os.IsNotExist(err) {
+		t.Errorf("lock not released: %v", err)
+	}
+}
+
+func TestInstallMCP_Preview(t *testing.T) {
+	dir := t.TempDir()
+	a := asset.Asset{Kind: asset.KindMCP, Name: "db", Meta: asset.MCPMeta{Command: "psql"}}
+
+	// Cursor goes through BaseSystem; OpenCode overrides MCP installation.
+	for _, tc := range []struct{ system, file string }{
+		{"cursor", ".cursor/mcp.json"},
+		{"opencode", "opencode.json"},
+	} {
+		sys, _ := ByName(tc.system)
+		var changes []ConfigChange
+		opts := InstallOptions{Preview: func(c ConfigChange) { changes = append(changes, c) }}
+		if err := sys.Install(a, dir, opts); err != nil {
+			t.Fatalf("%s: Install() error = %v", tc.system, err)
+		}
+		if len(changes) != 1 {
+			t.Fatalf("%s: got %d previewed changes, want 1", tc.system, len(changes))
+		}
+		c := changes[0]
+		if c.Path != filepath.Join(dir, tc.file) || c.Before != "" || !strings.Contains(c.After, `"db"`) {
+			t.Errorf("%s: change = %+v", tc.system, c)
+		}
+		if d := string(c.Diff(tc.file)); !strings.Contains(d, "+++ b/"+tc.file) {
+			t.Errorf("%s: Diff() = %q", tc.system, d)
+		}
+		if _, err := os.Stat(filepath.Join(dir, tc.file)); !os.IsNotExist(err) {
+			t.Errorf("%s: preview wrote %s", tc.system, tc.file)
+		}
+	}
+
+	if d := (ConfigChange{Before: "{}\n", After: "{}\n"}).Diff("x.json"); d != nil {
+		t.Errorf("Diff() of an unchanged file = %q, want nil", d)
+	}
+}
+
+func TestInstallSkill_Copy(t *testing.T) {
+	dir := t.TempDir()
+	canonical := filepath.Join(dir, ".agents/skills/lint")
+	if err := os.MkdirAll(canonical, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(canonical, "SKILL.md"), []byte("---\nname: lint\n---\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	claude, _ := ByName("claude-code")
+
+	a := asset.Asset{Kind: asset.KindSkill, Name: "lint"}
+	if err := claude.Install(a, dir, InstallOptions{Copy: true}); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	info, err := os.Lstat(filepath.Join(dir, ".claude/skills/lint"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.IsDir() {
+		t.Errorf("copy strategy left mode %v, want a real directory", info.Mode())
+	}
+	if _, err := os.Stat(filepath.Join(dir, ".claude/skills/lint/SKILL.md")); err != nil {
+		t.Errorf("copied skill missing SKILL.md: %v", err)
+	}
+}
+
+func TestNormalizeMCPConfig(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".cursor", "mcp.json")
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := `{
+	"mcpServers": {
+		"zeta": {"command": "zeta"},
+		// The database
+		"db": {"command": "db"}
+	},
+	"other": true
+}
+`
+	if err := os.WriteFile(path, []byte(config), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	cursor, _ := ByName("cursor")
+	n := cursor.(interface {
+		NormalizeMCPConfig(projectDir string) (string, bool, error)
+	})
+	rel, changed, err := n.NormalizeMCPConfig(dir)
+	if err != nil {
+		t.Fatalf("NormalizeMCPConfig() error = %v", err)
+	}
+	if rel != ".cursor/mcp.json" || !changed {
+		t.Fatalf("NormalizeMCPConfig() = %q, %v, want .cursor/mcp.json, true", rel, changed)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	content := string(data)
+	if !strings.Contains(content, "// The database\n\t\t\"db\"") {
+		t.Errorf("comment did not move with its entry:\n%s", content)
+	}
+	if strings.Index(content, `"db"`) > strings.Index(content, `"zeta"`) {
+		t.Errorf("entries not sorted by name:\n%s", content)
+	}
+
+	// Already sorted: nothing to do.
+	if _, changed, err := n.NormalizeMCPConfig(dir); err != nil || changed {
+		t.Errorf("second NormalizeMCPConfig() = %v, %v, want unchanged", changed, err)
+	}
+}
+
+func TestHasMCP(t *testing.T) {
+	dir := t.TempDir()
+	claude, _ := ByName("claude-code")
+	h := claude.(interface {
+		HasMCP(name string, projectDir string) bool
+	})
+
+	if h.HasMCP("db", dir) {
+		t.Error("HasMCP() = true without a config file")
+	}
+	a := asset.Asset{Kind: asset.KindMCP, Name: "db", Meta: asset.MCPMeta{Command: "db"}}
+	if err := claude.Install(a, dir, InstallOptions{}); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if !h.HasMCP("db", dir) {
+		t.Error("HasMCP(db) = false after install")
+	}
+	if h.HasMCP("other", dir) {
+		t.Error("HasMCP(other) = true")
+	}
+}
+
+func TestMCPConfigChanges(t *testing.T) {
+	claude, _ := ByName("claude-code")
+	c := claude.(interface {
+		MCPConfigChanges(before, after string) ([]string, bool, error)
+	})
+	head := `{
+  // team servers
+  "mcpServers": {
+    "db": {"command": "psql"},
+    "docs": {"command": "docs"}
+  }
+}`
+
+	tests := []struct {
+		name      string
+		before    string
+		after     string
+		wantNames []string
+		wantOther bool
+	}{
+		{"unchanged but reformatted", head, `{"mcpServers": {"docs": {"command": "docs"}, "db": {"command": "psql"}}}`, nil, false},
+		{"new file", "", `{"mcpServers": {"db": {"command": "psql"}}}`, []string{"db"}, false},
+		{"added and removed", head, `{"mcpServers": {"db": {"command": "psql"}, "web": {"command": "web"}}}`, []string{"docs", "web"}, false},
+		{"entry edited", head, `{"mcpServers": {"db": {"command": "pg"}, "docs": {"command": "docs"}}}`, []string{"db"}, false},
+		{"other key edited", head, `{"model": "x", "mcpServers": {"db": {"command": "psql"}, "docs": {"command": "docs"}}}`, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			names, other, err := c.MCPConfigChanges(tt.before, tt.after)
+			if err != nil {
+				t.Fatalf("MCPConfigChanges() error = %v", err)
+			}
+			if !reflect.DeepEqual(names, tt.wantNames) || other != tt.wantOther {
+				t.Errorf("MCPConfigChanges() = %v, %v; want %v, %v", names, other, tt.wantNames, tt.wantOther)
+			}
+		})
+	}
+
+	if _, _, err := c.MCPConfigChanges(head, "{"); err == nil {
+		t.Error("MCPConfigChanges() error = nil for invalid JSON")
+	}
+}
+
+func TestRemoveMCP_Unmanaged(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".mcp.json")
+	hand := `{
+  "mcpServers": {
+    "db": {"command": "psql"},
+    "docs": {"type": "http", "url": "https://docs.example.com/mcp", "headers": {"X-Key": "k"}}
+  }
+}
+`
+	if err := os.WriteFile(configPath, []byte(hand), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	claude, _ := ByName("claude-code")
+	r := claude.(interface {
+		RemoveMCP(name string, projectDir string, opts RemoveMCPOptions) error
+		ManagesMCP(name string, projectDir string, urlHash string) bool
+	})
+
+	for _, name := range []string{"db", "docs"} {
+		if r.ManagesMCP(name, dir, "") {
+			t.Errorf("ManagesMCP(%s) = true for a hand-written entry", name)
+		}
+		if err := claude.Remove(asset.KindMCP, name, dir); !errors.Is(err, ErrUnmanaged) {
+			t.Errorf("Remove(%s) error = %v, want ErrUnmanaged", name, err)
+		}
+	}
+	if got, _ := os.ReadFile(configPath); string(got) != hand {
+		t.Errorf("config changed by a refused Remove:\n%s", got)
+	}
+
+	// Entries duckrow writes are its own, stdio and remote alike.
+	for _, a := range []asset.Asset{
+		{Kind: asset.KindMCP, Name: "local", Meta: asset.MCPMeta{Command: "npx"}},
+		{Kind: asset.KindMCP, Name: "remote", Meta: asset.MCPMeta{URL: "https://mcp.example.com", Transport: "sse"}},
+	} {
+		if err := claude.Install(a, dir, InstallOptions{}); err != nil {
+			t.Fatalf("Install(%s) error = %v", a.Name, err)
+		}
+		if !r.ManagesMCP(a.Name, dir, "") {
+			t.Errorf("ManagesMCP(%s) = false after install", a.Name)
+		}
+		if err := claude.Remove(asset.KindMCP, a.Name, dir); err != nil {
+			t.Errorf("Remove(%s) error = %v", a.Name, err)
+		}
+	}
+
+	if err := r.RemoveMCP("db", dir, RemoveMCPOptions{TakeOwnership: true}); err != nil {
+		t.Fatalf("RemoveMCP(db, takeOwnership) error = %v", err)
+	}
+	if got, _ := os.ReadFile(configPath); strings.Contains(string(got), `"db"`) {
+		t.Errorf("db still in config after RemoveMCP with takeOwnership:\n%s", got)
+	}
+}
+
+func TestRemoveMCP_URLHash(t *testing.T) {
+	dir := t.TempDir()
+	configPath := filepath.Join(dir, ".mcp.json")
+	hand := `{
+  "mcpServers": {
+    "docs": {"type": "http", "url": "https://docs.example.com/mcp"},
+    "search": {"type": "http", "url": "https://search.example.com/mcp", "headers": {"X-Key": "k"}}
+  }
+}
+`
+	if err := os.WriteFile(configPath, []byte(hand), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	claude, _ := ByName("claude-code")
+	r := claude.(interface {
+		RemoveMCP(name string, projectDir string, opts RemoveMCPOptions) error
+		ManagesMCP(name string, projectDir string, urlHash string) bool
+	})
+
+	// A hand-written entry shaped like duckrow's is not ours when its URL
+	// is not the one in the lock.
+	locked := asset.URLHash("https://mcp.example.com")
+	if r.ManagesMCP("docs", dir, locked) {
+		t.Error("ManagesMCP(docs) = true for a URL the lock doesn't have")
+	}
+	if err := r.RemoveMCP("docs", dir, RemoveMCPOptions{URLHash: locked}); !errors.Is(err, ErrUnmanaged) {
+		t.Errorf("RemoveMCP(docs) error = %v, want ErrUnmanaged", err)
+	}
+
+	// Ours when the URL matches, even after the user added headers.
+	urlHash := asset.URLHash("https://search.example.com/mcp")
+	if !r.ManagesMCP("search", dir, urlHash) {
+		t.Error("ManagesMCP(search) = false for the locked URL")
+	}
+	if err := r.RemoveMCP("search", dir, RemoveMCPOptions{URLHash: urlHash}); err != nil {
+		t.Fatalf("RemoveMCP(search) error = %v", err)
+	}
+	got, _ := os.ReadFile(configPath)
+	if strings.Contains(string(got), `"search"`) || !strings.Contains(string(got), `"docs"`) {
+		t.Errorf("config after RemoveMCP(search):\n%s", got)
+	}
+}
+
+func TestInstallCommand(t *testing.T) {
+	dir := t.TempDir()
+	raw := "---\ndescription: Review the diff\n---\n\nReview $ARGUMENTS.\n"
+	data, err := asset.ParseCommandContent([]byte(raw), "review.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := asset.Asset{Kind: asset.KindCommand, Name: "review", Meta: asset.CommandDataMeta{Data: data}}
+
+	tests := []struct {
+		system string
+		path   string
+		want   string
+	}{
+		{"claude-code", ".claude/commands/review.md", raw},
+		{"opencode", ".opencode/command/review.md", raw},
+		// Cursor shows frontmatter as prompt text, so only the prompt is written.
+		{"cursor", ".cursor/commands/review.md", "Review $ARGUMENTS.\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.system, func(t *testing.T) {
+			sys, ok := ByName(tt.system)
+			if !ok {
+				t.Fatalf("system %q not registered", tt.system)
+			}
+			if !sys.Supports(asset.KindCommand) {
+				t.Fatalf("%s does not support commands", tt.system)
+			}
+			if err := sys.Install(a, dir, InstallOptions{}); err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+			got, err := os.ReadFile(filepath.Join(dir, tt.path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("%s = %q, want %q", tt.path, got, tt.want)
+			}
+
+			installed, err := sys.Scan(asset.KindCommand, dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(installed) != 1 || installed[0].Name != "review" {
+				t.Errorf("Scan() = %v, want review", installed)
+			}
+
+			if err := sys.Remove(asset.KindCommand, "review", dir); err != nil {
+				t.Fatalf("Remove() error = %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, tt.path)); !os.IsNotExist(err) {
+				t.Errorf("%s still exists after Remove()", tt.path)
+			}
+		})
+	}
+}
+
+func TestInstallAgent(t *testing.T) {
+	dir := t.TempDir()
+	data := &asset.AgentData{
+		Frontmatter: map[string]any{"name": "reviewer", "description": "Reviews code", "tools": []any{"Read"}},
+		Body:        "You review code.\n",
+	}
+	a := asset.Asset{Kind: asset.KindAgent, Name: "reviewer", Meta: asset.AgentDataMeta{Data: data}}
+
+	tests := []struct {
+		system string
+		path   string
+		want   string
+	}{
+		{"claude-code", ".claude/agents/reviewer.md", "name: reviewer\ndescription: Reviews code\ntools: Read\n"},
+		{"opencode", ".opencode/agents/reviewer.md", "description: Reviews code\ntools:\n  Read: true\n"},
+		{"github-copilot", ".github/agents/reviewer.agent.md", "description: Reviews code\ntools:\n  - Read\n"},
+		{"gemini-cli", ".gemini/agents/reviewer.md", "name: reviewer\ndescription: Reviews code\ntools:\n  - Read\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.system, func(t *testing.T) {
+			sys, ok := ByName(tt.system)
+			if !ok {
+				t.Fatalf("system %q not registered", tt.system)
+			}
+			if got := sys.AssetPath(asset.KindAgent, "reviewer", dir); got != filepath.Join(dir, tt.path) {
+				t.Errorf("AssetPath() = %q, want %q", got, filepath.Join(dir, tt.path))
+			}
+			if err := sys.Install(a, dir, InstallOptions{}); err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+			got, err := os.ReadFile(filepath.Join(dir, tt.path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(got), tt.want) {
+				t.Errorf("%s = %q, want it to contain %q", tt.path, got, tt.want)
+			}
+
+			installed, err := sys.Scan(asset.KindAgent, dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(installed) != 1 || installed[0].Name != "reviewer" {
+				t.Errorf("Scan() = %v, want reviewer", installed)
+			}
+
+			if err := sys.Remove(asset.KindAgent, "reviewer", dir); err != nil {
+				t.Fatalf("Remove() error = %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, tt.path)); !os.IsNotExist(err) {
+				t.Errorf("%s still exists after Remove()", tt.path)
+			}
+		})
+	}
+}
+
+func TestInstallAgent_ReplacesLegacyFile(t *testing.T) {
+	dir := t.TempDir()
+	legacy := filepath.Join(dir, ".github/agents/reviewer.md")
+	if err := os.MkdirAll(filepath.Dir(legacy), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(legacy, []byte("---\ndescription: Reviews code\n---\n\nOld.\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	sys, _ := ByName("github-copilot")
+	installed, err := sys.Scan(asset.KindAgent, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(installed) != 1 || installed[0].Name != "reviewer" {
+		t.Fatalf("Scan() = %v, want the legacy reviewer", installed)
+	}
+
+	data := &asset.AgentData{Frontmatter: map[string]any{"description": "Reviews code"}, Body: "New.\n"}
+	a := asset.Asset{Kind: asset.KindAgent, Name: "reviewer", Meta: asset.AgentDataMeta{Data: data}}
+	if err := sys.Install(a, dir, InstallOptions{}); err != nil {
+		t.Fatalf("Install() error = %v", err)
+	}
+	if _, err := os.Stat(legacy); !os.IsNotExist(err) {
+		t.Error("legacy reviewer.md still exists after Install()")
+	}
+	if !pathExists(filepath.Join(dir, ".github/agents/reviewer.agent.md")) {
+		t.Error("reviewer.agent.md not written")
+	}
+}
+
+func TestInstallRule(t *testing.T) {
+	dir := t.TempDir()
+	data := &asset.RuleData{Description: "Go style", Globs: []string{"*.go"}, Body: "Use gofmt.\n"}
+	a := asset.Asset{Kind: asset.KindRule, Name: "go-style", Meta: asset.RuleDataMeta{Data: data}}
+
+	tests := []struct {
+		system string
+		path   string
+		want   string
+	}{
+		{"cursor", ".cursor/rules/go-style.mdc", "globs: *.go"},
+		{"github-copilot", ".github/instructions/go-style.instructions.md", "applyTo: '*.go'"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.system, func(t *testing.T) {
+			sys, ok := ByName(tt.system)
+			if !ok {
+				t.Fatalf("system %q not registered", tt.system)
+			}
+			if got := sys.AssetPath(asset.KindRule, "go-style", dir); got != filepath.Join(dir, tt.path) {
+				t.Errorf("AssetPath() = %q, want %q", got, filepath.Join(dir, tt.path))
+			}
+			if err := sys.Install(a, dir, InstallOptions{}); err != nil {
+				t.Fatalf("Install() error = %v", err)
+			}
+			got, err := os.ReadFile(filepath.Join(dir, tt.path))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !strings.Contains(string(got), tt.want) {
+				t.Errorf("%s = %q, want it to contain %q", tt.path, got, tt.want)
+			}
+
+			installed, err := sys.Scan(asset.KindRule, dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(installed) != 1 || installed[0].Name != "go-style" || installed[0].Description != "Go style" {
+				t.Errorf("Scan() = %+v, want go-style", installed)
+			}
+
+			if err := sys.Remove(asset.KindRule, "go-style", dir); err != nil {
+				t.Fatalf("Remove() error = %v", err)
+			}
+			if _, err := os.Stat(filepath.Join(dir, tt.path)); !os.IsNotExist(err) {
+				t.Errorf("%s still exists after Remove()", tt.path)
+			}
+		})
+	}
+}
@@ -0,0 +1,258 @@
+package core
+
+import (
+	"bytes"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"sort"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// templateProjectDir is the directory of a template repo whose files are
+// copied into the project as-is, e.g. AGENTS.md or .cursor/rules/.
+const templateProjectDir = "project"
+
+// TemplateMode is how a template is applied to a project that already has
+// a lock file.
+type TemplateMode string
+
+const (
+	// TemplateMerge adds the template's entries and files that the project
+	// doesn't have yet. Entries and files the project already has win. The
+	// default.
+	TemplateMerge TemplateMode = "merge"
+	// TemplateReplace makes the project's lock file the template's, and
+	// overwrites project files with the template's.
+	TemplateReplace TemplateMode = "replace"
+)
+
+// ParseTemplateMode validates a template mode. An empty mode is the default.
+func ParseTemplateMode(s string) (TemplateMode, error) {
+	switch TemplateMode(s) {
+	case "", TemplateMerge:
+		return TemplateMerge, nil
+	case TemplateReplace:
+		return TemplateReplace, nil
+	}
+	return "", fmt.Errorf("unknown template mode %q (want %q or %q)", s, TemplateMerge, TemplateReplace)
+}
+
+// Template is a project template: a repo holding a duckrow.lock.json and,
+// optionally, a project/ directory of files to copy into the project.
+type Template struct {
+	Lock *LockFile
+
+	// Files maps the project-relative paths of the files under project/
+	// to their contents.
+	Files map[string][]byte
+	modes map[string]os.FileMode
+}
+
+// FetchTemplate shallow-clones a template repo and reads it. from is a repo
+// source (owner/repo, a git URL, or a canonical host/owner/repo path); a
+// subpath selects a template directory inside the repo.
+func FetchTemplate(from string, overrides map[string]string) (*Template, error) {
+	source, err := ParseSource(from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid template source: %w", err)
+	}
+	source.ApplyCloneURLOverride(overrides)
+
+	tmpDir, err := cloneRepo(source.CloneURL, source.Ref, true)
+	if err != nil {
+		return nil, fmt.Errorf("cloning: %w", err)
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	t, err := readTemplate(filepath.Join(tmpDir, filepath.FromSlash(source.SubPath)))
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", from, err)
+	}
+	return t, nil
+}
+
+// readTemplate reads a template from a directory. Only regular files under
+// project/ are read: a symlink, which could point anywhere on the machine
+// applying the template, is skipped.
+func readTemplate(dir string) (*Template, error) {
+	lf, err := ReadLockFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	if lf == nil {
+		return nil, fmt.Errorf("not a template: no %s found", lockFileName)
+	}
+	t := &Template{Lock: lf, Files: make(map[string][]byte), modes: make(map[string]os.FileMode)}
+
+	root := filepath.Join(dir, templateProjectDir)
+	if !dirExists(root) {
+		return t, nil
+	}
+	files, err := treeFiles(root)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s/: %w", templateProjectDir, err)
+	}
+	for rel := range files {
+		rel = filepath.ToSlash(rel)
+		if rel == lockFileName || rel == projectDuckrowDir+"/"+localLockFileName || filepath.Base(rel) == envFileName {
+			// The lock is the template's own; personal files and secrets
+			// don't belong in a template.
+			continue
+		}
+		p := filepath.Join(root, filepath.FromSlash(rel))
+		info, err := os.Lstat(p)
+		if err != nil {
+			return nil, err
+		}
+		if !info.Mode().IsRegular() {
+			continue
+		}
+		data, err := os.ReadFile(p)
+		if err != nil {
+			return nil, err
+		}
+		t.Files[rel] = data
+		t.modes[rel] = info.Mode().Perm()
+	}
+	return t, nil
+}
+
+// TemplateResult describes what applying a template changed (or would
+// change, in a dry run).
+type TemplateResult struct {
+	Added   []string // lock entries added, as "kind name"
+	Kept    []string // project entries kept over a different template entry
+	Removed []string // project entries dropped by replace
+	Files   []string // project files written
+	Skipped []string // existing project files left alone by merge
+
+	ExcludeAdded   []string // exclude rules added
+	ExcludeRemoved []string // exclude rules dropped by replace
+
+	// DefaultSystems is set when the project's default systems change.
+	DefaultSystems []string
+}
+
+// IsEmpty reports whether applying the template changes nothing.
+func (r *TemplateResult) IsEmpty() bool {
+	return len(r.Added) == 0 && len(r.Removed) == 0 && len(r.Files) == 0 && !r.lockChanged()
+}
+
+// lockChanged reports whether the lock file changes beyond its entries.
+func (r *TemplateResult) lockChanged() bool {
+	return len(r.ExcludeAdded) > 0 || len(r.ExcludeRemoved) > 0 || r.DefaultSystems != nil
+}
+
+// ApplyTemplate applies a template to the project in dir: its lock entries
+// are merged into (or replace) the project's duckrow.lock.json and its
+// project files are copied in. Nothing is installed; sync does that. With
+// dryRun, nothing is written.
+func ApplyTemplate(t *Template, dir string, mode TemplateMode, dryRun bool) (*TemplateResult, error) {
+	existing, err := ReadLockFile(dir)
+	if err != nil {
+		return nil, err
+	}
+	if existing == nil {
+		existing = &LockFile{LockVersion: currentLockVersion}
+	}
+
+	res := &TemplateResult{}
+	var lf *LockFile
+	switch mode {
+	case TemplateReplace:
+		lf = &LockFile{LockVersion: currentLockVersion, DefaultSystems: t.Lock.DefaultSystems, Exclude: t.Lock.Exclude, Assets: slices.Clone(t.Lock.Assets)}
+		for _, a := range t.Lock.Assets {
+			if prev := FindLockedAsset(existing, a.Kind, a.Name); prev == nil || !reflect.DeepEqual(*prev, a) {
+				res.Added = append(res.Added, lockLabel(a))
+			}
+		}
+		for _, a := range existing.Assets {
+			if FindLockedAsset(t.Lock, a.Kind, a.Name) == nil {
+				res.Removed = append(res.Removed, lockLabel(a))
+			}
+		}
+		for _, rule := range t.Lock.Exclude {
+			if !slices.Contains(existing.Exclude, rule) {
+				res.ExcludeAdded = append(res.ExcludeAdded, rule)
+			}
+		}
+		for _, rule := range existing.Exclude {
+			if !slices.Contains(t.Lock.Exclude, rule) {
+				res.ExcludeRemoved = append(res.ExcludeRemoved, rule)
+			}
+		}
+		if !slices.Equal(existing.DefaultSystems, t.Lock.DefaultSystems) {
+			res.DefaultSystems = append([]string{}, t.Lock.DefaultSystems...)
+		}
+	default:
+		lf = &LockFile{LockVersion: currentLockVersion, DefaultSystems: existing.DefaultSystems, Exclude: slices.Clone(existing.Exclude), Assets: slices.Clone(existing.Assets)}
+		for _, rule := range t.Lock.Exclude {
+			if !slices.Contains(lf.Exclude, rule) {
+				lf.Exclude = append(lf.Exclude, rule)
+				res.ExcludeAdded = append(res.ExcludeAdded, rule)
+			}
+		}
+		for _, a := range t.Lock.Assets {
+			prev := FindLockedAsset(existing, a.Kind, a.Name)
+			switch {
+			case prev == nil:
+				lf.Assets = append(lf.Assets, a)
+				res.Added = append(res.Added, lockLabel(a))
+			case !reflect.DeepEqual(*prev, a):
+				res.Kept = append(res.Kept, lockLabel(a))
+			}
+		}
+		if len(existing.DefaultSystems) == 0 && len(t.Lock.DefaultSystems) > 0 {
+			lf.DefaultSystems = t.Lock.DefaultSystems
+			res.DefaultSystems = t.Lock.DefaultSystems
+		}
+	}
+
+	paths := make([]string, 0, len(t.Files))
+	for rel := range t.Files {
+		paths = append(paths, rel)
+	}
+	sort.Strings(paths)
+	for _, rel := range paths {
+		current, err := os.ReadFile(filepath.Join(dir, filepath.FromSlash(rel)))
+		switch {
+		case err == nil && bytes.Equal(current, t.Files[rel]):
+			// Already up to date.
+		case err == nil && mode != TemplateReplace:
+			res.Skipped = append(res.Skipped, rel)
+		default:
+			res.Files = append(res.Files, rel)
+		}
+	}
+
+	if dryRun {
+		return res, nil
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("creating %s: %w", dir, err)
+	}
+	for _, rel := range res.Files {
+		p := filepath.Join(dir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", rel, err)
+		}
+		if err := os.WriteFile(p, t.Files[rel], t.modes[rel]); err != nil {
+			return nil, fmt.Errorf("writing %s: %w", rel, err)
+		}
+	}
+	if len(res.Added) > 0 || len(res.Removed) > 0 || res.lockChanged() {
+		if err := WriteLockFile(dir, lf); err != nil {
+			return nil, err
+		}
+	}
+	return res, nil
+}
+
+// lockLabel names a lock entry, e.g. "skill go-review".
+func lockLabel(a asset.LockedAsset) string {
+	return string(a.Kind) + " " + a.Name
+}
@@ -0,0 +1,205 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestReadTemplate_Symlink(t *testing.T) {
+	tmplDir := t.TempDir()
+	if err := WriteLockFile(tmplDir, &LockFile{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(tmplDir, "project"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(tmplDir, "project", "AGENTS.md"), []byte("agents"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	secret := filepath.Join(t.TempDir(), "id_rsa")
+	if err := os.WriteFile(secret, []byte("private key"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(secret, filepath.Join(tmplDir, "project", "key")); err != nil {
+		t.Skipf("symlinks not supported: %v", err)
+	}
+
+	tmpl, err := readTemplate(tmplDir)
+	if err != nil {
+		t.Fatalf("readTemplate() error = %v", err)
+	}
+	if _, ok := tmpl.Files["key"]; ok {
+		t.Error("readTemplate() followed a symlink out of the template")
+	}
+	if _, ok := tmpl.Files["AGENTS.md"]; !ok {
+		t.Error("readTemplate() dropped a regular file")
+	}
+}
+
+func TestApplyTemplate(t *testing.T) {
+	tmplDir := t.TempDir()
+	if err := WriteLockFile(tmplDir, &LockFile{DefaultSystems: []string{"cursor"}, Assets: []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "go-review", Source: "github.com/acme/skills/go-review", Commit: "aaaaaaa"},
+		{Kind: asset.KindSkill, Name: "lint", Source: "github.com/acme/skills/lint", Commit: "bbbbbbb"},
+	}}); err != nil {
+		t.Fatal(err)
+	}
+	for rel, content := range map[string]string{
+		"project/AGENTS.md":     "template agents",
+		"project/CLAUDE.md":     "template claude",
+		"project/.env.duckrow":  "TOKEN=secret",
+		"project/.cursor/r.mdc": "rule",
+	} {
+		p := filepath.Join(tmplDir, filepath.FromSlash(rel))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(content), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tmpl, err := readTemplate(tmplDir)
+	if err != nil {
+		t.Fatalf("readTemplate() error = %v", err)
+	}
+	if _, ok := tmpl.Files[".env.duckrow"]; ok {
+		t.Error("readTemplate() included an env file")
+	}
+
+	newProject := func(t *testing.T) string {
+		t.Helper()
+		dir := t.TempDir()
+		if err := WriteLockFile(dir, &LockFile{Assets: []asset.LockedAsset{
+			{Kind: asset.KindSkill, Name: "lint", Source: "github.com/acme/skills/lint", Commit: "ccccccc"},
+			{Kind: asset.KindSkill, Name: "own", Source: "github.com/acme/skills/own", Commit: "ddddddd"},
+		}}); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "AGENTS.md"), []byte("project agents"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	t.Run("merge", func(t *testing.T) {
+		dir := newProject(t)
+		res, err := ApplyTemplate(tmpl, dir, TemplateMerge, false)
+		if err != nil {
+			t.Fatalf("ApplyTemplate() error = %v", err)
+		}
+		want := &TemplateResult{
+			Added:          []string{"skill go-review"},
+			Kept:           []string{"skill lint"},
+			Files:          []string{".cursor/r.mdc", "CLAUDE.md"},
+			Skipped:        []string{"AGENTS.md"},
+			DefaultSystems: []string{"cursor"},
+		}
+		if !reflect.DeepEqual(res, want) {
+			t.Errorf("ApplyTemplate() = %+v, want %+v", res, want)
+		}
+		lf, _ := ReadLockFile(dir)
+		if got := FindLockedAsset(lf, asset.KindSkill, "lint"); got == nil || got.Commit != "ccccccc" {
+			t.Errorf("lint = %+v, want the project's entry", got)
+		}
+		if len(lf.Assets) != 3 {
+			t.Errorf("lock has %d entries, want 3", len(lf.Assets))
+		}
+		if data, _ := os.ReadFile(filepath.Join(dir, "AGENTS.md")); string(data) != "project agents" {
+			t.Errorf("AGENTS.md = %q, want the project's", data)
+		}
+	})
+
+	t.Run("replace", func(t *testing.T) {
+		dir := newProject(t)
+		res, err := ApplyTemplate(tmpl, dir, TemplateReplace, false)
+		if err != nil {
+			t.Fatalf("ApplyTemplate() error = %v", err)
+		}
+		if !reflect.DeepEqual(res.Removed, []string{"skill own"}) {
+			t.Errorf("Removed = %v, want [skill own]", res.Removed)
+		}
+		lf, _ := ReadLockFile(dir)
+		if !reflect.DeepEqual(lf.Assets, tmpl.Lock.Assets) {
+			t.Errorf("lock assets = %+v, want the template's", lf.Assets)
+		}
+		if data, _ := os.ReadFile(filepath.Join(dir, "AGENTS.md")); string(data) != "template agents" {
+			t.Errorf("AGENTS.md = %q, want the template's", data)
+		}
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		dir := newProject(t)
+		before, _ := os.ReadFile(LockFilePath(dir))
+		if _, err := ApplyTemplate(tmpl, dir, TemplateReplace, true); err != nil {
+			t.Fatal(err)
+		}
+		after, _ := os.ReadFile(LockFilePath(dir))
+		if string(before) != string(after) {
+			t.Error("dry run changed the lock file")
+		}
+		if _, err := os.Stat(filepath.Join(dir, "CLAUDE.md")); !os.IsNotExist(err) {
+			t.Error("dry run wrote CLAUDE.md")
+		}
+	})
+}
+
+func TestApplyTemplate_Excludes(t *testing.T) {
+	tmplDir := t.TempDir()
+	if err := WriteLockFile(tmplDir, &LockFile{Exclude: []string{"skill:legacy-*"}}); err != nil {
+		t.Fatal(err)
+	}
+	tmpl, err := readTemplate(tmplDir)
+	if err != nil {
+		t.Fatalf("readTemplate() error = %v", err)
+	}
+
+	newProject := func(t *testing.T) string {
+		t.Helper()
+		dir := t.TempDir()
+		if err := WriteLockFile(dir, &LockFile{Exclude: []string{"old-*"}}); err != nil {
+			t.Fatal(err)
+		}
+		return dir
+	}
+
+	tests := []struct {
+		mode        TemplateMode
+		added       []string
+		removed     []string
+		wantExclude []string
+	}{
+		{TemplateMerge, []string{"skill:legacy-*"}, nil, []string{"old-*", "skill:legacy-*"}},
+		{TemplateReplace, []string{"skill:legacy-*"}, []string{"old-*"}, []string{"skill:legacy-*"}},
+	}
+	for _, tt := range tests {
+		t.Run(string(tt.mode), func(t *testing.T) {
+			dir := newProject(t)
+			res, err := ApplyTemplate(tmpl, dir, tt.mode, false)
+			if err != nil {
+				t.Fatalf("ApplyTemplate() error = %v", err)
+			}
+			if res.IsEmpty() {
+				t.Error("IsEmpty() = true for a template that changes the excludes")
+			}
+			if !reflect.DeepEqual(res.ExcludeAdded, tt.added) || !reflect.DeepEqual(res.ExcludeRemoved, tt.removed) {
+				t.Errorf("ExcludeAdded = %v, ExcludeRemoved = %v; want %v, %v", res.ExcludeAdded, res.ExcludeRemoved, tt.added, tt.removed)
+			}
+			lf, _ := ReadLockFile(dir)
+			if !reflect.DeepEqual(lf.Exclude, tt.wantExclude) {
+				t.Errorf("lock exclude = %v, want %v", lf.Exclude, tt.wantExclude)
+			}
+
+			res, err = ApplyTemplate(tmpl, dir, tt.mode, false)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !res.IsEmpty() {
+				t.Errorf("second ApplyTemplate() = %+v, want no changes", res)
+			}
+		})
+	}
+}
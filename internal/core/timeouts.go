@@ -0,0 +1,61 @@
+package core
+
+import (
+	"sync/atomic"
+	"time"
+)
+
+// Default timeouts for network operations.
+const (
+	DefaultCloneTimeout    = 60 * time.Second
+	DefaultPullTimeout     = 30 * time.Second
+	DefaultDownloadTimeout = 30 * time.Second
+)
+
+// Timeouts bounds how long each kind of network operation may take.
+type Timeouts struct {
+	Clone    time.Duration // git clone, fetch, and ls-remote of sources and registries
+	Pull     time.Duration // git pull when refreshing a registry
+	Download time.Duration // HTTP downloads such as remote lock files and release archives
+}
+
+// withDefaults fills unset (non-positive) timeouts with the defaults.
+func (t Timeouts) withDefaults() Timeouts {
+	if t.Clone <= 0 {
+		t.Clone = DefaultCloneTimeout
+	}
+	if t.Pull <= 0 {
+		t.Pull = DefaultPullTimeout
+	}
+	if t.Download <= 0 {
+		t.Download = DefaultDownloadTimeout
+	}
+	return t
+}
+
+// Timeouts returns the configured network timeouts. Unset or non-positive
+// values use the defaults.
+func (s Settings) Timeouts() Timeouts {
+	return Timeouts{
+		Clone:    time.Duration(s.CloneTimeoutSeconds) * time.Second,
+		Pull:     time.Duration(s.PullTimeoutSeconds) * time.Second,
+		Download: time.Duration(s.DownloadTimeoutSeconds) * time.Second,
+	}.withDefaults()
+}
+
+// currentTimeoutsValue is process-wide like offline mode: the CLI sets it
+// once from the settings and flags, and the git and HTTP helpers read it.
+var currentTimeoutsValue atomic.Value
+
+// SetTimeouts sets the network timeouts. Unset values use the defaults.
+func SetTimeouts(t Timeouts) {
+	currentTimeoutsValue.Store(t.withDefaults())
+}
+
+// CurrentTimeouts returns the network timeouts in effect.
+func CurrentTimeouts() Timeouts {
+	if t, ok := currentTimeoutsValue.Load().(Timeouts); ok {
+		return t
+	}
+	return Timeouts{}.withDefaults()
+}
@@ -0,0 +1,46 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestSettings_Timeouts(t *testing.T) {
+	got := Settings{}.Timeouts()
+	want := Timeouts{Clone: DefaultCloneTimeout, Pull: DefaultPullTimeout, Download: DefaultDownloadTimeout}
+	if got != want {
+		t.Errorf("default Timeouts() = %+v, want %+v", got, want)
+	}
+
+	got = Settings{CloneTimeoutSeconds: 300, PullTimeoutSeconds: -1, DownloadTimeoutSeconds: 5}.Timeouts()
+	want = Timeouts{Clone: 5 * time.Minute, Pull: DefaultPullTimeout, Download: 5 * time.Second}
+	if got != want {
+		t.Errorf("Timeouts() = %+v, want %+v", got, want)
+	}
+}
+
+func TestCloneRepo_Timeout(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	sourceDir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(sourceDir, "README.md"), []byte("# Test"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	setupTestGitRepoInDir(t, sourceDir)
+
+	SetTimeouts(Timeouts{Clone: time.Millisecond})
+	t.Cleanup(func() { SetTimeouts(Timeouts{}) })
+
+	_, err := cloneRepo(sourceDir, "", true)
+	ce, ok := IsCloneError(err)
+	if !ok {
+		t.Fatalf("cloneRepo() error = %v, want a CloneError", err)
+	}
+	if ce.Kind != CloneErrTimeout {
+		t.Errorf("Kind = %v, want %v", ce.Kind, CloneErrTimeout)
+	}
+}
@@ -26,12 +26,152 @@ type Settings struct {
 	AutoAddCurrentDir   bool              `json:"autoAddCurrentDir"`
 	DisableAllTelemetry bool              `json:"disableAllTelemetry"`
 	CloneURLOverrides   map[string]string `json:"cloneURLOverrides,omitempty"`
+
+	// IgnorePatterns are gitignore-style patterns for files that should not be
+	// copied when installing skills. A skill's own .duckrowignore is applied
+	// on top of these.
+	IgnorePatterns []string `json:"ignorePatterns,omitempty"`
+
+	// MaxSkillSizeMB and MaxSkillFiles are the thresholds above which
+	// installing a skill requires confirmation. Zero uses the default;
+	// a negative value disables the check.
+	MaxSkillSizeMB int `json:"maxSkillSizeMB,omitempty"`
+	MaxSkillFiles  int `json:"maxSkillFiles,omitempty"`
+
+	// CommitCacheTTLMinutes is how long hydrated registry commits are reused
+	// before they are resolved again. Zero uses the default; a negative
+	// value disables the cache so every check re-resolves.
+	CommitCacheTTLMinutes int `json:"commitCacheTTLMinutes,omitempty"`
+
+	// SkipSystemSelection makes the TUI install wizards reuse the systems
+	// last selected for the folder and skip the selection step. The step is
+	// still shown the first time an asset kind is installed into a folder.
+	SkipSystemSelection bool `json:"skipSystemSelection,omitempty"`
+
+	// DisableHydration turns off commit hydration for all registries.
+	DisableHydration bool `json:"disableHydration,omitempty"`
+
+	// Offline forbids network access, as if --offline were always given.
+	Offline bool `json:"offline,omitempty"`
+
+	// AllowedHosts, when set, are the only hosts duckrow may contact: git
+	// hosts, HTTP registries, the GitHub API, webhooks, and remote MCP
+	// servers. "*.example.com" matches any subdomain of example.com.
+	AllowedHosts []string `json:"allowedHosts,omitempty"`
+
+	// Attest writes a signed provenance attestation of every asset
+	// installed or updated in a project to .duckrow/attestations, for
+	// 'duckrow attest verify'.
+	Attest bool `json:"attest,omitempty"`
+
+	// Timeouts for network operations, in seconds. Zero uses the default
+	// (60s for clones, 30s for registry pulls and downloads).
+	CloneTimeoutSeconds    int `json:"cloneTimeoutSeconds,omitempty"`
+	PullTimeoutSeconds     int `json:"pullTimeoutSeconds,omitempty"`
+	DownloadTimeoutSeconds int `json:"downloadTimeoutSeconds,omitempty"`
+
+	// CacheDir moves the clone cache, where sources are mirrored once so
+	// later clones only fetch what changed, from ~/.duckrow/cache. ~ and
+	// $VARS are expanded.
+	CacheDir string `json:"cacheDir,omitempty"`
+
+	// DisableCloneCache turns the clone cache off: every install, update
+	// check, and sync clones from scratch.
+	DisableCloneCache bool `json:"disableCloneCache,omitempty"`
+
+	// SharedCache makes CacheDir usable by several users in one group, e.g.
+	// CI jobs on a shared build machine.
+	SharedCache bool `json:"sharedCache,omitempty"`
+
+	// MaxRequestsPerMinute caps requests to remote hosts so large update
+	// checks don't trip GitHub's abuse detection. Zero uses the default
+	// (120); a negative value turns the limit off.
+	MaxRequestsPerMinute int `json:"maxRequestsPerMinute,omitempty"`
+
+	// InstallStrategy is how skills reach non-universal systems: "symlink"
+	// (the default) links to the canonical copy, "copy" always copies.
+	InstallStrategy string `json:"installStrategy,omitempty"`
+
+	// GitignorePolicy is which generated directories duckrow keeps in a
+	// project's .gitignore: "off" (the default), "canonical", or "systems".
+	// See GitignorePolicy.
+	GitignorePolicy string `json:"gitignorePolicy,omitempty"`
+
+	// SkillNamespaces is when registry skills are installed under a
+	// registry-scoped name: "never" (the default), "on-conflict", or
+	// "always". See NamespaceMode.
+	SkillNamespaces string `json:"skillNamespaces,omitempty"`
+
+	// Accessible makes the TUI and CLI output screen-reader friendly, as if
+	// --accessible were always given.
+	Accessible bool `json:"accessible,omitempty"`
+
+	// DisableUpgradeCheck stops duckrow from looking up, once a day, whether
+	// a newer release is out.
+	DisableUpgradeCheck bool `json:"disableUpgradeCheck,omitempty"`
+
+	// DisableUpdateHints stops duckrow from checking the current project for
+	// asset updates in the background, once a day, and hinting at them.
+	DisableUpdateHints bool `json:"disableUpdateHints,omitempty"`
+
+	// GitHubAPI resolves the commits of github.com sources, including the
+	// latest commit of each sub-path, through the GitHub API instead of git
+	// ls-remote and clones, authenticated with GITHUB_TOKEN or GH_TOKEN.
+	// Unset, the API is used whenever one of those tokens is set.
+	GitHubAPI *bool `json:"githubAPI,omitempty"`
+
+	// DesktopNotifications shows a desktop notification when a long-running
+	// operation (update --all, sync) completes.
+	DesktopNotifications bool `json:"desktopNotifications,omitempty"`
+
+	// NotifyWebhookURL receives a JSON POST with a summary, {"text": ...},
+	// when a long-running operation completes, e.g. a Slack incoming webhook.
+	NotifyWebhookURL string `json:"notifyWebhookURL,omitempty"`
 }
 
 // Registry is a private skill catalog backed by a git repository.
 type Registry struct {
 	Name string `json:"name"`
 	Repo string `json:"repo"`
+
+	// Alias is a short name set with 'duckrow registry alias', accepted
+	// wherever a registry name is (e.g. --registry a, or a/skill-name).
+	Alias string `json:"alias,omitempty"`
+
+	// Hydrate set to false skips commit hydration for this registry, e.g.
+	// for large registries of unpinned entries where cloning every source
+	// is too expensive.
+	Hydrate *bool `json:"hydrate,omitempty"`
+
+	// Local is set for a registry added from a directory on disk; Repo is
+	// then its absolute path, and the directory is read in place rather
+	// than cloned.
+	Local bool `json:"local,omitempty"`
+
+	// Auth gives a private HTTPS registry a token to clone with, so CI can
+	// reach it without interactive credential prompts. It also applies to
+	// skill sources under the same host and owner.
+	Auth *RegistryAuth `json:"auth,omitempty"`
+}
+
+// RegistryAuth is the token a registry is cloned with.
+type RegistryAuth struct {
+	// TokenEnv names the environment variable holding the token, e.g.
+	// GH_TOKEN. The token itself is never stored in the config.
+	TokenEnv string `json:"tokenEnv"`
+
+	// Type is how the token is sent: "https-basic" (default) as the basic
+	// auth password, or "bearer" as a bearer token.
+	Type string `json:"type,omitempty"`
+
+	// Username is the basic auth user name (default "x-access-token").
+	Username string `json:"username,omitempty"`
+}
+
+// Matches reports whether ref refers to the registry by repo URL, name, or
+// alias.
+func (r Registry) Matches(ref string) bool {
+	return ref != "" && (r.Repo == ref || r.Name == ref || r.Alias == ref)
 }
 
 // ParsedSource represents a parsed skill source string.
@@ -67,6 +207,20 @@ type UpdateInfo struct {
 	InstalledCommit string `json:"installed"`
 	AvailableCommit string `json:"available"`
 	HasUpdate       bool   `json:"hasUpdate"`
+	Error           string `json:"error,omitempty"`      // set when the source could not be checked
+	ErrorClass      string `json:"errorClass,omitempty"` // ErrorClass of Error
+
+	// For skills installed by version: the installed version, the newest
+	// version the lock entry's constraint allows, the newest version
+	// overall, and which part of the version the update changes ("major",
+	// "minor", or "patch").
+	InstalledVersion string `json:"installedVersion,omitempty"`
+	AvailableVersion string `json:"availableVersion,omitempty"`
+	LatestVersion    string `json:"latestVersion,omitempty"`
+	UpdateLevel      string `json:"updateLevel,omitempty"`
+
+	// Changelog is what the update pulls in, when asked for.
+	Changelog *Changelog `json:"changelog,omitempty"`
 }
 
 // CachedCommits stores resolved commit SHAs for unpinned registry skills.
@@ -75,3 +229,11 @@ type CachedCommits struct {
 	GeneratedAt time.Time         `json:"generatedAt"`
 	Commits     map[string]string `json:"commits"` // source -> commit SHA
 }
+
+// CachedWarnings stores the manifest validation warnings for a registry.
+// Written to <registryDir>/duckrow.warnings.json on add and refresh so the
+// warnings can be shown long after the command that produced them.
+type CachedWarnings struct {
+	GeneratedAt time.Time `json:"generatedAt"`
+	Warnings    []string  `json:"warnings"`
+}
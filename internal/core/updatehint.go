@@ -0,0 +1,153 @@
+package core
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+const (
+	// updateCheckFile caches the result of the background update check of
+	// each project, so it runs at most once per updateCheckInterval.
+	updateCheckFile     = "update-check.json"
+	updateCheckInterval = 24 * time.Hour
+)
+
+// ProjectUpdates is the result of the last background update check of a
+// project.
+type ProjectUpdates struct {
+	CheckedAt time.Time `json:"checkedAt"`
+	// LockHash identifies the project's lock files as they were checked;
+	// once they change, the counts no longer apply.
+	LockHash string `json:"lockHash"`
+	// Available is the number of assets with updates, per kind.
+	Available map[asset.Kind]int `json:"available,omitempty"`
+}
+
+// Hint is the one-line hint for the available updates, e.g. "3 skills
+// have updates; run 'duckrow skill update --all'", or "" if there are none.
+func (u ProjectUpdates) Hint() string {
+	var counts, commands []string
+	total := 0
+	for _, kind := range asset.Kinds() {
+		n := u.Available[kind]
+		if n == 0 {
+			continue
+		}
+		total += n
+		noun := string(kind)
+		if n != 1 {
+			noun += "s"
+		}
+		counts = append(counts, fmt.Sprintf("%d %s", n, noun))
+		commands = append(commands, fmt.Sprintf("'duckrow %s update --all'", kind))
+	}
+	if total == 0 {
+		return ""
+	}
+	verb := "have"
+	if total == 1 {
+		verb = "has"
+	}
+	return fmt.Sprintf("%s %s updates; run %s", strings.Join(counts, " and "), verb, strings.Join(commands, " and "))
+}
+
+// LockHash returns a hash of the project's team and personal lock files,
+// or "" if it has neither.
+func LockHash(dir string) string {
+	team, errTeam := os.ReadFile(LockFilePath(dir))
+	local, errLocal := os.ReadFile(LocalLockFilePath(dir))
+	if errTeam != nil && errLocal != nil {
+		return ""
+	}
+	h := sha256.New()
+	h.Write(team)
+	h.Write([]byte{0})
+	h.Write(local)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// CachedUpdates returns the last background update check of the project in
+// dir, if its lock files haven't changed since.
+func CachedUpdates(configDir, dir string) (ProjectUpdates, bool) {
+	u, ok := readUpdateCheckCache(configDir)[updateCheckKey(dir)]
+	if !ok || u.LockHash == "" || u.LockHash != LockHash(dir) {
+		return ProjectUpdates{}, false
+	}
+	return u, true
+}
+
+// UpdateCheckDue reports whether the project in dir should be checked for
+// updates in the background: it has a lock file and wasn't checked in the
+// last day. Changing the lock files hides the hint until the next check but
+// doesn't bring it forward, so projects are checked at most once a day.
+func UpdateCheckDue(configDir, dir string) bool {
+	if LockHash(dir) == "" {
+		return false
+	}
+	u, ok := readUpdateCheckCache(configDir)[updateCheckKey(dir)]
+	return !ok || time.Since(u.CheckedAt) > updateCheckInterval
+}
+
+// RecordUpdates caches the result of an update check of the project in dir.
+// Recording nil counts before the check starts keeps other commands from
+// starting another one meanwhile.
+func RecordUpdates(configDir, dir string, available map[asset.Kind]int) error {
+	cache := readUpdateCheckCache(configDir)
+	cache[updateCheckKey(dir)] = ProjectUpdates{
+		CheckedAt: time.Now(),
+		LockHash:  LockHash(dir),
+		Available: available,
+	}
+	data, err := json.MarshalIndent(cache, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		return err
+	}
+	// Checks of different projects may finish at the same time; write a
+	// temporary file and rename it so readers never see a partial file.
+	tmp, err := os.CreateTemp(configDir, updateCheckFile+".*")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return os.Rename(tmp.Name(), filepath.Join(configDir, updateCheckFile))
+}
+
+func updateCheckKey(dir string) string {
+	if abs, err := filepath.Abs(dir); err == nil {
+		return abs
+	}
+	return dir
+}
+
+// readUpdateCheckCache returns the cached checks keyed by absolute project
+// path, or an empty map if there are none.
+func readUpdateCheckCache(configDir string) map[string]ProjectUpdates {
+	cache := make(map[string]ProjectUpdates)
+	data, err := os.ReadFile(filepath.Join(configDir, updateCheckFile))
+	if err != nil {
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache); err != nil {
+		return make(map[string]ProjectUpdates)
+	}
+	return cache
+}
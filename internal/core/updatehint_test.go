@@ -0,0 +1,85 @@
+package core
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestProjectUpdates_Hint(t *testing.T) {
+	tests := []struct {
+		available map[asset.Kind]int
+		want      string
+	}{
+		{nil, ""},
+		{map[asset.Kind]int{asset.KindSkill: 0}, ""},
+		{map[asset.Kind]int{asset.KindSkill: 1}, "1 skill has updates; run 'duckrow skill update --all'"},
+		{map[asset.Kind]int{asset.KindSkill: 3}, "3 skills have updates; run 'duckrow skill update --all'"},
+		{map[asset.Kind]int{asset.KindSkill: 2, asset.KindAgent: 1},
+			"2 skills and 1 agent have updates; run 'duckrow skill update --all' and 'duckrow agent update --all'"},
+	}
+	for _, tt := range tests {
+		if got := (ProjectUpdates{Available: tt.available}).Hint(); got != tt.want {
+			t.Errorf("Hint(%v) = %q, want %q", tt.available, got, tt.want)
+		}
+	}
+}
+
+func TestUpdateCheckCache(t *testing.T) {
+	configDir := t.TempDir()
+	dir := t.TempDir()
+
+	if UpdateCheckDue(configDir, dir) {
+		t.Error("UpdateCheckDue() without a lock file = true, want false")
+	}
+
+	if err := WriteLockFile(dir, &LockFile{}); err != nil {
+		t.Fatal(err)
+	}
+	if !UpdateCheckDue(configDir, dir) {
+		t.Error("UpdateCheckDue() never checked = false, want true")
+	}
+	if _, ok := CachedUpdates(configDir, dir); ok {
+		t.Error("CachedUpdates() never checked: ok = true")
+	}
+
+	if err := RecordUpdates(configDir, dir, map[asset.Kind]int{asset.KindSkill: 2}); err != nil {
+		t.Fatalf("RecordUpdates() error: %v", err)
+	}
+	if UpdateCheckDue(configDir, dir) {
+		t.Error("UpdateCheckDue() just checked = true, want false")
+	}
+	u, ok := CachedUpdates(configDir, dir)
+	if !ok || u.Available[asset.KindSkill] != 2 {
+		t.Fatalf("CachedUpdates() = %+v, %v; want 2 skills", u, ok)
+	}
+
+	// A changed lock file makes the counts stale without making the check
+	// due again.
+	if err := WriteLockFile(dir, &LockFile{DefaultSystems: []string{"cursor"}}); err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := CachedUpdates(configDir, dir); ok {
+		t.Error("CachedUpdates() after the lock changed: ok = true")
+	}
+	if UpdateCheckDue(configDir, dir) {
+		t.Error("UpdateCheckDue() after the lock changed = true, want false")
+	}
+
+	// A day later the project is due again.
+	cache := readUpdateCheckCache(configDir)
+	entry := cache[updateCheckKey(dir)]
+	entry.CheckedAt = time.Now().Add(-25 * time.Hour)
+	cache[updateCheckKey(dir)] = entry
+	data, _ := json.Marshal(cache)
+	if err := os.WriteFile(filepath.Join(configDir, updateCheckFile), data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if !UpdateCheckDue(configDir, dir) {
+		t.Error("UpdateCheckDue() a day later = false, want true")
+	}
+}
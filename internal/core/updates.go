@@ -0,0 +1,272 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// RepoUpdates is the update status of the locked assets that share a
+// repository and ref.
+type RepoUpdates struct {
+	Repo    string // host/owner/repo
+	Ref     string
+	Head    string // commit the ref currently points to upstream, if resolved
+	Updates []UpdateInfo
+	Err     *RepoCheckError // non-nil if the repository could not be checked
+}
+
+// RepoCheckError records why a repository could not be checked for updates.
+// Assets from that repository are reported as up to date, with the error
+// attached to their UpdateInfo.
+type RepoCheckError struct {
+	Repo string
+	URL  string
+	Err  error
+}
+
+func (e *RepoCheckError) Error() string {
+	return fmt.Sprintf("checking %s: %v", e.Repo, e.Err)
+}
+
+func (e *RepoCheckError) Unwrap() error {
+	return e.Err
+}
+
+// CheckForUpdates checks each locked asset of the given kind for available
+// updates. It works for any source-based kind (skills, agents) that uses
+// commit-pinned lock entries. Repositories that cannot be reached don't
+// stop the check: their assets are reported with UpdateInfo.Error set, and
+// the returned error joins their RepoCheckErrors.
+func CheckForUpdates(lf *LockFile, kind asset.Kind, overrides map[string]string, registryCommits map[string]string) ([]UpdateInfo, error) {
+	var results []UpdateInfo
+	var errs []error
+	for _, r := range CheckForUpdatesByRepo(lf, kind, overrides, nil, registryCommits, nil) {
+		results = append(results, r.Updates...)
+		if r.Err != nil {
+			errs = append(errs, r.Err)
+		}
+	}
+	return results, errors.Join(errs...)
+}
+
+// CheckForUpdatesByRepo groups the locked assets of the given kind by
+// repository, ref, and registry mirror (see RegistryMirrors.Locked) and
+// resolves each group once. Assets with a known registry commit are
+// resolved without network access. For the rest, the ref is resolved with
+// git ls-remote; a full clone is only needed when an asset lives in a
+// sub-path and its installed commit is no longer the tip, since the latest
+// commit touching that path cannot be known otherwise.
+//
+// If fn is non-nil it is called with each group as soon as it is resolved,
+// so callers can display results progressively. Groups are returned in the
+// order their first asset appears in the lock file.
+func CheckForUpdatesByRepo(lf *LockFile, kind asset.Kind, overrides map[string]string, mirrors RegistryMirrors, registryCommits map[string]string, fn func(RepoUpdates)) []RepoUpdates {
+	pathIndex := BuildPathIndex(registryCommits)
+
+	type repoRefKey struct {
+		repo   string
+		ref    string
+		mirror string
+	}
+	groups := make(map[repoRefKey][]asset.LockedAsset)
+	var order []repoRefKey
+
+	for _, a := range AssetsByKind(lf, kind) {
+		key := repoRefKey{repo: repoKey(a.Source), ref: a.Ref, mirror: mirrors.Locked(a)}
+		if _, exists := groups[key]; !exists {
+			order = append(order, key)
+		}
+		groups[key] = append(groups[key], a)
+	}
+
+	var results []RepoUpdates
+	for _, key := range order {
+		r := checkRepoUpdates(key.repo, key.ref, key.mirror, groups[key], overrides, registryCommits, pathIndex)
+		if fn != nil {
+			fn(r)
+		}
+		results = append(results, r)
+	}
+	return results
+}
+
+// checkRepoUpdates resolves the available commits for assets from one repo.
+func checkRepoUpdates(repoStr, ref, mirror string, assets []asset.LockedAsset, overrides, registryCommits, pathIndex map[string]string) RepoUpdates {
+	r := RepoUpdates{Repo: repoStr, Ref: ref}
+	available := make(map[string]string, len(assets))
+	versions := resolveVersionUpdates(assets, overrides, mirror, available)
+
+	var pending []asset.LockedAsset
+	for _, a := range assets {
+		if _, ok := versions[a.Name]; ok {
+			continue
+		}
+		if regCommit := LookupRegistryCommit(a.Source, registryCommits, pathIndex); regCommit != "" {
+			available[a.Name] = regCommit
+			continue
+		}
+		pending = append(pending, a)
+	}
+
+	if len(pending) > 0 {
+		r.Err = resolveRepoUpdates(&r, pending, overrides, mirror, available)
+	}
+
+	for _, a := range assets {
+		info := UpdateInfo{
+			Name:            a.Name,
+			Source:          a.Source,
+			InstalledCommit: a.Commit,
+			AvailableCommit: a.Commit,
+		}
+		if commit, ok := available[a.Name]; ok {
+			info.AvailableCommit = commit
+			info.HasUpdate = a.Commit != commit
+		} else if r.Err != nil {
+			info.Error = r.Err.Err.Error()
+			info.ErrorClass = ErrorClass(r.Err.Err)
+		}
+		if v, ok := versions[a.Name]; ok {
+			info.InstalledVersion = v.installed.String()
+			info.AvailableVersion = v.available.String()
+			info.LatestVersion = v.latest.String()
+			if info.HasUpdate {
+				info.UpdateLevel = v.installed.UpdateLevel(v.available)
+			}
+		}
+		r.Updates = append(r.Updates, info)
+	}
+	return r
+}
+
+// lockCloneURL returns the URL to fetch a locked repository from, after
+// any clone URL override or, failing that, a registry entry's mirror ("" for
+// none).
+func lockCloneURL(host, owner, repo string, overrides map[string]string, mirror string) string {
+	if override, ok := LookupCloneURLOverride(overrides, host, owner, repo); ok {
+		return override
+	}
+	if mirror != "" {
+		return mirror
+	}
+	return fmt.Sprintf("https://%s/%s/%s.git", host, owner, repo)
+}
+
+// versionUpdate is where a skill installed by version stands: its
+// installed version, the newest its constraint allows, and the newest
+// overall.
+type versionUpdate struct {
+	installed, available, latest Version
+}
+
+// resolveVersionUpdates fills available with the commit of the newest
+// version tag each skill installed by version may update to, and returns
+// the versions by asset name. The repository's tags are listed once.
+// Assets not installed by version, or whose repository has no tag the
+// constraint allows, are left to be resolved by commit.
+func resolveVersionUpdates(assets []asset.LockedAsset, overrides map[string]string, mirror string, available map[string]string) map[string]versionUpdate {
+	versions := make(map[string]versionUpdate)
+	var tags map[string]string
+	listed := false
+	for _, a := range assets {
+		installed, err := ParseVersion(LockedVersion(a))
+		if err != nil {
+			continue
+		}
+		if !listed {
+			listed = true
+			host, owner, repo, _, err := ParseLockSource(a.Source)
+			if err != nil {
+				return versions
+			}
+			if tags, err = lsRemoteTags(lockCloneURL(host, owner, repo, overrides, mirror)); err != nil {
+				return versions
+			}
+		}
+		candidates := VersionTags(tags, LockedUpstreamName(a))
+		latest, ok := VersionConstraint{}.Latest(candidates)
+		if !ok {
+			continue
+		}
+		constraint, err := ParseVersionConstraint(LockedVersionConstraint(a))
+		if err != nil {
+			continue
+		}
+		next, ok := constraint.Latest(candidates)
+		if !ok || next.Version.Compare(installed) < 0 {
+			continue
+		}
+		available[a.Name] = next.Commit
+		versions[a.Name] = versionUpdate{installed: installed, available: next.Version, latest: latest.Version}
+	}
+	return versions
+}
+
+// resolveRepoUpdates fills available with the latest commit for each pending
+// asset, using ls-remote and falling back to a clone for changed sub-paths.
+// Repositories served by the GitHub API are resolved per path without a
+// clone.
+func resolveRepoUpdates(r *RepoUpdates, pending []asset.LockedAsset, overrides map[string]string, mirror string, available map[string]string) *RepoCheckError {
+	host, owner, repo, _, err := ParseLockSource(pending[0].Source)
+	if err != nil {
+		return &RepoCheckError{Repo: r.Repo, Err: err}
+	}
+	cloneURL := lockCloneURL(host, owner, repo, overrides, mirror)
+
+	head, err := resolveRef(cloneURL, host, owner, repo, r.Ref)
+	if err != nil {
+		return &RepoCheckError{Repo: r.Repo, URL: cloneURL, Err: err}
+	}
+	r.Head = head
+
+	var needHistory []asset.LockedAsset
+	for _, a := range pending {
+		switch {
+		case skillSubPath(a.Source) == "":
+			available[a.Name] = head
+		case a.Commit == head:
+			available[a.Name] = a.Commit
+		default:
+			needHistory = append(needHistory, a)
+		}
+	}
+	if githubAPIRepo(cloneURL, host, owner, repo) {
+		needHistory = resolvePathCommits(owner, repo, head, needHistory, available)
+	}
+	if len(needHistory) == 0 {
+		return nil
+	}
+
+	tmpDir, err := cloneRepo(cloneURL, r.Ref, false)
+	if err != nil {
+		return &RepoCheckError{Repo: r.Repo, URL: cloneURL, Err: err}
+	}
+	defer func() { _ = os.RemoveAll(tmpDir) }()
+
+	for _, a := range needHistory {
+		commit, commitErr := GetSkillCommit(tmpDir, skillSubPath(a.Source))
+		if commitErr != nil {
+			commit = a.Commit
+		}
+		available[a.Name] = commit
+	}
+	return nil
+}
+
+// resolvePathCommits fills available with the latest commit touching each
+// asset's sub-path at ref, asked of the GitHub API. It stops at the first
+// failure and returns the assets left unresolved, to be resolved from a
+// clone instead.
+func resolvePathCommits(owner, repo, ref string, assets []asset.LockedAsset, available map[string]string) []asset.LockedAsset {
+	for i, a := range assets {
+		commit, err := githubPathCommit(owner, repo, ref, skillSubPath(a.Source))
+		if err != nil {
+			return assets[i:]
+		}
+		available[a.Name] = commit
+	}
+	return nil
+}
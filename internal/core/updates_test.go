@@ -0,0 +1,207 @@
+package core
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// gitCommitAll commits all changes in dir and returns the new HEAD commit.
+func gitCommitAll(t *testing.T, dir, msg string) string {
+	t.Helper()
+	env := append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test",
+		"GIT_AUTHOR_EMAIL=test@test.com",
+		"GIT_COMMITTER_NAME=Test",
+		"GIT_COMMITTER_EMAIL=test@test.com",
+	)
+	for _, args := range [][]string{{"add", "."}, {"commit", "-m", msg}} {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = env
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v failed: %v\n%s", args, err, out)
+		}
+	}
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "HEAD").Output()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.TrimSpace(string(out))
+}
+
+func TestCheckForUpdatesByRepo(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	sourceDir := t.TempDir()
+	for _, name := range []string{"skill-a", "skill-b"} {
+		dir := filepath.Join(sourceDir, "skills", name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte("---\nname: "+name+"\n---\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	setupTestGitRepoInDir(t, sourceDir)
+	first, err := GetSkillCommit(sourceDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err := os.WriteFile(filepath.Join(sourceDir, "skills", "skill-b", "SKILL.md"), []byte("---\nname: skill-b\n---\nchanged\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	second := gitCommitAll(t, sourceDir, "update b")
+
+	lf := &LockFile{Assets: []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "skill-a", Source: "localhost/testorg/testrepo/skills/skill-a", Commit: first},
+		{Kind: asset.KindSkill, Name: "skill-b", Source: "localhost/testorg/testrepo/skills/skill-b", Commit: first},
+		{Kind: asset.KindSkill, Name: "whole", Source: "localhost/other/whole", Commit: first},
+		{Kind: asset.KindSkill, Name: "from-registry", Source: "localhost/other/whole/x", Commit: "aaa"},
+		{Kind: asset.KindSkill, Name: "missing", Source: "localhost/gone/repo/skill", Commit: "bbb"},
+	}}
+	overrides := map[string]string{
+		"testorg/testrepo": sourceDir,
+		"other/whole":      sourceDir,
+		"gone/repo":        filepath.Join(t.TempDir(), "does-not-exist"),
+	}
+	registryCommits := map[string]string{"localhost/other/whole/x": "ccc"}
+
+	var streamed []string
+	results := CheckForUpdatesByRepo(lf, asset.KindSkill, overrides, nil, registryCommits, func(r RepoUpdates) {
+		streamed = append(streamed, r.Repo)
+	})
+
+	wantRepos := []string{"localhost/testorg/testrepo", "localhost/other/whole", "localhost/gone/repo"}
+	if strings.Join(streamed, ",") != strings.Join(wantRepos, ",") {
+		t.Errorf("streamed repos = %v, want %v", streamed, wantRepos)
+	}
+	if len(results) != 3 {
+		t.Fatalf("len(results) = %d, want 3", len(results))
+	}
+	if results[0].Head != second {
+		t.Errorf("Head = %q, want %q", results[0].Head, second)
+	}
+
+	byName := make(map[string]UpdateInfo)
+	for _, r := range results {
+		for _, u := range r.Updates {
+			byName[u.Name] = u
+		}
+	}
+
+	tests := []struct {
+		name      string
+		available string
+		hasUpdate bool
+	}{
+		{"skill-a", first, false},
+		{"skill-b", second, true},
+		{"whole", second, true},
+		{"from-registry", "ccc", true},
+		{"missing", "bbb", false},
+	}
+	for _, tt := range tests {
+		u := byName[tt.name]
+		if u.AvailableCommit != tt.available || u.HasUpdate != tt.hasUpdate {
+			t.Errorf("%s: available = %q, hasUpdate = %v; want %q, %v",
+				tt.name, u.AvailableCommit, u.HasUpdate, tt.available, tt.hasUpdate)
+		}
+	}
+
+	if results[2].Err == nil {
+		t.Fatal("expected an error for the unreachable repo")
+	}
+	if byName["missing"].Error == "" {
+		t.Error("expected UpdateInfo.Error for the unreachable repo")
+	}
+	if results[1].Err != nil {
+		t.Errorf("unexpected error for reachable repo: %v", results[1].Err)
+	}
+
+	updates, err := CheckForUpdates(lf, asset.KindSkill, overrides, registryCommits)
+	var repoErr *RepoCheckError
+	if !errors.As(err, &repoErr) || repoErr.Repo != results[2].Repo {
+		t.Errorf("CheckForUpdates() error = %v, want the unreachable repo's RepoCheckError", err)
+	}
+	if len(updates) != len(lf.Assets) {
+		t.Errorf("CheckForUpdates() returned %d updates, want %d", len(updates), len(lf.Assets))
+	}
+}
+
+func TestCheckForUpdatesByRepo_Versions(t *testing.T) {
+	if testing.Short() {
+		t.Skip("skipping integration test in short mode")
+	}
+
+	sourceDir := t.TempDir()
+	skillFile := filepath.Join(sourceDir, "SKILL.md")
+	tag := func(name string) {
+		t.Helper()
+		if out, err := exec.Command("git", "-C", sourceDir, "tag", name).CombinedOutput(); err != nil {
+			t.Fatalf("git tag %s: %v\n%s", name, err, out)
+		}
+	}
+	if err := os.WriteFile(skillFile, []byte("---\nname: go-review\n---\nv1.2.0\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	setupTestGitRepoInDir(t, sourceDir)
+	v120, err := GetSkillCommit(sourceDir, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	tag("v1.2.0")
+	commits := map[string]string{}
+	for _, v := range []string{"1.3.0", "2.0.0"} {
+		if err := os.WriteFile(skillFile, []byte("---\nname: go-review\n---\nv"+v+"\n"), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		commits[v] = gitCommitAll(t, sourceDir, "release "+v)
+		tag("v" + v)
+	}
+
+	lf := &LockFile{Assets: []asset.LockedAsset{
+		{Kind: asset.KindSkill, Name: "go-review", Source: "localhost/acme/review", Commit: v120,
+			Data: map[string]any{"version": "1.2.0", "constraint": "^1.2"}},
+		{Kind: asset.KindSkill, Name: "latest", Source: "localhost/acme/review", Commit: v120,
+			Data: map[string]any{"version": "1.2.0"}},
+		{Kind: asset.KindSkill, Name: "by-commit", Source: "localhost/acme/review", Commit: v120},
+	}}
+	overrides := map[string]string{"acme/review": sourceDir}
+
+	byName := make(map[string]UpdateInfo)
+	for _, r := range CheckForUpdatesByRepo(lf, asset.KindSkill, overrides, nil, nil, nil) {
+		for _, u := range r.Updates {
+			byName[u.Name] = u
+		}
+	}
+
+	tests := []struct {
+		name      string
+		available string
+		version   string
+		level     string
+	}{
+		{"go-review", commits["1.3.0"], "1.3.0", "minor"},
+		{"latest", commits["2.0.0"], "2.0.0", "major"},
+		{"by-commit", commits["2.0.0"], "", ""},
+	}
+	for _, tt := range tests {
+		u := byName[tt.name]
+		if u.AvailableCommit != tt.available || u.AvailableVersion != tt.version || u.UpdateLevel != tt.level || !u.HasUpdate {
+			t.Errorf("%s: available = %q %q (%q), hasUpdate = %v; want %q %q (%q), true",
+				tt.name, u.AvailableCommit, u.AvailableVersion, u.UpdateLevel, u.HasUpdate, tt.available, tt.version, tt.level)
+		}
+	}
+	if got := byName["go-review"].LatestVersion; got != "2.0.0" {
+		t.Errorf("go-review: LatestVersion = %q, want 2.0.0 beyond its constraint", got)
+	}
+}
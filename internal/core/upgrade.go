@@ -0,0 +1,138 @@
+package core
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// latestReleaseURL is the GitHub API endpoint for duckrow's latest release;
+// tests point it at a local server.
+var latestReleaseURL = "https://api.github.com/repos/barysiuk/duckrow/releases/latest"
+
+const (
+	// releaseCheckFile caches the latest release so it is looked up at most
+	// once per releaseCheckInterval.
+	releaseCheckFile     = "release-check.json"
+	releaseCheckInterval = 24 * time.Hour
+
+	// releaseCheckTimeout bounds the lookup. It runs at the end of commands,
+	// so a slow network must not hold them up.
+	releaseCheckTimeout = 3 * time.Second
+)
+
+// LatestRelease is the latest duckrow release as last looked up.
+type LatestRelease struct {
+	Version   string    `json:"version"` // without the leading v
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// UpgradeNotice is the one-line notice shown when a newer release is out.
+func (r LatestRelease) UpgradeNotice(current string) string {
+	return fmt.Sprintf("duckrow %s is available (you have %s). Upgrade with 'brew upgrade barysiuk/tap/duckrow' or 'duckrow install-helper --version %s --checksum <sha256>'.",
+		r.Version, strings.TrimPrefix(current, "v"), r.Version)
+}
+
+// CheckForUpgrade reports whether a release newer than current is out. The
+// latest release is cached in configDir and looked up again once a day; in
+// offline mode only the cache is used. Development builds never report an
+// upgrade. It returns nil when there is nothing newer or nothing is known.
+func CheckForUpgrade(configDir, current string) *LatestRelease {
+	if !isReleaseVersion(current) {
+		return nil
+	}
+	rel := readReleaseCache(configDir)
+	if time.Since(rel.CheckedAt) > releaseCheckInterval && !Offline() {
+		if latest, err := fetchLatestRelease(); err == nil {
+			rel = latest
+		} else {
+			// Keep what was known and don't ask again until tomorrow, so
+			// a flaky network doesn't slow down every command.
+			rel.CheckedAt = time.Now()
+		}
+		_ = writeReleaseCache(configDir, rel)
+	}
+	if !NewerVersion(rel.Version, current) {
+		return nil
+	}
+	return &rel
+}
+
+// fetchLatestRelease asks the GitHub releases API for the latest release.
+func fetchLatestRelease() (LatestRelease, error) {
+	req, err := http.NewRequest(http.MethodGet, latestReleaseURL, nil)
+	if err != nil {
+		return LatestRelease{}, err
+	}
+	req.Header.Set("Accept", "application/vnd.github+json")
+	client := &http.Client{Timeout: releaseCheckTimeout}
+	resp, err := doHTTP(client, req)
+	if err != nil {
+		return LatestRelease{}, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	if resp.StatusCode != http.StatusOK {
+		return LatestRelease{}, fmt.Errorf("latest release: %s", resp.Status)
+	}
+
+	var body struct {
+		TagName string `json:"tag_name"`
+	}
+	if err := json.NewDecoder(io.LimitReader(resp.Body, 1<<20)).Decode(&body); err != nil {
+		return LatestRelease{}, fmt.Errorf("parsing latest release: %w", err)
+	}
+	version := strings.TrimPrefix(body.TagName, "v")
+	if !isReleaseVersion(version) {
+		return LatestRelease{}, fmt.Errorf("unexpected release tag %q", body.TagName)
+	}
+	return LatestRelease{Version: version, CheckedAt: time.Now()}, nil
+}
+
+// readReleaseCache returns the cached release, or a zero one if there is
+// none.
+func readReleaseCache(configDir string) LatestRelease {
+	var rel LatestRelease
+	data, err := os.ReadFile(filepath.Join(configDir, releaseCheckFile))
+	if err != nil {
+		return rel
+	}
+	if err := json.Unmarshal(data, &rel); err != nil {
+		return LatestRelease{}
+	}
+	return rel
+}
+
+func writeReleaseCache(configDir string, rel LatestRelease) error {
+	data, err := json.MarshalIndent(rel, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(configDir, 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(configDir, releaseCheckFile), data, 0o644)
+}
+
+// NewerVersion reports whether version a is newer than b, in semver
+// order: a pre-release is older than its release, so 1.2.0 is newer than
+// 1.2.0-rc.1. Either may have a leading v; build metadata is ignored.
+func NewerVersion(a, b string) bool {
+	va, errA := ParseVersion(a)
+	vb, errB := ParseVersion(b)
+	if errA != nil || errB != nil {
+		return false
+	}
+	return va.Compare(vb) > 0
+}
+
+// isReleaseVersion reports whether v is a release version, major.minor.patch
+// with an optional pre-release, rather than a development build like "dev".
+func isReleaseVersion(v string) bool {
+	_, given, err := parseVersion(v)
+	return err == nil && given == 3
+}
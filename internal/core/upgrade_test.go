@@ -0,0 +1,108 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// fakeReleases serves tag as duckrow's latest release and counts requests.
+func fakeReleases(t *testing.T, tag string) *int {
+	t.Helper()
+	calls := 0
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		if tag == "" {
+			http.Error(w, "boom", http.StatusInternalServerError)
+			return
+		}
+		_, _ = w.Write([]byte(`{"tag_name": "` + tag + `", "html_url": "https://example.com"}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	old := latestReleaseURL
+	latestReleaseURL = srv.URL
+	t.Cleanup(func() { latestReleaseURL = old })
+	noRateLimit(t)
+	return &calls
+}
+
+func TestCheckForUpgrade(t *testing.T) {
+	calls := fakeReleases(t, "v0.5.0")
+	dir := t.TempDir()
+
+	rel := CheckForUpgrade(dir, "0.4.2")
+	if rel == nil || rel.Version != "0.5.0" {
+		t.Fatalf("CheckForUpgrade() = %+v, want 0.5.0", rel)
+	}
+	if got := rel.UpgradeNotice("v0.4.2"); got != "duckrow 0.5.0 is available (you have 0.4.2). Upgrade with 'brew upgrade barysiuk/tap/duckrow' or 'duckrow install-helper --version 0.5.0 --checksum <sha256>'." {
+		t.Errorf("UpgradeNotice() = %q", got)
+	}
+
+	// Within a day the cache answers.
+	if rel := CheckForUpgrade(dir, "0.5.0"); rel != nil {
+		t.Errorf("CheckForUpgrade(current) = %+v, want nil", rel)
+	}
+	if *calls != 1 {
+		t.Errorf("requests = %d, want 1 (cached)", *calls)
+	}
+
+	// Development builds never check.
+	if rel := CheckForUpgrade(t.TempDir(), "dev"); rel != nil || *calls != 1 {
+		t.Errorf("CheckForUpgrade(dev) = %+v after %d requests, want nil without a request", rel, *calls)
+	}
+}
+
+func TestCheckForUpgrade_OfflineAndFailures(t *testing.T) {
+	calls := fakeReleases(t, "")
+	dir := t.TempDir()
+
+	// A failed lookup is not retried until the next day.
+	if rel := CheckForUpgrade(dir, "0.4.2"); rel != nil {
+		t.Errorf("CheckForUpgrade() after a failure = %+v, want nil", rel)
+	}
+	CheckForUpgrade(dir, "0.4.2")
+	if *calls != 1 {
+		t.Errorf("requests = %d, want 1", *calls)
+	}
+
+	// Offline, a stale cache is still used but not refreshed.
+	stale := LatestRelease{Version: "0.6.0", CheckedAt: time.Now().Add(-48 * time.Hour)}
+	if err := writeReleaseCache(dir, stale); err != nil {
+		t.Fatal(err)
+	}
+	SetOffline(true)
+	t.Cleanup(func() { SetOffline(false) })
+	if rel := CheckForUpgrade(dir, "0.4.2"); rel == nil || rel.Version != "0.6.0" {
+		t.Errorf("CheckForUpgrade(offline) = %+v, want the cached 0.6.0", rel)
+	}
+	if *calls != 1 {
+		t.Errorf("requests = %d, want none offline", *calls)
+	}
+}
+
+func TestNewerVersion(t *testing.T) {
+	tests := []struct {
+		a, b string
+		want bool
+	}{
+		{"0.5.0", "0.4.9", true},
+		{"v1.0.0", "0.99.0", true},
+		{"0.4.10", "0.4.9", true},
+		{"0.4.2", "0.4.2", false},
+		{"0.4.2", "v0.5.0", false},
+		{"0.5.0-rc1", "0.4.0", true},
+		{"1.2.0", "1.2.0-rc.1", true},
+		{"1.2.0-rc.1", "1.2.0", false},
+		{"1.2.0-rc.10", "1.2.0-rc.2", true},
+		{"1.2.0+build.7", "1.2.0", false},
+		{"0.5.0", "dev", false},
+		{"latest", "0.4.0", false},
+	}
+	for _, tt := range tests {
+		if got := NewerVersion(tt.a, tt.b); got != tt.want {
+			t.Errorf("NewerVersion(%q, %q) = %v, want %v", tt.a, tt.b, got, tt.want)
+		}
+	}
+}
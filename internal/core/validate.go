@@ -0,0 +1,44 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// InvalidSkillError is returned when skills fail SKILL.md validation during
+// install. Nothing has been written to the project when it is returned.
+type InvalidSkillError struct {
+	Errs []error // one per invalid skill, usually *asset.SkillValidationError
+}
+
+func (e *InvalidSkillError) Error() string {
+	if len(e.Errs) == 1 {
+		return "invalid SKILL.md: " + e.Errs[0].Error()
+	}
+	var b strings.Builder
+	fmt.Fprintf(&b, "invalid SKILL.md in %d skills:", len(e.Errs))
+	for _, err := range e.Errs {
+		b.WriteString("\n  ")
+		b.WriteString(err.Error())
+	}
+	return b.String()
+}
+
+// relativizeSkillErrors rewrites validation error paths relative to the
+// cloned repository, so they point at files the skill author can find.
+func relativizeSkillErrors(root string, errs []error) []error {
+	for _, err := range errs {
+		var ve *asset.SkillValidationError
+		if !errors.As(err, &ve) {
+			continue
+		}
+		if rel, relErr := filepath.Rel(root, ve.Path); relErr == nil {
+			ve.Path = filepath.ToSlash(rel)
+		}
+	}
+	return errs
+}
@@ -0,0 +1,315 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+// GitignorePolicy is which generated directories duckrow keeps in a
+// project's .gitignore. .env.duckrow and the personal local lock are
+// gitignored whatever the policy.
+type GitignorePolicy string
+
+const (
+	// GitignoreOff leaves generated directories to the user. The default.
+	GitignoreOff GitignorePolicy = "off"
+	// GitignoreCanonical ignores .agents/skills/, the canonical skill
+	// copies that sync reproduces from the lock file.
+	GitignoreCanonical GitignorePolicy = "canonical"
+	// GitignoreSystems also ignores every system's skill and agent
+	// directory (.claude/skills/, .cursor/skills/, .claude/agents/, ...),
+	// including any hand-written skills and agents in them.
+	GitignoreSystems GitignorePolicy = "systems"
+)
+
+// GitignorePolicies lists the valid gitignore policies.
+func GitignorePolicies() []GitignorePolicy {
+	return []GitignorePolicy{GitignoreOff, GitignoreCanonical, GitignoreSystems}
+}
+
+// ParseGitignorePolicy validates a gitignore policy name. An empty name is
+// the default.
+func ParseGitignorePolicy(s string) (GitignorePolicy, error) {
+	if s == "" {
+		return GitignoreOff, nil
+	}
+	if p := GitignorePolicy(s); slices.Contains(GitignorePolicies(), p) {
+		return p, nil
+	}
+	return "", fmt.Errorf("unknown gitignore policy %q (want %q, %q, or %q)", s, GitignoreOff, GitignoreCanonical, GitignoreSystems)
+}
+
+// Gitignore returns the configured gitignore policy. An invalid value falls
+// back to the default.
+func (s Settings) Gitignore() GitignorePolicy {
+	p, err := ParseGitignorePolicy(s.GitignorePolicy)
+	if err != nil {
+		return GitignoreOff
+	}
+	return p
+}
+
+// GeneratedPaths returns the .gitignore entries for the directories the
+// policy ignores, anchored to the project root, e.g. /.agents/skills/. MCP
+// config files are never included: they often hold hand-written entries.
+func (p GitignorePolicy) GeneratedPaths() []string {
+	if p != GitignoreCanonical && p != GitignoreSystems {
+		return nil
+	}
+	dirs := []string{canonicalSkillsDir}
+	if p == GitignoreSystems {
+		for _, sys := range system.All() {
+			for _, kind := range []asset.Kind{asset.KindSkill, asset.KindAgent, asset.KindCommand, asset.KindRule} {
+				if dir := sys.AssetDir(kind, ""); dir != "" && sys.Supports(kind) && !slices.Contains(dirs, dir) {
+					dirs = append(dirs, dir)
+				}
+			}
+		}
+		sort.Strings(dirs[1:])
+	}
+	paths := make([]string, len(dirs))
+	for i, dir := range dirs {
+		paths[i] = "/" + filepath.ToSlash(dir) + "/"
+	}
+	return paths
+}
+
+const (
+	gitignoreBlockStart = "# >>> duckrow: generated files (gitignorePolicy setting; do not edit)"
+	gitignoreBlockEnd   = "# <<< duckrow"
+)
+
+// SyncGitignore rewrites the duckrow block of the project's .gitignore to
+// list paths, appending the block if it is missing and removing it if
+// paths is empty. The rest of the file is kept. It reports whether the file
+// changed.
+func SyncGitignore(dir string, paths []string) (bool, error) {
+	path := filepath.Join(dir, ".gitignore")
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return false, fmt.Errorf("reading .gitignore: %w", err)
+	}
+
+	lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+	if len(data) == 0 {
+		lines = nil
+	}
+	var kept []string
+	inBlock := false
+	for _, line := range lines {
+		switch {
+		case line == gitignoreBlockStart:
+			inBlock = true
+		case line == gitignoreBlockEnd && inBlock:
+			inBlock = false
+		case !inBlock:
+			kept = append(kept, line)
+		}
+	}
+	if len(paths) > 0 {
+		if len(kept) > 0 && kept[len(kept)-1] != "" {
+			kept = append(kept, "")
+		}
+		kept = append(kept, gitignoreBlockStart)
+		kept = append(kept, paths...)
+		kept = append(kept, gitignoreBlockEnd)
+	}
+	for len(kept) > 0 && kept[len(kept)-1] == "" {
+		kept = kept[:len(kept)-1]
+	}
+
+	var content string
+	if len(kept) > 0 {
+		content = strings.Join(kept, "\n") + "\n"
+	}
+	if content == string(data) {
+		return false, nil
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		return false, fmt.Errorf("writing .gitignore: %w", err)
+	}
+	return true, nil
+}
+
+// gitignoreBlock returns the entries in the duckrow block of the project's
+// .gitignore.
+func gitignoreBlock(dir string) ([]string, error) {
+	data, err := os.ReadFile(filepath.Join(dir, ".gitignore"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading .gitignore: %w", err)
+	}
+	var entries []string
+	inBlock := false
+	for _, line := range strings.Split(string(data), "\n") {
+		switch {
+		case line == gitignoreBlockStart:
+			inBlock = true
+		case line == gitignoreBlockEnd:
+			inBlock = false
+		case inBlock:
+			entries = append(entries, line)
+		}
+	}
+	return entries, nil
+}
+
+// VCSIssue is a version control hygiene problem in a project.
+type VCSIssue struct {
+	Path    string // project-relative path the issue is about
+	Problem string
+	Hint    string // how to fix it
+	// Fixable is set when FixVCS fixes the issue.
+	Fixable bool
+}
+
+func (i VCSIssue) String() string {
+	return fmt.Sprintf("%s: %s", i.Path, i.Problem)
+}
+
+// ErrNotGitRepo is returned by CheckVCS for a directory outside any git
+// work tree.
+var ErrNotGitRepo = errors.New("not inside a git repository")
+
+// CheckVCS reports version control hygiene issues in the project in dir:
+// a gitignored lock file, an env file or personal local lock that is not
+// gitignored or is committed, and, under policy, generated directories
+// that are not gitignored or are committed.
+func CheckVCS(dir string, policy GitignorePolicy) ([]VCSIssue, error) {
+	if !isGitWorkTree(dir) {
+		return nil, ErrNotGitRepo
+	}
+
+	var issues []VCSIssue
+	if pathExists(LockFilePath(dir)) {
+		if rule, ignored := gitIgnoreRule(dir, lockFileName); ignored {
+			issues = append(issues, VCSIssue{
+				Path:    lockFileName,
+				Problem: fmt.Sprintf("gitignored by %s, so teammates won't get the project's assets", rule),
+				Hint:    "remove that rule, or add !" + lockFileName + " after it",
+			})
+		}
+	}
+
+	for _, private := range []struct{ path, what string }{
+		{envFileName, "holds secrets"},
+		{projectDuckrowDir + "/" + localLockFileName, "is personal"},
+	} {
+		if !pathExists(filepath.Join(dir, filepath.FromSlash(private.path))) {
+			continue
+		}
+		if _, ignored := gitIgnoreRule(dir, private.path); !ignored {
+			issues = append(issues, VCSIssue{
+				Path:    private.path,
+				Problem: private.what + " but is not gitignored",
+				Hint:    "run 'duckrow vcs check --fix'",
+				Fixable: true,
+			})
+		}
+		if tracked := gitTrackedFiles(dir, private.path); len(tracked) > 0 {
+			issues = append(issues, VCSIssue{
+				Path:    private.path,
+				Problem: private.what + " but is committed",
+				Hint:    "git rm --cached " + private.path,
+			})
+		}
+	}
+
+	want := policy.GeneratedPaths()
+	block, err := gitignoreBlock(dir)
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Equal(block, want) {
+		issue := VCSIssue{Path: ".gitignore", Hint: "run 'duckrow vcs check --fix'", Fixable: true}
+		if len(want) == 0 {
+			issue.Problem = fmt.Sprintf("has a duckrow block, but the gitignore policy is %q", policy)
+		} else {
+			issue.Problem = fmt.Sprintf("does not ignore the directories of the %q gitignore policy", policy)
+		}
+		issues = append(issues, issue)
+	}
+	for _, p := range want {
+		rel := strings.Trim(p, "/")
+		if tracked := gitTrackedFiles(dir, rel); len(tracked) > 0 {
+			issues = append(issues, VCSIssue{
+				Path:    rel + "/",
+				Problem: fmt.Sprintf("%d generated file(s) committed; git keeps tracking them despite .gitignore", len(tracked)),
+				Hint:    "git rm -r --cached " + rel,
+			})
+		}
+	}
+	return issues, nil
+}
+
+// FixVCS fixes the fixable issues CheckVCS reports: it gitignores the env
+// file and personal local lock and brings the duckrow block of .gitignore
+// in line with policy. It returns the issues it fixed.
+func FixVCS(dir string, policy GitignorePolicy, issues []VCSIssue) ([]VCSIssue, error) {
+	var fixed []VCSIssue
+	for _, issue := range issues {
+		if !issue.Fixable {
+			continue
+		}
+		var err error
+		if issue.Path == ".gitignore" {
+			_, err = SyncGitignore(dir, policy.GeneratedPaths())
+		} else {
+			err = ensureGitignoreEntry(dir, issue.Path)
+		}
+		if err != nil {
+			return fixed, err
+		}
+		fixed = append(fixed, issue)
+	}
+	return fixed, nil
+}
+
+// LockFileIgnoreRule returns the .gitignore rule that ignores the project's
+// lock file, e.g. ".gitignore:3:*.json", or "" if the lock file is not
+// ignored, does not exist, or the project is not in a git work tree.
+func LockFileIgnoreRule(dir string) string {
+	if !pathExists(LockFilePath(dir)) {
+		return ""
+	}
+	rule, _ := gitIgnoreRule(dir, lockFileName)
+	return rule
+}
+
+func isGitWorkTree(dir string) bool {
+	out, err := exec.Command("git", "-C", dir, "rev-parse", "--is-inside-work-tree").Output()
+	return err == nil && strings.TrimSpace(string(out)) == "true"
+}
+
+// gitIgnoreRule reports whether git's ignore rules match rel, a path
+// relative to dir, whether or not it is tracked, and the matching rule as
+// source:line:pattern.
+func gitIgnoreRule(dir, rel string) (string, bool) {
+	out, err := exec.Command("git", "-C", dir, "check-ignore", "-v", "--no-index", "--", rel).Output()
+	if err != nil {
+		return "", false
+	}
+	rule, _, _ := strings.Cut(strings.TrimSpace(string(out)), "\t")
+	return rule, true
+}
+
+// gitTrackedFiles returns the files git tracks under rel, a path relative
+// to dir.
+func gitTrackedFiles(dir, rel string) []string {
+	out, err := exec.Command("git", "-C", dir, "ls-files", "--", rel).Output()
+	if err != nil {
+		return nil
+	}
+	return strings.Fields(string(out))
+}
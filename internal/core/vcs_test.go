@@ -0,0 +1,67 @@
+package core
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestSyncGitignore(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".gitignore")
+	if err := os.WriteFile(path, []byte("node_modules/\n.env.duckrow\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	read := func() string {
+		t.Helper()
+		data, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	paths := GitignoreCanonical.GeneratedPaths()
+	if changed, err := SyncGitignore(dir, paths); err != nil || !changed {
+		t.Fatalf("SyncGitignore() = %v, %v; want changed", changed, err)
+	}
+	want := "node_modules/\n.env.duckrow\n\n" + gitignoreBlockStart + "\n/.agents/skills/\n" + gitignoreBlockEnd + "\n"
+	if got := read(); got != want {
+		t.Fatalf(".gitignore = %q, want %q", got, want)
+	}
+	if changed, err := SyncGitignore(dir, paths); err != nil || changed {
+		t.Errorf("second SyncGitignore() = %v, %v; want unchanged", changed, err)
+	}
+
+	// Lines added after the block are kept when it is rewritten.
+	if err := os.WriteFile(path, []byte(read()+"dist/\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := SyncGitignore(dir, GitignoreSystems.GeneratedPaths()); err != nil {
+		t.Fatal(err)
+	}
+	if got, err := gitignoreBlock(dir); err != nil || len(got) != len(GitignoreSystems.GeneratedPaths()) {
+		t.Errorf("gitignoreBlock() = %v, %v", got, err)
+	}
+
+	if _, err := SyncGitignore(dir, nil); err != nil {
+		t.Fatal(err)
+	}
+	if got, want := read(), "node_modules/\n.env.duckrow\n\ndist/\n"; got != want {
+		t.Errorf(".gitignore after removing the block = %q, want %q", got, want)
+	}
+}
+
+func TestParseGitignorePolicy(t *testing.T) {
+	for in, want := range map[string]GitignorePolicy{"": GitignoreOff, "off": GitignoreOff, "canonical": GitignoreCanonical, "systems": GitignoreSystems} {
+		if got, err := ParseGitignorePolicy(in); err != nil || got != want {
+			t.Errorf("ParseGitignorePolicy(%q) = %q, %v; want %q", in, got, err, want)
+		}
+	}
+	if _, err := ParseGitignorePolicy("all"); err == nil {
+		t.Error("ParseGitignorePolicy(all) succeeded")
+	}
+	if got := (Settings{GitignorePolicy: "bogus"}).Gitignore(); got != GitignoreOff {
+		t.Errorf("Gitignore() with an invalid value = %q, want off", got)
+	}
+}
@@ -0,0 +1,69 @@
+// Package filelock takes the advisory locks duckrow processes share, on
+// lock files next to what they guard: an OS file lock (flock, or LockFileEx
+// on Windows) on the file, which the OS drops when its holder exits. A lock
+// file left behind by a process that died therefore never blocks, and no
+// lock is ever broken by its age.
+package filelock
+
+import (
+	"errors"
+	"os"
+	"time"
+)
+
+// ErrTimeout is returned by Lock when another process still holds the lock
+// at the end of the wait.
+var ErrTimeout = errors.New("timed out waiting for lock")
+
+// errBusy is returned by openLockFile when the lock file cannot be opened
+// until its holder has finished with it.
+var errBusy = errors.New("lock file busy")
+
+// Lock takes the lock on the file at path, creating it with perm, polling
+// every poll for up to timeout while another process holds it. It returns
+// the function that releases the lock and removes the file.
+func Lock(path string, perm os.FileMode, timeout, poll time.Duration) (func(), error) {
+	deadline := time.Now().Add(timeout)
+	for {
+		f, err := openLockFile(path, perm)
+		if err != nil && !errors.Is(err, errBusy) {
+			return nil, err
+		}
+		if f != nil {
+			locked, err := tryLockFile(f)
+			if err != nil {
+				_ = f.Close()
+				return nil, err
+			}
+			if locked {
+				// The holder before us removed the file before unlocking
+				// it, so others may already lock a new one: start over.
+				if !isLockFile(f, path) {
+					unlockFile(f)
+					_ = f.Close()
+					continue
+				}
+				return func() {
+					_ = os.Remove(path)
+					unlockFile(f)
+					_ = f.Close()
+				}, nil
+			}
+			_ = f.Close()
+		}
+		if time.Now().After(deadline) {
+			return nil, ErrTimeout
+		}
+		time.Sleep(poll)
+	}
+}
+
+// isLockFile reports whether f is still the file at path.
+func isLockFile(f *os.File, path string) bool {
+	held, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	current, err := os.Stat(path)
+	return err == nil && os.SameFile(held, current)
+}
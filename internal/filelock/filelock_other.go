@@ -0,0 +1,36 @@
+//go:build !windows
+
+package filelock
+
+import (
+	"errors"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// openLockFile opens the lock file at path, creating it with perm if
+// needed. A lock file another user created without write access for us is
+// opened read-only, which is enough to lock it.
+func openLockFile(path string, perm os.FileMode) (*os.File, error) {
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_RDWR, perm)
+	if errors.Is(err, os.ErrPermission) {
+		return os.Open(path)
+	}
+	return f, err
+}
+
+// tryLockFile takes an exclusive flock on f without waiting. It reports
+// false if another open file holds it.
+func tryLockFile(f *os.File) (bool, error) {
+	err := unix.Flock(int(f.Fd()), unix.LOCK_EX|unix.LOCK_NB)
+	if errors.Is(err, unix.EWOULDBLOCK) {
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// unlockFile releases the flock taken by tryLockFile.
+func unlockFile(f *os.File) {
+	_ = unix.Flock(int(f.Fd()), unix.LOCK_UN)
+}
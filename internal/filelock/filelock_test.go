@@ -0,0 +1,36 @@
+package filelock
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLock(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "entry.lock")
+
+	unlock, err := Lock(path, 0o644, time.Second, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Lock() error = %v", err)
+	}
+	if _, err := Lock(path, 0o644, 100*time.Millisecond, 10*time.Millisecond); !errors.Is(err, ErrTimeout) {
+		t.Errorf("second Lock() error = %v, want ErrTimeout", err)
+	}
+	unlock()
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("unlock did not remove the lock file: %v", err)
+	}
+
+	// A lock file nobody holds, e.g. left by a process that died, is taken
+	// whatever its age.
+	if err := os.WriteFile(path, []byte("12345\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	unlock, err = Lock(path, 0o644, 100*time.Millisecond, 10*time.Millisecond)
+	if err != nil {
+		t.Fatalf("Lock() over a leftover lock file error = %v", err)
+	}
+	unlock()
+}
@@ -0,0 +1,229 @@
+// Package gittest runs a local git HTTP server with fixture repositories, so
+// tests can exercise clone-based flows (registry add, install, sync, update)
+// deterministically and without touching github.com.
+//
+// Repositories are served by git's own smart HTTP backend ("git http-backend")
+// through net/http/cgi, so clones behave exactly as they do against a real
+// host. Only git needs to be installed; this works on Linux, macOS, and
+// Windows (Git for Windows ships http-backend).
+//
+// A typical test creates a server, adds repositories, and points duckrow at
+// it with CloneURLOverrides:
+//
+//	srv := gittest.NewServer(t)
+//	skills := srv.NewRepo("acme", "skills")
+//	skills.AddSkill("skills/lint", "lint", "Lints things")
+//	skills.Commit("add lint")
+//
+//	source, _ := core.ParseSource("acme/skills")
+//	source.ApplyCloneURLOverride(srv.CloneURLOverrides())
+package gittest
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http/cgi"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Host is the host fixture repositories pretend to live on. Lock sources
+// recorded by installs from the server look like "github.com/owner/repo".
+const Host = "github.com"
+
+// Server is a git smart-HTTP server backed by bare repositories in a temp
+// directory. It is closed when the test finishes.
+type Server struct {
+	// URL is the base URL of the server, e.g. "http://127.0.0.1:41234".
+	// Repositories are served at URL/owner/repo.git.
+	URL string
+
+	tb   testing.TB
+	root string
+	git  string
+	srv  *httptest.Server
+}
+
+// NewServer starts a server with no repositories. The test is skipped if
+// git is not installed.
+func NewServer(tb testing.TB) *Server {
+	tb.Helper()
+	gitPath, err := exec.LookPath("git")
+	if err != nil {
+		tb.Skip("git not installed")
+	}
+
+	s := &Server{tb: tb, root: tb.TempDir(), git: gitPath}
+	s.srv = httptest.NewServer(&cgi.Handler{
+		Path: gitPath,
+		Args: []string{"http-backend"},
+		Env: []string{
+			"GIT_PROJECT_ROOT=" + s.root,
+			"GIT_HTTP_EXPORT_ALL=1",
+		},
+		InheritEnv: []string{"PATH", "HOME", "SYSTEMROOT", "TMPDIR", "TEMP", "TMP"},
+		Stderr:     testWriter{tb},
+	})
+	s.URL = s.srv.URL
+	tb.Cleanup(s.srv.Close)
+	return s
+}
+
+// CloneURLOverrides returns clone URL overrides that send every github.com
+// repository to this server, in the form stored in the duckrow config
+// (settings.cloneURLOverrides).
+func (s *Server) CloneURLOverrides() map[string]string {
+	return map[string]string{Host + "/*": s.URL + "/{owner}/{repo}.git"}
+}
+
+// NewRepo creates an empty repository served at URL/owner/name.git and
+// returns a working copy to add fixtures to. Nothing is served until the
+// first Commit.
+func (s *Server) NewRepo(owner, name string) *Repo {
+	s.tb.Helper()
+	r := &Repo{
+		Owner:    owner,
+		Name:     name,
+		CloneURL: fmt.Sprintf("%s/%s/%s.git", s.URL, owner, name),
+		Dir:      s.tb.TempDir(),
+		tb:       s.tb,
+		git:      s.git,
+		bare:     filepath.Join(s.root, owner, name+".git"),
+	}
+	if err := os.MkdirAll(filepath.Dir(r.bare), 0o755); err != nil {
+		s.tb.Fatalf("gittest: %v", err)
+	}
+	r.run("", "init", "--bare", "--initial-branch=main", r.bare)
+	r.run(r.Dir, "init", "--initial-branch=main")
+	r.run(r.Dir, "remote", "add", "origin", r.bare)
+	return r
+}
+
+// Repo is a working copy of a repository served by a Server. Files written
+// to it are published by Commit.
+type Repo struct {
+	Owner    string
+	Name     string
+	CloneURL string // URL the server serves the repository at
+	Dir      string // working copy
+
+	tb   testing.TB
+	git  string
+	bare string
+}
+
+// Source returns the canonical duckrow source for the repository, or for a
+// path inside it, e.g. "github.com/acme/skills/skills/lint".
+func (r *Repo) Source(path ...string) string {
+	parts := append([]string{Host, r.Owner, r.Name}, path...)
+	return strings.Join(parts, "/")
+}
+
+// WriteFile writes a file in the working copy, creating parent directories.
+// rel uses forward slashes.
+func (r *Repo) WriteFile(rel, content string) {
+	r.tb.Helper()
+	path := filepath.Join(r.Dir, filepath.FromSlash(rel))
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		r.tb.Fatalf("gittest: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		r.tb.Fatalf("gittest: %v", err)
+	}
+}
+
+// WriteJSON writes v as indented JSON, e.g. a registry manifest to
+// "duckrow.json".
+func (r *Repo) WriteJSON(rel string, v any) {
+	r.tb.Helper()
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		r.tb.Fatalf("gittest: encoding %s: %v", rel, err)
+	}
+	r.WriteFile(rel, string(data)+"\n")
+}
+
+// Remove deletes a file or directory from the working copy.
+func (r *Repo) Remove(rel string) {
+	r.tb.Helper()
+	if err := os.RemoveAll(filepath.Join(r.Dir, filepath.FromSlash(rel))); err != nil {
+		r.tb.Fatalf("gittest: %v", err)
+	}
+}
+
+// AddSkill writes a SKILL.md with valid frontmatter into dir ("" for the
+// repository root).
+func (r *Repo) AddSkill(dir, name, description string) {
+	r.tb.Helper()
+	r.WriteFile(joinRel(dir, "SKILL.md"), fmt.Sprintf(
+		"---\nname: %s\ndescription: %s\n---\n# %s\n", name, description, name))
+}
+
+// AddAgent writes an agent definition file at rel (e.g. "agents/review.md").
+func (r *Repo) AddAgent(rel, name, description string) {
+	r.tb.Helper()
+	r.WriteFile(rel, fmt.Sprintf(
+		"---\nname: %s\ndescription: %s\n---\nYou are %s.\n", name, description, name))
+}
+
+// Commit commits every change in the working copy, publishes it to the
+// server, and returns the new commit SHA.
+func (r *Repo) Commit(message string) string {
+	r.tb.Helper()
+	r.run(r.Dir, "add", "-A")
+	r.run(r.Dir, "commit", "--allow-empty", "-m", message)
+	r.run(r.Dir, "push", "--quiet", "origin", "HEAD:refs/heads/main")
+	return r.Head()
+}
+
+// Tag creates a lightweight tag at HEAD and publishes it.
+func (r *Repo) Tag(name string) {
+	r.tb.Helper()
+	r.run(r.Dir, "tag", name)
+	r.run(r.Dir, "push", "--quiet", "origin", "refs/tags/"+name)
+}
+
+// Head returns the commit SHA the working copy is at.
+func (r *Repo) Head() string {
+	r.tb.Helper()
+	return strings.TrimSpace(r.run(r.Dir, "rev-parse", "HEAD"))
+}
+
+// run runs git with a fixed identity in dir and returns its output.
+func (r *Repo) run(dir string, args ...string) string {
+	r.tb.Helper()
+	cmd := exec.Command(r.git, args...)
+	cmd.Dir = dir
+	cmd.Env = append(os.Environ(),
+		"GIT_AUTHOR_NAME=Test",
+		"GIT_AUTHOR_EMAIL=test@test.com",
+		"GIT_COMMITTER_NAME=Test",
+		"GIT_COMMITTER_EMAIL=test@test.com",
+		"GIT_CONFIG_NOSYSTEM=1",
+	)
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		r.tb.Fatalf("gittest: git %s: %v\n%s", strings.Join(args, " "), err, out)
+	}
+	return string(out)
+}
+
+// joinRel joins slash-separated path elements, skipping empty ones.
+func joinRel(dir, name string) string {
+	if dir == "" || dir == "." {
+		return name
+	}
+	return strings.TrimSuffix(dir, "/") + "/" + name
+}
+
+// testWriter sends http-backend's stderr to the test log.
+type testWriter struct{ tb testing.TB }
+
+func (w testWriter) Write(p []byte) (int, error) {
+	w.tb.Logf("git http-backend: %s", strings.TrimSpace(string(p)))
+	return len(p), nil
+}
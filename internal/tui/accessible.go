@@ -0,0 +1,176 @@
+package tui
+
+import (
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/spinner"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// Accessible mode trades the decorated look for output that screen readers
+// and low-vision users handle well: no box-drawing characters or symbols,
+// brighter text, status messages that say what they are, and spinners that
+// don't redraw the screen several times a second.
+
+// glyphSet holds the characters the views draw with.
+type glyphSet struct {
+	panelBorder lipgloss.Border // around the content panel and sidebar
+	selectBar   lipgloss.Border // left of the selected list item
+	rule        string          // one cell of a horizontal rule
+	tabSep      string          // between tabs
+	tabMore     string          // where tabs are scrolled out of view
+	stepSep     string          // between wizard steps
+	bullet      string          // before sidebar and warning list items
+	update      string          // marks an asset with an update
+	updateCount string          // format of the update count on a tab
+	helpSep     string          // between help bar bindings
+	set         string          // marks an env var that is set
+
+	// Status bar message prefixes.
+	success string
+	failure string
+	warning string
+}
+
+var (
+	fancyGlyphs = glyphSet{
+		panelBorder: lipgloss.RoundedBorder(),
+		selectBar:   lipgloss.NormalBorder(),
+		rule:        "─",
+		tabSep:      "│",
+		tabMore:     "…",
+		stepSep:     " → ",
+		bullet:      "·",
+		update:      "↓",
+		updateCount: " ↓%d",
+		helpSep:     " · ",
+		set:         "✓ set",
+		success:     "✓ ",
+		failure:     "✗ ",
+		warning:     "⚠ ",
+	}
+
+	// plainGlyphs keep the layout of fancyGlyphs with blank borders and
+	// rules, and words where there were symbols.
+	plainGlyphs = glyphSet{
+		panelBorder: lipgloss.HiddenBorder(),
+		selectBar:   lipgloss.Border{Left: ">"},
+		rule:        " ",
+		tabSep:      "|",
+		tabMore:     "...",
+		stepSep:     ", then ",
+		bullet:      "-",
+		update:      "update available",
+		updateCount: ", %d to update",
+		helpSep:     ", ",
+		set:         "set",
+		success:     "Done: ",
+		failure:     "",
+		warning:     "Warning: ",
+	}
+
+	glyphs = fancyGlyphs
+)
+
+// accessible reports whether accessible mode is on.
+var accessible bool
+
+// accessibleStatusDismiss is how long status messages stay up in accessible
+// mode, long enough for a screen reader to get to them.
+const accessibleStatusDismiss = 10 * time.Second
+
+// colorText is the high-contrast replacement for muted and border colors.
+var colorText = lipgloss.Color("#F3F4F6")
+
+// themeStyles are the styles accessible mode changes, captured at startup
+// so turning it off restores them.
+type themeStyles struct {
+	muted, help, sectionHeader, sectionRule, panelBorder lipgloss.Style
+	statusTask, tabInactive, wizardStepInactive          lipgloss.Style
+	sidebarLabel, selectedItem, dialogBox                lipgloss.Style
+}
+
+var defaultTheme = currentTheme()
+
+func currentTheme() themeStyles {
+	return themeStyles{
+		muted:              mutedStyle,
+		help:               helpStyle,
+		sectionHeader:      sectionHeaderStyle,
+		sectionRule:        sectionRuleStyle,
+		panelBorder:        panelBorderStyle,
+		statusTask:         statusTaskStyle,
+		tabInactive:        tabInactiveStyle,
+		wizardStepInactive: wizardStepInactiveStyle,
+		sidebarLabel:       sidebarLabelStyle,
+		selectedItem:       selectedItemStyle,
+		dialogBox:          dialogBoxStyle,
+	}
+}
+
+func (t themeStyles) apply() {
+	mutedStyle = t.muted
+	helpStyle = t.help
+	sectionHeaderStyle = t.sectionHeader
+	sectionRuleStyle = t.sectionRule
+	panelBorderStyle = t.panelBorder
+	statusTaskStyle = t.statusTask
+	tabInactiveStyle = t.tabInactive
+	wizardStepInactiveStyle = t.wizardStepInactive
+	sidebarLabelStyle = t.sidebarLabel
+	selectedItemStyle = t.selectedItem
+	dialogBoxStyle = t.dialogBox
+}
+
+// highContrastTheme brightens the gray text of the default theme, which is
+// hard to read on dark backgrounds, and drops the dialog's drawn border.
+func highContrastTheme() themeStyles {
+	t := defaultTheme
+	t.muted = t.muted.Foreground(colorText)
+	t.help = t.help.Foreground(colorText)
+	t.sectionHeader = t.sectionHeader.Foreground(colorText).Underline(true)
+	t.sectionRule = t.sectionRule.Foreground(colorText)
+	t.panelBorder = t.panelBorder.Foreground(colorText)
+	t.statusTask = t.statusTask.Foreground(colorText)
+	t.tabInactive = t.tabInactive.Foreground(colorText)
+	t.wizardStepInactive = t.wizardStepInactive.Foreground(colorText)
+	t.sidebarLabel = t.sidebarLabel.Foreground(colorText)
+	t.selectedItem = t.selectedItem.Foreground(colorSecondary).Underline(true)
+	t.dialogBox = t.dialogBox.Border(lipgloss.HiddenBorder())
+	return t
+}
+
+// SetAccessible turns accessible mode on or off. The styles are shared by
+// all views, so call it before NewApp or PickRegistryAsset.
+func SetAccessible(on bool) {
+	accessible = on
+	if on {
+		glyphs = plainGlyphs
+		highContrastTheme().apply()
+		return
+	}
+	glyphs = fancyGlyphs
+	defaultTheme.apply()
+}
+
+// newSpinner returns the spinner shown while work is in progress. In
+// accessible mode it is a still "Working" label that ticks once an hour
+// instead of ten times a second, so screen readers aren't flooded.
+func newSpinner() spinner.Model {
+	if accessible {
+		return spinner.New(
+			spinner.WithSpinner(spinner.Spinner{Frames: []string{"Working: "}, FPS: time.Hour}),
+			spinner.WithStyle(statusTaskStyle),
+		)
+	}
+	return spinner.New(
+		spinner.WithSpinner(spinner.Dot),
+		spinner.WithStyle(spinnerStyle),
+	)
+}
+
+// ruleLine returns a horizontal rule n cells wide.
+func ruleLine(n int) string {
+	return strings.Repeat(glyphs.rule, max(0, n))
+}
@@ -0,0 +1,41 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/charmbracelet/x/ansi"
+)
+
+// boxDrawing lists the characters accessible mode must not draw.
+const boxDrawing = "─│╭╮╰╯┌┐└┘✓✗⚠↓→·"
+
+func TestAccessibleMode(t *testing.T) {
+	SetAccessible(true)
+	t.Cleanup(func() { SetAccessible(false) })
+
+	var tabs tabsModel
+	tabs = tabs.setTabs([]tabDef{{label: "Skills (3)", extra: ", 2 to update"}, {label: "MCP Servers (1)"}})
+	sb := newStatusBarModel()
+	sb, _ = sb.showMsg("Installed go-review", statusSuccess)
+
+	out := ansi.Strip(renderPanel("Settings", tabs.view(), 60, 8, panelPadH, panelPadV) + "\n" + sb.view(""))
+	if i := strings.IndexAny(out, boxDrawing); i >= 0 {
+		t.Errorf("accessible output draws %q:\n%s", []rune(out[i:])[0], out)
+	}
+	for _, want := range []string{"[Skills (3, 2 to update)] | MCP Servers (1)", "Done: Installed go-review", "Settings"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("output missing %q:\n%s", want, out)
+		}
+	}
+
+	if fps := newSpinner().Spinner.FPS; fps < time.Minute {
+		t.Errorf("spinner FPS = %s, want a still label", fps)
+	}
+
+	SetAccessible(false)
+	if out := ansi.Strip(renderPanel("Settings", "", 20, 4, panelPadH, panelPadV)); !strings.Contains(out, "╭") {
+		t.Errorf("turning accessible mode off did not restore borders:\n%s", out)
+	}
+}
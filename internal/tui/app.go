@@ -31,6 +31,7 @@ const (
 	viewCloneError                    // Clone error overlay
 	viewRegistryWizard                // Registry add wizard overlay
 	viewAssetWizard                   // Asset install wizard overlay
+	viewLog                           // Log viewer overlay
 )
 
 // App is the root Bubbletea model for DuckRow.
@@ -64,6 +65,7 @@ type App struct {
 	sidebar     sidebarModel
 	regWizard   registryWizardModel
 	assetWizard assetWizardModel
+	logView     logViewModel
 
 	// View the user was on before clone error overlay opened (for going back).
 	previousView appView
@@ -74,8 +76,12 @@ type App struct {
 	previewLoading  bool
 	previewSpinner  spinner.Model
 
-	// Cached glamour renderer (lazy-initialized on first preview).
+	// Cached glamour renderer and its style (lazy-initialized on first
+	// preview), and the on-disk cache of rendered previews.
 	glamourRenderer *glamour.TermRenderer
+	glamourStyle    string
+	glamourWidth    int
+	previews        previewCache
 
 	// Help bar.
 	help help.Model
@@ -85,6 +91,11 @@ type App struct {
 	folderStatus   []core.FolderStatus
 	registryAssets []core.RegistryAssetInfo // Unified registry asset list (all kinds)
 
+	// Bookmarked folders whose scan is still streaming in after a load;
+	// loadGen tells their results apart from those of an earlier load.
+	scanning map[string]bool
+	loadGen  int
+
 	// Active folder's computed data.
 	activeFolderStatus *core.FolderStatus
 	activeFolderMCPs   []assetItem // Installed MCPs for the active folder
@@ -92,14 +103,32 @@ type App struct {
 	// Registry commit map: source -> commit (built from registry manifests).
 	registryCommits map[string]string
 
-	// Update info for the active folder's skills: skill name -> update info.
-	updateInfo map[string]core.UpdateInfo
+	// Manifest warning counts per registry: repo URL -> count.
+	registryWarnings map[string]int
+
+	// Update info for the active folder's skills and agents:
+	// kind -> name -> update info.
+	updateInfo map[asset.Kind]map[string]core.UpdateInfo
+
+	// Number of the active folder's MCPs with missing required env vars.
+	envProblems int
+
+	// Terminal window title last set, so it is only sent when it changes.
+	windowTitle string
 
 	// Status bar (replaces toast + refresh spinner).
 	statusBar statusBarModel
 
 	// Confirmation dialog (replaces help bar when active).
 	confirm confirmModel
+
+	// Session persistence: the session saved by the last run from cwd,
+	// restored on the first load; the folder view of each folder; and the
+	// state last written, so unchanged state isn't written again.
+	session         core.TUISession
+	sessionRestored bool
+	folderViews     map[string]core.FolderView
+	savedSession    string
 }
 
 // NewApp creates a new App model with the given core dependencies.
@@ -110,12 +139,17 @@ func NewApp(config *core.ConfigManager, version string) App {
 	cwd, _ := os.Getwd()
 
 	h := help.New()
-	h.ShortSeparator = " · "
+	h.ShortSeparator = glyphs.helpSep
 
-	s := spinner.New(
-		spinner.WithSpinner(spinner.Dot),
-		spinner.WithStyle(spinnerStyle),
-	)
+	s := newSpinner()
+
+	// Relaunching from the same directory returns to the folder shown last,
+	// as long as it is still there.
+	activeFolder := cwd
+	session, _ := config.TUISession(cwd)
+	if session.Folder != "" && isDir(session.Folder) {
+		activeFolder = session.Folder
+	}
 
 	return App{
 		config:         config,
@@ -124,11 +158,12 @@ func NewApp(config *core.ConfigManager, version string) App {
 		folders:        foldersManager,
 		registry:       registryMgr,
 		cwd:            cwd,
-		activeFolder:   cwd,
+		activeFolder:   activeFolder,
 		folder:         newFolderModel(),
 		bookmarks:      newBookmarksModel(),
 		install:        newInstallModel(),
 		settings:       newSettingsModel(),
+		logView:        newLogViewModel(),
 		cloneError:     newCloneErrorModel(),
 		sidebar:        newSidebarModel(),
 		regWizard:      newRegistryWizardModel(),
@@ -137,15 +172,30 @@ func NewApp(config *core.ConfigManager, version string) App {
 		previewSpinner: s,
 		statusBar:      newStatusBarModel(),
 		confirm:        newConfirmModel(),
+		previews:       newPreviewCache(config.ConfigDir()),
+		session:        session,
+		folderViews:    savedFolderViews(config),
 	}
 }
 
+// loadedDataMsg carries the config and registry data with the active
+// folder scanned. The other bookmarked folders are listed in pending with
+// only their path filled in; their scans follow as folderScannedMsg.
 type loadedDataMsg struct {
-	cfg             *core.Config
-	folderStatus    []core.FolderStatus
-	registryAssets  []core.RegistryAssetInfo
-	registryCommits map[string]string // source -> commit from registries
-	err             error
+	cfg              *core.Config
+	folderStatus     []core.FolderStatus
+	pending          []string
+	registryAssets   []core.RegistryAssetInfo
+	registryCommits  map[string]string // source -> commit from registries
+	registryWarnings map[string]int    // repo URL -> manifest warning count
+	err              error
+}
+
+// folderScannedMsg carries the scan of one bookmarked folder, streamed in
+// after the first paint.
+type folderScannedMsg struct {
+	gen    int
+	status core.FolderStatus
 }
 
 type errMsg struct {
@@ -157,24 +207,32 @@ type statusMsg struct {
 }
 
 type updateDoneMsg struct {
-	skillName string
-	err       error
+	name    string
+	warning string // e.g. why the update could not be attested
+	err     error
 }
 
 type bulkUpdateDoneMsg struct {
-	updated int
-	errors  int
+	updated    int
+	unattested int // updated, but the attestation could not be written
+	errors     int
 }
 
 // registryRefreshDoneMsg is sent when the async registry refresh completes.
 type registryRefreshDoneMsg struct {
-	registryCommits map[string]string // source -> latest commit
-	registryAssets  []core.RegistryAssetInfo
+	registryCommits  map[string]string // source -> latest commit
+	registryAssets   []core.RegistryAssetInfo
+	registryWarnings map[string]int // repo URL -> manifest warning count
 }
 
 // startRegistryRefreshMsg triggers the async registry refresh and shows the spinner.
 type startRegistryRefreshMsg struct{}
 
+// upgradeAvailableMsg is sent when a newer duckrow release is out.
+type upgradeAvailableMsg struct {
+	release core.LatestRelease
+}
+
 // openPreviewMsg is sent by the folder model to open the SKILL.md preview.
 type openPreviewMsg struct {
 	title   string
@@ -185,12 +243,27 @@ type openPreviewMsg struct {
 type previewRenderedMsg struct {
 	content  string
 	renderer *glamour.TermRenderer
+	style    string
+	width    int // word wrap width of renderer
 }
 
 // --- Init / Update / View ---
 
 func (a App) Init() tea.Cmd {
-	return tea.Batch(a.loadDataCmd, a.startRegistryRefreshCmd)
+	return tea.Batch(a.loadDataCmd, a.startRegistryRefreshCmd, a.checkUpgradeCmd)
+}
+
+// checkUpgradeCmd looks up whether a newer duckrow release is out, unless
+// the check is turned off in the settings.
+func (a App) checkUpgradeCmd() tea.Msg {
+	cfg, err := a.config.Load()
+	if err != nil || cfg.Settings.DisableUpgradeCheck {
+		return nil
+	}
+	if rel := core.CheckForUpgrade(a.config.ConfigDir(), a.version); rel != nil {
+		return upgradeAvailableMsg{release: *rel}
+	}
+	return nil
 }
 
 // startRegistryRefreshCmd sets the refreshing flag and kicks off the async refresh.
@@ -199,13 +272,30 @@ func (a App) startRegistryRefreshCmd() tea.Msg {
 	return startRegistryRefreshMsg{}
 }
 
+// Update handles a message, saves the session if it changed, and, when the
+// active folder's pending updates or env problems changed, retitles the
+// terminal window.
 func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	m, cmd := a.update(msg)
+	app, ok := m.(App)
+	if !ok {
+		return m, cmd
+	}
+	app.saveSession()
+	if title := windowTitle(app.pendingUpdates(), app.envProblems); title != app.windowTitle {
+		app.windowTitle = title
+		cmd = tea.Batch(cmd, tea.SetWindowTitle(title))
+	}
+	return app, cmd
+}
+
+func (a App) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.WindowSizeMsg:
 		a.width = msg.Width
 		a.height = msg.Height
 		a.ready = true
-		a.help.Width = msg.Width
+		a.help.Width = max(0, msg.Width-1) // minus the help bar's indent
 		a.propagateSize()
 		return a, nil
 
@@ -219,12 +309,37 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.folderStatus = msg.folderStatus
 		a.registryAssets = msg.registryAssets
 		a.registryCommits = msg.registryCommits
+		a.registryWarnings = msg.registryWarnings
+		a.loadGen++
+		a.scanning = make(map[string]bool, len(msg.pending))
+		for _, path := range msg.pending {
+			a.scanning[path] = true
+		}
 		a.refreshActiveFolder()
 		a.pushDataToSubModels()
 		// Re-propagate sizes — isTracked may have changed, affecting height budgets.
 		if a.ready {
 			a.propagateSize()
 		}
+		if !a.sessionRestored {
+			a.restoreSession()
+		}
+		return a, a.scanFoldersCmd(a.loadGen, msg.pending)
+
+	case folderScannedMsg:
+		path := msg.status.Folder.Path
+		if msg.gen != a.loadGen || !a.scanning[path] {
+			return a, nil
+		}
+		delete(a.scanning, path)
+		for i := range a.folderStatus {
+			if a.folderStatus[i].Folder.Path == path {
+				a.folderStatus[i].Assets = msg.status.Assets
+				a.folderStatus[i].Error = msg.status.Error
+				a.bookmarks = a.bookmarks.setScanned(path)
+				break
+			}
+		}
 		return a, nil
 
 	case bookmarkAddedMsg:
@@ -239,7 +354,12 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 	case assetInstalledMsg:
 		if a.activeView == viewAssetWizard {
-			a.assetWizard, _ = a.assetWizard.update(msg, &a)
+			var cmd tea.Cmd
+			a.assetWizard, cmd = a.assetWizard.update(msg, &a)
+			if a.assetWizard.isResolving() {
+				// The wizard asks how to go on, e.g. under which name.
+				return a, cmd
+			}
 		}
 		if msg.err != nil {
 			// Check for clone errors (any source-based asset kind may produce these).
@@ -260,15 +380,24 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, tea.Batch(cmd, a.loadDataCmd)
 		}
 		var cmd tea.Cmd
-		handler, _ := asset.Get(msg.kind)
-		label := msg.name
-		if handler != nil {
-			label = handler.DisplayName() + " " + msg.name
+		status, kind, notice := installSummary(msg.report)
+		a.statusBar, cmd = a.statusBar.showMsg(status, kind)
+		if a.activeView == viewAssetWizard {
+			// The wizard stays open on its summary step, which shows the
+			// note along with everything written.
+			return a, tea.Batch(cmd, a.loadDataCmd)
 		}
-		a.statusBar, cmd = a.statusBar.showMsg(fmt.Sprintf("Installed %s", label), statusSuccess)
 		a.activeView = viewFolder
+		if notice != "" {
+			a.confirm = a.confirm.showNotice(notice)
+		}
 		return a, tea.Batch(cmd, a.loadDataCmd)
 
+	case reportCopiedMsg:
+		var cmd tea.Cmd
+		a.statusBar, cmd = a.statusBar.showMsg("Copied install report", statusSuccess)
+		return a, cmd
+
 	case assetRemovedMsg:
 		if msg.err != nil {
 			var cmd tea.Cmd
@@ -287,24 +416,38 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case updateDoneMsg:
 		if msg.err != nil {
 			var cmd tea.Cmd
-			a.statusBar, cmd = a.statusBar.showMsg(fmt.Sprintf("Error updating %s: %v", msg.skillName, msg.err), statusError)
+			a.statusBar, cmd = a.statusBar.showMsg(fmt.Sprintf("Error updating %s: %v", msg.name, msg.err), statusError)
 			return a, tea.Batch(cmd, a.loadDataCmd)
 		}
 		var cmd tea.Cmd
-		a.statusBar, cmd = a.statusBar.showMsg(fmt.Sprintf("Updated %s", msg.skillName), statusSuccess)
+		if msg.warning != "" {
+			a.statusBar, cmd = a.statusBar.showMsg(fmt.Sprintf("Updated %s; %s", msg.name, msg.warning), statusWarning)
+			return a, tea.Batch(cmd, a.loadDataCmd)
+		}
+		a.statusBar, cmd = a.statusBar.showMsg(fmt.Sprintf("Updated %s", msg.name), statusSuccess)
 		return a, tea.Batch(cmd, a.loadDataCmd)
 
 	case bulkUpdateDoneMsg:
 		var cmd tea.Cmd
 		if msg.errors > 0 {
 			a.statusBar, cmd = a.statusBar.showMsg(
-				fmt.Sprintf("Updated %d skills, %d errors", msg.updated, msg.errors), statusWarning)
+				fmt.Sprintf("Updated %d, %d errors", msg.updated, msg.errors), statusWarning)
+		} else if msg.unattested > 0 {
+			a.statusBar, cmd = a.statusBar.showMsg(
+				fmt.Sprintf("Updated %d, %d not attested", msg.updated, msg.unattested), statusWarning)
 		} else {
 			a.statusBar, cmd = a.statusBar.showMsg(
-				fmt.Sprintf("Updated %d skills", msg.updated), statusSuccess)
+				fmt.Sprintf("Updated %d", msg.updated), statusSuccess)
 		}
 		return a, tea.Batch(cmd, a.loadDataCmd)
 
+	case upgradeAvailableMsg:
+		var cmd tea.Cmd
+		a.statusBar, cmd = a.statusBar.showMsg(
+			fmt.Sprintf("duckrow %s is available (you have %s)", msg.release.Version, strings.TrimPrefix(a.version, "v")),
+			statusWarning)
+		return a, cmd
+
 	case startRegistryRefreshMsg:
 		var cmd tea.Cmd
 		a.statusBar, cmd = a.statusBar.update(taskStartedMsg{})
@@ -315,6 +458,9 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		a.statusBar, cmd = a.statusBar.update(taskDoneMsg{})
 		a.registryCommits = msg.registryCommits
 		a.registryAssets = msg.registryAssets
+		if msg.registryWarnings != nil {
+			a.registryWarnings = msg.registryWarnings
+		}
 		a.refreshActiveFolder()
 		a.pushDataToSubModels()
 		return a, cmd
@@ -385,6 +531,11 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			return a, installCmd
 		}
+		if a.assetWizard.canSkipSystems() {
+			var cmd tea.Cmd
+			a.assetWizard, cmd = a.assetWizard.handleNext()
+			return a, cmd
+		}
 		return a, nil
 
 	case wizardDoneMsg:
@@ -423,27 +574,23 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			a.cloneError = a.cloneError.handleRetryResult(msg)
 			return a, nil
 		}
-		// Full success — dismiss the overlay and reload data.
+		// Full success — dismiss the overlay and reload data, unless the
+		// overlay first asks whether to remember an edited URL.
 		a.cloneError = a.cloneError.handleRetryResult(msg)
-		var successMsg string
-		switch msg.origin {
-		case retryOriginInstall:
-			successMsg = fmt.Sprintf("Installed %s", msg.assetName)
-			a.activeView = viewFolder
-		case retryOriginRegistryAdd:
-			successMsg = fmt.Sprintf("Added registry %s", msg.registryName)
-			// If the clone error was opened from the wizard, go to settings
-			// (the wizard is no longer meaningful after a successful retry).
-			if a.previousView == viewRegistryWizard {
-				a.activeView = viewSettings
-			} else {
-				a.activeView = a.previousView
-			}
+		if a.cloneError.isOffering() {
+			return a, nil
 		}
-		var cmd tea.Cmd
-		a.statusBar, cmd = a.statusBar.showMsg(successMsg, statusSuccess)
-		return a, tea.Batch(cmd, a.loadDataCmd, a.startRegistryRefreshCmd)
-
+		return a.finishCloneRetry(msg, "")
+
+	case cloneOverrideDoneMsg:
+		note := ""
+		switch {
+		case msg.err != nil:
+			note = fmt.Sprintf(" (saving clone URL failed: %v)", msg.err)
+		case msg.key != "":
+			note = fmt.Sprintf(" (clone URL saved for %s)", msg.key)
+		}
+		return a.finishCloneRetry(msg.result, note)
 	case openPreviewMsg:
 		a.activeView = viewSkillPreview
 		a.previewTitle = msg.title
@@ -453,15 +600,28 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		vp := viewport.New(w, max(0, h-4))
 		a.previewViewport = vp
 
-		// Render markdown in background to avoid blocking the UI.
+		// Render markdown in background to avoid blocking the UI, reusing
+		// an earlier rendering of the same content at the same width.
 		rawContent := msg.content
 		cachedRenderer := a.glamourRenderer
+		if a.glamourWidth != w {
+			// The renderer wraps at a fixed width; make a new one.
+			cachedRenderer = nil
+		}
+		style := a.glamourStyle
+		previews := a.previews
 		renderCmd := func() tea.Msg {
+			if style == "" {
+				style = previewStyle()
+			}
+			if rendered, ok := previews.load(rawContent, w, style); ok {
+				return previewRenderedMsg{content: rendered, renderer: cachedRenderer, style: style, width: w}
+			}
 			r := cachedRenderer
 			if r == nil {
 				var err error
 				r, err = glamour.NewTermRenderer(
-					glamour.WithAutoStyle(),
+					glamour.WithStandardStyle(style),
 					glamour.WithWordWrap(w),
 				)
 				if err != nil {
@@ -470,12 +630,11 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			rendered, err := r.Render(rawContent)
 			if err != nil {
-				rendered = rawContent
-			}
-			return previewRenderedMsg{
-				content:  strings.TrimRight(rendered, "\n"),
-				renderer: r,
+				return previewRenderedMsg{content: rawContent, renderer: r, style: style, width: w}
 			}
+			rendered = strings.TrimRight(rendered, "\n")
+			previews.store(rawContent, w, style, rendered)
+			return previewRenderedMsg{content: rendered, renderer: r, style: style, width: w}
 		}
 		return a, tea.Batch(a.previewSpinner.Tick, renderCmd)
 
@@ -485,9 +644,22 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Cache the renderer for future previews.
 		if msg.renderer != nil {
 			a.glamourRenderer = msg.renderer
+			a.glamourWidth = msg.width
+		}
+		if msg.style != "" {
+			a.glamourStyle = msg.style
 		}
 		return a, nil
 
+	case logTickMsg:
+		// Ticks stop once the viewer is closed.
+		if a.activeView != viewLog {
+			return a, nil
+		}
+		var cmd tea.Cmd
+		a.logView, cmd = a.logView.update(msg)
+		return a, cmd
+
 	case spinner.TickMsg:
 		// Route spinner ticks to all active consumers.
 		// Multiple spinners can be active simultaneously (e.g. status bar + preview),
@@ -575,16 +747,33 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return a, cmd
 		}
 
+		// Handle log viewer keys separately — viewport needs arrow/pgup/pgdn,
+		// and the search input owns q.
+		if a.activeView == viewLog {
+			if !a.logView.isSearching() && (key.Matches(msg, keys.Quit) ||
+				key.Matches(msg, keys.Back) && !a.logView.ownsBack()) {
+				a.activeView = a.logView.returnTo
+				return a, nil
+			}
+			var cmd tea.Cmd
+			a.logView, cmd = a.logView.update(msg)
+			return a, cmd
+		}
+
 		// Handle clone error keys — the overlay manages its own input.
 		if a.activeView == viewCloneError {
 			// While retrying, ignore all key input.
 			if a.cloneError.isRetrying() {
 				return a, nil
 			}
-			// Editing mode: don't intercept esc/q globally.
-			if a.cloneError.editing {
+			// Editing mode and the save-override prompt: don't intercept
+			// esc/q globally.
+			if a.cloneError.editing || a.cloneError.isOffering() {
 				break
 			}
+			if key.Matches(msg, keys.Log) {
+				return a.openLog()
+			}
 			if key.Matches(msg, keys.Back) || key.Matches(msg, keys.Quit) {
 				// If we came from the wizard, go to settings instead
 				// (the wizard is in a broken clone state).
@@ -645,20 +834,19 @@ func (a App) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			switch {
 			case key.Matches(msg, keys.Bookmarks):
 				a.activeView = viewBookmarks
-				a.bookmarks = a.bookmarks.activate(a.cwd, a.activeFolder, a.folderStatus)
+				a.bookmarks = a.bookmarks.activate(a.cwd, a.activeFolder, a.folderStatus, a.scanning)
 				return a, nil
 			case key.Matches(msg, keys.Install):
 				if len(a.registryAssets) > 0 {
-					a.activeView = viewInstallPicker
 					// Map the active folder tab to the install filter.
-					filter := installFilter(a.folder.activeKind)
-					a.install = a.install.setMCPData(a.registryAssets, a.activeFolderMCPs)
-					a.install = a.install.activate(filter, a.activeFolder, a.registryAssets, a.activeFolderStatus, system.All())
+					a.openInstallPicker(installFilter(a.folder.activeKind))
 				}
 				return a, nil
 			case key.Matches(msg, keys.Settings):
 				a.activeView = viewSettings
 				return a, nil
+			case key.Matches(msg, keys.Log):
+				return a.openLog()
 			}
 		}
 	}
@@ -700,9 +888,10 @@ func (a App) View() string {
 	//                 │ content                         │
 	//                 ╰─────────────────────────────────╯
 	//                 status bar
+	//
+	// Narrower terminals drop the sidebar; see layoutMode.
 
 	helpBar := a.statusBar.view(a.renderHelpBar())
-	helpBarH := lipgloss.Height(helpBar)
 
 	// Render active view content.
 	content := ""
@@ -717,6 +906,8 @@ func (a App) View() string {
 		content = a.settings.view()
 	case viewSkillPreview:
 		content = a.renderPreview()
+	case viewLog:
+		content = a.logView.view()
 	case viewCloneError:
 		content = a.cloneError.view()
 	case viewRegistryWizard:
@@ -733,34 +924,30 @@ func (a App) View() string {
 	// Determine content panel title.
 	panelTitle := a.contentPanelTitle()
 
-	if a.showSidebar() {
-		// Sidebar layout: content panel + sidebar panel, then status bar below.
-		bodyH := max(0, a.height-helpBarH)
-		contentBoxW := max(0, a.width-sidebarWidth) // sidebar takes sidebarWidth columns
+	boxW, boxH := a.contentBox()
+	padH := a.contentPadH()
 
-		// Clamp content to the text area inside the panel.
-		textW := max(0, contentBoxW-panelBorderH-panelPadH*2)
-		textH := max(0, bodyH-panelBorderV-panelPadV*2)
-		content = clampWidth(content, textW)
-		content = clampHeight(content, textH)
+	// Clamp content to the text area inside the panel.
+	textW := max(0, boxW-panelBorderH-padH*2)
+	textH := max(0, boxH-panelBorderV-panelPadV*2)
+	content = clampWidth(content, textW)
+	content = clampHeight(content, textH)
 
-		contentPanel := renderPanel(panelTitle, content, contentBoxW, bodyH, panelPadH, panelPadV)
-		sidebar := a.sidebar.view()
+	contentPanel := renderPanel(panelTitle, content, boxW, boxH, padH, panelPadV)
 
-		body := lipgloss.JoinHorizontal(lipgloss.Top, contentPanel, sidebar)
+	switch {
+	case a.showSidebar():
+		// Sidebar layout: content panel + sidebar panel, then status bar below.
+		body := lipgloss.JoinHorizontal(lipgloss.Top, contentPanel, a.sidebar.view())
 		return lipgloss.JoinVertical(lipgloss.Left, body, helpBar)
+	case a.showInfoStrip():
+		// Minimal layout: the sidebar's info collapses to a strip stacked
+		// above the content panel.
+		return lipgloss.JoinVertical(lipgloss.Left, a.sidebar.strip(a.width), contentPanel, helpBar)
 	}
 
-	// Non-folder views: full-width content panel + status bar.
-	bodyH := max(0, a.height-helpBarH)
-
-	textW := max(0, a.width-panelBorderH-panelPadH*2)
-	textH := max(0, bodyH-panelBorderV-panelPadV*2)
-	content = clampWidth(content, textW)
-	content = clampHeight(content, textH)
-
-	styled := renderPanel(panelTitle, content, a.width, bodyH, panelPadH, panelPadV)
-	return lipgloss.JoinVertical(lipgloss.Left, styled, helpBar)
+	// Full-width content panel + status bar.
+	return lipgloss.JoinVertical(lipgloss.Left, contentPanel, helpBar)
 }
 
 // contentPanelTitle returns the title for the content panel border based on the active view.
@@ -783,9 +970,13 @@ func (a App) contentPanelTitle() string {
 		return "Settings"
 	case viewSkillPreview:
 		return a.previewTitle
+	case viewLog:
+		return "Log"
 	case viewCloneError:
 		if a.cloneError.isRetrying() {
 			return "Cloning..."
+		} else if a.cloneError.isOffering() {
+			return "Save Clone URL"
 		} else if a.cloneError.postCloneErr != nil {
 			return "Clone Result"
 		}
@@ -796,11 +987,45 @@ func (a App) contentPanelTitle() string {
 	return ""
 }
 
+// layout returns the layout mode for the terminal width.
+func (a App) layout() layoutMode {
+	return layoutForWidth(a.width)
+}
+
 // showSidebar returns true if the sidebar should be visible.
-// It requires the folder view AND enough terminal width so the content
-// panel retains at least minContentWidth columns.
+// It requires the folder view AND the full layout, so the content panel
+// retains at least minContentWidth columns.
 func (a App) showSidebar() bool {
-	return a.activeView == viewFolder && a.width-sidebarWidth >= minContentWidth
+	return a.activeView == viewFolder && a.layout() == layoutFull
+}
+
+// showInfoStrip returns true if the sidebar's info is stacked above the
+// content panel as a strip, in the folder view in minimal mode.
+func (a App) showInfoStrip() bool {
+	return a.activeView == viewFolder && a.layout() == layoutMinimal
+}
+
+// contentPadH returns the horizontal padding inside the content panel.
+func (a App) contentPadH() int {
+	if a.layout() == layoutMinimal {
+		return minimalPadH
+	}
+	return panelPadH
+}
+
+// contentBox returns the outer size of the content panel: the terminal
+// minus the status bar, and minus the sidebar or info strip when shown.
+func (a App) contentBox() (width, height int) {
+	helpBar := a.statusBar.view(a.renderHelpBar())
+	width = a.width
+	height = max(0, a.height-lipgloss.Height(helpBar))
+	switch {
+	case a.showSidebar():
+		width = max(0, a.width-sidebarWidth)
+	case a.showInfoStrip():
+		height = max(0, height-infoStripHeight)
+	}
+	return width, height
 }
 
 func (a App) renderHelpBar() string {
@@ -808,7 +1033,7 @@ func (a App) renderHelpBar() string {
 
 	switch a.activeView {
 	case viewFolder:
-		km = folderHelpKeyMap{updatesAvailable: len(a.updateInfo) > 0}
+		km = folderHelpKeyMap{updatesAvailable: a.pendingUpdates() > 0}
 	case viewBookmarks:
 		km = bookmarksHelpKeyMap{}
 	case viewInstallPicker:
@@ -816,11 +1041,13 @@ func (a App) renderHelpBar() string {
 	case viewAssetWizard:
 		km = a.assetWizard.currentHelpKeyMap()
 	case viewSettings:
-		km = settingsHelpKeyMap{}
+		km = settingsHelpKeyMap{editing: a.settings.isEditing()}
 	case viewSkillPreview:
 		km = previewHelpKeyMap{}
+	case viewLog:
+		km = logHelpKeyMap{searching: a.logView.isSearching()}
 	case viewCloneError:
-		km = cloneErrorHelpKeyMap{editing: a.cloneError.editing, retrying: a.cloneError.isRetrying()}
+		km = cloneErrorHelpKeyMap{editing: a.cloneError.editing, retrying: a.cloneError.isRetrying(), offering: a.cloneError.isOffering()}
 	case viewRegistryWizard:
 		km = wizardHelpKeyMap{}
 	}
@@ -832,7 +1059,7 @@ func (a App) renderHelpBar() string {
 func (a App) renderPreview() string {
 	w, _ := a.innerContentSize()
 	title := viewportTitleStyle.Render(" " + a.previewTitle + " ")
-	line := strings.Repeat("─", max(0, w-lipgloss.Width(title)))
+	line := ruleLine(w - lipgloss.Width(title))
 	header := lipgloss.JoinHorizontal(lipgloss.Center, title, mutedStyle.Render(line))
 
 	if a.previewLoading {
@@ -846,6 +1073,22 @@ func (a App) renderPreview() string {
 	return header + "\n\n" + a.previewViewport.View() + "\n\n" + footer
 }
 
+// openLog opens the log viewer over the current view.
+func (a App) openLog() (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	a.logView, cmd = a.logView.activate(a.activeView)
+	a.activeView = viewLog
+	return a, cmd
+}
+
+// openInstallPicker opens the install picker on the registry assets of
+// the filter's kind.
+func (a *App) openInstallPicker(filter installFilter) {
+	a.activeView = viewInstallPicker
+	a.install = a.install.setMCPData(a.registryAssets, a.activeFolderMCPs, a.config)
+	a.install = a.install.activate(filter, a.activeFolder, a.registryAssets, a.activeFolderStatus, system.All())
+}
+
 // isListFiltering returns true if any list sub-model is currently in filter mode.
 func (a App) isListFiltering() bool {
 	switch a.activeView {
@@ -855,6 +1098,9 @@ func (a App) isListFiltering() bool {
 		return a.bookmarks.list.SettingFilter()
 	case viewInstallPicker:
 		return a.install.list.SettingFilter()
+	case viewSettings:
+		// Typing into an inline input, like filtering, owns q and esc.
+		return a.settings.isEditing()
 	}
 	return false
 }
@@ -867,14 +1113,18 @@ func (a App) loadDataCmd() tea.Msg {
 		return loadedDataMsg{err: err}
 	}
 
+	// Only the active folder is scanned before the first paint; the rest
+	// stream in through scanFoldersCmd.
 	var statuses []core.FolderStatus
+	var pending []string
 	for _, folder := range cfg.Folders {
-		assets, scanErr := a.orch.ScanFolder(folder.Path)
-		statuses = append(statuses, core.FolderStatus{
-			Folder: folder,
-			Assets: assets,
-			Error:  scanErr,
-		})
+		status := core.FolderStatus{Folder: folder}
+		if folder.Path == a.activeFolder {
+			status.Assets, status.Error = a.orch.ScanFolder(folder.Path)
+		} else {
+			pending = append(pending, folder.Path)
+		}
+		statuses = append(statuses, status)
 	}
 
 	regAssets := a.registry.ListAllAssets(cfg.Registries)
@@ -883,22 +1133,54 @@ func (a App) loadDataCmd() tea.Msg {
 	registryCommits := core.BuildRegistryCommitMap(cfg.Registries, a.registry)
 
 	return loadedDataMsg{
-		cfg:             cfg,
-		folderStatus:    statuses,
-		registryAssets:  regAssets,
-		registryCommits: registryCommits,
+		cfg:              cfg,
+		folderStatus:     statuses,
+		pending:          pending,
+		registryAssets:   regAssets,
+		registryCommits:  registryCommits,
+		registryWarnings: a.registry.WarningCounts(cfg.Registries),
 	}
 }
 
+// folderScanSlots bounds how many bookmarked folders are scanned at once,
+// so a long bookmark list doesn't hit the disk with every scan together.
+var folderScanSlots = make(chan struct{}, 4)
+
+// scanFoldersCmd scans the given bookmarked folders concurrently, each
+// result arriving as its own folderScannedMsg tagged with gen.
+func (a App) scanFoldersCmd(gen int, paths []string) tea.Cmd {
+	cmds := make([]tea.Cmd, len(paths))
+	for i, path := range paths {
+		cmds[i] = func() tea.Msg {
+			folderScanSlots <- struct{}{}
+			defer func() { <-folderScanSlots }()
+			assets, err := a.orch.ScanFolder(path)
+			return folderScannedMsg{gen: gen, status: core.FolderStatus{
+				Folder: core.TrackedFolder{Path: path},
+				Assets: assets,
+				Error:  err,
+			}}
+		}
+	}
+	return tea.Batch(cmds...)
+}
+
 func (a *App) refreshActiveFolder() {
 	a.isTracked = false
 	a.activeFolderStatus = nil
 	a.updateInfo = nil
 	a.activeFolderMCPs = nil
+	a.envProblems = 0
 
 	for i := range a.folderStatus {
 		if a.folderStatus[i].Folder.Path == a.activeFolder {
 			a.isTracked = true
+			if a.scanning[a.activeFolder] {
+				// Switched to a bookmark whose scan hasn't arrived yet.
+				fs := &a.folderStatus[i]
+				fs.Assets, fs.Error = a.orch.ScanFolder(a.activeFolder)
+				delete(a.scanning, a.activeFolder)
+			}
 			a.activeFolderStatus = &a.folderStatus[i]
 			break
 		}
@@ -916,7 +1198,7 @@ func (a *App) refreshActiveFolder() {
 	}
 
 	// Load MCPs from lock file for the active folder.
-	lf, lfErr := core.ReadLockFile(a.activeFolder)
+	lf, lfErr := core.ReadLayeredLockFile(a.activeFolder)
 	if lfErr == nil && lf != nil {
 		// Build description lookup from registry assets.
 		mcpDescriptions := make(map[string]string)
@@ -926,6 +1208,8 @@ func (a *App) refreshActiveFolder() {
 			}
 		}
 
+		a.envProblems = countEnvProblems(lf, a.activeFolder, a.config.ConfigDir())
+
 		lockedMCPs := core.AssetsByKind(lf, asset.KindMCP)
 		a.activeFolderMCPs = make([]assetItem, len(lockedMCPs))
 		for i, locked := range lockedMCPs {
@@ -942,16 +1226,19 @@ func (a *App) refreshActiveFolder() {
 	if len(a.registryCommits) > 0 {
 		if lfErr == nil && lf != nil {
 			pathIndex := core.BuildPathIndex(a.registryCommits)
-			a.updateInfo = make(map[string]core.UpdateInfo)
-			for _, locked := range core.AssetsByKind(lf, asset.KindSkill) {
-				if regCommit := core.LookupRegistryCommit(locked.Source, a.registryCommits, pathIndex); regCommit != "" {
-					if locked.Commit != regCommit {
-						a.updateInfo[locked.Name] = core.UpdateInfo{
-							Name:            locked.Name,
-							Source:          locked.Source,
-							InstalledCommit: locked.Commit,
-							AvailableCommit: regCommit,
-							HasUpdate:       true,
+			a.updateInfo = make(map[asset.Kind]map[string]core.UpdateInfo)
+			for _, kind := range updatableKinds {
+				a.updateInfo[kind] = make(map[string]core.UpdateInfo)
+				for _, locked := range core.AssetsByKind(lf, kind) {
+					if regCommit := core.LookupRegistryCommit(locked.Source, a.registryCommits, pathIndex); regCommit != "" {
+						if locked.Commit != regCommit {
+							a.updateInfo[kind][locked.Name] = core.UpdateInfo{
+								Name:            locked.Name,
+								Source:          locked.Source,
+								InstalledCommit: locked.Commit,
+								AvailableCommit: regCommit,
+								HasUpdate:       true,
+							}
 						}
 					}
 				}
@@ -960,31 +1247,104 @@ func (a *App) refreshActiveFolder() {
 	}
 }
 
+// finishCloneRetry dismisses the clone error overlay after a successful
+// retry and reloads data. note is appended to the status message.
+func (a App) finishCloneRetry(msg cloneRetryResultMsg, note string) (tea.Model, tea.Cmd) {
+	var successMsg string
+	kind := statusSuccess
+	switch msg.origin {
+	case retryOriginInstall:
+		var notice string
+		successMsg, kind, notice = installSummary(msg.report)
+		a.activeView = viewFolder
+		if notice != "" {
+			a.confirm = a.confirm.showNotice(notice)
+		}
+	case retryOriginRegistryAdd:
+		successMsg = fmt.Sprintf("Added registry %s", msg.registryName)
+		// If the clone error was opened from the wizard, go to settings
+		// (the wizard is no longer meaningful after a successful retry).
+		if a.previousView == viewRegistryWizard {
+			a.activeView = viewSettings
+		} else {
+			a.activeView = a.previousView
+		}
+	}
+	var cmd tea.Cmd
+	a.statusBar, cmd = a.statusBar.showMsg(successMsg+note, kind)
+	return a, tea.Batch(cmd, a.loadDataCmd, a.startRegistryRefreshCmd)
+}
+
 func (a *App) pushDataToSubModels() {
 	a.folder = a.folder.setData(a.activeFolderStatus, a.isTracked, a.registryAssets, a.updateInfo, a.activeFolderMCPs)
-	a.settings = a.settings.setData(a.cfg, a.version)
+	a.settings = a.settings.setData(a.cfg, a.version, a.registryWarnings, a.activeFolder)
 
 	// Re-activate bookmarks if we're currently viewing them so the list
 	// reflects adds/removes immediately.
 	if a.activeView == viewBookmarks {
-		a.bookmarks = a.bookmarks.activate(a.cwd, a.activeFolder, a.folderStatus)
+		a.bookmarks = a.bookmarks.activate(a.cwd, a.activeFolder, a.folderStatus, a.scanning)
 	}
 
 	// Sidebar shows the active folder, bookmark status, and systems whose own
 	// config files are present (not just duckrow-managed skill dirs).
 	sidebarSystems := system.DisplayNames(system.ActiveInFolder(a.activeFolder))
 	a.sidebar = a.sidebar.setData(a.activeFolder, a.isTracked, sidebarSystems)
+	a.sidebar = a.sidebar.setBadges(a.pendingUpdates(), a.envProblems)
+}
+
+// pendingUpdates returns how many of the active folder's assets have an
+// update available.
+func (a App) pendingUpdates() int {
+	n := 0
+	for _, infos := range a.updateInfo {
+		for _, ui := range infos {
+			if ui.HasUpdate {
+				n++
+			}
+		}
+	}
+	return n
+}
+
+// countEnvProblems returns how many locked MCPs are missing at least one of
+// their required env vars.
+func countEnvProblems(lf *core.LockFile, projectDir, globalDir string) int {
+	resolver := core.NewEnvResolver(projectDir, globalDir)
+	n := 0
+	for _, m := range lf.LockedMCPs() {
+		if _, missing := resolver.ResolveEnv(m.RequiredEnv); len(missing) > 0 {
+			n++
+		}
+	}
+	return n
+}
+
+// windowTitle is the terminal title for the given counts, e.g.
+// "duckrow — 3 updates, 1 env problem".
+func windowTitle(updates, envProblems int) string {
+	var parts []string
+	if updates > 0 {
+		parts = append(parts, plural(updates, "update", "updates"))
+	}
+	if envProblems > 0 {
+		parts = append(parts, plural(envProblems, "env problem", "env problems"))
+	}
+	if len(parts) == 0 {
+		return "duckrow"
+	}
+	return "duckrow — " + strings.Join(parts, ", ")
 }
 
 func (a *App) propagateSize() {
 	w, h := a.innerContentSize()
 	// innerContentSize returns the text content area (after border + padding).
 	// Sub-models render into this space.
-	a.folder = a.folder.setSize(w, h)
+	a.folder = a.folder.setSize(w, h).setLayout(a.layout())
 	a.bookmarks = a.bookmarks.setSize(w, h)
 	a.install = a.install.setSize(w, h)
 	a.settings = a.settings.setSize(w, h)
 	a.cloneError = a.cloneError.setSize(w, h)
+	a.logView = a.logView.setSize(w, h)
 	a.confirm = a.confirm.setSize(w, h)
 	a.statusBar.width = a.width
 
@@ -1022,39 +1382,38 @@ func (a *App) propagateSize() {
 //	╰──────────────────────────────╯
 //	status bar
 func (a App) innerContentSize() (width, height int) {
-	// Help/status bar height.
-	helpBar := a.statusBar.view(a.renderHelpBar())
-	helpBarH := lipgloss.Height(helpBar)
+	boxW, boxH := a.contentBox()
 
 	// Panel frame = border + padding.
-	frameH := panelBorderH + panelPadH*2
+	frameH := panelBorderH + a.contentPadH()*2
 	frameV := panelBorderV + panelPadV*2
 
-	bodyH := max(0, a.height-helpBarH)
-
-	if a.showSidebar() {
-		// Folder view with sidebar: sidebar panel takes sidebarWidth columns.
-		contentBoxW := max(0, a.width-sidebarWidth)
-		width = max(0, contentBoxW-frameH)
-	} else {
-		width = max(0, a.width-frameH)
-	}
-
-	height = max(0, bodyH-frameV)
-
-	return width, height
+	return max(0, boxW-frameH), max(0, boxH-frameV)
 }
 
 func (a *App) setActiveFolder(path string) {
+	a.folderViews[a.activeFolder] = a.folder.viewState()
 	a.activeFolder = path
 	a.refreshActiveFolder()
 	a.pushDataToSubModels()
+	if v, ok := a.folderViews[path]; ok {
+		a.folder = a.folder.restoreView(v)
+	}
 }
 
 func (a *App) reloadConfig() tea.Cmd {
 	return a.loadDataCmd
 }
 
+// cloneURLs returns the clone URL overrides of cfg and the mirrors its
+// registries declare, or neither if the config failed to load.
+func (a *App) cloneURLs(cfg *core.Config, err error) (map[string]string, core.RegistryMirrors) {
+	if err != nil {
+		return nil, nil
+	}
+	return cfg.Settings.CloneURLOverrides, a.registry.Mirrors(cfg.Registries)
+}
+
 // refreshRegistriesCmd refreshes all registries (network call), hydrates
 // unpinned skill commits, and returns the updated commit map plus refreshed
 // skill and MCP lists from the updated manifests.
@@ -1066,13 +1425,20 @@ func (a App) refreshRegistriesCmd() tea.Msg {
 	}
 
 	if len(cfg.Registries) > 0 {
-		// Refresh registries (git pull).
+		// Refresh registries (git pull), unless offline.
 		// Errors are intentionally ignored — stale data is acceptable.
-		_, _ = a.registry.RefreshAll(cfg.Registries)
+		if !core.Offline() {
+			_, _ = a.registry.RefreshAll(cfg.Registries)
+		}
 
-		// Hydrate unpinned skills: resolve latest commits via shallow clone.
+		// Hydrate unpinned skills: resolve latest commits via shallow clone,
+		// reusing caches younger than the configured TTL.
 		// Best-effort — clone errors are silently skipped.
-		a.registry.HydrateRegistryCommits(cfg.Registries, cfg.Settings.CloneURLOverrides)
+		a.registry.Hydrate(cfg.Registries, core.HydrateOptions{
+			Overrides: cfg.Settings.CloneURLOverrides,
+			TTL:       cfg.Settings.CommitCacheTTL(),
+			Disabled:  cfg.Settings.DisableHydration,
+		})
 	}
 
 	registryCommits := core.BuildRegistryCommitMap(cfg.Registries, a.registry)
@@ -1082,8 +1448,9 @@ func (a App) refreshRegistriesCmd() tea.Msg {
 	regAssets := a.registry.ListAllAssets(cfg.Registries)
 
 	return registryRefreshDoneMsg{
-		registryCommits: registryCommits,
-		registryAssets:  regAssets,
+		registryCommits:  registryCommits,
+		registryAssets:   regAssets,
+		registryWarnings: a.registry.WarningCounts(cfg.Registries),
 	}
 }
 
@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+// newLoadTestApp returns an app with three bookmarked folders, each with a
+// skill installed, and the first one active.
+func newLoadTestApp(t *testing.T) (App, []string) {
+	t.Helper()
+	cm := core.NewConfigManagerWithDir(filepath.Join(t.TempDir(), ".duckrow"))
+	var folders []string
+	cfg := &core.Config{}
+	for _, name := range []string{"one", "two", "three"} {
+		dir := filepath.Join(t.TempDir(), name)
+		skill := filepath.Join(dir, ".agents", "skills", name+"-skill")
+		if err := os.MkdirAll(skill, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		md := "---\nname: " + name + "-skill\ndescription: test\n---\n"
+		if err := os.WriteFile(filepath.Join(skill, "SKILL.md"), []byte(md), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		folders = append(folders, dir)
+		cfg.Folders = append(cfg.Folders, core.TrackedFolder{Path: dir})
+	}
+	if err := cm.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+	app := NewApp(cm, "dev")
+	app.activeFolder = folders[0]
+	return app, folders
+}
+
+func scannedSkills(a App, path string) int {
+	for _, fs := range a.folderStatus {
+		if fs.Folder.Path == path {
+			return len(fs.Assets[asset.KindSkill])
+		}
+	}
+	return -1
+}
+
+// folderScans runs a command and returns the folder scans it streams,
+// expanding batches.
+func folderScans(cmd tea.Cmd) []tea.Msg {
+	if cmd == nil {
+		return nil
+	}
+	switch msg := cmd().(type) {
+	case tea.BatchMsg:
+		var msgs []tea.Msg
+		for _, c := range msg {
+			msgs = append(msgs, folderScans(c)...)
+		}
+		return msgs
+	case folderScannedMsg:
+		return []tea.Msg{msg}
+	}
+	return nil
+}
+
+func TestLoadData_ActiveFolderFirst(t *testing.T) {
+	app, folders := newLoadTestApp(t)
+
+	msg, ok := app.loadDataCmd().(loadedDataMsg)
+	if !ok {
+		t.Fatal("loadDataCmd() did not return loadedDataMsg")
+	}
+	if !reflect.DeepEqual(msg.pending, folders[1:]) {
+		t.Errorf("pending = %v, want %v", msg.pending, folders[1:])
+	}
+
+	model, cmd := app.Update(msg)
+	app = model.(App)
+	if got := scannedSkills(app, folders[0]); got != 1 {
+		t.Errorf("active folder has %d skills before streaming, want 1", got)
+	}
+	if got := scannedSkills(app, folders[1]); got != 0 {
+		t.Errorf("pending folder has %d skills before streaming, want 0", got)
+	}
+	if app.activeFolderStatus == nil || len(app.activeFolderStatus.Assets[asset.KindSkill]) != 1 {
+		t.Error("active folder status not set on first paint")
+	}
+
+	scans := folderScans(cmd)
+	if len(scans) != 2 {
+		t.Fatalf("streamed %d messages, want 2", len(scans))
+	}
+	for _, m := range scans {
+		model, _ = app.Update(m)
+		app = model.(App)
+	}
+	for _, f := range folders {
+		if got := scannedSkills(app, f); got != 1 {
+			t.Errorf("%s has %d skills after streaming, want 1", filepath.Base(f), got)
+		}
+	}
+	if len(app.scanning) != 0 {
+		t.Errorf("scanning = %v after streaming, want none", app.scanning)
+	}
+}
+
+func TestLoadData_StaleScanDropped(t *testing.T) {
+	app, folders := newLoadTestApp(t)
+
+	model, cmd := app.Update(app.loadDataCmd())
+	app = model.(App)
+	stale := folderScans(cmd)
+
+	// A second load starts before the first one's scans arrive.
+	model, _ = app.Update(app.loadDataCmd())
+	app = model.(App)
+	for _, m := range stale {
+		model, _ = app.Update(m)
+		app = model.(App)
+	}
+	if got := scannedSkills(app, folders[1]); got != 0 {
+		t.Errorf("stale scan applied: %d skills, want 0", got)
+	}
+
+	// Switching to a folder whose scan hasn't arrived scans it on the spot.
+	app.setActiveFolder(folders[2])
+	if app.activeFolderStatus == nil || len(app.activeFolderStatus.Assets[asset.KindSkill]) != 1 {
+		t.Error("switching to a pending folder did not scan it")
+	}
+	if app.scanning[folders[2]] {
+		t.Error("switched-to folder still marked as scanning")
+	}
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"errors"
 	"fmt"
 	"strings"
 
@@ -8,138 +9,136 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/muesli/termenv"
 
 	"github.com/barysiuk/duckrow/internal/core"
 	"github.com/barysiuk/duckrow/internal/core/asset"
 	"github.com/barysiuk/duckrow/internal/core/system"
 )
 
-// assetWizardModel wraps a wizardModel for asset install flows.
-// Skills: Select Agents → Installing (agent step optional)
-// MCPs: Select Agents → Preview → Env Entry (optional) → Installing
+// assetWizardModel wraps a wizardModel for asset install flows:
+// Select Agents → the flow's extra steps → Installing → Summary. The kind's
+// assetWizardFlow supplies what differs, e.g.
+// Skills: Select Agents → Installing → Summary (agent step optional)
+// MCPs: Select Agents → Preview (with env entry) → Installing → Summary
 type assetWizardModel struct {
 	wizard wizardModel
 
 	asset        core.RegistryAssetInfo
+	flow         assetWizardFlow
 	activeFolder string
 
-	allSystems       []system.System
-	universalSystems []system.System
-	systemBoxes      []agentCheckbox
-	systemCursor     int
-	targetSystems    []system.System
+	allSystems    []system.System
+	alwaysSystems []system.System // always installed to; not selectable
+	systemBoxes   []agentCheckbox
+	systemCursor  int
+	targetSystems []system.System
+	skipSystems   bool  // reuse the last selection without showing the step
+	selectErr     error // why the selection was not accepted
 
-	// MCP env var status.
-	envStatus []envVarStatus
-
-	// Env var entry state.
-	envInput        textinput.Model
-	envMissingVars  []string
-	envCurrentIndex int
-	envSaveProject  bool
-
-	installing bool
+	installing  bool
+	alias       string // given in the Conflict step
+	acceptLarge bool   // confirmed in the Confirm step
 
 	app *App
 }
 
 // ---------------------------------------------------------------------------
-// Skill: Select Agents step
+// Select Agents step
 // ---------------------------------------------------------------------------
 
-type skillAgentStepModel struct {
-	universalAgents []system.System
-	agentBoxes      []agentCheckbox
-	agentCursor     int
-	skillName       string
-}
-
-func newSkillAgentStepModel() skillAgentStepModel {
-	return skillAgentStepModel{}
+// systemSelectStepModel renders the system selection with the flow's
+// selectView. assetWizardModel handles its keys and refreshes it before
+// each render.
+type systemSelectStepModel struct {
+	flow      assetWizardFlow
+	selection systemSelection
+	err       error
 }
 
-func (m skillAgentStepModel) Init() tea.Cmd { return nil }
+func (m systemSelectStepModel) Init() tea.Cmd { return nil }
 
-func (m skillAgentStepModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+func (m systemSelectStepModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, nil
 }
 
-func (m skillAgentStepModel) View() string {
-	var b strings.Builder
+func (m systemSelectStepModel) View() string {
+	if m.flow == nil {
+		return ""
+	}
+	view := m.flow.selectView(m.selection)
+	if m.err != nil {
+		view += "\n\n" + warningStyle.Render("! "+m.err.Error())
+	}
+	return view
+}
 
-	desc := mutedStyle.Render("Select which agents should have access to this skill.")
-	b.WriteString(desc)
-	b.WriteString("\n\n")
+// ---------------------------------------------------------------------------
+// MCP: Preview step
+// ---------------------------------------------------------------------------
 
-	universalLabel := mutedStyle.Render(".agents/skills/ (always installed)")
-	b.WriteString(universalLabel)
-	b.WriteString("\n")
-	for _, a := range m.universalAgents {
-		line := "[x] " + a.DisplayName()
-		b.WriteString(mutedStyle.Render(line))
-		b.WriteString("\n")
-	}
+// mcpPreviewStepModel shows what installing an MCP writes and the status
+// of its env vars. Configure turns it into a form asking for each missing
+// var in turn.
+type mcpPreviewStepModel struct {
+	mcp          asset.RegistryEntry
+	targetAgents []system.System
+	envStatus    []envVarStatus
+	diffs        []string
+	activeFolder string
 
-	b.WriteString("\n")
+	// configuring is set while the env var form is shown.
+	configuring bool
+	envEntry    mcpEnvEntryStepModel
+}
 
-	symlinkLabel := mutedStyle.Render("Agent-specific (optional)")
-	b.WriteString(symlinkLabel)
-	b.WriteString("\n")
-	for i, ab := range m.agentBoxes {
-		check := "[ ]"
-		if ab.checked {
-			check = "[x]"
-		}
+// maxPreviewDiffLines caps the diff lines shown per config file.
+const maxPreviewDiffLines = 12
 
-		prefix := "  "
-		if i == m.agentCursor {
-			prefix = "> "
-		}
+func (m mcpPreviewStepModel) Init() tea.Cmd { return nil }
 
-		line := prefix + check + " " + ab.system.DisplayName()
-		skillsDir := ""
-		if accessor, ok := ab.system.(interface{ SkillsDir() string }); ok {
-			skillsDir = accessor.SkillsDir()
-		}
-		dirHint := " (" + skillsDir + ")"
-		if i == m.agentCursor {
-			b.WriteString(selectedItemStyle.Render(line))
-			b.WriteString(mutedStyle.Render(dirHint))
-		} else {
-			b.WriteString(normalItemStyle.Render(line))
-			b.WriteString(mutedStyle.Render(dirHint))
+func (m mcpPreviewStepModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if m.configuring {
+		return m.updateEnvEntry(msg)
+	}
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, keys.Enter):
+			return m, func() tea.Msg { return wizardNextMsg{} }
+		case key.Matches(keyMsg, keys.Configure):
+			return m.startEnvEntry()
 		}
-		b.WriteString("\n")
 	}
-
-	b.WriteString("\n")
-	b.WriteString(mutedStyle.Render("Press enter to continue"))
-
-	return b.String()
-}
-
-// ---------------------------------------------------------------------------
-// MCP: Select Agents step
-// ---------------------------------------------------------------------------
-
-type mcpAgentStepModel struct {
-	mcp            asset.RegistryEntry
-	mcpAgentBoxes  []agentCheckbox
-	mcpAgentCursor int
-	activeFolder   string
+	return m, nil
 }
 
-func newMCPAgentStepModel() mcpAgentStepModel {
-	return mcpAgentStepModel{}
+func (m mcpPreviewStepModel) stepName() string {
+	if m.configuring {
+		return "Configure"
+	}
+	return "Preview"
 }
 
-func (m mcpAgentStepModel) Init() tea.Cmd { return nil }
+// handlesBack reports whether esc is the step's: the env var form uses it
+// to skip a var.
+func (m mcpPreviewStepModel) handlesBack() bool { return m.configuring }
 
-func (m mcpAgentStepModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	return m, nil
+func (m mcpPreviewStepModel) helpKeys() []key.Binding {
+	if m.configuring {
+		return []key.Binding{keys.Enter, keys.TabSaveLocation, keys.Back}
+	}
+	bindings := []key.Binding{keys.Confirm}
+	if len(m.envStatus) > 0 {
+		bindings = append(bindings, keys.Configure)
+	}
+	return append(bindings, keys.Back)
 }
 
-func (m mcpAgentStepModel) View() string {
+func (m mcpPreviewStepModel) View() string {
+	if m.configuring {
+		return m.envEntry.View()
+	}
+
 	var b strings.Builder
 
 	b.WriteString("Install MCP: ")
@@ -151,75 +150,68 @@ func (m mcpAgentStepModel) View() string {
 	}
 	b.WriteString("\n")
 
-	desc := mutedStyle.Render("Select target agents:")
-	b.WriteString(desc)
-	b.WriteString("\n\n")
+	meta, _ := m.mcp.Meta.(asset.MCPMeta)
+	writeMCPDetails(&b, meta, m.envStatus)
 
-	for i, ab := range m.mcpAgentBoxes {
-		check := "[ ]"
-		if ab.checked {
-			check = "[x]"
-		}
+	b.WriteString("\n")
 
-		prefix := "  "
-		if i == m.mcpAgentCursor {
-			prefix = "> "
+	b.WriteString("Will write to:\n")
+	for i, sys := range m.targetAgents {
+		configPath := resolveMCPConfigPathRel(sys, m.activeFolder)
+		b.WriteString("  " + normalItemStyle.Render(configPath) + "  " + mutedStyle.Render("("+sys.DisplayName()+")"))
+		b.WriteString("\n")
+		if i < len(m.diffs) {
+			writeConfigDiff(&b, m.diffs[i])
 		}
+	}
 
-		line := prefix + check + " " + ab.system.DisplayName()
-		resolvedPath := resolveMCPConfigPathRel(ab.system, m.activeFolder)
-		configHint := " (" + resolvedPath + ")"
-		if i == m.mcpAgentCursor {
-			b.WriteString(selectedItemStyle.Render(line))
-			b.WriteString(mutedStyle.Render(configHint))
-		} else {
-			b.WriteString(normalItemStyle.Render(line))
-			b.WriteString(mutedStyle.Render(configHint))
+	hasMissing := false
+	for _, ev := range m.envStatus {
+		if !ev.isSet {
+			hasMissing = true
+			break
 		}
+	}
+	if hasMissing {
+		b.WriteString("\n")
+		b.WriteString(warningStyle.Render("! Some vars not set — MCP may not work until added"))
 		b.WriteString("\n")
 	}
 
-	b.WriteString("\n")
-	b.WriteString(mutedStyle.Render("Press enter to continue"))
-
 	return b.String()
 }
 
-// ---------------------------------------------------------------------------
-// MCP: Preview step
-// ---------------------------------------------------------------------------
-
-type mcpPreviewStepModel struct {
-	mcp          asset.RegistryEntry
-	targetAgents []system.System
-	envStatus    []envVarStatus
-	activeFolder string
-}
-
-func newMCPPreviewStepModel() mcpPreviewStepModel {
-	return mcpPreviewStepModel{}
-}
-
-func (m mcpPreviewStepModel) Init() tea.Cmd { return nil }
-
-func (m mcpPreviewStepModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	return m, nil
-}
-
-func (m mcpPreviewStepModel) View() string {
-	var b strings.Builder
-
-	b.WriteString("Install MCP: ")
-	b.WriteString(selectedItemStyle.Render(m.mcp.Name))
-	b.WriteString("\n")
-	if m.mcp.Description != "" {
-		b.WriteString(mutedStyle.Render(m.mcp.Description))
+// writeConfigDiff writes the hunks of a unified diff, indented under the
+// config file it applies to, with added and removed lines colored.
+func writeConfigDiff(b *strings.Builder, diff string) {
+	var lines []string
+	for _, line := range strings.Split(strings.TrimSuffix(diff, "\n"), "\n") {
+		if line == "" || strings.HasPrefix(line, "diff ") || strings.HasPrefix(line, "--- ") || strings.HasPrefix(line, "+++ ") {
+			continue
+		}
+		lines = append(lines, line)
+	}
+	for i, line := range lines {
+		if i == maxPreviewDiffLines {
+			b.WriteString("    " + mutedStyle.Render(fmt.Sprintf("… %d more lines", len(lines)-i)) + "\n")
+			break
+		}
+		switch line[0] {
+		case '+':
+			b.WriteString("    " + installedStyle.Render(line))
+		case '-':
+			b.WriteString("    " + warningStyle.Render(line))
+		default:
+			b.WriteString("    " + mutedStyle.Render(line))
+		}
 		b.WriteString("\n")
 	}
-	b.WriteString("\n")
+}
 
-	meta, ok := m.mcp.Meta.(asset.MCPMeta)
-	if ok && meta.URL != "" {
+// writeMCPDetails writes how an MCP is run (command or URL and transport)
+// and the resolution status of its required env vars.
+func writeMCPDetails(b *strings.Builder, meta asset.MCPMeta, envStatus []envVarStatus) {
+	if meta.URL != "" {
 		b.WriteString("URL:      " + normalItemStyle.Render(meta.URL))
 		b.WriteString("\n")
 		if meta.Transport != "" {
@@ -235,48 +227,117 @@ func (m mcpPreviewStepModel) View() string {
 		b.WriteString("\n")
 	}
 
-	if len(m.envStatus) > 0 {
-		for i, ev := range m.envStatus {
-			prefix := "Env:      "
-			if i > 0 {
-				prefix = "          "
-			}
-			if ev.isSet {
-				b.WriteString(prefix + normalItemStyle.Render(ev.name) + "  " + installedStyle.Render("✓ set") + " " + mutedStyle.Render("("+ev.source+")"))
-			} else {
-				b.WriteString(prefix + normalItemStyle.Render(ev.name) + "  " + warningStyle.Render("! not set"))
+	for i, ev := range envStatus {
+		prefix := "Env:      "
+		if i > 0 {
+			prefix = "          "
+		}
+		if ev.isSet {
+			b.WriteString(prefix + normalItemStyle.Render(ev.name) + "  " + installedStyle.Render(glyphs.set) + " " + mutedStyle.Render("("+ev.source+")"))
+		} else {
+			b.WriteString(prefix + normalItemStyle.Render(ev.name) + "  " + warningStyle.Render("! not set"))
+		}
+		b.WriteString("\n")
+	}
+}
+
+// startEnvEntry shows the form for the first missing env var, if any.
+func (m mcpPreviewStepModel) startEnvEntry() (mcpPreviewStepModel, tea.Cmd) {
+	var missing []string
+	for _, ev := range m.envStatus {
+		if !ev.isSet {
+			missing = append(missing, ev.name)
+		}
+	}
+	if len(missing) == 0 {
+		return m, nil
+	}
+
+	input := textinput.New()
+	input.Placeholder = "Enter value..."
+	input.CharLimit = 512
+	input.Width = 40
+	input.Focus()
+
+	m.configuring = true
+	m.envEntry = mcpEnvEntryStepModel{
+		mcpName:        m.mcp.Name,
+		envInput:       input,
+		envMissingVars: missing,
+		envSaveProject: true,
+	}
+	m.envEntry.setEchoMode()
+
+	return m, textinput.Blink
+}
+
+func (m mcpPreviewStepModel) updateEnvEntry(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case envSaveDoneMsg:
+		return m.advanceEnvEntry()
+	case tea.KeyMsg:
+		switch {
+		case key.Matches(msg, keys.Enter):
+			if m.envEntry.envInput.Value() == "" {
+				return m.advanceEnvEntry()
 			}
-			b.WriteString("\n")
+			return m, m.saveEnvVar()
+		case key.Matches(msg, keys.TabSaveLocation):
+			m.envEntry.envSaveProject = !m.envEntry.envSaveProject
+			return m, nil
+		case key.Matches(msg, keys.Back):
+			return m.advanceEnvEntry()
 		}
 	}
 
-	b.WriteString("\n")
+	var cmd tea.Cmd
+	m.envEntry.envInput, cmd = m.envEntry.envInput.Update(msg)
+	return m, cmd
+}
 
-	b.WriteString("Will write to:\n")
-	for _, sys := range m.targetAgents {
-		configPath := resolveMCPConfigPathRel(sys, m.activeFolder)
-		b.WriteString("  " + normalItemStyle.Render(configPath) + "  " + mutedStyle.Render("("+sys.DisplayName()+")"))
-		b.WriteString("\n")
+// saveEnvVar writes the entered value to the chosen env file.
+func (m mcpPreviewStepModel) saveEnvVar() tea.Cmd {
+	varName := m.envEntry.envMissingVars[m.envEntry.envCurrentIndex]
+	value := m.envEntry.envInput.Value()
+	folder := m.activeFolder
+
+	saveDir := core.GlobalConfigDir()
+	if m.envEntry.envSaveProject {
+		saveDir = folder
 	}
 
-	hasMissing := false
-	for _, ev := range m.envStatus {
-		if !ev.isSet {
-			hasMissing = true
-			break
+	return func() tea.Msg {
+		if err := core.WriteEnvVar(saveDir, varName, value); err != nil {
+			return envSaveDoneMsg{err: err}
+		}
+		if saveDir == folder {
+			_ = core.EnsureGitignore(folder)
 		}
+		return envSaveDoneMsg{}
 	}
-	if hasMissing {
-		b.WriteString("\n")
-		b.WriteString(warningStyle.Render("! Some vars not set — MCP may not work until added"))
-		b.WriteString("\n")
+}
+
+// advanceEnvEntry moves on to the next missing var, or back to the
+// preview, with the env status resolved again, after the last one.
+func (m mcpPreviewStepModel) advanceEnvEntry() (tea.Model, tea.Cmd) {
+	m.envEntry.envCurrentIndex++
+
+	if m.envEntry.envCurrentIndex >= len(m.envEntry.envMissingVars) {
+		meta, _ := m.mcp.Meta.(asset.MCPMeta)
+		m.envStatus = mcpEnvStatus(meta, m.activeFolder)
+		m.configuring = false
+		return m, nil
 	}
 
-	return b.String()
+	m.envEntry.envInput.Reset()
+	m.envEntry.envSaveProject = true
+	m.envEntry.setEchoMode()
+
+	return m, textinput.Blink
 }
 
 // ---------------------------------------------------------------------------
-// MCP: Env Entry step
+// MCP: Env Entry form
 // ---------------------------------------------------------------------------
 
 type mcpEnvEntryStepModel struct {
@@ -287,10 +348,14 @@ type mcpEnvEntryStepModel struct {
 	envSaveProject  bool
 }
 
-func (m mcpEnvEntryStepModel) Init() tea.Cmd { return textinput.Blink }
-
-func (m mcpEnvEntryStepModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	return m, nil
+// setEchoMode masks the input when the current var looks sensitive.
+func (m *mcpEnvEntryStepModel) setEchoMode() {
+	if isSensitiveVarName(m.envMissingVars[m.envCurrentIndex]) {
+		m.envInput.EchoMode = textinput.EchoPassword
+		m.envInput.EchoCharacter = '•'
+	} else {
+		m.envInput.EchoMode = textinput.EchoNormal
+	}
 }
 
 func (m mcpEnvEntryStepModel) View() string {
@@ -333,6 +398,23 @@ func resolveMCPConfigPathRel(sys system.System, projectDir string) string {
 	return ""
 }
 
+// preselectedSystems returns the systems to pre-check when installing an
+// asset of kind into folder: the ones last selected for this folder, or else
+// the project's default systems, or else the ones in use. remembered reports
+// whether a last selection was used.
+func preselectedSystems(config *core.ConfigManager, folder string, kind asset.Kind) (systems []system.System, remembered bool) {
+	systems = system.ActiveInFolder(folder)
+	if defaults, _, err := core.DefaultSystems(folder); err == nil && len(defaults) > 0 {
+		systems = defaults
+	}
+	if names, ok := config.LastSystems(folder, kind); ok {
+		if last, err := system.ByNames(names); err == nil {
+			return last, true
+		}
+	}
+	return systems, false
+}
+
 func newAssetWizardModel() assetWizardModel {
 	return assetWizardModel{}
 }
@@ -341,74 +423,48 @@ func newAssetWizardModel() assetWizardModel {
 func (m assetWizardModel) activate(msg openAssetWizardMsg, app *App, width, height int) assetWizardModel {
 	m.app = app
 	m.asset = msg.asset
+	m.flow = wizardFlowFor(m.asset.Kind)
 	m.activeFolder = msg.activeFolder
 	m.allSystems = msg.allSystems
 	m.installing = false
+	m.alias = ""
+	m.acceptLarge = false
 	m.targetSystems = nil
-	m.envStatus = nil
-	m.envMissingVars = nil
+	m.selectErr = nil
 
-	activeSystemNames := system.DisplayNames(system.ActiveInFolder(msg.activeFolder))
+	preselected, remembered := preselectedSystems(app.config, msg.activeFolder, m.asset.Kind)
+	m.skipSystems = false
+	if remembered {
+		cfg, err := app.config.Load()
+		m.skipSystems = err == nil && cfg.Settings.SkipSystemSelection
+	}
+	activeSystemNames := system.DisplayNames(preselected)
 	activeSet := make(map[string]bool, len(activeSystemNames))
 	for _, name := range activeSystemNames {
 		activeSet[name] = true
 	}
 
-	switch m.asset.Kind {
-	case asset.KindSkill:
-		m.universalSystems = system.Universal()
-		nonUniversal := system.NonUniversal()
-		m.systemBoxes = make([]agentCheckbox, len(nonUniversal))
-		for i, s := range nonUniversal {
-			m.systemBoxes[i] = agentCheckbox{system: s, checked: activeSet[s.DisplayName()]}
-		}
-		m.systemCursor = 0
-
-		installStep := newAssetInstallingStepModel()
-		if len(nonUniversal) == 0 {
-			m.wizard = newWizardModel("Install Skill", []wizardStep{{name: "Installing", content: installStep}})
-		} else {
-			selectStep := newSkillAgentStepModel()
-			m.wizard = newWizardModel("Install Skill", []wizardStep{
-				{name: "Select Agents", content: selectStep},
-				{name: "Installing", content: installStep},
-			})
-		}
-	case asset.KindMCP:
-		mcpCapable := system.Supporting(asset.KindMCP)
-		m.systemBoxes = make([]agentCheckbox, len(mcpCapable))
-		for i, s := range mcpCapable {
-			m.systemBoxes[i] = agentCheckbox{system: s, checked: activeSet[s.DisplayName()]}
-		}
-		m.systemCursor = 0
-
-		selectStep := newMCPAgentStepModel()
-		previewStep := newMCPPreviewStepModel()
-		installStep := newAssetInstallingStepModel()
-		m.wizard = newWizardModel("Install MCP", []wizardStep{
-			{name: "Select Agents", content: selectStep},
-			{name: "Preview", content: previewStep},
-			{name: "Installing", content: installStep},
-		})
-	default:
-		supported := system.Supporting(m.asset.Kind)
-		m.systemBoxes = make([]agentCheckbox, len(supported))
-		for i, s := range supported {
-			m.systemBoxes[i] = agentCheckbox{system: s, checked: activeSet[s.DisplayName()]}
-		}
-		m.systemCursor = 0
-
-		title := "Install " + string(m.asset.Kind)
-		if handler, ok := asset.Get(m.asset.Kind); ok {
-			title = "Install " + handler.DisplayName()
+	selectable, always := m.flow.systems()
+	m.alwaysSystems = always
+	m.systemBoxes = make([]agentCheckbox, len(selectable))
+	for i, s := range selectable {
+		m.systemBoxes[i] = agentCheckbox{system: s, checked: activeSet[s.DisplayName()]}
+	}
+	m.systemCursor = 0
+
+	// The extra steps are filled in once the systems are selected.
+	var steps []wizardStep
+	if !m.canAutoInstall() {
+		steps = append(steps, wizardStep{name: "Select Agents", content: systemSelectStepModel{}})
+		for _, name := range m.flow.extraSteps() {
+			steps = append(steps, wizardStep{name: name})
 		}
-		selectStep := newGenericAgentStepModel()
-		installStep := newAssetInstallingStepModel()
-		m.wizard = newWizardModel(title, []wizardStep{
-			{name: "Select Agents", content: selectStep},
-			{name: "Installing", content: installStep},
-		})
 	}
+	steps = append(steps,
+		wizardStep{name: "Installing", content: newAssetInstallingStepModel()},
+		wizardStep{name: "Summary", content: assetSummaryStepModel{}},
+	)
+	m.wizard = newWizardModel("Install "+m.flow.label(), steps)
 
 	m.wizard = m.wizard.setSize(width, height)
 	return m
@@ -423,6 +479,13 @@ func (m assetWizardModel) isInstalling() bool {
 	return m.installing
 }
 
+// isResolving reports whether the install stopped on something the user
+// can resolve in the wizard, such as a name conflict or a skill over the
+// size limits, and waits for them.
+func (m assetWizardModel) isResolving() bool {
+	return m.currentPhase() == assetPhaseResolve
+}
+
 func (m assetWizardModel) selectedRegistryAssetInfo() core.RegistryAssetInfo {
 	return m.asset
 }
@@ -437,222 +500,177 @@ func (m assetWizardModel) selectedTargetSystems() []system.System {
 	return systems
 }
 
+// canAutoInstall reports whether there is nothing to ask: no systems to
+// choose from, the asset goes to those it always goes to, and the flow
+// has no steps of its own.
 func (m assetWizardModel) canAutoInstall() bool {
-	return m.asset.Kind == asset.KindSkill && len(m.systemBoxes) == 0
+	return len(m.systemBoxes) == 0 && len(m.alwaysSystems) > 0 && len(m.flow.extraSteps()) == 0
+}
+
+// canSkipSystems reports whether the system selection step should be
+// skipped in favor of the pre-checked last selection.
+func (m assetWizardModel) canSkipSystems() bool {
+	return m.skipSystems && m.currentPhase() == assetPhaseSelectAgents
+}
+
+// session returns the wizard's asset and checked systems to save for the
+// next launch, or nil once it is installing.
+func (m assetWizardModel) session() *core.WizardSession {
+	if phase := m.currentPhase(); phase == assetPhaseInstalling || phase == assetPhaseResolve || phase == assetPhaseSummary {
+		return nil
+	}
+	names := []string{}
+	for _, s := range m.selectedTargetSystems() {
+		names = append(names, s.Name())
+	}
+	return &core.WizardSession{
+		Kind:     m.asset.Kind,
+		Name:     m.asset.Entry.Name,
+		Registry: m.asset.RegistryRepo,
+		Systems:  names,
+	}
+}
+
+// checkSystems checks the boxes of the named systems and clears the rest.
+func (m assetWizardModel) checkSystems(names []string) assetWizardModel {
+	checked := make(map[string]bool, len(names))
+	for _, name := range names {
+		checked[name] = true
+	}
+	for i := range m.systemBoxes {
+		m.systemBoxes[i].checked = checked[m.systemBoxes[i].system.Name()]
+	}
+	return m
 }
 
 type assetWizardPhase int
 
 const (
 	assetPhaseSelectAgents assetWizardPhase = iota
-	assetPhasePreview
-	assetPhaseEnvEntry
+	assetPhaseFlowStep                      // one of the flow's extra steps
 	assetPhaseInstalling
+	assetPhaseResolve // the install stopped on something to resolve
+	assetPhaseSummary
 )
 
 func (m assetWizardModel) currentPhase() assetWizardPhase {
 	if m.installing {
 		return assetPhaseInstalling
 	}
-	if m.asset.Kind == asset.KindMCP {
-		switch m.wizard.activeIdx {
-		case 0:
-			return assetPhaseSelectAgents
-		case 1:
-			if _, ok := m.wizard.steps[1].content.(mcpEnvEntryStepModel); ok {
-				return assetPhaseEnvEntry
-			}
-			return assetPhasePreview
-		default:
-			return assetPhaseInstalling
-		}
+	step := m.wizard.activeStep()
+	if step == nil {
+		return assetPhaseInstalling
 	}
-	if len(m.wizard.steps) > 1 && m.wizard.activeIdx == 0 {
+	switch step.content.(type) {
+	case systemSelectStepModel:
 		return assetPhaseSelectAgents
+	case assetInstallingStepModel:
+		return assetPhaseInstalling
+	case assetConflictStepModel, assetConfirmStepModel:
+		return assetPhaseResolve
+	case assetSummaryStepModel:
+		return assetPhaseSummary
 	}
-	return assetPhaseInstalling
+	return assetPhaseFlowStep
 }
 
 func (m assetWizardModel) currentHelpKeyMap() assetWizardHelpKeyMap {
-	return assetWizardHelpKeyMap{phase: m.currentPhase(), hasEnvVars: len(m.envStatus) > 0}
+	km := assetWizardHelpKeyMap{phase: m.currentPhase()}
+	if step := m.wizard.activeStep(); step != nil {
+		if h, ok := step.content.(interface{ helpKeys() []key.Binding }); ok {
+			km.stepKeys = h.helpKeys()
+		}
+	}
+	return km
 }
 
 func (m assetWizardModel) update(msg tea.Msg, app *App) (assetWizardModel, tea.Cmd) {
 	m.app = app
 
-	switch msg.(type) {
+	switch msg := msg.(type) {
 	case wizardDoneMsg, wizardBackMsg:
 		return m, nil
 
 	case wizardNextMsg:
 		return m.handleNext()
 
-	case assetInstalledMsg:
-		m.installing = false
-		return m, nil
-
-	case envSaveDoneMsg:
-		return m.advanceEnvEntry()
-	}
-
-	if m.asset.Kind == asset.KindSkill {
-		return m.handleSkillKeys(msg)
-	}
-	if m.asset.Kind == asset.KindMCP {
-		switch m.currentPhase() {
-		case assetPhaseSelectAgents:
-			return m.handleAgentSelectKey(msg)
-		case assetPhasePreview:
-			return m.handlePreviewKey(msg)
-		case assetPhaseEnvEntry:
-			return m.handleEnvEntryKey(msg)
-		case assetPhaseInstalling:
-			return m.handleInstalling(msg)
-		}
-	}
-
-	return m.handleGenericKeys(msg)
-}
-
-func (m assetWizardModel) handleNext() (assetWizardModel, tea.Cmd) {
-	currentIdx := m.wizard.activeIdx
-
-	if m.asset.Kind == asset.KindSkill {
-		var cmd tea.Cmd
-		m.wizard, cmd = m.wizard.update(wizardNextMsg{})
-		installCmd := m.startInstall()
-		if installCmd != nil {
-			return m, tea.Batch(cmd, installCmd)
+	case assetRetryMsg:
+		// Back to installing, with the answer given.
+		if msg.alias != "" {
+			m.alias = msg.alias
 		}
+		m.acceptLarge = m.acceptLarge || msg.acceptLarge
+		m.wizard.steps[m.wizard.activeIdx].content = newAssetInstallingStepModel()
+		cmd := m.startInstall()
 		return m, cmd
-	}
 
-	if m.asset.Kind == asset.KindMCP {
-		if currentIdx == 0 {
-			m.targetSystems = m.selectedTargetSystems()
-			if len(m.targetSystems) == 0 {
-				return m, nil
+	case assetInstalledMsg:
+		m.installing = false
+		if msg.err != nil {
+			var conflictErr *core.ConflictError
+			if errors.As(msg.err, &conflictErr) {
+				return m.askAlias(conflictErr.Conflict)
 			}
-
-			m.resolveEnvStatus()
-			m.wizard.steps[1].content = mcpPreviewStepModel{
-				mcp:          m.asset.Entry,
-				targetAgents: m.targetSystems,
-				envStatus:    m.envStatus,
-				activeFolder: m.activeFolder,
+			var largeErr *core.LargeSkillError
+			if errors.As(msg.err, &largeErr) {
+				return m.askLarge(largeErr)
 			}
-
-			var cmd tea.Cmd
-			m.wizard, cmd = m.wizard.update(wizardNextMsg{})
-			return m, cmd
-		}
-		if currentIdx == 1 {
-			var cmd tea.Cmd
-			m.wizard, cmd = m.wizard.update(wizardNextMsg{})
-			installCmd := m.startInstall()
-			return m, tea.Batch(cmd, installCmd)
+			return m, nil
 		}
+		// Move on to the summary of what was written.
+		last := len(m.wizard.steps) - 1
+		m.wizard.steps[last].content = assetSummaryStepModel{report: msg.report, folder: msg.folder}
+		m.wizard.activeIdx = last
 		return m, nil
 	}
 
-	if currentIdx == 0 {
-		m.targetSystems = m.selectedTargetSystems()
-		var cmd tea.Cmd
-		m.wizard, cmd = m.wizard.update(wizardNextMsg{})
-		installCmd := m.startInstall()
-		return m, tea.Batch(cmd, installCmd)
-	}
-
-	return m, nil
-}
-
-func (m assetWizardModel) handleSkillKeys(msg tea.Msg) (assetWizardModel, tea.Cmd) {
-	if m.currentPhase() == assetPhaseSelectAgents {
-		if keyMsg, ok := msg.(tea.KeyMsg); ok {
-			switch {
-			case key.Matches(keyMsg, keys.Up):
-				if m.systemCursor > 0 {
-					m.systemCursor--
-				}
-				return m, nil
-			case key.Matches(keyMsg, keys.Down):
-				if m.systemCursor < len(m.systemBoxes)-1 {
-					m.systemCursor++
-				}
-				return m, nil
-			case key.Matches(keyMsg, keys.Toggle):
-				if len(m.systemBoxes) > 0 {
-					m.systemBoxes[m.systemCursor].checked = !m.systemBoxes[m.systemCursor].checked
-				}
-				return m, nil
-			case key.Matches(keyMsg, keys.ToggleAll):
-				m.toggleAllAgents()
-				return m, nil
-			case key.Matches(keyMsg, keys.Enter):
-				return m, func() tea.Msg { return wizardNextMsg{} }
-			}
-		}
-	}
-
-	if m.installing {
+	switch m.currentPhase() {
+	case assetPhaseSelectAgents:
+		return m.handleSelectKey(msg)
+	case assetPhaseInstalling:
 		return m.handleInstalling(msg)
+	case assetPhaseSummary:
+		return m.handleSummaryKey(msg)
 	}
 
+	// The flow's own steps handle their keys; the wizard handles esc.
 	var cmd tea.Cmd
 	m.wizard, cmd = m.wizard.update(msg)
 	return m, cmd
 }
 
-func (m assetWizardModel) handleGenericKeys(msg tea.Msg) (assetWizardModel, tea.Cmd) {
+// handleNext moves on from the active step. Leaving the selection step
+// checks the selection and builds the flow's steps for it; reaching the
+// Installing step starts the install.
+func (m assetWizardModel) handleNext() (assetWizardModel, tea.Cmd) {
 	if m.currentPhase() == assetPhaseSelectAgents {
-		if keyMsg, ok := msg.(tea.KeyMsg); ok {
-			switch {
-			case key.Matches(keyMsg, keys.Up):
-				if m.systemCursor > 0 {
-					m.systemCursor--
-				}
-				return m, nil
-			case key.Matches(keyMsg, keys.Down):
-				if m.systemCursor < len(m.systemBoxes)-1 {
-					m.systemCursor++
-				}
-				return m, nil
-			case key.Matches(keyMsg, keys.Toggle):
-				if len(m.systemBoxes) > 0 {
-					m.systemBoxes[m.systemCursor].checked = !m.systemBoxes[m.systemCursor].checked
-				}
-				return m, nil
-			case key.Matches(keyMsg, keys.ToggleAll):
-				m.toggleAllAgents()
-				return m, nil
-			case key.Matches(keyMsg, keys.Enter):
-				return m, func() tea.Msg { return wizardNextMsg{} }
-			}
+		m.targetSystems = m.selectedTargetSystems()
+		if len(m.targetSystems) == 0 && len(m.alwaysSystems) == 0 {
+			m.selectErr = fmt.Errorf("select at least one agent")
+			return m, nil
 		}
-	}
-
-	if m.installing {
-		return m.handleInstalling(msg)
+		req := m.installRequest()
+		if err := m.flow.validate(req); err != nil {
+			m.selectErr = err
+			return m, nil
+		}
+		m.selectErr = nil
+		for i, content := range m.flow.buildSteps(req) {
+			m.wizard.steps[1+i].content = content
+		}
+	} else if m.currentPhase() == assetPhaseInstalling {
+		return m, nil
 	}
 
 	var cmd tea.Cmd
-	m.wizard, cmd = m.wizard.update(msg)
-	return m, cmd
-}
-
-func (m assetWizardModel) handleInstalling(msg tea.Msg) (assetWizardModel, tea.Cmd) {
-	if _, ok := msg.(spinner.TickMsg); ok {
-		step := m.wizard.activeStep()
-		if step != nil {
-			var cmd tea.Cmd
-			step.content, cmd = step.content.Update(msg)
-			return m, cmd
-		}
+	m.wizard, cmd = m.wizard.update(wizardNextMsg{})
+	if m.currentPhase() == assetPhaseInstalling {
+		return m, tea.Batch(cmd, m.startInstall())
 	}
-	return m, nil
+	return m, cmd
 }
 
-func (m assetWizardModel) handleAgentSelectKey(msg tea.Msg) (assetWizardModel, tea.Cmd) {
+func (m assetWizardModel) handleSelectKey(msg tea.Msg) (assetWizardModel, tea.Cmd) {
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
 		switch {
 		case key.Matches(keyMsg, keys.Up):
@@ -683,41 +701,38 @@ func (m assetWizardModel) handleAgentSelectKey(msg tea.Msg) (assetWizardModel, t
 	return m, cmd
 }
 
-func (m assetWizardModel) handlePreviewKey(msg tea.Msg) (assetWizardModel, tea.Cmd) {
-	if keyMsg, ok := msg.(tea.KeyMsg); ok {
-		switch {
-		case key.Matches(keyMsg, keys.Enter):
-			return m, func() tea.Msg { return wizardNextMsg{} }
-		case key.Matches(keyMsg, keys.Configure):
-			return m.startEnvEntry()
+func (m assetWizardModel) handleInstalling(msg tea.Msg) (assetWizardModel, tea.Cmd) {
+	if _, ok := msg.(spinner.TickMsg); ok {
+		step := m.wizard.activeStep()
+		if step != nil {
+			var cmd tea.Cmd
+			step.content, cmd = step.content.Update(msg)
+			return m, cmd
 		}
 	}
-
-	var cmd tea.Cmd
-	m.wizard, cmd = m.wizard.update(msg)
-	return m, cmd
+	return m, nil
 }
 
-func (m assetWizardModel) handleEnvEntryKey(msg tea.Msg) (assetWizardModel, tea.Cmd) {
-	if keyMsg, ok := msg.(tea.KeyMsg); ok {
-		switch {
-		case key.Matches(keyMsg, keys.Enter):
-			value := m.envInput.Value()
-			if value == "" {
-				return m.advanceEnvEntry()
+// handleSummaryKey closes the wizard from the summary, or copies the
+// report. Esc closes it too, as there is nothing to go back to.
+func (m assetWizardModel) handleSummaryKey(msg tea.Msg) (assetWizardModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch {
+	case key.Matches(keyMsg, keys.Close):
+		return m, func() tea.Msg { return wizardDoneMsg{} }
+	case key.Matches(keyMsg, keys.Copy):
+		if step, ok := m.wizard.activeStep().content.(assetSummaryStepModel); ok {
+			text := step.text()
+			return m, func() tea.Msg {
+				copyToClipboard(text)
+				return reportCopiedMsg{}
 			}
-			return m.saveAndAdvanceEnvEntry()
-		case key.Matches(keyMsg, keys.TabSaveLocation):
-			m.envSaveProject = !m.envSaveProject
-			return m, nil
-		case key.Matches(keyMsg, keys.Back):
-			return m.advanceEnvEntry()
 		}
 	}
-
-	var cmd tea.Cmd
-	m.envInput, cmd = m.envInput.Update(msg)
-	return m, cmd
+	return m, nil
 }
 
 func (m assetWizardModel) view() string {
@@ -726,179 +741,51 @@ func (m assetWizardModel) view() string {
 		return ""
 	}
 
-	switch step.content.(type) {
-	case skillAgentStepModel:
-		step.content = skillAgentStepModel{
-			universalAgents: m.universalSystems,
-			agentBoxes:      m.systemBoxes,
-			agentCursor:     m.systemCursor,
-			skillName:       m.asset.Entry.Name,
-		}
-	case mcpAgentStepModel:
-		step.content = mcpAgentStepModel{
-			mcp:            m.asset.Entry,
-			mcpAgentBoxes:  m.systemBoxes,
-			mcpAgentCursor: m.systemCursor,
-			activeFolder:   m.activeFolder,
-		}
-	case mcpPreviewStepModel:
-		step.content = mcpPreviewStepModel{
-			mcp:          m.asset.Entry,
-			targetAgents: m.targetSystems,
-			envStatus:    m.envStatus,
-			activeFolder: m.activeFolder,
-		}
-	case mcpEnvEntryStepModel:
-		step.content = mcpEnvEntryStepModel{
-			mcpName:         m.asset.Entry.Name,
-			envInput:        m.envInput,
-			envMissingVars:  m.envMissingVars,
-			envCurrentIndex: m.envCurrentIndex,
-			envSaveProject:  m.envSaveProject,
-		}
-	case genericAgentStepModel:
-		step.content = genericAgentStepModel{
-			assetName:   m.asset.Entry.Name,
-			assetKind:   m.asset.Kind,
-			agentBoxes:  m.systemBoxes,
-			agentCursor: m.systemCursor,
+	if _, ok := step.content.(systemSelectStepModel); ok {
+		step.content = systemSelectStepModel{
+			flow: m.flow,
+			selection: systemSelection{
+				entry:  m.asset.Entry,
+				always: m.alwaysSystems,
+				boxes:  m.systemBoxes,
+				cursor: m.systemCursor,
+				folder: m.activeFolder,
+			},
+			err: m.selectErr,
 		}
 	}
 
 	return m.wizard.view()
 }
 
+// installRequest returns the request to install the asset for the
+// selected systems.
+func (m assetWizardModel) installRequest() assetInstallRequest {
+	return assetInstallRequest{
+		asset:   m.asset,
+		folder:  m.activeFolder,
+		systems: m.targetSystems,
+		app:     m.app,
+
+		alias:       m.alias,
+		acceptLarge: m.acceptLarge,
+	}
+}
+
 func (m *assetWizardModel) startInstall() tea.Cmd {
 	m.installing = true
 
-	assetInfo := m.asset
-	folder := m.activeFolder
-	app := m.app
+	req := m.installRequest()
+	flow := m.flow
+	selected := system.Names(req.systems)
 
 	installCmd := func() tea.Msg {
-		switch assetInfo.Kind {
-		case asset.KindSkill:
-			sourceStr := assetInfo.Entry.Source
-			if sourceStr == "" {
-				return assetInstalledMsg{kind: assetInfo.Kind, name: assetInfo.Entry.Name, folder: folder, err: fmt.Errorf("missing source")}
-			}
-			source, err := core.ParseSource(sourceStr)
-			if err != nil {
-				return assetInstalledMsg{kind: assetInfo.Kind, name: assetInfo.Entry.Name, folder: folder, err: fmt.Errorf("parsing source %q: %w", sourceStr, err)}
-			}
-
-			cfg, cfgErr := app.config.Load()
-			if cfgErr == nil {
-				source.ApplyCloneURLOverride(cfg.Settings.CloneURLOverrides)
-			}
-
-			var registryCommit string
-			if assetInfo.Entry.Commit != "" {
-				registryCommit = assetInfo.Entry.Commit
-			}
-
-			targetSystems := m.selectedTargetSystems()
-			results, err := app.orch.InstallFromSource(source, asset.KindSkill, core.OrchestratorInstallOptions{
-				TargetDir:       folder,
-				TargetSystems:   targetSystems,
-				IncludeInternal: true,
-				Commit:          registryCommit,
-			})
-			if err != nil {
-				return assetInstalledMsg{kind: assetInfo.Kind, name: assetInfo.Entry.Name, folder: folder, err: err}
-			}
+		_ = req.app.config.SaveLastSystems(req.folder, req.asset.Kind, selected)
 
-			for _, r := range results {
-				entry := asset.LockedAsset{
-					Kind:   asset.KindSkill,
-					Name:   r.Asset.Name,
-					Source: r.Asset.Source,
-					Commit: r.Commit,
-					Ref:    r.Ref,
-				}
-				_ = core.AddOrUpdateAsset(folder, entry)
-			}
-
-			return assetInstalledMsg{kind: assetInfo.Kind, name: assetInfo.Entry.Name, folder: folder}
-		case asset.KindMCP:
-			meta, ok := assetInfo.Entry.Meta.(asset.MCPMeta)
-			if !ok {
-				return assetInstalledMsg{kind: assetInfo.Kind, name: assetInfo.Entry.Name, folder: folder, err: fmt.Errorf("invalid MCP metadata")}
-			}
-
-			mcpAsset := asset.Asset{
-				Kind:        asset.KindMCP,
-				Name:        assetInfo.Entry.Name,
-				Description: assetInfo.Entry.Description,
-				Meta:        meta,
-			}
-
-			targetSystems := m.targetSystems
-			for _, sys := range targetSystems {
-				if err := sys.Install(mcpAsset, folder, system.InstallOptions{}); err != nil {
-					return assetInstalledMsg{kind: assetInfo.Kind, name: assetInfo.Entry.Name, folder: folder, err: err}
-				}
-			}
-
-			lockEntry := asset.LockedAsset{
-				Kind: asset.KindMCP,
-				Name: mcpAsset.Name,
-				Data: map[string]any{
-					"registry":   assetInfo.RegistryRepo,
-					"configHash": core.ComputeConfigHash(meta),
-				},
-			}
-			if required := core.ExtractRequiredEnv(meta.Env); len(required) > 0 {
-				lockEntry.Data["requiredEnv"] = required
-			}
-			_ = core.AddOrUpdateAsset(folder, lockEntry)
-
-			return assetInstalledMsg{kind: assetInfo.Kind, name: assetInfo.Entry.Name, folder: folder}
-		case asset.KindAgent:
-			sourceStr := assetInfo.Entry.Source
-			if sourceStr == "" {
-				return assetInstalledMsg{kind: assetInfo.Kind, name: assetInfo.Entry.Name, folder: folder, err: fmt.Errorf("missing source")}
-			}
-			source, err := core.ParseSource(sourceStr)
-			if err != nil {
-				return assetInstalledMsg{kind: assetInfo.Kind, name: assetInfo.Entry.Name, folder: folder, err: fmt.Errorf("parsing source %q: %w", sourceStr, err)}
-			}
-
-			cfg, cfgErr := app.config.Load()
-			if cfgErr == nil {
-				source.ApplyCloneURLOverride(cfg.Settings.CloneURLOverrides)
-			}
-
-			var registryCommit string
-			if assetInfo.Entry.Commit != "" {
-				registryCommit = assetInfo.Entry.Commit
-			}
-
-			targetSystems := m.selectedTargetSystems()
-			results, err := app.orch.InstallFromSource(source, asset.KindAgent, core.OrchestratorInstallOptions{
-				TargetDir:     folder,
-				TargetSystems: targetSystems,
-				Commit:        registryCommit,
-			})
-			if err != nil {
-				return assetInstalledMsg{kind: assetInfo.Kind, name: assetInfo.Entry.Name, folder: folder, err: err}
-			}
-
-			for _, r := range results {
-				entry := asset.LockedAsset{
-					Kind:   asset.KindAgent,
-					Name:   r.Asset.Name,
-					Source: r.Asset.Source,
-					Commit: r.Commit,
-					Ref:    r.Ref,
-				}
-				_ = core.AddOrUpdateAsset(folder, entry)
-			}
-
-			return assetInstalledMsg{kind: assetInfo.Kind, name: assetInfo.Entry.Name, folder: folder}
-		default:
-			return assetInstalledMsg{kind: assetInfo.Kind, name: assetInfo.Entry.Name, folder: folder, err: fmt.Errorf("unsupported asset kind %s", assetInfo.Kind)}
+		if err := core.CheckPlatform(req.asset.Kind, req.asset.Entry); err != nil {
+			return req.done(err)
 		}
+		return flow.install(req)
 	}
 
 	step := m.wizard.activeStep()
@@ -925,210 +812,236 @@ func (m *assetWizardModel) toggleAllAgents() {
 }
 
 // ---------------------------------------------------------------------------
-// Generic: Select Agents step
+// Installing step
 // ---------------------------------------------------------------------------
 
-type genericAgentStepModel struct {
-	assetName   string
-	assetKind   asset.Kind
-	agentBoxes  []agentCheckbox
-	agentCursor int
+type assetInstallingStepModel struct {
+	spinner spinner.Model
 }
 
-func newGenericAgentStepModel() genericAgentStepModel {
-	return genericAgentStepModel{}
+func newAssetInstallingStepModel() assetInstallingStepModel {
+	s := newSpinner()
+	return assetInstallingStepModel{spinner: s}
 }
 
-func (m genericAgentStepModel) Init() tea.Cmd { return nil }
+func (m assetInstallingStepModel) Init() tea.Cmd {
+	return m.spinner.Tick
+}
 
-func (m genericAgentStepModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	return m, nil
+func (m assetInstallingStepModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	var cmd tea.Cmd
+	m.spinner, cmd = m.spinner.Update(msg)
+	return m, cmd
 }
 
-func (m genericAgentStepModel) View() string {
-	var b strings.Builder
+func (m assetInstallingStepModel) View() string {
+	return m.spinner.View() + " Installing... please wait"
+}
 
-	label := strings.ToUpper(string(m.assetKind))
-	if handler, ok := asset.Get(m.assetKind); ok {
-		label = handler.DisplayName()
+// ---------------------------------------------------------------------------
+// Conflict step
+// ---------------------------------------------------------------------------
+
+// assetRetryMsg asks the wizard to install again, under alias if it is
+// set, or accepting a skill over the size limits.
+type assetRetryMsg struct {
+	alias       string
+	acceptLarge bool
+}
+
+// askAlias replaces the Installing step with the Conflict step, which asks
+// for another name to install the asset under than the one in conflict.
+func (m assetWizardModel) askAlias(c core.AssetConflict) (assetWizardModel, tea.Cmd) {
+	input := textinput.New()
+	input.Placeholder = "Enter name..."
+	input.CharLimit = 128
+	input.Width = 40
+	if m.asset.RegistryName != "" {
+		input.SetValue(core.NamespacedName(m.asset.RegistryName, c.Name))
 	}
+	input.Focus()
 
-	b.WriteString("Install " + label + ": ")
-	b.WriteString(selectedItemStyle.Render(m.assetName))
-	b.WriteString("\n\n")
+	m.wizard.steps[m.wizard.activeIdx].content = assetConflictStepModel{conflict: c, input: input}
+	return m, textinput.Blink
+}
 
-	desc := mutedStyle.Render("Select target agents:")
-	b.WriteString(desc)
-	b.WriteString("\n\n")
+// assetConflictStepModel asks for the name to install an asset under when
+// its own is locked from elsewhere. Enter installs it under the name
+// given; esc cancels the install.
+type assetConflictStepModel struct {
+	conflict core.AssetConflict
+	input    textinput.Model
+	err      error // why the name given was not accepted
+}
 
-	for i, ab := range m.agentBoxes {
-		check := "[ ]"
-		if ab.checked {
-			check = "[x]"
-		}
+func (m assetConflictStepModel) Init() tea.Cmd { return nil }
 
-		prefix := "  "
-		if i == m.agentCursor {
-			prefix = "> "
+func (m assetConflictStepModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, keys.Enter):
+			alias := strings.TrimSpace(m.input.Value())
+			if alias == "" {
+				m.err = fmt.Errorf("enter a name")
+				return m, nil
+			}
+			if err := core.ValidateAlias(alias); err != nil {
+				m.err = err
+				return m, nil
+			}
+			return m, func() tea.Msg { return assetRetryMsg{alias: alias} }
+		case key.Matches(keyMsg, keys.Back):
+			return m, func() tea.Msg { return wizardBackMsg{} }
 		}
+	}
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
 
-		line := prefix + check + " " + ab.system.DisplayName()
-		if i == m.agentCursor {
-			b.WriteString(selectedItemStyle.Render(line))
-		} else {
-			b.WriteString(normalItemStyle.Render(line))
-		}
+func (m assetConflictStepModel) stepName() string { return "Conflict" }
+
+// handlesBack reports that esc is the step's: it cancels the install
+// rather than going back to a step that already ran.
+func (m assetConflictStepModel) handlesBack() bool { return true }
+
+func (m assetConflictStepModel) helpKeys() []key.Binding {
+	return []key.Binding{keys.Enter, keys.Back}
+}
+
+func (m assetConflictStepModel) View() string {
+	var b strings.Builder
+	b.WriteString(warningStyle.Render("! " + m.conflict.String()))
+	b.WriteString("\n\n")
+	b.WriteString("Install as: " + m.input.View())
+	b.WriteString("\n")
+	if m.err != nil {
+		b.WriteString("\n")
+		b.WriteString(warningStyle.Render("! " + m.err.Error()))
 		b.WriteString("\n")
 	}
-
 	b.WriteString("\n")
-	b.WriteString(mutedStyle.Render("Press enter to continue"))
-
+	b.WriteString(mutedStyle.Render("Press enter to install under this name, esc to cancel"))
 	return b.String()
 }
 
 // ---------------------------------------------------------------------------
-// Installing step
+// Confirm step
 // ---------------------------------------------------------------------------
 
-type assetInstallingStepModel struct {
-	spinner spinner.Model
+// askLarge replaces the Installing step with the Confirm step, which asks
+// whether to install a skill over the size limits anyway.
+func (m assetWizardModel) askLarge(e *core.LargeSkillError) (assetWizardModel, tea.Cmd) {
+	m.wizard.steps[m.wizard.activeIdx].content = assetConfirmStepModel{
+		message: e.Error(),
+		retry:   assetRetryMsg{acceptLarge: true},
+	}
+	return m, nil
 }
 
-func newAssetInstallingStepModel() assetInstallingStepModel {
-	s := spinner.New(
-		spinner.WithSpinner(spinner.Dot),
-		spinner.WithStyle(spinnerStyle),
-	)
-	return assetInstallingStepModel{spinner: s}
+// assetConfirmStepModel asks whether to go on with an install that
+// stopped, e.g. on a skill over the size limits. y installs again with
+// retry's answer; n or esc cancels the install.
+type assetConfirmStepModel struct {
+	message string
+	retry   assetRetryMsg
 }
 
-func (m assetInstallingStepModel) Init() tea.Cmd {
-	return m.spinner.Tick
+func (m assetConfirmStepModel) Init() tea.Cmd { return nil }
+
+func (m assetConfirmStepModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(keyMsg, confirmYesKey):
+			retry := m.retry
+			return m, func() tea.Msg { return retry }
+		case key.Matches(keyMsg, confirmNoKey), key.Matches(keyMsg, keys.Back):
+			return m, func() tea.Msg { return wizardBackMsg{} }
+		}
+	}
+	return m, nil
 }
 
-func (m assetInstallingStepModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	var cmd tea.Cmd
-	m.spinner, cmd = m.spinner.Update(msg)
-	return m, cmd
+func (m assetConfirmStepModel) stepName() string { return "Confirm" }
+
+// handlesBack reports that esc is the step's: it cancels the install
+// rather than going back to a step that already ran.
+func (m assetConfirmStepModel) handlesBack() bool { return true }
+
+func (m assetConfirmStepModel) helpKeys() []key.Binding {
+	return []key.Binding{confirmYesKey, confirmNoKey}
 }
 
-func (m assetInstallingStepModel) View() string {
-	return m.spinner.View() + " Installing... please wait"
+func (m assetConfirmStepModel) View() string {
+	var b strings.Builder
+	b.WriteString(warningStyle.Render("! " + m.message))
+	b.WriteString("\n\n")
+	b.WriteString("Install anyway?")
+	b.WriteString("\n\n")
+	b.WriteString(mutedStyle.Render("Press y to install, n or esc to cancel"))
+	return b.String()
 }
 
 // ---------------------------------------------------------------------------
-// MCP env resolution helpers
+// Summary step
 // ---------------------------------------------------------------------------
 
-func (m *assetWizardModel) resolveEnvStatus() {
-	m.envStatus = nil
-	meta, ok := m.asset.Entry.Meta.(asset.MCPMeta)
-	if ok && meta.URL == "" && len(meta.Env) > 0 {
-		requiredVars := core.ExtractRequiredEnv(meta.Env)
-		if len(requiredVars) > 0 {
-			resolver := core.NewEnvResolver(m.activeFolder, "")
-			results := resolver.ResolveEnvWithSource(requiredVars)
-			for _, r := range results {
-				status := envVarStatus{name: r.Name}
-				if r.Source != "" {
-					status.isSet = true
-					status.source = string(r.Source)
-				}
-				m.envStatus = append(m.envStatus, status)
-			}
-		}
-	}
-}
+// copyToClipboard copies text to the clipboard through the terminal (OSC
+// 52), which works over SSH too. Tests replace it.
+var copyToClipboard = termenv.Copy
 
-func (m assetWizardModel) startEnvEntry() (assetWizardModel, tea.Cmd) {
-	m.envMissingVars = nil
-	for _, ev := range m.envStatus {
-		if !ev.isSet {
-			m.envMissingVars = append(m.envMissingVars, ev.name)
-		}
-	}
+// reportCopiedMsg is sent once the summary's report has been copied.
+type reportCopiedMsg struct{}
 
-	if len(m.envMissingVars) == 0 {
-		return m, nil
-	}
+// assetSummaryStepModel shows what the install wrote, until the user closes
+// the wizard.
+type assetSummaryStepModel struct {
+	report core.InstallReport
+	folder string
+}
 
-	m.envCurrentIndex = 0
-	m.envSaveProject = true
-	m.envInput = textinput.New()
-	m.envInput.Placeholder = "Enter value..."
-	m.envInput.CharLimit = 512
-	m.envInput.Width = 40
-	m.envInput.Focus()
+func (m assetSummaryStepModel) Init() tea.Cmd { return nil }
 
-	if isSensitiveVarName(m.envMissingVars[0]) {
-		m.envInput.EchoMode = textinput.EchoPassword
-		m.envInput.EchoCharacter = '•'
-	} else {
-		m.envInput.EchoMode = textinput.EchoNormal
-	}
+func (m assetSummaryStepModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) { return m, nil }
 
-	m.wizard.steps[1].content = mcpEnvEntryStepModel{
-		mcpName:         m.asset.Entry.Name,
-		envInput:        m.envInput,
-		envMissingVars:  m.envMissingVars,
-		envCurrentIndex: m.envCurrentIndex,
-		envSaveProject:  m.envSaveProject,
-	}
-	m.wizard.steps[1].name = "Configure"
+// text returns the report as plain text, as it is copied.
+func (m assetSummaryStepModel) text() string {
+	return installReportText(m.report, m.folder)
+}
 
-	return m, textinput.Blink
+func (m assetSummaryStepModel) View() string {
+	var b strings.Builder
+	b.WriteString(m.text())
+	b.WriteString("\n\n")
+	b.WriteString(mutedStyle.Render("Press enter to close, c to copy this report"))
+	return b.String()
 }
 
-func (m assetWizardModel) saveAndAdvanceEnvEntry() (assetWizardModel, tea.Cmd) {
-	varName := m.envMissingVars[m.envCurrentIndex]
-	value := m.envInput.Value()
+// ---------------------------------------------------------------------------
+// MCP env resolution helpers
+// ---------------------------------------------------------------------------
 
-	var saveDir string
-	if m.envSaveProject {
-		saveDir = m.activeFolder
-	} else {
-		saveDir = core.GlobalConfigDir()
+// mcpEnvStatus resolves the env vars a stdio MCP requires against the
+// process env and the project and global env files.
+func mcpEnvStatus(meta asset.MCPMeta, folder string) []envVarStatus {
+	if meta.URL != "" || len(meta.Env) == 0 {
+		return nil
 	}
-
-	saveCmd := func() tea.Msg {
-		if err := core.WriteEnvVar(saveDir, varName, value); err != nil {
-			return envSaveDoneMsg{err: err}
-		}
-		if saveDir == m.activeFolder {
-			_ = core.EnsureGitignore(m.activeFolder)
-		}
-		return envSaveDoneMsg{}
+	requiredVars := core.ExtractRequiredEnv(meta.Env)
+	if len(requiredVars) == 0 {
+		return nil
 	}
-
-	return m, saveCmd
-}
-
-func (m assetWizardModel) advanceEnvEntry() (assetWizardModel, tea.Cmd) {
-	m.envCurrentIndex++
-
-	if m.envCurrentIndex >= len(m.envMissingVars) {
-		m.resolveEnvStatus()
-		m.wizard.steps[1].name = "Preview"
-		m.wizard.steps[1].content = mcpPreviewStepModel{
-			mcp:          m.asset.Entry,
-			targetAgents: m.targetSystems,
-			envStatus:    m.envStatus,
-			activeFolder: m.activeFolder,
+	var statuses []envVarStatus
+	resolver := core.NewEnvResolver(folder, "")
+	for _, r := range resolver.ResolveEnvWithSource(requiredVars) {
+		status := envVarStatus{name: r.Name}
+		if r.Source != "" {
+			status.isSet = true
+			status.source = string(r.Source)
 		}
-		return m, nil
+		statuses = append(statuses, status)
 	}
-
-	m.envInput.Reset()
-	m.envSaveProject = true
-	if isSensitiveVarName(m.envMissingVars[m.envCurrentIndex]) {
-		m.envInput.EchoMode = textinput.EchoPassword
-		m.envInput.EchoCharacter = '•'
-	} else {
-		m.envInput.EchoMode = textinput.EchoNormal
-	}
-
-	return m, textinput.Blink
+	return statuses
 }
 
 // ---------------------------------------------------------------------------
@@ -1136,25 +1049,21 @@ func (m assetWizardModel) advanceEnvEntry() (assetWizardModel, tea.Cmd) {
 // ---------------------------------------------------------------------------
 
 type assetWizardHelpKeyMap struct {
-	phase      assetWizardPhase
-	hasEnvVars bool
+	phase    assetWizardPhase
+	stepKeys []key.Binding // a flow step's own keys, if it has any
 }
 
 func (k assetWizardHelpKeyMap) ShortHelp() []key.Binding {
 	switch k.phase {
 	case assetPhaseSelectAgents:
 		return []key.Binding{keys.Up, keys.Down, keys.Toggle, keys.ToggleAll, keys.Next, keys.Back}
-	case assetPhasePreview:
-		bindings := []key.Binding{keys.Confirm}
-		if k.hasEnvVars {
-			bindings = append(bindings, keys.Configure)
-		}
-		bindings = append(bindings, keys.Back)
-		return bindings
-	case assetPhaseEnvEntry:
-		return []key.Binding{keys.Enter, keys.TabSaveLocation, keys.Back}
 	case assetPhaseInstalling:
 		return []key.Binding{}
+	case assetPhaseSummary:
+		return []key.Binding{keys.Close, keys.Copy}
+	}
+	if k.stepKeys != nil {
+		return k.stepKeys
 	}
 	return []key.Binding{keys.Enter, keys.Back}
 }
@@ -0,0 +1,493 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+// assetWizardFlow adapts the asset wizard to one asset kind. The wizard
+// always starts by selecting systems (unless there are none to choose
+// from) and ends by installing; a flow decides which systems are offered
+// and how they are shown, checks the selection, adds steps in between,
+// such as a preview, and does the install.
+//
+// Kinds without a registered flow get sourceWizardFlow, which installs an
+// entry from its source repository for the selected systems, so a new kind
+// gets a working wizard without changes here. Flows embed baseWizardFlow
+// for the defaults.
+type assetWizardFlow interface {
+	// label names the kind in titles, e.g. "Skill" in "Install Skill".
+	label() string
+
+	// systems returns the systems offered for selection, and those the
+	// asset always goes to, which are listed but can't be unchecked.
+	systems() (selectable, always []system.System)
+
+	// selectView renders the system selection step.
+	selectView(s systemSelection) string
+
+	// validate checks the selection before the wizard moves on. Its error
+	// is shown on the selection step.
+	validate(req assetInstallRequest) error
+
+	// extraSteps names the steps shown between selecting systems and
+	// installing, e.g. "Preview"; buildSteps returns their content, in the
+	// same order, once the systems are selected. A step moves the wizard on
+	// by emitting wizardNextMsg, and may implement stepName, handlesBack,
+	// and helpKeys (see wizardStep).
+	extraSteps() []string
+	buildSteps(req assetInstallRequest) []tea.Model
+
+	// install installs the asset. It runs in a tea.Cmd.
+	install(req assetInstallRequest) assetInstalledMsg
+}
+
+// assetInstallRequest is an asset to install, where, and for which
+// selected systems.
+type assetInstallRequest struct {
+	asset   core.RegistryAssetInfo
+	folder  string
+	systems []system.System
+	app     *App
+
+	// alias is the name given in the Conflict step to install the asset
+	// under instead of one already locked from elsewhere, and acceptLarge
+	// is set once the Confirm step accepted a skill over the size limits.
+	alias       string
+	acceptLarge bool
+}
+
+// done returns the message reporting an install that failed with err.
+func (r assetInstallRequest) done(err error) assetInstalledMsg {
+	return r.reported(r.newReport(), err)
+}
+
+// reported returns the message reporting the install's outcome.
+func (r assetInstallRequest) reported(report core.InstallReport, err error) assetInstalledMsg {
+	return assetInstalledMsg{kind: r.asset.Kind, name: r.asset.Entry.Name, folder: r.folder, report: report, err: err}
+}
+
+// newReport starts the report of the install, with what the registry entry
+// says.
+func (r assetInstallRequest) newReport() core.InstallReport {
+	return core.InstallReport{
+		Kind:               r.asset.Kind,
+		Registry:           r.asset.RegistryName,
+		PostInstallMessage: r.asset.Entry.PostInstallMessage,
+	}
+}
+
+// lock records entry in the folder's lock file, noting the outcome in
+// report. The TUI always locks what it installs.
+func (r assetInstallRequest) lock(report *core.InstallReport, entry asset.LockedAsset) {
+	if err := core.AddOrUpdateAsset(r.folder, entry); err != nil {
+		report.Warn("failed to update lock file: %v", err)
+		return
+	}
+	report.LockFile = "duckrow.lock.json"
+	r.attest(report, entry)
+}
+
+// attest writes an attestation of an installed asset when the attest
+// setting is on, noting it, or why it could not be written, in report.
+func (r assetInstallRequest) attest(report *core.InstallReport, entry asset.LockedAsset) {
+	if r.app == nil {
+		return
+	}
+	cfg, err := r.app.config.Load()
+	if err != nil {
+		return
+	}
+	path, err := attestAsset(r.app, cfg, r.folder, entry, "install")
+	if err != nil {
+		report.Warn("%v", err)
+	} else if path != "" {
+		report.Attestations = append(report.Attestations, path)
+	}
+}
+
+// attestAsset attests an asset installed or updated (op) in folder (see
+// core.AttestInstalled).
+func attestAsset(app *App, cfg *core.Config, folder string, entry asset.LockedAsset, op string) (string, error) {
+	return core.AttestInstalled(cfg, folder, entry, core.AttestOptions{
+		ConfigDir: app.config.ConfigDir(),
+		Version:   app.version,
+		Operation: op,
+	})
+}
+
+// sourceReport reports and locks the results of installing from the
+// entry's source. An asset whose commit could not be determined is locked
+// unpinned, which the report warns about.
+func (r assetInstallRequest) sourceReport(results []core.OrchestratorInstallResult) core.InstallReport {
+	entry := r.asset.Entry
+	report := r.newReport()
+	for _, res := range results {
+		report.Assets = append(report.Assets, core.NewInstallReportAsset(res, r.folder))
+		if res.Commit == "" {
+			report.Warn("could not determine the commit of %s; it is not pinned in the lock file", res.Asset.Name)
+		}
+		r.lock(&report, res.LockEntry(entry.Platforms, entry.Tags))
+	}
+	return report
+}
+
+// systemSelection is what the system selection step shows.
+type systemSelection struct {
+	entry  asset.RegistryEntry
+	always []system.System
+	boxes  []agentCheckbox
+	cursor int
+	folder string
+}
+
+// assetWizardFlows holds the flows of kinds that need more than
+// sourceWizardFlow.
+var assetWizardFlows = map[asset.Kind]assetWizardFlow{
+	asset.KindSkill: skillWizardFlow{baseWizardFlow{kind: asset.KindSkill}},
+	asset.KindMCP:   mcpWizardFlow{baseWizardFlow{kind: asset.KindMCP}},
+}
+
+// wizardFlowFor returns the wizard flow for kind.
+func wizardFlowFor(kind asset.Kind) assetWizardFlow {
+	if flow, ok := assetWizardFlows[kind]; ok {
+		return flow
+	}
+	return sourceWizardFlow{baseWizardFlow{kind: kind}}
+}
+
+// ---------------------------------------------------------------------------
+// Defaults
+// ---------------------------------------------------------------------------
+
+// baseWizardFlow implements everything but install for a kind written
+// into the systems that support it: those are offered, listed under the
+// entry's name and description, and nothing comes between selecting and
+// installing.
+type baseWizardFlow struct {
+	kind asset.Kind
+}
+
+func (f baseWizardFlow) label() string {
+	if handler, ok := asset.Get(f.kind); ok {
+		return handler.DisplayName()
+	}
+	return strings.ToUpper(string(f.kind))
+}
+
+func (f baseWizardFlow) systems() (selectable, always []system.System) {
+	return system.Supporting(f.kind), nil
+}
+
+func (f baseWizardFlow) selectView(s systemSelection) string {
+	return checkboxSelectView(f.label(), s, nil)
+}
+
+func (f baseWizardFlow) validate(req assetInstallRequest) error { return nil }
+
+func (f baseWizardFlow) extraSteps() []string { return nil }
+
+func (f baseWizardFlow) buildSteps(req assetInstallRequest) []tea.Model { return nil }
+
+// checkboxSelectView renders a selection step that lists the entry, then
+// one checkbox per system, with hint's text (if any) beside it.
+func checkboxSelectView(label string, s systemSelection, hint func(system.System) string) string {
+	var b strings.Builder
+
+	b.WriteString("Install " + label + ": ")
+	b.WriteString(selectedItemStyle.Render(s.entry.Name))
+	b.WriteString("\n")
+	if s.entry.Description != "" {
+		b.WriteString(mutedStyle.Render(s.entry.Description))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(mutedStyle.Render("Select target agents:"))
+	b.WriteString("\n\n")
+	writeCheckboxes(&b, s, hint)
+
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render("Press enter to continue"))
+
+	return b.String()
+}
+
+// writeCheckboxes writes one line per system checkbox, the one under the
+// cursor highlighted.
+func writeCheckboxes(b *strings.Builder, s systemSelection, hint func(system.System) string) {
+	for i, ab := range s.boxes {
+		check := "[ ]"
+		if ab.checked {
+			check = "[x]"
+		}
+
+		prefix := "  "
+		if i == s.cursor {
+			prefix = "> "
+		}
+
+		line := prefix + check + " " + ab.system.DisplayName()
+		if i == s.cursor {
+			b.WriteString(selectedItemStyle.Render(line))
+		} else {
+			b.WriteString(normalItemStyle.Render(line))
+		}
+		if hint != nil {
+			b.WriteString(mutedStyle.Render(" (" + hint(ab.system) + ")"))
+		}
+		b.WriteString("\n")
+	}
+}
+
+// ---------------------------------------------------------------------------
+// Source-based kinds: agents, commands, rules, and any new kind
+// ---------------------------------------------------------------------------
+
+// sourceWizardFlow installs an entry from its source repository for the
+// selected systems.
+type sourceWizardFlow struct {
+	baseWizardFlow
+}
+
+func (f sourceWizardFlow) install(req assetInstallRequest) assetInstalledMsg {
+	entry := req.asset.Entry
+	if entry.Source == "" {
+		return req.done(fmt.Errorf("missing source"))
+	}
+	source, err := core.ParseSource(entry.Source)
+	if err != nil {
+		return req.done(fmt.Errorf("parsing source %q: %w", entry.Source, err))
+	}
+	if cfg, err := req.app.config.Load(); err == nil {
+		source.ApplyMirror(cfg.Settings.CloneURLOverrides, entry.CloneURL)
+	}
+
+	// The lock is checked for assets of the same name from elsewhere.
+	existingLock, _ := core.ReadLayeredLockFile(req.folder)
+
+	results, err := req.app.orch.InstallFromSource(source, f.kind, core.OrchestratorInstallOptions{
+		TargetDir:     req.folder,
+		TargetSystems: req.systems,
+		NameFilter:    entry.Name,
+		Commit:        entry.Commit,
+		Alias:         req.alias,
+		Lock:          existingLock,
+	})
+	if err != nil {
+		return req.done(err)
+	}
+
+	return req.reported(req.sourceReport(results), nil)
+}
+
+// ---------------------------------------------------------------------------
+// Skills
+// ---------------------------------------------------------------------------
+
+// skillWizardFlow installs a skill into .agents/skills, which the
+// universal systems read, and links it for the selected other systems.
+type skillWizardFlow struct {
+	baseWizardFlow
+}
+
+func (f skillWizardFlow) systems() (selectable, always []system.System) {
+	return system.NonUniversal(), system.Universal()
+}
+
+func (f skillWizardFlow) selectView(s systemSelection) string {
+	var b strings.Builder
+
+	b.WriteString(mutedStyle.Render("Select which agents should have access to this skill."))
+	b.WriteString("\n\n")
+
+	b.WriteString(mutedStyle.Render(".agents/skills/ (always installed)"))
+	b.WriteString("\n")
+	for _, a := range s.always {
+		b.WriteString(mutedStyle.Render("[x] " + a.DisplayName()))
+		b.WriteString("\n")
+	}
+	b.WriteString("\n")
+
+	b.WriteString(mutedStyle.Render("Agent-specific (optional)"))
+	b.WriteString("\n")
+	writeCheckboxes(&b, s, func(sys system.System) string {
+		if accessor, ok := sys.(interface{ SkillsDir() string }); ok {
+			return accessor.SkillsDir()
+		}
+		return ""
+	})
+
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render("Press enter to continue"))
+
+	return b.String()
+}
+
+func (f skillWizardFlow) install(req assetInstallRequest) assetInstalledMsg {
+	entry := req.asset.Entry
+	if entry.Source == "" {
+		return req.done(fmt.Errorf("missing source"))
+	}
+	source, err := core.ParseSource(entry.Source)
+	if err != nil {
+		return req.done(fmt.Errorf("parsing source %q: %w", entry.Source, err))
+	}
+
+	var ignorePatterns []string
+	var namespaceMode core.NamespaceMode
+	var limits core.SizeLimits
+	cfg, cfgErr := req.app.config.Load()
+	overrides, mirrors := req.app.cloneURLs(cfg, cfgErr)
+	if cfgErr == nil {
+		source.ApplyMirror(cfg.Settings.CloneURLOverrides, entry.CloneURL)
+		ignorePatterns = cfg.Settings.IgnorePatterns
+		namespaceMode = cfg.Settings.Namespaces()
+		if !req.acceptLarge {
+			limits = cfg.Settings.SkillSizeLimits()
+		}
+	}
+	// The lock is checked for skills of the same name from elsewhere, and
+	// for local changes to the skill being replaced.
+	existingLock, _ := core.ReadLayeredLockFile(req.folder)
+
+	results, err := req.app.orch.InstallFromSource(source, asset.KindSkill, core.OrchestratorInstallOptions{
+		TargetDir:       req.folder,
+		TargetSystems:   req.systems,
+		IncludeInternal: true,
+		Commit:          entry.Commit,
+		IgnorePatterns:  ignorePatterns,
+		Limits:          limits,
+		Alias:           req.alias,
+		Namespace:       req.asset.RegistryName,
+		NamespaceMode:   namespaceMode,
+		Lock:            existingLock,
+
+		CloneURLOverrides: overrides,
+		Mirrors:           mirrors,
+	})
+	if err != nil {
+		return req.done(err)
+	}
+
+	return req.reported(req.sourceReport(results), nil)
+}
+
+// ---------------------------------------------------------------------------
+// MCPs
+// ---------------------------------------------------------------------------
+
+// mcpWizardFlow writes an MCP's config into the selected systems' config
+// files, after a preview of the changes that offers to set missing env
+// vars.
+type mcpWizardFlow struct {
+	baseWizardFlow
+}
+
+func (f mcpWizardFlow) label() string { return "MCP" }
+
+func (f mcpWizardFlow) selectView(s systemSelection) string {
+	return checkboxSelectView(f.label(), s, func(sys system.System) string {
+		return resolveMCPConfigPathRel(sys, s.folder)
+	})
+}
+
+func (f mcpWizardFlow) validate(req assetInstallRequest) error {
+	meta, ok := req.asset.Entry.Meta.(asset.MCPMeta)
+	if !ok {
+		return fmt.Errorf("invalid MCP metadata")
+	}
+	if err := core.CheckMCPHost(meta); err != nil {
+		return err
+	}
+	existingLock, _ := core.ReadLayeredLockFile(req.folder)
+	return core.CheckInstallName(existingLock, asset.KindMCP, req.asset.Entry.Name)
+}
+
+func (f mcpWizardFlow) extraSteps() []string { return []string{"Preview"} }
+
+func (f mcpWizardFlow) buildSteps(req assetInstallRequest) []tea.Model {
+	meta, _ := req.asset.Entry.Meta.(asset.MCPMeta)
+	return []tea.Model{mcpPreviewStepModel{
+		mcp:          req.asset.Entry,
+		targetAgents: req.systems,
+		envStatus:    mcpEnvStatus(meta, req.folder),
+		diffs:        mcpConfigDiffs(req.asset.Entry, req.folder, req.systems),
+		activeFolder: req.folder,
+	}}
+}
+
+func (f mcpWizardFlow) install(req assetInstallRequest) assetInstalledMsg {
+	meta, ok := req.asset.Entry.Meta.(asset.MCPMeta)
+	if !ok {
+		return req.done(fmt.Errorf("invalid MCP metadata"))
+	}
+
+	mcpAsset := mcpAssetFor(req.asset.Entry, meta)
+	existingLock, _ := core.ReadLayeredLockFile(req.folder)
+	if err := core.CheckInstallName(existingLock, asset.KindMCP, mcpAsset.Name); err != nil {
+		return req.done(err)
+	}
+
+	installed := core.InstallReportAsset{Name: mcpAsset.Name, RequiredEnv: core.ExtractRequiredEnv(meta.Env)}
+	for _, sys := range req.systems {
+		if err := sys.Install(mcpAsset, req.folder, system.InstallOptions{}); err != nil {
+			return req.done(err)
+		}
+		installed.Systems = append(installed.Systems, sys.Name())
+		installed.Files = append(installed.Files, core.InstallReportFile{
+			Path:   resolveMCPConfigPathRel(sys, req.folder),
+			System: sys.DisplayName(),
+			Key:    core.MCPConfigKey(sys, mcpAsset.Name),
+		})
+	}
+
+	report := req.newReport()
+	report.Assets = []core.InstallReportAsset{installed}
+	for _, status := range mcpEnvStatus(meta, req.folder) {
+		if !status.isSet {
+			report.Warn("%s is not set; add it to .env.duckrow or ~/.duckrow/.env.duckrow", status.name)
+		}
+	}
+	handler, _ := asset.Get(asset.KindMCP)
+	req.lock(&report, handler.BuildLockEntry(mcpAsset, asset.InstallInfo{
+		Registry:  req.asset.RegistryName,
+		Platforms: req.asset.Entry.Platforms,
+		Tags:      req.asset.Entry.Tags,
+	}))
+	return req.reported(report, nil)
+}
+
+// mcpAssetFor returns the asset an MCP registry entry installs.
+func mcpAssetFor(entry asset.RegistryEntry, meta asset.MCPMeta) asset.Asset {
+	return asset.Asset{
+		Kind:        asset.KindMCP,
+		Name:        entry.Name,
+		Description: entry.Description,
+		Meta:        meta,
+	}
+}
+
+// mcpConfigDiffs dry-runs the MCP install for each system and returns the
+// diffs of their config files, in order, so the preview shows how
+// hand-maintained configs will change; "" where nothing changes.
+func mcpConfigDiffs(entry asset.RegistryEntry, folder string, systems []system.System) []string {
+	meta, _ := entry.Meta.(asset.MCPMeta)
+	a := mcpAssetFor(entry, meta)
+	diffs := make([]string, len(systems))
+	for i, sys := range systems {
+		name := resolveMCPConfigPathRel(sys, folder)
+		_ = sys.Install(a, folder, system.InstallOptions{
+			Preview: func(c system.ConfigChange) {
+				diffs[i] = string(c.Diff(name))
+			},
+		})
+	}
+	return diffs
+}
@@ -49,17 +49,16 @@ func (m bookmarksModel) setSize(width, height int) bookmarksModel {
 
 // activate is called when the bookmarks view opens. It receives the cwd
 // (original launch directory), the currently active folder path, the full list
-// of bookmarked folder statuses, and agent definitions for detecting active
-// agents per folder.
+// of bookmarked folder statuses, and the folders whose scan is still pending.
 //
 // If the cwd is not bookmarked, it is prepended to the list as a synthetic
 // entry so the user can always navigate back to it.
-func (m bookmarksModel) activate(cwd, activeFolder string, folders []core.FolderStatus) bookmarksModel {
+func (m bookmarksModel) activate(cwd, activeFolder string, folders []core.FolderStatus, scanning map[string]bool) bookmarksModel {
 	m.cwd = cwd
 	m.activeFolder = activeFolder
 	m.folders = folders
 
-	items := foldersToItems(folders, activeFolder)
+	items := foldersToItems(folders, activeFolder, scanning)
 
 	// If the cwd is not in the bookmarks list, prepend it.
 	cwdBookmarked := false
@@ -71,7 +70,7 @@ func (m bookmarksModel) activate(cwd, activeFolder string, folders []core.Folder
 	}
 	if !cwdBookmarked {
 		var installed int
-		if lf, err := core.ReadLockFile(cwd); err == nil && lf != nil {
+		if lf, err := core.ReadLayeredLockFile(cwd); err == nil && lf != nil {
 			installed = len(lf.Assets)
 		}
 		currentItem := folderItem{
@@ -100,6 +99,19 @@ func (m bookmarksModel) activate(cwd, activeFolder string, folders []core.Folder
 	return m
 }
 
+// setScanned marks a bookmarked folder's scan as arrived, in place, so the
+// cursor and any filter stay where they are while scans stream in.
+func (m bookmarksModel) setScanned(path string) bookmarksModel {
+	for i, item := range m.list.Items() {
+		if fi, ok := item.(folderItem); ok && fi.status.Folder.Path == path && fi.scanning {
+			fi.scanning = false
+			m.list.SetItem(i, fi)
+			break
+		}
+	}
+	return m
+}
+
 func (m bookmarksModel) update(msg tea.Msg, app *App) (bookmarksModel, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -11,7 +11,6 @@ import (
 	"github.com/charmbracelet/lipgloss"
 
 	"github.com/barysiuk/duckrow/internal/core"
-	"github.com/barysiuk/duckrow/internal/core/asset"
 	"github.com/barysiuk/duckrow/internal/core/system"
 )
 
@@ -52,6 +51,12 @@ type cloneErrorModel struct {
 	// When set, the overlay shows clone success + this error instead of dismissing.
 	postCloneErr error
 
+	// Override offer: the retry succeeded with an edited URL, and the overlay
+	// asks whether to remember it before dismissing. offerResult is the
+	// successful retry, handed back to the app once the user decides.
+	offer       *cloneOverrideOffer
+	offerResult cloneRetryResultMsg
+
 	// Retry context — what to do when the user retries.
 	origin cloneRetryOrigin
 
@@ -72,10 +77,7 @@ func newCloneErrorModel() cloneErrorModel {
 	ti.Placeholder = "Enter clone URL..."
 	ti.CharLimit = 512
 
-	s := spinner.New(
-		spinner.WithSpinner(spinner.Dot),
-		spinner.WithStyle(spinnerStyle),
-	)
+	s := newSpinner()
 
 	return cloneErrorModel{
 		textInput: ti,
@@ -101,6 +103,7 @@ func (m cloneErrorModel) activateForInstall(ce *core.CloneError, assetInfo core.
 	m.retrying = false
 	m.retryURL = ""
 	m.postCloneErr = nil
+	m.offer = nil
 	m.scrollOffset = 0
 	m.textInput.SetValue(ce.URL)
 	return m
@@ -117,6 +120,7 @@ func (m cloneErrorModel) activateForRegistryAdd(ce *core.CloneError, url string)
 	m.retrying = false
 	m.retryURL = ""
 	m.postCloneErr = nil
+	m.offer = nil
 	m.scrollOffset = 0
 	m.textInput.SetValue(ce.URL)
 	return m
@@ -126,6 +130,11 @@ func (m cloneErrorModel) isRetrying() bool {
 	return m.retrying
 }
 
+// isOffering reports whether the overlay is asking to save a clone override.
+func (m cloneErrorModel) isOffering() bool {
+	return m.offer != nil
+}
+
 func (m cloneErrorModel) update(msg tea.Msg, app *App) (cloneErrorModel, tea.Cmd) {
 	// Handle spinner ticks while retrying.
 	if m.retrying {
@@ -144,6 +153,9 @@ func (m cloneErrorModel) update(msg tea.Msg, app *App) (cloneErrorModel, tea.Cmd
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
+		if m.offer != nil {
+			return m.updateOffer(msg, app)
+		}
 		if m.editing {
 			switch {
 			case key.Matches(msg, keys.Back):
@@ -198,6 +210,32 @@ func (m cloneErrorModel) update(msg tea.Msg, app *App) (cloneErrorModel, tea.Cmd
 	return m, nil
 }
 
+// updateOffer handles the save-override prompt shown after a successful
+// retry with an edited URL.
+func (m cloneErrorModel) updateOffer(msg tea.KeyMsg, app *App) (cloneErrorModel, tea.Cmd) {
+	offer := m.offer
+	var saveKey, saveURL string
+	switch {
+	case key.Matches(msg, keys.SaveRepo):
+		saveKey, saveURL = offer.repoKey, offer.url
+	case key.Matches(msg, keys.SaveHost) && offer.hostKey != "":
+		saveKey, saveURL = offer.hostKey, offer.template
+	case key.Matches(msg, keys.SkipSave), key.Matches(msg, keys.Back):
+	default:
+		return m, nil
+	}
+
+	result := m.offerResult
+	m.offer = nil
+	return m, func() tea.Msg {
+		done := cloneOverrideDoneMsg{result: result, key: saveKey}
+		if saveKey != "" {
+			done.err = app.config.SaveCloneURLOverride(saveKey, saveURL)
+		}
+		return done
+	}
+}
+
 // startRetry transitions to the retrying state and launches the retry command.
 func (m cloneErrorModel) startRetry(app *App, url string) (cloneErrorModel, tea.Cmd) {
 	m.retrying = true
@@ -231,7 +269,13 @@ func (m cloneErrorModel) handleRetryResult(result cloneRetryResultMsg) cloneErro
 		return m
 	}
 
-	// Full success — caller will dismiss the overlay.
+	// Full success. If the URL was edited, ask whether to remember it;
+	// otherwise the caller dismisses the overlay.
+	if result.offer != nil {
+		m.offer = result.offer
+		m.offerResult = result
+		m.scrollOffset = 0
+	}
 	return m
 }
 
@@ -315,26 +359,22 @@ func (m cloneErrorModel) retryInstallCmd(app *App, url string) tea.Cmd {
 		}
 
 		// Write lock file entries for installed assets (TUI always locks).
+		req := assetInstallRequest{asset: assetInfo, folder: folder, systems: targetSystems, app: app}
+		report := req.newReport()
 		for _, r := range results {
-			entry := asset.LockedAsset{
-				Kind:   assetInfo.Kind,
-				Name:   r.Asset.Name,
-				Source: r.Asset.Source,
-				Commit: r.Commit,
-				Ref:    r.Ref,
-			}
-			_ = core.AddOrUpdateAsset(folder, entry)
+			report.Assets = append(report.Assets, core.NewInstallReportAsset(r, folder))
+			req.lock(&report, r.LockEntry(assetInfo.Entry.Platforms, assetInfo.Entry.Tags))
 		}
 
-		// Full success — save clone URL override if the URL differs from
-		// what ParseSource would normally produce for this repo.
-		saveCloneURLOverride(app, source, url)
-
+		// Full success — offer to save a clone URL override if the URL
+		// differs from what ParseSource would normally produce for this repo.
 		return cloneRetryResultMsg{
 			origin:    retryOriginInstall,
 			retryURL:  url,
 			assetName: assetInfo.Entry.Name,
 			folder:    folder,
+			report:    report,
+			offer:     newCloneOverrideOffer(source, url),
 		}
 	}
 }
@@ -379,47 +419,61 @@ func (m cloneErrorModel) retryRegistryAddCmd(app *App, url string) tea.Cmd {
 			}
 		}
 
-		// Save clone URL override — the repo URL from the registry entry
+		// Offer a clone URL override — the repo URL from the registry entry
 		// tells us the owner/repo. Parse it to get the RepoKey.
-		source, parseErr := core.ParseSource(url)
-		if parseErr == nil {
-			saveCloneURLOverride(app, source, url)
+		var offer *cloneOverrideOffer
+		if source, parseErr := core.ParseSource(url); parseErr == nil {
+			offer = newCloneOverrideOffer(source, url)
 		}
 
 		return cloneRetryResultMsg{
 			origin:       retryOriginRegistryAdd,
 			retryURL:     url,
 			registryName: manifest.Name,
+			offer:        offer,
 		}
 	}
 }
 
-// saveCloneURLOverride persists a clone URL override if the URL used for a
-// successful clone differs from what ParseSource would normally produce.
-// This is called after a successful retry with an edited URL so that future
-// installs from the same repo use the working URL automatically.
-func saveCloneURLOverride(app *App, source *core.ParsedSource, usedURL string) {
+// cloneOverrideOffer is an edited clone URL that worked on retry and can be
+// remembered in Settings.CloneURLOverrides, for this repo or for every repo
+// on the same host.
+type cloneOverrideOffer struct {
+	url      string // the URL that worked
+	repoKey  string // "owner/repo"
+	hostKey  string // "host/*", or "" if no host-wide template applies
+	template string // host-wide URL template for hostKey
+}
+
+// newCloneOverrideOffer returns an offer to save usedURL as a clone URL
+// override, or nil if it is what ParseSource would normally produce for the
+// repo (no override needed).
+func newCloneOverrideOffer(source *core.ParsedSource, usedURL string) *cloneOverrideOffer {
 	repoKey := source.RepoKey()
 	if repoKey == "" {
-		return
+		return nil
 	}
 
-	// Only save if the URL is actually different from the default.
 	// Re-parse the original source shorthand to see what the default would be.
 	defaultURL := source.CloneURL
-	if source.Owner != "" && source.Repo != "" {
-		defaultSource, err := core.ParseSource(source.Owner + "/" + source.Repo)
-		if err == nil {
-			defaultURL = defaultSource.CloneURL
+	host := source.Host
+	defaultSource, err := core.ParseSource(source.Owner + "/" + source.Repo)
+	if err == nil {
+		defaultURL = defaultSource.CloneURL
+		if host == "" {
+			host = defaultSource.Host
 		}
 	}
-
 	if usedURL == defaultURL {
-		return // No override needed — using the default URL.
+		return nil
 	}
 
-	// Best-effort save — don't fail the operation if this fails.
-	_ = app.config.SaveCloneURLOverride(repoKey, usedURL)
+	offer := &cloneOverrideOffer{url: usedURL, repoKey: repoKey}
+	if tmpl, ok := core.CloneURLTemplate(usedURL, source.Owner, source.Repo); ok && host != "" {
+		offer.hostKey = core.HostOverrideKey(host)
+		offer.template = tmpl
+	}
+	return offer
 }
 
 func (m cloneErrorModel) view() string {
@@ -439,6 +493,19 @@ func (m cloneErrorModel) view() string {
 		return b.String()
 	}
 
+	// --- Override offer: clone succeeded with an edited URL ---
+	if m.offer != nil {
+		b.WriteString("  ")
+		b.WriteString(installedStyle.Render("Clone succeeded"))
+		b.WriteString("\n")
+		b.WriteString(mutedStyle.Render("    " + m.offer.url))
+		b.WriteString("\n\n")
+		b.WriteString(normalItemStyle.Render("  Use this URL for future clones?"))
+		b.WriteString("\n\n")
+		b.WriteString(renderCloneOverrideActions(m.offer))
+		return b.String()
+	}
+
 	// --- Post-clone error: clone succeeded, but something after failed ---
 	if m.postCloneErr != nil {
 		// Show clone success.
@@ -568,12 +635,25 @@ type cloneRetryResultMsg struct {
 	// The URL that was used for the retry.
 	retryURL string
 
-	// For install retries: context for reload.
+	// For install retries: context for reload, and on success what was
+	// installed.
 	assetName string
 	folder    string
+	report    core.InstallReport
 
 	// For registry add retries: the registry name on success.
 	registryName string
+
+	// On success with an edited URL: the override that can be saved.
+	offer *cloneOverrideOffer
+}
+
+// cloneOverrideDoneMsg is sent once the user has answered the save-override
+// prompt; key is the override key that was saved, or "" if skipped.
+type cloneOverrideDoneMsg struct {
+	result cloneRetryResultMsg
+	key    string
+	err    error
 }
 
 // registryAddDoneMsg is sent when a registry add completes (from settings, not retry).
@@ -582,6 +662,9 @@ type registryAddDoneMsg struct {
 	name     string
 	warnings []string
 	err      error
+
+	// recommended are the assets the registry recommends installing.
+	recommended []core.RegistryAssetInfo
 }
 
 // hintBulletStyle styles the bullet point for hint items.
@@ -594,6 +677,30 @@ var hintKeyStyle = lipgloss.NewStyle().
 	Bold(true)
 
 // renderCloneErrorActions renders the inline call-to-action block.
+func renderCloneOverrideActions(offer *cloneOverrideOffer) string {
+	var b strings.Builder
+	b.WriteString("  ")
+	b.WriteString(hintKeyStyle.Render("[y]"))
+	b.WriteString(" ")
+	b.WriteString(normalItemStyle.Render("Save for " + offer.repoKey))
+	b.WriteString("\n")
+	if offer.hostKey != "" {
+		b.WriteString("  ")
+		b.WriteString(hintKeyStyle.Render("[h]"))
+		b.WriteString(" ")
+		b.WriteString(normalItemStyle.Render("Save for all repos on " + strings.TrimSuffix(offer.hostKey, "/*")))
+		b.WriteString("\n")
+		b.WriteString(mutedStyle.Render("      " + offer.template))
+		b.WriteString("\n")
+	}
+	b.WriteString("  ")
+	b.WriteString(hintKeyStyle.Render("[n]"))
+	b.WriteString(" ")
+	b.WriteString(normalItemStyle.Render("Don't save"))
+	b.WriteString("\n")
+	return b.String()
+}
+
 func renderCloneErrorActions() string {
 	var b strings.Builder
 	b.WriteString("  ")
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/barysiuk/duckrow/internal/core"
+)
+
+func TestNewCloneOverrideOffer(t *testing.T) {
+	source, err := core.ParseSource("acme/skills")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if offer := newCloneOverrideOffer(source, source.CloneURL); offer != nil {
+		t.Errorf("offer for default URL = %+v, want nil", offer)
+	}
+
+	offer := newCloneOverrideOffer(source, "git@github.com:acme/skills.git")
+	if offer == nil {
+		t.Fatal("expected an offer for an edited URL")
+	}
+	if offer.repoKey != "acme/skills" {
+		t.Errorf("repoKey = %q, want acme/skills", offer.repoKey)
+	}
+	if offer.hostKey != "github.com/*" || offer.template != "git@github.com:{owner}/{repo}.git" {
+		t.Errorf("host offer = %q -> %q", offer.hostKey, offer.template)
+	}
+
+	// A URL that doesn't contain owner/repo can only be saved per repo.
+	offer = newCloneOverrideOffer(source, "https://mirror.example/skills-mirror.git")
+	if offer == nil || offer.hostKey != "" {
+		t.Errorf("offer = %+v, want repo-only offer", offer)
+	}
+}
+
+func TestCloneErrorOfferSavesOverride(t *testing.T) {
+	cm := core.NewConfigManagerWithDir(filepath.Join(t.TempDir(), ".duckrow"))
+	app := &App{config: cm}
+
+	source, _ := core.ParseSource("acme/skills")
+	result := cloneRetryResultMsg{
+		origin:    retryOriginInstall,
+		assetName: "go-review",
+		offer:     newCloneOverrideOffer(source, "git@github.com:acme/skills.git"),
+	}
+
+	m := newCloneErrorModel()
+	m.cloneErr = &core.CloneError{URL: source.CloneURL}
+	m = m.handleRetryResult(result)
+	if !m.isOffering() {
+		t.Fatal("expected the overlay to offer saving the URL")
+	}
+	if !strings.Contains(m.view(), "Save for all repos on github.com") {
+		t.Errorf("view missing host option:\n%s", m.view())
+	}
+
+	m, cmd := m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("h")}, app)
+	if m.isOffering() {
+		t.Error("offer should be cleared after answering")
+	}
+	done, ok := cmd().(cloneOverrideDoneMsg)
+	if !ok || done.err != nil || done.key != "github.com/*" {
+		t.Fatalf("done = %+v, want saved host override", done)
+	}
+
+	cfg, err := cm.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := cfg.Settings.CloneURLOverrides["github.com/*"]; got != "git@github.com:{owner}/{repo}.git" {
+		t.Errorf("saved override = %q", got)
+	}
+}
@@ -19,11 +19,17 @@ import (
 //
 // On confirm the stored onConfirm command is executed and a confirmResultMsg
 // is sent. On cancel the dialog is dismissed silently.
+//
+// The dialog can also show a notice, with a single OK button, for something
+// the user should read before going on:
+//
+//	app.confirm = app.confirm.showNotice("Installed Skill foo\n\nRun make seed first.")
 type confirmModel struct {
 	active    bool
 	message   string
 	onConfirm tea.Cmd // Command to execute on confirmation.
 	focusYes  bool    // true = Yes focused, false = No focused.
+	notice    bool    // true = a notice with only an OK button.
 
 	// Layout dimensions — set by the app so the dialog can center itself.
 	width  int
@@ -49,6 +55,13 @@ func (m confirmModel) show(message string, onConfirm tea.Cmd) confirmModel {
 	return m
 }
 
+// showNotice activates the dialog as a notice, dismissed with enter or esc.
+func (m confirmModel) showNotice(message string) confirmModel {
+	m = m.show(message, nil)
+	m.notice = true
+	return m
+}
+
 // setSize updates the available area for centering the dialog.
 func (m confirmModel) setSize(width, height int) confirmModel {
 	m.width = width
@@ -62,6 +75,7 @@ func (m confirmModel) dismiss() confirmModel {
 	m.message = ""
 	m.onConfirm = nil
 	m.focusYes = false
+	m.notice = false
 	return m
 }
 
@@ -94,6 +108,13 @@ func (m confirmModel) update(msg tea.Msg) (confirmModel, tea.Cmd, bool) {
 		return m, nil, false
 	}
 
+	if m.notice {
+		if key.Matches(keyMsg, keys.Enter) || key.Matches(keyMsg, keys.Back) {
+			m = m.dismiss()
+		}
+		return m, nil, true
+	}
+
 	switch {
 	// Shortcut accelerators.
 	case key.Matches(keyMsg, confirmYesKey):
@@ -142,16 +163,18 @@ func (m confirmModel) view() string {
 		Align(lipgloss.Center).
 		Render(m.message)
 
-	var yesBtn, noBtn string
-	if m.focusYes {
-		yesBtn = dialogActiveButtonStyle.Render("Yes")
-		noBtn = dialogButtonStyle.Render("No")
-	} else {
-		yesBtn = dialogButtonStyle.Render("Yes")
-		noBtn = dialogActiveButtonStyle.Render("No")
+	var buttons string
+	switch {
+	case m.notice:
+		buttons = dialogActiveButtonStyle.Render("OK")
+	case m.focusYes:
+		buttons = lipgloss.JoinHorizontal(lipgloss.Top,
+			dialogActiveButtonStyle.Render("Yes"), "  ", dialogButtonStyle.Render("No"))
+	default:
+		buttons = lipgloss.JoinHorizontal(lipgloss.Top,
+			dialogButtonStyle.Render("Yes"), "  ", dialogActiveButtonStyle.Render("No"))
 	}
 
-	buttons := lipgloss.JoinHorizontal(lipgloss.Top, yesBtn, "  ", noBtn)
 	ui := lipgloss.JoinVertical(lipgloss.Center, question, "", buttons)
 	dialog := dialogBoxStyle.Render(ui)
 
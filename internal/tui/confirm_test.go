@@ -473,3 +473,33 @@ func TestConfirmUpdate_YesThenInactive(t *testing.T) {
 		t.Error("keys should not be consumed after confirmation dismisses dialog")
 	}
 }
+
+func TestConfirmNotice(t *testing.T) {
+	m := newConfirmModel()
+	m = m.showNotice("Installed Skill db-helper\n\nRun make seed first.")
+	m = m.setSize(80, 24)
+
+	v := m.view()
+	if !strings.Contains(v, "Run make seed first.") || !strings.Contains(v, "OK") {
+		t.Errorf("view() = %q, want the message and an OK button", v)
+	}
+	if strings.Contains(v, "Yes") {
+		t.Errorf("view() = %q, a notice has no Yes button", v)
+	}
+
+	// y and other keys don't dismiss it; enter does, without a result.
+	m, _, consumed := m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{'y'}})
+	if !consumed || !m.active {
+		t.Fatal("y should be consumed without dismissing the notice")
+	}
+	m, cmd, consumed := m.update(tea.KeyMsg{Type: tea.KeyEnter})
+	if !consumed || m.active {
+		t.Error("enter should dismiss the notice")
+	}
+	if cmd != nil {
+		t.Error("dismissing a notice should not send a result")
+	}
+	if m.notice {
+		t.Error("notice should be reset after dismiss")
+	}
+}
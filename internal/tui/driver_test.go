@@ -0,0 +1,300 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/barysiuk/duckrow/internal/core"
+)
+
+// driverTimeout bounds how long waitFor lets commands run before failing.
+const driverTimeout = 30 * time.Second
+
+// driverOptions describe the world a driver starts in.
+type driverOptions struct {
+	width, height int
+
+	// manifest is the duckrow.json of a local registry, if not empty.
+	manifest string
+	// extraManifests are the duckrow.json files of further local
+	// registries, added after manifest's.
+	extraManifests []string
+
+	// skills are installed into the project before the app starts, by
+	// name with their description, as just a SKILL.md each.
+	skills map[string]string
+
+	// overrides are saved as settings.cloneURLOverrides, e.g. to send
+	// installs to a gittest server.
+	overrides map[string]string
+
+	// namespaces is saved as settings.skillNamespaces.
+	namespaces string
+}
+
+// driver runs an App headlessly, the way the bubbletea runtime would minus
+// the terminal. Messages go through Update; the commands Update returns run
+// in their own goroutines and their messages are queued, like the runtime
+// does, and applied by waitFor. Batches and sequences are expanded.
+//
+// Init is not run, so nothing checks for a duckrow upgrade or hydrates
+// registries unless a test asks for it; the driver loads the app's data
+// itself.
+type driver struct {
+	t       *testing.T
+	app     App
+	config  *core.ConfigManager
+	project string
+	golden  string // testdata/golden, resolved before the driver changes directory
+	opts    driverOptions
+
+	msgs chan tea.Msg
+	done chan struct{}
+	quit bool
+}
+
+// newDriver returns a driver for an App launched in a project under a
+// fresh HOME, so paths render the same on every machine, with the app's
+// data loaded.
+func newDriver(t *testing.T, opts driverOptions) *driver {
+	t.Helper()
+	// Resolved so the working directory matches it where temp dirs sit
+	// behind a symlink, as on macOS.
+	home, err := filepath.EvalSymlinks(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("HOME", home)
+	t.Setenv("XDG_CONFIG_HOME", "")
+
+	project := filepath.Join(home, "project")
+	if err := os.MkdirAll(filepath.Join(project, ".cursor"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for name, description := range opts.skills {
+		dir := filepath.Join(project, ".agents", "skills", name)
+		if err := os.MkdirAll(dir, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		md := "---\nname: " + name + "\ndescription: " + description + "\n---\n# " + name + "\n"
+		if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(md), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	cm := core.NewConfigManagerWithDir(filepath.Join(home, ".duckrow"))
+	cfg := &core.Config{Folders: []core.TrackedFolder{{Path: project}}}
+	cfg.Settings.CloneURLOverrides = opts.overrides
+	cfg.Settings.SkillNamespaces = opts.namespaces
+	manifests := opts.extraManifests
+	if opts.manifest != "" {
+		manifests = append([]string{opts.manifest}, manifests...)
+	}
+	for i, data := range manifests {
+		registry := filepath.Join(home, "registry")
+		if i > 0 {
+			registry += strconv.Itoa(i + 1)
+		}
+		if err := os.MkdirAll(registry, 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(registry, "duckrow.json"), []byte(data), 0o644); err != nil {
+			t.Fatal(err)
+		}
+		repo, manifest, err := core.NewRegistryManager(cm.RegistriesDir()).AddLocal(registry)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.Registries = append(cfg.Registries, core.Registry{Name: manifest.Name, Repo: repo, Local: true})
+	}
+	if err := cm.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	golden, err := filepath.Abs(filepath.Join("testdata", "golden"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Chdir(project)
+
+	return launchDriver(t, cm, project, golden, opts)
+}
+
+// launchDriver starts an App on the given config in the working directory
+// and loads its data.
+func launchDriver(t *testing.T, cm *core.ConfigManager, project, golden string, opts driverOptions) *driver {
+	t.Helper()
+	d := &driver{
+		t:       t,
+		app:     NewApp(cm, "dev"),
+		config:  cm,
+		project: project,
+		golden:  golden,
+		opts:    opts,
+		msgs:    make(chan tea.Msg, 64),
+		done:    make(chan struct{}),
+	}
+	t.Cleanup(func() { close(d.done) })
+	d.send(tea.WindowSizeMsg{Width: opts.width, Height: opts.height})
+	d.send(d.app.loadDataCmd())
+	return d
+}
+
+// relaunch returns a driver for a new App started where this one was, as
+// if duckrow was quit and run again.
+func (d *driver) relaunch() *driver {
+	d.t.Helper()
+	return launchDriver(d.t, d.config, d.project, d.golden, d.opts)
+}
+
+// send passes msg through the app's Update and starts the command it
+// returns.
+func (d *driver) send(msg tea.Msg) {
+	d.t.Helper()
+	if _, ok := msg.(tea.QuitMsg); ok {
+		d.quit = true
+		return
+	}
+	model, cmd := d.app.Update(msg)
+	app, ok := model.(App)
+	if !ok {
+		d.t.Fatalf("Update(%T) returned %T, want App", msg, model)
+	}
+	d.app = app
+	d.run(cmd)
+}
+
+// run executes cmd in the background and queues the message it returns.
+func (d *driver) run(cmd tea.Cmd) {
+	if cmd == nil {
+		return
+	}
+	go func() { d.deliver(cmd()) }()
+}
+
+// deliver queues msg, running the commands of a batch concurrently and
+// those of a sequence one after another, as the runtime does.
+func (d *driver) deliver(msg tea.Msg) {
+	if msg == nil {
+		return
+	}
+	if batch, ok := msg.(tea.BatchMsg); ok {
+		for _, cmd := range batch {
+			d.run(cmd)
+		}
+		return
+	}
+	if seq := sequenceCmds(msg); seq != nil {
+		for _, cmd := range seq {
+			if cmd != nil {
+				d.deliver(cmd())
+			}
+		}
+		return
+	}
+	select {
+	case d.msgs <- msg:
+	case <-d.done:
+	}
+}
+
+// sequenceCmds returns the commands of a tea.Sequence message, whose type
+// bubbletea doesn't export.
+func sequenceCmds(msg tea.Msg) []tea.Cmd {
+	v := reflect.ValueOf(msg)
+	if v.Kind() != reflect.Slice || v.Type().Elem() != reflect.TypeOf(tea.Cmd(nil)) {
+		return nil
+	}
+	cmds := make([]tea.Cmd, v.Len())
+	for i := range cmds {
+		cmds[i] = v.Index(i).Interface().(tea.Cmd)
+	}
+	return cmds
+}
+
+// keyTypes maps key names to the key types that aren't runes.
+var keyTypes = map[string]tea.KeyType{
+	"enter":     tea.KeyEnter,
+	"esc":       tea.KeyEsc,
+	"up":        tea.KeyUp,
+	"down":      tea.KeyDown,
+	"left":      tea.KeyLeft,
+	"right":     tea.KeyRight,
+	"tab":       tea.KeyTab,
+	"shift+tab": tea.KeyShiftTab,
+	"space":     tea.KeySpace,
+	"backspace": tea.KeyBackspace,
+	"ctrl+c":    tea.KeyCtrlC,
+}
+
+// press sends key presses in order, e.g. press("i", "enter"). Names in
+// keyTypes are special keys; anything else is typed as runes.
+func (d *driver) press(keyNames ...string) {
+	d.t.Helper()
+	for _, k := range keyNames {
+		if kt, ok := keyTypes[k]; ok {
+			d.send(tea.KeyMsg{Type: kt})
+			continue
+		}
+		d.send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(k)})
+	}
+}
+
+// waitFor applies queued messages until cond holds, failing the test with
+// the current view if it doesn't within driverTimeout.
+func (d *driver) waitFor(what string, cond func(App) bool) {
+	d.t.Helper()
+	deadline := time.After(driverTimeout)
+	for !cond(d.app) {
+		select {
+		case msg := <-d.msgs:
+			d.send(msg)
+		case <-deadline:
+			d.t.Fatalf("timed out waiting for %s; view:\n%s", what, d.view())
+		}
+	}
+}
+
+// waitForView waits until the view contains text.
+func (d *driver) waitForView(text string) {
+	d.t.Helper()
+	d.waitFor("view to show "+text, func(a App) bool {
+		return strings.Contains(ansi.Strip(a.View()), text)
+	})
+}
+
+// view returns the app's view without styling or trailing padding.
+func (d *driver) view() string {
+	lines := strings.Split(ansi.Strip(d.app.View()), "\n")
+	for i, l := range lines {
+		lines[i] = strings.TrimRight(l, " ")
+	}
+	return strings.Join(lines, "\n") + "\n"
+}
+
+// registryAsset returns the loaded registry entry for name.
+func (d *driver) registryAsset(name string) core.RegistryAssetInfo {
+	d.t.Helper()
+	return d.registryAssetFrom("", name)
+}
+
+// registryAssetFrom returns the loaded entry for name in the named
+// registry, or in any registry if it is "".
+func (d *driver) registryAssetFrom(registry, name string) core.RegistryAssetInfo {
+	d.t.Helper()
+	for _, a := range d.app.registryAssets {
+		if a.Entry.Name == name && (registry == "" || a.RegistryName == registry) {
+			return a
+		}
+	}
+	d.t.Fatalf("registry asset %q not loaded from %q", name, registry)
+	return core.RegistryAssetInfo{}
+}
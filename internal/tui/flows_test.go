@@ -0,0 +1,405 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/muesli/termenv"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+	"github.com/barysiuk/duckrow/internal/gittest"
+)
+
+// lockedCommit returns the commit the project's lock file records for a
+// skill, or "" if it isn't locked.
+func lockedCommit(t *testing.T, dir, name string) string {
+	t.Helper()
+	lf, err := core.ReadLayeredLockFile(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if lf == nil {
+		return ""
+	}
+	if a := core.FindLockedAsset(lf, asset.KindSkill, name); a != nil {
+		return a.Commit
+	}
+	return ""
+}
+
+func hasSkill(a App, name string) bool {
+	if a.activeFolderStatus == nil {
+		return false
+	}
+	for _, s := range a.activeFolderStatus.Assets[asset.KindSkill] {
+		if s.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// TestFlow_InstallUpdateRemove installs a skill from a registry through the
+// install picker and wizard, updates it after its source moves on, and
+// removes it, all by key presses against a local git server.
+func TestFlow_InstallUpdateRemove(t *testing.T) {
+	srv := gittest.NewServer(t)
+	skills := srv.NewRepo("acme", "skills")
+	skills.AddSkill("skills/lint", "lint", "Lints things")
+	first := skills.Commit("add lint")
+
+	d := newDriver(t, driverOptions{
+		width:     120,
+		height:    40,
+		manifest:  `{"name": "acme", "skills": [{"name": "lint", "description": "Lints things", "source": "` + skills.Source("skills", "lint") + `"}]}`,
+		overrides: srv.CloneURLOverrides(),
+	})
+
+	// Install: the picker opens the wizard on the systems step, and enter
+	// moves on to installing.
+	d.press("i")
+	if d.app.activeView != viewInstallPicker {
+		t.Fatalf("i opened view %d, want the install picker", d.app.activeView)
+	}
+	d.press("enter")
+	d.waitFor("the install wizard", func(a App) bool { return a.activeView == viewAssetWizard })
+	if d.app.assetWizard.wizard.activeIdx != 0 {
+		t.Fatalf("wizard opened on step %d, want 0", d.app.assetWizard.wizard.activeIdx)
+	}
+	d.press("enter")
+	d.waitFor("the installing step", func(a App) bool {
+		return a.activeView != viewAssetWizard || a.assetWizard.wizard.activeIdx == 1
+	})
+	// The summary lists what was written, can be copied, and enter closes
+	// it.
+	d.waitFor("the summary step", func(a App) bool {
+		return a.assetWizard.currentPhase() == assetPhaseSummary
+	})
+	d.waitForView("Installed Skill lint at " + core.TruncateCommit(first))
+	d.waitForView(".agents/skills/lint (read by")
+	d.waitForView("Updated duckrow.lock.json")
+	var copied string
+	copyToClipboard = func(s string) { copied = s }
+	t.Cleanup(func() { copyToClipboard = termenv.Copy })
+	d.press("c")
+	d.waitForView("Copied install report")
+	if !strings.Contains(copied, "Updated duckrow.lock.json") {
+		t.Errorf("copied report = %q", copied)
+	}
+	d.press("enter")
+	d.waitFor("lint to be installed", func(a App) bool {
+		return a.activeView == viewFolder && hasSkill(a, "lint")
+	})
+	if got := lockedCommit(t, d.project, "lint"); got != first {
+		t.Fatalf("locked commit = %q, want %q", got, first)
+	}
+
+	// Update: once the registry refresh sees the new commit, u asks to
+	// update and y confirms. A local edit stops it.
+	skills.WriteFile("skills/lint/rules.md", "v2\n")
+	second := skills.Commit("update lint")
+	skillMD := filepath.Join(d.project, ".agents", "skills", "lint", "SKILL.md")
+	original, err := os.ReadFile(skillMD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := string(original) + "Edited locally.\n"
+	if err := os.WriteFile(skillMD, []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	d.press("r")
+	d.waitFor("the update to be found", func(a App) bool { return a.updateInfo[asset.KindSkill]["lint"].HasUpdate })
+	d.press("u")
+	if !d.app.confirm.active {
+		t.Fatal("u did not ask to confirm the update")
+	}
+	d.press("y")
+	d.waitForView("has local modifications")
+	if got := lockedCommit(t, d.project, "lint"); got != first {
+		t.Fatalf("locked commit after a refused update = %q, want %q", got, first)
+	}
+	if data, _ := os.ReadFile(skillMD); string(data) != edited {
+		t.Fatalf("refused update overwrote the local edit:\n%s", data)
+	}
+
+	if err := os.WriteFile(skillMD, original, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	d.press("u", "y")
+	d.waitFor("lint to be updated", func(a App) bool { return !a.updateInfo[asset.KindSkill]["lint"].HasUpdate })
+	if got := lockedCommit(t, d.project, "lint"); got != second {
+		t.Fatalf("locked commit after update = %q, want %q", got, second)
+	}
+
+	// Remove: d asks first, and n keeps the skill.
+	d.press("d")
+	if !d.app.confirm.active {
+		t.Fatal("d did not ask to confirm the removal")
+	}
+	d.press("n")
+	if d.app.confirm.active || !hasSkill(d.app, "lint") {
+		t.Fatal("n did not cancel the removal")
+	}
+	d.press("d", "y")
+	d.waitFor("lint to be removed", func(a App) bool { return !hasSkill(a, "lint") })
+	if got := lockedCommit(t, d.project, "lint"); got != "" {
+		t.Errorf("lint still locked at %q after removal", got)
+	}
+}
+
+// TestFlow_UpdateAgent installs an agent for one system, then updates it
+// from the Agents tab after its source moves on: the new version is
+// rendered again for that system only.
+func TestFlow_UpdateAgent(t *testing.T) {
+	srv := gittest.NewServer(t)
+	agents := srv.NewRepo("acme", "agents")
+	agents.AddAgent("agents/reviewer.md", "reviewer", "Reviews code")
+	agents.Commit("add reviewer")
+
+	d := newDriver(t, driverOptions{
+		width:     120,
+		height:    40,
+		manifest:  `{"name": "acme", "agents": [{"name": "reviewer", "description": "Reviews code", "source": "` + agents.Source("agents", "reviewer.md") + `"}]}`,
+		overrides: srv.CloneURLOverrides(),
+	})
+
+	d.press("tab", "tab")
+	d.waitFor("the Agents tab", func(a App) bool { return a.folder.activeKind == asset.KindAgent })
+	d.press("i", "enter")
+	d.waitFor("the install wizard", func(a App) bool { return a.activeView == viewAssetWizard })
+	d.app.assetWizard = d.app.assetWizard.checkSystems([]string{"claude-code"})
+	d.press("enter")
+	d.waitForView(".claude/agents/reviewer.md (Claude Code)")
+	d.press("esc")
+	d.waitFor("reviewer to be installed", func(a App) bool {
+		return a.activeView == viewFolder && len(a.activeFolderStatus.Assets[asset.KindAgent]) == 1
+	})
+
+	agents.AddAgent("agents/reviewer.md", "reviewer", "Reviews code thoroughly")
+	agents.Commit("update reviewer")
+	d.press("r")
+	d.waitFor("the update to be found", func(a App) bool { return a.updateInfo[asset.KindAgent]["reviewer"].HasUpdate })
+	d.press("u")
+	if !d.app.confirm.active {
+		t.Fatal("u did not ask to confirm the update")
+	}
+	d.press("y")
+	d.waitFor("reviewer to be updated", func(a App) bool { return !a.updateInfo[asset.KindAgent]["reviewer"].HasUpdate })
+
+	data, err := os.ReadFile(filepath.Join(d.project, ".claude", "agents", "reviewer.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(data), "Reviews code thoroughly") {
+		t.Errorf("claude-code agent not updated:\n%s", data)
+	}
+	if _, err := os.Stat(filepath.Join(d.project, ".opencode", "agents", "reviewer.md")); !os.IsNotExist(err) {
+		t.Errorf("update wrote the agent for a system it wasn't installed for (stat error %v)", err)
+	}
+}
+
+// skillManifest returns the duckrow.json of a registry with one skill.
+func skillManifest(registry, name, description, source string) string {
+	return `{"name": "` + registry + `", "skills": [{"name": "` + name + `", "description": "` + description + `", "source": "` + source + `"}]}`
+}
+
+// installFromWizard opens the install wizard on a registry's skill and
+// installs it for the preselected systems, waiting for the step it stops
+// on.
+func installFromWizard(d *driver, registry, name string) {
+	d.t.Helper()
+	d.send(openAssetWizardMsg{asset: d.registryAssetFrom(registry, name), allSystems: system.All(), activeFolder: d.project})
+	d.press("enter")
+	d.waitFor("the install to stop", func(a App) bool {
+		phase := a.assetWizard.currentPhase()
+		return a.activeView != viewAssetWizard || phase == assetPhaseSummary || a.assetWizard.isResolving()
+	})
+}
+
+// TestFlow_InstallConflict installs a skill from one registry, then the
+// skill of the same name from another: the wizard asks for another name
+// to install it under instead of replacing the first.
+func TestFlow_InstallConflict(t *testing.T) {
+	srv := gittest.NewServer(t)
+	acme := srv.NewRepo("acme", "skills")
+	acme.AddSkill("skills/lint", "lint", "Lints things")
+	acme.Commit("add lint")
+	other := srv.NewRepo("other", "skills")
+	other.AddSkill("skills/lint", "lint", "Lints other things")
+	other.Commit("add lint")
+
+	d := newDriver(t, driverOptions{
+		width:          120,
+		height:         40,
+		manifest:       skillManifest("acme", "lint", "Lints things", acme.Source("skills", "lint")),
+		extraManifests: []string{skillManifest("other", "lint", "Lints other things", other.Source("skills", "lint"))},
+		overrides:      srv.CloneURLOverrides(),
+	})
+
+	installFromWizard(d, "acme", "lint")
+	d.press("enter")
+	d.waitFor("lint to be installed", func(a App) bool { return hasSkill(a, "lint") })
+
+	installFromWizard(d, "other", "lint")
+	if !d.app.assetWizard.isResolving() {
+		t.Fatalf("installing other's lint did not stop on the conflict; view:\n%s", d.view())
+	}
+	d.waitForView("is already installed from")
+	skillMD := filepath.Join(d.project, ".agents", "skills", "lint", "SKILL.md")
+	if data, _ := os.ReadFile(skillMD); !strings.Contains(string(data), "Lints things") {
+		t.Fatalf("the conflicting install replaced acme's lint:\n%s", data)
+	}
+
+	// The namespaced name is offered; an invalid one is refused.
+	d.press("!", "enter")
+	d.waitForView("invalid")
+	if !d.app.assetWizard.isResolving() {
+		t.Fatal("an invalid name was accepted")
+	}
+	d.press("backspace", "enter")
+	d.waitFor("the summary step", func(a App) bool { return a.assetWizard.currentPhase() == assetPhaseSummary })
+	lf, err := core.ReadLayeredLockFile(d.project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for name, want := range map[string]string{"lint": acme.Source("skills", "lint"), "other--lint": other.Source("skills", "lint")} {
+		if a := core.FindLockedAsset(lf, asset.KindSkill, name); a == nil || a.Source != want {
+			t.Errorf("locked %s = %+v, want source %s", name, a, want)
+		}
+	}
+}
+
+// TestFlow_InstallNamespaceOnConflict installs same-named skills from two
+// registries with skillNamespaces set to on-conflict: the second is
+// installed under its namespaced name without asking.
+func TestFlow_InstallNamespaceOnConflict(t *testing.T) {
+	srv := gittest.NewServer(t)
+	acme := srv.NewRepo("acme", "skills")
+	acme.AddSkill("skills/lint", "lint", "Lints things")
+	acme.Commit("add lint")
+	other := srv.NewRepo("other", "skills")
+	other.AddSkill("skills/lint", "lint", "Lints other things")
+	other.Commit("add lint")
+
+	d := newDriver(t, driverOptions{
+		width:          120,
+		height:         40,
+		manifest:       skillManifest("acme", "lint", "Lints things", acme.Source("skills", "lint")),
+		extraManifests: []string{skillManifest("other", "lint", "Lints other things", other.Source("skills", "lint"))},
+		overrides:      srv.CloneURLOverrides(),
+		namespaces:     "on-conflict",
+	})
+
+	installFromWizard(d, "acme", "lint")
+	d.press("enter")
+	d.waitFor("lint to be installed", func(a App) bool { return hasSkill(a, "lint") })
+
+	installFromWizard(d, "other", "lint")
+	if d.app.assetWizard.currentPhase() != assetPhaseSummary {
+		t.Fatalf("installing other's lint stopped before the summary; view:\n%s", d.view())
+	}
+	d.waitForView("Installed Skill other--lint")
+	skillMD := filepath.Join(d.project, ".agents", "skills", "lint", "SKILL.md")
+	if data, _ := os.ReadFile(skillMD); !strings.Contains(string(data), "Lints things") {
+		t.Fatalf("the namespaced install replaced acme's lint:\n%s", data)
+	}
+	lf, err := core.ReadLayeredLockFile(d.project)
+	if err != nil {
+		t.Fatal(err)
+	}
+	a := core.FindLockedAsset(lf, asset.KindSkill, "other--lint")
+	if a == nil || a.Source != other.Source("skills", "lint") || a.Data["namespace"] != "other" {
+		t.Errorf("locked other--lint = %+v, want it namespaced under other", a)
+	}
+}
+
+// TestFlow_InstallLarge installs a skill over the configured file limit:
+// the wizard asks first, n cancels, and y installs it anyway.
+func TestFlow_InstallLarge(t *testing.T) {
+	srv := gittest.NewServer(t)
+	skills := srv.NewRepo("acme", "skills")
+	skills.AddSkill("skills/lint", "lint", "Lints things")
+	skills.WriteFile("skills/lint/rules.md", "rules\n")
+	skills.WriteFile("skills/lint/examples.md", "examples\n")
+	skills.Commit("add lint")
+
+	d := newDriver(t, driverOptions{
+		width:     120,
+		height:    40,
+		manifest:  skillManifest("acme", "lint", "Lints things", skills.Source("skills", "lint")),
+		overrides: srv.CloneURLOverrides(),
+	})
+	cfg, err := d.config.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.Settings.MaxSkillFiles = 2
+	if err := d.config.Save(cfg); err != nil {
+		t.Fatal(err)
+	}
+
+	installFromWizard(d, "acme", "lint")
+	if !d.app.assetWizard.isResolving() {
+		t.Fatalf("installing a skill over the limit did not ask first; view:\n%s", d.view())
+	}
+	d.waitForView("is too large")
+	d.press("n")
+	d.waitFor("the install to be cancelled", func(a App) bool { return a.activeView != viewAssetWizard })
+	if _, err := os.Stat(filepath.Join(d.project, ".agents", "skills", "lint")); !os.IsNotExist(err) {
+		t.Fatalf("a cancelled install wrote the skill (stat error %v)", err)
+	}
+
+	installFromWizard(d, "acme", "lint")
+	d.waitForView("is too large")
+	d.press("y")
+	d.waitFor("the summary step", func(a App) bool { return a.assetWizard.currentPhase() == assetPhaseSummary })
+	if got := lockedCommit(t, d.project, "lint"); got == "" {
+		t.Error("lint not locked after confirming the install")
+	}
+}
+
+// TestFlow_InstallOverModified installs a skill through the wizard again
+// after its source moved on and its installed copy was edited: the
+// install stops instead of discarding the local edit.
+func TestFlow_InstallOverModified(t *testing.T) {
+	srv := gittest.NewServer(t)
+	skills := srv.NewRepo("acme", "skills")
+	skills.AddSkill("skills/lint", "lint", "Lints things")
+	first := skills.Commit("add lint")
+
+	d := newDriver(t, driverOptions{
+		width:     120,
+		height:    40,
+		manifest:  skillManifest("acme", "lint", "Lints things", skills.Source("skills", "lint")),
+		overrides: srv.CloneURLOverrides(),
+	})
+
+	installFromWizard(d, "acme", "lint")
+	d.press("enter")
+	d.waitFor("lint to be installed", func(a App) bool { return hasSkill(a, "lint") })
+
+	skills.WriteFile("skills/lint/rules.md", "v2\n")
+	skills.Commit("update lint")
+	skillMD := filepath.Join(d.project, ".agents", "skills", "lint", "SKILL.md")
+	original, err := os.ReadFile(skillMD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	edited := string(original) + "Edited locally.\n"
+	if err := os.WriteFile(skillMD, []byte(edited), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	installFromWizard(d, "acme", "lint")
+	d.waitForView("has local modifications")
+	if data, _ := os.ReadFile(skillMD); string(data) != edited {
+		t.Fatalf("the install overwrote the local edit:\n%s", data)
+	}
+	if got := lockedCommit(t, d.project, "lint"); got != first {
+		t.Errorf("locked commit after a refused install = %q, want %q", got, first)
+	}
+}
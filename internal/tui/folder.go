@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
@@ -22,6 +23,7 @@ import (
 type folderModel struct {
 	width  int
 	height int
+	layout layoutMode
 
 	// Tabs.
 	activeKind asset.Kind
@@ -37,14 +39,22 @@ type folderModel struct {
 	regAssets  []core.RegistryAssetInfo // All registry assets (unified)
 	availCount int                      // Number of registry items NOT installed
 
-	// Update info: skill name -> update info.
-	updateInfo  map[string]core.UpdateInfo
-	updateCount int // Number of skills with updates available
+	// Update info: kind -> name -> update info.
+	updateInfo  map[asset.Kind]map[string]core.UpdateInfo
+	updateCount int // Number of skills and agents with updates available
 
 	// MCP data from lock file.
 	mcps []assetItem
+
+	// systemFilter limits the skill and agent lists to what one system sees
+	// (system name, "" = all systems).
+	systemFilter string
 }
 
+// updatableKinds are the kinds the folder view checks for updates and can
+// update in place.
+var updatableKinds = []asset.Kind{asset.KindSkill, asset.KindAgent}
+
 func newFolderModel() folderModel {
 	kinds := asset.Kinds()
 	if len(kinds) == 0 {
@@ -95,6 +105,7 @@ func newFolderList() list.Model {
 func (m folderModel) setSize(width, height int) folderModel {
 	m.width = width
 	m.height = height
+	m.tabs = m.tabs.setWidth(width)
 	// Actual list height is calculated dynamically in view().
 	for _, l := range m.lists {
 		l.SetSize(width, max(1, height))
@@ -102,20 +113,53 @@ func (m folderModel) setSize(width, height int) folderModel {
 	return m
 }
 
-func (m folderModel) setData(status *core.FolderStatus, isTracked bool, regAssets []core.RegistryAssetInfo, updateInfo map[string]core.UpdateInfo, mcps []assetItem) folderModel {
+// setLayout switches the view to a layout mode, rebuilding the installed
+// items when their system chips collapse or expand.
+func (m folderModel) setLayout(mode layoutMode) folderModel {
+	if mode == m.layout {
+		return m
+	}
+	wasCompact := m.compactChips()
+	m.layout = mode
+	if m.status == nil {
+		return m
+	}
+	m.tabs = m.updateTabLabels()
+	if m.compactChips() == wasCompact {
+		return m
+	}
+	for _, kind := range m.keyOrder {
+		if kind == asset.KindMCP {
+			continue
+		}
+		if list := m.lists[kind]; list != nil {
+			list.SetItems(installedAssetsToItems(kind, m.visibleAssets(kind), m.updateInfo[kind], m.compactChips()))
+		}
+	}
+	return m
+}
+
+// compactChips reports whether items collapse their system chips. Below
+// the full layout they do, so asset names stay readable.
+func (m folderModel) compactChips() bool {
+	return m.layout != layoutFull
+}
+
+func (m folderModel) setData(status *core.FolderStatus, isTracked bool, regAssets []core.RegistryAssetInfo, updateInfo map[asset.Kind]map[string]core.UpdateInfo, mcps []assetItem) folderModel {
 	m.status = status
 	m.isTracked = isTracked
 	m.regAssets = regAssets
 	m.availCount = m.countAvailable()
 	m.updateInfo = updateInfo
 	m.mcps = mcps
+	if m.systemFilter != "" && !slices.Contains(m.filterSystems(), m.systemFilter) {
+		m.systemFilter = ""
+	}
 
-	// Count skills with updates.
+	// Count skills and agents with updates.
 	m.updateCount = 0
-	for _, ui := range updateInfo {
-		if ui.HasUpdate {
-			m.updateCount++
-		}
+	for _, kind := range updatableKinds {
+		m.updateCount += m.kindUpdateCount(kind)
 	}
 
 	for _, kind := range m.keyOrder {
@@ -128,7 +172,7 @@ func (m folderModel) setData(status *core.FolderStatus, isTracked bool, regAsset
 			list.SetItems(lockedAssetsToItems(kind, lockedFromAssetItems(mcps), descLookupFromAssetItems(mcps)))
 		default:
 			if status != nil {
-				list.SetItems(installedAssetsToItems(kind, status.Assets[kind], updateInfo))
+				list.SetItems(installedAssetsToItems(kind, m.visibleAssets(kind), updateInfo[kind], m.compactChips()))
 			} else {
 				list.SetItems(nil)
 			}
@@ -145,23 +189,17 @@ func (m folderModel) setData(status *core.FolderStatus, isTracked bool, regAsset
 func (m folderModel) updateTabLabels() tabsModel {
 	defs := make([]tabDef, 0, len(m.keyOrder))
 	for _, kind := range m.keyOrder {
-		handler, _ := asset.Get(kind)
-		label := string(kind)
-		if handler != nil {
-			label = handler.DisplayName() + "s"
-		}
+		label := m.tabLabel(kind)
 		count := 0
 		switch kind {
 		case asset.KindMCP:
 			count = len(m.mcps)
 		default:
-			if m.status != nil {
-				count = len(m.status.Assets[kind])
-			}
+			count = len(m.visibleAssets(kind))
 		}
 		def := tabDef{label: fmt.Sprintf("%s (%d)", label, count)}
-		if kind == asset.KindSkill && m.updateCount > 0 {
-			def.extra = fmt.Sprintf(" ↓%d", m.updateCount)
+		if n := m.kindUpdateCount(kind); n > 0 {
+			def.extra = fmt.Sprintf(glyphs.updateCount, n)
 		}
 		defs = append(defs, def)
 	}
@@ -169,6 +207,131 @@ func (m folderModel) updateTabLabels() tabsModel {
 	return m.tabs.setTabs(defs)
 }
 
+// kindUpdateCount returns how many assets of kind have an update available.
+func (m folderModel) kindUpdateCount(kind asset.Kind) int {
+	n := 0
+	for _, ui := range m.updateInfo[kind] {
+		if ui.HasUpdate {
+			n++
+		}
+	}
+	return n
+}
+
+// tabLabel returns the tab label for a kind. In minimal layout MCP Servers
+// shortens to MCPs so all four tabs fit on one line.
+func (m folderModel) tabLabel(kind asset.Kind) string {
+	if kind == asset.KindMCP && m.layout == layoutMinimal {
+		return "MCPs"
+	}
+	if handler, ok := asset.Get(kind); ok {
+		return handler.DisplayName() + "s"
+	}
+	return string(kind)
+}
+
+// visibleAssets returns the installed assets of kind that pass the system
+// filter.
+func (m folderModel) visibleAssets(kind asset.Kind) []asset.InstalledAsset {
+	if m.status == nil {
+		return nil
+	}
+	if m.systemFilter == "" {
+		return m.status.Assets[kind]
+	}
+	var visible []asset.InstalledAsset
+	for _, a := range m.status.Assets[kind] {
+		if slices.Contains(a.Systems, m.systemFilter) {
+			visible = append(visible, a)
+		}
+	}
+	return visible
+}
+
+// filterSystems returns the names of the systems the system filter cycles
+// through: those that see at least one installed skill or agent, in
+// registration order.
+func (m folderModel) filterSystems() []string {
+	if m.status == nil {
+		return nil
+	}
+	seen := make(map[string]bool)
+	for kind, assets := range m.status.Assets {
+		if kind == asset.KindMCP {
+			continue
+		}
+		for _, a := range assets {
+			for _, name := range a.Systems {
+				seen[name] = true
+			}
+		}
+	}
+	var names []string
+	for _, sys := range system.All() {
+		if seen[sys.Name()] {
+			names = append(names, sys.Name())
+		}
+	}
+	return names
+}
+
+// cycleSystemFilter moves the system filter to the next system, and back to
+// all systems after the last one.
+func (m folderModel) cycleSystemFilter() folderModel {
+	names := m.filterSystems()
+	next := ""
+	if i := slices.Index(names, m.systemFilter); i+1 < len(names) {
+		next = names[i+1]
+	}
+	m.systemFilter = next
+
+	for _, kind := range m.keyOrder {
+		if kind == asset.KindMCP {
+			continue
+		}
+		if list := m.lists[kind]; list != nil {
+			list.ResetFilter()
+			list.SetItems(installedAssetsToItems(kind, m.visibleAssets(kind), m.updateInfo[kind], m.compactChips()))
+			list.ResetSelected()
+		}
+	}
+	m.tabs = m.updateTabLabels()
+	return m
+}
+
+// viewState returns the active tab and the item selected in each tab, to
+// restore when the folder is shown again.
+func (m folderModel) viewState() core.FolderView {
+	v := core.FolderView{Tab: m.activeKind, Selected: make(map[asset.Kind]string)}
+	for kind, l := range m.lists {
+		if item := l.SelectedItem(); item != nil {
+			v.Selected[kind] = item.FilterValue()
+		}
+	}
+	return v
+}
+
+// restoreView switches to a saved tab and reselects each tab's saved item,
+// which scrolls it into view. A tab whose item is gone starts at the top.
+func (m folderModel) restoreView(v core.FolderView) folderModel {
+	if idx := slices.Index(m.keyOrder, v.Tab); idx >= 0 {
+		m.activeKind = v.Tab
+		m.tabs.activeTab = idx
+	}
+	for kind, l := range m.lists {
+		l.ResetFilter()
+		idx := 0
+		for i, item := range l.Items() {
+			if item.FilterValue() == v.Selected[kind] {
+				idx = i
+				break
+			}
+		}
+		l.Select(idx)
+	}
+	return m
+}
+
 // activeList returns a pointer to the currently active list model.
 func (m *folderModel) activeList() *list.Model {
 	if list := m.lists[m.activeKind]; list != nil {
@@ -214,24 +377,27 @@ func (m folderModel) update(msg tea.Msg, app *App) (folderModel, tea.Cmd) {
 				return m, m.removeSelectedMCP(app)
 			case asset.KindSkill:
 				return m, m.removeSelectedSkill(app)
-			case asset.KindAgent:
-				return m, m.removeSelectedAgent(app)
+			case asset.KindAgent, asset.KindCommand, asset.KindRule:
+				return m, m.removeSelectedFileAsset(app, m.activeKind)
 			default:
 				return m, nil
 			}
 
 		case key.Matches(msg, keys.Update):
-			if m.activeKind == asset.KindSkill {
-				return m, m.updateSelectedSkill(app)
+			if slices.Contains(updatableKinds, m.activeKind) {
+				return m, m.updateSelected(app, m.activeKind)
 			}
 			return m, nil
 
 		case key.Matches(msg, keys.UpdateAll):
-			return m, m.updateAllSkills(app)
+			return m, m.updateAll(app)
 
 		case key.Matches(msg, keys.Refresh):
 			return m, m.refreshWithRegistries(app)
 
+		case key.Matches(msg, keys.SystemFilter):
+			return m.cycleSystemFilter(), nil
+
 		case key.Matches(msg, keys.Enter):
 			if m.activeKind == asset.KindSkill {
 				return m, m.openPreview(app)
@@ -297,22 +463,30 @@ func (m folderModel) view() string {
 	// 1. Render fixed chrome parts.
 	tabBar := m.tabs.view() + "\n"
 
-	// Build footer: optional update prefix + registry status.
+	// Build footer: optional system filter + update prefix + registry status.
+	// Minimal mode drops the key hints; the help bar still lists the keys.
+	hint := func(text string) string {
+		if m.layout == layoutMinimal {
+			return ""
+		}
+		return "  " + mutedStyle.Render(text)
+	}
 	var parts []string
+	if m.systemFilter != "" {
+		parts = append(parts,
+			badgeStyle.Render("Seen by "+systemDisplayName(m.systemFilter))+hint("[f] Next system"))
+	}
 	if m.updateCount > 0 {
 		parts = append(parts,
-			warningStyle.Render(fmt.Sprintf("%d updates available", m.updateCount))+
-				"  "+mutedStyle.Render("[u] Update"))
+			warningStyle.Render(fmt.Sprintf("%d updates available", m.updateCount))+hint("[u] Update"))
 	}
 
 	if m.availCount > 0 {
 		parts = append(parts,
-			mutedStyle.Render(fmt.Sprintf("%d available from registries", m.availCount))+
-				"  "+mutedStyle.Render("[i] Install"))
+			mutedStyle.Render(fmt.Sprintf("%d available from registries", m.availCount))+hint("[i] Install"))
 	} else if len(m.regAssets) == 0 {
 		parts = append(parts,
-			mutedStyle.Render("No registries configured.")+
-				"  "+mutedStyle.Render("[s] Settings to add"))
+			mutedStyle.Render("No registries configured.")+hint("[s] Settings to add"))
 	} else {
 		parts = append(parts,
 			mutedStyle.Render("All registry items installed"))
@@ -338,7 +512,11 @@ func (m folderModel) view() string {
 			if handler != nil {
 				emptyLabel = strings.ToLower(handler.DisplayName()) + "s"
 			}
-			listView = "\n" + mutedStyle.Render("  No "+emptyLabel+" installed")
+			if m.systemFilter != "" && m.activeKind != asset.KindMCP {
+				listView = "\n" + mutedStyle.Render("  No "+emptyLabel+" seen by "+systemDisplayName(m.systemFilter))
+			} else {
+				listView = "\n" + mutedStyle.Render("  No "+emptyLabel+" installed")
+			}
 		} else {
 			list.SetSize(m.width, listH)
 			listView = list.View()
@@ -385,7 +563,7 @@ func (m folderModel) removeSelectedSkill(app *App) tea.Cmd {
 			return assetRemovedMsg{kind: asset.KindSkill, name: skill.Name, err: fmt.Errorf("removing %s: %w", skill.Name, err)}
 		}
 		// Remove lock entry (TUI always updates lock file).
-		_ = core.RemoveAssetEntry(folderPath, asset.KindSkill, skillDirName)
+		_ = core.RemoveLayeredAssetEntry(folderPath, asset.KindSkill, skillDirName)
 		return assetRemovedMsg{kind: asset.KindSkill, name: skill.Name}
 	}
 
@@ -396,13 +574,14 @@ func (m folderModel) removeSelectedSkill(app *App) tea.Cmd {
 	return nil
 }
 
-// updateSelectedSkill updates the currently selected skill if it has an update available.
-func (m folderModel) updateSelectedSkill(app *App) tea.Cmd {
-	if m.updateCount == 0 {
+// updateSelected updates the selected asset of kind if it has an update
+// available.
+func (m folderModel) updateSelected(app *App, kind asset.Kind) tea.Cmd {
+	if m.kindUpdateCount(kind) == 0 {
 		return nil
 	}
 
-	list := m.lists[asset.KindSkill]
+	list := m.lists[kind]
 	if list == nil {
 		return nil
 	}
@@ -416,14 +595,14 @@ func (m folderModel) updateSelectedSkill(app *App) tea.Cmd {
 		return nil
 	}
 
-	ui, hasUpdate := m.updateInfo[si.name]
+	ui, hasUpdate := m.updateInfo[kind][si.name]
 	if !hasUpdate || !ui.HasUpdate {
 		return nil
 	}
 
 	folderPath := app.activeFolder
 
-	updateCmd := m.buildUpdateCmd(app, ui, folderPath)
+	updateCmd := m.buildUpdateCmd(app, kind, ui, folderPath)
 
 	shortOld := core.TruncateCommit(ui.InstalledCommit)
 	shortNew := core.TruncateCommit(ui.AvailableCommit)
@@ -435,44 +614,71 @@ func (m folderModel) updateSelectedSkill(app *App) tea.Cmd {
 	return nil
 }
 
-// updateAllSkills updates all skills that have updates available.
-func (m folderModel) updateAllSkills(app *App) tea.Cmd {
+// updateAll updates all skills and agents that have updates available.
+func (m folderModel) updateAll(app *App) tea.Cmd {
 	if m.updateCount == 0 {
 		return nil
 	}
 
 	folderPath := app.activeFolder
+	systems := make(map[asset.Kind]map[string][]system.System)
+	for _, kind := range updatableKinds {
+		systems[kind] = make(map[string][]system.System)
+		for name := range m.updateInfo[kind] {
+			systems[kind][name] = m.updateSystems(kind, name)
+		}
+	}
 
 	bulkCmd := func() tea.Msg {
-		var updated, errors int
+		var updated, unattested, errors int
 		cfg, cfgErr := app.config.Load()
+		overrides, mirrors := app.cloneURLs(cfg, cfgErr)
 
-		for _, ui := range m.updateInfo {
-			if !ui.HasUpdate {
-				continue
-			}
+		for _, kind := range updatableKinds {
+			for _, ui := range m.updateInfo[kind] {
+				if !ui.HasUpdate {
+					continue
+				}
 
-			err := executeSkillUpdate(app, ui, folderPath, cfg, cfgErr)
-			if err != nil {
-				errors++
-				continue
+				warning, err := executeUpdate(app, kind, ui, folderPath, systems[kind][ui.Name], cfg, cfgErr, overrides, mirrors)
+				if err != nil {
+					errors++
+					continue
+				}
+				if warning != "" {
+					unattested++
+				}
+				updated++
 			}
-			updated++
 		}
 
 		return bulkUpdateDoneMsg{
-			updated: updated,
-			errors:  errors,
+			updated:    updated,
+			unattested: unattested,
+			errors:     errors,
 		}
 	}
 
 	app.confirm = app.confirm.show(
-		fmt.Sprintf("Update all skills? (%d updates available)", m.updateCount),
+		fmt.Sprintf("Update all? (%d updates available)", m.updateCount),
 		bulkCmd,
 	)
 	return nil
 }
 
+// updateSystems returns the systems an updated asset is written to. Agents
+// are rendered again for each system they are installed for; skills go
+// wherever the install puts them (nil).
+func (m folderModel) updateSystems(kind asset.Kind, name string) []system.System {
+	if !asset.IsSystemFile(kind) || m.status == nil {
+		return nil
+	}
+	if systems := core.SystemsWithAsset(kind, name, m.status.Folder.Path); len(systems) > 0 {
+		return systems
+	}
+	return system.Supporting(kind)
+}
+
 // refreshWithRegistries triggers an async registry refresh + data reload.
 func (m folderModel) refreshWithRegistries(app *App) tea.Cmd {
 	return tea.Batch(
@@ -481,47 +687,47 @@ func (m folderModel) refreshWithRegistries(app *App) tea.Cmd {
 	)
 }
 
-// buildUpdateCmd creates a tea.Cmd that updates a single skill.
-func (m folderModel) buildUpdateCmd(app *App, ui core.UpdateInfo, folderPath string) tea.Cmd {
+// buildUpdateCmd creates a tea.Cmd that updates a single skill or agent.
+func (m folderModel) buildUpdateCmd(app *App, kind asset.Kind, ui core.UpdateInfo, folderPath string) tea.Cmd {
+	systems := m.updateSystems(kind, ui.Name)
 	return func() tea.Msg {
 		cfg, cfgErr := app.config.Load()
+		overrides, mirrors := app.cloneURLs(cfg, cfgErr)
 
-		err := executeSkillUpdate(app, ui, folderPath, cfg, cfgErr)
-		if err != nil {
-			return updateDoneMsg{
-				skillName: ui.Name,
-				err:       err,
-			}
-		}
-
+		warning, err := executeUpdate(app, kind, ui, folderPath, systems, cfg, cfgErr, overrides, mirrors)
 		return updateDoneMsg{
-			skillName: ui.Name,
+			name:    ui.Name,
+			warning: warning,
+			err:     err,
 		}
 	}
 }
 
-// executeSkillUpdate performs the actual update: remove old skill, reinstall at new commit,
-// update lock entry. Returns an error if any step fails.
-func executeSkillUpdate(app *App, ui core.UpdateInfo, folderPath string, cfg *core.Config, cfgErr error) error {
+// executeUpdate performs the actual update: install the new commit over the
+// old asset for systems (nil = the install's default), and update the lock
+// entry, attesting it when the attest setting is on.
+// Returns an error if any step fails, or a warning if the update could not
+// be attested, as the CLI does.
+func executeUpdate(app *App, kind asset.Kind, ui core.UpdateInfo, folderPath string, systems []system.System, cfg *core.Config, cfgErr error, overrides map[string]string, mirrors core.RegistryMirrors) (string, error) {
 	// Read lock file to get the ref.
-	lf, err := core.ReadLockFile(folderPath)
+	lf, err := core.ReadLayeredLockFile(folderPath)
 	if err != nil {
-		return fmt.Errorf("reading lock file: %w", err)
+		return "", fmt.Errorf("reading lock file: %w", err)
 	}
 	if lf == nil {
-		return fmt.Errorf("no lock file found")
+		return "", fmt.Errorf("no lock file found")
 	}
 
-	// Find the lock entry for this skill.
-	lockEntry := core.FindLockedAsset(lf, asset.KindSkill, ui.Name)
+	// Find the lock entry for this asset.
+	lockEntry := core.FindLockedAsset(lf, kind, ui.Name)
 	if lockEntry == nil {
-		return fmt.Errorf("skill %s not found in lock file", ui.Name)
+		return "", fmt.Errorf("%s %s not found in lock file", kind, ui.Name)
 	}
 
 	// Parse lock source to build a ParsedSource.
 	host, owner, repo, subPath, parseErr := core.ParseLockSource(ui.Source)
 	if parseErr != nil {
-		return fmt.Errorf("parsing source: %w", parseErr)
+		return "", fmt.Errorf("parsing source: %w", parseErr)
 	}
 
 	cloneURL := fmt.Sprintf("https://%s/%s/%s.git", host, owner, repo)
@@ -535,45 +741,55 @@ func executeSkillUpdate(app *App, ui core.UpdateInfo, folderPath string, cfg *co
 		Ref:      lockEntry.Ref,
 	}
 
-	// Apply clone URL override.
-	if cfgErr == nil && cfg != nil {
-		source.ApplyCloneURLOverride(cfg.Settings.CloneURLOverrides)
-	}
-
-	// Remove existing skill.
-	remover := core.NewOrchestrator()
-	removeErr := remover.RemoveAsset(asset.KindSkill, ui.Name, folderPath, system.All())
-	if removeErr != nil {
-		return fmt.Errorf("removing: %w", removeErr)
-	}
+	// Apply clone URL override, or the mirror of the registry entry the
+	// asset was installed from.
+	source.ApplyMirror(overrides, mirrors.Locked(*lockEntry))
 
-	// Reinstall at the available commit.
+	// Install the available commit over the installed one.
 	installer := core.NewOrchestrator()
-	result, installErr := installer.InstallFromSource(source, asset.KindSkill, core.OrchestratorInstallOptions{
+	installOpts := core.OrchestratorInstallOptions{
 		TargetDir:       folderPath,
-		NameFilter:      ui.Name,
+		TargetSystems:   systems,
+		NameFilter:      core.LockedUpstreamName(*lockEntry),
 		Commit:          ui.AvailableCommit,
 		IncludeInternal: true,
-	})
+		LegacyNames:     true,
+		Namespace:       core.LockedNamespace(*lockEntry),
+
+		Version:           ui.AvailableVersion,
+		VersionConstraint: core.LockedVersionConstraint(*lockEntry),
+
+		CloneURLOverrides: overrides,
+		Mirrors:           mirrors,
+	}
+	if core.LockedAliasOf(*lockEntry) != "" {
+		installOpts.Alias = lockEntry.Name
+	}
+	if cfgErr == nil && cfg != nil {
+		installOpts.IgnorePatterns = cfg.Settings.IgnorePatterns
+	}
+	result, installErr := installer.UpdateAsset(source, kind, *lockEntry, installOpts)
 	if installErr != nil {
-		return fmt.Errorf("installing: %w", installErr)
+		return "", fmt.Errorf("installing: %w", installErr)
 	}
 
-	// Update lock file with new commit.
+	// Update lock file with new commit, keeping the entry in its lock layer.
+	writeLock := core.AddOrUpdateAsset
+	if lf.Origin(kind, ui.Name) == core.OriginLocal {
+		writeLock = core.AddOrUpdateLocalAsset
+	}
+	var warnings []string
 	for _, r := range result {
-		entry := asset.LockedAsset{
-			Kind:   asset.KindSkill,
-			Name:   r.Asset.Name,
-			Source: r.Asset.Source,
-			Commit: r.Commit,
-			Ref:    r.Ref,
+		entry := r.LockEntry(lockEntry.Platforms, lockEntry.Tags)
+		if lockErr := writeLock(folderPath, entry); lockErr != nil {
+			return "", fmt.Errorf("updating lock file: %w", lockErr)
 		}
-		if lockErr := core.AddOrUpdateAsset(folderPath, entry); lockErr != nil {
-			return fmt.Errorf("updating lock file: %w", lockErr)
+		if _, err := attestAsset(app, cfg, folderPath, entry, "update"); err != nil {
+			warnings = append(warnings, err.Error())
 		}
 	}
 
-	return nil
+	return strings.Join(warnings, "; "), nil
 }
 
 // removeSelectedMCP shows a confirmation dialog for the selected MCP.
@@ -622,13 +838,22 @@ func (m folderModel) removeSelectedMCP(app *App) tea.Cmd {
 
 	deleteCmd := func() tea.Msg {
 		// Remove from all MCP-capable system config files.
+		opts := system.RemoveMCPOptions{URLHash: asset.LockedURLHash(*mcp.locked)}
 		for _, sys := range mcpSystems {
-			if err := sys.Remove(asset.KindMCP, mcp.locked.Name, folderPath); err != nil {
+			var err error
+			if r, ok := sys.(interface {
+				RemoveMCP(string, string, system.RemoveMCPOptions) error
+			}); ok {
+				err = r.RemoveMCP(mcp.locked.Name, folderPath, opts)
+			} else {
+				err = sys.Remove(asset.KindMCP, mcp.locked.Name, folderPath)
+			}
+			if err != nil {
 				return assetRemovedMsg{kind: asset.KindMCP, name: mcp.locked.Name, err: fmt.Errorf("removing MCP %s: %w", mcp.locked.Name, err)}
 			}
 		}
 		// Remove lock entry.
-		_ = core.RemoveAssetEntry(folderPath, asset.KindMCP, mcp.locked.Name)
+		_ = core.RemoveLayeredAssetEntry(folderPath, asset.KindMCP, mcp.locked.Name)
 		return assetRemovedMsg{kind: asset.KindMCP, name: mcp.locked.Name}
 	}
 
@@ -636,9 +861,10 @@ func (m folderModel) removeSelectedMCP(app *App) tea.Cmd {
 	return nil
 }
 
-// removeSelectedAgent shows a confirmation dialog for the selected agent.
-func (m folderModel) removeSelectedAgent(app *App) tea.Cmd {
-	list := m.lists[asset.KindAgent]
+// removeSelectedFileAsset shows a confirmation dialog for the selected
+// agent, command, or rule.
+func (m folderModel) removeSelectedFileAsset(app *App, kind asset.Kind) tea.Cmd {
+	list := m.lists[kind]
 	if list == nil {
 		return nil
 	}
@@ -652,20 +878,24 @@ func (m folderModel) removeSelectedAgent(app *App) tea.Cmd {
 		return nil
 	}
 
-	agentName := ai.name
+	name := ai.name
 	folderPath := app.activeFolder
+	label := string(kind)
+	if handler, ok := asset.Get(kind); ok {
+		label = strings.ToLower(handler.DisplayName())
+	}
 
 	deleteCmd := func() tea.Msg {
 		orch := core.NewOrchestrator()
-		if err := orch.RemoveAsset(asset.KindAgent, agentName, folderPath, nil); err != nil {
-			return assetRemovedMsg{kind: asset.KindAgent, name: agentName, err: fmt.Errorf("removing agent %s: %w", agentName, err)}
+		if err := orch.RemoveAsset(kind, name, folderPath, nil); err != nil {
+			return assetRemovedMsg{kind: kind, name: name, err: fmt.Errorf("removing %s %s: %w", label, name, err)}
 		}
-		_ = core.RemoveAssetEntry(folderPath, asset.KindAgent, agentName)
-		return assetRemovedMsg{kind: asset.KindAgent, name: agentName}
+		_ = core.RemoveLayeredAssetEntry(folderPath, kind, name)
+		return assetRemovedMsg{kind: kind, name: name}
 	}
 
 	app.confirm = app.confirm.show(
-		fmt.Sprintf("Remove agent %s?", agentName),
+		fmt.Sprintf("Remove %s %s?", label, name),
 		deleteCmd,
 	)
 	return nil
@@ -0,0 +1,65 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestFolder_SystemFilter(t *testing.T) {
+	status := &core.FolderStatus{Assets: map[asset.Kind][]asset.InstalledAsset{
+		asset.KindSkill: {
+			{Kind: asset.KindSkill, Name: "shared", Systems: []string{"codex", "claude-code"}},
+			{Kind: asset.KindSkill, Name: "unlinked", Systems: []string{"codex"}},
+		},
+	}}
+	m := newFolderModel().setSize(80, 20).setData(status, true, nil, nil, nil)
+
+	names := func() []string {
+		var out []string
+		for _, item := range m.lists[asset.KindSkill].Items() {
+			out = append(out, item.(assetItem).name)
+		}
+		return out
+	}
+	press := func() {
+		m, _ = m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("f")}, nil)
+	}
+
+	if got := strings.Join(names(), ","); got != "shared,unlinked" {
+		t.Fatalf("unfiltered items = %s", got)
+	}
+	view := ansi.Strip(m.view())
+	if !strings.Contains(view, "[Claude Code]") || !strings.Contains(view, "[Codex]") {
+		t.Errorf("view missing system chips:\n%s", view)
+	}
+
+	// Systems cycle in registration order, then back to all.
+	press()
+	if got := strings.Join(names(), ","); m.systemFilter != "claude-code" || got != "shared" {
+		t.Errorf("filter %q items = %s, want claude-code: shared", m.systemFilter, got)
+	}
+	if view := ansi.Strip(m.view()); !strings.Contains(view, "Seen by Claude Code") || !strings.Contains(view, "Skills (1)") {
+		t.Errorf("filtered view missing filter or count:\n%s", view)
+	}
+	press()
+	if got := strings.Join(names(), ","); m.systemFilter != "codex" || got != "shared,unlinked" {
+		t.Errorf("filter %q items = %s, want codex: shared,unlinked", m.systemFilter, got)
+	}
+	press()
+	if m.systemFilter != "" {
+		t.Errorf("filter = %q after the last system, want all", m.systemFilter)
+	}
+
+	// A refresh drops a filter for a system that no longer sees anything.
+	m.systemFilter = "cursor"
+	m = m.setData(status, true, nil, nil, nil)
+	if m.systemFilter != "" {
+		t.Errorf("filter = %q after refresh, want cleared", m.systemFilter)
+	}
+}
@@ -0,0 +1,130 @@
+package tui
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+// updateGolden rewrites the golden files instead of comparing against them:
+//
+//	go test ./internal/tui -run TestGolden -update
+var updateGolden = flag.Bool("update", false, "rewrite the golden files in testdata/golden")
+
+// goldenSizes are the terminal sizes every golden view is rendered at: one
+// in each layout mode (minimal at 60, compact at 80, full at 100) and a
+// roomy one, so layout math is covered at every breakpoint.
+var goldenSizes = []struct{ width, height int }{
+	{60, 24},
+	{80, 24},
+	{100, 30},
+	{120, 40},
+}
+
+const goldenManifest = `{
+  "name": "acme",
+  "description": "Acme team registry",
+  "skills": [
+    {"name": "go-review", "description": "Review Go code", "source": "github.com/acme/skills/skills/go-review"},
+    {"name": "release-notes", "description": "Draft release notes", "source": "github.com/acme/skills/skills/release-notes"}
+  ],
+  "mcps": [
+    {"name": "db", "description": "Query the team database", "command": "npx", "args": ["-y", "@acme/db"]}
+  ]
+}
+`
+
+// assertGolden compares the app's view, without styling, to
+// testdata/golden/<name>.golden.
+func (d *driver) assertGolden(name string) {
+	d.t.Helper()
+	got := d.view()
+	path := filepath.Join(d.golden, name+".golden")
+	if *updateGolden {
+		if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+			d.t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(got), 0o644); err != nil {
+			d.t.Fatal(err)
+		}
+		return
+	}
+	want, err := os.ReadFile(path)
+	if err != nil {
+		d.t.Fatalf("reading golden file (run with -update to create it): %v", err)
+	}
+	if got != string(want) {
+		d.t.Errorf("view does not match %s (run with -update to accept):\n--- got\n%s--- want\n%s", path, got, want)
+	}
+}
+
+// goldenViews open each main view from a freshly loaded app. Only the
+// messages they send are applied, so the views are never mid-update.
+var goldenViews = []struct {
+	name string
+	open func(d *driver)
+}{
+	{"folder", func(d *driver) {}},
+	{"install_picker", func(d *driver) {
+		d.press("i")
+		if d.app.activeView != viewInstallPicker {
+			d.t.Fatal("i did not open the install picker")
+		}
+	}},
+	{"asset_wizard", func(d *driver) {
+		d.send(openAssetWizardMsg{
+			asset:        d.registryAsset("go-review"),
+			allSystems:   system.All(),
+			activeFolder: d.project,
+		})
+	}},
+	{"registry_wizard", func(d *driver) {
+		d.press("s")
+		d.send(openRegistryWizardMsg{})
+	}},
+	{"clone_error", func(d *driver) {
+		d.press("s")
+		d.send(openRegistryWizardMsg{})
+		d.send(registryAddDoneMsg{
+			url: "git@github.com:acme/private-registry.git",
+			err: &core.CloneError{
+				Kind:      core.CloneErrSSHKey,
+				Protocol:  "ssh",
+				URL:       "git@github.com:acme/private-registry.git",
+				Command:   "git clone git@github.com:acme/private-registry.git",
+				RawOutput: "git@github.com: Permission denied (publickey).",
+				Hints:     []string{"Add your SSH key to the agent: ssh-add ~/.ssh/id_ed25519"},
+			},
+		})
+		if d.app.activeView != viewCloneError {
+			d.t.Fatal("clone error overlay not shown")
+		}
+	}},
+}
+
+func TestGolden(t *testing.T) {
+	for _, v := range goldenViews {
+		for _, size := range goldenSizes {
+			name := fmt.Sprintf("%s_%dx%d", v.name, size.width, size.height)
+			t.Run(name, func(t *testing.T) {
+				d := newDriver(t, driverOptions{
+					width:    size.width,
+					height:   size.height,
+					manifest: goldenManifest,
+					skills:   map[string]string{"lint": "Lint the project"},
+				})
+				if n := len(d.app.activeFolderStatus.Assets[asset.KindSkill]); n != 1 {
+					t.Fatalf("project has %d skills, want 1", n)
+				}
+				v.open(d)
+				d.assertGolden(name)
+			})
+		}
+	}
+}
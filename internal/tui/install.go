@@ -1,11 +1,14 @@
 package tui
 
 import (
+	"fmt"
+	"path/filepath"
 	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/list"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
 
 	"github.com/barysiuk/duckrow/internal/core"
 	"github.com/barysiuk/duckrow/internal/core/asset"
@@ -26,6 +29,7 @@ type assetInstalledMsg struct {
 	kind   asset.Kind
 	name   string
 	folder string
+	report core.InstallReport // what was installed, when err is nil
 	err    error
 }
 
@@ -85,8 +89,18 @@ type installModel struct {
 	allSystems    []system.System          // All system definitions
 	allRegAssets  []core.RegistryAssetInfo // All registry assets (for filtering)
 	installedMCPs []assetItem              // Currently installed MCPs (for filtering)
+	config        *core.ConfigManager      // For the systems an install would target
+
+	// Detail pane for the selected MCP, resolved when the selection changes.
+	detailKey     string
+	detailEnv     []envVarStatus
+	detailSystems []system.System
 }
 
+// mcpDetailMinListHeight is the list height below which the MCP detail pane
+// is hidden to leave room for the list.
+const mcpDetailMinListHeight = 4
+
 func newInstallModel() installModel {
 	l := list.New(nil, registryAssetDelegate{}, 0, 0)
 	l.SetShowTitle(false)
@@ -115,7 +129,7 @@ func (m installModel) setSize(width, height int) installModel {
 
 // activate is called when the install picker opens. It filters registry items
 // to show only those NOT already installed in the active folder, scoped to
-// the given filter (asset kind).
+// the given filter (asset kind). Entries the folder excludes are hidden.
 func (m installModel) activate(filter installFilter, activeFolder string, regAssets []core.RegistryAssetInfo, folderStatus *core.FolderStatus, systems []system.System) installModel {
 	m.activeFolder = activeFolder
 	m.allSystems = systems
@@ -138,9 +152,11 @@ func (m installModel) activate(filter installFilter, activeFolder string, regAss
 		}
 	}
 
-	// Filter to available (not installed) assets of the selected kind.
+	// Filter to available (not installed, not excluded) assets of the
+	// selected kind. An unreadable lock file excludes nothing.
+	excludes, _ := core.ProjectExcludes(activeFolder)
 	m.available = nil
-	for _, info := range regAssets {
+	for _, info := range excludes.Filter(regAssets) {
 		if info.Kind != asset.Kind(filter) {
 			continue
 		}
@@ -163,14 +179,17 @@ func (m installModel) activate(filter installFilter, activeFolder string, regAss
 		}
 	}
 
+	m.detailKey = ""
+	m.refreshDetail()
 	return m
 }
 
-// setMCPData sets the MCP data needed for filtering in the install picker.
-// Called from app.go before activate.
-func (m installModel) setMCPData(regAssets []core.RegistryAssetInfo, installedMCPs []assetItem) installModel {
+// setMCPData sets the MCP data needed for filtering the install picker and
+// for its detail pane. Called from app.go before activate.
+func (m installModel) setMCPData(regAssets []core.RegistryAssetInfo, installedMCPs []assetItem, config *core.ConfigManager) installModel {
 	m.allRegAssets = regAssets
 	m.installedMCPs = installedMCPs
+	m.config = config
 	m.list.ResetFilter()
 	return m
 }
@@ -195,10 +214,76 @@ func (m installModel) update(msg tea.Msg) (installModel, tea.Cmd) {
 
 	// Skip separator items.
 	m.skipSeparators()
+	m.refreshDetail()
 
 	return m, cmd
 }
 
+// selectedMCP returns the MCP registry entry under the cursor, if any.
+func (m installModel) selectedMCP() (core.RegistryAssetInfo, bool) {
+	if asset.Kind(m.filter) != asset.KindMCP {
+		return core.RegistryAssetInfo{}, false
+	}
+	it, ok := m.list.SelectedItem().(registryAssetItem)
+	return it.info, ok
+}
+
+// refreshDetail resolves the env status and target systems shown in the
+// detail pane when the selected MCP changes.
+func (m *installModel) refreshDetail() {
+	info, ok := m.selectedMCP()
+	if !ok {
+		m.detailKey = ""
+		m.detailEnv = nil
+		m.detailSystems = nil
+		return
+	}
+	key := info.RegistryRepo + "\x00" + info.Entry.Name
+	if key == m.detailKey {
+		return
+	}
+	m.detailKey = key
+	meta, _ := info.Entry.Meta.(asset.MCPMeta)
+	m.detailEnv = mcpEnvStatus(meta, m.activeFolder)
+
+	m.detailSystems = nil
+	if m.config == nil {
+		return
+	}
+	preselected, _ := preselectedSystems(m.config, m.activeFolder, asset.KindMCP)
+	capable := make(map[string]bool)
+	for _, s := range system.Supporting(asset.KindMCP) {
+		capable[s.Name()] = true
+	}
+	for _, s := range preselected {
+		if capable[s.Name()] {
+			m.detailSystems = append(m.detailSystems, s)
+		}
+	}
+}
+
+// detailView renders the detail pane for the selected MCP: how it runs, its
+// required env and whether each var is set, and the systems an install
+// would target by default.
+func (m installModel) detailView() string {
+	info, ok := m.selectedMCP()
+	if !ok {
+		return ""
+	}
+	meta, _ := info.Entry.Meta.(asset.MCPMeta)
+
+	var b strings.Builder
+	b.WriteString(renderSectionHeader("DETAILS", m.width))
+	b.WriteString("\n")
+	writeMCPDetails(&b, meta, m.detailEnv)
+	if len(m.detailSystems) > 0 {
+		b.WriteString("Systems:  " + normalItemStyle.Render(strings.Join(system.DisplayNames(m.detailSystems), ", ")))
+	} else {
+		b.WriteString("Systems:  " + mutedStyle.Render("none detected, choose in the next step"))
+	}
+	return b.String()
+}
+
 // handleItemSelected emits the appropriate wizard message for the selected item.
 func (m installModel) handleItemSelected() (installModel, tea.Cmd) {
 	item := m.list.SelectedItem()
@@ -246,10 +331,170 @@ func (m installModel) view() string {
 		return mutedStyle.Render("  All registry " + label + " are already installed.")
 	}
 
-	// Size list to fill available space.
-	m.list.SetSize(m.width, max(1, m.height))
+	// Size list to fill available space, leaving room for the MCP detail
+	// pane when it fits.
+	detail := m.detailView()
+	listH := m.height
+	if detail != "" {
+		listH = m.height - lipgloss.Height(detail) - 1
+		if listH < mcpDetailMinListHeight {
+			detail = ""
+			listH = m.height
+		}
+	}
+	m.list.SetSize(m.width, max(1, listH))
 
-	return m.list.View()
+	if detail == "" {
+		return m.list.View()
+	}
+	return lipgloss.JoinVertical(lipgloss.Left, m.list.View(), "", detail)
 }
 
 // (buildRegistryAssets removed — the unified core.RegistryAssetInfo is used directly)
+
+// installSummary turns an install report into the status bar message and,
+// when the registry entry has a post-install message, the text of a notice
+// dialog so it isn't missed like a status bar message would be.
+func installSummary(report core.InstallReport) (status string, kind statusMsgKind, notice string) {
+	var names, missing []string
+	for _, a := range report.Installed() {
+		names = append(names, a.Name)
+		missing = append(missing, a.MissingRequirements...)
+	}
+	verb := "Installed"
+	if len(names) == 0 {
+		verb = "Already installed"
+		for _, a := range report.Assets {
+			names = append(names, a.Name)
+		}
+	}
+	label := strings.Join(names, ", ")
+	if handler, ok := asset.Get(report.Kind); ok && len(names) == 1 {
+		label = handler.DisplayName() + " " + label
+	}
+
+	status, kind = verb+" "+label, statusSuccess
+	if len(missing) > 0 {
+		status = fmt.Sprintf("Installed %s, but this folder has no %s; it may not work here",
+			label, strings.Join(missing, ", "))
+		kind = statusWarning
+	} else if len(report.Warnings) > 0 {
+		status = fmt.Sprintf("%s %s; %s", verb, label, report.Warnings[0])
+		kind = statusWarning
+	}
+
+	// The MCP wizard asks for missing env vars itself, so only the note
+	// needs a dialog.
+	if note := strings.TrimSpace(report.PostInstallMessage); note != "" && verb == "Installed" {
+		notice = fmt.Sprintf("Installed %s\n\n%s", label, note)
+	}
+	return status, kind, notice
+}
+
+// installReportText describes what a wizard install wrote, for the summary
+// step and for copying: each asset with the files, links, and config
+// entries written for each system, then the lock file, the warnings, and
+// the registry entry's note.
+func installReportText(report core.InstallReport, folder string) string {
+	label := string(report.Kind)
+	if handler, ok := asset.Get(report.Kind); ok {
+		label = handler.DisplayName()
+	}
+
+	var b strings.Builder
+	for i, a := range report.Assets {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		verb := "Installed"
+		if a.Unchanged {
+			verb = "Already installed"
+		}
+		fmt.Fprintf(&b, "%s %s %s", verb, label, a.Name)
+		if a.Version != "" {
+			fmt.Fprintf(&b, " %s", a.Version)
+		}
+		if a.Commit != "" {
+			fmt.Fprintf(&b, " at %s", core.TruncateCommit(a.Commit))
+		}
+		b.WriteString("\n")
+		if a.Namespace != "" {
+			fmt.Fprintf(&b, "  Namespaced: %s from registry %s\n", a.AliasOf, a.Namespace)
+		} else if a.AliasOf != "" {
+			fmt.Fprintf(&b, "  Alias of: %s\n", a.AliasOf)
+		}
+		for _, line := range writtenPaths(report.Kind, a, folder) {
+			b.WriteString("  " + line + "\n")
+		}
+	}
+
+	if report.LockFile != "" {
+		fmt.Fprintf(&b, "\nUpdated %s\n", report.LockFile)
+	}
+	for _, path := range report.Attestations {
+		fmt.Fprintf(&b, "Attested: %s\n", path)
+	}
+	if len(report.Warnings) > 0 {
+		b.WriteString("\nWarnings:\n")
+		for _, w := range report.Warnings {
+			b.WriteString("  ! " + w + "\n")
+		}
+	}
+	if note := strings.TrimSpace(report.PostInstallMessage); note != "" && len(report.Installed()) > 0 {
+		b.WriteString("\nNote:\n")
+		for _, line := range strings.Split(note, "\n") {
+			b.WriteString(strings.TrimRight("  "+line, " ") + "\n")
+		}
+	}
+	return strings.TrimRight(b.String(), "\n")
+}
+
+// writtenPaths lists what installing a wrote, relative to folder, one line
+// per path: for skills the shared copy in .agents/skills and each other
+// system's link to it, for other kinds each system's file or config entry.
+func writtenPaths(kind asset.Kind, a core.InstallReportAsset, folder string) []string {
+	rel := func(path string) string {
+		if r, err := filepath.Rel(folder, path); err == nil && filepath.IsAbs(path) && !strings.HasPrefix(r, "..") {
+			return r
+		}
+		return path
+	}
+
+	var lines []string
+	if kind == asset.KindSkill {
+		shared := filepath.Join(".agents", "skills", a.Name)
+		if !a.Unchanged {
+			lines = append(lines, fmt.Sprintf("+ %s (read by %s)", shared,
+				strings.Join(system.DisplayNames(system.Universal()), ", ")))
+		}
+		how := "link to"
+		if core.CurrentInstallStrategy() == core.StrategyCopy {
+			how = "copy of"
+		}
+		for _, name := range a.Systems {
+			sys, ok := system.ByName(name)
+			if !ok || sys.IsUniversal() {
+				continue
+			}
+			lines = append(lines, fmt.Sprintf("+ %s (%s, %s %s)",
+				rel(sys.AssetPath(kind, a.Name, folder)), sys.DisplayName(), how, shared))
+		}
+		return lines
+	}
+
+	for _, f := range a.Files {
+		path := rel(f.Path)
+		if f.Key != "" {
+			path += " " + f.Key
+		}
+		switch {
+		case f.Error != "":
+			lines = append(lines, fmt.Sprintf("x %s (%s): %s", path, f.System, f.Error))
+		case f.Skipped != "":
+			lines = append(lines, fmt.Sprintf("! %s (%s): %s", path, f.System, f.Skipped))
+		default:
+			lines = append(lines, fmt.Sprintf("+ %s (%s)", path, f.System))
+		}
+	}
+	return lines
+}
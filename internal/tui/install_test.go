@@ -0,0 +1,273 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+func testMCPAssets() []core.RegistryAssetInfo {
+	return []core.RegistryAssetInfo{
+		{RegistryName: "org", RegistryRepo: "repo-a", Kind: asset.KindMCP, Entry: asset.RegistryEntry{
+			Name: "db",
+			Meta: asset.MCPMeta{Command: "npx", Args: []string{"-y", "@org/db"}, Env: []string{"DUCKROW_TEST_DB_URL"}},
+		}},
+		{RegistryName: "org", RegistryRepo: "repo-a", Kind: asset.KindMCP, Entry: asset.RegistryEntry{
+			Name: "search",
+			Meta: asset.MCPMeta{URL: "https://mcp.example.com/search", Transport: "http"},
+		}},
+	}
+}
+
+func TestInstallPicker_MCPDetails(t *testing.T) {
+	folder := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(folder, ".cursor"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	config := core.NewConfigManagerWithDir(t.TempDir())
+
+	m := newInstallModel().setSize(80, 30)
+	m = m.setMCPData(testMCPAssets(), nil, config)
+	m = m.activate(installFilter(asset.KindMCP), folder, testMCPAssets(), nil, system.All())
+
+	view := ansi.Strip(m.view())
+	for _, want := range []string{"Command:  npx -y @org/db", "DUCKROW_TEST_DB_URL  ! not set", "Systems:  Cursor"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyDown})
+	view = ansi.Strip(m.view())
+	for _, want := range []string{"URL:      https://mcp.example.com/search", "Type:     http"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view after moving down missing %q:\n%s", want, view)
+		}
+	}
+}
+
+func TestMCPPreview_ShowsConfigDiff(t *testing.T) {
+	folder := t.TempDir()
+	configPath := filepath.Join(folder, ".cursor", "mcp.json")
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	existing := "{\n  // hand-maintained\n  \"mcpServers\": {}\n}\n"
+	if err := os.WriteFile(configPath, []byte(existing), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	cursor, _ := system.ByName("cursor")
+
+	entry := testMCPAssets()[0].Entry
+	targets := []system.System{cursor}
+
+	view := ansi.Strip(mcpPreviewStepModel{
+		mcp:          entry,
+		targetAgents: targets,
+		diffs:        mcpConfigDiffs(entry, folder, targets),
+		activeFolder: folder,
+	}.View())
+	for _, want := range []string{".cursor/mcp.json  (Cursor)", "// hand-maintained", `+    "db": {`, "more lines"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("preview missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "+++") {
+		t.Errorf("preview shows diff headers:\n%s", view)
+	}
+	if data, _ := os.ReadFile(configPath); string(data) != existing {
+		t.Errorf("preview wrote the config:\n%s", data)
+	}
+}
+
+func TestInstallPicker_NoDetailsForSkills(t *testing.T) {
+	m := newInstallModel().setSize(80, 30)
+	m = m.activate(installFilter(asset.KindSkill), t.TempDir(), testPickerAssets(), nil, system.All())
+	if view := ansi.Strip(m.view()); strings.Contains(view, "DETAILS") {
+		t.Errorf("skill picker shows a detail pane:\n%s", view)
+	}
+}
+
+func TestInstallPicker_HidesExcluded(t *testing.T) {
+	folder := t.TempDir()
+	if _, err := core.AddExcludes(folder, []string{"mcp:db"}, false); err != nil {
+		t.Fatal(err)
+	}
+	m := newInstallModel().setSize(80, 30)
+	m = m.activate(installFilter(asset.KindMCP), folder, testMCPAssets(), nil, system.All())
+	if len(m.available) != 1 || m.available[0].Entry.Name != "search" {
+		t.Errorf("available = %v, want only search", m.available)
+	}
+}
+
+func TestMCPPreview_ConfigureEnvVars(t *testing.T) {
+	folder := t.TempDir()
+	entry := testMCPAssets()[0].Entry
+	meta := entry.Meta.(asset.MCPMeta)
+
+	var step tea.Model = mcpPreviewStepModel{mcp: entry, envStatus: mcpEnvStatus(meta, folder), activeFolder: folder}
+	step, _ = step.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("c")})
+	m := step.(mcpPreviewStepModel)
+	if m.stepName() != "Configure" || !m.handlesBack() {
+		t.Fatalf("c did not open the env var form (step %q)", m.stepName())
+	}
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "DUCKROW_TEST_DB_URL") {
+		t.Errorf("form does not ask for the missing var:\n%s", view)
+	}
+
+	step, _ = step.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("postgres://db")})
+	step, cmd := step.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("enter did not save the value")
+	}
+	step, _ = step.Update(cmd())
+	m = step.(mcpPreviewStepModel)
+	if m.stepName() != "Preview" || m.handlesBack() {
+		t.Fatalf("saving the last var did not return to the preview (step %q)", m.stepName())
+	}
+	if view := ansi.Strip(m.View()); !strings.Contains(view, "(project)") {
+		t.Errorf("preview does not show the saved var as set:\n%s", view)
+	}
+}
+
+func TestAssetWizard_Flows(t *testing.T) {
+	d := newDriver(t, driverOptions{width: 100, height: 30})
+
+	stepNames := func() []string {
+		var names []string
+		for _, s := range d.app.assetWizard.wizard.steps {
+			names = append(names, s.label())
+		}
+		return names
+	}
+
+	// An MCP previews its config before installing, and needs a system.
+	d.send(openAssetWizardMsg{asset: testMCPAssets()[0], allSystems: system.All(), activeFolder: d.project})
+	if got, want := strings.Join(stepNames(), " → "), "Select Agents → Preview → Installing → Summary"; got != want {
+		t.Errorf("MCP steps = %s, want %s", got, want)
+	}
+	d.app.assetWizard = d.app.assetWizard.checkSystems(nil)
+	d.press("enter")
+	d.waitForView("select at least one agent")
+	d.app.assetWizard = d.app.assetWizard.checkSystems([]string{"cursor"})
+	d.press("enter")
+	d.waitForView(".cursor/mcp.json  (Cursor)")
+
+	// A kind without a flow of its own gets the source-based one.
+	if _, ok := wizardFlowFor(asset.KindRule).(sourceWizardFlow); !ok {
+		t.Errorf("rule flow = %T, want sourceWizardFlow", wizardFlowFor(asset.KindRule))
+	}
+	rule := core.RegistryAssetInfo{RegistryName: "org", Kind: asset.KindRule, Entry: asset.RegistryEntry{Name: "style", Description: "House style"}}
+	d.send(openAssetWizardMsg{asset: rule, allSystems: system.All(), activeFolder: d.project})
+	if got, want := strings.Join(stepNames(), " → "), "Select Agents → Installing → Summary"; got != want {
+		t.Errorf("rule steps = %s, want %s", got, want)
+	}
+	d.waitForView("House style")
+}
+
+func TestInstallSummary(t *testing.T) {
+	tests := []struct {
+		name       string
+		report     core.InstallReport
+		wantStatus string
+		wantKind   statusMsgKind
+		wantNotice string
+	}{
+		{
+			name:       "installed",
+			report:     core.InstallReport{Kind: asset.KindAgent, Assets: []core.InstallReportAsset{{Name: "reviewer"}}},
+			wantStatus: "Installed Agent reviewer",
+			wantKind:   statusSuccess,
+		},
+		{
+			name:       "unchanged",
+			report:     core.InstallReport{Kind: asset.KindSkill, Assets: []core.InstallReportAsset{{Name: "go-review", Unchanged: true}}, PostInstallMessage: "Run make."},
+			wantStatus: "Already installed Skill go-review",
+			wantKind:   statusSuccess,
+		},
+		{
+			name: "missing requirements",
+			report: core.InstallReport{Kind: asset.KindSkill, Assets: []core.InstallReportAsset{
+				{Name: "go-review", MissingRequirements: []string{"go.mod"}},
+			}},
+			wantStatus: "Installed Skill go-review, but this folder has no go.mod; it may not work here",
+			wantKind:   statusWarning,
+		},
+		{
+			name:       "lock warning",
+			report:     core.InstallReport{Kind: asset.KindMCP, Assets: []core.InstallReportAsset{{Name: "db"}}, Warnings: []string{"failed to update lock file"}},
+			wantStatus: "Installed MCP Server db; failed to update lock file",
+			wantKind:   statusWarning,
+		},
+		{
+			name:       "post-install message",
+			report:     core.InstallReport{Kind: asset.KindSkill, Assets: []core.InstallReportAsset{{Name: "db-helper"}}, PostInstallMessage: "Run make seed first.\n"},
+			wantStatus: "Installed Skill db-helper",
+			wantKind:   statusSuccess,
+			wantNotice: "Installed Skill db-helper\n\nRun make seed first.",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, kind, notice := installSummary(tt.report)
+			if status != tt.wantStatus || kind != tt.wantKind || notice != tt.wantNotice {
+				t.Errorf("installSummary() = %q, %v, %q; want %q, %v, %q",
+					status, kind, notice, tt.wantStatus, tt.wantKind, tt.wantNotice)
+			}
+		})
+	}
+}
+
+func TestInstallReportText(t *testing.T) {
+	folder := t.TempDir()
+
+	mcp := installReportText(core.InstallReport{
+		Kind: asset.KindMCP,
+		Assets: []core.InstallReportAsset{{Name: "db", Files: []core.InstallReportFile{
+			{Path: ".cursor/mcp.json", System: "Cursor", Key: "mcpServers.db"},
+			{Path: filepath.Join(folder, ".mcp.json"), System: "Claude Code", Skipped: "already exists"},
+		}}},
+		LockFile:           "duckrow.lock.json",
+		Warnings:           []string{"DB_URL is not set"},
+		PostInstallMessage: "Run make seed first.\n",
+	}, folder)
+	want := `Installed MCP Server db
+  + .cursor/mcp.json mcpServers.db (Cursor)
+  ! .mcp.json (Claude Code): already exists
+
+Updated duckrow.lock.json
+
+Warnings:
+  ! DB_URL is not set
+
+Note:
+  Run make seed first.`
+	if mcp != want {
+		t.Errorf("MCP report:\n%s\nwant:\n%s", mcp, want)
+	}
+
+	skill := installReportText(core.InstallReport{
+		Kind:   asset.KindSkill,
+		Assets: []core.InstallReportAsset{{Name: "lint", Commit: "0123456789abcdef", Systems: []string{"claude-code", "codex"}}},
+	}, folder)
+	for _, line := range []string{
+		"Installed Skill lint at 0123456",
+		"  + .agents/skills/lint (read by Codex,",
+		"  + .claude/skills/lint (Claude Code, link to .agents/skills/lint)",
+	} {
+		if !strings.Contains(skill, line) {
+			t.Errorf("skill report missing %q:\n%s", line, skill)
+		}
+	}
+	if strings.Contains(skill, "Note:") || strings.Contains(skill, "Updated") {
+		t.Errorf("skill report has a note or lock file it shouldn't:\n%s", skill)
+	}
+}
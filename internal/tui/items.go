@@ -26,15 +26,61 @@ type assetItem struct {
 	desc      string
 	path      string                // On-disk path (for skills with disk presence)
 	hasUpdate bool                  // Whether an update is available
+	systems   []string              // Display names of the systems that see the asset
+	compact   bool                  // Collapse the system chips to the first and a count
 	installed *asset.InstalledAsset // Set for disk-scanned assets (skills)
 	locked    *asset.LockedAsset    // Set for lock-file-only assets (MCPs)
 }
 
 func (i assetItem) Title() string {
+	title := i.name
 	if i.hasUpdate {
-		return i.name + "  " + warningStyle.Render("↓")
+		title += "  " + warningStyle.Render(glyphs.update)
 	}
-	return i.name
+	chips := systemChips(i.systems)
+	if i.compact {
+		chips = compactSystemChips(i.systems)
+	}
+	if chips != "" {
+		title += "  " + chips
+	}
+	return title
+}
+
+// systemChips renders system names as a row of [chips].
+func systemChips(names []string) string {
+	chips := make([]string, len(names))
+	for i, name := range names {
+		chips[i] = badgeStyle.Render("[" + name + "]")
+	}
+	return strings.Join(chips, " ")
+}
+
+// compactSystemChips renders the first system name as a chip and the rest
+// as a count, e.g. "[Cursor] +2", for narrow terminals.
+func compactSystemChips(names []string) string {
+	if len(names) <= 1 {
+		return systemChips(names)
+	}
+	return systemChips(names[:1]) + " " + badgeStyle.Render(fmt.Sprintf("+%d", len(names)-1))
+}
+
+// systemDisplayName maps a system name to its display name, keeping the
+// name of an unknown system as it is.
+func systemDisplayName(name string) string {
+	if sys, ok := system.ByName(name); ok {
+		return sys.DisplayName()
+	}
+	return name
+}
+
+// systemDisplayNames maps system names to display names.
+func systemDisplayNames(names []string) []string {
+	display := make([]string, len(names))
+	for i, name := range names {
+		display[i] = systemDisplayName(name)
+	}
+	return display
 }
 
 func (i assetItem) Description() string {
@@ -60,8 +106,9 @@ func (i assetItem) Description() string {
 func (i assetItem) FilterValue() string { return i.name }
 
 // installedAssetsToItems converts a slice of InstalledAsset to list items,
-// optionally marking items that have updates available.
-func installedAssetsToItems(kind asset.Kind, assets []asset.InstalledAsset, updateInfo map[string]core.UpdateInfo) []list.Item {
+// optionally marking items that have updates available. With compact set,
+// the items collapse their system chips.
+func installedAssetsToItems(kind asset.Kind, assets []asset.InstalledAsset, updateInfo map[string]core.UpdateInfo, compact bool) []list.Item {
 	items := make([]list.Item, len(assets))
 	for i, a := range assets {
 		_, hasUpdate := updateInfo[a.Name]
@@ -71,6 +118,8 @@ func installedAssetsToItems(kind asset.Kind, assets []asset.InstalledAsset, upda
 			desc:      a.Description,
 			path:      a.Path,
 			hasUpdate: hasUpdate,
+			systems:   systemDisplayNames(a.Systems),
+			compact:   compact,
 			installed: &assets[i],
 		}
 	}
@@ -197,6 +246,7 @@ type folderItem struct {
 	isCurrent bool     // synthetic entry for the cwd (not bookmarked)
 	systems   []string // display names from system detection
 	installed int      // skills + MCPs managed by duckrow (from lock file)
+	scanning  bool     // folder scan still streaming in after a load
 }
 
 func (i folderItem) FilterValue() string { return i.status.Folder.Path }
@@ -230,6 +280,9 @@ func (d folderDelegate) Render(w io.Writer, m list.Model, index int, item list.I
 	}
 
 	active := ""
+	if fi.scanning {
+		active = "  " + mutedStyle.Render("scanning...")
+	}
 	if fi.isCurrent {
 		active = "  " + mutedStyle.Render("(current, not bookmarked)")
 	} else if fi.isActive {
@@ -247,11 +300,11 @@ func (d folderDelegate) Render(w io.Writer, m list.Model, index int, item list.I
 // It detects active systems per folder so the bookmark list shows
 // systems based on config artifacts, not duckrow-managed skill directories.
 // The installed count comes from the lock file (all asset kinds managed by duckrow).
-func foldersToItems(folders []core.FolderStatus, activeFolder string) []list.Item {
+func foldersToItems(folders []core.FolderStatus, activeFolder string, scanning map[string]bool) []list.Item {
 	items := make([]list.Item, len(folders))
 	for i, fs := range folders {
 		var installed int
-		if lf, err := core.ReadLockFile(fs.Folder.Path); err == nil && lf != nil {
+		if lf, err := core.ReadLayeredLockFile(fs.Folder.Path); err == nil && lf != nil {
 			installed = len(lf.Assets)
 		}
 		items[i] = folderItem{
@@ -259,6 +312,7 @@ func foldersToItems(folders []core.FolderStatus, activeFolder string) []list.Ite
 			isActive:  fs.Folder.Path == activeFolder,
 			systems:   system.DisplayNames(system.DetectInFolder(fs.Folder.Path)),
 			installed: installed,
+			scanning:  scanning[fs.Folder.Path],
 		}
 	}
 	return items
@@ -19,13 +19,21 @@ type keyMap struct {
 	Delete          key.Binding
 	Refresh         key.Binding
 	Filter          key.Binding
+	SystemFilter    key.Binding
+	Log             key.Binding
+	LogLevel        key.Binding
 	Edit            key.Binding
 	Retry           key.Binding
+	SaveRepo        key.Binding
+	SaveHost        key.Binding
+	SkipSave        key.Binding
 	Toggle          key.Binding
 	ToggleAll       key.Binding
 	Update          key.Binding
 	UpdateAll       key.Binding
 	Configure       key.Binding
+	Copy            key.Binding
+	Close           key.Binding
 	Tab             key.Binding
 	ShiftTab        key.Binding
 	TabSaveLocation key.Binding
@@ -92,6 +100,18 @@ var keys = keyMap{
 		key.WithKeys("/"),
 		key.WithHelp("/", "filter"),
 	),
+	SystemFilter: key.NewBinding(
+		key.WithKeys("f"),
+		key.WithHelp("f", "filter by system"),
+	),
+	Log: key.NewBinding(
+		key.WithKeys("L"),
+		key.WithHelp("L", "log"),
+	),
+	LogLevel: key.NewBinding(
+		key.WithKeys("v"),
+		key.WithHelp("v", "level"),
+	),
 	Edit: key.NewBinding(
 		key.WithKeys("e"),
 		key.WithHelp("e", "edit URL"),
@@ -100,6 +120,18 @@ var keys = keyMap{
 		key.WithKeys("r"),
 		key.WithHelp("r", "retry"),
 	),
+	SaveRepo: key.NewBinding(
+		key.WithKeys("y"),
+		key.WithHelp("y", "save for repo"),
+	),
+	SaveHost: key.NewBinding(
+		key.WithKeys("h"),
+		key.WithHelp("h", "save for host"),
+	),
+	SkipSave: key.NewBinding(
+		key.WithKeys("n"),
+		key.WithHelp("n", "don't save"),
+	),
 	Toggle: key.NewBinding(
 		key.WithKeys(" ", "x"),
 		key.WithHelp("space/x", "toggle"),
@@ -120,6 +152,14 @@ var keys = keyMap{
 		key.WithKeys("c"),
 		key.WithHelp("c", "configure env vars"),
 	),
+	Copy: key.NewBinding(
+		key.WithKeys("c"),
+		key.WithHelp("c", "copy report"),
+	),
+	Close: key.NewBinding(
+		key.WithKeys("enter", "esc"),
+		key.WithHelp("enter/esc", "close"),
+	),
 	Tab: key.NewBinding(
 		key.WithKeys("tab"),
 		key.WithHelp("tab", "next tab"),
@@ -147,14 +187,14 @@ type folderHelpKeyMap struct {
 func (k folderHelpKeyMap) ShortHelp() []key.Binding {
 	bindings := []key.Binding{
 		keys.Up, keys.Down, keys.Enter,
-		keys.Filter, keys.Tab,
+		keys.Filter, keys.SystemFilter, keys.Tab,
 	}
 	if k.updatesAvailable {
 		bindings = append(bindings, keys.Update, keys.UpdateAll)
 	}
 	bindings = append(bindings,
 		keys.Delete, keys.Refresh,
-		keys.Install, keys.Bookmarks, keys.Settings, keys.Quit,
+		keys.Install, keys.Bookmarks, keys.Settings, keys.Log, keys.Quit,
 	)
 	return bindings
 }
@@ -191,11 +231,18 @@ func (k installHelpKeyMap) FullHelp() [][]key.Binding {
 }
 
 // settingsHelpKeyMap is shown in the settings view.
-type settingsHelpKeyMap struct{}
+type settingsHelpKeyMap struct {
+	editing bool
+}
 
 func (k settingsHelpKeyMap) ShortHelp() []key.Binding {
+	if k.editing {
+		return []key.Binding{
+			keys.Confirm, keys.Back,
+		}
+	}
 	return []key.Binding{
-		keys.Up, keys.Down, keys.Enter,
+		keys.Up, keys.Down, keys.Enter, keys.Toggle,
 		keys.Delete, keys.Refresh, keys.Back, keys.Quit,
 	}
 }
@@ -217,10 +264,31 @@ func (k previewHelpKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{k.ShortHelp()}
 }
 
+// logHelpKeyMap is shown in the log viewer.
+type logHelpKeyMap struct {
+	searching bool
+}
+
+func (k logHelpKeyMap) ShortHelp() []key.Binding {
+	if k.searching {
+		return []key.Binding{
+			keys.Confirm, keys.Back,
+		}
+	}
+	return []key.Binding{
+		keys.Up, keys.Down, keys.LogLevel, keys.Filter, keys.Back,
+	}
+}
+
+func (k logHelpKeyMap) FullHelp() [][]key.Binding {
+	return [][]key.Binding{k.ShortHelp()}
+}
+
 // cloneErrorHelpKeyMap is shown in the clone error overlay.
 type cloneErrorHelpKeyMap struct {
 	editing  bool
 	retrying bool
+	offering bool
 }
 
 func (k cloneErrorHelpKeyMap) ShortHelp() []key.Binding {
@@ -232,8 +300,13 @@ func (k cloneErrorHelpKeyMap) ShortHelp() []key.Binding {
 			keys.Enter, keys.Back,
 		}
 	}
+	if k.offering {
+		return []key.Binding{
+			keys.SaveRepo, keys.SaveHost, keys.SkipSave,
+		}
+	}
 	return []key.Binding{
-		keys.Edit, keys.Retry, keys.Back,
+		keys.Edit, keys.Retry, keys.Log, keys.Back,
 	}
 }
 
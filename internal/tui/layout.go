@@ -0,0 +1,62 @@
+package tui
+
+// layoutMode is how the TUI arranges its panels for the terminal width.
+// The modes switch at fixed breakpoints so a given width always renders
+// the same way:
+//
+//	layoutFull     width >= fullLayoutWidth      sidebar beside the folder view
+//	layoutCompact  width >= compactLayoutWidth   sidebar hidden, chips collapsed
+//	layoutMinimal  narrower                      info stacked above the content
+type layoutMode int
+
+const (
+	// layoutFull shows the sidebar beside the folder view.
+	layoutFull layoutMode = iota
+
+	// layoutCompact hides the sidebar and collapses each asset's system
+	// chips to the first one and a count of the rest.
+	layoutCompact
+
+	// layoutMinimal is compact with tighter panel padding and shorter
+	// footer hints, and stacks a one-line Info panel above the folder view
+	// instead of the sidebar.
+	layoutMinimal
+)
+
+const (
+	// fullLayoutWidth is the narrowest terminal that fits the sidebar next
+	// to a content panel of minContentWidth columns.
+	fullLayoutWidth = sidebarWidth + minContentWidth
+
+	// compactLayoutWidth is the narrowest terminal rendered in compact mode.
+	compactLayoutWidth = 70
+)
+
+// minimalPadH is the horizontal padding inside the content panel in
+// minimal mode, in place of panelPadH.
+const minimalPadH = 1
+
+// infoStripHeight is the height of the Info panel stacked above the folder
+// view in minimal mode: one line between two borders.
+const infoStripHeight = 3
+
+// layoutForWidth returns the layout mode for a terminal width.
+func layoutForWidth(width int) layoutMode {
+	switch {
+	case width >= fullLayoutWidth:
+		return layoutFull
+	case width >= compactLayoutWidth:
+		return layoutCompact
+	}
+	return layoutMinimal
+}
+
+func (m layoutMode) String() string {
+	switch m {
+	case layoutFull:
+		return "full"
+	case layoutCompact:
+		return "compact"
+	}
+	return "minimal"
+}
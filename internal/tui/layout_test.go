@@ -0,0 +1,125 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+)
+
+func TestLayoutForWidth(t *testing.T) {
+	tests := []struct {
+		width int
+		want  layoutMode
+	}{
+		{40, layoutMinimal},
+		{60, layoutMinimal},
+		{compactLayoutWidth - 1, layoutMinimal},
+		{compactLayoutWidth, layoutCompact},
+		{80, layoutCompact},
+		{fullLayoutWidth - 1, layoutCompact},
+		{fullLayoutWidth, layoutFull},
+		{100, layoutFull},
+	}
+	for _, tt := range tests {
+		if got := layoutForWidth(tt.width); got != tt.want {
+			t.Errorf("layoutForWidth(%d) = %v, want %v", tt.width, got, tt.want)
+		}
+	}
+}
+
+// TestView_FitsTerminal renders every golden view at the breakpoint widths
+// and checks the view fills the terminal exactly, with no line wrapping or
+// overrunning it.
+func TestView_FitsTerminal(t *testing.T) {
+	const height = 24
+	for _, v := range goldenViews {
+		for _, width := range []int{60, 80, 100} {
+			t.Run(fmt.Sprintf("%s_%d", v.name, width), func(t *testing.T) {
+				d := newDriver(t, driverOptions{
+					width:    width,
+					height:   height,
+					manifest: goldenManifest,
+					skills:   map[string]string{"lint": "Lint the project"},
+				})
+				v.open(d)
+				lines := strings.Split(d.app.View(), "\n")
+				if len(lines) != height {
+					t.Errorf("view has %d lines, want %d", len(lines), height)
+				}
+				for i, line := range lines {
+					if w := lipgloss.Width(line); w > width {
+						t.Errorf("line %d is %d columns wide, want at most %d: %q", i+1, w, width, line)
+					}
+				}
+			})
+		}
+	}
+}
+
+func TestFolderView_LayoutModes(t *testing.T) {
+	tests := []struct {
+		width     int
+		sidebar   bool
+		infoStrip bool
+		chips     string
+	}{
+		{60, false, true, "[Codex] +3"},
+		{80, false, false, "[Codex] +3"},
+		{100, true, false, "[Codex] [Gemini CLI] [GitHub Copilot] [OpenCode]"},
+	}
+	for _, tt := range tests {
+		t.Run(fmt.Sprint(tt.width), func(t *testing.T) {
+			d := newDriver(t, driverOptions{
+				width:    tt.width,
+				height:   24,
+				manifest: goldenManifest,
+				skills:   map[string]string{"lint": "Lint the project"},
+			})
+			view := d.view()
+			if got := strings.Contains(view, "Folder:"); got != tt.sidebar {
+				t.Errorf("sidebar shown = %v, want %v:\n%s", got, tt.sidebar, view)
+			}
+			if got := strings.Contains(view, "Bookmarked · Up to date"); got != tt.infoStrip {
+				t.Errorf("info strip shown = %v, want %v:\n%s", got, tt.infoStrip, view)
+			}
+			if !strings.Contains(view, "lint  "+tt.chips) {
+				t.Errorf("view does not show chips %q:\n%s", tt.chips, view)
+			}
+		})
+	}
+}
+
+// TestTabs_ScrollToActive checks that a tab bar too wide for its width
+// keeps the active tab in view and marks the hidden tabs.
+func TestTabs_ScrollToActive(t *testing.T) {
+	tabs := newTabsModel([]tabDef{
+		{label: "Skills (1)"}, {label: "MCP Servers (0)"}, {label: "Agents (0)"},
+		{label: "Commands (0)"}, {label: "Rules (0)"},
+	}).setWidth(40)
+
+	tests := []struct {
+		active int
+		want   string
+	}{
+		{0, "  Skills (1) │ MCP Servers (0) …"},
+		{4, "  … Commands (0) │ Rules (0)"},
+	}
+	for _, tt := range tests {
+		tabs.activeTab = tt.active
+		line, underline, _ := strings.Cut(ansi.Strip(tabs.view()), "\n")
+		if line != tt.want {
+			t.Errorf("active %d: tab bar = %q, want %q", tt.active, line, tt.want)
+		}
+		if w := lipgloss.Width(line); w > 40 {
+			t.Errorf("active %d: tab bar is %d columns wide, want at most 40", tt.active, w)
+		}
+		label := tabs.tabs[tt.active].label
+		want := lipgloss.Width(line[:strings.Index(line, label)])
+		if start := strings.Index(underline, "─"); start != want {
+			t.Errorf("active %d: underline starts at column %d, want %d under %q", tt.active, start, want, label)
+		}
+	}
+}
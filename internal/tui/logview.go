@@ -0,0 +1,252 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	"github.com/charmbracelet/bubbles/viewport"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+
+	"github.com/barysiuk/duckrow/internal/core"
+)
+
+// logTailInterval is how often the open log viewer picks up new entries.
+const logTailInterval = 500 * time.Millisecond
+
+// logTickMsg asks the log viewer to pick up new entries. gen tells ticks
+// of an earlier opening, which are dropped, from the current ones.
+type logTickMsg struct{ gen int }
+
+// logViewModel is the overlay that tails the event log: the git commands
+// and HTTP requests made so far, with the output of those that failed.
+//
+// Entries below the minimum level are hidden, and a search narrows the
+// rest to those whose message or output contains the query. While the
+// view is scrolled to the bottom it follows new entries.
+type logViewModel struct {
+	width  int
+	height int
+
+	// View the log was opened from, returned to on close.
+	returnTo appView
+
+	// gen counts openings, so only the latest one keeps ticking.
+	gen int
+
+	entries  []core.LogEntry
+	lastSeq  int
+	minLevel core.LogLevel
+
+	searching bool
+	search    textinput.Model
+	query     string
+
+	viewport viewport.Model
+}
+
+func newLogViewModel() logViewModel {
+	ti := textinput.New()
+	ti.Placeholder = "Search the log..."
+	ti.CharLimit = 256
+	ti.Prompt = "/"
+	return logViewModel{search: ti, viewport: viewport.New(0, 0)}
+}
+
+func (m logViewModel) setSize(width, height int) logViewModel {
+	m.width = width
+	m.height = height
+	m.search.Width = max(0, width-2)
+	m.viewport.Width = width
+	m.viewport.Height = max(0, height-4) // header + separator + footer + separator
+	m.viewport.SetContent(m.render())
+	return m
+}
+
+// activate opens the viewer on the whole log, scrolled to the newest entry.
+func (m logViewModel) activate(returnTo appView) (logViewModel, tea.Cmd) {
+	m.returnTo = returnTo
+	m.gen++
+	m.entries = core.LogEntries(0)
+	m.lastSeq = 0
+	if n := len(m.entries); n > 0 {
+		m.lastSeq = m.entries[n-1].Seq
+	}
+	m.searching = false
+	m.search.Blur()
+	m.viewport.SetContent(m.render())
+	m.viewport.GotoBottom()
+	return m, logTickCmd(m.gen)
+}
+
+// isSearching reports whether the search input owns the keyboard.
+func (m logViewModel) isSearching() bool {
+	return m.searching
+}
+
+// ownsBack reports whether esc is handled by the viewer, which first ends
+// the search, rather than closing it.
+func (m logViewModel) ownsBack() bool {
+	return m.searching || m.query != ""
+}
+
+func logTickCmd(gen int) tea.Cmd {
+	return tea.Tick(logTailInterval, func(time.Time) tea.Msg { return logTickMsg{gen: gen} })
+}
+
+// tail appends the entries recorded since the last look, keeping the view
+// at the bottom if it was there.
+func (m logViewModel) tail() logViewModel {
+	fresh := core.LogEntries(m.lastSeq)
+	if len(fresh) == 0 {
+		return m
+	}
+	follow := m.viewport.AtBottom()
+	m.entries = append(m.entries, fresh...)
+	m.lastSeq = fresh[len(fresh)-1].Seq
+	m.viewport.SetContent(m.render())
+	if follow {
+		m.viewport.GotoBottom()
+	}
+	return m
+}
+
+func (m logViewModel) update(msg tea.Msg) (logViewModel, tea.Cmd) {
+	switch msg := msg.(type) {
+	case logTickMsg:
+		if msg.gen != m.gen {
+			return m, nil
+		}
+		return m.tail(), logTickCmd(m.gen)
+
+	case tea.KeyMsg:
+		if m.searching {
+			switch {
+			case key.Matches(msg, keys.Confirm):
+				m.searching = false
+				m.search.Blur()
+				m.query = strings.TrimSpace(m.search.Value())
+				return m.refilter(), nil
+			case key.Matches(msg, keys.Back):
+				m.searching = false
+				m.search.Blur()
+				m.search.SetValue(m.query)
+				return m, nil
+			}
+			var cmd tea.Cmd
+			m.search, cmd = m.search.Update(msg)
+			return m, cmd
+		}
+
+		switch {
+		case key.Matches(msg, keys.Filter):
+			m.searching = true
+			m.search.SetValue(m.query)
+			m.search.CursorEnd()
+			return m, m.search.Focus()
+		case key.Matches(msg, keys.LogLevel):
+			m.minLevel = (m.minLevel + 1) % (core.LogError + 1)
+			return m.refilter(), nil
+		case key.Matches(msg, keys.Back) && m.query != "":
+			m.query = ""
+			m.search.SetValue("")
+			return m.refilter(), nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.viewport, cmd = m.viewport.Update(msg)
+	return m, cmd
+}
+
+// refilter re-renders after the level or search changed, showing the
+// newest matches.
+func (m logViewModel) refilter() logViewModel {
+	m.viewport.SetContent(m.render())
+	m.viewport.GotoBottom()
+	return m
+}
+
+// visible returns the entries that pass the level filter and search.
+func (m logViewModel) visible() []core.LogEntry {
+	query := strings.ToLower(m.query)
+	var out []core.LogEntry
+	for _, e := range m.entries {
+		if e.Level < m.minLevel {
+			continue
+		}
+		if query != "" && !strings.Contains(strings.ToLower(e.Message), query) &&
+			!strings.Contains(strings.ToLower(e.Output), query) {
+			continue
+		}
+		out = append(out, e)
+	}
+	return out
+}
+
+// render returns the viewport content: one line per entry, followed by
+// its output indented.
+func (m logViewModel) render() string {
+	entries := m.visible()
+	if len(entries) == 0 {
+		if len(m.entries) == 0 {
+			return mutedStyle.Render("Nothing logged yet. Git commands and network requests show up here.")
+		}
+		return mutedStyle.Render("No entries match.")
+	}
+	var b strings.Builder
+	for i, e := range entries {
+		if i > 0 {
+			b.WriteString("\n")
+		}
+		b.WriteString(mutedStyle.Render(e.Time.Format("15:04:05")))
+		b.WriteString(" ")
+		b.WriteString(logLevelStyle(e.Level).Render(fmt.Sprintf("%-5s", strings.ToUpper(e.Level.String()))))
+		b.WriteString(" ")
+		b.WriteString(e.Message)
+		if e.Output != "" {
+			for _, line := range strings.Split(e.Output, "\n") {
+				b.WriteString("\n")
+				b.WriteString(mutedStyle.Render("    " + line))
+			}
+		}
+	}
+	return b.String()
+}
+
+// logLevelStyle colors the level label of an entry.
+func logLevelStyle(l core.LogLevel) lipgloss.Style {
+	switch l {
+	case core.LogError:
+		return errorStyle
+	case core.LogWarn:
+		return warningStyle
+	case core.LogInfo:
+		return installedStyle
+	}
+	return mutedStyle
+}
+
+func (m logViewModel) view() string {
+	title := viewportTitleStyle.Render(" Log ")
+	line := ruleLine(m.width - lipgloss.Width(title))
+	header := lipgloss.JoinHorizontal(lipgloss.Center, title, mutedStyle.Render(line))
+
+	var footer string
+	switch {
+	case m.searching:
+		footer = m.search.View()
+	default:
+		parts := []string{"Level: " + m.minLevel.String() + "+"}
+		if m.query != "" {
+			parts = append(parts, fmt.Sprintf("Search: %q", m.query))
+		}
+		parts = append(parts, fmt.Sprintf("%d of %d entries", len(m.visible()), len(m.entries)))
+		footer = mutedStyle.Render(strings.Join(parts, "  ·  ")) +
+			previewPctStyle.Render(fmt.Sprintf(" %3.0f%% ", m.viewport.ScrollPercent()*100))
+	}
+	return header + "\n\n" + m.viewport.View() + "\n\n" + footer
+}
@@ -0,0 +1,78 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/barysiuk/duckrow/internal/core"
+)
+
+func TestLogView_LevelFilterAndSearch(t *testing.T) {
+	m := newLogViewModel().setSize(100, 30)
+	now := time.Now()
+	m.entries = []core.LogEntry{
+		{Seq: 1, Time: now, Level: core.LogDebug, Message: "git ls-remote https://github.com/acme/skills.git"},
+		{Seq: 2, Time: now, Level: core.LogWarn, Message: "rate limited by api.github.com; retrying in 2s"},
+		{Seq: 3, Time: now, Level: core.LogError, Message: "git clone https://github.com/acme/private.git: exit status 128",
+			Output: "fatal: Authentication failed for 'https://github.com/acme/private.git/'"},
+	}
+	m = m.refilter()
+
+	view := ansi.Strip(m.view())
+	for _, want := range []string{"DEBUG git ls-remote", "WARN  rate limited", "    fatal: Authentication failed", "3 of 3 entries"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+
+	// v raises the minimum level: debug hidden at info, warn hidden at error.
+	for i := 0; i < 3; i++ {
+		m, _ = m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	}
+	if got := m.visible(); len(got) != 1 || got[0].Seq != 3 {
+		t.Errorf("visible at error level = %+v, want only the failed clone", got)
+	}
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("v")})
+	if m.minLevel != core.LogDebug {
+		t.Errorf("minLevel after cycling = %v, want debug", m.minLevel)
+	}
+
+	// / searches messages and output.
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("/")})
+	if !m.isSearching() {
+		t.Fatal("/ did not start a search")
+	}
+	m, _ = m.update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("authentication")})
+	m, _ = m.update(enterKey)
+	if got := m.visible(); len(got) != 1 || got[0].Seq != 3 {
+		t.Errorf("visible for search = %+v, want only the failed clone", got)
+	}
+	if view := ansi.Strip(m.view()); !strings.Contains(view, `Search: "authentication"`) {
+		t.Errorf("footer missing the search:\n%s", view)
+	}
+
+	// esc clears the search before the viewer closes.
+	if !m.ownsBack() {
+		t.Error("ownsBack() with a search = false, want true")
+	}
+	m, _ = m.update(escKey)
+	if m.query != "" || len(m.visible()) != 3 {
+		t.Errorf("after esc: query = %q, %d visible; want the search cleared", m.query, len(m.visible()))
+	}
+}
+
+func TestLogView_StaleTicksDropped(t *testing.T) {
+	m := newLogViewModel().setSize(100, 30)
+	m, _ = m.activate(viewFolder)
+	m, _ = m.activate(viewFolder)
+	if _, cmd := m.update(logTickMsg{gen: m.gen - 1}); cmd != nil {
+		t.Error("a tick from an earlier opening was rescheduled")
+	}
+	if _, cmd := m.update(logTickMsg{gen: m.gen}); cmd == nil {
+		t.Error("a current tick was not rescheduled")
+	}
+}
@@ -0,0 +1,123 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/list"
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/barysiuk/duckrow/internal/core"
+)
+
+// pickerModel is a lightweight, standalone asset picker used by the CLI when
+// an install command is run without arguments on a TTY. It reuses the install
+// picker's list rendering but runs inline (no alt screen) and exits as soon
+// as the user selects an item or cancels.
+type pickerModel struct {
+	title    string
+	list     list.Model
+	selected *core.RegistryAssetInfo
+	quitting bool
+}
+
+// pickerMaxHeight caps the inline list height so the picker doesn't take
+// over the whole terminal.
+const pickerMaxHeight = 15
+
+func newPickerModel(title string, assets []core.RegistryAssetInfo) pickerModel {
+	l := list.New(registryAssetsToItems(assets), registryAssetDelegate{}, 0, 0)
+	l.SetShowTitle(false)
+	l.SetShowStatusBar(false)
+	l.SetShowHelp(false)
+	l.SetShowPagination(false)
+	l.SetFilteringEnabled(true)
+	l.DisableQuitKeybindings()
+	l.KeyMap.AcceptWhileFiltering.SetKeys("tab", "shift+tab")
+
+	m := pickerModel{title: title, list: l}
+	m.skipSeparators()
+	return m
+}
+
+func (m pickerModel) Init() tea.Cmd { return nil }
+
+func (m pickerModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.list.SetSize(msg.Width, min(pickerMaxHeight, max(1, msg.Height-2)))
+		return m, nil
+
+	case tea.KeyMsg:
+		switch {
+		case msg.String() == "ctrl+c":
+			m.quitting = true
+			return m, tea.Quit
+
+		case key.Matches(msg, keys.Back) && !m.list.SettingFilter() && !m.list.IsFiltered():
+			m.quitting = true
+			return m, tea.Quit
+
+		case key.Matches(msg, keys.Enter):
+			// Enter selects the highlighted item even while the filter input
+			// is focused, which gives the fzf-style "type and hit enter" flow.
+			if it, ok := m.list.SelectedItem().(registryAssetItem); ok {
+				info := it.info
+				m.selected = &info
+				m.quitting = true
+				return m, tea.Quit
+			}
+			return m, nil
+		}
+	}
+
+	var cmd tea.Cmd
+	m.list, cmd = m.list.Update(msg)
+	m.skipSeparators()
+	return m, cmd
+}
+
+// skipSeparators moves the cursor off separator items.
+func (m *pickerModel) skipSeparators() {
+	items := m.list.VisibleItems()
+	idx := m.list.Index()
+	if idx >= 0 && idx < len(items) {
+		if _, ok := items[idx].(registrySeparatorItem); ok {
+			if idx+1 < len(items) {
+				m.list.Select(idx + 1)
+			} else if idx-1 >= 0 {
+				m.list.Select(idx - 1)
+			}
+		}
+	}
+}
+
+func (m pickerModel) View() string {
+	if m.quitting {
+		return ""
+	}
+
+	var b strings.Builder
+	b.WriteString(panelTitleStyle.Render(m.title))
+	b.WriteString("\n")
+	b.WriteString(m.list.View())
+	b.WriteString("\n")
+	b.WriteString(mutedStyle.Render("  / filter · enter select · esc cancel"))
+	return b.String()
+}
+
+// PickRegistryAsset shows an inline, filterable picker of registry assets and
+// returns the one the user selected. It returns nil (and no error) when the
+// user cancels.
+func PickRegistryAsset(title string, assets []core.RegistryAssetInfo) (*core.RegistryAssetInfo, error) {
+	if len(assets) == 0 {
+		return nil, fmt.Errorf("no registry entries to choose from")
+	}
+
+	final, err := tea.NewProgram(newPickerModel(title, assets)).Run()
+	if err != nil {
+		return nil, fmt.Errorf("running picker: %w", err)
+	}
+	return final.(pickerModel).selected, nil
+}
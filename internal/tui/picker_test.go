@@ -0,0 +1,59 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func testPickerAssets() []core.RegistryAssetInfo {
+	return []core.RegistryAssetInfo{
+		{RegistryName: "org", RegistryRepo: "repo-a", Kind: asset.KindSkill, Entry: asset.RegistryEntry{Name: "go-review"}},
+		{RegistryName: "org", RegistryRepo: "repo-a", Kind: asset.KindSkill, Entry: asset.RegistryEntry{Name: "py-review"}},
+	}
+}
+
+func TestPickerSkipsSeparatorOnStart(t *testing.T) {
+	m := newPickerModel("Pick", testPickerAssets())
+	it, ok := m.list.SelectedItem().(registryAssetItem)
+	if !ok {
+		t.Fatalf("selected item = %T, want registryAssetItem", m.list.SelectedItem())
+	}
+	if it.info.Entry.Name != "go-review" {
+		t.Errorf("selected = %q, want %q", it.info.Entry.Name, "go-review")
+	}
+}
+
+func TestPickerEnterSelects(t *testing.T) {
+	var model tea.Model = newPickerModel("Pick", testPickerAssets())
+	model, _ = model.Update(tea.WindowSizeMsg{Width: 80, Height: 24})
+	model, _ = model.Update(tea.KeyMsg{Type: tea.KeyDown})
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEnter})
+
+	m := model.(pickerModel)
+	if m.selected == nil {
+		t.Fatal("expected a selection after enter")
+	}
+	if m.selected.Entry.Name != "py-review" {
+		t.Errorf("selected = %q, want %q", m.selected.Entry.Name, "py-review")
+	}
+	if cmd == nil {
+		t.Error("expected quit command after selection")
+	}
+}
+
+func TestPickerEscCancels(t *testing.T) {
+	var model tea.Model = newPickerModel("Pick", testPickerAssets())
+	model, cmd := model.Update(tea.KeyMsg{Type: tea.KeyEsc})
+
+	m := model.(pickerModel)
+	if m.selected != nil {
+		t.Errorf("selected = %v, want nil", m.selected)
+	}
+	if cmd == nil {
+		t.Error("expected quit command after esc")
+	}
+}
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/charmbracelet/glamour/styles"
+	"github.com/charmbracelet/lipgloss"
+	"golang.org/x/term"
+
+	"github.com/barysiuk/duckrow/internal/core"
+)
+
+// previewCacheMax is how many rendered previews are kept; the least
+// recently written are removed beyond that.
+const previewCacheMax = 200
+
+// previewCache keeps rendered skill previews on disk, keyed by a hash of
+// the markdown, the wrap width, and the glamour style, so reopening a large
+// SKILL.md skips rendering it again.
+type previewCache struct {
+	dir string // empty disables the cache
+}
+
+// newPreviewCache returns the preview cache: under the clone cache
+// directory when one is configured, otherwise under the config directory.
+func newPreviewCache(configDir string) previewCache {
+	if c := core.CurrentCache(); c.Enabled() {
+		return previewCache{dir: filepath.Join(c.Dir, "previews")}
+	}
+	if configDir == "" {
+		return previewCache{}
+	}
+	return previewCache{dir: filepath.Join(configDir, "preview-cache")}
+}
+
+func (c previewCache) path(content string, width int, style string) string {
+	sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d\x00%s", style, width, content)))
+	return filepath.Join(c.dir, hex.EncodeToString(sum[:])+".ansi")
+}
+
+// load returns the cached rendering of content, if there is one.
+func (c previewCache) load(content string, width int, style string) (string, bool) {
+	if c.dir == "" {
+		return "", false
+	}
+	data, err := os.ReadFile(c.path(content, width, style))
+	if err != nil {
+		return "", false
+	}
+	return string(data), true
+}
+
+// store caches a rendering of content. Failures are ignored: the cache
+// only saves time.
+func (c previewCache) store(content string, width int, style, rendered string) {
+	if c.dir == "" {
+		return
+	}
+	if err := os.MkdirAll(c.dir, 0o755); err != nil {
+		return
+	}
+	tmp, err := os.CreateTemp(c.dir, "preview-*.tmp")
+	if err != nil {
+		return
+	}
+	_, werr := tmp.WriteString(rendered)
+	cerr := tmp.Close()
+	if werr != nil || cerr != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	if err := os.Rename(tmp.Name(), c.path(content, width, style)); err != nil {
+		_ = os.Remove(tmp.Name())
+		return
+	}
+	c.prune()
+}
+
+// prune removes the oldest renderings beyond previewCacheMax.
+func (c previewCache) prune() {
+	matches, _ := filepath.Glob(filepath.Join(c.dir, "*.ansi"))
+	if len(matches) <= previewCacheMax {
+		return
+	}
+	modTimes := make(map[string]int64, len(matches))
+	for _, m := range matches {
+		if info, err := os.Stat(m); err == nil {
+			modTimes[m] = info.ModTime().UnixNano()
+		}
+	}
+	sort.Slice(matches, func(i, j int) bool { return modTimes[matches[i]] < modTimes[matches[j]] })
+	for _, m := range matches[:len(matches)-previewCacheMax] {
+		_ = os.Remove(m)
+	}
+}
+
+// previewStyle picks the glamour style the way glamour's auto style does,
+// so it can be part of the cache key: plain when stdout is not a terminal,
+// otherwise dark or light to match the terminal background.
+func previewStyle() string {
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return styles.NoTTYStyle
+	}
+	if lipgloss.HasDarkBackground() {
+		return styles.DarkStyle
+	}
+	return styles.LightStyle
+}
@@ -0,0 +1,70 @@
+package tui
+
+import (
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core"
+)
+
+func TestPreviewCache_RoundTrip(t *testing.T) {
+	c := newPreviewCache(t.TempDir())
+
+	if _, ok := c.load("# Skill", 80, "dark"); ok {
+		t.Fatal("load() on an empty cache: ok = true")
+	}
+	c.store("# Skill", 80, "dark", "rendered")
+	if got, ok := c.load("# Skill", 80, "dark"); !ok || got != "rendered" {
+		t.Errorf("load() = %q, %v; want rendered", got, ok)
+	}
+
+	for _, miss := range []struct {
+		content string
+		width   int
+		style   string
+	}{
+		{"# Skill v2", 80, "dark"},
+		{"# Skill", 100, "dark"},
+		{"# Skill", 80, "light"},
+	} {
+		if _, ok := c.load(miss.content, miss.width, miss.style); ok {
+			t.Errorf("load(%q, %d, %s) hit a rendering of something else", miss.content, miss.width, miss.style)
+		}
+	}
+}
+
+func TestPreviewCache_Dir(t *testing.T) {
+	configDir := t.TempDir()
+	if got, want := newPreviewCache(configDir).dir, filepath.Join(configDir, "preview-cache"); got != want {
+		t.Errorf("dir = %q, want %q", got, want)
+	}
+
+	cacheDir := t.TempDir()
+	core.SetCache(core.Cache{Dir: cacheDir})
+	t.Cleanup(func() { core.SetCache(core.Cache{}) })
+	if got, want := newPreviewCache(configDir).dir, filepath.Join(cacheDir, "previews"); got != want {
+		t.Errorf("dir with a clone cache = %q, want %q", got, want)
+	}
+
+	core.SetCache(core.Cache{})
+	disabled := newPreviewCache("")
+	disabled.store("# Skill", 80, "dark", "rendered")
+	if _, ok := disabled.load("# Skill", 80, "dark"); ok {
+		t.Error("load() on a disabled cache: ok = true")
+	}
+}
+
+func TestPreviewCache_Prune(t *testing.T) {
+	c := newPreviewCache(t.TempDir())
+	for i := 0; i <= previewCacheMax; i++ {
+		c.store(fmt.Sprintf("skill %d", i), 80, "dark", "rendered")
+	}
+	matches, _ := filepath.Glob(filepath.Join(c.dir, "*.ansi"))
+	if len(matches) != previewCacheMax {
+		t.Errorf("%d renderings kept, want %d", len(matches), previewCacheMax)
+	}
+	if tmp, _ := filepath.Glob(filepath.Join(c.dir, "*.tmp")); len(tmp) > 0 {
+		t.Errorf("temporary files left behind: %v", tmp)
+	}
+}
@@ -2,6 +2,7 @@ package tui
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/spinner"
@@ -94,6 +95,27 @@ func (m registryWizardModel) update(msg tea.Msg, app *App) (registryWizardModel,
 		if m.wizard.activeIdx == 1 {
 			cloneStep := m.wizard.steps[1].content.(regCloneStepModel)
 			cloneStep = cloneStep.handleResult(msg)
+			cloneStep.folder = app.activeFolder
+			m.wizard.steps[1].content = cloneStep
+		}
+		return m, nil
+
+	case installRecommendedMsg:
+		if m.wizard.activeIdx == 1 {
+			cloneStep := m.wizard.steps[1].content.(regCloneStepModel)
+			cloneStep.installing = true
+			m.wizard.steps[1].content = cloneStep
+			return m, tea.Batch(cloneStep.spinner.Tick, m.makeInstallRecommendedCmd(cloneStep.recommended))
+		}
+		return m, nil
+
+	case recommendedInstalledMsg:
+		if m.wizard.activeIdx == 1 {
+			cloneStep := m.wizard.steps[1].content.(regCloneStepModel)
+			cloneStep.installing = false
+			cloneStep.recDone = true
+			cloneStep.recResults = msg.results
+			cloneStep.recErr = msg.err
 			m.wizard.steps[1].content = cloneStep
 		}
 		return m, nil
@@ -103,8 +125,8 @@ func (m registryWizardModel) update(msg tea.Msg, app *App) (registryWizardModel,
 	if keyMsg, ok := msg.(tea.KeyMsg); ok && key.Matches(keyMsg, keys.Back) {
 		if m.wizard.activeIdx == 1 {
 			cloneStep := m.wizard.steps[1].content.(regCloneStepModel)
-			if cloneStep.cloning {
-				// Don't go back while cloning.
+			if cloneStep.cloning || cloneStep.installing {
+				// Don't go back while cloning or installing.
 				return m, nil
 			}
 			if cloneStep.err != nil {
@@ -135,7 +157,14 @@ func (m registryWizardModel) makeCloneCmd(url string) tea.Cmd {
 	app := m.app
 	return func() tea.Msg {
 		regMgr := core.NewRegistryManager(app.config.RegistriesDir())
-		manifest, err := regMgr.Add(url)
+		repo, local := url, core.IsLocalRegistryPath(url)
+		var manifest *core.RegistryManifest
+		var err error
+		if local {
+			repo, manifest, err = regMgr.AddLocal(url)
+		} else {
+			manifest, err = regMgr.Add(url)
+		}
 		if err != nil {
 			return registryAddDoneMsg{url: url, err: fmt.Errorf("adding registry: %w", err)}
 		}
@@ -145,20 +174,54 @@ func (m registryWizardModel) makeCloneCmd(url string) tea.Cmd {
 		if err != nil {
 			return registryAddDoneMsg{url: url, err: err}
 		}
+		var recommended []core.RegistryAssetInfo
+		if parsed, err := core.ParseManifest(manifest); err == nil {
+			recommended = core.RecommendedAssets(parsed, repo)
+		}
 		for _, r := range cfg.Registries {
-			if r.Repo == url {
+			if r.Repo == repo {
 				// Same repo already registered — report success.
-				return registryAddDoneMsg{url: url, name: manifest.Name, warnings: manifest.Warnings}
+				return registryAddDoneMsg{url: url, name: manifest.Name, warnings: manifest.Warnings, recommended: recommended}
 			}
 		}
 		cfg.Registries = append(cfg.Registries, core.Registry{
-			Name: manifest.Name,
-			Repo: url,
+			Name:  manifest.Name,
+			Repo:  repo,
+			Local: local,
 		})
 		if err := app.config.Save(cfg); err != nil {
 			return registryAddDoneMsg{url: url, err: err}
 		}
-		return registryAddDoneMsg{url: url, name: manifest.Name, warnings: manifest.Warnings}
+		return registryAddDoneMsg{url: url, name: manifest.Name, warnings: manifest.Warnings, recommended: recommended}
+	}
+}
+
+// installRecommendedMsg asks the wizard to install the recommended assets.
+type installRecommendedMsg struct{}
+
+// recommendedInstalledMsg is sent when installing the recommended assets
+// completes.
+type recommendedInstalledMsg struct {
+	results []core.RecommendedResult
+	err     error
+}
+
+// makeInstallRecommendedCmd creates the command that installs the
+// recommended assets into the active folder, all or none of them.
+func (m registryWizardModel) makeInstallRecommendedCmd(recommended []core.RegistryAssetInfo) tea.Cmd {
+	app := m.app
+	folder := app.activeFolder
+	return func() tea.Msg {
+		opts := core.RecommendedInstallOptions{TargetDir: folder}
+		if systems, _, err := core.DefaultSystems(folder); err == nil {
+			opts.TargetSystems = systems
+		}
+		if cfg, err := app.config.Load(); err == nil {
+			opts.IgnorePatterns = cfg.Settings.IgnorePatterns
+			opts.CloneURLOverrides = cfg.Settings.CloneURLOverrides
+		}
+		results, err := app.orch.InstallRecommended(recommended, opts)
+		return recommendedInstalledMsg{results: results, err: err}
 	}
 }
 
@@ -173,7 +236,7 @@ type regURLStepModel struct {
 
 func newRegURLStepModel() regURLStepModel {
 	ti := textinput.New()
-	ti.Placeholder = "Git repository URL..."
+	ti.Placeholder = "Git repository URL or local path..."
 	ti.CharLimit = 256
 	ti.Width = 60
 	ti.Focus()
@@ -226,13 +289,18 @@ type regCloneStepModel struct {
 	warnings []string
 	err      error
 	done     bool
+
+	// Recommended assets, offered for installing into the active folder.
+	recommended []core.RegistryAssetInfo
+	folder      string
+	installing  bool
+	recDone     bool
+	recResults  []core.RecommendedResult
+	recErr      error
 }
 
 func newRegCloneStepModel() regCloneStepModel {
-	s := spinner.New(
-		spinner.WithSpinner(spinner.Dot),
-		spinner.WithStyle(spinnerStyle),
-	)
+	s := newSpinner()
 	return regCloneStepModel{spinner: s}
 }
 
@@ -244,6 +312,11 @@ func (m regCloneStepModel) startClone(url string) regCloneStepModel {
 	m.err = nil
 	m.name = ""
 	m.warnings = nil
+	m.recommended = nil
+	m.installing = false
+	m.recDone = false
+	m.recResults = nil
+	m.recErr = nil
 	return m
 }
 
@@ -254,6 +327,7 @@ func (m regCloneStepModel) handleResult(msg registryAddDoneMsg) regCloneStepMode
 	m.err = msg.err
 	m.name = msg.name
 	m.warnings = msg.warnings
+	m.recommended = msg.recommended
 	return m
 }
 
@@ -262,8 +336,8 @@ func (m regCloneStepModel) Init() tea.Cmd {
 }
 
 func (m regCloneStepModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
-	if m.cloning {
-		// Only handle spinner ticks while cloning.
+	if m.cloning || m.installing {
+		// Only handle spinner ticks while cloning or installing.
 		var cmd tea.Cmd
 		m.spinner, cmd = m.spinner.Update(msg)
 		return m, cmd
@@ -272,7 +346,10 @@ func (m regCloneStepModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
 		if m.done && m.err == nil {
-			// Success — any key dismisses (emit wizardDoneMsg).
+			if m.offersRecommended() && key.Matches(msg, keys.Install) {
+				return m, func() tea.Msg { return installRecommendedMsg{} }
+			}
+			// Success — enter or esc dismisses (emit wizardDoneMsg).
 			if key.Matches(msg, keys.Enter) || key.Matches(msg, keys.Back) {
 				return m, func() tea.Msg { return wizardDoneMsg{} }
 			}
@@ -295,13 +372,59 @@ func (m regCloneStepModel) View() string {
 	}
 
 	// Success.
-	result := installedStyle.Render("✓") + " Registry " + selectedItemStyle.Render(m.name) + " added successfully."
+	result := installedStyle.Render(glyphs.success) + "Registry " + selectedItemStyle.Render(m.name) + " added successfully."
 	if len(m.warnings) > 0 {
 		result += "\n\n" + warningStyle.Render(fmt.Sprintf("%d warning(s):", len(m.warnings)))
 		for _, w := range m.warnings {
-			result += "\n  " + mutedStyle.Render("• "+w)
+			result += "\n  " + mutedStyle.Render(glyphs.bullet+" "+w)
 		}
 	}
-	result += "\n\n" + mutedStyle.Render("Press enter to continue.")
+	result += m.recommendedView()
+	if m.offersRecommended() {
+		result += "\n\n" + mutedStyle.Render("Press i to install them into "+shortenPath(m.folder)+", or enter to skip.")
+	} else {
+		result += "\n\n" + mutedStyle.Render("Press enter to continue.")
+	}
 	return result
 }
+
+// offersRecommended reports whether the step still offers to install the
+// registry's recommended assets.
+func (m regCloneStepModel) offersRecommended() bool {
+	return len(m.recommended) > 0 && !m.installing && !m.recDone
+}
+
+// recommendedView renders the recommended assets: the offer, the spinner
+// while installing them, or the outcome.
+func (m regCloneStepModel) recommendedView() string {
+	if len(m.recommended) == 0 {
+		return ""
+	}
+	if m.installing {
+		return "\n\n" + m.spinner.View() + " Installing recommended assets..."
+	}
+	if m.recErr != nil {
+		return "\n\n" + errorStyle.Render("Error: ") + m.recErr.Error()
+	}
+	if m.recDone {
+		var b strings.Builder
+		for _, r := range m.recResults {
+			if r.Skipped != "" {
+				b.WriteString("\n  " + mutedStyle.Render(fmt.Sprintf("%s %s skipped (%s)", glyphs.bullet, r.Asset.Entry.Name, r.Skipped)))
+				continue
+			}
+			b.WriteString("\n  " + installedStyle.Render(glyphs.success) + r.Asset.Entry.Name + mutedStyle.Render(" ("+string(r.Asset.Kind)+")"))
+			if len(r.RequiredEnv) > 0 {
+				b.WriteString(warningStyle.Render(" needs " + strings.Join(r.RequiredEnv, ", ")))
+			}
+		}
+		return "\n\n" + b.String()[1:]
+	}
+
+	var b strings.Builder
+	b.WriteString("\n\nRecommended by " + selectedItemStyle.Render(m.name) + ":")
+	for _, r := range m.recommended {
+		b.WriteString("\n  " + mutedStyle.Render(glyphs.bullet+" ") + r.Entry.Name + mutedStyle.Render(" ("+string(r.Kind)+")"))
+	}
+	return b.String()
+}
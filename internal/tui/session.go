@@ -0,0 +1,127 @@
+package tui
+
+import (
+	"encoding/json"
+	"os"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+// sessionViews names the views a session can be restored to, as saved in
+// the state file. The rest (previews, the log, the registry wizard, the
+// clone error) are transient and restore to the folder view.
+var sessionViews = map[appView]string{
+	viewFolder:        "folder",
+	viewBookmarks:     "bookmarks",
+	viewInstallPicker: "install",
+	viewSettings:      "settings",
+	viewAssetWizard:   "install-wizard",
+}
+
+// savedFolderViews returns the saved folder views of the folders that
+// still exist.
+func savedFolderViews(config *core.ConfigManager) map[string]core.FolderView {
+	views := config.FolderViews()
+	for path := range views {
+		if !isDir(path) {
+			delete(views, path)
+		}
+	}
+	return views
+}
+
+func isDir(path string) bool {
+	info, err := os.Stat(path)
+	return err == nil && info.IsDir()
+}
+
+// currentSession returns the session to save for the app as it is now.
+func (a App) currentSession() core.TUISession {
+	s := core.TUISession{View: sessionViews[a.activeView], Folder: a.activeFolder}
+	if a.activeView == viewAssetWizard {
+		s.Wizard = a.assetWizard.session()
+		if s.Wizard == nil {
+			// Installing: the next launch shows the folder it installs into.
+			s.View = ""
+		}
+	}
+	return s
+}
+
+// saveSession writes the session and the folder views to the state file
+// when they changed since the last write. Nothing is saved until the last
+// session has been restored, so the first paint doesn't overwrite it, and
+// failures are ignored: the session is a convenience.
+func (a *App) saveSession() {
+	if !a.sessionRestored {
+		return
+	}
+	a.folderViews[a.activeFolder] = a.folder.viewState()
+	s := a.currentSession()
+	data, err := json.Marshal(struct {
+		Session core.TUISession
+		Views   map[string]core.FolderView
+	}{s, a.folderViews})
+	if err != nil || string(data) == a.savedSession {
+		return
+	}
+	if err := a.config.SaveTUISession(a.cwd, s, a.folderViews); err == nil {
+		a.savedSession = string(data)
+	}
+}
+
+// restoreSession returns the app, once its data is loaded, to where the
+// last session from the same directory left it: the active folder's tab and
+// selections, then the view. An install wizard is reopened on its system
+// selection with the same systems checked, as long as its asset can still
+// be installed; anything further along, like typed env values, is not kept.
+func (a *App) restoreSession() {
+	a.sessionRestored = true
+	if v, ok := a.folderViews[a.activeFolder]; ok {
+		a.folder = a.folder.restoreView(v)
+	}
+
+	switch a.session.View {
+	case sessionViews[viewBookmarks]:
+		a.activeView = viewBookmarks
+		a.bookmarks = a.bookmarks.activate(a.cwd, a.activeFolder, a.folderStatus, a.scanning)
+	case sessionViews[viewInstallPicker]:
+		if len(a.registryAssets) > 0 {
+			a.openInstallPicker(installFilter(a.folder.activeKind))
+		}
+	case sessionViews[viewSettings]:
+		a.activeView = viewSettings
+	case sessionViews[viewAssetWizard]:
+		if a.session.Wizard != nil {
+			a.restoreAssetWizard(*a.session.Wizard)
+		}
+	}
+}
+
+// restoreAssetWizard reopens the install wizard for a saved wizard session
+// over the install picker it was opened from. If the asset is gone from the
+// registries, or already installed, only the picker opens.
+func (a *App) restoreAssetWizard(w core.WizardSession) {
+	if len(a.registryAssets) == 0 {
+		return
+	}
+	a.openInstallPicker(installFilter(w.Kind))
+	for _, info := range a.install.available {
+		if info.Entry.Name != w.Name || (w.Registry != "" && info.RegistryRepo != w.Registry) {
+			continue
+		}
+		width, height := a.innerContentSize()
+		wizard := a.assetWizard.activate(openAssetWizardMsg{
+			asset:        info,
+			allSystems:   system.All(),
+			activeFolder: a.activeFolder,
+		}, a, width, height)
+		if wizard.currentPhase() != assetPhaseSelectAgents {
+			return // nothing to choose; leave starting the install to the user
+		}
+		a.activeView = viewAssetWizard
+		a.assetWizard = wizard.checkSystems(w.Systems)
+		return
+	}
+}
@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func sessionDriverOptions() driverOptions {
+	return driverOptions{
+		width:    100,
+		height:   30,
+		manifest: goldenManifest,
+		skills:   map[string]string{"fmt": "Format the project", "lint": "Lint the project"},
+	}
+}
+
+func selectedName(a App, kind asset.Kind) string {
+	if item := a.folder.lists[kind].SelectedItem(); item != nil {
+		return item.FilterValue()
+	}
+	return ""
+}
+
+func TestSession_RestoresFolderView(t *testing.T) {
+	d := newDriver(t, sessionDriverOptions())
+	d.press("down")
+	if got := selectedName(d.app, asset.KindSkill); got != "lint" {
+		t.Fatalf("selected %q after down, want lint", got)
+	}
+	d.press("s")
+
+	r := d.relaunch()
+	if r.app.activeView != viewSettings {
+		t.Errorf("relaunched on view %v, want settings", r.app.activeView)
+	}
+	if got := selectedName(r.app, asset.KindSkill); got != "lint" {
+		t.Errorf("relaunched with %q selected, want lint", got)
+	}
+
+	// Transient views aren't restored.
+	r.press("esc", "L")
+	if r.app.activeView != viewLog {
+		t.Fatalf("L did not open the log")
+	}
+	if r = r.relaunch(); r.app.activeView != viewFolder {
+		t.Errorf("relaunched from the log on view %v, want the folder view", r.app.activeView)
+	}
+}
+
+func TestSession_RestoresAssetWizard(t *testing.T) {
+	d := newDriver(t, sessionDriverOptions())
+	d.press("i", "enter")
+	d.waitFor("the install wizard", func(a App) bool { return a.activeView == viewAssetWizard })
+	d.press("space")
+	want := d.app.assetWizard.session()
+	if want == nil || want.Name != "go-review" {
+		t.Fatalf("wizard session = %+v, want go-review", want)
+	}
+
+	r := d.relaunch()
+	if r.app.activeView != viewAssetWizard {
+		t.Fatalf("relaunched on view %v, want the install wizard", r.app.activeView)
+	}
+	if got := r.app.assetWizard.session(); !reflect.DeepEqual(got, want) {
+		t.Errorf("restored wizard session = %+v, want %+v", got, want)
+	}
+
+	// Once the asset is installed, relaunching opens the picker instead.
+	dir := filepath.Join(d.project, ".agents", "skills", "go-review")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	md := "---\nname: go-review\ndescription: Review Go code\n---\n"
+	if err := os.WriteFile(filepath.Join(dir, "SKILL.md"), []byte(md), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if r = r.relaunch(); r.app.activeView != viewInstallPicker {
+		t.Errorf("relaunched on view %v, want the install picker", r.app.activeView)
+	}
+}
@@ -2,12 +2,18 @@ package tui
 
 import (
 	"fmt"
+	"slices"
+	"sort"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 
 	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/system"
 )
 
 // settingsSection defines navigable sections in settings.
@@ -16,11 +22,47 @@ type settingsSection int
 const (
 	settingsRegistries settingsSection = iota
 	settingsAddRegistry
+	settingsOverrides
+	settingsAddOverride
+	settingsDefaultSystems
+	settingsSkipSystems
+	settingsStrategy
+	settingsNamespaces
+	settingsAccessible
+	settingsUpgradeCheck
+	settingsTimeouts
 )
 
+// settingsEdit is the value being typed into the settings input, if any.
+type settingsEdit int
+
+const (
+	editNone settingsEdit = iota
+	editOverridePattern
+	editOverrideURL
+	editTimeout
+)
+
+// timeoutSettings are the rows of the TIMEOUTS section, in order.
+var timeoutSettings = []struct {
+	label string
+	field func(*core.Settings) *int
+	def   time.Duration
+}{
+	{"Clone", func(s *core.Settings) *int { return &s.CloneTimeoutSeconds }, core.DefaultCloneTimeout},
+	{"Pull", func(s *core.Settings) *int { return &s.PullTimeoutSeconds }, core.DefaultPullTimeout},
+	{"Download", func(s *core.Settings) *int { return &s.DownloadTimeoutSeconds }, core.DefaultDownloadTimeout},
+}
+
 // openRegistryWizardMsg is sent when the user selects "+ Add Registry".
 type openRegistryWizardMsg struct{}
 
+// settingsRow is one selectable row: a section and the index within it.
+type settingsRow struct {
+	section settingsSection
+	index   int
+}
+
 // settingsModel is the settings/configuration screen.
 type settingsModel struct {
 	width  int
@@ -30,13 +72,29 @@ type settingsModel struct {
 	section settingsSection
 	cursor  int // Cursor within the current section.
 
+	// Inline editing of clone URL overrides and timeouts.
+	edit       settingsEdit
+	input      textinput.Model
+	editTarget string // Override pattern being edited, or the timeout label.
+	editErr    string // Validation error for the current input.
+
 	// Data.
-	cfg     *core.Config
-	version string // App version (e.g. "0.3.0", "dev").
+	cfg      *core.Config
+	version  string         // App version (e.g. "0.3.0", "dev").
+	warnings map[string]int // Manifest warning counts keyed by registry repo URL.
+
+	// Default systems of the active folder and the lock layer that sets
+	// them ("" = the built-in defaults).
+	folder         string
+	defaultSystems []string
+	defaultsOrigin core.LockOrigin
 }
 
 func newSettingsModel() settingsModel {
-	return settingsModel{}
+	ti := textinput.New()
+	ti.CharLimit = 256
+	ti.Width = 60
+	return settingsModel{input: ti}
 }
 
 func (m settingsModel) setSize(width, height int) settingsModel {
@@ -45,16 +103,86 @@ func (m settingsModel) setSize(width, height int) settingsModel {
 	return m
 }
 
-func (m settingsModel) setData(cfg *core.Config, version string) settingsModel {
+func (m settingsModel) setData(cfg *core.Config, version string, warnings map[string]int, folder string) settingsModel {
 	m.cfg = cfg
 	m.version = version
+	m.warnings = warnings
+	m.folder = folder
+	m.defaultSystems, m.defaultsOrigin = nil, ""
+	if cfg == nil {
+		return m
+	}
+	if folder != "" {
+		if systems, origin, err := core.DefaultSystems(folder); err == nil {
+			m.defaultSystems, m.defaultsOrigin = system.Names(systems), origin
+		}
+	}
+
+	// Keep the cursor on a row that still exists, e.g. after a removal.
+	rows := m.rows()
+	if !slices.Contains(rows, settingsRow{m.section, m.cursor}) {
+		for i := len(rows) - 1; i >= 0; i-- {
+			if rows[i].section <= m.section {
+				m.section, m.cursor = rows[i].section, rows[i].index
+				break
+			}
+		}
+	}
 	return m
 }
 
+// isEditing reports whether the inline input has focus, so app.go doesn't
+// treat q and esc as global keys.
+func (m settingsModel) isEditing() bool {
+	return m.edit != editNone
+}
+
+// overrideKeys returns the clone URL override patterns in display order.
+func (m settingsModel) overrideKeys() []string {
+	patterns := make([]string, 0, len(m.cfg.Settings.CloneURLOverrides))
+	for k := range m.cfg.Settings.CloneURLOverrides {
+		patterns = append(patterns, k)
+	}
+	sort.Strings(patterns)
+	return patterns
+}
+
+// rows returns every selectable row, top to bottom.
+func (m settingsModel) rows() []settingsRow {
+	var rows []settingsRow
+	for i := range m.cfg.Registries {
+		rows = append(rows, settingsRow{settingsRegistries, i})
+	}
+	rows = append(rows, settingsRow{settingsAddRegistry, 0})
+	for i := range len(m.cfg.Settings.CloneURLOverrides) {
+		rows = append(rows, settingsRow{settingsOverrides, i})
+	}
+	rows = append(rows, settingsRow{settingsAddOverride, 0})
+	if m.folder != "" {
+		for i := range system.All() {
+			rows = append(rows, settingsRow{settingsDefaultSystems, i})
+		}
+	}
+	rows = append(rows,
+		settingsRow{settingsSkipSystems, 0},
+		settingsRow{settingsStrategy, 0},
+		settingsRow{settingsNamespaces, 0},
+		settingsRow{settingsAccessible, 0},
+		settingsRow{settingsUpgradeCheck, 0},
+	)
+	for i := range timeoutSettings {
+		rows = append(rows, settingsRow{settingsTimeouts, i})
+	}
+	return rows
+}
+
 func (m settingsModel) update(msg tea.Msg, app *App) (settingsModel, tea.Cmd) {
 	if m.cfg == nil {
 		return m, nil
 	}
+	if m.isEditing() {
+		return m.updateEdit(msg, app)
+	}
 
 	switch msg := msg.(type) {
 	case tea.KeyMsg:
@@ -67,7 +195,7 @@ func (m settingsModel) update(msg tea.Msg, app *App) (settingsModel, tea.Cmd) {
 			m = m.moveCursorDown()
 			return m, nil
 
-		case key.Matches(msg, keys.Enter):
+		case key.Matches(msg, keys.Enter), key.Matches(msg, keys.Toggle):
 			return m.handleEnter(app)
 
 		case key.Matches(msg, keys.Delete):
@@ -88,31 +216,17 @@ func (m settingsModel) update(msg tea.Msg, app *App) (settingsModel, tea.Cmd) {
 }
 
 func (m settingsModel) moveCursorUp() settingsModel {
-	switch m.section {
-	case settingsRegistries:
-		if m.cursor > 0 {
-			m.cursor--
-		}
-	case settingsAddRegistry:
-		if len(m.cfg.Registries) > 0 {
-			m.section = settingsRegistries
-			m.cursor = len(m.cfg.Registries) - 1
-		}
+	rows := m.rows()
+	if i := slices.Index(rows, settingsRow{m.section, m.cursor}); i > 0 {
+		m.section, m.cursor = rows[i-1].section, rows[i-1].index
 	}
 	return m
 }
 
 func (m settingsModel) moveCursorDown() settingsModel {
-	switch m.section {
-	case settingsRegistries:
-		if m.cursor < len(m.cfg.Registries)-1 {
-			m.cursor++
-		} else {
-			m.section = settingsAddRegistry
-			m.cursor = 0
-		}
-	case settingsAddRegistry:
-		// No more sections below.
+	rows := m.rows()
+	if i := slices.Index(rows, settingsRow{m.section, m.cursor}); i >= 0 && i < len(rows)-1 {
+		m.section, m.cursor = rows[i+1].section, rows[i+1].index
 	}
 	return m
 }
@@ -122,6 +236,65 @@ func (m settingsModel) handleEnter(app *App) (settingsModel, tea.Cmd) {
 	case settingsAddRegistry:
 		// Open the registry wizard overlay.
 		return m, func() tea.Msg { return openRegistryWizardMsg{} }
+	case settingsOverrides:
+		patterns := m.overrideKeys()
+		if m.cursor < len(patterns) {
+			k := patterns[m.cursor]
+			return m.startEdit(editOverrideURL, k, m.cfg.Settings.CloneURLOverrides[k], "Clone URL...")
+		}
+	case settingsAddOverride:
+		return m.startEdit(editOverridePattern, "", "", "owner/repo or host/*")
+	case settingsDefaultSystems:
+		all := system.All()
+		if m.cursor < len(all) {
+			return m, m.toggleDefaultSystem(app, all[m.cursor].Name())
+		}
+	case settingsSkipSystems:
+		return m, m.saveSettings(app, func(s *core.Settings) error {
+			s.SkipSystemSelection = !s.SkipSystemSelection
+			return nil
+		})
+	case settingsStrategy:
+		return m, m.saveSettings(app, func(s *core.Settings) error {
+			strategies := core.InstallStrategies()
+			next := strategies[(slices.Index(strategies, s.Strategy())+1)%len(strategies)]
+			s.InstallStrategy = string(next)
+			if next == core.StrategySymlink {
+				s.InstallStrategy = "" // the default
+			}
+			core.SetInstallStrategy(next)
+			return nil
+		})
+	case settingsNamespaces:
+		return m, m.saveSettings(app, func(s *core.Settings) error {
+			modes := core.NamespaceModes()
+			next := modes[(slices.Index(modes, s.Namespaces())+1)%len(modes)]
+			s.SkillNamespaces = string(next)
+			if next == core.NamespaceNever {
+				s.SkillNamespaces = "" // the default
+			}
+			return nil
+		})
+	case settingsAccessible:
+		// The styles are set up at launch, so this applies to the next one.
+		return m, m.saveSettings(app, func(s *core.Settings) error {
+			s.Accessible = !s.Accessible
+			return nil
+		})
+	case settingsUpgradeCheck:
+		return m, m.saveSettings(app, func(s *core.Settings) error {
+			s.DisableUpgradeCheck = !s.DisableUpgradeCheck
+			return nil
+		})
+	case settingsTimeouts:
+		if m.cursor < len(timeoutSettings) {
+			t := timeoutSettings[m.cursor]
+			value := ""
+			if secs := *t.field(&m.cfg.Settings); secs > 0 {
+				value = strconv.Itoa(secs)
+			}
+			return m.startEdit(editTimeout, t.label, value, fmt.Sprintf("seconds (empty = %s)", t.def))
+		}
 	}
 	return m, nil
 }
@@ -157,10 +330,153 @@ func (m settingsModel) handleDelete(app *App) (settingsModel, tea.Cmd) {
 			)
 			return m, nil
 		}
+	case settingsOverrides:
+		patterns := m.overrideKeys()
+		if m.cursor < len(patterns) {
+			k := patterns[m.cursor]
+			app.confirm = app.confirm.show(
+				fmt.Sprintf("Remove clone URL override %s?", k),
+				m.saveSettings(app, func(s *core.Settings) error {
+					delete(s.CloneURLOverrides, k)
+					return nil
+				}),
+			)
+			return m, nil
+		}
+	case settingsTimeouts:
+		// Reset to the default.
+		if m.cursor < len(timeoutSettings) {
+			t := timeoutSettings[m.cursor]
+			return m, m.saveSettings(app, func(s *core.Settings) error {
+				*t.field(s) = 0
+				core.SetTimeouts(s.Timeouts())
+				return nil
+			})
+		}
+	}
+	return m, nil
+}
+
+// startEdit focuses the inline input for a value.
+func (m settingsModel) startEdit(edit settingsEdit, target, value, placeholder string) (settingsModel, tea.Cmd) {
+	m.edit = edit
+	m.editTarget = target
+	m.editErr = ""
+	m.input.Placeholder = placeholder
+	m.input.SetValue(value)
+	m.input.CursorEnd()
+	return m, m.input.Focus()
+}
+
+// updateEdit handles keys while the inline input has focus: enter submits
+// the value, esc cancels.
+func (m settingsModel) updateEdit(msg tea.Msg, app *App) (settingsModel, tea.Cmd) {
+	if msg, ok := msg.(tea.KeyMsg); ok {
+		switch {
+		case key.Matches(msg, keys.Back):
+			m.edit = editNone
+			m.input.Blur()
+			return m, nil
+		case key.Matches(msg, keys.Enter):
+			return m.submitEdit(app)
+		}
+	}
+
+	var cmd tea.Cmd
+	m.input, cmd = m.input.Update(msg)
+	return m, cmd
+}
+
+func (m settingsModel) submitEdit(app *App) (settingsModel, tea.Cmd) {
+	value := strings.TrimSpace(m.input.Value())
+	switch m.edit {
+	case editOverridePattern:
+		// Lookups lowercase owner, repo, and host.
+		pattern := strings.ToLower(value)
+		if err := core.ValidateCloneURLOverride(pattern, "{owner}/{repo}"); err != nil {
+			m.editErr = err.Error()
+			return m, nil
+		}
+		return m.startEdit(editOverrideURL, pattern, m.cfg.Settings.CloneURLOverrides[pattern], "Clone URL...")
+
+	case editOverrideURL:
+		pattern := m.editTarget
+		if err := core.ValidateCloneURLOverride(pattern, value); err != nil {
+			m.editErr = err.Error()
+			return m, nil
+		}
+		m.edit = editNone
+		m.input.Blur()
+		return m, m.saveSettings(app, func(s *core.Settings) error {
+			if s.CloneURLOverrides == nil {
+				s.CloneURLOverrides = make(map[string]string)
+			}
+			s.CloneURLOverrides[pattern] = value
+			return nil
+		})
+
+	case editTimeout:
+		secs := 0
+		if value != "" {
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 {
+				m.editErr = "timeout must be a whole number of seconds"
+				return m, nil
+			}
+			secs = n
+		}
+		label := m.editTarget
+		m.edit = editNone
+		m.input.Blur()
+		return m, m.saveSettings(app, func(s *core.Settings) error {
+			for _, t := range timeoutSettings {
+				if t.label == label {
+					*t.field(s) = secs
+				}
+			}
+			core.SetTimeouts(s.Timeouts())
+			return nil
+		})
 	}
 	return m, nil
 }
 
+// saveSettings applies change to the settings in a fresh load of the config
+// file, saves it, and reloads.
+func (m settingsModel) saveSettings(app *App, change func(*core.Settings) error) tea.Cmd {
+	return func() tea.Msg {
+		cfg, err := app.config.Load()
+		if err != nil {
+			return errMsg{err: err}
+		}
+		if err := change(&cfg.Settings); err != nil {
+			return errMsg{err: err}
+		}
+		if err := app.config.Save(cfg); err != nil {
+			return errMsg{err: err}
+		}
+		return app.reloadConfig()()
+	}
+}
+
+// toggleDefaultSystem adds or removes a system from the folder's default
+// systems, saved as a personal override in the local lock.
+func (m settingsModel) toggleDefaultSystem(app *App, name string) tea.Cmd {
+	folder := m.folder
+	names := slices.Clone(m.defaultSystems)
+	if i := slices.Index(names, name); i >= 0 {
+		names = slices.Delete(names, i, i+1)
+	} else {
+		names = append(names, name)
+	}
+	return func() tea.Msg {
+		if err := core.SetLocalDefaultSystems(folder, names); err != nil {
+			return errMsg{err: err}
+		}
+		return app.reloadConfig()()
+	}
+}
+
 func (m settingsModel) refreshSelectedRegistry(app *App) tea.Cmd {
 	if m.cursor >= len(m.cfg.Registries) {
 		return nil
@@ -184,6 +500,14 @@ func (m settingsModel) view() string {
 	}
 
 	var b strings.Builder
+	selectedLine := 0
+	mark := func(section settingsSection, index int) bool {
+		if m.section == section && m.cursor == index {
+			selectedLine = strings.Count(b.String(), "\n")
+			return true
+		}
+		return false
+	}
 
 	// Registries section.
 	b.WriteString(renderSectionHeader("REGISTRIES", m.width))
@@ -195,22 +519,105 @@ func (m settingsModel) view() string {
 	}
 
 	for i, reg := range m.cfg.Registries {
-		isSelected := m.section == settingsRegistries && i == m.cursor
+		isSelected := mark(settingsRegistries, i)
 		b.WriteString(m.renderRegistryRow(reg, isSelected))
 	}
 
 	// Add Registry action.
 	b.WriteString("\n")
-	isAddReg := m.section == settingsAddRegistry
-	if isAddReg {
-		b.WriteString(selectedItemStyle.Render("  + Add Registry"))
-	} else {
-		b.WriteString(mutedStyle.Render("  + Add Registry"))
+	b.WriteString(m.renderActionRow("+ Add Registry", mark(settingsAddRegistry, 0)))
+
+	// Clone URL overrides section.
+	b.WriteString("\n")
+	b.WriteString(renderSectionHeader("CLONE URL OVERRIDES", m.width))
+	b.WriteString("\n")
+	patterns := m.overrideKeys()
+	if len(patterns) == 0 {
+		b.WriteString(mutedStyle.Render("    No overrides"))
+		b.WriteString("\n")
+	}
+	for i, k := range patterns {
+		isSelected := mark(settingsOverrides, i)
+		if isSelected && m.edit == editOverrideURL {
+			b.WriteString(m.renderEditRow(k))
+			continue
+		}
+		b.WriteString(m.renderValueRow(k, m.cfg.Settings.CloneURLOverrides[k], isSelected))
 	}
 	b.WriteString("\n")
+	isAddOverride := mark(settingsAddOverride, 0)
+	switch {
+	case isAddOverride && m.edit == editOverridePattern:
+		b.WriteString(m.renderEditRow("Pattern"))
+	case isAddOverride && m.edit == editOverrideURL:
+		b.WriteString(m.renderEditRow(m.editTarget))
+	default:
+		b.WriteString(m.renderActionRow("+ Add Override", isAddOverride))
+	}
+
+	// Default systems of the active folder.
+	if m.folder != "" {
+		b.WriteString("\n")
+		b.WriteString(renderSectionHeader("DEFAULT SYSTEMS", m.width))
+		b.WriteString("\n")
+		hint := "none set: universal systems for skills, detected systems for MCPs and agents"
+		switch m.defaultsOrigin {
+		case core.OriginLocal:
+			hint = "your override in .duckrow/local.lock.json"
+		case core.OriginTeam:
+			hint = "from duckrow.lock.json; changes save a personal override"
+		}
+		b.WriteString("    " + mutedStyle.Render(hint) + "\n")
+		for i, sys := range system.All() {
+			on := slices.Contains(m.defaultSystems, sys.Name())
+			b.WriteString(m.renderToggleRow(sys.DisplayName(), sys.Name(), on, mark(settingsDefaultSystems, i)))
+		}
+	}
+
+	// Preferences section.
+	b.WriteString("\n")
+	b.WriteString(renderSectionHeader("PREFERENCES", m.width))
+	b.WriteString("\n")
+	b.WriteString(m.renderToggleRow("Reuse last system selection", "skip the Select Agents step when installing",
+		m.cfg.Settings.SkipSystemSelection, mark(settingsSkipSystems, 0)))
+	strategyHint := "link non-universal systems to .agents/skills/"
+	if m.cfg.Settings.Strategy() == core.StrategyCopy {
+		strategyHint = "copy skills into each system's directory"
+	}
+	b.WriteString(m.renderValueRow("Install strategy", string(m.cfg.Settings.Strategy())+"  "+mutedStyle.Render(strategyHint),
+		mark(settingsStrategy, 0)))
+	namespaceHints := map[core.NamespaceMode]string{
+		core.NamespaceNever:      "install registry skills under their own names",
+		core.NamespaceOnConflict: "use <registry>--<name> when the name is taken",
+		core.NamespaceAlways:     "install registry skills as <registry>--<name>",
+	}
+	namespaces := m.cfg.Settings.Namespaces()
+	b.WriteString(m.renderValueRow("Skill namespaces", string(namespaces)+"  "+mutedStyle.Render(namespaceHints[namespaces]),
+		mark(settingsNamespaces, 0)))
+	b.WriteString(m.renderToggleRow("Accessible mode", "plain text, high contrast, no spinners; applies on next launch",
+		m.cfg.Settings.Accessible, mark(settingsAccessible, 0)))
+	b.WriteString(m.renderToggleRow("Check for new releases", "once a day; shows a notice when a newer duckrow is out",
+		!m.cfg.Settings.DisableUpgradeCheck, mark(settingsUpgradeCheck, 0)))
+
+	// Timeouts section.
+	b.WriteString("\n")
+	b.WriteString(renderSectionHeader("TIMEOUTS", m.width))
+	b.WriteString("\n")
+	for i, t := range timeoutSettings {
+		isSelected := mark(settingsTimeouts, i)
+		if isSelected && m.edit == editTimeout {
+			b.WriteString(m.renderEditRow(t.label))
+			continue
+		}
+		value := t.def.String() + " (default)"
+		if secs := *t.field(&m.cfg.Settings); secs > 0 {
+			value = (time.Duration(secs) * time.Second).String()
+		}
+		b.WriteString(m.renderValueRow(t.label, value, isSelected))
+	}
 
 	// Footer: version + learn more link, pinned to the bottom.
-	content := b.String()
+	content := m.scroll(b.String(), selectedLine)
 	footer := m.renderFooter()
 	footerLines := strings.Count(footer, "\n") + 1
 	contentLines := strings.Count(content, "\n")
@@ -223,6 +630,21 @@ func (m settingsModel) view() string {
 	return content
 }
 
+// scroll cuts content to the lines that fit above the footer, keeping the
+// selected line (and the validation error below it) in view.
+func (m settingsModel) scroll(content string, selectedLine int) string {
+	lines := strings.Split(strings.TrimSuffix(content, "\n"), "\n")
+	avail := m.height - 3 // footer lines plus one blank
+	if avail <= 0 || len(lines) <= avail {
+		return content
+	}
+	start := 0
+	if end := selectedLine + 2; end > avail {
+		start = min(end-avail, len(lines)-avail)
+	}
+	return strings.Join(lines[start:start+avail], "\n") + "\n"
+}
+
 func (m settingsModel) renderRegistryRow(reg core.Registry, selected bool) string {
 	indicator := "    "
 	if selected {
@@ -236,11 +658,54 @@ func (m settingsModel) renderRegistryRow(reg core.Registry, selected bool) strin
 		b.WriteString(indicator + normalItemStyle.Render(reg.Name))
 	}
 	b.WriteString("  " + mutedStyle.Render(reg.Repo))
+	if n := m.warnings[reg.Repo]; n > 0 {
+		label := "warnings"
+		if n == 1 {
+			label = "warning"
+		}
+		b.WriteString("  " + warningStyle.Render(fmt.Sprintf("%d %s", n, label)))
+	}
 	b.WriteString("\n")
 
 	return b.String()
 }
 
+func (m settingsModel) renderActionRow(label string, selected bool) string {
+	if selected {
+		return selectedItemStyle.Render("  "+label) + "\n"
+	}
+	return mutedStyle.Render("  "+label) + "\n"
+}
+
+func (m settingsModel) renderValueRow(label, value string, selected bool) string {
+	if selected {
+		return "  > " + selectedItemStyle.Render(label) + "  " + value + "\n"
+	}
+	return "    " + normalItemStyle.Render(label) + "  " + mutedStyle.Render(value) + "\n"
+}
+
+// renderEditRow shows the inline input in place of the selected row, with
+// the validation error, if any, below it.
+func (m settingsModel) renderEditRow(label string) string {
+	row := "  > " + selectedItemStyle.Render(label) + "  " + m.input.View() + "\n"
+	if m.editErr != "" {
+		row += "    " + errorStyle.Render(m.editErr) + "\n"
+	}
+	return row
+}
+
+func (m settingsModel) renderToggleRow(label, hint string, on, selected bool) string {
+	check := "[ ]"
+	if on {
+		check = "[x]"
+	}
+	line := check + " " + label
+	if selected {
+		return "  > " + selectedItemStyle.Render(line) + "  " + mutedStyle.Render(hint) + "\n"
+	}
+	return "    " + normalItemStyle.Render(line) + "  " + mutedStyle.Render(hint) + "\n"
+}
+
 func (m settingsModel) renderFooter() string {
 	ver := m.version
 	if ver == "" {
@@ -0,0 +1,191 @@
+package tui
+
+import (
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/system"
+)
+
+// settingsHarness drives a settings model backed by a real config file.
+type settingsHarness struct {
+	t      *testing.T
+	app    App
+	folder string
+	m      settingsModel
+}
+
+func newSettingsHarness(t *testing.T) *settingsHarness {
+	t.Helper()
+	cm := core.NewConfigManagerWithDir(filepath.Join(t.TempDir(), ".duckrow"))
+	h := &settingsHarness{t: t, app: NewApp(cm, "dev"), folder: t.TempDir(), m: newSettingsModel()}
+	h.reload()
+	return h
+}
+
+func (h *settingsHarness) config() *core.Config {
+	h.t.Helper()
+	cfg, err := h.app.config.Load()
+	if err != nil {
+		h.t.Fatal(err)
+	}
+	return cfg
+}
+
+func (h *settingsHarness) reload() {
+	h.m = h.m.setSize(100, 80).setData(h.config(), "dev", nil, h.folder)
+}
+
+// moveTo puts the cursor on a row.
+func (h *settingsHarness) moveTo(section settingsSection, index int) {
+	h.t.Helper()
+	h.m.section, h.m.cursor = section, index
+}
+
+// key sends a key and runs the resulting command, reloading on a save.
+// Commands while editing only blink the cursor and are dropped.
+func (h *settingsHarness) key(msg tea.KeyMsg) {
+	h.t.Helper()
+	var cmd tea.Cmd
+	h.m, cmd = h.m.update(msg, &h.app)
+	if cmd == nil || h.m.isEditing() {
+		return
+	}
+	switch msg := cmd().(type) {
+	case errMsg:
+		h.t.Fatalf("command failed: %v", msg.err)
+	case loadedDataMsg:
+		h.reload()
+	}
+}
+
+func (h *settingsHarness) typeText(s string) {
+	h.t.Helper()
+	h.key(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)})
+}
+
+var (
+	enterKey = tea.KeyMsg{Type: tea.KeyEnter}
+	escKey   = tea.KeyMsg{Type: tea.KeyEsc}
+)
+
+func TestSettings_AddCloneURLOverride(t *testing.T) {
+	h := newSettingsHarness(t)
+	h.moveTo(settingsAddOverride, 0)
+
+	h.key(enterKey)
+	if !h.m.isEditing() {
+		t.Fatal("enter on + Add Override did not start editing")
+	}
+	h.typeText("acme")
+	h.key(enterKey)
+	if view := ansi.Strip(h.m.view()); !strings.Contains(view, "owner/repo or host/*") {
+		t.Errorf("invalid pattern not reported:\n%s", view)
+	}
+
+	h.typeText("/Skills")
+	h.key(enterKey)
+	if h.m.edit != editOverrideURL || h.m.editTarget != "acme/skills" {
+		t.Fatalf("edit = %v %q, want the URL of acme/skills", h.m.edit, h.m.editTarget)
+	}
+	h.typeText("git@mirror.example:acme/skills.git")
+	h.key(enterKey)
+	if h.m.isEditing() {
+		t.Error("still editing after a valid URL")
+	}
+	if got := h.config().Settings.CloneURLOverrides["acme/skills"]; got != "git@mirror.example:acme/skills.git" {
+		t.Errorf("saved override = %q", got)
+	}
+	if view := ansi.Strip(h.m.view()); !strings.Contains(view, "acme/skills") {
+		t.Errorf("view missing the override:\n%s", view)
+	}
+
+	// Remove it again through the confirmation dialog.
+	h.moveTo(settingsOverrides, 0)
+	h.key(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("d")})
+	if !h.app.confirm.active {
+		t.Fatal("d did not ask for confirmation")
+	}
+	if msg := h.app.confirm.onConfirm(); msg != nil {
+		if _, ok := msg.(errMsg); ok {
+			t.Fatalf("remove failed: %v", msg)
+		}
+	}
+	if got := h.config().Settings.CloneURLOverrides; len(got) != 0 {
+		t.Errorf("overrides after removal = %v", got)
+	}
+}
+
+func TestSettings_EditTimeout(t *testing.T) {
+	t.Cleanup(func() { core.SetTimeouts(core.Timeouts{}) })
+	h := newSettingsHarness(t)
+	h.moveTo(settingsTimeouts, 0)
+
+	h.key(enterKey)
+	h.typeText("soon")
+	h.key(enterKey)
+	if h.m.editErr == "" {
+		t.Error("non-numeric timeout accepted")
+	}
+	h.key(escKey)
+	if h.m.isEditing() {
+		t.Fatal("esc did not cancel the edit")
+	}
+
+	h.key(enterKey)
+	h.typeText("300")
+	h.key(enterKey)
+	if got := h.config().Settings.CloneTimeoutSeconds; got != 300 {
+		t.Errorf("cloneTimeoutSeconds = %d, want 300", got)
+	}
+	if got := core.CurrentTimeouts().Clone; got != 300*time.Second {
+		t.Errorf("clone timeout in effect = %s, want 5m", got)
+	}
+}
+
+func TestSettings_StrategyAndDefaultSystems(t *testing.T) {
+	t.Cleanup(func() { core.SetInstallStrategy("") })
+	h := newSettingsHarness(t)
+
+	h.moveTo(settingsStrategy, 0)
+	h.key(enterKey)
+	if got := h.config().Settings.InstallStrategy; got != "copy" {
+		t.Errorf("installStrategy = %q, want copy", got)
+	}
+	if core.CurrentInstallStrategy() != core.StrategyCopy {
+		t.Error("copy strategy not applied")
+	}
+	h.key(enterKey)
+	if got := h.config().Settings.InstallStrategy; got != "" {
+		t.Errorf("installStrategy = %q, want the default", got)
+	}
+
+	h.moveTo(settingsNamespaces, 0)
+	h.key(enterKey)
+	if got := h.config().Settings.SkillNamespaces; got != "on-conflict" {
+		t.Errorf("skillNamespaces = %q, want on-conflict", got)
+	}
+	h.key(enterKey)
+	h.key(enterKey)
+	if got := h.config().Settings.SkillNamespaces; got != "" {
+		t.Errorf("skillNamespaces = %q, want the default", got)
+	}
+
+	first := system.All()[0].Name()
+	h.moveTo(settingsDefaultSystems, 0)
+	h.key(enterKey)
+	systems, origin, err := core.DefaultSystems(h.folder)
+	if got := system.Names(systems); err != nil || !reflect.DeepEqual(got, []string{first}) || origin != core.OriginLocal {
+		t.Errorf("DefaultSystems() = %v (%s), %v; want [%s] (local)", got, origin, err, first)
+	}
+	if view := ansi.Strip(h.m.view()); !strings.Contains(view, "your override") {
+		t.Errorf("view does not show the local override:\n%s", view)
+	}
+}
@@ -1,6 +1,7 @@
 package tui
 
 import (
+	"fmt"
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
@@ -25,6 +26,10 @@ const minContentWidth = 60
 //	Bookmarked: No          ← red when not bookmarked
 //	[b] to bookmark it      ← dimmed hint, only when not bookmarked
 //
+//	Status:
+//	· 3 updates available   ← yellow; "Up to date" when nothing is pending
+//	· 1 MCP missing env     ← red, only when MCPs lack required env vars
+//
 //	Systems:                ← omitted when no systems detected
 //	· OpenCode
 //	· Cursor
@@ -35,6 +40,8 @@ type sidebarModel struct {
 	activeFolder string
 	isBookmarked bool
 	systems      []string // detected system names for the active folder
+	updates      int      // assets with updates available
+	envProblems  int      // MCPs with missing required env vars
 }
 
 func newSidebarModel() sidebarModel {
@@ -53,6 +60,13 @@ func (m sidebarModel) setData(activeFolder string, isBookmarked bool, systems []
 	return m
 }
 
+// setBadges sets the pending update and env problem counts.
+func (m sidebarModel) setBadges(updates, envProblems int) sidebarModel {
+	m.updates = updates
+	m.envProblems = envProblems
+	return m
+}
+
 func (m sidebarModel) view() string {
 	// Inner width: sidebar width minus border (2) minus padding on each side.
 	innerW := sidebarWidth - panelBorderH - sidebarPadH*2
@@ -80,12 +94,25 @@ func (m sidebarModel) view() string {
 		lines = append(lines, sidebarLabelStyle.Render("Bookmarked: ")+sidebarAgentStyle.Render("No ")+hint)
 	}
 
+	// Status section.
+	lines = append(lines, "")
+	lines = append(lines, sidebarLabelStyle.Render("Status:"))
+	if m.updates == 0 && m.envProblems == 0 {
+		lines = append(lines, mutedStyle.Render(glyphs.bullet+" Up to date"))
+	}
+	if m.updates > 0 {
+		lines = append(lines, warningStyle.Render(glyphs.bullet+" "+plural(m.updates, "update", "updates")+" available"))
+	}
+	if m.envProblems > 0 {
+		lines = append(lines, errorStyle.Render(glyphs.bullet+" "+plural(m.envProblems, "MCP", "MCPs")+" missing env"))
+	}
+
 	// Systems section (only if systems detected).
 	if len(m.systems) > 0 {
 		lines = append(lines, "")
 		lines = append(lines, sidebarLabelStyle.Render("Systems:"))
 		for _, name := range m.systems {
-			lines = append(lines, sidebarAgentStyle.Render("· "+name))
+			lines = append(lines, sidebarAgentStyle.Render(glyphs.bullet+" "+name))
 		}
 	}
 
@@ -93,3 +120,44 @@ func (m sidebarModel) view() string {
 
 	return renderPanel("Info", content, sidebarWidth, m.height, sidebarPadH, sidebarPadV)
 }
+
+// strip renders the sidebar's info as a one-line panel of the given width,
+// stacked above the folder view in minimal mode. The folder path is left
+// to the content panel's title.
+//
+//	╭─ Info ──────────────────────────────────────╮
+//	│ Not bookmarked [b] · Up to date · 2 systems │
+//	╰─────────────────────────────────────────────╯
+func (m sidebarModel) strip(width int) string {
+	var parts []string
+	if m.isBookmarked {
+		parts = append(parts, sidebarAgentStyle.Render("Bookmarked"))
+	} else {
+		parts = append(parts, sidebarAgentStyle.Render("Not bookmarked ")+mutedStyle.Italic(true).Render("[b]"))
+	}
+	if m.updates == 0 && m.envProblems == 0 {
+		parts = append(parts, mutedStyle.Render("Up to date"))
+	}
+	if m.updates > 0 {
+		parts = append(parts, warningStyle.Render(plural(m.updates, "update", "updates")))
+	}
+	if m.envProblems > 0 {
+		parts = append(parts, errorStyle.Render(plural(m.envProblems, "MCP", "MCPs")+" missing env"))
+	}
+	if len(m.systems) > 0 {
+		parts = append(parts, sidebarAgentStyle.Render(plural(len(m.systems), "system", "systems")))
+	}
+
+	line := strings.Join(parts, mutedStyle.Render(" "+glyphs.bullet+" "))
+	line = clampWidth(line, width-panelBorderH-sidebarPadH*2)
+	return renderPanel("Info", line, width, infoStripHeight, sidebarPadH, 0)
+}
+
+// plural formats a count with the singular or plural noun, e.g. "1 update"
+// or "3 updates".
+func plural(n int, one, many string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, one)
+	}
+	return fmt.Sprintf("%d %s", n, many)
+}
@@ -0,0 +1,61 @@
+package tui
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/barysiuk/duckrow/internal/core"
+	"github.com/barysiuk/duckrow/internal/core/asset"
+)
+
+func TestWindowTitle(t *testing.T) {
+	tests := []struct {
+		updates, env int
+		want         string
+	}{
+		{0, 0, "duckrow"},
+		{3, 0, "duckrow — 3 updates"},
+		{1, 1, "duckrow — 1 update, 1 env problem"},
+		{0, 2, "duckrow — 2 env problems"},
+	}
+	for _, tt := range tests {
+		if got := windowTitle(tt.updates, tt.env); got != tt.want {
+			t.Errorf("windowTitle(%d, %d) = %q, want %q", tt.updates, tt.env, got, tt.want)
+		}
+	}
+}
+
+func TestSidebar_StatusBadges(t *testing.T) {
+	m := newSidebarModel().setSize(30).setData("/work/app", true, nil)
+	if view := m.view(); !strings.Contains(view, "Up to date") {
+		t.Errorf("view without badges does not say up to date:\n%s", view)
+	}
+
+	view := m.setBadges(2, 1).view()
+	for _, want := range []string{"2 updates available", "1 MCP missing env"} {
+		if !strings.Contains(view, want) {
+			t.Errorf("view missing %q:\n%s", want, view)
+		}
+	}
+	if strings.Contains(view, "Up to date") {
+		t.Errorf("view with badges says up to date:\n%s", view)
+	}
+}
+
+func TestCountEnvProblems(t *testing.T) {
+	project := t.TempDir()
+	global := t.TempDir()
+	if err := os.WriteFile(filepath.Join(project, ".env.duckrow"), []byte("DUCKROW_TEST_SET=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	lf := &core.LockFile{Assets: []asset.LockedAsset{
+		{Kind: asset.KindMCP, Name: "ok", Data: map[string]any{"requiredEnv": []string{"DUCKROW_TEST_SET"}}},
+		{Kind: asset.KindMCP, Name: "broken", Data: map[string]any{"requiredEnv": []string{"DUCKROW_TEST_UNSET_VAR"}}},
+		{Kind: asset.KindMCP, Name: "none"},
+	}}
+	if got := countEnvProblems(lf, project, global); got != 1 {
+		t.Errorf("countEnvProblems() = %d, want 1", got)
+	}
+}
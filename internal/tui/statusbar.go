@@ -7,6 +7,7 @@ import (
 	"github.com/charmbracelet/bubbles/spinner"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
 )
 
 // statusMsgKind defines the visual style of a transient status message.
@@ -55,10 +56,7 @@ type taskStartedMsg struct{}
 type taskDoneMsg struct{}
 
 func newStatusBarModel() statusBarModel {
-	s := spinner.New(
-		spinner.WithSpinner(spinner.Dot),
-		spinner.WithStyle(spinnerStyle),
-	)
+	s := newSpinner()
 	return statusBarModel{
 		spinner: s,
 	}
@@ -73,7 +71,11 @@ func (m statusBarModel) showMsg(text string, kind statusMsgKind) (statusBarModel
 	m.nextID++
 
 	id := m.msgID
-	cmd := tea.Tick(statusAutoDismiss, func(_ time.Time) tea.Msg {
+	dismiss := statusAutoDismiss
+	if accessible {
+		dismiss = accessibleStatusDismiss
+	}
+	cmd := tea.Tick(dismiss, func(_ time.Time) tea.Msg {
 		return statusDismissMsg{id: id}
 	})
 	return m, cmd
@@ -138,7 +140,7 @@ func (m statusBarModel) view(helpContent string) string {
 	// If a message is active, hide help — show message + right zone only.
 	if left != "" {
 		if right == "" {
-			return left
+			return m.fit(left)
 		}
 		leftW := lipgloss.Width(left)
 		rightW := lipgloss.Width(right)
@@ -146,12 +148,12 @@ func (m statusBarModel) view(helpContent string) string {
 		if gap < 2 {
 			gap = 2
 		}
-		return left + fmt.Sprintf("%*s%s", gap, "", right)
+		return m.fit(left + fmt.Sprintf("%*s%s", gap, "", right))
 	}
 
 	// No message — show help + right zone.
 	if right == "" {
-		return helpContent
+		return m.fit(helpContent)
 	}
 	helpW := lipgloss.Width(helpContent)
 	rightW := lipgloss.Width(right)
@@ -159,7 +161,16 @@ func (m statusBarModel) view(helpContent string) string {
 	if gap < 2 {
 		gap = 2
 	}
-	return helpContent + fmt.Sprintf("%*s%s", gap, "", right)
+	return m.fit(helpContent + fmt.Sprintf("%*s%s", gap, "", right))
+}
+
+// fit cuts a rendered bar to the status bar's width, so a long message or
+// a narrow terminal never wraps it onto a second line.
+func (m statusBarModel) fit(bar string) string {
+	if m.width <= 0 || lipgloss.Width(bar) <= m.width {
+		return bar
+	}
+	return ansi.Truncate(bar, m.width, "…")
 }
 
 // renderLeft renders the left zone (transient message).
@@ -170,11 +181,11 @@ func (m statusBarModel) renderLeft() string {
 
 	switch m.msgKind {
 	case statusSuccess:
-		return statusSuccessStyle.Render("✓ " + m.msg)
+		return statusSuccessStyle.Render(glyphs.success + m.msg)
 	case statusError:
-		return statusErrorStyle.Render("✗ " + m.msg)
+		return statusErrorStyle.Render(glyphs.failure + m.msg)
 	case statusWarning:
-		return statusWarningStyle.Render("⚠ " + m.msg)
+		return statusWarningStyle.Render(glyphs.warning + m.msg)
 	}
 
 	return ""
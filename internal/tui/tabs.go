@@ -23,9 +23,13 @@ type tabDef struct {
 //
 //	Skills (3 ↓2)  │  MCPs (2)
 //	─────────────
+//
+// When the tabs don't fit the width, the bar scrolls to keep the active tab
+// in view and marks the hidden ones with glyphs.tabMore.
 type tabsModel struct {
 	tabs      []tabDef
 	activeTab int
+	width     int // available columns; 0 = unlimited
 }
 
 func newTabsModel(tabs []tabDef) tabsModel {
@@ -40,6 +44,12 @@ func (m tabsModel) setTabs(tabs []tabDef) tabsModel {
 	return m
 }
 
+// setWidth sets the columns the tab bar may use.
+func (m tabsModel) setWidth(width int) tabsModel {
+	m.width = width
+	return m
+}
+
 // update handles Tab / Shift+Tab to cycle through tabs.
 // Returns the updated model, an optional command, and whether the key was consumed.
 // blocked should be true when the parent wants to prevent tab switching (e.g. during filter mode).
@@ -82,7 +92,7 @@ func (m tabsModel) view() string {
 		return ""
 	}
 
-	sep := tabSeparatorStyle.Render("│")
+	sep := tabSeparatorStyle.Render(glyphs.tabSep)
 
 	var parts []string
 	var rawWidths []int
@@ -109,25 +119,81 @@ func (m tabsModel) view() string {
 				rendered = tabInactiveStyle.Render(tab.label)
 			}
 		}
+		rawW := lipgloss.Width(tab.label) + lipgloss.Width(tab.extra)
+		if accessible && i == m.activeTab {
+			// The underline is blank, so brackets mark the active tab.
+			rendered = "[" + rendered + "]"
+			rawW += 2
+		}
 		parts = append(parts, rendered)
 		// Raw width = label + extra (plain text widths).
-		rawWidths = append(rawWidths, lipgloss.Width(tab.label)+lipgloss.Width(tab.extra))
+		rawWidths = append(rawWidths, rawW)
 	}
 
-	tabLine := "  " + strings.Join(parts, sep)
+	start, end := m.window(rawWidths, lipgloss.Width(sep))
+	more := tabInactiveStyle.Render(glyphs.tabMore)
+	moreW := lipgloss.Width(glyphs.tabMore) + 1
+
+	tabLine := "  "
+	offset := 2 // leading indent "  "
+	if start > 0 {
+		tabLine += more + " "
+		offset += moreW
+	}
+	tabLine += strings.Join(parts[start:end], sep)
+	if end < len(parts) {
+		tabLine += " " + more
+	}
 
 	// Draw an underline below the active tab.
 	activeW := rawWidths[m.activeTab]
 
-	// Calculate the offset: sum widths of all tabs + separators before the active one.
-	offset := 2 // leading indent "  "
-	for i := 0; i < m.activeTab; i++ {
+	// Calculate the offset: sum widths of the visible tabs + separators
+	// before the active one.
+	for i := start; i < m.activeTab; i++ {
 		offset += rawWidths[i]
 		offset += lipgloss.Width(sep)
 	}
 
 	underline := strings.Repeat(" ", offset) +
-		tabUnderlineStyle.Render(strings.Repeat("─", activeW))
+		tabUnderlineStyle.Render(ruleLine(activeW))
 
 	return tabLine + "\n" + underline
 }
+
+// window returns the range of tabs [start, end) to show: all of them when
+// they fit the width, otherwise as many as fit around the active tab,
+// leaving room for the markers on the sides with hidden tabs.
+func (m tabsModel) window(widths []int, sepW int) (start, end int) {
+	n := len(widths)
+	if m.width <= 0 {
+		return 0, n
+	}
+	avail := m.width - 2 // leading indent
+	moreW := lipgloss.Width(glyphs.tabMore) + 1
+	fits := func(start, end int) bool {
+		w := sepW * (end - start - 1)
+		for i := start; i < end; i++ {
+			w += widths[i]
+		}
+		if start > 0 {
+			w += moreW
+		}
+		if end < n {
+			w += moreW
+		}
+		return w <= avail
+	}
+
+	end = m.activeTab + 1
+	for start < m.activeTab && !fits(start, end) {
+		start++
+	}
+	for end < n && fits(start, end+1) {
+		end++
+	}
+	for start > 0 && fits(start-1, end) {
+		start--
+	}
+	return start, end
+}
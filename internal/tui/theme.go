@@ -154,7 +154,7 @@ var (
 // renderSectionHeader renders a section label with short rules on both sides:
 // "  ── SKILLS ──────"
 func renderSectionHeader(label string, _ int) string {
-	rule := sectionRuleStyle.Render("──")
+	rule := sectionRuleStyle.Render(ruleLine(2))
 	text := sectionHeaderStyle.Render(" " + label + " ")
 	return "  " + rule + text + rule
 }
@@ -175,14 +175,14 @@ func newSkillDelegate() list.DefaultDelegate {
 		Padding(0, 0, 0, 2)
 
 	d.Styles.SelectedTitle = lipgloss.NewStyle().
-		Border(lipgloss.NormalBorder(), false, false, false, true).
+		Border(glyphs.selectBar, false, false, false, true).
 		BorderForeground(colorPrimary).
 		Foreground(colorSecondary).
 		Bold(true).
 		Padding(0, 0, 0, 1)
 
 	d.Styles.SelectedDesc = lipgloss.NewStyle().
-		Border(lipgloss.NormalBorder(), false, false, false, true).
+		Border(glyphs.selectBar, false, false, false, true).
 		BorderForeground(colorPrimary).
 		Foreground(colorMuted).
 		Padding(0, 0, 0, 1)
@@ -250,7 +250,7 @@ func renderPanel(title, content string, outerW, outerH, padH, padV int) string {
 		Render(content)
 
 	// Build top border: ╭─ Title ───...───╮
-	border := lipgloss.RoundedBorder()
+	border := glyphs.panelBorder
 	styledBorder := panelBorderStyle
 
 	titleText := ""
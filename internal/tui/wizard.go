@@ -17,12 +17,25 @@ type wizardDoneMsg struct{}
 // wizardBackMsg is emitted by wizardModel when esc is pressed on step 0.
 type wizardBackMsg struct{}
 
-// wizardStep defines one step in a wizard flow.
+// wizardStep defines one step in a wizard flow. Besides tea.Model, the
+// content may implement
+//
+//	stepName() string        // renames the step as it goes, e.g. to "Configure"
+//	handlesBack() bool       // while true, esc goes to the step, not back
+//	helpKeys() []key.Binding // the keys shown in the help bar
 type wizardStep struct {
 	name    string    // Displayed in the step indicator.
 	content tea.Model // The step's own Bubble Tea model.
 }
 
+// label returns the step's name as the indicator shows it.
+func (s wizardStep) label() string {
+	if n, ok := s.content.(interface{ stepName() string }); ok {
+		return n.stepName()
+	}
+	return s.name
+}
+
 // wizardModel provides a shared multi-step wizard wrapper with a step
 // indicator breadcrumb. It renders as plain content inside the app's
 // renderPanel — the panel border and title are handled by app.go.
@@ -80,6 +93,11 @@ func (m wizardModel) update(msg tea.Msg) (wizardModel, tea.Cmd) {
 		// Don't intercept keys while a text input may be focused —
 		// let the step handle esc itself if needed. Only handle esc
 		// at the wizard level for non-input steps.
+		if step := m.activeStep(); step != nil {
+			if b, ok := step.content.(interface{ handlesBack() bool }); ok && b.handlesBack() {
+				break
+			}
+		}
 		if key.Matches(msg, keys.Back) {
 			if m.activeIdx > 0 {
 				m.activeIdx--
@@ -139,26 +157,30 @@ func (m wizardModel) renderStepIndicator() string {
 	for i, step := range m.steps {
 		var label string
 		if i == m.activeIdx {
-			label = wizardStepActiveStyle.Render(step.name)
-			activeLabel = step.name
+			label = wizardStepActiveStyle.Render(step.label())
+			activeLabel = step.label()
+			if accessible {
+				// The underline is blank, so brackets mark the active step.
+				label = "[" + label + "]"
+			}
 		} else {
-			label = wizardStepInactiveStyle.Render(step.name)
+			label = wizardStepInactiveStyle.Render(step.label())
 		}
 		parts = append(parts, label)
 	}
 
-	sep := wizardStepSeparatorStyle.Render(" → ")
+	sep := wizardStepSeparatorStyle.Render(glyphs.stepSep)
 	breadcrumb := strings.Join(parts, sep)
 
 	// Underline below the active step label.
-	underline := wizardStepActiveStyle.Render(strings.Repeat("─", len(activeLabel)))
+	underline := wizardStepActiveStyle.Render(ruleLine(len(activeLabel)))
 
 	// Calculate offset: the visible width of all labels + separators before
 	// the active step. This positions the underline below the active label.
 	offset := 0
 	sepWidth := lipgloss.Width(sep)
 	for i := 0; i < m.activeIdx; i++ {
-		offset += len(m.steps[i].name) + sepWidth
+		offset += len(m.steps[i].label()) + sepWidth
 	}
 
 	padding := strings.Repeat(" ", offset)